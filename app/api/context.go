@@ -0,0 +1,42 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ContextUserIDKey is the gin context key the auth middleware stores the user ID under.
+const ContextUserIDKey = "userID"
+
+// ContextImpersonatorIDKey holds the admin ID when the request uses an impersonation token.
+const ContextImpersonatorIDKey = "impersonatorID"
+
+// ContextPermissionsKey is the gin context key holding the caller's permissions,
+// either a *PermissionMatcher or a []string of permission names.
+const ContextPermissionsKey = "permissions"
+
+// UserIDFromContext returns the authenticated user ID set by the auth middleware.
+func UserIDFromContext(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get(ContextUserIDKey)
+	if !exists {
+		return uuid.Nil, false
+	}
+	userID, ok := value.(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// ImpersonatorIDFromContext returns the admin acting as the authenticated user, if any.
+func ImpersonatorIDFromContext(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get(ContextImpersonatorIDKey)
+	if !exists {
+		return uuid.Nil, false
+	}
+	impersonatorID, ok := value.(uuid.UUID)
+	if !ok || impersonatorID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return impersonatorID, true
+}
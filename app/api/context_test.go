@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserIDFromContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("present", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		id := uuid.New()
+		c.Set(ContextUserIDKey, id)
+
+		userID, ok := UserIDFromContext(c)
+		assert.True(t, ok)
+		assert.Equal(t, id, userID)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+		_, ok := UserIDFromContext(c)
+		assert.False(t, ok)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set(ContextUserIDKey, "not-a-uuid")
+
+		_, ok := UserIDFromContext(c)
+		assert.False(t, ok)
+	})
+}
+
+func TestImpersonatorIDFromContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	_, ok := ImpersonatorIDFromContext(c)
+	assert.False(t, ok)
+
+	id := uuid.New()
+	c.Set(ContextImpersonatorIDKey, id)
+	impersonatorID, ok := ImpersonatorIDFromContext(c)
+	assert.True(t, ok)
+	assert.Equal(t, id, impersonatorID)
+}
@@ -4,25 +4,28 @@ import "github.com/gin-gonic/gin"
 
 func Can(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		permissionsValue, exists := c.Get("permissions")
+		permissionsValue, exists := c.Get(ContextPermissionsKey)
 		if !exists {
 			ForbiddenResponse(c, "Access Denied: Permissions not found in context")
 			c.Abort()
 			return
 		}
 
-		permissions, ok := permissionsValue.([]string)
-		if !ok {
+		var matcher *PermissionMatcher
+		switch permissions := permissionsValue.(type) {
+		case *PermissionMatcher:
+			matcher = permissions
+		case []string:
+			matcher = NewPermissionMatcher(permissions)
+		default:
 			ForbiddenResponse(c, "Access Denied: Invalid permissions data in context")
 			c.Abort()
 			return
 		}
 
-		for _, p := range permissions {
-			if p == permission {
-				c.Next()
-				return
-			}
+		if matcher.Allows(permission) {
+			c.Next()
+			return
 		}
 
 		ForbiddenResponse(c, "Access Denied: You do not have the required permission")
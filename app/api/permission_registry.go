@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PermissionDefinition describes a permission that routes can require.
+type PermissionDefinition struct {
+	Name        string
+	Description string
+}
+
+var permissionRegistry = struct {
+	sync.RWMutex
+	items map[string]PermissionDefinition
+}{items: make(map[string]PermissionDefinition)}
+
+// DefinePermission adds a permission to the central catalogue and returns its
+// name. Modules declare the permissions their routes require as package
+// variables, so importing a module is enough to register its catalogue:
+//
+//	var PermissionUsersRead = api.DefinePermission("admin:users:read", "List and view users")
+func DefinePermission(name, description string) string {
+	if name == "" || strings.HasPrefix(name, PermissionDenyPrefix) || strings.Contains(name, PermissionWildcard) {
+		panic(fmt.Sprintf("api: invalid permission name %q", name))
+	}
+
+	permissionRegistry.Lock()
+	defer permissionRegistry.Unlock()
+	if _, exists := permissionRegistry.items[name]; !exists {
+		permissionRegistry.items[name] = PermissionDefinition{Name: name, Description: description}
+	}
+	return name
+}
+
+// RegisteredPermissions returns the catalogue sorted by name.
+func RegisteredPermissions() []PermissionDefinition {
+	permissionRegistry.RLock()
+	defer permissionRegistry.RUnlock()
+
+	definitions := make([]PermissionDefinition, 0, len(permissionRegistry.items))
+	for _, d := range permissionRegistry.items {
+		definitions = append(definitions, d)
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Name < definitions[j].Name })
+	return definitions
+}
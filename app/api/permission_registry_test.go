@@ -0,0 +1,39 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinePermission(t *testing.T) {
+	name := DefinePermission("test:registry:read", "Read the registry")
+	assert.Equal(t, "test:registry:read", name)
+
+	// Redefinitions keep the first description.
+	DefinePermission("test:registry:read", "Other description")
+
+	var found []PermissionDefinition
+	for _, d := range RegisteredPermissions() {
+		if d.Name == name {
+			found = append(found, d)
+		}
+	}
+	assert.Equal(t, []PermissionDefinition{{Name: name, Description: "Read the registry"}}, found)
+}
+
+func TestDefinePermission_RejectsPatterns(t *testing.T) {
+	assert.Panics(t, func() { DefinePermission("", "empty") })
+	assert.Panics(t, func() { DefinePermission("admin:*", "wildcard") })
+	assert.Panics(t, func() { DefinePermission("!admin:users:read", "deny") })
+}
+
+func TestRegisteredPermissions_Sorted(t *testing.T) {
+	DefinePermission("test:sorted:b", "")
+	DefinePermission("test:sorted:a", "")
+
+	definitions := RegisteredPermissions()
+	for i := 1; i < len(definitions); i++ {
+		assert.Less(t, definitions[i-1].Name, definitions[i].Name)
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// PermissionWildcard grants every permission below the segment it replaces,
+	// e.g. "admin:users:*" or "admin:*".
+	PermissionWildcard = "*"
+	// PermissionDenyPrefix marks an explicit deny, e.g. "!admin:users:delete".
+	// Denies override any grant, including wildcards.
+	PermissionDenyPrefix = "!"
+
+	permissionSeparator = ":"
+)
+
+// PermissionMatcher answers permission checks against a resolved set of
+// grants and denies in time proportional to the depth of the permission name.
+type PermissionMatcher struct {
+	names  []string
+	grants map[string]struct{}
+	denies map[string]struct{}
+}
+
+// NewPermissionMatcher builds a matcher from permission names. Names prefixed
+// with PermissionDenyPrefix are denies; everything else is a grant.
+func NewPermissionMatcher(permissions []string) *PermissionMatcher {
+	m := &PermissionMatcher{
+		grants: make(map[string]struct{}, len(permissions)),
+		denies: make(map[string]struct{}),
+	}
+	for _, p := range permissions {
+		if name, ok := strings.CutPrefix(p, PermissionDenyPrefix); ok {
+			m.denies[name] = struct{}{}
+			continue
+		}
+		m.grants[p] = struct{}{}
+	}
+
+	m.names = append(make([]string, 0, len(permissions)), permissions...)
+	sort.Strings(m.names)
+	return m
+}
+
+// Permissions returns the sorted permission names the matcher was built from.
+func (m *PermissionMatcher) Permissions() []string {
+	if m == nil {
+		return nil
+	}
+	return m.names
+}
+
+// Allows reports whether permission is granted and not denied.
+func (m *PermissionMatcher) Allows(permission string) bool {
+	if m == nil || permission == "" {
+		return false
+	}
+	return !matches(m.denies, permission) && matches(m.grants, permission)
+}
+
+// matches checks the exact name, then each wildcard ancestor:
+// "a:b:c" is matched by "a:b:c", "a:b:*", "a:*" and "*".
+func matches(set map[string]struct{}, permission string) bool {
+	if len(set) == 0 {
+		return false
+	}
+	if _, ok := set[permission]; ok {
+		return true
+	}
+
+	prefix := permission
+	for {
+		i := strings.LastIndex(prefix, permissionSeparator)
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+		if _, ok := set[prefix+permissionSeparator+PermissionWildcard]; ok {
+			return true
+		}
+	}
+	_, ok := set[PermissionWildcard]
+	return ok
+}
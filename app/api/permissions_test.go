@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionMatcher_Allows(t *testing.T) {
+	matcher := NewPermissionMatcher([]string{
+		"admin:users:*",
+		"admin:kyc:read",
+		"markets:*",
+		"!admin:users:unlock",
+		"!markets:delete",
+	})
+
+	tests := []struct {
+		permission string
+		want       bool
+	}{
+		{"admin:users:read", true},
+		{"admin:users:update_status", true},
+		{"admin:users:unlock", false},
+		{"admin:kyc:read", true},
+		{"admin:kyc:review", false},
+		{"admin:roles:create", false},
+		{"markets:create", true},
+		{"markets:delete", false},
+		{"admin", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matcher.Allows(tt.permission), tt.permission)
+	}
+}
+
+func TestPermissionMatcher_TopLevelWildcards(t *testing.T) {
+	assert.True(t, NewPermissionMatcher([]string{"*"}).Allows("admin:users:read"))
+	assert.True(t, NewPermissionMatcher([]string{"admin:*"}).Allows("admin:users:read"))
+	assert.False(t, NewPermissionMatcher([]string{"admin:*"}).Allows("users:read"))
+
+	// A deny wildcard overrides every grant below it.
+	denied := NewPermissionMatcher([]string{"*", "!admin:*"})
+	assert.False(t, denied.Allows("admin:users:read"))
+	assert.True(t, denied.Allows("markets:read"))
+}
+
+func TestPermissionMatcher_Nil(t *testing.T) {
+	var matcher *PermissionMatcher
+	assert.False(t, matcher.Allows("admin:users:read"))
+	assert.Nil(t, matcher.Permissions())
+}
+
+func TestPermissionMatcher_Permissions(t *testing.T) {
+	matcher := NewPermissionMatcher([]string{"b", "!c", "a"})
+	assert.Equal(t, []string{"!c", "a", "b"}, matcher.Permissions())
+}
+
+func TestCan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		permissions interface{}
+		want        int
+	}{
+		{"matcher grant", NewPermissionMatcher([]string{"admin:users:*"}), http.StatusOK},
+		{"matcher deny", NewPermissionMatcher([]string{"admin:*", "!admin:users:read"}), http.StatusForbidden},
+		{"string slice", []string{"admin:users:read"}, http.StatusOK},
+		{"empty slice", []string{}, http.StatusForbidden},
+		{"invalid type", "admin:users:read", http.StatusForbidden},
+		{"missing", nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", func(c *gin.Context) {
+				if tt.permissions != nil {
+					c.Set(ContextPermissionsKey, tt.permissions)
+				}
+				c.Next()
+			}, Can("admin:users:read"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/test", http.NoBody))
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
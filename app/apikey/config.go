@@ -0,0 +1,44 @@
+package apikey
+
+import (
+	"errors"
+	"time"
+)
+
+// Config represents the configuration for the API key module
+type Config struct {
+	// ReplayWindow is how far a request timestamp may drift from server time.
+	ReplayWindow time.Duration `env:"API_KEY_REPLAY_WINDOW"`
+	// MaxKeysPerUser caps active (unrevoked) keys per user.
+	MaxKeysPerUser int `env:"API_KEY_MAX_PER_USER"`
+	// MaxBodySize bounds the request body read for signature verification.
+	MaxBodySize int64 `env:"API_KEY_MAX_BODY_SIZE"`
+	// LastUsedInterval throttles last-used writes so busy bots don't update the row on every request.
+	LastUsedInterval time.Duration `env:"API_KEY_LAST_USED_INTERVAL"`
+}
+
+func (c *Config) Validate() error {
+	if c.ReplayWindow <= 0 {
+		return errors.New("replay window must be positive")
+	}
+	if c.MaxKeysPerUser < 1 {
+		return errors.New("max keys per user must be at least 1")
+	}
+	if c.MaxBodySize <= 0 {
+		return errors.New("max body size must be positive")
+	}
+	if c.LastUsedInterval < 0 {
+		return errors.New("last used interval cannot be negative")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default API key configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		ReplayWindow:     5 * time.Minute,
+		MaxKeysPerUser:   10,
+		MaxBodySize:      1 << 20,
+		LastUsedInterval: time.Minute,
+	}
+}
@@ -0,0 +1,105 @@
+package apikey
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// CreateRequest is the request to create an API key.
+type CreateRequest struct {
+	Name        string     `json:"name"`
+	Scopes      []string   `json:"scopes"`
+	IPAllowlist []string   `json:"ip_allowlist"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// Validate sanitizes and checks the request data.
+func (r *CreateRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	r.Name = s.StripHTML(r.Name)
+	for i := range r.IPAllowlist {
+		r.IPAllowlist[i] = strings.TrimSpace(r.IPAllowlist[i])
+	}
+
+	v.Check(validator.MinRunes(r.Name, 2) && validator.MaxRunes(r.Name, 100), "name", "name must be between 2 and 100 characters")
+	v.Check(len(r.Scopes) > 0, "scopes", "at least one scope is required")
+	v.Check(validator.NoDuplicates(r.Scopes), "scopes", "scopes must not contain duplicates")
+
+	valid := make([]string, 0, len(models.ValidAPIKeyScopes))
+	for _, scope := range models.ValidAPIKeyScopes {
+		valid = append(valid, string(scope))
+	}
+	v.Check(validator.AllIn(r.Scopes, valid...), "scopes", "scopes must be any of read, trade, wallet:read")
+
+	v.Check(len(r.IPAllowlist) <= 20, "ip_allowlist", "ip allowlist must not contain more than 20 entries")
+	for _, entry := range r.IPAllowlist {
+		if !isIPOrCIDR(entry) {
+			v.AddError("ip_allowlist", "ip allowlist entries must be IP addresses or CIDR ranges")
+			break
+		}
+	}
+
+	if r.ExpiresAt != nil {
+		v.Check(r.ExpiresAt.After(time.Now()), "expires_at", "expiry must be in the future")
+	}
+}
+
+func isIPOrCIDR(entry string) bool {
+	if _, _, err := net.ParseCIDR(entry); err == nil {
+		return true
+	}
+	return net.ParseIP(entry) != nil
+}
+
+// SignedRequest carries everything needed to authenticate an API key request.
+type SignedRequest struct {
+	KeyID     string
+	Timestamp string
+	Signature string
+	Method    string
+	Path      string
+	FullPath  string
+	Body      []byte
+	IP        string
+}
+
+// Response represents an API key without its secret.
+type Response struct {
+	ID          uuid.UUID                `json:"id"`
+	Name        string                   `json:"name"`
+	KeyID       string                   `json:"key_id"`
+	Scopes      models.APIKeyScopes      `json:"scopes"`
+	IPAllowlist models.APIKeyIPAllowlist `json:"ip_allowlist"`
+	ExpiresAt   *time.Time               `json:"expires_at"`
+	LastUsedAt  *time.Time               `json:"last_used_at"`
+	LastUsedIP  string                   `json:"last_used_ip,omitempty"`
+	RevokedAt   *time.Time               `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+}
+
+// CreateResponse includes the secret, which is only ever returned once.
+type CreateResponse struct {
+	Response
+	Secret string `json:"secret"`
+}
+
+// ToResponse converts an API key model to a response.
+func ToResponse(k *models.APIKey) *Response {
+	return &Response{
+		ID:          k.ID,
+		Name:        k.Name,
+		KeyID:       k.KeyID,
+		Scopes:      k.Scopes,
+		IPAllowlist: k.IPAllowlist,
+		ExpiresAt:   k.ExpiresAt,
+		LastUsedAt:  k.LastUsedAt,
+		LastUsedIP:  k.LastUsedIP,
+		RevokedAt:   k.RevokedAt,
+		CreatedAt:   k.CreatedAt,
+	}
+}
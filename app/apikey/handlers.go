@@ -0,0 +1,140 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// Handler handles HTTP requests for API key management
+type Handler struct {
+	service   Service
+	sanitizer sanitizer.HTMLStripperer
+	logger    logger.Logger
+}
+
+// NewHandler creates a new API key handler
+func NewHandler(service Service, s sanitizer.HTMLStripperer, lg logger.Logger) *Handler {
+	return &Handler{service: service, sanitizer: s, logger: lg}
+}
+
+// CreateAPIKey godoc
+// @Summary      Create API key
+// @Description  Create a scoped API key. The secret is returned once; sign requests with HMAC-SHA256 keyed by the hex SHA-256 of the secret over "METHOD\nPATH\nTIMESTAMP\nhex(SHA-256(body))".
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreateRequest true "API key"
+// @Success      201  {object}  api.Response{data=CreateResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/api-keys [post]
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	key, err := h.service.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, ErrTooManyKeys) {
+			api.ConflictResponse(c, err.Error())
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "CreateAPIKey", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to create API key")
+		return
+	}
+
+	api.CreatedResponse(c, "API key created successfully", key)
+}
+
+// ListAPIKeys godoc
+// @Summary      List API keys
+// @Description  List the authenticated user's API keys
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=[]Response}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/api-keys [get]
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	keys, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "ListAPIKeys", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to retrieve API keys")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "API keys retrieved successfully", keys)
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke API key
+// @Description  Revoke one of the authenticated user's API keys
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "API key ID"
+// @Success      200  {object}  api.Response
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid API key ID format")
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "API key")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "RevokeAPIKey", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to revoke API key")
+		return
+	}
+
+	api.DeletedResponse(c, "API key revoked successfully")
+}
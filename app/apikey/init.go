@@ -0,0 +1,62 @@
+package apikey
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "api_key_repository"
+	ServiceKey = "api_key_service"
+	ConfigKey  = "api_key_config"
+)
+
+// MountAuthenticated mounts API key management routes. These are unreachable
+// with an API key; see RequiredScope.
+func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	keysGroup := r.Group("/users/api-keys")
+	keysGroup.POST("", handler.CreateAPIKey)
+	keysGroup.GET("", handler.ListAPIKeys)
+	keysGroup.DELETE("/:id", handler.RevokeAPIKey)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid API key configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+	container.RegisterService(ServiceKey, NewService(repo, signatureCounters(container), container.TokenMaker, config))
+	container.RegisterService(ConfigKey, config)
+}
+
+// signatureCounters records signatures in the shared cache, so Redis catches
+// a replay sent to another instance. A cache without counters falls back to
+// this process.
+func signatureCounters(container *deps.Container) cache.Counters {
+	if counters, ok := container.Cache.(cache.Counters); ok {
+		return counters
+	}
+	return cache.NewMemoryCache[string]()
+}
+
+// Middleware returns the API key auth middleware wired from the container,
+// falling back to the given token middleware for requests without a key.
+func Middleware(container *deps.Container, fallback gin.HandlerFunc) gin.HandlerFunc {
+	service := container.GetService(ServiceKey).(Service)
+	config := container.GetService(ConfigKey).(*Config)
+	return AuthMiddleware(service, config, fallback)
+}
+
+// createHandler creates an API key handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+
+	return NewHandler(service, container.Sanitizer, container.Logger)
+}
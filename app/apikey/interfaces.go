@@ -0,0 +1,32 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+)
+
+type Repository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByKeyID(ctx context.Context, keyID string) (*models.APIKey, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	CountActiveByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time, ip string) error
+}
+
+// SecretSealer encrypts key secrets for storage and decrypts them again.
+// security.Maker satisfies it with the token key.
+type SecretSealer interface {
+	Seal(claims interface{}, purpose string) (string, error)
+	Open(token, purpose string, claims interface{}) error
+}
+
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, req *CreateRequest) (*CreateResponse, error)
+	List(ctx context.Context, userID uuid.UUID) ([]Response, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	Authenticate(ctx context.Context, req *SignedRequest) (*models.APIKey, error)
+}
@@ -0,0 +1,64 @@
+package apikey
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/models"
+)
+
+// ContextAPIKey is the gin context key the authenticated API key is stored under
+const ContextAPIKey = "apiKey"
+
+// AuthMiddleware authenticates requests signed with an API key and hands every
+// other request to fallback, so it can wrap the regular token middleware:
+//
+//	mounter.Authenticated(engine).WithAuth(apikey.AuthMiddleware(svc, cfg, user.AuthMiddleware(...)))
+//
+// API key requests never carry role permissions, so api.Can rejects them.
+func AuthMiddleware(service Service, config *Config, fallback gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyID := c.GetHeader(HeaderKeyID)
+		if keyID == "" {
+			fallback(c)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, config.MaxBodySize+1))
+		if err != nil || int64(len(body)) > config.MaxBodySize {
+			api.BadRequestResponse(c, "Invalid request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key, err := service.Authenticate(c.Request.Context(), &SignedRequest{
+			KeyID:     keyID,
+			Timestamp: c.GetHeader(HeaderTimestamp),
+			Signature: c.GetHeader(HeaderSignature),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.RequestURI(),
+			FullPath:  c.FullPath(),
+			Body:      body,
+			IP:        c.ClientIP(),
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrAPIKeyScopeDenied), errors.Is(err, models.ErrAPIKeyIPNotAllowed):
+				api.ForbiddenResponse(c, err.Error())
+			default:
+				api.UnauthorizedResponse(c)
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(api.ContextUserIDKey, key.UserID)
+		c.Set(api.ContextPermissionsKey, []string{})
+		c.Set(ContextAPIKey, key)
+		c.Next()
+	}
+}
@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/models"
+)
+
+func setupRouter(repo *MockRepository, fallbackCalled *bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	fallback := func(c *gin.Context) {
+		*fallbackCalled = true
+		c.Next()
+	}
+
+	r := gin.New()
+	group := r.Group("/api/v1")
+	group.Use(AuthMiddleware(newTestService(repo), GetDefaultConfig(), fallback))
+	group.POST("/bets", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		userID, _ := api.UserIDFromContext(c)
+		c.JSON(http.StatusOK, gin.H{"body": string(body), "user_id": userID})
+	})
+	return r
+}
+
+func TestAuthMiddleware_FallsBackWithoutKey(t *testing.T) {
+	called := false
+	r := setupRouter(new(MockRepository), &called)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/bets", http.NoBody))
+
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthMiddleware_SignedRequest(t *testing.T) {
+	repo := new(MockRepository)
+	key := testKey(models.APIKeyScopeTrade)
+	repo.On("GetByKeyID", mock.Anything, "nk_test").Return(key, nil)
+	repo.On("TouchLastUsed", mock.Anything, key.ID, mock.Anything, mock.Anything).Return(nil)
+
+	called := false
+	r := setupRouter(repo, &called)
+
+	body := []byte(`{"amount":"100"}`)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bets", bytes.NewReader(body))
+	req.Header.Set(HeaderKeyID, "nk_test")
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(SigningKey(testSecret), http.MethodPost, "/api/v1/bets", timestamp, body))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `{\"amount\":\"100\"}`)
+	assert.Contains(t, w.Body.String(), key.UserID.String())
+}
+
+func TestAuthMiddleware_BadSignature(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("GetByKeyID", mock.Anything, "nk_test").Return(testKey(models.APIKeyScopeTrade), nil)
+
+	called := false
+	r := setupRouter(repo, &called)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bets", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set(HeaderKeyID, "nk_test")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+	req.Header.Set(HeaderSignature, "deadbeef")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthMiddleware_ScopeDenied(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("GetByKeyID", mock.Anything, "nk_test").Return(testKey(models.APIKeyScopeRead), nil)
+
+	called := false
+	r := setupRouter(repo, &called)
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bets", http.NoBody)
+	req.Header.Set(HeaderKeyID, "nk_test")
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(SigningKey(testSecret), http.MethodPost, "/api/v1/bets", timestamp, nil))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
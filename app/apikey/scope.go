@@ -0,0 +1,46 @@
+package apikey
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/joefazee/neo/models"
+)
+
+// API keys are refused everywhere except the routes below, whatever their
+// scopes. Anything not listed, such as profiles, data exports, statements,
+// sessions, KYC, key management and admin routes, stays behind a login.
+var (
+	readRoutes   = []string{"/api/v1/markets", "/api/v1/categories", "/api/v1/countries", "/api/v1/bets", "/api/v1/leaderboards"}
+	walletRoutes = []string{"/api/v1/wallets", "/api/v1/users/:user_id/wallets"}
+	tradeRoutes  = []string{"/api/v1/bets"}
+)
+
+// RequiredScope maps a matched route to the scope an API key needs to call it.
+// The boolean is false for routes API keys cannot use at all.
+func RequiredScope(method, fullPath string) (models.APIKeyScope, bool) {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		if matchesRoute(fullPath, walletRoutes) {
+			return models.APIKeyScopeWalletRead, true
+		}
+		if matchesRoute(fullPath, readRoutes) {
+			return models.APIKeyScopeRead, true
+		}
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		if matchesRoute(fullPath, tradeRoutes) {
+			return models.APIKeyScopeTrade, true
+		}
+	}
+	return "", false
+}
+
+// matchesRoute reports whether fullPath is one of routes or below one of them
+func matchesRoute(fullPath string, routes []string) bool {
+	for _, route := range routes {
+		if fullPath == route || strings.HasPrefix(fullPath, route+"/") {
+			return true
+		}
+	}
+	return false
+}
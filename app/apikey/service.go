@@ -0,0 +1,180 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/models"
+)
+
+// ErrTooManyKeys is returned when a user already holds the maximum number of active keys
+var ErrTooManyKeys = errors.New("maximum number of API keys reached")
+
+// secretPurpose binds sealed secrets to this use of the token key
+const secretPurpose = "api_key_secret"
+
+type service struct {
+	repo     Repository
+	counters cache.Counters
+	sealer   SecretSealer
+	config   *Config
+}
+
+// NewService creates a new API key service. The counters remember recently
+// seen signatures so a captured request cannot be replayed inside the window;
+// counting is atomic, so parallel replays are caught too. The sealer encrypts
+// secrets, so reading the api_keys table is not enough to sign requests.
+func NewService(repo Repository, counters cache.Counters, sealer SecretSealer, config *Config) Service {
+	return &service{repo: repo, counters: counters, sealer: sealer, config: config}
+}
+
+// Create issues a new key. The returned secret is only stored encrypted and
+// cannot be retrieved again.
+func (s *service) Create(ctx context.Context, userID uuid.UUID, req *CreateRequest) (*CreateResponse, error) {
+	count, err := s.repo.CountActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count api keys: %w", err)
+	}
+	if count >= int64(s.config.MaxKeysPerUser) {
+		return nil, ErrTooManyKeys
+	}
+
+	keyID, secret, err := generateCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	sealed, err := s.sealer.Seal(secret, secretPurpose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt api key secret: %w", err)
+	}
+
+	scopes := make(models.APIKeyScopes, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scopes = append(scopes, models.APIKeyScope(scope))
+	}
+
+	key := &models.APIKey{
+		UserID:           userID,
+		Name:             req.Name,
+		KeyID:            keyID,
+		SecretCiphertext: sealed,
+		Scopes:           scopes,
+		IPAllowlist:      req.IPAllowlist,
+		ExpiresAt:        req.ExpiresAt,
+	}
+	if err := key.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &CreateResponse{Response: *ToResponse(key), Secret: secret}, nil
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID) ([]Response, error) {
+	keys, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]Response, 0, len(keys))
+	for i := range keys {
+		responses = append(responses, *ToResponse(&keys[i]))
+	}
+	return responses, nil
+}
+
+func (s *service) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.Revoke(ctx, userID, id)
+}
+
+// Authenticate verifies a signed request and returns the key that signed it.
+func (s *service) Authenticate(ctx context.Context, req *SignedRequest) (*models.APIKey, error) {
+	if err := s.checkTimestamp(req.Timestamp); err != nil {
+		return nil, err
+	}
+
+	key, err := s.repo.GetByKeyID(ctx, req.KeyID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, models.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if !key.IsUsable() || key.User == nil || key.User.IsActive == nil || !*key.User.IsActive || key.User.IsLocked() {
+		return nil, models.ErrInvalidAPIKey
+	}
+
+	var secret string
+	if err := s.sealer.Open(key.SecretCiphertext, secretPurpose, &secret); err != nil {
+		return nil, models.ErrInvalidAPIKey
+	}
+	if !verifySignature(SigningKey(secret), req.Method, req.Path, req.Timestamp, req.Body, req.Signature) {
+		return nil, models.ErrInvalidAPIKeySignature
+	}
+
+	if !key.IPAllowlist.Allows(net.ParseIP(req.IP)) {
+		return nil, models.ErrAPIKeyIPNotAllowed
+	}
+
+	scope, ok := RequiredScope(req.Method, req.FullPath)
+	if !ok || !key.Scopes.Has(scope) {
+		return nil, models.ErrAPIKeyScopeDenied
+	}
+
+	if err := s.markSignatureUsed(ctx, key.KeyID, req.Signature); err != nil {
+		return nil, err
+	}
+
+	s.touch(ctx, key, req.IP)
+	return key, nil
+}
+
+func (s *service) checkTimestamp(timestamp string) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return models.ErrAPIKeyRequestReplayed
+	}
+	drift := time.Since(time.Unix(unix, 0))
+	if drift < 0 {
+		drift = -drift
+	}
+	if drift > s.config.ReplayWindow {
+		return models.ErrAPIKeyRequestReplayed
+	}
+	return nil
+}
+
+// markSignatureUsed rejects a signature already seen within the replay window.
+func (s *service) markSignatureUsed(ctx context.Context, keyID, signature string) error {
+	cacheKey := fmt.Sprintf("apikey:%s:sig:%s", keyID, signature)
+	// Timestamps are accepted on either side of now, so remember signatures for both halves.
+	seen, err := s.counters.IncrementCounter(ctx, cacheKey, 2*s.config.ReplayWindow)
+	if err != nil {
+		return fmt.Errorf("failed to record signature: %w", err)
+	}
+	if seen > 1 {
+		return models.ErrAPIKeyRequestReplayed
+	}
+	return nil
+}
+
+// touch records last use, at most once per LastUsedInterval. Failures are not fatal.
+func (s *service) touch(ctx context.Context, key *models.APIKey, ip string) {
+	now := time.Now()
+	if key.LastUsedAt != nil && now.Sub(*key.LastUsedAt) < s.config.LastUsedInterval {
+		return
+	}
+	if err := s.repo.TouchLastUsed(ctx, key.ID, now, ip); err == nil {
+		key.LastUsedAt = &now
+		key.LastUsedIP = ip
+	}
+}
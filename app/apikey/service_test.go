@@ -0,0 +1,280 @@
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return m.Called(ctx, key).Error(0)
+}
+
+func (m *MockRepository) GetByKeyID(ctx context.Context, keyID string) (*models.APIKey, error) {
+	args := m.Called(ctx, keyID)
+	if k := args.Get(0); k != nil {
+		return k.(*models.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if k := args.Get(0); k != nil {
+		return k.([]models.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) CountActiveByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time, ip string) error {
+	return m.Called(ctx, id, at, ip).Error(0)
+}
+
+const testSecret = "test-secret"
+
+func testSealer() SecretSealer {
+	maker, err := security.NewPasetoMaker("12345678901234567890123456789012")
+	if err != nil {
+		panic(err)
+	}
+	return maker
+}
+
+func testKey(scopes ...models.APIKeyScope) *models.APIKey {
+	active := true
+	sealed, err := testSealer().Seal(testSecret, secretPurpose)
+	if err != nil {
+		panic(err)
+	}
+	return &models.APIKey{
+		ID:               uuid.New(),
+		UserID:           uuid.New(),
+		KeyID:            "nk_test",
+		SecretCiphertext: sealed,
+		Scopes:           scopes,
+		User:             &models.User{IsActive: &active},
+	}
+}
+
+func signedRequest(method, path, fullPath string, body []byte) *SignedRequest {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return &SignedRequest{
+		KeyID:     "nk_test",
+		Timestamp: timestamp,
+		Signature: Sign(SigningKey(testSecret), method, path, timestamp, body),
+		Method:    method,
+		Path:      path,
+		FullPath:  fullPath,
+		Body:      body,
+		IP:        "10.0.0.1",
+	}
+}
+
+func newTestService(repo *MockRepository) Service {
+	return NewService(repo, cache.NewMemoryCache[string](), testSealer(), GetDefaultConfig())
+}
+
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("returns secret once and stores it encrypted", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("CountActiveByUser", ctx, userID).Return(int64(0), nil)
+		var stored *models.APIKey
+		repo.On("Create", ctx, mock.AnythingOfType("*models.APIKey")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.APIKey)
+		}).Return(nil)
+
+		resp, err := newTestService(repo).Create(ctx, userID, &CreateRequest{
+			Name:   "market maker",
+			Scopes: []string{"read", "trade"},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.Secret)
+		assert.NotContains(t, stored.SecretCiphertext, resp.Secret)
+		assert.NotContains(t, stored.SecretCiphertext, SigningKey(resp.Secret), "the signing key is not stored either")
+		var opened string
+		require.NoError(t, testSealer().Open(stored.SecretCiphertext, secretPurpose, &opened))
+		assert.Equal(t, resp.Secret, opened)
+		assert.True(t, stored.Scopes.Has(models.APIKeyScopeTrade))
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("CountActiveByUser", ctx, userID).Return(int64(GetDefaultConfig().MaxKeysPerUser), nil)
+
+		_, err := newTestService(repo).Create(ctx, userID, &CreateRequest{Name: "bot", Scopes: []string{"read"}})
+		assert.ErrorIs(t, err, ErrTooManyKeys)
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	body := []byte(`{"amount":"100"}`)
+
+	t.Run("valid trade request", func(t *testing.T) {
+		repo := new(MockRepository)
+		key := testKey(models.APIKeyScopeTrade)
+		repo.On("GetByKeyID", ctx, "nk_test").Return(key, nil)
+		repo.On("TouchLastUsed", ctx, key.ID, mock.Anything, "10.0.0.1").Return(nil)
+
+		got, err := newTestService(repo).Authenticate(ctx, signedRequest(http.MethodPost, "/api/v1/bets", "/api/v1/bets", body))
+		require.NoError(t, err)
+		assert.Equal(t, key.UserID, got.UserID)
+		assert.NotNil(t, got.LastUsedAt)
+	})
+
+	t.Run("replayed signature", func(t *testing.T) {
+		repo := new(MockRepository)
+		key := testKey(models.APIKeyScopeTrade)
+		repo.On("GetByKeyID", ctx, "nk_test").Return(key, nil)
+		repo.On("TouchLastUsed", ctx, key.ID, mock.Anything, mock.Anything).Return(nil).Once()
+
+		svc := newTestService(repo)
+		req := signedRequest(http.MethodPost, "/api/v1/bets", "/api/v1/bets", body)
+		_, err := svc.Authenticate(ctx, req)
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate(ctx, req)
+		assert.ErrorIs(t, err, models.ErrAPIKeyRequestReplayed)
+	})
+
+	t.Run("parallel replays", func(t *testing.T) {
+		repo := new(MockRepository)
+		key := testKey(models.APIKeyScopeTrade)
+		repo.On("GetByKeyID", ctx, "nk_test").Return(key, nil)
+		repo.On("TouchLastUsed", ctx, key.ID, mock.Anything, mock.Anything).Return(nil)
+
+		svc := newTestService(repo)
+		req := signedRequest(http.MethodPost, "/api/v1/bets", "/api/v1/bets", body)
+		var accepted atomic.Int32
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := svc.Authenticate(ctx, req); err == nil {
+					accepted.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), accepted.Load(), "only one of the same signed requests gets through")
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		req := signedRequest(http.MethodGet, "/api/v1/markets", "/api/v1/markets", nil)
+		req.Timestamp = strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+		_, err := newTestService(new(MockRepository)).Authenticate(ctx, req)
+		assert.ErrorIs(t, err, models.ErrAPIKeyRequestReplayed)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetByKeyID", ctx, "nk_test").Return(testKey(models.APIKeyScopeTrade), nil)
+
+		req := signedRequest(http.MethodPost, "/api/v1/bets", "/api/v1/bets", body)
+		req.Body = []byte(`{"amount":"100000"}`)
+
+		_, err := newTestService(repo).Authenticate(ctx, req)
+		assert.ErrorIs(t, err, models.ErrInvalidAPIKeySignature)
+	})
+
+	t.Run("missing scope", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetByKeyID", ctx, "nk_test").Return(testKey(models.APIKeyScopeRead), nil)
+
+		_, err := newTestService(repo).Authenticate(ctx, signedRequest(http.MethodPost, "/api/v1/bets", "/api/v1/bets", body))
+		assert.ErrorIs(t, err, models.ErrAPIKeyScopeDenied)
+	})
+
+	t.Run("ip not allowed", func(t *testing.T) {
+		repo := new(MockRepository)
+		key := testKey(models.APIKeyScopeRead)
+		key.IPAllowlist = models.APIKeyIPAllowlist{"192.168.0.0/16"}
+		repo.On("GetByKeyID", ctx, "nk_test").Return(key, nil)
+
+		_, err := newTestService(repo).Authenticate(ctx, signedRequest(http.MethodGet, "/api/v1/markets", "/api/v1/markets", nil))
+		assert.ErrorIs(t, err, models.ErrAPIKeyIPNotAllowed)
+	})
+
+	t.Run("expired key", func(t *testing.T) {
+		repo := new(MockRepository)
+		key := testKey(models.APIKeyScopeRead)
+		past := time.Now().Add(-time.Minute)
+		key.ExpiresAt = &past
+		repo.On("GetByKeyID", ctx, "nk_test").Return(key, nil)
+
+		_, err := newTestService(repo).Authenticate(ctx, signedRequest(http.MethodGet, "/api/v1/markets", "/api/v1/markets", nil))
+		assert.ErrorIs(t, err, models.ErrInvalidAPIKey)
+	})
+
+	t.Run("stored value alone cannot sign", func(t *testing.T) {
+		repo := new(MockRepository)
+		key := testKey(models.APIKeyScopeRead)
+		repo.On("GetByKeyID", ctx, "nk_test").Return(key, nil)
+
+		req := signedRequest(http.MethodGet, "/api/v1/markets", "/api/v1/markets", nil)
+		req.Signature = Sign(key.SecretCiphertext, req.Method, req.Path, req.Timestamp, nil)
+		_, err := newTestService(repo).Authenticate(ctx, req)
+		assert.ErrorIs(t, err, models.ErrInvalidAPIKeySignature)
+	})
+
+	t.Run("secret not sealed with the token key", func(t *testing.T) {
+		repo := new(MockRepository)
+		key := testKey(models.APIKeyScopeRead)
+		key.SecretCiphertext = SigningKey(testSecret)
+		repo.On("GetByKeyID", ctx, "nk_test").Return(key, nil)
+
+		_, err := newTestService(repo).Authenticate(ctx, signedRequest(http.MethodGet, "/api/v1/markets", "/api/v1/markets", nil))
+		assert.ErrorIs(t, err, models.ErrInvalidAPIKey)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetByKeyID", ctx, "nk_test").Return(nil, models.ErrRecordNotFound)
+
+		_, err := newTestService(repo).Authenticate(ctx, signedRequest(http.MethodGet, "/api/v1/markets", "/api/v1/markets", nil))
+		assert.ErrorIs(t, err, models.ErrInvalidAPIKey)
+	})
+
+	t.Run("recent use is not written again", func(t *testing.T) {
+		repo := new(MockRepository)
+		key := testKey(models.APIKeyScopeRead)
+		recent := time.Now().Add(-time.Second)
+		key.LastUsedAt = &recent
+		repo.On("GetByKeyID", ctx, "nk_test").Return(key, nil)
+
+		_, err := newTestService(repo).Authenticate(ctx, signedRequest(http.MethodGet, "/api/v1/markets", "/api/v1/markets", nil))
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,94 @@
+package apikey
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/models"
+)
+
+func TestSign(t *testing.T) {
+	key := SigningKey("secret")
+	body := []byte(`{"amount":"100"}`)
+
+	signature := Sign(key, "post", "/api/v1/bets", "1700000000", body)
+	assert.Len(t, signature, 64)
+	assert.True(t, verifySignature(key, "POST", "/api/v1/bets", "1700000000", body, signature))
+	assert.True(t, verifySignature(key, "POST", "/api/v1/bets", "1700000000", body, strings.ToUpper(signature)))
+
+	assert.False(t, verifySignature(key, "POST", "/api/v1/bets", "1700000001", body, signature))
+	assert.False(t, verifySignature(key, "POST", "/api/v1/bets?x=1", "1700000000", body, signature))
+	assert.False(t, verifySignature(key, "POST", "/api/v1/bets", "1700000000", []byte(`{}`), signature))
+	assert.False(t, verifySignature(SigningKey("other"), "POST", "/api/v1/bets", "1700000000", body, signature))
+}
+
+// Every sensitive route is refused, even to a key holding every scope
+func TestRequiredScope_DeniesSensitiveRoutes(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/users/api-keys"},
+		{http.MethodGet, "/api/v1/users/sessions"},
+		{http.MethodGet, "/api/v1/users/profile"},
+		{http.MethodGet, "/api/v1/users/me/export"},
+		{http.MethodGet, "/api/v1/users/me/export/download"},
+		{http.MethodGet, "/api/v1/users/statements"},
+		{http.MethodGet, "/api/v1/users/statements/:id/download"},
+		{http.MethodGet, "/api/v1/users/referrals"},
+		{http.MethodGet, "/api/v1/kyc/status"},
+		{http.MethodGet, "/api/v1/responsible-gambling"},
+		{http.MethodGet, "/api/v1/admin/users"},
+		{http.MethodGet, "/api/v1/admin/users/:id/statements/:statement_id/download"},
+		{http.MethodPost, "/api/v1/users/me/erasure"},
+	}
+
+	for _, tt := range tests {
+		scope, ok := RequiredScope(tt.method, tt.path)
+		assert.False(t, ok, "%s %s", tt.method, tt.path)
+		assert.Empty(t, scope, "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestGenerateCredentials(t *testing.T) {
+	keyID, secret, err := generateCredentials()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(keyID, KeyIDPrefix))
+	assert.Len(t, secret, 64)
+
+	otherID, otherSecret, err := generateCredentials()
+	require.NoError(t, err)
+	assert.NotEqual(t, keyID, otherID)
+	assert.NotEqual(t, secret, otherSecret)
+}
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		scope  models.APIKeyScope
+		ok     bool
+	}{
+		{http.MethodGet, "/api/v1/markets", models.APIKeyScopeRead, true},
+		{http.MethodGet, "/api/v1/bets/positions", models.APIKeyScopeRead, true},
+		{http.MethodGet, "/api/v1/wallets/:id", models.APIKeyScopeWalletRead, true},
+		{http.MethodGet, "/api/v1/users/:user_id/wallets", models.APIKeyScopeWalletRead, true},
+		{http.MethodPost, "/api/v1/bets", models.APIKeyScopeTrade, true},
+		{http.MethodPost, "/api/v1/bets/:id/cancel", models.APIKeyScopeTrade, true},
+		{http.MethodPost, "/api/v1/wallets/:id/debit", "", false},
+		{http.MethodPost, "/api/v1/markets", "", false},
+		{http.MethodGet, "/api/v1/users/api-keys", "", false},
+		{http.MethodPost, "/api/v1/kyc/submissions", "", false},
+		{http.MethodGet, "/api/v1/betsfoo", "", false},
+	}
+
+	for _, tt := range tests {
+		scope, ok := RequiredScope(tt.method, tt.path)
+		assert.Equal(t, tt.ok, ok, "%s %s", tt.method, tt.path)
+		assert.Equal(t, tt.scope, scope, "%s %s", tt.method, tt.path)
+	}
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+)
+
+type actorContextKey struct{}
+
+// Actor identifies who performed a request and where it came from.
+// UserID is uuid.Nil for unauthenticated requests. ImpersonatorID is set when
+// an admin is acting as UserID with an impersonation token.
+type Actor struct {
+	UserID         uuid.UUID
+	ImpersonatorID uuid.UUID
+	IP             net.IP
+	UserAgent      string
+}
+
+// WithActor returns a copy of ctx carrying the actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor attached by ContextMiddleware, or the zero Actor.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorContextKey{}).(Actor)
+	return actor
+}
+
+// NewLog builds an audit entry attributed to the actor in ctx. Without an
+// authenticated actor the entry is recorded as a system action. Under
+// impersonation the admin is the actor and the user is kept in the new values.
+func NewLog(ctx context.Context,
+	action, resourceType string,
+	resourceID *uuid.UUID,
+	oldValues, newValues models.AuditValues) *models.AuditLog {
+	actor := ActorFromContext(ctx)
+	if actor.ImpersonatorID != uuid.Nil {
+		values := models.AuditValues{"impersonated_user_id": actor.UserID.String()}
+		for key, value := range newValues {
+			values[key] = value
+		}
+		return models.CreateUserAuditLog(actor.ImpersonatorID, action, resourceType, resourceID,
+			oldValues, values, actor.IP, actor.UserAgent)
+	}
+	if actor.UserID == uuid.Nil {
+		log := models.CreateSystemAuditLog(action, resourceType, resourceID, oldValues, newValues)
+		log.IPAddress = actor.IP
+		log.UserAgent = actor.UserAgent
+		return log
+	}
+	return models.CreateUserAuditLog(actor.UserID, action, resourceType, resourceID,
+		oldValues, newValues, actor.IP, actor.UserAgent)
+}
@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// LogFilters defines the query parameters for browsing the audit trail.
+// From and To accept RFC3339 timestamps.
+type LogFilters struct {
+	Page         int    `form:"page"`
+	PerPage      int    `form:"per_page"`
+	UserID       string `form:"user_id"`
+	Action       string `form:"action"`
+	ResourceType string `form:"resource_type"`
+	ResourceID   string `form:"resource_id"`
+	From         string `form:"from"`
+	To           string `form:"to"`
+
+	ParsedUserID     *uuid.UUID `form:"-"`
+	ParsedResourceID *uuid.UUID `form:"-"`
+	ParsedFrom       *time.Time `form:"-"`
+	ParsedTo         *time.Time `form:"-"`
+}
+
+// SanitizeAndValidate cleans the filter inputs and parses the typed values.
+func (f *LogFilters) SanitizeAndValidate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	f.UserID = s.StripHTML(f.UserID)
+	f.Action = s.StripHTML(f.Action)
+	f.ResourceType = s.StripHTML(f.ResourceType)
+	f.ResourceID = s.StripHTML(f.ResourceID)
+	f.From = s.StripHTML(f.From)
+	f.To = s.StripHTML(f.To)
+
+	v.Check(validator.MaxRunes(f.Action, 50), "action", "action must not be more than 50 characters")
+	v.Check(validator.MaxRunes(f.ResourceType, 50), "resource_type", "resource type must not be more than 50 characters")
+
+	f.ParsedUserID = parseUUID(v, "user_id", f.UserID)
+	f.ParsedResourceID = parseUUID(v, "resource_id", f.ResourceID)
+	f.ParsedFrom = parseTime(v, "from", f.From)
+	f.ParsedTo = parseTime(v, "to", f.To)
+
+	if f.ParsedFrom != nil && f.ParsedTo != nil {
+		v.Check(!f.ParsedTo.Before(*f.ParsedFrom), "to", "to must not be before from")
+	}
+}
+
+func parseUUID(v *validator.Validator, field, value string) *uuid.UUID {
+	if value == "" {
+		return nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		v.AddError(field, "must be a valid UUID")
+		return nil
+	}
+	return &id
+}
+
+func parseTime(v *validator.Validator, field, value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		v.AddError(field, "must be an RFC3339 timestamp")
+		return nil
+	}
+	return &t
+}
+
+// LogResponse represents an audit entry in API responses.
+type LogResponse struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        *uuid.UUID         `json:"user_id"`
+	Action        string             `json:"action"`
+	ResourceType  string             `json:"resource_type"`
+	ResourceID    *uuid.UUID         `json:"resource_id"`
+	OldValues     models.AuditValues `json:"old_values"`
+	NewValues     models.AuditValues `json:"new_values"`
+	ChangedFields []string           `json:"changed_fields"`
+	IPAddress     string             `json:"ip_address,omitempty"`
+	UserAgent     string             `json:"user_agent,omitempty"`
+	IsSystem      bool               `json:"is_system"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// ToLogResponse converts an audit log model.
+func ToLogResponse(log *models.AuditLog) *LogResponse {
+	response := &LogResponse{
+		ID:            log.ID,
+		UserID:        log.UserID,
+		Action:        log.Action,
+		ResourceType:  log.ResourceType,
+		ResourceID:    log.ResourceID,
+		OldValues:     log.OldValues,
+		NewValues:     log.NewValues,
+		ChangedFields: changedFields(log),
+		UserAgent:     log.UserAgent,
+		IsSystem:      log.IsSystemAction(),
+		CreatedAt:     log.CreatedAt,
+	}
+	if log.IPAddress != nil {
+		response.IPAddress = log.IPAddress.String()
+	}
+	return response
+}
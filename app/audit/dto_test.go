@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+)
+
+func TestLogFilters_SanitizeAndValidate(t *testing.T) {
+	userID := uuid.New()
+	filters := &LogFilters{
+		UserID: userID.String(),
+		Action: "wallet_debited",
+		From:   "2026-01-01T00:00:00Z",
+		To:     "2026-02-01T00:00:00Z",
+	}
+
+	v := validator.New()
+	filters.SanitizeAndValidate(v, sanitizer.NewHTMLStripper())
+
+	assert.True(t, v.Valid())
+	assert.Equal(t, userID, *filters.ParsedUserID)
+	assert.Nil(t, filters.ParsedResourceID)
+	assert.NotNil(t, filters.ParsedFrom)
+	assert.NotNil(t, filters.ParsedTo)
+}
+
+func TestLogFilters_Invalid(t *testing.T) {
+	filters := &LogFilters{
+		UserID:     "nope",
+		ResourceID: "nope",
+		From:       "2026-02-01T00:00:00Z",
+		To:         "2026-01-01T00:00:00Z",
+	}
+
+	v := validator.New()
+	filters.SanitizeAndValidate(v, sanitizer.NewHTMLStripper())
+
+	assert.False(t, v.Valid())
+	assert.Contains(t, v.Errors, "user_id")
+	assert.Contains(t, v.Errors, "resource_id")
+	assert.Contains(t, v.Errors, "to")
+}
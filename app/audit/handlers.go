@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+)
+
+// Handler handles HTTP requests for the audit trail
+type Handler struct {
+	service   Service
+	sanitizer sanitizer.HTMLStripperer
+	logger    logger.Logger
+}
+
+// NewHandler creates a new audit handler
+func NewHandler(service Service, s sanitizer.HTMLStripperer, lg logger.Logger) *Handler {
+	return &Handler{service: service, sanitizer: s, logger: lg}
+}
+
+// GetAuditLogs godoc
+// @Summary      List audit logs (Admin)
+// @Description  Retrieves a paginated, filtered view of the audit trail, newest first
+// @Tags         Admin
+// @Produce      json
+// @Param        page          query int    false "Page number" default(1)
+// @Param        per_page      query int    false "Items per page" default(20)
+// @Param        user_id       query string false "Filter by acting user ID"
+// @Param        action        query string false "Filter by action"
+// @Param        resource_type query string false "Filter by resource type"
+// @Param        resource_id   query string false "Filter by resource ID"
+// @Param        from          query string false "Only entries at or after this RFC3339 time"
+// @Param        to            query string false "Only entries at or before this RFC3339 time"
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=[]LogResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/audit-logs [get]
+func (h *Handler) GetAuditLogs(c *gin.Context) {
+	var filters LogFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	filters.SanitizeAndValidate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	logs, total, err := h.service.List(c.Request.Context(), &filters)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetAuditLogs"})
+		api.InternalErrorResponse(c, "Failed to retrieve audit logs")
+		return
+	}
+
+	api.PaginatedResponse(c, "Audit logs retrieved successfully", logs, api.PaginationMeta{
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+		Total:   total,
+	})
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "audit_repository"
+	ServiceKey = "audit_service"
+)
+
+// MountAdmin mounts the audit trail routes
+func MountAdmin(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	r.GET("/admin/audit-logs", api.Can(PermissionLogsRead), handler.GetAuditLogs)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+	container.RegisterService(ServiceKey, NewService(repo))
+}
+
+// AdminMiddleware returns the audit middleware for admin route groups.
+func AdminMiddleware(container *deps.Container) gin.HandlerFunc {
+	return Middleware(container.GetService(ServiceKey).(Service), container.Logger)
+}
+
+// ImpersonationAuditMiddleware returns the global middleware that audits impersonated requests.
+func ImpersonationAuditMiddleware(container *deps.Container) gin.HandlerFunc {
+	return ImpersonationMiddleware(container.GetService(ServiceKey).(Service), container.Logger)
+}
+
+// createHandler creates an audit handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+	return NewHandler(service, container.Sanitizer, container.Logger)
+}
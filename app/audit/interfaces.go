@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/joefazee/neo/models"
+)
+
+type Repository interface {
+	Create(ctx context.Context, log *models.AuditLog) error
+	List(ctx context.Context, filters *LogFilters) ([]models.AuditLog, int64, error)
+}
+
+type Service interface {
+	Record(ctx context.Context, log *models.AuditLog) error
+	List(ctx context.Context, filters *LogFilters) ([]LogResponse, int64, error)
+}
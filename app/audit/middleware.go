@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+// maxCapturedBodySize bounds how much of an admin request body is copied into the audit trail
+const maxCapturedBodySize = 64 << 10
+
+// redactedValue replaces sensitive request fields in the audit trail
+const redactedValue = "[REDACTED]"
+
+// sensitiveFieldMarkers flags request fields that must never be stored
+var sensitiveFieldMarkers = []string{"password", "secret", "token"}
+
+// ContextMiddleware attaches the request's Actor to the request context so
+// services can attribute audit entries. Mount it globally to capture the client
+// address, and again after authentication to pick up the user.
+func ContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(WithActor(c.Request.Context(), actorFromRequest(c)))
+		c.Next()
+	}
+}
+
+// Middleware records every mutating request on the routes it guards, including
+// rejected ones. It must run after authentication.
+func Middleware(service Service, lg logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := actorFromRequest(c)
+		c.Request = c.Request.WithContext(WithActor(c.Request.Context(), actor))
+
+		if !isMutation(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		body := captureBody(c)
+		c.Next()
+
+		newValues := models.AuditValues{
+			"method": c.Request.Method,
+			"route":  c.FullPath(),
+			"status": c.Writer.Status(),
+		}
+		if body != nil {
+			newValues["body"] = body
+		}
+
+		log := NewLog(c.Request.Context(), models.AuditActionAdminRequest,
+			resourceTypeFromRoute(c.FullPath()), resourceIDFromParams(c), nil, newValues)
+		if err := service.Record(c.Request.Context(), log); err != nil {
+			lg.Error(err, logger.Fields{"middleware": "audit", "route": c.FullPath(), "user_id": actor.UserID})
+		}
+	}
+}
+
+// ImpersonationMiddleware records every request made with an impersonation
+// token, including those rejected by the auth middleware. Mount it globally.
+func ImpersonationMiddleware(service Service, lg logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		actor := actorFromRequest(c)
+		if actor.ImpersonatorID == uuid.Nil {
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		newValues := models.AuditValues{
+			"method": c.Request.Method,
+			"route":  route,
+			"path":   c.Request.URL.Path,
+			"status": c.Writer.Status(),
+		}
+
+		ctx := WithActor(c.Request.Context(), actor)
+		log := NewLog(ctx, models.AuditActionImpersonated, resourceTypeFromRoute(route), resourceIDFromParams(c), nil, newValues)
+		if err := service.Record(ctx, log); err != nil {
+			lg.Error(err, logger.Fields{"middleware": "audit_impersonation", "route": route, "impersonator_id": actor.ImpersonatorID})
+		}
+	}
+}
+
+func actorFromRequest(c *gin.Context) Actor {
+	userID, _ := api.UserIDFromContext(c)
+	impersonatorID, _ := api.ImpersonatorIDFromContext(c)
+	return Actor{
+		UserID:         userID,
+		ImpersonatorID: impersonatorID,
+		IP:             net.ParseIP(c.ClientIP()),
+		UserAgent:      c.Request.UserAgent(),
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// captureBody copies a JSON object body for the audit trail and restores it for the handler.
+func captureBody(c *gin.Context) map[string]interface{} {
+	if c.Request.Body == nil {
+		return nil
+	}
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCapturedBodySize+1))
+	if err != nil {
+		return nil
+	}
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(raw), c.Request.Body))
+	if len(raw) == 0 || len(raw) > maxCapturedBodySize {
+		return nil
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil
+	}
+	return redact(body)
+}
+
+func redact(values map[string]interface{}) map[string]interface{} {
+	for key, value := range values {
+		if isSensitiveField(key) {
+			values[key] = redactedValue
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			values[key] = redact(nested)
+		}
+	}
+	return values
+}
+
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range sensitiveFieldMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceTypeFromRoute names the resource after the first path segment past
+// the API and admin prefixes, e.g. /api/v1/admin/users/:id becomes "users".
+func resourceTypeFromRoute(route string) string {
+	route = strings.TrimPrefix(route, "/api/v1")
+	route = strings.TrimPrefix(route, "/admin")
+	segment := strings.Split(strings.TrimPrefix(route, "/"), "/")[0]
+	if segment == "" || strings.HasPrefix(segment, ":") {
+		return "unknown"
+	}
+	if len(segment) > 50 {
+		segment = segment[:50]
+	}
+	return segment
+}
+
+func resourceIDFromParams(c *gin.Context) *uuid.UUID {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
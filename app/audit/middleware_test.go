@@ -0,0 +1,208 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Record(ctx context.Context, log *models.AuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockService) List(ctx context.Context, filters *LogFilters) ([]LogResponse, int64, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]LogResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func newAuditedRouter(service Service, actorID uuid.UUID, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(api.ContextUserIDKey, actorID)
+		c.Next()
+	})
+	r.Use(Middleware(service, logger.NewNullLogger()))
+	r.PATCH("/api/v1/admin/users/:id/status", handler)
+	r.GET("/api/v1/admin/users/:id", handler)
+	return r
+}
+
+func TestMiddleware_RecordsMutation(t *testing.T) {
+	service := &MockService{}
+	actorID := uuid.New()
+	targetID := uuid.New()
+
+	var recorded *models.AuditLog
+	service.On("Record", mock.Anything, mock.AnythingOfType("*models.AuditLog")).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).(*models.AuditLog) }).
+		Return(nil)
+
+	var handlerBody string
+	var handlerActor Actor
+	r := newAuditedRouter(service, actorID, func(c *gin.Context) {
+		raw, _ := io.ReadAll(c.Request.Body)
+		handlerBody = string(raw)
+		handlerActor = ActorFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	body := `{"is_active":false,"admin_password":"hunter2","nested":{"api_token":"t"}}`
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/admin/users/"+targetID.String()+"/status", strings.NewReader(body))
+	req.Header.Set("User-Agent", "audit-test")
+	req.RemoteAddr = "10.1.2.3:4567"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, body, handlerBody, "handler must still see the full body")
+	assert.Equal(t, actorID, handlerActor.UserID)
+
+	require.NotNil(t, recorded)
+	assert.Equal(t, actorID, *recorded.UserID)
+	assert.Equal(t, models.AuditActionAdminRequest, recorded.Action)
+	assert.Equal(t, "users", recorded.ResourceType)
+	assert.Equal(t, targetID, *recorded.ResourceID)
+	assert.Equal(t, "10.1.2.3", recorded.IPAddress.String())
+	assert.Equal(t, "audit-test", recorded.UserAgent)
+	assert.Equal(t, http.MethodPatch, recorded.NewValues["method"])
+	assert.Equal(t, "/api/v1/admin/users/:id/status", recorded.NewValues["route"])
+	assert.Equal(t, http.StatusOK, recorded.NewValues["status"])
+
+	captured := recorded.NewValues["body"].(map[string]interface{})
+	assert.Equal(t, false, captured["is_active"])
+	assert.Equal(t, redactedValue, captured["admin_password"])
+	assert.Equal(t, redactedValue, captured["nested"].(map[string]interface{})["api_token"])
+}
+
+func TestMiddleware_RecordsRejectedMutation(t *testing.T) {
+	service := &MockService{}
+	service.On("Record", mock.Anything, mock.MatchedBy(func(log *models.AuditLog) bool {
+		return log.NewValues["status"] == http.StatusForbidden
+	})).Return(nil)
+
+	r := newAuditedRouter(service, uuid.New(), func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusForbidden)
+	})
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/admin/users/"+uuid.NewString()+"/status", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	service.AssertExpectations(t)
+}
+
+func TestMiddleware_SkipsReads(t *testing.T) {
+	service := &MockService{}
+	r := newAuditedRouter(service, uuid.New(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/"+uuid.NewString(), nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	service.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+}
+
+func TestContextMiddleware_AnonymousActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ContextMiddleware())
+
+	var entry *models.AuditLog
+	r.POST("/api/v1/auth/login", func(c *gin.Context) {
+		entry = NewLog(c.Request.Context(), models.AuditActionAccountLocked, models.AuditResourceUser, nil, nil, nil)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+	req.Header.Set("User-Agent", "audit-test")
+	req.RemoteAddr = "10.1.2.3:4567"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, entry)
+	assert.True(t, entry.IsSystemAction())
+	assert.Equal(t, "10.1.2.3", entry.IPAddress.String())
+	assert.Equal(t, "audit-test", entry.UserAgent)
+}
+
+func TestResourceTypeFromRoute(t *testing.T) {
+	assert.Equal(t, "users", resourceTypeFromRoute("/api/v1/admin/users/:id/status"))
+	assert.Equal(t, "roles", resourceTypeFromRoute("/api/v1/admin/roles"))
+	assert.Equal(t, "markets", resourceTypeFromRoute("/api/v1/markets/:id/resolve"))
+	assert.Equal(t, "unknown", resourceTypeFromRoute(""))
+}
+
+func TestImpersonationMiddleware_RecordsEveryRequest(t *testing.T) {
+	service := &MockService{}
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	var recorded []*models.AuditLog
+	service.On("Record", mock.Anything, mock.AnythingOfType("*models.AuditLog")).
+		Run(func(args mock.Arguments) { recorded = append(recorded, args.Get(1).(*models.AuditLog)) }).
+		Return(nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ImpersonationMiddleware(service, logger.NewNullLogger()))
+	r.Use(func(c *gin.Context) {
+		c.Set(api.ContextUserIDKey, userID)
+		c.Set(api.ContextImpersonatorIDKey, adminID)
+		if c.Request.Method != http.MethodGet {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	})
+	r.GET("/api/v1/wallets/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/v1/wallets/:id/debit", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	walletID := uuid.New()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+walletID.String(), nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+walletID.String()+"/debit", nil))
+
+	require.Len(t, recorded, 2)
+	for _, log := range recorded {
+		assert.Equal(t, models.AuditActionImpersonated, log.Action)
+		assert.Equal(t, adminID, *log.UserID)
+		assert.Equal(t, userID.String(), log.NewValues["impersonated_user_id"])
+		assert.Equal(t, "wallets", log.ResourceType)
+		assert.Equal(t, walletID, *log.ResourceID)
+	}
+	assert.Equal(t, http.StatusOK, recorded[0].NewValues["status"])
+	assert.Equal(t, http.StatusForbidden, recorded[1].NewValues["status"])
+}
+
+func TestImpersonationMiddleware_IgnoresRegularRequests(t *testing.T) {
+	service := &MockService{}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ImpersonationMiddleware(service, logger.NewNullLogger()))
+	r.GET("/api/v1/wallets", func(c *gin.Context) {
+		c.Set(api.ContextUserIDKey, uuid.New())
+		c.Status(http.StatusOK)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/wallets", nil))
+
+	service.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,6 @@
+package audit
+
+import "github.com/joefazee/neo/app/api"
+
+// PermissionLogsRead is required to browse the audit trail.
+var PermissionLogsRead = api.DefinePermission("admin:audit:read", "View the audit trail")
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// List returns a page of audit entries, newest first.
+func (r *repository) List(ctx context.Context, filters *LogFilters) ([]models.AuditLog, int64, error) {
+	var logs []models.AuditLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+	if filters.ParsedUserID != nil {
+		query = query.Where("user_id = ?", *filters.ParsedUserID)
+	}
+	if filters.Action != "" {
+		query = query.Where("action = ?", filters.Action)
+	}
+	if filters.ResourceType != "" {
+		query = query.Where("resource_type = ?", filters.ResourceType)
+	}
+	if filters.ParsedResourceID != nil {
+		query = query.Where("resource_id = ?", *filters.ParsedResourceID)
+	}
+	if filters.ParsedFrom != nil {
+		query = query.Where("created_at >= ?", *filters.ParsedFrom)
+	}
+	if filters.ParsedTo != nil {
+		query = query.Where("created_at <= ?", *filters.ParsedTo)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting audit logs: %w", err)
+	}
+
+	offset := (filters.Page - 1) * filters.PerPage
+	err := query.Order("created_at DESC").Offset(offset).Limit(filters.PerPage).Find(&logs).Error
+	return logs, total, err
+}
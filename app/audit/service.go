@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joefazee/neo/models"
+)
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// Record validates and stores an audit entry.
+func (s *service) Record(ctx context.Context, log *models.AuditLog) error {
+	if err := log.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, log)
+}
+
+// List returns a page of the audit trail matching the filters.
+func (s *service) List(ctx context.Context, filters *LogFilters) ([]LogResponse, int64, error) {
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.PerPage < 1 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	logs, total, err := s.repo.List(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]LogResponse, 0, len(logs))
+	for i := range logs {
+		responses = append(responses, *ToLogResponse(&logs[i]))
+	}
+	return responses, total, nil
+}
+
+// changedFields returns the changed field names in a stable order.
+func changedFields(log *models.AuditLog) []string {
+	fields := log.GetChangedFields()
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/models"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockRepository) List(ctx context.Context, filters *LogFilters) ([]models.AuditLog, int64, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.AuditLog), args.Get(1).(int64), args.Error(2)
+}
+
+func TestService_Record(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	err := NewService(repo).Record(context.Background(),
+		models.CreateSystemAuditLog(models.AuditActionWalletCredited, models.AuditResourceWallet, nil, nil, nil))
+	assert.NoError(t, err)
+
+	err = NewService(repo).Record(context.Background(), &models.AuditLog{ResourceType: models.AuditResourceWallet})
+	assert.ErrorIs(t, err, models.ErrInvalidAuditAction)
+	repo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestService_List(t *testing.T) {
+	repo := &MockRepository{}
+	walletID := uuid.New()
+	logs := []models.AuditLog{{
+		ID:           uuid.New(),
+		Action:       models.AuditActionWalletCredited,
+		ResourceType: models.AuditResourceWallet,
+		ResourceID:   &walletID,
+		OldValues:    models.AuditValues{"balance": "10.00", "locked_balance": "0.00", "is_locked": false},
+		NewValues:    models.AuditValues{"balance": "25.00", "locked_balance": "0.00", "is_locked": false},
+	}}
+	repo.On("List", mock.Anything, mock.MatchedBy(func(f *LogFilters) bool {
+		return f.Page == 1 && f.PerPage == 20
+	})).Return(logs, int64(1), nil)
+
+	filters := &LogFilters{PerPage: 500}
+	responses, total, err := NewService(repo).List(context.Background(), filters)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, responses, 1)
+	assert.Equal(t, []string{"balance"}, responses[0].ChangedFields)
+	assert.True(t, responses[0].IsSystem)
+}
+
+func TestService_ListError(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("List", mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("db down"))
+
+	_, _, err := NewService(repo).List(context.Background(), &LogFilters{})
+	assert.Error(t, err)
+}
+
+func TestNewLog_UsesActor(t *testing.T) {
+	actorID := uuid.New()
+	ctx := WithActor(context.Background(), Actor{UserID: actorID, UserAgent: "agent"})
+
+	log := NewLog(ctx, models.AuditActionWalletDebited, models.AuditResourceWallet, nil,
+		models.AuditValues{"balance": "5.00"}, models.AuditValues{"balance": "1.00"})
+
+	assert.Equal(t, actorID, *log.UserID)
+	assert.Equal(t, "agent", log.UserAgent)
+	assert.True(t, NewLog(context.Background(), models.AuditActionBetPlaced, models.AuditResourceWallet, nil, nil, nil).IsSystemAction())
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
 )
 
 // CreateCategoryRequest represents the request to create a category
@@ -14,6 +15,9 @@ type CreateCategoryRequest struct {
 	Slug        string    `json:"slug" binding:"required,min=2,max=100,lowercase"`
 	Description string    `json:"description,omitempty" binding:"omitempty,max=500"`
 	SortOrder   int       `json:"sort_order,omitempty"`
+	// Optional bet limits for the category's markets, within the country's
+	MinBetAmount *decimal.Decimal `json:"min_bet_amount,omitempty" example:"200"`
+	MaxBetAmount *decimal.Decimal `json:"max_bet_amount,omitempty" example:"20000"`
 }
 
 // UpdateCategoryRequest represents the request to update a category
@@ -22,19 +26,24 @@ type UpdateCategoryRequest struct {
 	Description *string `json:"description,omitempty" binding:"omitempty,max=500"`
 	IsActive    *bool   `json:"is_active,omitempty"`
 	SortOrder   *int    `json:"sort_order,omitempty"`
+	// A zero bet limit removes the category's limit
+	MinBetAmount *decimal.Decimal `json:"min_bet_amount,omitempty" example:"200"`
+	MaxBetAmount *decimal.Decimal `json:"max_bet_amount,omitempty" example:"20000"`
 }
 
 // CategoryResponse represents the response for category data
 type CategoryResponse struct {
-	ID          uuid.UUID `json:"id"`
-	CountryID   uuid.UUID `json:"country_id"`
-	Name        string    `json:"name"`
-	Slug        string    `json:"slug"`
-	Description string    `json:"description"`
-	IsActive    bool      `json:"is_active"`
-	SortOrder   int       `json:"sort_order"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           uuid.UUID        `json:"id"`
+	CountryID    uuid.UUID        `json:"country_id"`
+	Name         string           `json:"name"`
+	Slug         string           `json:"slug"`
+	Description  string           `json:"description"`
+	IsActive     bool             `json:"is_active"`
+	SortOrder    int              `json:"sort_order"`
+	MinBetAmount *decimal.Decimal `json:"min_bet_amount,omitempty"`
+	MaxBetAmount *decimal.Decimal `json:"max_bet_amount,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
 }
 
 // CategoryWithCountryResponse represents category data with country information
@@ -63,15 +72,17 @@ type CountryReference struct {
 // ToCategoryResponse converts a models.Category to CategoryResponse
 func ToCategoryResponse(category *models.Category) *CategoryResponse {
 	return &CategoryResponse{
-		ID:          category.ID,
-		CountryID:   category.CountryID,
-		Name:        category.Name,
-		Slug:        category.Slug,
-		Description: category.Description,
-		IsActive:    category.IsActive,
-		SortOrder:   category.SortOrder,
-		CreatedAt:   category.CreatedAt,
-		UpdatedAt:   category.UpdatedAt,
+		ID:           category.ID,
+		CountryID:    category.CountryID,
+		Name:         category.Name,
+		Slug:         category.Slug,
+		Description:  category.Description,
+		IsActive:     category.IsActive,
+		SortOrder:    category.SortOrder,
+		MinBetAmount: category.MinBetAmount,
+		MaxBetAmount: category.MaxBetAmount,
+		CreatedAt:    category.CreatedAt,
+		UpdatedAt:    category.UpdatedAt,
 	}
 }
 
@@ -160,7 +160,8 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 	if err != nil {
 		if errors.Is(err, models.ErrInvalidCountryID) ||
 			errors.Is(err, models.ErrInvalidCategoryName) ||
-			errors.Is(err, models.ErrInvalidCategorySlug) {
+			errors.Is(err, models.ErrInvalidCategorySlug) ||
+			errors.Is(err, models.ErrInvalidBetAmountLimits) {
 			api.BadRequestResponse(c, err.Error())
 			return
 		}
@@ -205,7 +206,8 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 			api.NotFoundResponse(c, "Category")
 			return
 		}
-		if errors.Is(err, models.ErrInvalidCategoryName) {
+		if errors.Is(err, models.ErrInvalidCategoryName) ||
+			errors.Is(err, models.ErrInvalidBetAmountLimits) {
 			api.BadRequestResponse(c, err.Error())
 			return
 		}
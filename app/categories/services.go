@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 
 	"github.com/joefazee/neo/models"
@@ -84,12 +85,14 @@ func (s *service) CreateCategory(ctx context.Context, req CreateCategoryRequest)
 	}
 
 	category := &models.Category{
-		CountryID:   req.CountryID,
-		Name:        strings.TrimSpace(req.Name),
-		Slug:        slug,
-		Description: strings.TrimSpace(req.Description),
-		IsActive:    true,
-		SortOrder:   req.SortOrder,
+		CountryID:    req.CountryID,
+		Name:         strings.TrimSpace(req.Name),
+		Slug:         slug,
+		Description:  strings.TrimSpace(req.Description),
+		IsActive:     true,
+		SortOrder:    req.SortOrder,
+		MinBetAmount: req.MinBetAmount,
+		MaxBetAmount: req.MaxBetAmount,
 	}
 
 	if err := category.Validate(); err != nil {
@@ -126,6 +129,12 @@ func (s *service) UpdateCategory(ctx context.Context, id uuid.UUID, req UpdateCa
 	if req.SortOrder != nil {
 		category.SortOrder = *req.SortOrder
 	}
+	if req.MinBetAmount != nil {
+		category.MinBetAmount = nonZeroLimit(*req.MinBetAmount)
+	}
+	if req.MaxBetAmount != nil {
+		category.MaxBetAmount = nonZeroLimit(*req.MaxBetAmount)
+	}
 
 	if err := category.Validate(); err != nil {
 		return nil, err
@@ -152,6 +161,14 @@ func (s *service) DeleteCategory(ctx context.Context, id uuid.UUID) error {
 	return s.repo.Delete(ctx, id)
 }
 
+// nonZeroLimit turns a zero bet limit into no limit
+func nonZeroLimit(amount decimal.Decimal) *decimal.Decimal {
+	if amount.IsZero() {
+		return nil
+	}
+	return &amount
+}
+
 // normalizeSlug normalizes a slug to lowercase and replaces spaces with hyphens
 func (s *service) normalizeSlug(slug string) string {
 	// Convert to lowercase
@@ -4,6 +4,7 @@ import (
 	"github.com/joefazee/neo/app/database"
 	"github.com/joefazee/neo/app/markets"
 	"github.com/joefazee/neo/app/prediction"
+	"github.com/joefazee/neo/app/rbac"
 	"github.com/joefazee/neo/app/user"
 	"github.com/joefazee/neo/internal/nexus"
 )
@@ -13,6 +14,7 @@ type Config struct {
 	User       user.Config
 	Market     markets.Config
 	Prediction prediction.Config
+	RBAC       rbac.Config
 
 	AppHost string `env:"APP_HOST" default:"localhost"`
 	AppPort string `env:"APP_PORT" default:"8080"`
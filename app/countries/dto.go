@@ -19,6 +19,8 @@ type CreateCountryRequest struct {
 	MaxBet         decimal.Decimal `json:"max_bet" binding:"required,gt=0"`
 	KYCRequired    bool            `json:"kyc_required"`
 	Timezone       string          `json:"timezone,omitempty"`
+	// RiskRules replaces the global risk settings for the country's markets
+	RiskRules *models.RiskRuleSettings `json:"risk_rules,omitempty"`
 }
 
 // UpdateCountryRequest represents the request to update a country
@@ -31,6 +33,8 @@ type UpdateCountryRequest struct {
 	MaxBet         *decimal.Decimal `json:"max_bet,omitempty" binding:"omitempty,gt=0"`
 	KYCRequired    *bool            `json:"kyc_required,omitempty"`
 	Timezone       *string          `json:"timezone,omitempty"`
+	// RiskRules replaces the country's risk settings
+	RiskRules *models.RiskRuleSettings `json:"risk_rules,omitempty"`
 }
 
 // CountryResponse represents the response for country data
@@ -54,6 +58,8 @@ type CountryConfigResponse struct {
 	KYCRequired  bool            `json:"kyc_required"`
 	TaxRate      decimal.Decimal `json:"tax_rate,omitempty"`
 	Timezone     string          `json:"timezone,omitempty"`
+	// RiskRules is nil when the country uses the global risk settings
+	RiskRules *models.RiskRuleSettings `json:"risk_rules,omitempty"`
 }
 
 // ToCountryResponse converts a models.Country to CountryResponse
@@ -77,6 +83,7 @@ func ToCountryResponse(country *models.Country) *CountryResponse {
 			KYCRequired:  country.Config.KYCRequired,
 			TaxRate:      country.Config.TaxRate,
 			Timezone:     country.Config.Timezone,
+			RiskRules:    country.Config.RiskRules,
 		}
 	} else {
 		res.Config = CountryConfigResponse{}
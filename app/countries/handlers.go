@@ -151,7 +151,8 @@ func (h *Handler) CreateCountry(c *gin.Context) {
 		if errors.Is(err, models.ErrInvalidCountryCode) ||
 			errors.Is(err, models.ErrInvalidCountryName) ||
 			errors.Is(err, models.ErrInvalidCurrencyCode) ||
-			errors.Is(err, models.ErrInvalidCurrencySymbol) {
+			errors.Is(err, models.ErrInvalidCurrencySymbol) ||
+			errors.Is(err, models.ErrInvalidRiskScoreThreshold) {
 			api.BadRequestResponse(c, err.Error())
 			return
 		}
@@ -198,7 +199,8 @@ func (h *Handler) UpdateCountry(c *gin.Context) {
 			return
 		}
 		if errors.Is(err, models.ErrInvalidCountryName) ||
-			errors.Is(err, models.ErrInvalidCurrencySymbol) {
+			errors.Is(err, models.ErrInvalidCurrencySymbol) ||
+			errors.Is(err, models.ErrInvalidRiskScoreThreshold) {
 			api.BadRequestResponse(c, err.Error())
 			return
 		}
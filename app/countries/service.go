@@ -93,12 +93,18 @@ func (s *service) CreateCountry(ctx context.Context, req *CreateCountryRequest)
 			MaxBet:       req.MaxBet,
 			KYCRequired:  req.KYCRequired,
 			Timezone:     req.Timezone,
+			RiskRules:    req.RiskRules,
 		},
 	}
 
 	if err := country.Validate(); err != nil {
 		return nil, err
 	}
+	if req.RiskRules != nil {
+		if err := req.RiskRules.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.repo.Create(ctx, country); err != nil {
 		return nil, err
@@ -144,6 +150,12 @@ func (s *service) UpdateCountry(ctx context.Context, id uuid.UUID, req UpdateCou
 	if req.Timezone != nil {
 		country.Config.Timezone = *req.Timezone
 	}
+	if req.RiskRules != nil {
+		if err := req.RiskRules.Validate(); err != nil {
+			return nil, err
+		}
+		country.Config.RiskRules = req.RiskRules
+	}
 
 	// Validate min/max bet amounts if both are set
 	if !country.Config.MinBet.IsZero() && !country.Config.MaxBet.IsZero() {
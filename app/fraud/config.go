@@ -0,0 +1,71 @@
+package fraud
+
+import (
+	"errors"
+
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// Config represents the configuration for the fraud module
+type Config struct {
+	// SignalWeights is how strongly sharing each signal type ties two accounts
+	// to one person. Signal types without a weight are recorded but never link.
+	SignalWeights map[models.AccountSignalType]float64
+
+	// LinkThreshold is the combined weight of shared signal types at which two
+	// accounts are treated as linked.
+	LinkThreshold float64 `env:"FRAUD_LINK_THRESHOLD"`
+
+	// ThinMarketPool is the pool size below which linked accounts betting on
+	// different outcomes of the same market are flagged for collusion.
+	ThinMarketPool decimal.Decimal `env:"FRAUD_THIN_MARKET_POOL"`
+
+	// AutoLockWallets locks the wallets of every account in a case as soon as
+	// it is opened, instead of waiting for an admin to confirm it.
+	AutoLockWallets bool `env:"FRAUD_AUTO_LOCK_WALLETS"`
+}
+
+func (c *Config) Validate() error {
+	for signal, weight := range c.SignalWeights {
+		if !signal.IsValid() {
+			return errors.New("unknown account signal type: " + string(signal))
+		}
+		if weight < 0 {
+			return errors.New("signal weights cannot be negative")
+		}
+	}
+	if c.LinkThreshold <= 0 {
+		return errors.New("link threshold must be positive")
+	}
+	if !c.ThinMarketPool.IsPositive() {
+		return errors.New("thin market pool must be positive")
+	}
+	return nil
+}
+
+// LinkScore is the combined weight of the shared signal types
+func (c *Config) LinkScore(shared []models.AccountSignalType) float64 {
+	var score float64
+	for _, signal := range shared {
+		score += c.SignalWeights[signal]
+	}
+	return score
+}
+
+// GetDefaultConfig returns the default fraud configuration. A shared IP
+// address alone does not link accounts, since households and mobile carriers
+// put many people behind one address.
+func GetDefaultConfig() *Config {
+	return &Config{
+		SignalWeights: map[models.AccountSignalType]float64{
+			models.AccountSignalIP:                0.5,
+			models.AccountSignalDevice:            1,
+			models.AccountSignalPaymentInstrument: 1,
+			models.AccountSignalPhone:             1,
+		},
+		LinkThreshold:   1,
+		ThinMarketPool:  decimal.NewFromInt(50000), // ₦50,000
+		AutoLockWallets: false,
+	}
+}
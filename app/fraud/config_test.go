@@ -0,0 +1,38 @@
+package fraud
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joefazee/neo/models"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, GetDefaultConfig().Validate())
+
+	config := GetDefaultConfig()
+	config.SignalWeights["email"] = 1
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.SignalWeights[models.AccountSignalIP] = -1
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.LinkThreshold = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.ThinMarketPool = decimal.Zero
+	assert.Error(t, config.Validate())
+}
+
+func TestConfig_LinkScore(t *testing.T) {
+	config := GetDefaultConfig()
+
+	assert.Less(t, config.LinkScore([]models.AccountSignalType{models.AccountSignalIP}), config.LinkThreshold)
+	assert.GreaterOrEqual(t, config.LinkScore([]models.AccountSignalType{models.AccountSignalDevice}), config.LinkThreshold)
+	assert.GreaterOrEqual(t, config.LinkScore([]models.AccountSignalType{models.AccountSignalIP, models.AccountSignalPhone}), config.LinkThreshold)
+}
@@ -0,0 +1,84 @@
+package fraud
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// ReviewRequest is an admin decision on an open case. Confirming a case locks
+// the accounts' wallets; dismissing it releases the locks the case placed.
+type ReviewRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// Validate sanitizes and checks the review decision.
+func (r *ReviewRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	r.Status = s.StripHTML(r.Status)
+	r.Note = s.StripHTML(r.Note)
+
+	v.Check(validator.In(models.FraudCaseStatus(r.Status), models.FraudCaseStatusConfirmed, models.FraudCaseStatusDismissed),
+		"status", "status must be either confirmed or dismissed")
+	v.Check(validator.NotBlank(r.Note), "note", "a note is required")
+	v.Check(validator.MaxRunes(r.Note, 1000), "note", "note must not be more than 1000 characters")
+}
+
+// CaseFilters defines the query parameters for the case queue.
+type CaseFilters struct {
+	Page    int    `form:"page"`
+	PerPage int    `form:"per_page"`
+	Status  string `form:"status"`
+	Kind    string `form:"kind"`
+}
+
+// SanitizeAndValidate cleans and validates the filter inputs.
+func (f *CaseFilters) SanitizeAndValidate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	f.Status = s.StripHTML(f.Status)
+	f.Kind = s.StripHTML(f.Kind)
+
+	v.Check(validator.In(models.FraudCaseStatus(f.Status), "",
+		models.FraudCaseStatusOpen,
+		models.FraudCaseStatusConfirmed,
+		models.FraudCaseStatusDismissed), "status", "invalid status")
+	v.Check(validator.In(models.FraudCaseKind(f.Kind), "",
+		models.FraudCaseMultiAccounting,
+		models.FraudCaseCollusion), "kind", "invalid kind")
+}
+
+// CaseResponse represents a fraud case in API responses.
+type CaseResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	Kind          models.FraudCaseKind   `json:"kind"`
+	Status        models.FraudCaseStatus `json:"status"`
+	MarketID      *uuid.UUID             `json:"market_id,omitempty"`
+	UserIDs       []uuid.UUID            `json:"user_ids"`
+	Evidence      models.FraudEvidence   `json:"evidence"`
+	WalletsLocked bool                   `json:"wallets_locked"`
+	ReviewNote    string                 `json:"review_note,omitempty"`
+	ReviewedBy    *uuid.UUID             `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time             `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// ToCaseResponse converts a fraud case model.
+func ToCaseResponse(c *models.FraudCase) *CaseResponse {
+	return &CaseResponse{
+		ID:            c.ID,
+		Kind:          c.Kind,
+		Status:        c.Status,
+		MarketID:      c.MarketID,
+		UserIDs:       c.UserIDs(),
+		Evidence:      c.Evidence,
+		WalletsLocked: c.WalletsLocked,
+		ReviewNote:    c.ReviewNote,
+		ReviewedBy:    c.ReviewedBy,
+		ReviewedAt:    c.ReviewedAt,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
@@ -0,0 +1,159 @@
+package fraud
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// Handler handles HTTP requests for the fraud case queue
+type Handler struct {
+	service   Service
+	sanitizer sanitizer.HTMLStripperer
+	logger    logger.Logger
+}
+
+// NewHandler creates a new fraud handler
+func NewHandler(service Service, s sanitizer.HTMLStripperer, lg logger.Logger) *Handler {
+	return &Handler{service: service, sanitizer: s, logger: lg}
+}
+
+// GetCases godoc
+// @Summary      List fraud cases (Admin)
+// @Description  Retrieves the multi-accounting and collusion case queue. Defaults to open cases.
+// @Tags         Admin
+// @Produce      json
+// @Param        page     query int    false "Page number" default(1)
+// @Param        per_page query int    false "Items per page" default(20)
+// @Param        status   query string false "Filter by status" Enums(open, confirmed, dismissed)
+// @Param        kind     query string false "Filter by kind" Enums(multi_accounting, collusion)
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=[]CaseResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/fraud/cases [get]
+func (h *Handler) GetCases(c *gin.Context) {
+	var filters CaseFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	filters.SanitizeAndValidate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	cases, total, err := h.service.GetCases(c.Request.Context(), &filters)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetCases"})
+		api.InternalErrorResponse(c, "Failed to retrieve fraud cases")
+		return
+	}
+
+	api.PaginatedResponse(c, "Fraud cases retrieved successfully", cases, api.PaginationMeta{
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+		Total:   total,
+	})
+}
+
+// GetCase godoc
+// @Summary      Get fraud case (Admin)
+// @Description  Retrieves a single fraud case with its accounts and evidence
+// @Tags         Admin
+// @Produce      json
+// @Param        id path string true "Case ID"
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=CaseResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/fraud/cases/{id} [get]
+func (h *Handler) GetCase(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid case ID format")
+		return
+	}
+
+	fraudCase, err := h.service.GetCase(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Fraud case")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "GetCase", "case_id": id})
+		api.InternalErrorResponse(c, "Failed to retrieve fraud case")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Fraud case retrieved successfully", fraudCase)
+}
+
+// ReviewCase godoc
+// @Summary      Review fraud case (Admin)
+// @Description  Confirm or dismiss an open fraud case. Confirming locks the accounts' wallets; dismissing releases the locks the case placed.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id      path string        true "Case ID"
+// @Param        request body ReviewRequest true "Review decision"
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=CaseResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/fraud/cases/{id}/review [post]
+func (h *Handler) ReviewCase(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid case ID format")
+		return
+	}
+
+	reviewerID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	fraudCase, err := h.service.ReviewCase(c.Request.Context(), reviewerID, id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRecordNotFound):
+			api.NotFoundResponse(c, "Fraud case")
+		case errors.Is(err, models.ErrFraudCaseClosed):
+			api.ConflictResponse(c, "Fraud case has already been decided")
+		default:
+			h.logger.Error(err, logger.Fields{"handler": "ReviewCase", "case_id": id})
+			api.InternalErrorResponse(c, "Failed to review fraud case")
+		}
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Fraud case reviewed successfully", fraudCase)
+}
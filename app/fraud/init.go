@@ -0,0 +1,41 @@
+package fraud
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "fraud_repository"
+	ServiceKey = "fraud_service"
+)
+
+// MountAdmin mounts the fraud case queue
+func MountAdmin(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	adminGroup := r.Group("/admin/fraud/cases")
+	adminGroup.GET("", api.Can(PermissionCasesRead), handler.GetCases)
+	adminGroup.GET("/:id", api.Can(PermissionCasesRead), handler.GetCase)
+	adminGroup.POST("/:id/review", api.Can(PermissionCasesReview), handler.ReviewCase)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid fraud configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+	container.RegisterService(ServiceKey, NewService(repo, config, container.Logger))
+}
+
+// createHandler creates a fraud handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+
+	return NewHandler(service, container.Sanitizer, container.Logger)
+}
@@ -0,0 +1,56 @@
+package fraud
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+)
+
+type Repository interface {
+	RecordSignal(ctx context.Context, signal *models.AccountSignal) error
+	GetSignalsByUser(ctx context.Context, userID uuid.UUID) ([]models.AccountSignal, error)
+	GetMatchingSignals(ctx context.Context, userID uuid.UUID, signals []models.AccountSignal) ([]models.AccountSignal, error)
+
+	GetMarketByID(ctx context.Context, id uuid.UUID) (*models.Market, error)
+	GetMarketStakes(ctx context.Context, marketID uuid.UUID, userIDs []uuid.UUID) ([]models.FraudPosition, error)
+
+	FindCases(ctx context.Context, kind models.FraudCaseKind, marketID *uuid.UUID, userIDs []uuid.UUID) ([]models.FraudCase, error)
+	CreateCase(ctx context.Context, fraudCase *models.FraudCase) error
+	UpdateCase(ctx context.Context, fraudCase *models.FraudCase, added []uuid.UUID) error
+	GetCases(ctx context.Context, filters *CaseFilters) ([]models.FraudCase, int64, error)
+	GetCaseByID(ctx context.Context, id uuid.UUID) (*models.FraudCase, error)
+	SaveReview(ctx context.Context, fraudCase *models.FraudCase, log *models.AuditLog) error
+
+	LockWallets(ctx context.Context, userIDs []uuid.UUID, reason string) error
+	UnlockWallets(ctx context.Context, userIDs []uuid.UUID, reason string) error
+}
+
+// Signal is an identifier a user was seen with, such as the IP address of a
+// login or the fingerprint of a card they paid with
+type Signal struct {
+	Type  models.AccountSignalType
+	Value string
+}
+
+// Recorder collects identifying signals and links accounts that share them.
+// The user and wallet modules call it on registration, login and deposit.
+// Failures are logged rather than returned so they never block the user.
+type Recorder interface {
+	RecordSignals(ctx context.Context, userID uuid.UUID, signals ...Signal)
+}
+
+// Monitor looks for coordinated betting between linked accounts. The
+// prediction module calls it after each bet; failures are logged.
+type Monitor interface {
+	ReviewBet(ctx context.Context, bet *models.Bet)
+}
+
+type Service interface {
+	Recorder
+	Monitor
+
+	GetCases(ctx context.Context, filters *CaseFilters) ([]CaseResponse, int64, error)
+	GetCase(ctx context.Context, id uuid.UUID) (*CaseResponse, error)
+	ReviewCase(ctx context.Context, reviewerID, caseID uuid.UUID, req *ReviewRequest) (*CaseResponse, error)
+}
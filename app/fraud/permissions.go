@@ -0,0 +1,9 @@
+package fraud
+
+import "github.com/joefazee/neo/app/api"
+
+// Permissions required by the admin routes of this module.
+var (
+	PermissionCasesRead   = api.DefinePermission("admin:fraud:read", "View multi-accounting and collusion cases")
+	PermissionCasesReview = api.DefinePermission("admin:fraud:review", "Confirm or dismiss fraud cases")
+)
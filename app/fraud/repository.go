@@ -0,0 +1,243 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxMatchingSignals bounds how many other accounts' signals one lookup loads,
+// so a busy shared address cannot turn a login into a table scan
+const maxMatchingSignals = 500
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new fraud repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// RecordSignal stores the signal, or moves its last-seen time forward if the
+// user was already seen with it.
+func (r *repository) RecordSignal(ctx context.Context, signal *models.AccountSignal) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "value_hash"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"last_seen_at": signal.LastSeenAt}),
+		}).
+		Create(signal).Error
+}
+
+func (r *repository) GetSignalsByUser(ctx context.Context, userID uuid.UUID) ([]models.AccountSignal, error) {
+	var signals []models.AccountSignal
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&signals).Error
+	return signals, err
+}
+
+// GetMatchingSignals returns other users' signals with the same type and value
+// as any of the given ones.
+func (r *repository) GetMatchingSignals(ctx context.Context,
+	userID uuid.UUID,
+	signals []models.AccountSignal) ([]models.AccountSignal, error) {
+	if len(signals) == 0 {
+		return nil, nil
+	}
+	pairs := make([][]interface{}, 0, len(signals))
+	for _, signal := range signals {
+		pairs = append(pairs, []interface{}{signal.Type, signal.ValueHash})
+	}
+
+	var matches []models.AccountSignal
+	err := r.db.WithContext(ctx).
+		Where("user_id <> ?", userID).
+		Where("(type, value_hash) IN ?", pairs).
+		Order("last_seen_at DESC").
+		Limit(maxMatchingSignals).
+		Find(&matches).Error
+	return matches, err
+}
+
+func (r *repository) GetMarketByID(ctx context.Context, id uuid.UUID) (*models.Market, error) {
+	var market models.Market
+	if err := r.db.WithContext(ctx).First(&market, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &market, nil
+}
+
+// GetMarketStakes totals the users' active stakes on each outcome of the market.
+func (r *repository) GetMarketStakes(ctx context.Context, marketID uuid.UUID, userIDs []uuid.UUID) ([]models.FraudPosition, error) {
+	var positions []models.FraudPosition
+	err := r.db.WithContext(ctx).
+		Model(&models.Bet{}).
+		Select("user_id, market_outcome_id AS outcome_id, SUM(amount) AS amount").
+		Where("market_id = ? AND user_id IN ? AND status = ?", marketID, userIDs, models.BetStatusActive).
+		Group("user_id, market_outcome_id").
+		Order("user_id, market_outcome_id").
+		Scan(&positions).Error
+	return positions, err
+}
+
+// FindCases returns the cases of the kind that hold any of the users, open
+// cases first and then newest first.
+func (r *repository) FindCases(ctx context.Context,
+	kind models.FraudCaseKind,
+	marketID *uuid.UUID,
+	userIDs []uuid.UUID) ([]models.FraudCase, error) {
+	members := r.db.Model(&models.FraudCaseAccount{}).Select("case_id").Where("user_id IN ?", userIDs)
+
+	query := r.db.WithContext(ctx).
+		Preload("Accounts").
+		Where("kind = ?", kind).
+		Where("id IN (?)", members)
+	if marketID != nil {
+		query = query.Where("market_id = ?", *marketID)
+	}
+
+	var cases []models.FraudCase
+	err := query.Order("status = 'open' DESC, created_at DESC").Find(&cases).Error
+	return cases, err
+}
+
+// CreateCase stores the case with its accounts.
+func (r *repository) CreateCase(ctx context.Context, fraudCase *models.FraudCase) error {
+	return r.db.WithContext(ctx).Create(fraudCase).Error
+}
+
+// UpdateCase saves the case's evidence and lock state and adds the new accounts.
+func (r *repository) UpdateCase(ctx context.Context, fraudCase *models.FraudCase, added []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(fraudCase).Updates(map[string]interface{}{
+			"evidence":       fraudCase.Evidence,
+			"wallets_locked": fraudCase.WalletsLocked,
+		}).Error; err != nil {
+			return fmt.Errorf("updating fraud case: %w", err)
+		}
+		if len(added) == 0 {
+			return nil
+		}
+
+		accounts := make([]models.FraudCaseAccount, 0, len(added))
+		for _, userID := range added {
+			accounts = append(accounts, models.FraudCaseAccount{CaseID: fraudCase.ID, UserID: userID})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&accounts).Error; err != nil {
+			return fmt.Errorf("adding fraud case accounts: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetCases retrieves a paginated list of cases, oldest first so the queue is
+// worked in order of arrival.
+func (r *repository) GetCases(ctx context.Context, filters *CaseFilters) ([]models.FraudCase, int64, error) {
+	var cases []models.FraudCase
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.FraudCase{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.Kind != "" {
+		query = query.Where("kind = ?", filters.Kind)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting fraud cases: %w", err)
+	}
+
+	offset := (filters.Page - 1) * filters.PerPage
+	err := query.Preload("Accounts").
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(filters.PerPage).
+		Find(&cases).Error
+	return cases, total, err
+}
+
+func (r *repository) GetCaseByID(ctx context.Context, id uuid.UUID) (*models.FraudCase, error) {
+	var fraudCase models.FraudCase
+	if err := r.db.WithContext(ctx).Preload("Accounts").First(&fraudCase, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &fraudCase, nil
+}
+
+// SaveReview persists the decision and its audit entry atomically.
+func (r *repository) SaveReview(ctx context.Context, fraudCase *models.FraudCase, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(fraudCase).Updates(map[string]interface{}{
+			"status":         fraudCase.Status,
+			"review_note":    fraudCase.ReviewNote,
+			"reviewed_by":    fraudCase.ReviewedBy,
+			"reviewed_at":    fraudCase.ReviewedAt,
+			"wallets_locked": fraudCase.WalletsLocked,
+		}).Error; err != nil {
+			return fmt.Errorf("updating fraud case: %w", err)
+		}
+		return tx.Create(log).Error
+	})
+}
+
+// LockWallets locks every unlocked wallet of the users with the reason,
+// auditing each lock as the actor in ctx.
+func (r *repository) LockWallets(ctx context.Context, userIDs []uuid.UUID, reason string) error {
+	return r.updateWalletLocks(ctx, models.AuditActionWalletLocked, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("user_id IN ? AND is_locked = ?", userIDs, false)
+	}, func(wallet *models.Wallet) {
+		wallet.Lock(reason)
+	})
+}
+
+// UnlockWallets unlocks the users' wallets that are locked with the reason,
+// leaving locks placed for anything else in place.
+func (r *repository) UnlockWallets(ctx context.Context, userIDs []uuid.UUID, reason string) error {
+	return r.updateWalletLocks(ctx, models.AuditActionWalletUnlocked, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("user_id IN ? AND is_locked = ? AND lock_reason = ?", userIDs, true, reason)
+	}, func(wallet *models.Wallet) {
+		wallet.Unlock()
+	})
+}
+
+func (r *repository) updateWalletLocks(ctx context.Context,
+	action string,
+	scope func(tx *gorm.DB) *gorm.DB,
+	apply func(wallet *models.Wallet)) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wallets []models.Wallet
+		if err := scope(tx.Clauses(clause.Locking{Strength: "UPDATE"})).Find(&wallets).Error; err != nil {
+			return fmt.Errorf("loading wallets: %w", err)
+		}
+
+		for i := range wallets {
+			wallet := &wallets[i]
+			oldValues := wallet.AuditValues()
+			apply(wallet)
+			if err := tx.Model(wallet).Updates(map[string]interface{}{
+				"is_locked":   wallet.IsLocked,
+				"lock_reason": wallet.LockReason,
+			}).Error; err != nil {
+				return fmt.Errorf("updating wallet: %w", err)
+			}
+			log := audit.NewLog(ctx, action, models.AuditResourceWallet, &wallet.ID, oldValues, wallet.AuditValues())
+			if err := tx.Create(log).Error; err != nil {
+				return fmt.Errorf("failed to write audit log: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,336 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+type service struct {
+	repo   Repository
+	config *Config
+	logger logger.Logger
+}
+
+// NewService creates a new fraud service.
+func NewService(repo Repository, config *Config, lg logger.Logger) Service {
+	return &service{repo: repo, config: config, logger: lg}
+}
+
+func (s *service) RecordSignals(ctx context.Context, userID uuid.UUID, signals ...Signal) {
+	if err := s.recordSignals(ctx, userID, signals); err != nil {
+		s.logger.Error(err, logger.Fields{"component": "fraud", "user_id": userID})
+	}
+}
+
+// recordSignals stores the signals and puts the user in a multi-accounting
+// case with every account they are now linked to.
+func (s *service) recordSignals(ctx context.Context, userID uuid.UUID, signals []Signal) error {
+	now := time.Now()
+	recorded := 0
+	for _, signal := range signals {
+		if signal.Value == "" || !signal.Type.IsValid() {
+			continue
+		}
+		err := s.repo.RecordSignal(ctx, &models.AccountSignal{
+			UserID:      userID,
+			Type:        signal.Type,
+			ValueHash:   models.HashAccountSignal(signal.Type, signal.Value),
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record %s signal: %w", signal.Type, err)
+		}
+		recorded++
+	}
+	if recorded == 0 {
+		return nil
+	}
+
+	linked, err := s.linkedAccounts(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(linked) == 0 {
+		return nil
+	}
+
+	var evidence models.FraudEvidence
+	userIDs := []uuid.UUID{userID}
+	for _, linkedID := range sortedUserIDs(linked) {
+		userIDs = append(userIDs, linkedID)
+		evidence.AddSignals(linked[linkedID]...)
+	}
+	return s.flag(ctx, models.FraudCaseMultiAccounting, nil, userIDs, evidence)
+}
+
+func (s *service) ReviewBet(ctx context.Context, bet *models.Bet) {
+	if err := s.reviewBet(ctx, bet); err != nil {
+		s.logger.Error(err, logger.Fields{"component": "fraud", "user_id": bet.UserID, "bet_id": bet.ID})
+	}
+}
+
+// reviewBet flags the bettor and their linked accounts for collusion when
+// they hold stakes on different outcomes of a thin market.
+func (s *service) reviewBet(ctx context.Context, bet *models.Bet) error {
+	linked, err := s.linkedAccounts(ctx, bet.UserID)
+	if err != nil {
+		return err
+	}
+	if len(linked) == 0 {
+		return nil
+	}
+
+	market, err := s.repo.GetMarketByID(ctx, bet.MarketID)
+	if err != nil {
+		return fmt.Errorf("failed to get market: %w", err)
+	}
+	if market.TotalPoolAmount.GreaterThanOrEqual(s.config.ThinMarketPool) {
+		return nil
+	}
+
+	userIDs := append([]uuid.UUID{bet.UserID}, sortedUserIDs(linked)...)
+	positions, err := s.repo.GetMarketStakes(ctx, market.ID, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get market stakes: %w", err)
+	}
+	if !takeOpposingSides(positions) {
+		return nil
+	}
+
+	evidence := models.FraudEvidence{Positions: positions}
+	cluster := make([]uuid.UUID, 0, len(positions))
+	seen := make(map[uuid.UUID]bool, len(positions))
+	for _, position := range positions {
+		if seen[position.UserID] {
+			continue
+		}
+		seen[position.UserID] = true
+		cluster = append(cluster, position.UserID)
+		evidence.AddSignals(linked[position.UserID]...)
+	}
+	return s.flag(ctx, models.FraudCaseCollusion, &market.ID, cluster, evidence)
+}
+
+// takeOpposingSides reports whether two different accounts staked on two
+// different outcomes. One account hedging across outcomes is not collusion.
+func takeOpposingSides(positions []models.FraudPosition) bool {
+	for i := range positions {
+		for j := i + 1; j < len(positions); j++ {
+			if positions[i].UserID != positions[j].UserID && positions[i].OutcomeID != positions[j].OutcomeID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// linkedAccounts returns the other users who share enough signals with the
+// user to be linked to them, with the signal types they share.
+func (s *service) linkedAccounts(ctx context.Context, userID uuid.UUID) (map[uuid.UUID][]models.AccountSignalType, error) {
+	own, err := s.repo.GetSignalsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signals: %w", err)
+	}
+	matches, err := s.repo.GetMatchingSignals(ctx, userID, own)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matching signals: %w", err)
+	}
+
+	// A user can share several values of one type, such as two IP addresses
+	shared := make(map[uuid.UUID]*models.FraudEvidence)
+	for _, match := range matches {
+		if shared[match.UserID] == nil {
+			shared[match.UserID] = &models.FraudEvidence{}
+		}
+		shared[match.UserID].AddSignals(match.Type)
+	}
+
+	linked := make(map[uuid.UUID][]models.AccountSignalType)
+	for linkedID, evidence := range shared {
+		if s.config.LinkScore(evidence.Signals) >= s.config.LinkThreshold {
+			linked[linkedID] = evidence.Signals
+		}
+	}
+	return linked, nil
+}
+
+// sortedUserIDs returns the map's users in a stable order
+func sortedUserIDs(linked map[uuid.UUID][]models.AccountSignalType) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(linked))
+	for id := range linked {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+// flag grows the open case that already holds any of the accounts, so one
+// cluster is reviewed once, or opens a new case. A cluster that was already
+// decided is only reopened when accounts outside that decision join it.
+func (s *service) flag(ctx context.Context,
+	kind models.FraudCaseKind,
+	marketID *uuid.UUID,
+	userIDs []uuid.UUID,
+	evidence models.FraudEvidence) error {
+	cases, err := s.repo.FindCases(ctx, kind, marketID, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to find fraud cases: %w", err)
+	}
+
+	var fraudCase *models.FraudCase
+	for i := range cases {
+		if cases[i].IsOpen() {
+			fraudCase = &cases[i]
+			break
+		}
+		if coversAll(&cases[i], userIDs) {
+			return nil
+		}
+	}
+
+	if fraudCase == nil {
+		fraudCase = &models.FraudCase{
+			ID:       uuid.New(),
+			Kind:     kind,
+			Status:   models.FraudCaseStatusOpen,
+			MarketID: marketID,
+			Evidence: evidence,
+		}
+		fraudCase.AddAccounts(userIDs...)
+		if err := s.repo.CreateCase(ctx, fraudCase); err != nil {
+			return fmt.Errorf("failed to create fraud case: %w", err)
+		}
+		return s.autoLock(ctx, fraudCase, fraudCase.UserIDs())
+	}
+
+	added := fraudCase.AddAccounts(userIDs...)
+	changed := fraudCase.Evidence.AddSignals(evidence.Signals...) || len(added) > 0
+	if len(evidence.Positions) > 0 {
+		fraudCase.Evidence.Positions = evidence.Positions
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if err := s.repo.UpdateCase(ctx, fraudCase, added); err != nil {
+		return fmt.Errorf("failed to update fraud case: %w", err)
+	}
+	return s.autoLock(ctx, fraudCase, added)
+}
+
+// coversAll reports whether every user is already in the case
+func coversAll(fraudCase *models.FraudCase, userIDs []uuid.UUID) bool {
+	members := make(map[uuid.UUID]bool, len(fraudCase.Accounts))
+	for _, account := range fraudCase.Accounts {
+		members[account.UserID] = true
+	}
+	for _, userID := range userIDs {
+		if !members[userID] {
+			return false
+		}
+	}
+	return true
+}
+
+// autoLock locks the wallets of accounts newly put in the case when
+// automatic locking is on.
+func (s *service) autoLock(ctx context.Context, fraudCase *models.FraudCase, userIDs []uuid.UUID) error {
+	if !s.config.AutoLockWallets || len(userIDs) == 0 {
+		return nil
+	}
+
+	// The lock is the system's doing, not that of the user whose request found the cluster
+	systemCtx := audit.WithActor(ctx, audit.Actor{})
+	if err := s.repo.LockWallets(systemCtx, userIDs, fraudCase.LockReason()); err != nil {
+		return fmt.Errorf("failed to lock wallets: %w", err)
+	}
+	if fraudCase.WalletsLocked {
+		return nil
+	}
+	fraudCase.WalletsLocked = true
+	if err := s.repo.UpdateCase(ctx, fraudCase, nil); err != nil {
+		return fmt.Errorf("failed to update fraud case: %w", err)
+	}
+	return nil
+}
+
+// GetCases lists cases, defaulting to those still waiting on a decision.
+func (s *service) GetCases(ctx context.Context, filters *CaseFilters) ([]CaseResponse, int64, error) {
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.PerPage < 1 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+	if filters.Status == "" {
+		filters.Status = string(models.FraudCaseStatusOpen)
+	}
+
+	cases, total, err := s.repo.GetCases(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]CaseResponse, 0, len(cases))
+	for i := range cases {
+		responses = append(responses, *ToCaseResponse(&cases[i]))
+	}
+	return responses, total, nil
+}
+
+// GetCase returns a single case by ID.
+func (s *service) GetCase(ctx context.Context, id uuid.UUID) (*CaseResponse, error) {
+	fraudCase, err := s.repo.GetCaseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToCaseResponse(fraudCase), nil
+}
+
+// ReviewCase records an admin decision on an open case. Confirming it locks
+// the accounts' wallets; dismissing it releases the locks the case placed.
+func (s *service) ReviewCase(ctx context.Context,
+	reviewerID, caseID uuid.UUID,
+	req *ReviewRequest) (*CaseResponse, error) {
+	fraudCase, err := s.repo.GetCaseByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := models.AuditValues{"status": fraudCase.Status, "wallets_locked": fraudCase.WalletsLocked}
+	if err := fraudCase.Resolve(models.FraudCaseStatus(req.Status), reviewerID, req.Note); err != nil {
+		return nil, err
+	}
+
+	switch fraudCase.Status {
+	case models.FraudCaseStatusConfirmed:
+		if err := s.repo.LockWallets(ctx, fraudCase.UserIDs(), fraudCase.LockReason()); err != nil {
+			return nil, fmt.Errorf("failed to lock wallets: %w", err)
+		}
+		fraudCase.WalletsLocked = true
+	case models.FraudCaseStatusDismissed:
+		if fraudCase.WalletsLocked {
+			if err := s.repo.UnlockWallets(ctx, fraudCase.UserIDs(), fraudCase.LockReason()); err != nil {
+				return nil, fmt.Errorf("failed to unlock wallets: %w", err)
+			}
+			fraudCase.WalletsLocked = false
+		}
+	}
+
+	log := audit.NewLog(ctx, models.AuditActionFraudReviewed, models.AuditResourceFraudCase, &fraudCase.ID,
+		oldValues,
+		models.AuditValues{"status": fraudCase.Status, "wallets_locked": fraudCase.WalletsLocked, "note": fraudCase.ReviewNote})
+	if err := s.repo.SaveReview(ctx, fraudCase, log); err != nil {
+		return nil, fmt.Errorf("failed to save review: %w", err)
+	}
+
+	return ToCaseResponse(fraudCase), nil
+}
@@ -0,0 +1,416 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) RecordSignal(ctx context.Context, signal *models.AccountSignal) error {
+	return m.Called(ctx, signal).Error(0)
+}
+
+func (m *MockRepository) GetSignalsByUser(ctx context.Context, userID uuid.UUID) ([]models.AccountSignal, error) {
+	args := m.Called(ctx, userID)
+	if s := args.Get(0); s != nil {
+		return s.([]models.AccountSignal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetMatchingSignals(ctx context.Context,
+	userID uuid.UUID,
+	signals []models.AccountSignal) ([]models.AccountSignal, error) {
+	args := m.Called(ctx, userID, signals)
+	if s := args.Get(0); s != nil {
+		return s.([]models.AccountSignal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetMarketByID(ctx context.Context, id uuid.UUID) (*models.Market, error) {
+	args := m.Called(ctx, id)
+	if market := args.Get(0); market != nil {
+		return market.(*models.Market), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetMarketStakes(ctx context.Context, marketID uuid.UUID, userIDs []uuid.UUID) ([]models.FraudPosition, error) {
+	args := m.Called(ctx, marketID, userIDs)
+	if p := args.Get(0); p != nil {
+		return p.([]models.FraudPosition), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) FindCases(ctx context.Context,
+	kind models.FraudCaseKind,
+	marketID *uuid.UUID,
+	userIDs []uuid.UUID) ([]models.FraudCase, error) {
+	args := m.Called(ctx, kind, marketID, userIDs)
+	if c := args.Get(0); c != nil {
+		return c.([]models.FraudCase), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) CreateCase(ctx context.Context, fraudCase *models.FraudCase) error {
+	return m.Called(ctx, fraudCase).Error(0)
+}
+
+func (m *MockRepository) UpdateCase(ctx context.Context, fraudCase *models.FraudCase, added []uuid.UUID) error {
+	return m.Called(ctx, fraudCase, added).Error(0)
+}
+
+func (m *MockRepository) GetCases(ctx context.Context, filters *CaseFilters) ([]models.FraudCase, int64, error) {
+	args := m.Called(ctx, filters)
+	if c := args.Get(0); c != nil {
+		return c.([]models.FraudCase), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockRepository) GetCaseByID(ctx context.Context, id uuid.UUID) (*models.FraudCase, error) {
+	args := m.Called(ctx, id)
+	if c := args.Get(0); c != nil {
+		return c.(*models.FraudCase), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) SaveReview(ctx context.Context, fraudCase *models.FraudCase, log *models.AuditLog) error {
+	return m.Called(ctx, fraudCase, log).Error(0)
+}
+
+func (m *MockRepository) LockWallets(ctx context.Context, userIDs []uuid.UUID, reason string) error {
+	return m.Called(ctx, userIDs, reason).Error(0)
+}
+
+func (m *MockRepository) UnlockWallets(ctx context.Context, userIDs []uuid.UUID, reason string) error {
+	return m.Called(ctx, userIDs, reason).Error(0)
+}
+
+func newTestService(repo *MockRepository, config *Config) Service {
+	return NewService(repo, config, logger.NewNullLogger())
+}
+
+// linkUsers makes the repository report other as sharing the given signal types with userID
+func linkUsers(repo *MockRepository, userID, other uuid.UUID, types ...models.AccountSignalType) {
+	own := []models.AccountSignal{{UserID: userID, Type: models.AccountSignalIP, ValueHash: "ip"}}
+	matches := make([]models.AccountSignal, 0, len(types))
+	for _, t := range types {
+		matches = append(matches, models.AccountSignal{UserID: other, Type: t, ValueHash: string(t)})
+	}
+	repo.On("GetSignalsByUser", mock.Anything, userID).Return(own, nil)
+	repo.On("GetMatchingSignals", mock.Anything, userID, own).Return(matches, nil)
+}
+
+func TestRecordSignals_HashesAndSkipsEmptyValues(t *testing.T) {
+	repo := &MockRepository{}
+	userID := uuid.New()
+
+	repo.On("RecordSignal", mock.Anything, mock.MatchedBy(func(s *models.AccountSignal) bool {
+		return s.UserID == userID && s.Type == models.AccountSignalIP &&
+			s.ValueHash == models.HashAccountSignal(models.AccountSignalIP, "203.0.113.7")
+	})).Return(nil).Once()
+	linkUsers(repo, userID, uuid.New())
+
+	newTestService(repo, GetDefaultConfig()).RecordSignals(context.Background(), userID,
+		Signal{Type: models.AccountSignalIP, Value: "203.0.113.7"},
+		Signal{Type: models.AccountSignalDevice, Value: ""})
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "FindCases", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordSignals_SharedIPAloneDoesNotLink(t *testing.T) {
+	repo := &MockRepository{}
+	userID := uuid.New()
+
+	repo.On("RecordSignal", mock.Anything, mock.Anything).Return(nil)
+	linkUsers(repo, userID, uuid.New(), models.AccountSignalIP)
+
+	newTestService(repo, GetDefaultConfig()).RecordSignals(context.Background(), userID,
+		Signal{Type: models.AccountSignalIP, Value: "203.0.113.7"})
+
+	repo.AssertNotCalled(t, "FindCases", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "CreateCase", mock.Anything, mock.Anything)
+}
+
+func TestRecordSignals_OpensMultiAccountingCase(t *testing.T) {
+	repo := &MockRepository{}
+	userID, other := uuid.New(), uuid.New()
+
+	repo.On("RecordSignal", mock.Anything, mock.Anything).Return(nil)
+	linkUsers(repo, userID, other, models.AccountSignalIP, models.AccountSignalDevice)
+	repo.On("FindCases", mock.Anything, models.FraudCaseMultiAccounting, (*uuid.UUID)(nil), []uuid.UUID{userID, other}).
+		Return([]models.FraudCase{}, nil)
+	repo.On("CreateCase", mock.Anything, mock.MatchedBy(func(c *models.FraudCase) bool {
+		return c.Kind == models.FraudCaseMultiAccounting && c.IsOpen() &&
+			assert.ObjectsAreEqual([]uuid.UUID{userID, other}, c.UserIDs()) &&
+			assert.ObjectsAreEqual([]models.AccountSignalType{models.AccountSignalIP, models.AccountSignalDevice}, c.Evidence.Signals)
+	})).Return(nil)
+
+	newTestService(repo, GetDefaultConfig()).RecordSignals(context.Background(), userID,
+		Signal{Type: models.AccountSignalDevice, Value: "device-1"})
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "LockWallets", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordSignals_AutoLocksWallets(t *testing.T) {
+	repo := &MockRepository{}
+	userID, other := uuid.New(), uuid.New()
+	config := GetDefaultConfig()
+	config.AutoLockWallets = true
+
+	repo.On("RecordSignal", mock.Anything, mock.Anything).Return(nil)
+	linkUsers(repo, userID, other, models.AccountSignalPhone)
+	repo.On("FindCases", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.FraudCase{}, nil)
+
+	var created *models.FraudCase
+	repo.On("CreateCase", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.FraudCase)
+	}).Return(nil)
+	repo.On("LockWallets", mock.Anything, []uuid.UUID{userID, other}, mock.AnythingOfType("string")).Return(nil)
+	repo.On("UpdateCase", mock.Anything, mock.Anything, []uuid.UUID(nil)).Return(nil)
+
+	newTestService(repo, config).RecordSignals(context.Background(), userID,
+		Signal{Type: models.AccountSignalPhone, Value: "+2348000000000"})
+
+	require.NotNil(t, created)
+	assert.True(t, created.WalletsLocked)
+	repo.AssertCalled(t, "LockWallets", mock.Anything, []uuid.UUID{userID, other}, created.LockReason())
+}
+
+func TestRecordSignals_GrowsOpenCase(t *testing.T) {
+	repo := &MockRepository{}
+	userID, other, existing := uuid.New(), uuid.New(), uuid.New()
+	open := models.FraudCase{
+		ID:       uuid.New(),
+		Kind:     models.FraudCaseMultiAccounting,
+		Status:   models.FraudCaseStatusOpen,
+		Evidence: models.FraudEvidence{Signals: []models.AccountSignalType{models.AccountSignalDevice}},
+	}
+	open.AddAccounts(other, existing)
+
+	repo.On("RecordSignal", mock.Anything, mock.Anything).Return(nil)
+	linkUsers(repo, userID, other, models.AccountSignalDevice)
+	repo.On("FindCases", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.FraudCase{open}, nil)
+	repo.On("UpdateCase", mock.Anything, mock.MatchedBy(func(c *models.FraudCase) bool {
+		return c.ID == open.ID && len(c.Accounts) == 3
+	}), []uuid.UUID{userID}).Return(nil)
+
+	newTestService(repo, GetDefaultConfig()).RecordSignals(context.Background(), userID,
+		Signal{Type: models.AccountSignalDevice, Value: "device-1"})
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CreateCase", mock.Anything, mock.Anything)
+}
+
+func TestRecordSignals_DecidedClusterIsNotReopened(t *testing.T) {
+	repo := &MockRepository{}
+	userID, other := uuid.New(), uuid.New()
+	dismissed := models.FraudCase{ID: uuid.New(), Kind: models.FraudCaseMultiAccounting, Status: models.FraudCaseStatusDismissed}
+	dismissed.AddAccounts(userID, other)
+
+	repo.On("RecordSignal", mock.Anything, mock.Anything).Return(nil)
+	linkUsers(repo, userID, other, models.AccountSignalDevice)
+	repo.On("FindCases", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.FraudCase{dismissed}, nil)
+
+	newTestService(repo, GetDefaultConfig()).RecordSignals(context.Background(), userID,
+		Signal{Type: models.AccountSignalDevice, Value: "device-1"})
+
+	repo.AssertNotCalled(t, "CreateCase", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpdateCase", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordSignals_ErrorsAreNotReturned(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("RecordSignal", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	assert.NotPanics(t, func() {
+		newTestService(repo, GetDefaultConfig()).RecordSignals(context.Background(), uuid.New(),
+			Signal{Type: models.AccountSignalIP, Value: "203.0.113.7"})
+	})
+	repo.AssertNotCalled(t, "GetSignalsByUser", mock.Anything, mock.Anything)
+}
+
+func TestReviewBet(t *testing.T) {
+	yes, no := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		pool      decimal.Decimal
+		positions func(userID, other uuid.UUID) []models.FraudPosition
+		flagged   bool
+	}{
+		{
+			name: "opposite sides of a thin market",
+			pool: decimal.NewFromInt(2000),
+			positions: func(userID, other uuid.UUID) []models.FraudPosition {
+				return []models.FraudPosition{
+					{UserID: userID, OutcomeID: yes, Amount: decimal.NewFromInt(1000)},
+					{UserID: other, OutcomeID: no, Amount: decimal.NewFromInt(1000)},
+				}
+			},
+			flagged: true,
+		},
+		{
+			name: "same side",
+			pool: decimal.NewFromInt(2000),
+			positions: func(userID, other uuid.UUID) []models.FraudPosition {
+				return []models.FraudPosition{
+					{UserID: userID, OutcomeID: yes, Amount: decimal.NewFromInt(1000)},
+					{UserID: other, OutcomeID: yes, Amount: decimal.NewFromInt(1000)},
+				}
+			},
+		},
+		{
+			name: "one account hedging",
+			pool: decimal.NewFromInt(2000),
+			positions: func(userID, _ uuid.UUID) []models.FraudPosition {
+				return []models.FraudPosition{
+					{UserID: userID, OutcomeID: yes, Amount: decimal.NewFromInt(1000)},
+					{UserID: userID, OutcomeID: no, Amount: decimal.NewFromInt(1000)},
+				}
+			},
+		},
+		{
+			name: "deep market",
+			pool: decimal.NewFromInt(1000000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockRepository{}
+			userID, other := uuid.New(), uuid.New()
+			market := &models.Market{ID: uuid.New(), TotalPoolAmount: tt.pool}
+			bet := &models.Bet{ID: uuid.New(), UserID: userID, MarketID: market.ID, MarketOutcomeID: yes}
+
+			linkUsers(repo, userID, other, models.AccountSignalDevice)
+			repo.On("GetMarketByID", mock.Anything, market.ID).Return(market, nil)
+			if tt.positions != nil {
+				repo.On("GetMarketStakes", mock.Anything, market.ID, []uuid.UUID{userID, other}).
+					Return(tt.positions(userID, other), nil)
+			}
+			repo.On("FindCases", mock.Anything, models.FraudCaseCollusion, &market.ID, []uuid.UUID{userID, other}).
+				Return([]models.FraudCase{}, nil)
+			repo.On("CreateCase", mock.Anything, mock.MatchedBy(func(c *models.FraudCase) bool {
+				return c.Kind == models.FraudCaseCollusion && *c.MarketID == market.ID &&
+					len(c.Evidence.Positions) == 2 && len(c.Accounts) == 2
+			})).Return(nil)
+
+			newTestService(repo, GetDefaultConfig()).ReviewBet(context.Background(), bet)
+
+			if tt.flagged {
+				repo.AssertCalled(t, "CreateCase", mock.Anything, mock.Anything)
+			} else {
+				repo.AssertNotCalled(t, "CreateCase", mock.Anything, mock.Anything)
+			}
+			if tt.positions == nil {
+				repo.AssertNotCalled(t, "GetMarketStakes", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestReviewBet_UnlinkedBettorSkipsMarketLookup(t *testing.T) {
+	repo := &MockRepository{}
+	userID := uuid.New()
+	linkUsers(repo, userID, uuid.New())
+
+	newTestService(repo, GetDefaultConfig()).ReviewBet(context.Background(), &models.Bet{UserID: userID, MarketID: uuid.New()})
+
+	repo.AssertNotCalled(t, "GetMarketByID", mock.Anything, mock.Anything)
+}
+
+func openCase(userIDs ...uuid.UUID) *models.FraudCase {
+	fraudCase := &models.FraudCase{ID: uuid.New(), Kind: models.FraudCaseMultiAccounting, Status: models.FraudCaseStatusOpen}
+	fraudCase.AddAccounts(userIDs...)
+	return fraudCase
+}
+
+func TestReviewCase_ConfirmLocksWallets(t *testing.T) {
+	repo := &MockRepository{}
+	reviewer, first, second := uuid.New(), uuid.New(), uuid.New()
+	fraudCase := openCase(first, second)
+
+	repo.On("GetCaseByID", mock.Anything, fraudCase.ID).Return(fraudCase, nil)
+	repo.On("LockWallets", mock.Anything, []uuid.UUID{first, second}, fraudCase.LockReason()).Return(nil)
+	repo.On("SaveReview", mock.Anything, fraudCase, mock.MatchedBy(func(log *models.AuditLog) bool {
+		return log.Action == models.AuditActionFraudReviewed && *log.ResourceID == fraudCase.ID
+	})).Return(nil)
+
+	resp, err := newTestService(repo, GetDefaultConfig()).ReviewCase(context.Background(), reviewer, fraudCase.ID,
+		&ReviewRequest{Status: string(models.FraudCaseStatusConfirmed), Note: "same card on both accounts"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.FraudCaseStatusConfirmed, resp.Status)
+	assert.True(t, resp.WalletsLocked)
+	assert.Equal(t, &reviewer, resp.ReviewedBy)
+	repo.AssertExpectations(t)
+}
+
+func TestReviewCase_DismissReleasesLocks(t *testing.T) {
+	repo := &MockRepository{}
+	first, second := uuid.New(), uuid.New()
+	fraudCase := openCase(first, second)
+	fraudCase.WalletsLocked = true
+
+	repo.On("GetCaseByID", mock.Anything, fraudCase.ID).Return(fraudCase, nil)
+	repo.On("UnlockWallets", mock.Anything, []uuid.UUID{first, second}, fraudCase.LockReason()).Return(nil)
+	repo.On("SaveReview", mock.Anything, fraudCase, mock.Anything).Return(nil)
+
+	resp, err := newTestService(repo, GetDefaultConfig()).ReviewCase(context.Background(), uuid.New(), fraudCase.ID,
+		&ReviewRequest{Status: string(models.FraudCaseStatusDismissed), Note: "siblings sharing a laptop"})
+
+	require.NoError(t, err)
+	assert.False(t, resp.WalletsLocked)
+	repo.AssertExpectations(t)
+}
+
+func TestReviewCase_AlreadyDecided(t *testing.T) {
+	repo := &MockRepository{}
+	fraudCase := openCase(uuid.New())
+	fraudCase.Status = models.FraudCaseStatusDismissed
+
+	repo.On("GetCaseByID", mock.Anything, fraudCase.ID).Return(fraudCase, nil)
+
+	_, err := newTestService(repo, GetDefaultConfig()).ReviewCase(context.Background(), uuid.New(), fraudCase.ID,
+		&ReviewRequest{Status: string(models.FraudCaseStatusConfirmed), Note: "note"})
+
+	assert.ErrorIs(t, err, models.ErrFraudCaseClosed)
+	repo.AssertNotCalled(t, "SaveReview", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetCases_DefaultsToOpen(t *testing.T) {
+	repo := &MockRepository{}
+	filters := &CaseFilters{}
+
+	repo.On("GetCases", mock.Anything, filters).Return([]models.FraudCase{*openCase(uuid.New())}, int64(1), nil)
+
+	cases, total, err := newTestService(repo, GetDefaultConfig()).GetCases(context.Background(), filters)
+
+	require.NoError(t, err)
+	assert.Len(t, cases, 1)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, string(models.FraudCaseStatusOpen), filters.Status)
+	assert.Equal(t, 1, filters.Page)
+	assert.Equal(t, 20, filters.PerPage)
+}
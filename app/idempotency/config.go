@@ -0,0 +1,51 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+// Config represents the configuration for the idempotency module
+type Config struct {
+	// KeyTTL is how long a stored response is replayed before the key can be
+	// used for a new request.
+	KeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL"`
+	// LockTimeout is how long a key may go without its request renewing the
+	// claim before it is considered abandoned, for example after a crash, and
+	// handed to a retry. Running requests renew it every third of this.
+	LockTimeout time.Duration `env:"IDEMPOTENCY_LOCK_TIMEOUT"`
+	// CleanupInterval is how often expired keys are deleted
+	CleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL"`
+	// MaxBodyBytes is the largest request body read to fingerprint a request
+	// carrying a key. Larger requests are refused with 413.
+	MaxBodyBytes int64 `env:"IDEMPOTENCY_MAX_BODY_BYTES"`
+}
+
+func (c *Config) Validate() error {
+	if c.KeyTTL <= 0 {
+		return errors.New("idempotency key TTL must be positive")
+	}
+	if c.LockTimeout <= 0 {
+		return errors.New("idempotency lock timeout must be positive")
+	}
+	if c.LockTimeout >= c.KeyTTL {
+		return errors.New("idempotency lock timeout must be shorter than the key TTL")
+	}
+	if c.CleanupInterval <= 0 {
+		return errors.New("idempotency cleanup interval must be positive")
+	}
+	if c.MaxBodyBytes <= 0 {
+		return errors.New("idempotency max body size must be positive")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default idempotency configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		KeyTTL:          24 * time.Hour,
+		LockTimeout:     5 * time.Minute,
+		CleanupInterval: time.Hour,
+		MaxBodyBytes:    1 << 20,
+	}
+}
@@ -0,0 +1,32 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, GetDefaultConfig().Validate())
+
+	config := GetDefaultConfig()
+	config.KeyTTL = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.LockTimeout = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.LockTimeout = config.KeyTTL + time.Minute
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.CleanupInterval = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.MaxBodyBytes = 0
+	assert.Error(t, config.Validate())
+}
@@ -0,0 +1,45 @@
+package idempotency
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "idempotency_repository"
+	ServiceKey = "idempotency_service"
+	ConfigKey  = "idempotency_config"
+)
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid idempotency configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+	container.RegisterService(ServiceKey, NewService(repo, container.Cache, config))
+	container.RegisterService(ConfigKey, config)
+}
+
+// StartJobs starts the module's background jobs. They run until ctx is done.
+func StartJobs(ctx context.Context, container *deps.Container) {
+	config := container.GetService(ConfigKey).(*Config)
+	cleaner := container.GetService(ServiceKey).(Cleaner)
+	go RunExpiredKeyCleanup(ctx, cleaner, config.CleanupInterval)
+}
+
+// Require returns the idempotency middleware for a module's mutating routes.
+// It passes requests through when this module has not been initialized.
+func Require(container *deps.Container) gin.HandlerFunc {
+	service, _ := container.GetService(ServiceKey).(Service)
+	config, ok := container.GetService(ConfigKey).(*Config)
+	if !ok {
+		config = GetDefaultConfig()
+	}
+	return Middleware(service, config.MaxBodyBytes, container.Logger)
+}
@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+)
+
+type Repository interface {
+	// Reserve inserts the key unless the user already has it, reporting
+	// whether this call created it.
+	Reserve(ctx context.Context, key *models.IdempotencyKey) (bool, error)
+	GetKey(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotencyKey, error)
+	// RenewKey marks a key still being processed as alive, reporting whether
+	// it was still held
+	RenewKey(ctx context.Context, id uuid.UUID) (bool, error)
+	// CompleteKey stores the response of a key still being processed,
+	// reporting whether it was still held
+	CompleteKey(ctx context.Context, key *models.IdempotencyKey) (bool, error)
+	DeleteKey(ctx context.Context, id uuid.UUID) error
+	// DeleteExpiredKeys removes keys that expired before the given time and
+	// returns how many were removed
+	DeleteExpiredKeys(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Cleaner deletes keys whose responses are no longer replayed
+type Cleaner interface {
+	DeleteExpiredKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Service tracks requests sent with an Idempotency-Key header so that retries
+// replay the first response instead of running again.
+type Service interface {
+	Cleaner
+
+	// Begin claims key for a request. It returns a processing record when the
+	// caller should run the request, or a completed record to replay.
+	// A key held by a request still running fails with
+	// models.ErrIdempotencyKeyInUse, and one first used for a different
+	// request with models.ErrIdempotencyKeyReused.
+	Begin(ctx context.Context, userID uuid.UUID, key, fingerprint string) (*models.IdempotencyKey, error)
+	// KeepAlive renews the claim on record until the returned stop function
+	// is called, so a slow request is not mistaken for an abandoned one.
+	KeepAlive(ctx context.Context, record *models.IdempotencyKey) (stop func())
+	// Complete stores the response of a request claimed with Begin. It fails
+	// with models.ErrIdempotencyKeyLost when the claim was handed to a retry.
+	Complete(ctx context.Context, record *models.IdempotencyKey, status int, body []byte) error
+	// Release gives up a claim without storing a response, so a retry runs
+	// the request again.
+	Release(ctx context.Context, record *models.IdempotencyKey) error
+}
@@ -0,0 +1,133 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+const (
+	// HeaderKey carries the client-chosen key that identifies a request across retries
+	HeaderKey = "Idempotency-Key"
+	// ReplayedHeader is set on responses served from a stored earlier attempt
+	ReplayedHeader = "Idempotent-Replayed"
+	// MaxKeyLength is the longest key accepted
+	MaxKeyLength = 255
+)
+
+// Middleware makes a route safe to retry. Requests carrying an Idempotency-Key
+// header run once per user and key; retries with the same body get the stored
+// response back. Requests without the header, and every request when service
+// is nil, pass straight through. Responses with a 5xx status are not stored, so
+// a retry runs the request again. Requests with a key and a body larger than
+// maxBodyBytes are refused.
+func Middleware(service Service, maxBodyBytes int64, lg logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(HeaderKey))
+		if service == nil || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > MaxKeyLength {
+			api.BadRequestResponse(c, HeaderKey+" must not be more than 255 characters")
+			c.Abort()
+			return
+		}
+
+		userID, ok := api.UserIDFromContext(c)
+		if !ok {
+			api.UnauthorizedResponse(c)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				api.ErrorResponse(c, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE",
+					"Request body is too large", nil)
+			} else {
+				api.BadRequestResponse(c, "failed to read request body")
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		record, err := service.Begin(ctx, userID, key, Fingerprint(c.Request.Method, c.Request.URL.Path, body))
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrIdempotencyKeyInUse):
+				api.ConflictResponse(c, "A request with this idempotency key is still being processed")
+			case errors.Is(err, models.ErrIdempotencyKeyReused):
+				api.ErrorResponse(c, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED",
+					"This idempotency key was already used with a different request", nil)
+			default:
+				lg.Error(err, logger.Fields{"middleware": "idempotency"})
+				api.InternalErrorResponse(c, "Failed to process idempotency key")
+			}
+			c.Abort()
+			return
+		}
+
+		if record.IsCompleted() {
+			c.Header(ReplayedHeader, "true")
+			c.Data(record.ResponseStatus, "application/json; charset=utf-8", record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		stop := service.KeepAlive(ctx, record)
+		c.Next()
+		stop()
+
+		if status := recorder.Status(); status >= http.StatusInternalServerError {
+			err = service.Release(ctx, record)
+		} else {
+			err = service.Complete(ctx, record, status, recorder.body.Bytes())
+		}
+		if err != nil {
+			lg.Error(err, logger.Fields{"middleware": "idempotency", "user_id": userID})
+		}
+	}
+}
+
+// Fingerprint identifies the request a key was first used for
+func Fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body as it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,188 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Begin(ctx context.Context, userID uuid.UUID, key, fingerprint string) (*models.IdempotencyKey, error) {
+	args := m.Called(ctx, userID, key, fingerprint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockService) KeepAlive(ctx context.Context, record *models.IdempotencyKey) func() {
+	m.Called(ctx, record)
+	return func() {}
+}
+
+func (m *MockService) DeleteExpiredKeys(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockService) Complete(ctx context.Context, record *models.IdempotencyKey, status int, body []byte) error {
+	return m.Called(ctx, record, status, body).Error(0)
+}
+
+func (m *MockService) Release(ctx context.Context, record *models.IdempotencyKey) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func setupMiddlewareRouter(svc Service, userID uuid.UUID, status int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(api.ContextUserIDKey, userID)
+		c.Next()
+	})
+	router.POST("/bets", Middleware(svc, 64, logger.NewNullLogger()), func(c *gin.Context) {
+		*calls++
+		c.JSON(status, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func postBet(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/bets", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_WithoutKeyPassesThrough(t *testing.T) {
+	svc := new(MockService)
+	calls := 0
+	router := setupMiddlewareRouter(svc, uuid.New(), http.StatusCreated, &calls)
+
+	w := postBet(router, "", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+	svc.AssertNotCalled(t, "Begin")
+}
+
+func TestMiddleware_StoresFirstResponse(t *testing.T) {
+	svc := new(MockService)
+	userID := uuid.New()
+	calls := 0
+	router := setupMiddlewareRouter(svc, userID, http.StatusCreated, &calls)
+	record := &models.IdempotencyKey{Status: models.IdempotencyKeyStatusProcessing}
+
+	svc.On("Begin", mock.Anything, userID, "key-1", Fingerprint(http.MethodPost, "/bets", []byte(`{"amount":10}`))).
+		Return(record, nil)
+	svc.On("KeepAlive", mock.Anything, record).Return()
+	svc.On("Complete", mock.Anything, record, http.StatusCreated, []byte(`{"call":1}`)).Return(nil)
+
+	w := postBet(router, "key-1", `{"amount":10}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+	svc.AssertExpectations(t)
+}
+
+func TestMiddleware_ReplaysCompletedResponse(t *testing.T) {
+	svc := new(MockService)
+	userID := uuid.New()
+	calls := 0
+	router := setupMiddlewareRouter(svc, userID, http.StatusCreated, &calls)
+
+	record := &models.IdempotencyKey{}
+	record.Complete(http.StatusCreated, []byte(`{"call":1}`))
+	svc.On("Begin", mock.Anything, userID, "key-1", mock.Anything).Return(record, nil)
+
+	w := postBet(router, "key-1", `{"amount":10}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"call":1}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(ReplayedHeader))
+	assert.Equal(t, 0, calls)
+	svc.AssertNotCalled(t, "KeepAlive")
+}
+
+func TestMiddleware_ReleasesKeyOnServerError(t *testing.T) {
+	svc := new(MockService)
+	userID := uuid.New()
+	calls := 0
+	router := setupMiddlewareRouter(svc, userID, http.StatusInternalServerError, &calls)
+	record := &models.IdempotencyKey{Status: models.IdempotencyKeyStatusProcessing}
+
+	svc.On("Begin", mock.Anything, userID, "key-1", mock.Anything).Return(record, nil)
+	svc.On("KeepAlive", mock.Anything, record).Return()
+	svc.On("Release", mock.Anything, record).Return(nil)
+
+	w := postBet(router, "key-1", `{}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	svc.AssertExpectations(t)
+	svc.AssertNotCalled(t, "Complete")
+}
+
+func TestMiddleware_RejectsConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"concurrent duplicate", models.ErrIdempotencyKeyInUse, http.StatusConflict},
+		{"different body", models.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockService)
+			userID := uuid.New()
+			calls := 0
+			router := setupMiddlewareRouter(svc, userID, http.StatusCreated, &calls)
+			svc.On("Begin", mock.Anything, userID, "key-1", mock.Anything).Return(nil, tt.err)
+
+			w := postBet(router, "key-1", `{}`)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, 0, calls)
+		})
+	}
+}
+
+func TestMiddleware_RejectsLongKey(t *testing.T) {
+	calls := 0
+	router := setupMiddlewareRouter(new(MockService), uuid.New(), http.StatusCreated, &calls)
+
+	w := postBet(router, strings.Repeat("k", MaxKeyLength+1), `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestMiddleware_RejectsLargeBody(t *testing.T) {
+	svc := new(MockService)
+	calls := 0
+	router := setupMiddlewareRouter(svc, uuid.New(), http.StatusCreated, &calls)
+
+	w := postBet(router, "key-1", `{"note":"`+strings.Repeat("x", 64)+`"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, 0, calls)
+	svc.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFingerprint(t *testing.T) {
+	base := Fingerprint(http.MethodPost, "/wallets/1/credit", []byte(`{"amount":10}`))
+	assert.Equal(t, base, Fingerprint(http.MethodPost, "/wallets/1/credit", []byte(`{"amount":10}`)))
+	assert.NotEqual(t, base, Fingerprint(http.MethodPost, "/wallets/2/credit", []byte(`{"amount":10}`)))
+	assert.NotEqual(t, base, Fingerprint(http.MethodPost, "/wallets/1/credit", []byte(`{"amount":20}`)))
+}
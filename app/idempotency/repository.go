@@ -0,0 +1,86 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new idempotency repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Reserve(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+			DoNothing: true,
+		}).
+		Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) GetKey(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND key = ?", userID, key).
+		First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *repository) RenewKey(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where("id = ? AND status = ?", id, models.IdempotencyKeyStatusProcessing).
+		Update("updated_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) CompleteKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where("id = ? AND status = ?", key.ID, models.IdempotencyKeyStatusProcessing).
+		Updates(map[string]interface{}{
+			"status":          key.Status,
+			"response_status": key.ResponseStatus,
+			"response_body":   key.ResponseBody,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) DeleteKey(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.IdempotencyKey{}, "id = ?", id).Error
+}
+
+func (r *repository) DeleteExpiredKeys(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at <= ?", before).
+		Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,209 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/models"
+)
+
+// maxReserveAttempts covers one retry after clearing an expired or abandoned key
+const maxReserveAttempts = 2
+
+type service struct {
+	repo   Repository
+	cache  cache.Cache[string]
+	config *Config
+	now    func() time.Time
+}
+
+// NewService creates an idempotency service. Completed responses are also kept
+// in c, when it is not nil, so replays do not reach the database.
+func NewService(repo Repository, c cache.Cache[string], config *Config) Service {
+	return &service{repo: repo, cache: c, config: config, now: time.Now}
+}
+
+func (s *service) Begin(ctx context.Context, userID uuid.UUID, key, fingerprint string) (*models.IdempotencyKey, error) {
+	if cached := s.cached(ctx, userID, key); cached != nil {
+		if !cached.Matches(fingerprint) {
+			return nil, models.ErrIdempotencyKeyReused
+		}
+		return cached, nil
+	}
+
+	for attempt := 0; attempt < maxReserveAttempts; attempt++ {
+		now := s.now()
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Fingerprint: fingerprint,
+			Status:      models.IdempotencyKeyStatusProcessing,
+			ExpiresAt:   now.Add(s.config.KeyTTL),
+		}
+		created, err := s.repo.Reserve(ctx, record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if created {
+			return record, nil
+		}
+
+		existing, err := s.repo.GetKey(ctx, userID, key)
+		if errors.Is(err, models.ErrRecordNotFound) {
+			// Released between the insert and the read
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load idempotency key: %w", err)
+		}
+
+		if existing.IsExpired(now) || existing.IsAbandoned(now, s.config.LockTimeout) {
+			if err := s.repo.DeleteKey(ctx, existing.ID); err != nil {
+				return nil, fmt.Errorf("failed to clear idempotency key: %w", err)
+			}
+			continue
+		}
+		if !existing.Matches(fingerprint) {
+			return nil, models.ErrIdempotencyKeyReused
+		}
+		if !existing.IsCompleted() {
+			return nil, models.ErrIdempotencyKeyInUse
+		}
+
+		s.remember(ctx, existing)
+		return existing, nil
+	}
+
+	// Another request claimed the key while it was being cleared
+	return nil, models.ErrIdempotencyKeyInUse
+}
+
+func (s *service) KeepAlive(ctx context.Context, record *models.IdempotencyKey) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	id := record.ID
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.LockTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			held, err := s.repo.RenewKey(ctx, id)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Warning: Failed to renew idempotency key %s: %v", id, err)
+				}
+				continue
+			}
+			if !held {
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func (s *service) Complete(ctx context.Context, record *models.IdempotencyKey, status int, body []byte) error {
+	record.Complete(status, body)
+	held, err := s.repo.CompleteKey(ctx, record)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	if !held {
+		return models.ErrIdempotencyKeyLost
+	}
+	s.remember(ctx, record)
+	return nil
+}
+
+func (s *service) Release(ctx context.Context, record *models.IdempotencyKey) error {
+	if err := s.repo.DeleteKey(ctx, record.ID); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *service) DeleteExpiredKeys(ctx context.Context, now time.Time) (int64, error) {
+	deleted, err := s.repo.DeleteExpiredKeys(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return deleted, nil
+}
+
+// RunExpiredKeyCleanup deletes expired keys straight away and then every
+// interval until ctx is done. Expired keys are already ignored by Begin, so
+// this only keeps the table from growing.
+func RunExpiredKeyCleanup(ctx context.Context, cleaner Cleaner, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := cleaner.DeleteExpiredKeys(ctx, time.Now()); err != nil {
+			log.Printf("Warning: Failed to clean up idempotency keys: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cached returns a completed, unexpired record from the cache, if any.
+// Cache failures fall through to the database.
+func (s *service) cached(ctx context.Context, userID uuid.UUID, key string) *models.IdempotencyKey {
+	if s.cache == nil {
+		return nil
+	}
+	raw, err := s.cache.Get(ctx, s.cacheKey(userID, key))
+	if err != nil {
+		return nil
+	}
+
+	var record models.IdempotencyKey
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		return nil
+	}
+	if !record.IsCompleted() || record.IsExpired(s.now()) {
+		return nil
+	}
+	return &record
+}
+
+func (s *service) remember(ctx context.Context, record *models.IdempotencyKey) {
+	if s.cache == nil {
+		return
+	}
+	ttl := record.ExpiresAt.Sub(s.now())
+	if ttl <= 0 {
+		return
+	}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	_ = s.cache.Set(ctx, s.cacheKey(record.UserID, record.Key), string(raw), ttl)
+}
+
+func (s *service) cacheKey(userID uuid.UUID, key string) string {
+	return fmt.Sprintf("idempotency:%s:%s", userID, key)
+}
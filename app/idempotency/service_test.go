@@ -0,0 +1,214 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Reserve(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	args := m.Called(ctx, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) GetKey(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	args := m.Called(ctx, userID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockRepository) RenewKey(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CompleteKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	args := m.Called(ctx, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) DeleteKey(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockRepository) DeleteExpiredKeys(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newTestService(repo Repository) (*service, cache.Cache[string]) {
+	c := cache.NewMemoryCache[string]()
+	return NewService(repo, c, GetDefaultConfig()).(*service), c
+}
+
+func storedKey(userID uuid.UUID, fingerprint string, status models.IdempotencyKeyStatus) *models.IdempotencyKey {
+	now := time.Now()
+	return &models.IdempotencyKey{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Key:         "key-1",
+		Fingerprint: fingerprint,
+		Status:      status,
+		ExpiresAt:   now.Add(time.Hour),
+		UpdatedAt:   now,
+	}
+}
+
+func TestService_BeginReservesNewKey(t *testing.T) {
+	repo := new(MockRepository)
+	svc, _ := newTestService(repo)
+	userID := uuid.New()
+
+	repo.On("Reserve", mock.Anything, mock.MatchedBy(func(k *models.IdempotencyKey) bool {
+		return k.UserID == userID && k.Key == "key-1" && k.Fingerprint == "fp" && !k.IsCompleted()
+	})).Return(true, nil)
+
+	record, err := svc.Begin(context.Background(), userID, "key-1", "fp")
+	require.NoError(t, err)
+	assert.False(t, record.IsCompleted())
+	repo.AssertExpectations(t)
+}
+
+func TestService_BeginReplaysCompletedKey(t *testing.T) {
+	repo := new(MockRepository)
+	svc, _ := newTestService(repo)
+	userID := uuid.New()
+	existing := storedKey(userID, "fp", models.IdempotencyKeyStatusCompleted)
+	existing.ResponseStatus = http.StatusCreated
+
+	repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil).Once()
+	repo.On("GetKey", mock.Anything, userID, "key-1").Return(existing, nil).Once()
+
+	record, err := svc.Begin(context.Background(), userID, "key-1", "fp")
+	require.NoError(t, err)
+	assert.True(t, record.IsCompleted())
+
+	// The second retry is served from the cache
+	record, err = svc.Begin(context.Background(), userID, "key-1", "fp")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, record.ResponseStatus)
+	repo.AssertExpectations(t)
+}
+
+func TestService_BeginRejectsConflicts(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("in progress", func(t *testing.T) {
+		repo := new(MockRepository)
+		svc, _ := newTestService(repo)
+		repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil)
+		repo.On("GetKey", mock.Anything, userID, "key-1").
+			Return(storedKey(userID, "fp", models.IdempotencyKeyStatusProcessing), nil)
+
+		_, err := svc.Begin(context.Background(), userID, "key-1", "fp")
+		assert.ErrorIs(t, err, models.ErrIdempotencyKeyInUse)
+	})
+
+	t.Run("different request", func(t *testing.T) {
+		repo := new(MockRepository)
+		svc, _ := newTestService(repo)
+		repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil)
+		repo.On("GetKey", mock.Anything, userID, "key-1").
+			Return(storedKey(userID, "other", models.IdempotencyKeyStatusCompleted), nil)
+
+		_, err := svc.Begin(context.Background(), userID, "key-1", "fp")
+		assert.ErrorIs(t, err, models.ErrIdempotencyKeyReused)
+	})
+}
+
+func TestService_BeginTakesOverAbandonedKey(t *testing.T) {
+	repo := new(MockRepository)
+	svc, _ := newTestService(repo)
+	userID := uuid.New()
+	abandoned := storedKey(userID, "other", models.IdempotencyKeyStatusProcessing)
+	abandoned.UpdatedAt = time.Now().Add(-time.Hour)
+
+	repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil).Once()
+	repo.On("GetKey", mock.Anything, userID, "key-1").Return(abandoned, nil).Once()
+	repo.On("DeleteKey", mock.Anything, abandoned.ID).Return(nil).Once()
+	repo.On("Reserve", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	record, err := svc.Begin(context.Background(), userID, "key-1", "fp")
+	require.NoError(t, err)
+	assert.Equal(t, "fp", record.Fingerprint)
+	repo.AssertExpectations(t)
+}
+
+func TestService_CompleteStoresAndCachesResponse(t *testing.T) {
+	repo := new(MockRepository)
+	svc, c := newTestService(repo)
+	record := storedKey(uuid.New(), "fp", models.IdempotencyKeyStatusProcessing)
+
+	repo.On("CompleteKey", mock.Anything, record).Return(true, nil)
+
+	require.NoError(t, svc.Complete(context.Background(), record, http.StatusOK, []byte(`{"ok":true}`)))
+	assert.True(t, record.IsCompleted())
+
+	_, err := c.Get(context.Background(), svc.cacheKey(record.UserID, record.Key))
+	assert.NoError(t, err)
+}
+
+func TestService_CompleteFailsWhenClaimWasLost(t *testing.T) {
+	repo := new(MockRepository)
+	svc, c := newTestService(repo)
+	record := storedKey(uuid.New(), "fp", models.IdempotencyKeyStatusProcessing)
+
+	repo.On("CompleteKey", mock.Anything, record).Return(false, nil)
+
+	err := svc.Complete(context.Background(), record, http.StatusOK, []byte(`{"ok":true}`))
+	assert.ErrorIs(t, err, models.ErrIdempotencyKeyLost)
+
+	_, err = c.Get(context.Background(), svc.cacheKey(record.UserID, record.Key))
+	assert.Error(t, err)
+}
+
+func TestService_KeepAliveRenewsClaimUntilStopped(t *testing.T) {
+	repo := new(MockRepository)
+	svc, _ := newTestService(repo)
+	svc.config.LockTimeout = 30 * time.Millisecond
+	record := storedKey(uuid.New(), "fp", models.IdempotencyKeyStatusProcessing)
+
+	renewed := make(chan struct{}, 10)
+	repo.On("RenewKey", mock.Anything, record.ID).Return(true, nil).
+		Run(func(mock.Arguments) { renewed <- struct{}{} })
+
+	stop := svc.KeepAlive(context.Background(), record)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-renewed:
+		case <-time.After(time.Second):
+			t.Fatal("claim was not renewed")
+		}
+	}
+	stop()
+
+	calls := len(repo.Calls)
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, repo.Calls, calls)
+}
+
+func TestService_DeleteExpiredKeys(t *testing.T) {
+	repo := new(MockRepository)
+	svc, _ := newTestService(repo)
+	now := time.Now()
+
+	repo.On("DeleteExpiredKeys", mock.Anything, now).Return(int64(3), nil)
+
+	deleted, err := svc.DeleteExpiredKeys(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
@@ -0,0 +1,38 @@
+package kyc
+
+import (
+	"errors"
+)
+
+// Config represents the configuration for the KYC module
+type Config struct {
+	DefaultProvider string `env:"KYC_DEFAULT_PROVIDER"`
+	WebhookSecret   string `env:"KYC_WEBHOOK_SECRET"`
+	// LocalAutoApprove makes the local provider verify submissions immediately
+	// instead of leaving them in the manual review queue.
+	LocalAutoApprove bool `env:"KYC_LOCAL_AUTO_APPROVE"`
+	MinimumAge       int  `env:"KYC_MINIMUM_AGE"`
+}
+
+func (c *Config) Validate() error {
+	if c.DefaultProvider == "" {
+		return errors.New("default KYC provider must be set")
+	}
+	if c.WebhookSecret == "" {
+		return errors.New("KYC webhook secret must be set")
+	}
+	if c.MinimumAge < 18 {
+		return errors.New("KYC minimum age must be at least 18")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default KYC configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		DefaultProvider:  LocalProviderName,
+		WebhookSecret:    "change-me-kyc-webhook-secret",
+		LocalAutoApprove: false,
+		MinimumAge:       18,
+	}
+}
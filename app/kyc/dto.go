@@ -0,0 +1,139 @@
+package kyc
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+const dateLayout = "2006-01-02"
+
+// SubmitRequest is the identity data a user provides for verification.
+type SubmitRequest struct {
+	DocumentType   string    `json:"document_type"`
+	DocumentNumber string    `json:"document_number"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	DateOfBirth    string    `json:"date_of_birth"`
+	Address        string    `json:"address"`
+	ParsedDOB      time.Time `json:"-"`
+}
+
+// Validate sanitizes and checks the submission. minimumAge is enforced against the date of birth.
+func (r *SubmitRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer, minimumAge int) {
+	r.DocumentType = s.StripHTML(r.DocumentType)
+	r.DocumentNumber = s.StripHTML(r.DocumentNumber)
+	r.FirstName = s.StripHTML(r.FirstName)
+	r.LastName = s.StripHTML(r.LastName)
+	r.DateOfBirth = s.StripHTML(r.DateOfBirth)
+	r.Address = s.StripHTML(r.Address)
+
+	v.Check(validator.In(models.KYCDocumentType(r.DocumentType),
+		models.KYCDocumentNIN,
+		models.KYCDocumentBVN,
+		models.KYCDocumentPassport,
+		models.KYCDocumentDriverLicense,
+		models.KYCDocumentVotersCard), "document_type", "invalid document type")
+	v.Check(validator.MinRunes(r.DocumentNumber, 5) && validator.MaxRunes(r.DocumentNumber, 50), "document_number", "document number must be between 5 and 50 characters")
+	v.Check(validator.MinRunes(r.FirstName, 2) && validator.MaxRunes(r.FirstName, 100), "first_name", "first name must be between 2 and 100 characters")
+	v.Check(validator.MinRunes(r.LastName, 2) && validator.MaxRunes(r.LastName, 100), "last_name", "last name must be between 2 and 100 characters")
+	v.Check(validator.MaxRunes(r.Address, 500), "address", "address must not be more than 500 characters")
+
+	dob, err := time.Parse(dateLayout, r.DateOfBirth)
+	if err != nil {
+		v.AddError("date_of_birth", "date of birth must be in YYYY-MM-DD format")
+		return
+	}
+	v.Check(!dob.AddDate(minimumAge, 0, 0).After(time.Now()), "date_of_birth", "you must be of legal age to verify")
+	r.ParsedDOB = dob
+}
+
+// ReviewRequest is an admin decision on a submission in the manual review queue.
+type ReviewRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// Validate sanitizes and checks the review decision.
+func (r *ReviewRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	r.Status = s.StripHTML(r.Status)
+	r.Reason = s.StripHTML(r.Reason)
+
+	v.Check(validator.In(models.KYCStatus(r.Status), models.KYCStatusVerified, models.KYCStatusRejected), "status", "status must be either verified or rejected")
+	if models.KYCStatus(r.Status) == models.KYCStatusRejected {
+		v.Check(validator.NotBlank(r.Reason), "reason", "a reason is required when rejecting")
+	}
+	v.Check(validator.MaxRunes(r.Reason, 500), "reason", "reason must not be more than 500 characters")
+}
+
+// SubmissionFilters defines the query parameters for the review queue.
+type SubmissionFilters struct {
+	Page     int    `form:"page"`
+	PerPage  int    `form:"per_page"`
+	Status   string `form:"status"`
+	Provider string `form:"provider"`
+}
+
+// SanitizeAndValidate cleans and validates the filter inputs.
+func (f *SubmissionFilters) SanitizeAndValidate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	f.Status = s.StripHTML(f.Status)
+	f.Provider = s.StripHTML(f.Provider)
+
+	v.Check(validator.In(models.KYCStatus(f.Status), "",
+		models.KYCStatusPending,
+		models.KYCStatusInProgress,
+		models.KYCStatusVerified,
+		models.KYCStatusRejected), "status", "invalid status")
+}
+
+// SubmissionResponse represents a KYC submission in API responses.
+type SubmissionResponse struct {
+	ID              uuid.UUID              `json:"id"`
+	UserID          uuid.UUID              `json:"user_id"`
+	Provider        string                 `json:"provider"`
+	Reference       string                 `json:"reference"`
+	Status          models.KYCStatus       `json:"status"`
+	DocumentType    models.KYCDocumentType `json:"document_type"`
+	DocumentNumber  string                 `json:"document_number"`
+	FirstName       string                 `json:"first_name"`
+	LastName        string                 `json:"last_name"`
+	DateOfBirth     string                 `json:"date_of_birth"`
+	Address         string                 `json:"address,omitempty"`
+	RejectionReason string                 `json:"rejection_reason,omitempty"`
+	ReviewedBy      *uuid.UUID             `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time             `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// StatusResponse is the user's current KYC state.
+type StatusResponse struct {
+	Status           models.KYCStatus    `json:"status"`
+	VerifiedAt       *time.Time          `json:"verified_at,omitempty"`
+	LatestSubmission *SubmissionResponse `json:"latest_submission,omitempty"`
+}
+
+// ToSubmissionResponse converts a submission model; the document number is always masked.
+func ToSubmissionResponse(s *models.KYCSubmission) *SubmissionResponse {
+	return &SubmissionResponse{
+		ID:              s.ID,
+		UserID:          s.UserID,
+		Provider:        s.Provider,
+		Reference:       s.ProviderReference,
+		Status:          s.Status,
+		DocumentType:    s.DocumentType,
+		DocumentNumber:  s.MaskedDocumentNumber(),
+		FirstName:       s.FirstName,
+		LastName:        s.LastName,
+		DateOfBirth:     s.DateOfBirth.Format(dateLayout),
+		Address:         s.Address,
+		RejectionReason: s.RejectionReason,
+		ReviewedBy:      s.ReviewedBy,
+		ReviewedAt:      s.ReviewedAt,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
@@ -0,0 +1,61 @@
+package kyc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+)
+
+func TestSubmitRequest_Validate(t *testing.T) {
+	s := sanitizer.NewHTMLStripper()
+
+	req := &SubmitRequest{
+		DocumentType:   "passport",
+		DocumentNumber: "A12345678",
+		FirstName:      "Ada",
+		LastName:       "Obi",
+		DateOfBirth:    "1990-05-17",
+	}
+	v := validator.New()
+	req.Validate(v, s, 18)
+	assert.True(t, v.Valid())
+	assert.Equal(t, 1990, req.ParsedDOB.Year())
+
+	underage := &SubmitRequest{
+		DocumentType:   "nin",
+		DocumentNumber: "12345678901",
+		FirstName:      "Ada",
+		LastName:       "Obi",
+		DateOfBirth:    time.Now().AddDate(-17, 0, 0).Format(dateLayout),
+	}
+	v = validator.New()
+	underage.Validate(v, s, 18)
+	assert.Contains(t, v.Errors, "date_of_birth")
+
+	invalid := &SubmitRequest{DocumentType: "library_card", DateOfBirth: "17/05/1990"}
+	v = validator.New()
+	invalid.Validate(v, s, 18)
+	assert.Contains(t, v.Errors, "document_type")
+	assert.Contains(t, v.Errors, "document_number")
+	assert.Contains(t, v.Errors, "date_of_birth")
+}
+
+func TestReviewRequest_Validate(t *testing.T) {
+	s := sanitizer.NewHTMLStripper()
+
+	v := validator.New()
+	(&ReviewRequest{Status: "verified"}).Validate(v, s)
+	assert.True(t, v.Valid())
+
+	v = validator.New()
+	(&ReviewRequest{Status: "rejected"}).Validate(v, s)
+	assert.Contains(t, v.Errors, "reason")
+
+	v = validator.New()
+	(&ReviewRequest{Status: "pending"}).Validate(v, s)
+	assert.Contains(t, v.Errors, "status")
+}
@@ -0,0 +1,288 @@
+package kyc
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// SignatureHeader carries the provider's HMAC signature on webhook requests
+const SignatureHeader = "X-KYC-Signature"
+
+// maxWebhookBodySize bounds the webhook payload read into memory
+const maxWebhookBodySize = 1 << 20
+
+// Handler handles HTTP requests for KYC operations
+type Handler struct {
+	service   Service
+	config    *Config
+	sanitizer sanitizer.HTMLStripperer
+	logger    logger.Logger
+}
+
+// NewHandler creates a new KYC handler
+func NewHandler(service Service, config *Config, s sanitizer.HTMLStripperer, lg logger.Logger) *Handler {
+	return &Handler{service: service, config: config, sanitizer: s, logger: lg}
+}
+
+// Submit godoc
+// @Summary      Submit identity data
+// @Description  Submit identity details for KYC verification
+// @Tags         kyc
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body SubmitRequest true "KYC submission"
+// @Success      201  {object}  api.Response{data=SubmissionResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/kyc/submissions [post]
+func (h *Handler) Submit(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req SubmitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v, h.sanitizer, h.config.MinimumAge)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	submission, err := h.service.Submit(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrKYCAlreadyVerified):
+			api.ConflictResponse(c, "KYC is already verified")
+		case errors.Is(err, models.ErrKYCSubmissionPending):
+			api.ConflictResponse(c, "A KYC submission is already awaiting review")
+		case errors.Is(err, models.ErrInvalidKYCDocument):
+			api.BadRequestResponse(c, err.Error())
+		default:
+			h.logger.Error(err, logger.Fields{"handler": "Submit", "user_id": userID})
+			api.InternalErrorResponse(c, "Failed to submit KYC")
+		}
+		return
+	}
+
+	api.CreatedResponse(c, "KYC submission received", submission)
+}
+
+// GetStatus godoc
+// @Summary      Get KYC status
+// @Description  Get the authenticated user's KYC status and latest submission
+// @Tags         kyc
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=StatusResponse}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/kyc/status [get]
+func (h *Handler) GetStatus(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	status, err := h.service.GetStatus(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetStatus", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to retrieve KYC status")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "KYC status retrieved successfully", status)
+}
+
+// Webhook godoc
+// @Summary      KYC provider webhook
+// @Description  Receives signed verification status updates from a KYC provider
+// @Tags         kyc
+// @Accept       json
+// @Produce      json
+// @Param        provider path string true "Provider name"
+// @Param        X-KYC-Signature header string true "Hex encoded HMAC-SHA256 of the body"
+// @Success      200  {object}  api.Response
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/kyc/webhooks/{provider} [post]
+func (h *Handler) Webhook(c *gin.Context) {
+	provider := c.Param("provider")
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid request body")
+		return
+	}
+
+	err = h.service.HandleWebhook(c.Request.Context(), provider, payload, c.GetHeader(SignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidKYCSignature):
+			api.UnauthorizedResponse(c)
+		case errors.Is(err, models.ErrInvalidKYCProvider):
+			api.NotFoundResponse(c, "KYC provider")
+		case errors.Is(err, models.ErrRecordNotFound):
+			api.NotFoundResponse(c, "KYC submission")
+		default:
+			h.logger.Error(err, logger.Fields{"handler": "Webhook", "provider": provider})
+			api.BadRequestResponse(c, "Failed to process webhook")
+		}
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Webhook processed", nil)
+}
+
+// GetSubmissions godoc
+// @Summary      List KYC submissions (Admin)
+// @Description  Retrieves the KYC manual review queue. Defaults to submissions awaiting review.
+// @Tags         Admin
+// @Produce      json
+// @Param        page     query int    false "Page number" default(1)
+// @Param        per_page query int    false "Items per page" default(20)
+// @Param        status   query string false "Filter by status" Enums(pending, in_progress, verified, rejected)
+// @Param        provider query string false "Filter by provider"
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=[]SubmissionResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/kyc/submissions [get]
+func (h *Handler) GetSubmissions(c *gin.Context) {
+	var filters SubmissionFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	filters.SanitizeAndValidate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	submissions, total, err := h.service.GetReviewQueue(c.Request.Context(), &filters)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetSubmissions"})
+		api.InternalErrorResponse(c, "Failed to retrieve KYC submissions")
+		return
+	}
+
+	api.PaginatedResponse(c, "KYC submissions retrieved successfully", submissions, api.PaginationMeta{
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+		Total:   total,
+	})
+}
+
+// GetSubmission godoc
+// @Summary      Get KYC submission (Admin)
+// @Description  Retrieves a single KYC submission
+// @Tags         Admin
+// @Produce      json
+// @Param        id path string true "Submission ID"
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=SubmissionResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/kyc/submissions/{id} [get]
+func (h *Handler) GetSubmission(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid submission ID format")
+		return
+	}
+
+	submission, err := h.service.GetSubmission(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "KYC submission")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "GetSubmission", "submission_id": id})
+		api.InternalErrorResponse(c, "Failed to retrieve KYC submission")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "KYC submission retrieved successfully", submission)
+}
+
+// ReviewSubmission godoc
+// @Summary      Review KYC submission (Admin)
+// @Description  Approve or reject a KYC submission awaiting manual review
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id      path string        true "Submission ID"
+// @Param        request body ReviewRequest true "Review decision"
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=SubmissionResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/kyc/submissions/{id}/review [post]
+func (h *Handler) ReviewSubmission(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid submission ID format")
+		return
+	}
+
+	reviewerID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	submission, err := h.service.ReviewSubmission(c.Request.Context(), reviewerID, id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRecordNotFound):
+			api.NotFoundResponse(c, "KYC submission")
+		case errors.Is(err, models.ErrKYCSubmissionFinal):
+			api.ConflictResponse(c, "KYC submission has already been decided")
+		default:
+			h.logger.Error(err, logger.Fields{"handler": "ReviewSubmission", "submission_id": id})
+			api.InternalErrorResponse(c, "Failed to review KYC submission")
+		}
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "KYC submission reviewed successfully", submission)
+}
@@ -0,0 +1,63 @@
+package kyc
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "kyc_repository"
+	ServiceKey = "kyc_service"
+	ConfigKey  = "kyc_config"
+)
+
+// MountPublic mounts provider webhook routes
+func MountPublic(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	kycGroup := r.Group("/kyc")
+	kycGroup.POST("/webhooks/:provider", handler.Webhook)
+}
+
+// MountAuthenticated mounts user KYC routes
+func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	kycGroup := r.Group("/kyc")
+	kycGroup.POST("/submissions", handler.Submit)
+	kycGroup.GET("/status", handler.GetStatus)
+}
+
+// MountAdmin mounts the manual review queue
+func MountAdmin(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	adminGroup := r.Group("/admin/kyc/submissions")
+	adminGroup.GET("", api.Can(PermissionSubmissionsRead), handler.GetSubmissions)
+	adminGroup.GET("/:id", api.Can(PermissionSubmissionsRead), handler.GetSubmission)
+	adminGroup.POST("/:id/review", api.Can(PermissionSubmissionsReview), handler.ReviewSubmission)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid KYC configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+
+	localProvider := NewLocalProvider(config.WebhookSecret, config.LocalAutoApprove)
+	container.RegisterService(ServiceKey, NewService(repo, config, localProvider))
+	container.RegisterService(ConfigKey, config)
+}
+
+// createHandler creates a KYC handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+	config := container.GetService(ConfigKey).(*Config)
+
+	return NewHandler(service, config, container.Sanitizer, container.Logger)
+}
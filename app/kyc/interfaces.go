@@ -0,0 +1,28 @@
+package kyc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+)
+
+type Repository interface {
+	CreateSubmission(ctx context.Context, submission *models.KYCSubmission) error
+	GetSubmissionByID(ctx context.Context, id uuid.UUID) (*models.KYCSubmission, error)
+	GetSubmissionByReference(ctx context.Context, provider, reference string) (*models.KYCSubmission, error)
+	GetLatestSubmissionByUser(ctx context.Context, userID uuid.UUID) (*models.KYCSubmission, error)
+	GetSubmissions(ctx context.Context, filters *SubmissionFilters) ([]models.KYCSubmission, int64, error)
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	SaveDecision(ctx context.Context, submission *models.KYCSubmission, user *models.User) error
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+}
+
+type Service interface {
+	Submit(ctx context.Context, userID uuid.UUID, req *SubmitRequest) (*SubmissionResponse, error)
+	GetStatus(ctx context.Context, userID uuid.UUID) (*StatusResponse, error)
+	HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) error
+	GetReviewQueue(ctx context.Context, filters *SubmissionFilters) ([]SubmissionResponse, int64, error)
+	GetSubmission(ctx context.Context, id uuid.UUID) (*SubmissionResponse, error)
+	ReviewSubmission(ctx context.Context, reviewerID, submissionID uuid.UUID, req *ReviewRequest) (*SubmissionResponse, error)
+}
@@ -0,0 +1,9 @@
+package kyc
+
+import "github.com/joefazee/neo/app/api"
+
+// Permissions required by the admin routes of this module.
+var (
+	PermissionSubmissionsRead   = api.DefinePermission("admin:kyc:read", "View KYC submissions")
+	PermissionSubmissionsReview = api.DefinePermission("admin:kyc:review", "Approve or reject KYC submissions")
+)
@@ -0,0 +1,48 @@
+package kyc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/models"
+)
+
+func TestLocalProvider_Submit(t *testing.T) {
+	submission := &models.KYCSubmission{DocumentType: models.KYCDocumentNIN, DocumentNumber: "12345678901"}
+
+	result, err := NewLocalProvider("secret", false).Submit(context.Background(), submission)
+	require.NoError(t, err)
+	assert.Contains(t, result.Reference, LocalProviderName+"_")
+	assert.Equal(t, models.KYCStatusInProgress, result.Status)
+
+	result, err = NewLocalProvider("secret", true).Submit(context.Background(), submission)
+	require.NoError(t, err)
+	assert.Equal(t, models.KYCStatusVerified, result.Status)
+
+	_, err = NewLocalProvider("secret", false).Submit(context.Background(), &models.KYCSubmission{})
+	assert.ErrorIs(t, err, models.ErrInvalidKYCDocument)
+}
+
+func TestLocalProvider_ParseWebhook(t *testing.T) {
+	provider := NewLocalProvider("secret", false)
+	payload := []byte(`{"reference":"local_abc","status":"rejected","reason":"blurry document"}`)
+
+	event, err := provider.ParseWebhook(payload, SignPayload("secret", payload))
+	require.NoError(t, err)
+	assert.Equal(t, "local_abc", event.Reference)
+	assert.Equal(t, models.KYCStatusRejected, event.Status)
+	assert.Equal(t, "blurry document", event.Reason)
+
+	_, err = provider.ParseWebhook(payload, SignPayload("wrong", payload))
+	assert.ErrorIs(t, err, models.ErrInvalidKYCSignature)
+
+	_, err = provider.ParseWebhook(payload, "")
+	assert.ErrorIs(t, err, models.ErrInvalidKYCSignature)
+
+	missingRef := []byte(`{"status":"verified"}`)
+	_, err = provider.ParseWebhook(missingRef, SignPayload("secret", missingRef))
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new KYC repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateSubmission(ctx context.Context, submission *models.KYCSubmission) error {
+	return r.db.WithContext(ctx).Create(submission).Error
+}
+
+func (r *repository) GetSubmissionByID(ctx context.Context, id uuid.UUID) (*models.KYCSubmission, error) {
+	var submission models.KYCSubmission
+	err := r.db.WithContext(ctx).First(&submission, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (r *repository) GetSubmissionByReference(ctx context.Context, provider, reference string) (*models.KYCSubmission, error) {
+	var submission models.KYCSubmission
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND provider_reference = ?", provider, reference).
+		First(&submission).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (r *repository) GetLatestSubmissionByUser(ctx context.Context, userID uuid.UUID) (*models.KYCSubmission, error) {
+	var submission models.KYCSubmission
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&submission).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// GetSubmissions retrieves a paginated list of submissions, oldest first so the
+// review queue is worked in order of arrival.
+func (r *repository) GetSubmissions(ctx context.Context, filters *SubmissionFilters) ([]models.KYCSubmission, int64, error) {
+	var submissions []models.KYCSubmission
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.KYCSubmission{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.Provider != "" {
+		query = query.Where("provider = ?", filters.Provider)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting kyc submissions: %w", err)
+	}
+
+	offset := (filters.Page - 1) * filters.PerPage
+	err := query.Order("created_at ASC").Offset(offset).Limit(filters.PerPage).Find(&submissions).Error
+	return submissions, total, err
+}
+
+func (r *repository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, "id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SaveDecision persists the submission status and the user's KYC fields atomically.
+func (r *repository) SaveDecision(ctx context.Context, submission *models.KYCSubmission, user *models.User) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(submission).Updates(map[string]interface{}{
+			"status":             submission.Status,
+			"provider_reference": submission.ProviderReference,
+			"rejection_reason":   submission.RejectionReason,
+			"reviewed_by":        submission.ReviewedBy,
+			"reviewed_at":        submission.ReviewedAt,
+			"provider_data":      submission.ProviderData,
+		}).Error; err != nil {
+			return fmt.Errorf("updating kyc submission: %w", err)
+		}
+
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"kyc_status":      user.KYCStatus,
+			"kyc_provider":    user.KYCProvider,
+			"kyc_reference":   user.KYCReference,
+			"kyc_verified_at": user.KYCVerifiedAt,
+		}).Error; err != nil {
+			return fmt.Errorf("updating user kyc status: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *repository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
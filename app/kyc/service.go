@@ -0,0 +1,233 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/models"
+)
+
+type service struct {
+	repo      Repository
+	providers map[string]KYCProvider
+	config    *Config
+}
+
+// NewService creates a new KYC service. The provider named by config.DefaultProvider
+// handles new submissions; every registered provider may deliver webhooks.
+func NewService(repo Repository, config *Config, providers ...KYCProvider) Service {
+	registry := make(map[string]KYCProvider, len(providers))
+	for _, p := range providers {
+		registry[p.Name()] = p
+	}
+	return &service{repo: repo, providers: registry, config: config}
+}
+
+// Submit records the user's identity data and forwards it to the default provider.
+func (s *service) Submit(ctx context.Context, userID uuid.UUID, req *SubmitRequest) (*SubmissionResponse, error) {
+	provider, ok := s.providers[s.config.DefaultProvider]
+	if !ok {
+		return nil, models.ErrInvalidKYCProvider
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsKYCVerified() {
+		return nil, models.ErrKYCAlreadyVerified
+	}
+
+	latest, err := s.repo.GetLatestSubmissionByUser(ctx, userID)
+	if err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get latest submission: %w", err)
+	}
+	if latest != nil && latest.NeedsReview() {
+		return nil, models.ErrKYCSubmissionPending
+	}
+
+	submission := &models.KYCSubmission{
+		UserID:         userID,
+		Provider:       provider.Name(),
+		Status:         models.KYCStatusPending,
+		DocumentType:   models.KYCDocumentType(req.DocumentType),
+		DocumentNumber: req.DocumentNumber,
+		FirstName:      req.FirstName,
+		LastName:       req.LastName,
+		DateOfBirth:    req.ParsedDOB,
+		Address:        req.Address,
+	}
+	if err := submission.Validate(); err != nil {
+		return nil, err
+	}
+
+	result, err := provider.Submit(ctx, submission)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s rejected submission: %w", provider.Name(), err)
+	}
+	submission.ProviderReference = result.Reference
+	submission.ProviderData = result.Data
+
+	if err := s.repo.CreateSubmission(ctx, submission); err != nil {
+		return nil, fmt.Errorf("failed to create submission: %w", err)
+	}
+
+	submission.ApplyDecision(user, result.Status, result.Reason)
+	if err := s.repo.SaveDecision(ctx, submission, user); err != nil {
+		return nil, fmt.Errorf("failed to save kyc status: %w", err)
+	}
+
+	return ToSubmissionResponse(submission), nil
+}
+
+// GetStatus returns the user's KYC status together with their latest submission.
+func (s *service) GetStatus(ctx context.Context, userID uuid.UUID) (*StatusResponse, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	resp := &StatusResponse{Status: user.KYCStatus, VerifiedAt: user.KYCVerifiedAt}
+
+	latest, err := s.repo.GetLatestSubmissionByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return resp, nil
+		}
+		return nil, fmt.Errorf("failed to get latest submission: %w", err)
+	}
+	resp.LatestSubmission = ToSubmissionResponse(latest)
+	return resp, nil
+}
+
+// HandleWebhook applies an asynchronous status update from a provider. Updates for
+// submissions that already reached a final status are ignored so retries are safe.
+func (s *service) HandleWebhook(ctx context.Context, providerName string, payload []byte, signature string) error {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return models.ErrInvalidKYCProvider
+	}
+
+	event, err := provider.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+	if !isKnownStatus(event.Status) {
+		return fmt.Errorf("unknown kyc status %q", event.Status)
+	}
+
+	submission, err := s.repo.GetSubmissionByReference(ctx, provider.Name(), event.Reference)
+	if err != nil {
+		return err
+	}
+	if submission.IsFinal() {
+		return nil
+	}
+
+	user, err := s.repo.GetUserByID(ctx, submission.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if event.Data != nil {
+		if submission.ProviderData == nil {
+			submission.ProviderData = models.KYCSubmissionData{}
+		}
+		for k, v := range event.Data {
+			submission.ProviderData[k] = v
+		}
+	}
+
+	submission.ApplyDecision(user, event.Status, event.Reason)
+	if err := s.repo.SaveDecision(ctx, submission, user); err != nil {
+		return fmt.Errorf("failed to save kyc status: %w", err)
+	}
+	return nil
+}
+
+// GetReviewQueue lists submissions, defaulting to those still waiting on a decision.
+func (s *service) GetReviewQueue(ctx context.Context, filters *SubmissionFilters) ([]SubmissionResponse, int64, error) {
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.PerPage < 1 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+	if filters.Status == "" {
+		filters.Status = string(models.KYCStatusInProgress)
+	}
+
+	submissions, total, err := s.repo.GetSubmissions(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]SubmissionResponse, 0, len(submissions))
+	for i := range submissions {
+		responses = append(responses, *ToSubmissionResponse(&submissions[i]))
+	}
+	return responses, total, nil
+}
+
+// GetSubmission returns a single submission by ID.
+func (s *service) GetSubmission(ctx context.Context, id uuid.UUID) (*SubmissionResponse, error) {
+	submission, err := s.repo.GetSubmissionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToSubmissionResponse(submission), nil
+}
+
+// ReviewSubmission records a manual decision on a pending submission.
+func (s *service) ReviewSubmission(ctx context.Context,
+	reviewerID, submissionID uuid.UUID,
+	req *ReviewRequest) (*SubmissionResponse, error) {
+	submission, err := s.repo.GetSubmissionByID(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if !submission.NeedsReview() {
+		return nil, models.ErrKYCSubmissionFinal
+	}
+
+	user, err := s.repo.GetUserByID(ctx, submission.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	oldValues := models.AuditValues{"status": submission.Status}
+
+	now := time.Now()
+	submission.ReviewedBy = &reviewerID
+	submission.ReviewedAt = &now
+	submission.ApplyDecision(user, models.KYCStatus(req.Status), req.Reason)
+	if err := s.repo.SaveDecision(ctx, submission, user); err != nil {
+		return nil, fmt.Errorf("failed to save kyc status: %w", err)
+	}
+
+	actor := audit.ActorFromContext(ctx)
+	auditLog := models.CreateUserAuditLog(reviewerID,
+		models.AuditActionKYCReviewed,
+		models.AuditResourceKYCSubmission,
+		&submission.ID,
+		oldValues,
+		models.AuditValues{"status": submission.Status, "reason": submission.RejectionReason},
+		actor.IP, actor.UserAgent)
+	if err := s.repo.CreateAuditLog(ctx, auditLog); err != nil {
+		return nil, fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return ToSubmissionResponse(submission), nil
+}
+
+func isKnownStatus(status models.KYCStatus) bool {
+	switch status {
+	case models.KYCStatusPending, models.KYCStatusInProgress, models.KYCStatusVerified, models.KYCStatusRejected:
+		return true
+	}
+	return false
+}
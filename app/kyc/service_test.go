@@ -0,0 +1,247 @@
+package kyc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/models"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateSubmission(ctx context.Context, submission *models.KYCSubmission) error {
+	return m.Called(ctx, submission).Error(0)
+}
+
+func (m *MockRepository) GetSubmissionByID(ctx context.Context, id uuid.UUID) (*models.KYCSubmission, error) {
+	args := m.Called(ctx, id)
+	if s := args.Get(0); s != nil {
+		return s.(*models.KYCSubmission), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetSubmissionByReference(ctx context.Context, provider, reference string) (*models.KYCSubmission, error) {
+	args := m.Called(ctx, provider, reference)
+	if s := args.Get(0); s != nil {
+		return s.(*models.KYCSubmission), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetLatestSubmissionByUser(ctx context.Context, userID uuid.UUID) (*models.KYCSubmission, error) {
+	args := m.Called(ctx, userID)
+	if s := args.Get(0); s != nil {
+		return s.(*models.KYCSubmission), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetSubmissions(ctx context.Context, filters *SubmissionFilters) ([]models.KYCSubmission, int64, error) {
+	args := m.Called(ctx, filters)
+	if s := args.Get(0); s != nil {
+		return s.([]models.KYCSubmission), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if u := args.Get(0); u != nil {
+		return u.(*models.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) SaveDecision(ctx context.Context, submission *models.KYCSubmission, user *models.User) error {
+	return m.Called(ctx, submission, user).Error(0)
+}
+
+func (m *MockRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return m.Called(ctx, log).Error(0)
+}
+
+func newTestService(repo *MockRepository, autoApprove bool) Service {
+	config := GetDefaultConfig()
+	return NewService(repo, config, NewLocalProvider(config.WebhookSecret, autoApprove))
+}
+
+func validSubmitRequest() *SubmitRequest {
+	return &SubmitRequest{
+		DocumentType:   string(models.KYCDocumentNIN),
+		DocumentNumber: "12345678901",
+		FirstName:      "Ada",
+		LastName:       "Obi",
+		ParsedDOB:      time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestService_Submit(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("queues submission for review", func(t *testing.T) {
+		repo := new(MockRepository)
+		user := &models.User{ID: userID, KYCStatus: models.KYCStatusPending}
+		repo.On("GetUserByID", ctx, userID).Return(user, nil)
+		repo.On("GetLatestSubmissionByUser", ctx, userID).Return(nil, models.ErrRecordNotFound)
+		repo.On("CreateSubmission", ctx, mock.AnythingOfType("*models.KYCSubmission")).Return(nil)
+		repo.On("SaveDecision", ctx, mock.AnythingOfType("*models.KYCSubmission"), user).Return(nil)
+
+		resp, err := newTestService(repo, false).Submit(ctx, userID, validSubmitRequest())
+		require.NoError(t, err)
+		assert.Equal(t, models.KYCStatusInProgress, resp.Status)
+		assert.Equal(t, "*******8901", resp.DocumentNumber)
+		assert.Equal(t, models.KYCStatusInProgress, user.KYCStatus)
+		assert.Equal(t, LocalProviderName, user.KYCProvider)
+		assert.Nil(t, user.KYCVerifiedAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("auto approval verifies user", func(t *testing.T) {
+		repo := new(MockRepository)
+		user := &models.User{ID: userID, KYCStatus: models.KYCStatusPending}
+		repo.On("GetUserByID", ctx, userID).Return(user, nil)
+		repo.On("GetLatestSubmissionByUser", ctx, userID).Return(nil, models.ErrRecordNotFound)
+		repo.On("CreateSubmission", ctx, mock.Anything).Return(nil)
+		repo.On("SaveDecision", ctx, mock.Anything, user).Return(nil)
+
+		_, err := newTestService(repo, true).Submit(ctx, userID, validSubmitRequest())
+		require.NoError(t, err)
+		assert.True(t, user.IsKYCVerified())
+	})
+
+	t.Run("already verified", func(t *testing.T) {
+		repo := new(MockRepository)
+		now := time.Now()
+		repo.On("GetUserByID", ctx, userID).Return(&models.User{ID: userID, KYCStatus: models.KYCStatusVerified, KYCVerifiedAt: &now}, nil)
+
+		_, err := newTestService(repo, false).Submit(ctx, userID, validSubmitRequest())
+		assert.ErrorIs(t, err, models.ErrKYCAlreadyVerified)
+	})
+
+	t.Run("submission already awaiting review", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByID", ctx, userID).Return(&models.User{ID: userID}, nil)
+		repo.On("GetLatestSubmissionByUser", ctx, userID).Return(&models.KYCSubmission{Status: models.KYCStatusInProgress}, nil)
+
+		_, err := newTestService(repo, false).Submit(ctx, userID, validSubmitRequest())
+		assert.ErrorIs(t, err, models.ErrKYCSubmissionPending)
+		repo.AssertNotCalled(t, "CreateSubmission", mock.Anything, mock.Anything)
+	})
+
+	t.Run("resubmission after rejection", func(t *testing.T) {
+		repo := new(MockRepository)
+		user := &models.User{ID: userID, KYCStatus: models.KYCStatusRejected}
+		repo.On("GetUserByID", ctx, userID).Return(user, nil)
+		repo.On("GetLatestSubmissionByUser", ctx, userID).Return(&models.KYCSubmission{Status: models.KYCStatusRejected}, nil)
+		repo.On("CreateSubmission", ctx, mock.Anything).Return(nil)
+		repo.On("SaveDecision", ctx, mock.Anything, user).Return(nil)
+
+		_, err := newTestService(repo, false).Submit(ctx, userID, validSubmitRequest())
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_HandleWebhook(t *testing.T) {
+	ctx := context.Background()
+	secret := GetDefaultConfig().WebhookSecret
+	userID := uuid.New()
+	payload := []byte(`{"reference":"local_1","status":"verified"}`)
+
+	t.Run("verifies user", func(t *testing.T) {
+		repo := new(MockRepository)
+		submission := &models.KYCSubmission{UserID: userID, Provider: LocalProviderName, ProviderReference: "local_1", Status: models.KYCStatusInProgress}
+		user := &models.User{ID: userID}
+		repo.On("GetSubmissionByReference", ctx, LocalProviderName, "local_1").Return(submission, nil)
+		repo.On("GetUserByID", ctx, userID).Return(user, nil)
+		repo.On("SaveDecision", ctx, submission, user).Return(nil)
+
+		err := newTestService(repo, false).HandleWebhook(ctx, LocalProviderName, payload, SignPayload(secret, payload))
+		require.NoError(t, err)
+		assert.Equal(t, models.KYCStatusVerified, submission.Status)
+		assert.True(t, user.IsKYCVerified())
+		assert.Equal(t, "local_1", user.KYCReference)
+	})
+
+	t.Run("final submission is left untouched", func(t *testing.T) {
+		repo := new(MockRepository)
+		submission := &models.KYCSubmission{UserID: userID, Status: models.KYCStatusRejected}
+		repo.On("GetSubmissionByReference", ctx, LocalProviderName, "local_1").Return(submission, nil)
+
+		err := newTestService(repo, false).HandleWebhook(ctx, LocalProviderName, payload, SignPayload(secret, payload))
+		require.NoError(t, err)
+		assert.Equal(t, models.KYCStatusRejected, submission.Status)
+		repo.AssertNotCalled(t, "SaveDecision", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		err := newTestService(new(MockRepository), false).HandleWebhook(ctx, LocalProviderName, payload, "bad")
+		assert.ErrorIs(t, err, models.ErrInvalidKYCSignature)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		err := newTestService(new(MockRepository), false).HandleWebhook(ctx, "acme", payload, "")
+		assert.ErrorIs(t, err, models.ErrInvalidKYCProvider)
+	})
+}
+
+func TestService_ReviewSubmission(t *testing.T) {
+	ctx := context.Background()
+	reviewerID := uuid.New()
+	userID := uuid.New()
+	submissionID := uuid.New()
+
+	t.Run("rejects with reason and audits", func(t *testing.T) {
+		repo := new(MockRepository)
+		submission := &models.KYCSubmission{ID: submissionID, UserID: userID, Status: models.KYCStatusInProgress}
+		user := &models.User{ID: userID, KYCStatus: models.KYCStatusInProgress}
+		repo.On("GetSubmissionByID", ctx, submissionID).Return(submission, nil)
+		repo.On("GetUserByID", ctx, userID).Return(user, nil)
+		repo.On("SaveDecision", ctx, submission, user).Return(nil)
+		repo.On("CreateAuditLog", ctx, mock.MatchedBy(func(log *models.AuditLog) bool {
+			return log.Action == models.AuditActionKYCReviewed && *log.UserID == reviewerID && *log.ResourceID == submissionID
+		})).Return(nil)
+
+		resp, err := newTestService(repo, false).ReviewSubmission(ctx, reviewerID, submissionID,
+			&ReviewRequest{Status: string(models.KYCStatusRejected), Reason: "document expired"})
+		require.NoError(t, err)
+		assert.Equal(t, models.KYCStatusRejected, resp.Status)
+		assert.Equal(t, "document expired", resp.RejectionReason)
+		assert.Equal(t, &reviewerID, resp.ReviewedBy)
+		assert.Equal(t, models.KYCStatusRejected, user.KYCStatus)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("already decided", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetSubmissionByID", ctx, submissionID).Return(&models.KYCSubmission{Status: models.KYCStatusVerified}, nil)
+
+		_, err := newTestService(repo, false).ReviewSubmission(ctx, reviewerID, submissionID,
+			&ReviewRequest{Status: string(models.KYCStatusVerified)})
+		assert.ErrorIs(t, err, models.ErrKYCSubmissionFinal)
+	})
+}
+
+func TestService_GetReviewQueue_Defaults(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockRepository)
+	filters := &SubmissionFilters{}
+	repo.On("GetSubmissions", ctx, filters).Return([]models.KYCSubmission{{ID: uuid.New()}}, int64(1), nil)
+
+	submissions, total, err := newTestService(repo, false).GetReviewQueue(ctx, filters)
+	require.NoError(t, err)
+	assert.Len(t, submissions, 1)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 1, filters.Page)
+	assert.Equal(t, 20, filters.PerPage)
+	assert.Equal(t, string(models.KYCStatusInProgress), filters.Status)
+}
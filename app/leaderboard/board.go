@@ -0,0 +1,85 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Periods a leaderboard covers. Dated periods run in UTC; weeks are ISO weeks.
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+	PeriodAllTime = "all_time"
+)
+
+// Metrics users are ranked by
+const (
+	MetricProfit = "profit"
+	MetricROI    = "roi"
+	MetricWins   = "wins"
+)
+
+// totalStaked is kept alongside the ranked metrics to derive ROI from
+const totalStaked = "staked"
+
+// allCategories stands in for the category of a country-wide board
+const allCategories = "all"
+
+var periods = []string{PeriodDaily, PeriodWeekly, PeriodMonthly, PeriodAllTime}
+
+// board identifies one leaderboard; each metric on it is its own sorted set
+type board struct {
+	period    string
+	window    string
+	countryID uuid.UUID
+	category  string
+}
+
+func newBoard(period string, at time.Time, countryID, categoryID uuid.UUID) board {
+	category := allCategories
+	if categoryID != uuid.Nil {
+		category = categoryID.String()
+	}
+	return board{period: period, window: periodWindow(period, at), countryID: countryID, category: category}
+}
+
+// set names the sorted set that holds metric for this board
+func (b board) set(metric string) string {
+	return fmt.Sprintf("leaderboard:%s:%s:%s:%s:%s", b.period, b.window, b.countryID, b.category, metric)
+}
+
+// periodWindow labels the period containing at, e.g. 2024-01-15, 2024-W03 or 2024-01
+func periodWindow(period string, at time.Time) string {
+	at = at.UTC()
+	switch period {
+	case PeriodDaily:
+		return at.Format("2006-01-02")
+	case PeriodWeekly:
+		year, week := at.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonthly:
+		return at.Format("2006-01")
+	default:
+		return "all"
+	}
+}
+
+// periodRetention is how long a dated board is kept: its own period plus one
+// more, so a board is still there to read once its period has ended. All-time
+// boards are kept indefinitely.
+func periodRetention(period string) time.Duration {
+	const day = 24 * time.Hour
+	switch period {
+	case PeriodDaily:
+		return 2 * day
+	case PeriodWeekly:
+		return 14 * day
+	case PeriodMonthly:
+		return 62 * day
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,38 @@
+package leaderboard
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// Config represents the configuration for the leaderboard module
+type Config struct {
+	// ROIMinimumStake is how much a user must have staked on settled bets in
+	// a period before they are ranked by ROI, so a lucky small stake cannot
+	// top the board.
+	ROIMinimumStake decimal.Decimal `env:"LEADERBOARD_ROI_MINIMUM_STAKE"`
+
+	// DefaultEntries and MaxEntries bound how many users one request lists.
+	DefaultEntries int `env:"LEADERBOARD_DEFAULT_ENTRIES"`
+	MaxEntries     int `env:"LEADERBOARD_MAX_ENTRIES"`
+}
+
+func (c *Config) Validate() error {
+	if !c.ROIMinimumStake.IsPositive() {
+		return errors.New("ROI minimum stake must be positive")
+	}
+	if c.DefaultEntries < 1 || c.MaxEntries < c.DefaultEntries {
+		return errors.New("leaderboard entries must be at least 1 and no more than the maximum")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default leaderboard configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		ROIMinimumStake: decimal.NewFromInt(1000),
+		DefaultEntries:  20,
+		MaxEntries:      100,
+	}
+}
@@ -0,0 +1,25 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, GetDefaultConfig().Validate())
+
+	config := GetDefaultConfig()
+	config.ROIMinimumStake = decimal.Zero
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.DefaultEntries = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.MaxEntries = config.DefaultEntries - 1
+	assert.Error(t, config.Validate())
+}
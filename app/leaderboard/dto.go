@@ -0,0 +1,93 @@
+package leaderboard
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// LeaderboardRequest selects a leaderboard. Date picks the period that
+// contains it and defaults to today; past periods are only kept for a while.
+type LeaderboardRequest struct {
+	CountryID  string `form:"country_id"`
+	CategoryID string `form:"category_id"`
+	Period     string `form:"period"`
+	Metric     string `form:"metric"`
+	Date       string `form:"date"`
+	Limit      int    `form:"limit"`
+
+	ParsedCountryID  uuid.UUID `form:"-"`
+	ParsedCategoryID uuid.UUID `form:"-"`
+	ParsedDate       time.Time `form:"-"`
+}
+
+// Validate fills in defaults and parses the typed values.
+func (r *LeaderboardRequest) Validate(v *validator.Validator, config *Config, now time.Time) bool {
+	if r.Period == "" {
+		r.Period = PeriodAllTime
+	}
+	if r.Metric == "" {
+		r.Metric = MetricProfit
+	}
+	if r.Limit == 0 {
+		r.Limit = config.DefaultEntries
+	}
+	v.Check(validator.In(r.Period, periods...), "period", "period must be one of daily, weekly, monthly or all_time")
+	v.Check(validator.In(r.Metric, MetricProfit, MetricROI, MetricWins), "metric", "metric must be one of profit, roi or wins")
+	v.Check(r.Limit > 0 && r.Limit <= config.MaxEntries, "limit", "limit is out of range")
+
+	if id, err := uuid.Parse(r.CountryID); err != nil {
+		v.AddError("country_id", "must be a valid UUID")
+	} else {
+		r.ParsedCountryID = id
+	}
+	if r.CategoryID != "" {
+		if id, err := uuid.Parse(r.CategoryID); err != nil {
+			v.AddError("category_id", "must be a valid UUID")
+		} else {
+			r.ParsedCategoryID = id
+		}
+	}
+
+	r.ParsedDate = now
+	if r.Date != "" {
+		date, err := time.Parse("2006-01-02", r.Date)
+		if err != nil {
+			v.AddError("date", "date must be in YYYY-MM-DD format")
+		} else {
+			v.Check(!date.After(now), "date", "date must not be in the future")
+			r.ParsedDate = date
+		}
+	}
+	return v.Valid()
+}
+
+// LeaderboardEntry is one ranked user. Only their first name and last
+// initial are shown.
+type LeaderboardEntry struct {
+	Rank        int             `json:"rank"`
+	DisplayName string          `json:"display_name"`
+	Score       decimal.Decimal `json:"score"`
+}
+
+// LeaderboardResponse is one leaderboard, best first. Profit is in the
+// country's currency, ROI a percentage and wins a count of winning bets.
+type LeaderboardResponse struct {
+	CountryID  uuid.UUID          `json:"country_id"`
+	CategoryID *uuid.UUID         `json:"category_id,omitempty"`
+	Period     string             `json:"period"`
+	Window     string             `json:"window"`
+	Metric     string             `json:"metric"`
+	Entries    []LeaderboardEntry `json:"entries"`
+}
+
+func displayName(user *models.User) string {
+	lastName := []rune(user.LastName)
+	if len(lastName) == 0 {
+		return user.FirstName
+	}
+	return user.FirstName + " " + string(lastName[0]) + "."
+}
@@ -0,0 +1,61 @@
+package leaderboard
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/validator"
+)
+
+// Handler handles HTTP requests for leaderboards
+type Handler struct {
+	service Service
+	config  *Config
+	logger  logger.Logger
+}
+
+// NewHandler creates a new leaderboard handler
+func NewHandler(service Service, config *Config, lg logger.Logger) *Handler {
+	return &Handler{service: service, config: config, logger: lg}
+}
+
+// GetLeaderboard godoc
+// @Summary      Get a leaderboard
+// @Description  Rank users in a country, optionally within one category, by net profit, ROI or winning bets over a day, week, month or all time. Users who opted out are not shown.
+// @Tags         leaderboards
+// @Produce      json
+// @Param        country_id   query     string  true   "Country ID"
+// @Param        category_id  query     string  false  "Category ID"
+// @Param        period       query     string  false  "daily, weekly, monthly or all_time (default)"
+// @Param        metric       query     string  false  "profit (default), roi or wins"
+// @Param        date         query     string  false  "A day in the period to show, YYYY-MM-DD (default today)"
+// @Param        limit        query     int     false  "Number of users to list"
+// @Success      200  {object}  api.Response{data=LeaderboardResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/leaderboards [get]
+func (h *Handler) GetLeaderboard(c *gin.Context) {
+	var req LeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	if !req.Validate(v, h.config, time.Now()) {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	leaderboard, err := h.service.GetLeaderboard(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetLeaderboard"})
+		api.InternalErrorResponse(c, "Failed to retrieve leaderboard")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Leaderboard retrieved successfully", leaderboard)
+}
@@ -0,0 +1,51 @@
+package leaderboard
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "leaderboard_repository"
+	ServiceKey = "leaderboard_service"
+	ConfigKey  = "leaderboard_config"
+)
+
+// MountPublic mounts the leaderboards, which anyone may read
+func MountPublic(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	r.GET("/leaderboards", handler.GetLeaderboard)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid leaderboard configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+	container.RegisterService(ServiceKey, NewService(repo, sortedSets(container), config))
+	container.RegisterService(ConfigKey, config)
+}
+
+// sortedSets keeps the boards in the shared cache, so Redis shares them
+// across instances. A cache without sorted sets falls back to boards local
+// to this process.
+func sortedSets(container *deps.Container) cache.SortedSets {
+	if sets, ok := container.Cache.(cache.SortedSets); ok {
+		return sets
+	}
+	return cache.NewMemoryCache[string]()
+}
+
+// createHandler creates a leaderboard handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+	config := container.GetService(ConfigKey).(*Config)
+
+	return NewHandler(service, config, container.Logger)
+}
@@ -0,0 +1,42 @@
+package leaderboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+type Repository interface {
+	// GetRankableUsers returns those of the given users who may be shown on
+	// a leaderboard: active and not opted out.
+	GetRankableUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.User, error)
+}
+
+// Result is one won or lost position as it counts toward the leaderboards.
+// Refunds are not results and are never recorded.
+type Result struct {
+	UserID    uuid.UUID
+	CountryID uuid.UUID
+	// CategoryID is uuid.Nil for positions that span categories; they count
+	// toward the country-wide boards only.
+	CategoryID uuid.UUID
+	Stake      decimal.Decimal
+	Payout     decimal.Decimal
+	Won        bool
+	SettledAt  time.Time
+}
+
+// Recorder updates the leaderboards as positions settle. Failures are logged
+// rather than returned so they never undo the settlement.
+type Recorder interface {
+	RecordResult(ctx context.Context, result Result)
+}
+
+type Service interface {
+	Recorder
+
+	GetLeaderboard(ctx context.Context, req *LeaderboardRequest) (*LeaderboardResponse, error)
+}
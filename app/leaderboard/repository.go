@@ -0,0 +1,34 @@
+package leaderboard
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new leaderboard repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetRankableUsers leaves out suspended and erased users, who are inactive,
+// and users who opted out of leaderboards in their profile.
+func (r *repository) GetRankableUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).
+		Select("id", "first_name", "last_name").
+		Where("id IN ?", userIDs).
+		Where("is_active = ?", true).
+		Where("COALESCE((metadata ->> 'hide_from_leaderboards')::boolean, false) = ?", false).
+		Find(&users).Error
+	return users, err
+}
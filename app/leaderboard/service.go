@@ -0,0 +1,138 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/shopspring/decimal"
+)
+
+type service struct {
+	repo   Repository
+	sets   cache.SortedSets
+	config *Config
+}
+
+// NewService creates a new leaderboard service that keeps its boards in sets.
+func NewService(repo Repository, sets cache.SortedSets, config *Config) Service {
+	return &service{repo: repo, sets: sets, config: config}
+}
+
+// RecordResult adds a settled position to every board it counts toward: each
+// period, for the country as a whole and for the position's category.
+func (s *service) RecordResult(ctx context.Context, result Result) {
+	categories := []uuid.UUID{uuid.Nil}
+	if result.CategoryID != uuid.Nil {
+		categories = append(categories, result.CategoryID)
+	}
+	for _, period := range periods {
+		for _, categoryID := range categories {
+			b := newBoard(period, result.SettledAt, result.CountryID, categoryID)
+			if err := s.record(ctx, b, result); err != nil {
+				log.Printf("Warning: Failed to record %s leaderboard result for user %s: %v", period, result.UserID, err)
+			}
+		}
+	}
+}
+
+// record applies one result to a board. ROI is recomputed from the running
+// totals rather than incremented, and only once the user has staked enough
+// to qualify.
+func (s *service) record(ctx context.Context, b board, result Result) error {
+	member := result.UserID.String()
+	profit := result.Payout.Sub(result.Stake).InexactFloat64()
+
+	totalProfit, err := s.sets.IncrementScore(ctx, b.set(MetricProfit), member, profit)
+	if err != nil {
+		return fmt.Errorf("increment profit: %w", err)
+	}
+	staked, err := s.sets.IncrementScore(ctx, b.set(totalStaked), member, result.Stake.InexactFloat64())
+	if err != nil {
+		return fmt.Errorf("increment stake: %w", err)
+	}
+	if result.Won {
+		if _, err := s.sets.IncrementScore(ctx, b.set(MetricWins), member, 1); err != nil {
+			return fmt.Errorf("increment wins: %w", err)
+		}
+	}
+	if staked > 0 && staked >= s.config.ROIMinimumStake.InexactFloat64() {
+		if err := s.sets.SetScore(ctx, b.set(MetricROI), member, totalProfit/staked*100); err != nil {
+			return fmt.Errorf("set roi: %w", err)
+		}
+	}
+
+	if ttl := periodRetention(b.period); ttl > 0 {
+		for _, metric := range []string{MetricProfit, MetricROI, MetricWins, totalStaked} {
+			if err := s.sets.ExpireSet(ctx, b.set(metric), ttl); err != nil {
+				return fmt.Errorf("expire %s: %w", metric, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetLeaderboard lists the top users on a board. Users who may not be shown
+// are skipped and the ones below them move up, so the board is read in pages
+// until it has enough users to show or runs out.
+func (s *service) GetLeaderboard(ctx context.Context, req *LeaderboardRequest) (*LeaderboardResponse, error) {
+	b := newBoard(req.Period, req.ParsedDate, req.ParsedCountryID, req.ParsedCategoryID)
+	resp := &LeaderboardResponse{
+		CountryID: req.ParsedCountryID,
+		Period:    req.Period,
+		Window:    b.window,
+		Metric:    req.Metric,
+		Entries:   []LeaderboardEntry{},
+	}
+	if req.ParsedCategoryID != uuid.Nil {
+		resp.CategoryID = &req.ParsedCategoryID
+	}
+
+	for offset := 0; len(resp.Entries) < req.Limit; offset += req.Limit {
+		members, err := s.sets.TopMembers(ctx, b.set(req.Metric), offset, req.Limit)
+		if err != nil {
+			return nil, fmt.Errorf("read leaderboard: %w", err)
+		}
+		if err := s.appendRankable(ctx, resp, members, req.Limit); err != nil {
+			return nil, err
+		}
+		if len(members) < req.Limit {
+			break
+		}
+	}
+	return resp, nil
+}
+
+// appendRankable adds the members that may be shown to resp, in score order,
+// until it holds limit entries
+func (s *service) appendRankable(ctx context.Context, resp *LeaderboardResponse, members []cache.ScoredMember, limit int) error {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		if id, err := uuid.Parse(m.Member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	users, err := s.repo.GetRankableUsers(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("get users: %w", err)
+	}
+	names := make(map[string]string, len(users))
+	for i := range users {
+		names[users[i].ID.String()] = displayName(&users[i])
+	}
+
+	for _, m := range members {
+		name, ok := names[m.Member]
+		if !ok || len(resp.Entries) == limit {
+			continue
+		}
+		resp.Entries = append(resp.Entries, LeaderboardEntry{
+			Rank:        len(resp.Entries) + 1,
+			DisplayName: name,
+			Score:       decimal.NewFromFloat(m.Score).Round(2),
+		})
+	}
+	return nil
+}
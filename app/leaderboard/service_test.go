@@ -0,0 +1,177 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetRankableUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.User, error) {
+	args := m.Called(ctx, userIDs)
+	if fn, ok := args.Get(0).(func(context.Context, []uuid.UUID) []models.User); ok {
+		return fn(ctx, userIDs), args.Error(1)
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+// rankableUsers makes the repository return those of the requested users
+// found in users
+func rankableUsers(repo *MockRepository, users ...models.User) {
+	repo.On("GetRankableUsers", mock.Anything, mock.Anything).Return(func(_ context.Context, ids []uuid.UUID) []models.User {
+		var found []models.User
+		for _, id := range ids {
+			for _, user := range users {
+				if user.ID == id {
+					found = append(found, user)
+				}
+			}
+		}
+		return found
+	}, nil)
+}
+
+func newTestService(t *testing.T, repo Repository) (*service, *cache.MemoryCache[string]) {
+	t.Helper()
+	sets := cache.NewMemoryCache[string]()
+	t.Cleanup(sets.Stop)
+	config := GetDefaultConfig()
+	config.ROIMinimumStake = decimal.NewFromInt(200)
+	return NewService(repo, sets, config).(*service), sets
+}
+
+func result(userID, countryID, categoryID uuid.UUID, stake, payout int64, at time.Time) Result {
+	return Result{
+		UserID:     userID,
+		CountryID:  countryID,
+		CategoryID: categoryID,
+		Stake:      decimal.NewFromInt(stake),
+		Payout:     decimal.NewFromInt(payout),
+		Won:        payout > 0,
+		SettledAt:  at,
+	}
+}
+
+func TestService_RecordResult(t *testing.T) {
+	s, sets := newTestService(t, new(MockRepository))
+	ctx := context.Background()
+	countryID, categoryID := uuid.New(), uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+	at := time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+	s.RecordResult(ctx, result(alice, countryID, categoryID, 100, 250, at))
+	s.RecordResult(ctx, result(alice, countryID, categoryID, 100, 0, at))
+	s.RecordResult(ctx, result(bob, countryID, uuid.Nil, 50, 120, at))
+
+	score := func(b board, metric string, userID uuid.UUID) float64 {
+		t.Helper()
+		value, err := sets.Score(ctx, b.set(metric), userID.String())
+		require.NoError(t, err)
+		return value
+	}
+
+	for _, period := range periods {
+		overall := newBoard(period, at, countryID, uuid.Nil)
+		assert.Equal(t, 50.0, score(overall, MetricProfit, alice), period)
+		assert.Equal(t, 1.0, score(overall, MetricWins, alice), period)
+		assert.Equal(t, 25.0, score(overall, MetricROI, alice), period)
+		assert.Equal(t, 70.0, score(overall, MetricProfit, bob), period)
+	}
+
+	category := newBoard(PeriodWeekly, at, countryID, categoryID)
+	assert.Equal(t, 50.0, score(category, MetricProfit, alice))
+	_, err := sets.Score(ctx, category.set(MetricProfit), bob.String())
+	assert.ErrorIs(t, err, cache.ErrCacheMiss, "a result without a category stays off category boards")
+
+	_, err = sets.Score(ctx, newBoard(PeriodAllTime, at, countryID, uuid.Nil).set(MetricROI), bob.String())
+	assert.ErrorIs(t, err, cache.ErrCacheMiss, "a small stake does not qualify for the ROI board")
+
+	_, err = sets.Score(ctx, newBoard(PeriodDaily, at.AddDate(0, 0, 1), countryID, uuid.Nil).set(MetricProfit), alice.String())
+	assert.ErrorIs(t, err, cache.ErrCacheMiss, "the next day starts a new board")
+}
+
+func TestService_GetLeaderboard(t *testing.T) {
+	repo := new(MockRepository)
+	s, _ := newTestService(t, repo)
+	ctx := context.Background()
+	countryID := uuid.New()
+	now := time.Now()
+
+	users := make([]models.User, 5)
+	for i := range users {
+		users[i] = models.User{ID: uuid.New(), FirstName: "User", LastName: string(rune('A' + i))}
+		s.RecordResult(ctx, result(users[i].ID, countryID, uuid.Nil, 10, int64(100*(i+1)), now))
+	}
+	// The two best users are hidden, so the board reads on to fill the page
+	rankableUsers(repo, users[0], users[1], users[2])
+
+	req := &LeaderboardRequest{CountryID: countryID.String(), Limit: 2}
+	require.True(t, req.Validate(validator.New(), s.config, now))
+
+	board, err := s.GetLeaderboard(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, PeriodAllTime, board.Period)
+	assert.Equal(t, "all", board.Window)
+	require.Len(t, board.Entries, 2)
+	assert.Equal(t, 1, board.Entries[0].Rank)
+	assert.Equal(t, "User C.", board.Entries[0].DisplayName)
+	assert.True(t, decimal.NewFromInt(290).Equal(board.Entries[0].Score), "score %s", board.Entries[0].Score)
+	assert.Equal(t, 2, board.Entries[1].Rank)
+	assert.Equal(t, "User B.", board.Entries[1].DisplayName)
+
+	req = &LeaderboardRequest{CountryID: countryID.String(), Metric: MetricROI}
+	require.True(t, req.Validate(validator.New(), s.config, now))
+	board, err = s.GetLeaderboard(ctx, req)
+	require.NoError(t, err)
+	assert.Empty(t, board.Entries, "nobody has staked enough for ROI")
+}
+
+func TestLeaderboardRequest_Validate(t *testing.T) {
+	config := GetDefaultConfig()
+	now := time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+	req := &LeaderboardRequest{CountryID: uuid.NewString()}
+	require.True(t, req.Validate(validator.New(), config, now))
+	assert.Equal(t, PeriodAllTime, req.Period)
+	assert.Equal(t, MetricProfit, req.Metric)
+	assert.Equal(t, config.DefaultEntries, req.Limit)
+	assert.Equal(t, now, req.ParsedDate)
+
+	req = &LeaderboardRequest{CountryID: uuid.NewString(), Period: PeriodWeekly, Date: "2024-01-02"}
+	require.True(t, req.Validate(validator.New(), config, now))
+	assert.Equal(t, time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC), req.ParsedDate)
+
+	for name, req := range map[string]*LeaderboardRequest{
+		"missing country": {},
+		"bad category":    {CountryID: uuid.NewString(), CategoryID: "sports"},
+		"bad period":      {CountryID: uuid.NewString(), Period: "yearly"},
+		"bad metric":      {CountryID: uuid.NewString(), Metric: "volume"},
+		"future date":     {CountryID: uuid.NewString(), Date: "2024-02-01"},
+		"limit too large": {CountryID: uuid.NewString(), Limit: config.MaxEntries + 1},
+	} {
+		assert.False(t, req.Validate(validator.New(), config, now), name)
+	}
+}
+
+func TestPeriodWindow(t *testing.T) {
+	at := time.Date(2024, time.January, 1, 23, 30, 0, 0, time.FixedZone("WAT", -3600))
+	assert.Equal(t, "2024-01-02", periodWindow(PeriodDaily, at), "windows are in UTC")
+	assert.Equal(t, "2024-W01", periodWindow(PeriodWeekly, at))
+	assert.Equal(t, "2024-01", periodWindow(PeriodMonthly, at))
+	assert.Equal(t, "all", periodWindow(PeriodAllTime, at))
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joefazee/neo/app/categories"
 	"github.com/joefazee/neo/app/countries"
+	"github.com/joefazee/neo/app/prediction"
 	"github.com/joefazee/neo/internal/deps"
 )
 
@@ -56,7 +57,8 @@ func InitRepositories(container *deps.Container) {
 	container.RegisterRepository(MarketRepoKey, repo)
 
 	// Initialize service
-	service := NewService(repo, config, pe, se)
+	settler, _ := container.GetService(prediction.ServiceKey).(prediction.MarketSettler)
+	service := NewService(repo, config, pe, se, settler)
 	container.RegisterService(MarketServiceKey, service)
 }
 
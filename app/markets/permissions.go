@@ -0,0 +1,6 @@
+package markets
+
+import "github.com/joefazee/neo/app/api"
+
+// PermissionMarketAdmin gates the market administration routes.
+var PermissionMarketAdmin = api.DefinePermission("market:admin", "Manage markets")
@@ -11,6 +11,7 @@ import (
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 
+	"github.com/joefazee/neo/app/prediction"
 	"github.com/joefazee/neo/models"
 )
 
@@ -20,15 +21,22 @@ type service struct {
 	config          *Config
 	pricingEngine   PricingEngine
 	safeguardEngine SafeguardEngine
+	settler         prediction.MarketSettler
 }
 
-// NewService creates a new market service
-func NewService(repo Repository, config *Config, pricingEngine PricingEngine, safeguardEngine SafeguardEngine) Service {
+// NewService creates a new market service. settler, which may be nil, is
+// told when a market is resolved or voided so the bets on it are settled.
+func NewService(repo Repository,
+	config *Config,
+	pricingEngine PricingEngine,
+	safeguardEngine SafeguardEngine,
+	settler prediction.MarketSettler) Service {
 	return &service{
 		repo:            repo,
 		config:          config,
 		pricingEngine:   pricingEngine,
 		safeguardEngine: safeguardEngine,
+		settler:         settler,
 	}
 }
 
@@ -248,7 +256,7 @@ func (s *service) ResolveMarket(ctx context.Context, id uuid.UUID, req ResolveMa
 		return nil, fmt.Errorf("failed to save resolved market: %w", err)
 	}
 
-	// TODO: Trigger settlement process (async)
+	// Trigger settlement process (async)
 	go s.processMarketSettlement(context.Background(), market.ID)
 
 	return ToMarketDetailResponse(market), nil
@@ -276,7 +284,7 @@ func (s *service) VoidMarket(ctx context.Context, id uuid.UUID, reason string) e
 		return fmt.Errorf("failed to void market: %w", err)
 	}
 
-	// TODO: Trigger refund process (async)
+	// Trigger refund process (async)
 	go s.processMarketRefunds(context.Background(), market.ID)
 
 	return nil
@@ -570,12 +578,18 @@ func (s *service) incrementViewCount(_ context.Context, _ uuid.UUID) {
 	// TODO: Implement view count increment
 }
 
-func (s *service) processMarketSettlement(_ context.Context, _ uuid.UUID) {
-	// TODO: Implement market settlement process
+func (s *service) processMarketSettlement(ctx context.Context, marketID uuid.UUID) {
+	// Single bets and parlay legs on the market are settled here
+	if s.settler != nil {
+		s.settler.SettleMarket(ctx, marketID)
+	}
 }
 
-func (s *service) processMarketRefunds(_ context.Context, _ uuid.UUID) {
-	// TODO: Implement market refund process
+func (s *service) processMarketRefunds(ctx context.Context, marketID uuid.UUID) {
+	// Single bets on the market are refunded and parlay legs voided here
+	if s.settler != nil {
+		s.settler.SettleMarket(ctx, marketID)
+	}
 }
 
 func (s *service) processOracleResolution(_ context.Context, _ uuid.UUID) {
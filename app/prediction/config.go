@@ -12,6 +12,7 @@ type Config struct {
 	MaxBetAmount                    decimal.Decimal `env:"MAX_BET_AMOUNT"`
 	MinBetAmount                    decimal.Decimal `env:"MIN_BET_AMOUNT"`
 	MaxSlippagePercentage           decimal.Decimal `env:"MAX_SLIPPAGE_PERCENTAGE"`
+	QuoteTolerancePercentage        decimal.Decimal `env:"QUOTE_TOLERANCE_PERCENTAGE"`
 	MaxPositionPerUser              decimal.Decimal `env:"MAX_POSITION_PER_USER"`
 	MaxPositionPerMarket            decimal.Decimal `env:"MAX_POSITION_PER_MARKET"`
 	BetTimeoutSeconds               int             `env:"BET_TIMEOUT_SECONDS"`
@@ -27,6 +28,23 @@ type Config struct {
 	HighPriceImpactThreshold        decimal.Decimal `env:"HIGH_PRICE_IMPACT_THRESHOLD"`
 	MaxBetsForStatsCalculation      int             `env:"MAX_BETS_FOR_STATS_CALCULATION"`
 	BetCancellationWindow           time.Duration   `env:"BET_CANCELLATION_WINDOW"`
+	MaxParlayLegs                   int             `env:"MAX_PARLAY_LEGS"`
+	MaxParlayPayout                 decimal.Decimal `env:"MAX_PARLAY_PAYOUT"`
+	ParlayReconcileInterval         time.Duration   `env:"PARLAY_RECONCILE_INTERVAL"`
+	PortfolioSnapshotInterval       time.Duration   `env:"PORTFOLIO_SNAPSHOT_INTERVAL"`
+	MaxPortfolioHistoryDays         int             `env:"MAX_PORTFOLIO_HISTORY_DAYS"`
+	// RiskRules applies to bets on markets in countries without their own
+	// risk settings
+	RiskRules models.RiskRuleSettings
+}
+
+// RiskRulesFor returns the risk settings for a market's country, falling back
+// to the defaults when the country has none
+func (c *Config) RiskRulesFor(country *models.Country) *models.RiskRuleSettings {
+	if settings := country.GetRiskRules(); settings != nil {
+		return settings
+	}
+	return &c.RiskRules
 }
 
 func (c *Config) Validate() error {
@@ -44,6 +62,9 @@ func (c *Config) Validate() error {
 		{c.MaxSlippagePercentage.GreaterThanOrEqual(decimal.Zero) &&
 			c.MaxSlippagePercentage.LessThanOrEqual(maxImpact),
 			models.ErrInvalidSlippageLimit},
+		{c.QuoteTolerancePercentage.GreaterThanOrEqual(decimal.Zero) &&
+			c.QuoteTolerancePercentage.LessThanOrEqual(maxImpact),
+			models.ErrInvalidQuoteTolerance},
 
 		{c.MaxPositionPerUser.GreaterThan(decimal.Zero), models.ErrInvalidPositionLimit},
 
@@ -53,6 +74,13 @@ func (c *Config) Validate() error {
 		{c.CooldownPeriod >= 0, models.ErrInvalidCooldownPeriod},
 		{c.BetCancellationWindow >= 0, models.ErrInvalidBetCancellationWindow},
 
+		{c.MaxParlayLegs >= models.MinParlayLegs && c.MaxParlayLegs <= models.MaxParlayLegs, models.ErrInvalidParlayLimits},
+		{c.MaxParlayPayout.GreaterThan(c.MaxBetAmount), models.ErrInvalidParlayLimits},
+		{c.ParlayReconcileInterval > 0, models.ErrInvalidParlayLimits},
+
+		{c.PortfolioSnapshotInterval > 0 && c.PortfolioSnapshotInterval <= 24*time.Hour, models.ErrInvalidPortfolioSettings},
+		{c.MaxPortfolioHistoryDays > 0, models.ErrInvalidPortfolioSettings},
+
 		{c.SignificantPriceImpactThreshold.GreaterThan(decimal.Zero) &&
 			c.ModeratePriceImpactThreshold.GreaterThan(decimal.Zero) &&
 			c.HighPriceImpactThreshold.GreaterThan(decimal.Zero),
@@ -68,7 +96,8 @@ func (c *Config) Validate() error {
 			return v.err
 		}
 	}
-	return nil
+
+	return c.RiskRules.Validate()
 }
 
 // GetDefaultConfig returns the default betting configuration
@@ -77,6 +106,7 @@ func GetDefaultConfig() *Config {
 		MaxBetAmount:                    decimal.NewFromInt(50000),  // ₦50,000
 		MinBetAmount:                    decimal.NewFromInt(100),    // ₦100
 		MaxSlippagePercentage:           decimal.NewFromFloat(5.0),  // 5%
+		QuoteTolerancePercentage:        decimal.NewFromFloat(1.0),  // 1%
 		MaxPositionPerUser:              decimal.NewFromInt(500000), // ₦500,000
 		MaxPositionPerMarket:            decimal.NewFromInt(100000), // ₦100,000
 		BetTimeoutSeconds:               30,                         // 30 seconds
@@ -92,5 +122,13 @@ func GetDefaultConfig() *Config {
 		HighPriceImpactThreshold:        decimal.NewFromFloat(10.0), // 10% price impact
 		MaxBetsForStatsCalculation:      1000,
 		BetCancellationWindow:           5 * time.Minute,
+		MaxParlayLegs:                   10,
+		MaxParlayPayout:                 decimal.NewFromInt(10000000), // ₦10,000,000
+		ParlayReconcileInterval:         10 * time.Minute,
+		PortfolioSnapshotInterval:       time.Hour,
+		MaxPortfolioHistoryDays:         366,
+		RiskRules: models.RiskRuleSettings{
+			ReviewScore: decimal.NewFromFloat(0.6),
+		},
 	}
 }
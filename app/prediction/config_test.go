@@ -17,6 +17,7 @@ func TestGetDefaultConfig(t *testing.T) {
 	assert.True(t, config.MaxBetAmount.Equal(decimal.NewFromInt(50000)), "Default MaxBetAmount mismatch")
 	assert.True(t, config.MinBetAmount.Equal(decimal.NewFromInt(100)), "Default MinBetAmount mismatch")
 	assert.True(t, config.MaxSlippagePercentage.Equal(decimal.NewFromFloat(5.0)), "Default MaxSlippagePercentage mismatch")
+	assert.True(t, config.QuoteTolerancePercentage.Equal(decimal.NewFromFloat(1.0)), "Default QuoteTolerancePercentage mismatch")
 	assert.Equal(t, 30, config.BetTimeoutSeconds, "Default BetTimeoutSeconds mismatch")
 	assert.True(t, config.EnableSlippageProtection, "Default EnableSlippageProtection mismatch")
 	assert.True(t, config.EnablePositionLimits, "Default EnablePositionLimits mismatch")
@@ -24,6 +25,10 @@ func TestGetDefaultConfig(t *testing.T) {
 	assert.Equal(t, 10, config.MaxBetsPerMinute, "Default MaxBetsPerMinute mismatch")
 	assert.True(t, config.MaxDailyBetAmount.Equal(decimal.NewFromInt(1000000)), "Default MaxDailyBetAmount mismatch")
 	assert.Equal(t, 5*time.Second, config.CooldownPeriod, "Default CooldownPeriod mismatch")
+	assert.Equal(t, 10, config.MaxParlayLegs, "Default MaxParlayLegs mismatch")
+	assert.True(t, config.MaxParlayPayout.Equal(decimal.NewFromInt(10000000)), "Default MaxParlayPayout mismatch")
+	assert.Equal(t, time.Hour, config.PortfolioSnapshotInterval, "Default PortfolioSnapshotInterval mismatch")
+	assert.Equal(t, 366, config.MaxPortfolioHistoryDays, "Default MaxPortfolioHistoryDays mismatch")
 
 	err := config.Validate()
 	assert.NoError(t, err, "Default configuration should be valid")
@@ -100,6 +105,20 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "Invalid QuoteTolerancePercentage (negative)",
+			modifier: func(c *Config) {
+				c.QuoteTolerancePercentage = decimal.NewFromFloat(-0.5)
+			},
+			expectedErr: models.ErrInvalidQuoteTolerance,
+		},
+		{
+			name: "Invalid QuoteTolerancePercentage (greater than 100)",
+			modifier: func(c *Config) {
+				c.QuoteTolerancePercentage = decimal.NewFromInt(101)
+			},
+			expectedErr: models.ErrInvalidQuoteTolerance,
+		},
 		{
 			name: "Invalid MaxPositionPerUser (zero)",
 			modifier: func(c *Config) {
@@ -170,6 +189,69 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "Invalid MaxParlayLegs (below two)",
+			modifier: func(c *Config) {
+				c.MaxParlayLegs = 1
+			},
+			expectedErr: models.ErrInvalidParlayLimits,
+		},
+		{
+			name: "Invalid MaxParlayLegs (above ten)",
+			modifier: func(c *Config) {
+				c.MaxParlayLegs = 11
+			},
+			expectedErr: models.ErrInvalidParlayLimits,
+		},
+		{
+			name: "Invalid MaxParlayPayout (not above MaxBetAmount)",
+			modifier: func(c *Config) {
+				c.MaxParlayPayout = c.MaxBetAmount
+			},
+			expectedErr: models.ErrInvalidParlayLimits,
+		},
+		{
+			name: "Invalid ParlayReconcileInterval (zero)",
+			modifier: func(c *Config) {
+				c.ParlayReconcileInterval = 0
+			},
+			expectedErr: models.ErrInvalidParlayLimits,
+		},
+		{
+			name: "Invalid PortfolioSnapshotInterval (zero)",
+			modifier: func(c *Config) {
+				c.PortfolioSnapshotInterval = 0
+			},
+			expectedErr: models.ErrInvalidPortfolioSettings,
+		},
+		{
+			name: "Invalid PortfolioSnapshotInterval (over a day)",
+			modifier: func(c *Config) {
+				c.PortfolioSnapshotInterval = 25 * time.Hour
+			},
+			expectedErr: models.ErrInvalidPortfolioSettings,
+		},
+		{
+			name: "Invalid MaxPortfolioHistoryDays (zero)",
+			modifier: func(c *Config) {
+				c.MaxPortfolioHistoryDays = 0
+			},
+			expectedErr: models.ErrInvalidPortfolioSettings,
+		},
+		{
+			name: "Invalid risk review score (above 1)",
+			modifier: func(c *Config) {
+				c.RiskRules.ReviewScore = decimal.NewFromFloat(1.5)
+			},
+			expectedErr: models.ErrInvalidRiskScoreThreshold,
+		},
+		{
+			name: "Invalid risk deny score (below review score)",
+			modifier: func(c *Config) {
+				c.RiskRules.DenyScore = decimal.NewFromFloat(0.4)
+			},
+			expectedErr: models.ErrInvalidRiskScoreThreshold,
+		},
 	}
 
 	for _, tt := range tests {
@@ -187,3 +269,17 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_RiskRulesFor(t *testing.T) {
+	config := GetDefaultConfig()
+	kenya := &models.Country{Code: "KEN", Config: &models.CountryConfig{
+		RiskRules: &models.RiskRuleSettings{DisabledRules: []string{"cooldown"}},
+	}}
+	nigeria := &models.Country{Code: "NGA", Config: &models.CountryConfig{}}
+
+	assert.True(t, config.RiskRulesFor(kenya).IsDisabled("cooldown"))
+	assert.True(t, config.RiskRulesFor(kenya).ReviewScore.IsZero())
+	assert.False(t, config.RiskRulesFor(nigeria).IsDisabled("cooldown"))
+	assert.True(t, config.RiskRulesFor(nigeria).ReviewScore.Equal(decimal.NewFromFloat(0.6)))
+	assert.True(t, config.RiskRulesFor(nil).ReviewScore.Equal(decimal.NewFromFloat(0.6)))
+}
@@ -1,30 +1,47 @@
 package prediction
 
 import (
+	"reflect"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/joefazee/neo/models"
 	"github.com/shopspring/decimal"
 )
 
+// newValidator returns a struct validator that compares decimal.Decimal fields
+// as numbers, so tags like gt=0 and omitempty work on amounts and prices
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if d, ok := field.Interface().(decimal.Decimal); ok {
+			f, _ := d.Float64()
+			return f
+		}
+		return nil
+	}, decimal.Decimal{})
+	return v
+}
+
 // PlaceBetRequest represents the request to place a bet
 // @Description Request payload for placing a bet on a market outcome
 type PlaceBetRequest struct {
-	MarketID       uuid.UUID       `json:"market_id" validate:"required"`
-	OutcomeID      uuid.UUID       `json:"outcome_id" validate:"required"`
-	Amount         decimal.Decimal `json:"amount" validate:"required,gt=0"`
-	MaxSlippage    decimal.Decimal `json:"max_slippage,omitempty" validate:"omitempty,gte=0,lte=100"`
-	ExpectedPrice  decimal.Decimal `json:"expected_price,omitempty" validate:"omitempty,gte=1,lte=99"`
-	TimeoutSeconds int             `json:"timeout_seconds,omitempty" validate:"omitempty,min=5,max=300"`
+	MarketID      uuid.UUID       `json:"market_id" validate:"required"`
+	OutcomeID     uuid.UUID       `json:"outcome_id" validate:"required"`
+	Amount        decimal.Decimal `json:"amount" validate:"required,gt=0"`
+	MaxSlippage   decimal.Decimal `json:"max_slippage,omitempty" validate:"omitempty,gte=0,lte=100"`
+	ExpectedPrice decimal.Decimal `json:"expected_price,omitempty" validate:"omitempty,gte=1,lte=99"`
+	QuoteID       string          `json:"quote_id,omitempty"` // Executes at the quoted price instead of ExpectedPrice
 }
 
 // BetQuoteRequest represents the request for a bet quote
 // @Description Request payload for getting a bet quote without placing the bet
 type BetQuoteRequest struct {
-	MarketID  uuid.UUID       `json:"market_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440000"`  // Market ID
-	OutcomeID uuid.UUID       `json:"outcome_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440001"` // Outcome ID
-	Amount    decimal.Decimal `json:"amount" validate:"required,gt=0" example:"1000.00"`                             // Bet amount
+	MarketID       uuid.UUID       `json:"market_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440000"`  // Market ID
+	OutcomeID      uuid.UUID       `json:"outcome_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440001"` // Outcome ID
+	Amount         decimal.Decimal `json:"amount" validate:"required,gt=0" example:"1000.00"`                             // Bet amount
+	TimeoutSeconds int             `json:"timeout_seconds,omitempty" validate:"omitempty,min=5,max=300" example:"30"`     // Seconds to honour the quote, at most the server's limit
 }
 
 // BetFilters represents filters for bet queries
@@ -80,6 +97,7 @@ type BetQuoteResponse struct {
 	MaxLoss           decimal.Decimal `json:"max_loss" example:"1000.00"`                                // Maximum possible loss
 	EstimatedSlippage decimal.Decimal `json:"estimated_slippage" example:"2.00"`                         // Estimated slippage
 	ValidUntil        time.Time       `json:"valid_until" example:"2024-01-15T10:35:00Z"`                // Quote expiry time
+	QuoteID           string          `json:"quote_id,omitempty"`                                        // Pass to place the bet at CurrentPrice until ValidUntil
 	Warnings          []string        `json:"warnings,omitempty" example:"[\"High slippage expected\"]"` // Warning messages
 }
 
@@ -114,6 +132,21 @@ type PositionResponse struct {
 	LastBetAt         time.Time       `json:"last_bet_at" example:"2024-01-15T10:30:00Z"`                // Last bet timestamp
 }
 
+// BetLimitsResponse represents the bet limits a user faces on a market
+// @Description Effective bet limits for a user on a market and the layers they come from
+type BetLimitsResponse struct {
+	UserID       uuid.UUID       `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440002"`   // User ID
+	MarketID     uuid.UUID       `json:"market_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Market ID
+	MinBet       decimal.Decimal `json:"min_bet" example:"200.00"`                                 // Smallest bet allowed
+	MinBetSource string          `json:"min_bet_source" example:"category"`                        // Layer that set the minimum
+	MaxBet       decimal.Decimal `json:"max_bet" example:"20000.00"`                               // Largest bet allowed
+	MaxBetSource string          `json:"max_bet_source" example:"country"`                         // Layer that set the maximum
+	CanBet       bool            `json:"can_bet" example:"true"`                                   // Whether any amount fits between the limits
+	KYCRequired  bool            `json:"kyc_required" example:"true"`                              // Whether the user must be KYC verified
+	ContractUnit decimal.Decimal `json:"contract_unit" example:"100.00"`                           // Payout of one winning contract
+	Layers       []BetLimitLayer `json:"layers"`                                                   // Limits asked for by each layer, broadest first
+}
+
 // BetListResponse represents paginated bet list
 // @Description Paginated list of user bets
 type BetListResponse struct {
@@ -138,6 +171,53 @@ type PortfolioResponse struct {
 	LastActivityAt    time.Time          `json:"last_activity_at" example:"2024-01-15T10:30:00Z"`        // Last betting activity
 }
 
+// PortfolioHistoryRequest selects the range and granularity of a portfolio history
+// @Description Date range (YYYY-MM-DD, inclusive) and granularity for portfolio history
+type PortfolioHistoryRequest struct {
+	From        time.Time `form:"from" time_format:"2006-01-02" example:"2024-01-01"`                  // First day, defaults to 30 days before To
+	To          time.Time `form:"to" time_format:"2006-01-02" example:"2024-01-31"`                    // Last day, defaults to today
+	Granularity string    `form:"granularity" validate:"omitempty,oneof=day week month" example:"day"` // day, week or month
+}
+
+// PortfolioHistoryPoint is the portfolio at the end of one period
+// @Description Portfolio value and P&L at the end of a day, week or month
+type PortfolioHistoryPoint struct {
+	Date                 time.Time       `json:"date" example:"2024-01-15T00:00:00Z"`     // First day of the period
+	PortfolioValue       decimal.Decimal `json:"portfolio_value" example:"5500.00"`       // Value of open bets at the period's prices
+	OpenStake            decimal.Decimal `json:"open_stake" example:"5000.00"`            // Amount staked on open bets
+	RealizedProfitLoss   decimal.Decimal `json:"realized_profit_loss" example:"1200.00"`  // P&L on settled bets to date
+	UnrealizedProfitLoss decimal.Decimal `json:"unrealized_profit_loss" example:"500.00"` // P&L on open bets
+	TotalProfitLoss      decimal.Decimal `json:"total_profit_loss" example:"1700.00"`     // Realized plus unrealized P&L
+	Staked               decimal.Decimal `json:"staked" example:"800.00"`                 // Amount staked during the period
+	ROI                  decimal.Decimal `json:"roi" example:"12.50"`                     // Realized P&L as a percentage of settled stake
+}
+
+// PortfolioBreakdown is the portfolio within one category or market
+// @Description Staking and P&L of a category or market over the history range
+type PortfolioBreakdown struct {
+	ID                   uuid.UUID       `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"` // Category or market ID
+	Name                 string          `json:"name" example:"Politics"`                           // Category name or market title
+	Staked               decimal.Decimal `json:"staked" example:"3000.00"`                          // Amount staked during the range
+	OpenStake            decimal.Decimal `json:"open_stake" example:"1000.00"`                      // Amount staked on open bets
+	OpenValue            decimal.Decimal `json:"open_value" example:"1100.00"`                      // Value of open bets
+	SettledStake         decimal.Decimal `json:"settled_stake" example:"2000.00"`                   // Amount staked on settled bets to date
+	RealizedProfitLoss   decimal.Decimal `json:"realized_profit_loss" example:"400.00"`             // P&L on settled bets to date
+	UnrealizedProfitLoss decimal.Decimal `json:"unrealized_profit_loss" example:"100.00"`           // P&L on open bets
+	ROI                  decimal.Decimal `json:"roi" example:"20.00"`                               // Realized P&L as a percentage of settled stake
+}
+
+// PortfolioHistoryResponse is a user's portfolio over time
+// @Description Portfolio time series with category and market breakdowns
+type PortfolioHistoryResponse struct {
+	UserID      uuid.UUID               `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"` // User ID
+	From        time.Time               `json:"from" example:"2024-01-01T00:00:00Z"`                    // First day charted
+	To          time.Time               `json:"to" example:"2024-01-31T00:00:00Z"`                      // Last day charted
+	Granularity string                  `json:"granularity" example:"day"`                              // day, week or month
+	Points      []PortfolioHistoryPoint `json:"points"`                                                 // One point per period with a snapshot
+	Categories  []PortfolioBreakdown    `json:"categories"`                                             // Breakdown by category
+	Markets     []PortfolioBreakdown    `json:"markets"`                                                // Breakdown by market
+}
+
 // BettingStatsResponse represents user betting statistics
 // @Description Detailed betting statistics and performance metrics
 type BettingStatsResponse struct {
@@ -218,6 +298,22 @@ func ToBetResponse(bet *models.Bet) *BetResponse {
 }
 
 // ToBetResponseList converts a slice of models.Bet to BetResponse
+// ToBetLimitsResponse converts resolved limits to a response
+func ToBetLimitsResponse(userID, marketID uuid.UUID, limits *BetLimits) *BetLimitsResponse {
+	return &BetLimitsResponse{
+		UserID:       userID,
+		MarketID:     marketID,
+		MinBet:       limits.MinBet,
+		MinBetSource: limits.MinBetSource,
+		MaxBet:       limits.MaxBet,
+		MaxBetSource: limits.MaxBetSource,
+		CanBet:       limits.CanBet(),
+		KYCRequired:  limits.KYCRequired,
+		ContractUnit: limits.ContractUnit,
+		Layers:       limits.Layers,
+	}
+}
+
 func ToBetResponseList(bets []models.Bet) []BetResponse {
 	responses := make([]BetResponse, len(bets))
 	for i := range bets {
@@ -226,3 +322,120 @@ func ToBetResponseList(bets []models.Bet) []BetResponse {
 	}
 	return responses
 }
+
+// ParlayLegRequest selects the outcome for one leg of a parlay
+// @Description One selection of a parlay
+type ParlayLegRequest struct {
+	MarketID  uuid.UUID `json:"market_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440000"`  // Market ID
+	OutcomeID uuid.UUID `json:"outcome_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440001"` // Outcome ID
+}
+
+// ParlayQuoteRequest represents the request for a parlay quote
+// @Description Request payload for pricing a parlay without placing it
+type ParlayQuoteRequest struct {
+	Stake decimal.Decimal    `json:"stake" validate:"required,gt=0" example:"1000.00"` // Stake on the whole parlay
+	Legs  []ParlayLegRequest `json:"legs" validate:"required,min=2,max=10,dive"`       // Selections, each on a different market
+}
+
+// PlaceParlayRequest represents the request to place a parlay
+// @Description Request payload for placing a parlay
+type PlaceParlayRequest struct {
+	Stake          decimal.Decimal    `json:"stake" validate:"required,gt=0" example:"1000.00"`                         // Stake on the whole parlay
+	Legs           []ParlayLegRequest `json:"legs" validate:"required,min=2,max=10,dive"`                               // Selections, each on a different market
+	ExpectedPayout decimal.Decimal    `json:"expected_payout,omitempty" validate:"omitempty,gt=0" example:"8000.00"`    // Payout from the quote
+	MaxSlippage    decimal.Decimal    `json:"max_slippage,omitempty" validate:"omitempty,gte=0,lte=100" example:"5.00"` // Tolerated payout movement in percent
+}
+
+// ParlayFilters represents filters for parlay queries
+// @Description Filters for listing user parlays
+type ParlayFilters struct {
+	Status  *models.ParlayStatus `form:"status" example:"active"`
+	Page    int                  `form:"page" example:"1"`
+	PerPage int                  `form:"per_page" example:"20"`
+}
+
+// ParlayLegResponse represents a parlay leg in API responses
+// @Description One selection of a parlay with its price and result
+type ParlayLegResponse struct {
+	MarketID     uuid.UUID       `json:"market_id" example:"550e8400-e29b-41d4-a716-446655440000"`  // Market ID
+	MarketTitle  string          `json:"market_title,omitempty" example:"Will it rain in Lagos?"`   // Market title
+	OutcomeID    uuid.UUID       `json:"outcome_id" example:"550e8400-e29b-41d4-a716-446655440001"` // Outcome ID
+	OutcomeLabel string          `json:"outcome_label,omitempty" example:"Yes"`                     // Outcome label
+	Price        decimal.Decimal `json:"price" example:"0.45"`                                      // Implied probability when placed
+	Status       string          `json:"status,omitempty" example:"pending"`                        // Leg result
+	SettledAt    *time.Time      `json:"settled_at,omitempty" example:"2024-01-20T15:00:00Z"`       // When the leg was decided
+}
+
+// ParlayQuoteResponse represents a parlay quote
+// @Description Price and payout of a potential parlay
+type ParlayQuoteResponse struct {
+	Stake           decimal.Decimal     `json:"stake" example:"1000.00"`                    // Stake
+	Legs            []ParlayLegResponse `json:"legs"`                                       // Priced selections
+	CombinedPrice   decimal.Decimal     `json:"combined_price" example:"0.125"`             // Product of the legs' prices
+	PotentialPayout decimal.Decimal     `json:"potential_payout" example:"8000.00"`         // Payout if every leg wins
+	ValidUntil      time.Time           `json:"valid_until" example:"2024-01-15T10:35:00Z"` // Quote expiry time
+}
+
+// ParlayResponse represents a parlay in API responses
+// @Description Parlay with its legs and settlement
+type ParlayResponse struct {
+	ID               uuid.UUID           `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`      // Parlay ID
+	UserID           uuid.UUID           `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440001"` // User ID
+	Stake            decimal.Decimal     `json:"stake" example:"1000.00"`                                // Stake
+	CurrencyCode     string              `json:"currency_code" example:"NGN"`                            // Wallet currency
+	CombinedPrice    decimal.Decimal     `json:"combined_price" example:"0.125"`                         // Product of the prices of legs not voided
+	PotentialPayout  decimal.Decimal     `json:"potential_payout" example:"8000.00"`                     // Payout if every remaining leg wins
+	Status           string              `json:"status" example:"active"`                                // Parlay status
+	PlacedAt         time.Time           `json:"placed_at" example:"2024-01-15T10:30:00Z"`               // When the parlay was placed
+	SettledAt        *time.Time          `json:"settled_at,omitempty" example:"2024-01-20T15:00:00Z"`    // When the parlay was settled
+	SettlementAmount *decimal.Decimal    `json:"settlement_amount,omitempty" example:"8000.00"`          // Amount paid back
+	Legs             []ParlayLegResponse `json:"legs"`                                                   // Selections
+}
+
+// ParlayListResponse represents a paginated parlay list
+// @Description Paginated list of user parlays
+type ParlayListResponse struct {
+	Parlays []ParlayResponse `json:"parlays"`  // List of parlays
+	Total   int64            `json:"total"`    // Total number of parlays
+	Page    int              `json:"page"`     // Current page
+	PerPage int              `json:"per_page"` // Items per page
+}
+
+// ToParlayLegResponse converts a models.ParlayLeg to ParlayLegResponse
+func ToParlayLegResponse(leg *models.ParlayLeg) ParlayLegResponse {
+	response := ParlayLegResponse{
+		MarketID:  leg.MarketID,
+		OutcomeID: leg.MarketOutcomeID,
+		Price:     leg.Price,
+		Status:    string(leg.Status),
+		SettledAt: leg.SettledAt,
+	}
+	if leg.Market != nil {
+		response.MarketTitle = leg.Market.Title
+	}
+	if leg.MarketOutcome != nil {
+		response.OutcomeLabel = leg.MarketOutcome.OutcomeLabel
+	}
+	return response
+}
+
+// ToParlayResponse converts a models.Parlay to ParlayResponse
+func ToParlayResponse(parlay *models.Parlay) *ParlayResponse {
+	legs := make([]ParlayLegResponse, len(parlay.Legs))
+	for i := range parlay.Legs {
+		legs[i] = ToParlayLegResponse(&parlay.Legs[i])
+	}
+	return &ParlayResponse{
+		ID:               parlay.ID,
+		UserID:           parlay.UserID,
+		Stake:            parlay.Stake,
+		CurrencyCode:     parlay.CurrencyCode,
+		CombinedPrice:    parlay.CombinedPrice,
+		PotentialPayout:  parlay.PotentialPayout,
+		Status:           string(parlay.Status),
+		PlacedAt:         parlay.CreatedAt,
+		SettledAt:        parlay.SettledAt,
+		SettlementAmount: parlay.SettlementAmount,
+		Legs:             legs,
+	}
+}
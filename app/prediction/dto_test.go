@@ -191,3 +191,22 @@ func TestToBetResponseList(t *testing.T) {
 		assert.NotNil(t, dtos[1].SettledAt)
 	})
 }
+
+func TestNewValidator_PlaceBetRequestDecimals(t *testing.T) {
+	v := newValidator()
+
+	valid := &PlaceBetRequest{
+		MarketID:  uuid.New(),
+		OutcomeID: uuid.New(),
+		Amount:    decimal.NewFromInt(25),
+	}
+	assert.NoError(t, v.Struct(valid))
+
+	zeroAmount := *valid
+	zeroAmount.Amount = decimal.Zero
+	assert.Error(t, v.Struct(&zeroAmount))
+
+	badPrice := *valid
+	badPrice.ExpectedPrice = decimal.NewFromInt(120)
+	assert.Error(t, v.Struct(&badPrice))
+}
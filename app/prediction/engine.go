@@ -57,15 +57,16 @@ func (be *bettingEngine) CalculateContractPrice(market *models.Market, outcome *
 	return price
 }
 
-// CalculateContractsBought calculates how many contracts can be bought with the bet amount
-func (be *bettingEngine) CalculateContractsBought(betAmount, price decimal.Decimal) decimal.Decimal {
-	if price.IsZero() || price.Div(decimal.NewFromInt(100)).IsZero() { // Avoid division by zero for priceDecimal
+// CalculateContractsBought calculates how many contracts can be bought with the bet amount.
+// A winning contract pays out unit, so at a price of P one contract costs unit * P / 100.
+func (be *bettingEngine) CalculateContractsBought(betAmount, price, unit decimal.Decimal) decimal.Decimal {
+	if price.IsZero() || price.Div(decimal.NewFromInt(100)).IsZero() || !unit.IsPositive() { // Avoid division by zero for priceDecimal
 		return decimal.Zero
 	}
 
-	// Contracts = bet amount / (price / 100)
+	// Contracts = bet amount / (unit * price / 100)
 	priceDecimal := price.Div(decimal.NewFromInt(100))
-	return betAmount.Div(priceDecimal)
+	return betAmount.Div(priceDecimal.Mul(unit))
 }
 
 // CalculatePriceImpact calculates how much the price will move due to a bet
@@ -160,16 +161,16 @@ func (be *bettingEngine) CalculatePotentialPayout(contracts, totalWinningContrac
 	return contracts.Div(totalWinningContracts).Mul(prizePool)
 }
 
-// CalculateBreakevenPrice calculates the price at which the bet breaks even
-// This assumes 'contracts' represents the number of shares/units that would pay out 100 currency units each if the price were 100.
-func (be *bettingEngine) CalculateBreakevenPrice(betAmount, contracts decimal.Decimal) decimal.Decimal {
-	if contracts.IsZero() {
+// CalculateBreakevenPrice calculates the price at which the bet breaks even,
+// given each contract pays out unit if it wins.
+func (be *bettingEngine) CalculateBreakevenPrice(betAmount, contracts, unit decimal.Decimal) decimal.Decimal {
+	if contracts.IsZero() || !unit.IsPositive() {
 		return decimal.Zero // Avoid division by zero
 	}
 
-	// Breakeven price = (bet amount / contracts) * 100
-	// Example: Bet 100, got 200 contracts (implies price was 50). Breakeven is (100/200)*100 = 50.
-	return betAmount.Div(contracts).Mul(decimal.NewFromInt(100))
+	// Breakeven price = bet amount / (contracts * unit) * 100
+	// Example: Bet 100 with a unit of 1, got 200 contracts (implies price was 50). Breakeven is (100/200)*100 = 50.
+	return betAmount.Div(contracts.Mul(unit)).Mul(decimal.NewFromInt(100))
 }
 
 // CalculateImpliedProbability converts price to implied probability
@@ -134,19 +134,19 @@ func TestBettingEngine_CalculateContractsBought(t *testing.T) {
 	engine := NewBettingEngine(newTestConfig())
 
 	t.Run("Normal calculation", func(t *testing.T) {
-		contracts := engine.CalculateContractsBought(decimal.NewFromInt(100), decimal.NewFromInt(50))
+		contracts := engine.CalculateContractsBought(decimal.NewFromInt(100), decimal.NewFromInt(50), decimal.NewFromInt(1))
 		assert.True(t, decimal.NewFromInt(200).Equal(contracts))
 	})
 
 	t.Run("Zero price", func(t *testing.T) {
-		contracts := engine.CalculateContractsBought(decimal.NewFromInt(100), decimal.Zero)
+		contracts := engine.CalculateContractsBought(decimal.NewFromInt(100), decimal.Zero, decimal.NewFromInt(1))
 		// Corrected engine.go: returns Zero if price or priceDecimal is zero
 		assert.True(t, decimal.Zero.Equal(contracts))
 	})
 
 	t.Run("Price leads to zero priceDecimal (e.g. price 0.001)", func(t *testing.T) {
 		smallPrice := decimal.NewFromFloat(0.001) // price/100 will be 0.00001
-		contracts := engine.CalculateContractsBought(decimal.NewFromInt(100), smallPrice)
+		contracts := engine.CalculateContractsBought(decimal.NewFromInt(100), smallPrice, decimal.NewFromInt(1))
 		expectedContracts := decimal.NewFromInt(100).Div(smallPrice.Div(decimal.NewFromInt(100)))
 		assert.True(t, expectedContracts.Equal(contracts))
 
@@ -157,7 +157,18 @@ func TestBettingEngine_CalculateContractsBought(t *testing.T) {
 	})
 
 	t.Run("Zero bet amount", func(t *testing.T) {
-		contracts := engine.CalculateContractsBought(decimal.Zero, decimal.NewFromInt(50))
+		contracts := engine.CalculateContractsBought(decimal.Zero, decimal.NewFromInt(50), decimal.NewFromInt(1))
+		assert.True(t, decimal.Zero.Equal(contracts))
+	})
+
+	t.Run("Country contract unit", func(t *testing.T) {
+		// A contract paying 100 costs 50 at a price of 50
+		contracts := engine.CalculateContractsBought(decimal.NewFromInt(1000), decimal.NewFromInt(50), decimal.NewFromInt(100))
+		assert.True(t, decimal.NewFromInt(20).Equal(contracts))
+	})
+
+	t.Run("Zero contract unit", func(t *testing.T) {
+		contracts := engine.CalculateContractsBought(decimal.NewFromInt(100), decimal.NewFromInt(50), decimal.Zero)
 		assert.True(t, decimal.Zero.Equal(contracts))
 	})
 }
@@ -359,12 +370,17 @@ func TestBettingEngine_CalculateBreakevenPrice(t *testing.T) {
 	engine := NewBettingEngine(newTestConfig())
 
 	t.Run("Normal calculation", func(t *testing.T) {
-		price := engine.CalculateBreakevenPrice(decimal.NewFromInt(100), decimal.NewFromInt(200))
+		price := engine.CalculateBreakevenPrice(decimal.NewFromInt(100), decimal.NewFromInt(200), decimal.NewFromInt(1))
+		assert.True(t, decimal.NewFromInt(50).Equal(price))
+	})
+
+	t.Run("Country contract unit", func(t *testing.T) {
+		price := engine.CalculateBreakevenPrice(decimal.NewFromInt(1000), decimal.NewFromInt(20), decimal.NewFromInt(100))
 		assert.True(t, decimal.NewFromInt(50).Equal(price))
 	})
 
 	t.Run("Zero contracts", func(t *testing.T) {
-		price := engine.CalculateBreakevenPrice(decimal.NewFromInt(100), decimal.Zero)
+		price := engine.CalculateBreakevenPrice(decimal.NewFromInt(100), decimal.Zero, decimal.NewFromInt(1))
 		// Corrected engine.go: returns Zero
 		assert.True(t, decimal.Zero.Equal(price))
 	})
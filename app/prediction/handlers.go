@@ -24,23 +24,26 @@ type Handler struct {
 func NewHandler(service Service) *Handler {
 	return &Handler{
 		service:   service,
-		validator: validator.New(),
+		validator: newValidator(),
 	}
 }
 
 // PlaceBet godoc
 // @Summary Place a bet
-// @Description Place a bet on a market outcome
+// @Description Place a bet on a market outcome. With a quote_id from /bets/quote the bet executes at the quoted price unless the quote has expired or the price has moved beyond tolerance.
 // @Tags betting
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body PlaceBetRequest true "Bet placement request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the first response"
 // @Success 201 {object} api.Response{data=BetResponse}
 // @Failure 400 {object} api.Response{error=api.ErrorInfo}
 // @Failure 401 {object} api.Response{error=api.ErrorInfo}
 // @Failure 403 {object} api.Response{error=api.ErrorInfo}
 // @Failure 429 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 422 {object} api.Response{error=api.ErrorInfo}
 // @Failure 500 {object} api.Response{error=api.ErrorInfo}
 // @Router /api/v1/bets [post]
 func (h *Handler) PlaceBet(c *gin.Context) {
@@ -63,6 +66,26 @@ func (h *Handler) PlaceBet(c *gin.Context) {
 
 	bet, err := h.service.PlaceBet(c.Request.Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, models.ErrQuoteExpired) {
+			api.ErrorResponse(c, 400, "QUOTE_EXPIRED", "The quote has expired; request a new one", nil)
+			return
+		}
+		if errors.Is(err, models.ErrQuoteUsed) {
+			api.ErrorResponse(c, 400, "QUOTE_USED", "The quote has already been used; request a new one", nil)
+			return
+		}
+		if errors.Is(err, models.ErrKYCNotVerified) {
+			api.ErrorResponse(c, 403, "KYC_REQUIRED", err.Error(), nil)
+			return
+		}
+		if errors.Is(err, models.ErrPlayerExcluded) {
+			api.ErrorResponse(c, 403, "PLAYER_EXCLUDED", err.Error(), nil)
+			return
+		}
+		if errors.Is(err, models.ErrUnauthorized) {
+			api.ForbiddenResponse(c, "Account is not allowed to place bets")
+			return
+		}
 		if h.isBettingError(err) {
 			api.ErrorResponse(c, 400, "BETTING_ERROR", err.Error(), nil)
 			return
@@ -84,7 +107,7 @@ func (h *Handler) PlaceBet(c *gin.Context) {
 
 // GetBetQuote godoc
 // @Summary Get bet quote
-// @Description Get a quote for a potential bet without placing it
+// @Description Get a quote for a potential bet without placing it. The returned quote_id guarantees the current price until valid_until.
 // @Tags betting
 // @Accept json
 // @Produce json
@@ -114,7 +137,7 @@ func (h *Handler) GetBetQuote(c *gin.Context) {
 		return
 	}
 
-	quote, err := h.service.CalculateBetQuote(c.Request.Context(), req)
+	quote, err := h.service.CalculateBetQuote(c.Request.Context(), userID, req)
 	if err != nil {
 		if errors.Is(err, models.ErrRecordNotFound) {
 			api.NotFoundResponse(c, "Market or outcome")
@@ -331,6 +354,52 @@ func (h *Handler) GetMyPortfolio(c *gin.Context) {
 	api.SuccessResponse(c, 200, "Portfolio retrieved successfully", portfolio)
 }
 
+// GetMyPortfolioHistory godoc
+// @Summary Get portfolio history
+// @Description Chart the user's portfolio value, realized and unrealized P&L, ROI and staking over time from daily snapshots, broken down by category and market
+// @Tags betting
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day (YYYY-MM-DD), defaults to 30 days before to"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to today"
+// @Param granularity query string false "Period of each point" Enums(day,week,month) default(day)
+// @Success 200 {object} api.Response{data=PortfolioHistoryResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/bets/portfolio/history [get]
+func (h *Handler) GetMyPortfolioHistory(c *gin.Context) {
+	userID := h.getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req PortfolioHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		api.BadRequestResponse(c, h.formatValidationErrors(err))
+		return
+	}
+
+	history, err := h.service.GetPortfolioHistory(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidHistoryRange) {
+			api.BadRequestResponse(c, err.Error())
+			return
+		}
+		api.InternalErrorResponse(c, "Failed to fetch portfolio history")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "Portfolio history retrieved successfully", history)
+}
+
 // GetMyStats godoc
 // @Summary Get betting statistics
 // @Description Get detailed betting statistics and performance metrics
@@ -420,18 +489,247 @@ func (h *Handler) GetPriceImpact(c *gin.Context) {
 	api.SuccessResponse(c, 200, "Price impact calculated successfully", impact)
 }
 
-// Helper methods
+// PreviewBetLimits godoc
+// @Summary Preview bet limits
+// @Description Show the bet limits a user faces on a market and which country, category, market or user layer set them
+// @Tags admin-betting
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string true "User ID"
+// @Param market_id query string true "Market ID"
+// @Success 200 {object} api.Response{data=BetLimitsResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/bets/limits [get]
+func (h *Handler) PreviewBetLimits(c *gin.Context) {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid user ID format")
+		return
+	}
+	marketID, err := uuid.Parse(c.Query("market_id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid market ID format")
+		return
+	}
 
-func (h *Handler) getUserIDFromContext(c *gin.Context) uuid.UUID {
-	// TODO: Extract user ID from JWT context
-	// For now, return a dummy UUID for testing
-	// In production, this would extract from the JWT token
-	if userIDStr, exists := c.Get("user_id"); exists {
-		if userID, ok := userIDStr.(uuid.UUID); ok {
-			return userID
+	limits, err := h.service.PreviewBetLimits(c.Request.Context(), userID, marketID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "User or market")
+			return
+		}
+		api.InternalErrorResponse(c, "Failed to resolve bet limits")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "Bet limits retrieved successfully", limits)
+}
+
+// GetParlayQuote godoc
+// @Summary Get parlay quote
+// @Description Price a parlay of 2-10 selections on different markets without placing it
+// @Tags betting
+// @Accept json
+// @Produce json
+// @Param request body ParlayQuoteRequest true "Parlay quote request"
+// @Success 200 {object} api.Response{data=ParlayQuoteResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/bets/parlays/quote [post]
+func (h *Handler) GetParlayQuote(c *gin.Context) {
+	var req ParlayQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, h.formatValidationErrors(err))
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		api.BadRequestResponse(c, h.formatValidationErrors(err))
+		return
+	}
+
+	quote, err := h.service.QuoteParlay(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Market or outcome")
+			return
+		}
+		if h.isBettingError(err) {
+			api.ErrorResponse(c, 400, "BETTING_ERROR", err.Error(), nil)
+			return
+		}
+		api.InternalErrorResponse(c, "Failed to calculate parlay quote")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "Parlay quote calculated successfully", quote)
+}
+
+// PlaceParlay godoc
+// @Summary Place a parlay
+// @Description Place one stake on 2-10 selections on different markets; it wins only if every selection wins
+// @Tags betting
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PlaceParlayRequest true "Parlay placement request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the first response"
+// @Success 201 {object} api.Response{data=ParlayResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 429 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/bets/parlays [post]
+func (h *Handler) PlaceParlay(c *gin.Context) {
+	userID := h.getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req PlaceParlayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, h.formatValidationErrors(err))
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		api.BadRequestResponse(c, h.formatValidationErrors(err))
+		return
+	}
+
+	parlay, err := h.service.PlaceParlay(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrKYCNotVerified) {
+			api.ErrorResponse(c, 403, "KYC_REQUIRED", err.Error(), nil)
+			return
+		}
+		if errors.Is(err, models.ErrPlayerExcluded) {
+			api.ErrorResponse(c, 403, "PLAYER_EXCLUDED", err.Error(), nil)
+			return
+		}
+		if errors.Is(err, models.ErrUnauthorized) {
+			api.ForbiddenResponse(c, "Account is not allowed to place bets")
+			return
+		}
+		if h.isBettingError(err) {
+			api.ErrorResponse(c, 400, "BETTING_ERROR", err.Error(), nil)
+			return
+		}
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Market or outcome")
+			return
+		}
+		if h.isRateLimitError(err) {
+			api.ErrorResponse(c, 429, "RATE_LIMIT_EXCEEDED", err.Error(), nil)
+			return
+		}
+		api.InternalErrorResponse(c, "Failed to place parlay")
+		return
+	}
+
+	api.CreatedResponse(c, "Parlay placed successfully", parlay)
+}
+
+// GetMyParlays godoc
+// @Summary Get user parlays
+// @Description Get paginated list of user's parlays
+// @Tags betting
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by parlay status" Enums(active,won,lost,refunded)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} api.Response{data=[]ParlayResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/bets/parlays [get]
+func (h *Handler) GetMyParlays(c *gin.Context) {
+	userID := h.getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var filters ParlayFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	result, err := h.service.GetUserParlays(c.Request.Context(), userID, &filters)
+	if err != nil {
+		api.InternalErrorResponse(c, "Failed to fetch parlays")
+		return
+	}
+
+	meta := api.PaginationMeta{
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		Total:      result.Total,
+		TotalPages: int((result.Total + int64(result.PerPage) - 1) / int64(result.PerPage)),
+		HasNext:    int64(result.Page*result.PerPage) < result.Total,
+		HasPrev:    result.Page > 1,
+	}
+
+	api.SuccessResponseWithMeta(c, 200, "Parlays retrieved successfully", result.Parlays, meta)
+}
+
+// GetParlayByID godoc
+// @Summary Get parlay details
+// @Description Get a parlay with the price and result of each leg
+// @Tags betting
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Parlay ID"
+// @Success 200 {object} api.Response{data=ParlayResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/bets/parlays/{id} [get]
+func (h *Handler) GetParlayByID(c *gin.Context) {
+	userID := h.getUserIDFromContext(c)
+	if userID == uuid.Nil {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	parlayID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid parlay ID format")
+		return
+	}
+
+	parlay, err := h.service.GetParlayByID(c.Request.Context(), userID, parlayID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Parlay")
+			return
 		}
+		if errors.Is(err, models.ErrForbidden) {
+			api.ForbiddenResponse(c, "Access denied to this parlay")
+			return
+		}
+		api.InternalErrorResponse(c, "Failed to fetch parlay")
+		return
 	}
-	return uuid.Nil
+
+	api.SuccessResponse(c, 200, "Parlay retrieved successfully", parlay)
+}
+
+// Helper methods
+
+func (h *Handler) getUserIDFromContext(c *gin.Context) uuid.UUID {
+	userID, _ := api.UserIDFromContext(c)
+	return userID
 }
 
 func (h *Handler) formatValidationErrors(err error) interface{} {
@@ -474,6 +772,16 @@ func (h *Handler) isBettingError(err error) bool {
 		errors.Is(err, models.ErrBetTooSmall) ||
 		errors.Is(err, models.ErrBetTooLarge) ||
 		errors.Is(err, models.ErrDailyLimitExceeded) ||
+		errors.Is(err, models.ErrStakeLimitExceeded) ||
+		errors.Is(err, models.ErrLossLimitExceeded) ||
+		errors.Is(err, models.ErrBetRefused) ||
+		errors.Is(err, models.ErrParlayLegCount) ||
+		errors.Is(err, models.ErrParlayDuplicateMarket) ||
+		errors.Is(err, models.ErrParlayCurrencyMismatch) ||
+		errors.Is(err, models.ErrParlayPayoutTooLarge) ||
+		errors.Is(err, models.ErrInvalidQuote) ||
+		errors.Is(err, models.ErrQuoteMismatch) ||
+		errors.Is(err, models.ErrQuotePriceMoved) ||
 		strings.Contains(err.Error(), "betting") ||
 		strings.Contains(err.Error(), "slippage") ||
 		strings.Contains(err.Error(), "limit")
@@ -2,7 +2,15 @@
 package prediction
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/idempotency"
+	"github.com/joefazee/neo/app/leaderboard"
+	"github.com/joefazee/neo/app/referral"
+	"github.com/joefazee/neo/app/responsible"
 	"github.com/joefazee/neo/internal/deps"
 )
 
@@ -11,6 +19,7 @@ const (
 	ServiceKey       = "prediction_service"
 	BettingEngineKey = "betting_engine"
 	RiskEngineKey    = "risk_engine"
+	ConfigKey        = "prediction_config"
 )
 
 // MountPublic mounts public prediction routes (quotes, price impact - read-only data)
@@ -19,28 +28,44 @@ func MountPublic(r *gin.RouterGroup, container *deps.Container) {
 
 	// Public betting information endpoints
 	bettingGroup := r.Group("/bets")
-	bettingGroup.POST("/quote", handler.GetBetQuote)
 	bettingGroup.GET("/markets/:market_id/outcomes/:outcome_id/price-impact", handler.GetPriceImpact)
+	bettingGroup.POST("/parlays/quote", handler.GetParlayQuote)
 }
 
 // MountAuthenticated mounts authenticated prediction routes (user betting operations)
 func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
 	handler := createHandler(container)
+	idempotent := idempotency.Require(container)
 
 	bettingGroup := r.Group("/bets")
 
 	// Core betting operations
-	bettingGroup.POST("", handler.PlaceBet)
+	bettingGroup.POST("", idempotent, handler.PlaceBet)
+	bettingGroup.POST("/quote", handler.GetBetQuote)
 	bettingGroup.GET("", handler.GetMyBets)
 	bettingGroup.GET("/:id", handler.GetBetByID)
 	bettingGroup.POST("/:id/cancel", handler.CancelBet)
 
+	// Parlays
+	bettingGroup.POST("/parlays", idempotent, handler.PlaceParlay)
+	bettingGroup.GET("/parlays", handler.GetMyParlays)
+	bettingGroup.GET("/parlays/:id", handler.GetParlayByID)
+
 	// User portfolio and statistics
 	bettingGroup.GET("/positions", handler.GetMyPositions)
 	bettingGroup.GET("/portfolio", handler.GetMyPortfolio)
+	bettingGroup.GET("/portfolio/history", handler.GetMyPortfolioHistory)
 	bettingGroup.GET("/stats", handler.GetMyStats)
 }
 
+// MountAdmin mounts betting administration routes
+func MountAdmin(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	adminGroup := r.Group("/admin/bets")
+	adminGroup.GET("/limits", api.Can(PermissionBetLimitsRead), handler.PreviewBetLimits)
+}
+
 // InitRepositories initializes and registers repositories and services for this module
 func InitRepositories(container *deps.Container) {
 	// Get or create default config
@@ -57,12 +82,26 @@ func InitRepositories(container *deps.Container) {
 	bettingEngine := NewBettingEngine(config)
 	container.RegisterService(BettingEngineKey, bettingEngine)
 
-	riskEngine := NewRiskEngine(config, repo)
+	guard, _ := container.GetService(responsible.ServiceKey).(responsible.Guard)
+	riskEngine := NewRiskEngine(config, repo, guard)
 	container.RegisterService(RiskEngineKey, riskEngine)
 
 	// Initialize service
-	service := NewService(container.DB, repo, config, bettingEngine, riskEngine)
+	monitor, _ := container.GetService(fraud.ServiceKey).(fraud.Monitor)
+	recorder, _ := container.GetService(leaderboard.ServiceKey).(leaderboard.Recorder)
+	referrals, _ := container.GetService(referral.ServiceKey).(referral.Tracker)
+	service := NewService(container.DB, repo, config, bettingEngine, riskEngine, guard, monitor, container.TokenMaker, recorder, referrals)
 	container.RegisterService(ServiceKey, service)
+	container.RegisterService(ConfigKey, config)
+}
+
+// StartJobs starts the module's background jobs. They run until ctx is done.
+func StartJobs(ctx context.Context, container *deps.Container) {
+	config := container.GetService(ConfigKey).(*Config)
+	snapshotter := container.GetService(ServiceKey).(PortfolioSnapshotter)
+	go RunPortfolioSnapshots(ctx, snapshotter, config.PortfolioSnapshotInterval)
+	reconciler := container.GetService(ServiceKey).(ParlayReconciler)
+	go RunParlayReconciliation(ctx, reconciler, config.ParlayReconcileInterval)
 }
 
 // createHandler creates a prediction handler with all dependencies
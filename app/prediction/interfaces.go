@@ -18,10 +18,13 @@ type Repository interface {
 	GetBetByID(ctx context.Context, id uuid.UUID) (*models.Bet, error)
 	GetBetsByUser(ctx context.Context, userID uuid.UUID, filters *BetFilters) ([]models.Bet, int64, error)
 	GetBetsByMarket(ctx context.Context, marketID uuid.UUID) ([]models.Bet, error)
+	GetActiveBetIDsByMarket(ctx context.Context, marketID uuid.UUID) ([]uuid.UUID, error)
+	CreateSettlement(ctx context.Context, settlement *models.Settlement) error
 	GetActiveBetsByUser(ctx context.Context, userID uuid.UUID) ([]models.Bet, error)
 	CreateBet(ctx context.Context, bet *models.Bet) error
 	UpdateBet(ctx context.Context, bet *models.Bet) error
 	UpdateTransaction(ctx context.Context, transaction *models.Transaction) error
+	IsQuoteUsed(ctx context.Context, nonce uuid.UUID) (bool, error)
 
 	// Position calculations
 	GetUserPositionInMarket(ctx context.Context, userID, marketID uuid.UUID) (decimal.Decimal, error)
@@ -34,10 +37,47 @@ type Repository interface {
 	UpdateMarketOutcome(ctx context.Context, outcome *models.MarketOutcome) error
 	UpdateMarket(ctx context.Context, market *models.Market) error
 
+	// Row locks for the bet write path. Callers must take them inside a
+	// transaction and in the order bet, market, outcome, wallet so that
+	// concurrent placements and refunds cannot deadlock.
+	LockBet(ctx context.Context, betID uuid.UUID) (*models.Bet, error)
+	LockMarket(ctx context.Context, marketID uuid.UUID) (*models.Market, error)
+	LockMarketOutcome(ctx context.Context, outcomeID uuid.UUID) (*models.MarketOutcome, error)
+	LockUserWallet(ctx context.Context, userID uuid.UUID, currencyCode string) (*models.Wallet, error)
+	// AdjustPools atomically adds delta (negative on refund) to the market and outcome pools.
+	AdjustPools(ctx context.Context, marketID, outcomeID uuid.UUID, delta decimal.Decimal) error
+
+	// User data
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
+
 	// User wallet operations
 	GetUserWallet(ctx context.Context, userID uuid.UUID, currencyCode string) (*models.Wallet, error)
 	UpdateWallet(ctx context.Context, wallet *models.Wallet) error
 	CreateTransaction(ctx context.Context, transaction *models.Transaction) error
+
+	// Parlays. Settlement locks the parlay before the wallet; placement locks
+	// the leg markets in ID order before the wallet.
+	CreateParlay(ctx context.Context, parlay *models.Parlay) error
+	UpdateParlay(ctx context.Context, parlay *models.Parlay) error
+	GetParlayByID(ctx context.Context, id uuid.UUID) (*models.Parlay, error)
+	GetParlaysByUser(ctx context.Context, userID uuid.UUID, filters *ParlayFilters) ([]models.Parlay, int64, error)
+	GetActiveParlayIDsByMarket(ctx context.Context, marketID uuid.UUID) ([]uuid.UUID, error)
+	GetDecidedMarketIDsWithActiveBets(ctx context.Context) ([]uuid.UUID, error)
+	LockParlay(ctx context.Context, id uuid.UUID) (*models.Parlay, error)
+
+	// Portfolio snapshots
+	GetPortfolioHoldings(ctx context.Context, dayStart, dayEnd time.Time) ([]PortfolioHolding, error)
+	GetMarketsWithOutcomes(ctx context.Context, marketIDs []uuid.UUID) ([]models.Market, error)
+	SavePortfolioSnapshots(ctx context.Context, snapshots []models.PortfolioSnapshot) error
+	GetPortfolioSnapshots(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.PortfolioSnapshot, error)
+	GetPortfolioRealizedTotals(ctx context.Context, dayStart, dayEnd time.Time) ([]models.PortfolioRealizedTotal, error)
+	SavePortfolioRealizedTotals(ctx context.Context, totals []models.PortfolioRealizedTotal) error
+	GetUserRealizedTotals(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.PortfolioRealizedTotal, error)
+
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+	CreateRiskDecision(ctx context.Context, decision *models.RiskDecision) error
+	// LinkRiskDecisions points the decisions at the bet or parlay they let through
+	LinkRiskDecisions(ctx context.Context, decisionIDs []uuid.UUID, betID, parlayID *uuid.UUID) error
 }
 
 // Service defines the interface for betting business logic
@@ -50,23 +90,62 @@ type Service interface {
 	GetUserPositions(ctx context.Context, userID uuid.UUID) ([]PositionResponse, error)
 
 	// Market analysis
-	CalculateBetQuote(ctx context.Context, req BetQuoteRequest) (*BetQuoteResponse, error)
+	CalculateBetQuote(ctx context.Context, userID uuid.UUID, req BetQuoteRequest) (*BetQuoteResponse, error)
 	GetMarketPriceImpact(ctx context.Context, marketID, outcomeID uuid.UUID, amount decimal.Decimal) (*PriceImpactResponse, error)
 
 	// Portfolio management
 	GetUserPortfolio(ctx context.Context, userID uuid.UUID) (*PortfolioResponse, error)
 	GetUserBettingStats(ctx context.Context, userID uuid.UUID) (*BettingStatsResponse, error)
+	GetPortfolioHistory(ctx context.Context, userID uuid.UUID, req *PortfolioHistoryRequest) (*PortfolioHistoryResponse, error)
+	PortfolioSnapshotter
+
+	// Parlays
+	QuoteParlay(ctx context.Context, req *ParlayQuoteRequest) (*ParlayQuoteResponse, error)
+	PlaceParlay(ctx context.Context, userID uuid.UUID, req *PlaceParlayRequest) (*ParlayResponse, error)
+	GetParlayByID(ctx context.Context, userID, parlayID uuid.UUID) (*ParlayResponse, error)
+	GetUserParlays(ctx context.Context, userID uuid.UUID, filters *ParlayFilters) (*ParlayListResponse, error)
+	MarketSettler
+	ParlayReconciler
+
+	// Administration
+	PreviewBetLimits(ctx context.Context, userID, marketID uuid.UUID) (*BetLimitsResponse, error)
+}
+
+// QuoteSealer seals quotes into tamper-proof IDs and opens them again.
+// security.Maker satisfies it with the token key.
+type QuoteSealer interface {
+	Seal(claims interface{}, purpose string) (string, error)
+	Open(token, purpose string, claims interface{}) error
+}
+
+// MarketSettler settles the bets that depend on a market once the market is
+// resolved or voided. It is safe to call more than once for a market.
+type MarketSettler interface {
+	SettleMarket(ctx context.Context, marketID uuid.UUID)
+}
+
+// ParlayReconciler settles bets and parlay legs on resolved or voided markets
+// that SettleMarket missed, for example because it failed or the process stopped.
+type ParlayReconciler interface {
+	ReconcileParlays(ctx context.Context) (int, error)
+}
+
+// PortfolioSnapshotter records the day's open and changed holdings and
+// realized totals so portfolio history can be charted. Snapshotting a day
+// again replaces it.
+type PortfolioSnapshotter interface {
+	SnapshotPortfolios(ctx context.Context, day time.Time) (int, error)
 }
 
 // BettingEngine defines the interface for core betting calculations
 type BettingEngine interface {
 	CalculateContractPrice(market *models.Market, outcome *models.MarketOutcome) decimal.Decimal
-	CalculateContractsBought(betAmount, price decimal.Decimal) decimal.Decimal
+	CalculateContractsBought(betAmount, price, unit decimal.Decimal) decimal.Decimal
 	CalculatePriceImpact(currentPool, betAmount decimal.Decimal) decimal.Decimal
 	CalculateSlippage(expectedPrice, actualPrice decimal.Decimal) decimal.Decimal
 	ValidateSlippage(slippage, tolerance decimal.Decimal) error
 	CalculateNewPrice(market *models.Market, outcome *models.MarketOutcome, betAmount decimal.Decimal) decimal.Decimal
-	CalculateBreakevenPrice(betAmount, contracts decimal.Decimal) decimal.Decimal
+	CalculateBreakevenPrice(betAmount, contracts, unit decimal.Decimal) decimal.Decimal
 	CalculatePotentialPayout(contracts decimal.Decimal, totalWinningContracts, prizePool decimal.Decimal) decimal.Decimal
 	EstimateGasPrice(market *models.Market, outcome *models.MarketOutcome, betAmount decimal.Decimal) decimal.Decimal
 	CalculateLiquidityScore(market *models.Market) decimal.Decimal
@@ -75,14 +154,9 @@ type BettingEngine interface {
 	CalculateOptimalBetSize(bankroll, price, trueProbability decimal.Decimal) decimal.Decimal
 }
 
-// RiskEngine defines the interface for betting risk management
+// RiskEngine runs the risk rules against a bet and records the decision
 type RiskEngine interface {
-	CheckBettingLimits(userID uuid.UUID, amount decimal.Decimal, market *models.Market) error
-	CheckPositionLimits(userID uuid.UUID, amount decimal.Decimal, market *models.Market) error
-	CheckRateLimit(userID uuid.UUID) error
-	CheckCooldown(userID uuid.UUID) error
-	ValidateMarketForBetting(market *models.Market) error
-	ValidateUserForBetting(user *models.User) error
-	CheckWalletBalance(userID uuid.UUID, amount decimal.Decimal, currencyCode string) error
-	AssessRiskScore(userID uuid.UUID, amount decimal.Decimal, market *models.Market) (decimal.Decimal, error)
+	Evaluate(ctx context.Context, bet *BetContext) (*models.RiskDecision, error)
+	AssessRiskScore(ctx context.Context, bet *BetContext) (decimal.Decimal, error)
+	ResolveBetLimits(ctx context.Context, userID uuid.UUID, market *models.Market) (*BetLimits, error)
 }
@@ -0,0 +1,120 @@
+package prediction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// Bet limit layers, from the broadest to the most specific
+const (
+	LimitSourceGlobal   = "global"
+	LimitSourceCountry  = "country"
+	LimitSourceCategory = "category"
+	LimitSourceMarket   = "market"
+	LimitSourceDaily    = "daily_limit"
+	LimitSourcePlayer   = "player_limit"
+)
+
+// BetLimitLayer is the limits one layer asks for; nil means the layer sets none
+type BetLimitLayer struct {
+	Source string           `json:"source"`
+	MinBet *decimal.Decimal `json:"min_bet,omitempty"`
+	MaxBet *decimal.Decimal `json:"max_bet,omitempty"`
+}
+
+// BetLimits are the limits in force for a bet. Each layer can only tighten
+// what the layers before it allow, so the tightest limit wins.
+type BetLimits struct {
+	MinBet       decimal.Decimal
+	MinBetSource string
+	MaxBet       decimal.Decimal
+	MaxBetSource string
+	KYCRequired  bool
+	ContractUnit decimal.Decimal
+	Layers       []BetLimitLayer
+}
+
+// CanBet checks if any amount fits between the limits
+func (l *BetLimits) CanBet() bool {
+	return l.MinBet.LessThanOrEqual(l.MaxBet)
+}
+
+// apply narrows the limits with a layer
+func (l *BetLimits) apply(source string, minBet, maxBet *decimal.Decimal) {
+	if minBet == nil && maxBet == nil {
+		return
+	}
+	l.Layers = append(l.Layers, BetLimitLayer{Source: source, MinBet: minBet, MaxBet: maxBet})
+	if minBet != nil && minBet.GreaterThan(l.MinBet) {
+		l.MinBet, l.MinBetSource = *minBet, source
+	}
+	if maxBet != nil && maxBet.LessThan(l.MaxBet) {
+		l.MaxBet, l.MaxBetSource = *maxBet, source
+	}
+}
+
+// marketLimits resolves the limits set for a market: the global config, then
+// its country, its category and the market itself. A nil market gets the
+// global limits only.
+func (re *riskEngine) marketLimits(market *models.Market) *BetLimits {
+	minBet, maxBet := re.config.MinBetAmount, re.config.MaxBetAmount
+	limits := &BetLimits{
+		MinBet:       minBet,
+		MinBetSource: LimitSourceGlobal,
+		MaxBet:       maxBet,
+		MaxBetSource: LimitSourceGlobal,
+		KYCRequired:  re.config.RequireKYCForBetting,
+		Layers:       []BetLimitLayer{{Source: LimitSourceGlobal, MinBet: &minBet, MaxBet: &maxBet}},
+	}
+	if market == nil {
+		return limits
+	}
+	limits.ContractUnit = market.GetContractUnit()
+
+	if country := market.Country; country != nil {
+		minBet, maxBet := country.GetMinBetAmount(), country.GetMaxBetAmount()
+		limits.apply(LimitSourceCountry, &minBet, &maxBet)
+		limits.KYCRequired = limits.KYCRequired || country.RequiresKYC()
+	}
+
+	if category := market.Category; category != nil {
+		limits.apply(LimitSourceCategory, category.MinBetAmount, category.MaxBetAmount)
+	}
+
+	var marketMin *decimal.Decimal
+	if market.MinBetAmount.IsPositive() {
+		marketMin = &market.MinBetAmount
+	}
+	limits.apply(LimitSourceMarket, marketMin, market.MaxBetAmount)
+
+	return limits
+}
+
+// ResolveBetLimits returns the limits a user faces on a market right now: the
+// market's limits narrowed by what is left of the user's daily betting
+// allowance and of their own stake and loss limits.
+func (re *riskEngine) ResolveBetLimits(ctx context.Context, userID uuid.UUID, market *models.Market) (*BetLimits, error) {
+	limits := re.marketLimits(market)
+
+	dailyAmount, err := re.repo.GetUserDailyBetAmount(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily bet amount: %w", err)
+	}
+	dailyLeft := decimal.Max(re.config.MaxDailyBetAmount.Sub(dailyAmount), decimal.Zero)
+	limits.apply(LimitSourceDaily, nil, &dailyLeft)
+
+	if re.guard != nil {
+		headroom, err := re.guard.StakeHeadroom(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get player limits: %w", err)
+		}
+		limits.apply(LimitSourcePlayer, nil, headroom)
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,110 @@
+package prediction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func limitsMarket() *models.Market {
+	categoryMin, categoryMax := decimal.NewFromInt(300), decimal.NewFromInt(30000)
+	marketMax := decimal.NewFromInt(40000)
+	return &models.Market{
+		ID: uuid.New(),
+		Country: &models.Country{Config: &models.CountryConfig{
+			MinBet:       decimal.NewFromInt(200),
+			MaxBet:       decimal.NewFromInt(20000),
+			ContractUnit: decimal.NewFromInt(50),
+			KYCRequired:  true,
+		}},
+		Category:     &models.Category{MinBetAmount: &categoryMin, MaxBetAmount: &categoryMax},
+		MinBetAmount: decimal.NewFromInt(150),
+		MaxBetAmount: &marketMax,
+	}
+}
+
+func TestRiskEngine_MarketLimits(t *testing.T) {
+	config := GetDefaultConfig()
+	config.RequireKYCForBetting = false
+	engine := newTestRiskEngine(config, new(MockRepository), nil)
+
+	t.Run("Tightest layer wins", func(t *testing.T) {
+		limits := engine.marketLimits(limitsMarket())
+		assert.True(t, decimal.NewFromInt(300).Equal(limits.MinBet), "min %s", limits.MinBet)
+		assert.Equal(t, LimitSourceCategory, limits.MinBetSource)
+		assert.True(t, decimal.NewFromInt(20000).Equal(limits.MaxBet), "max %s", limits.MaxBet)
+		assert.Equal(t, LimitSourceCountry, limits.MaxBetSource)
+		assert.True(t, limits.KYCRequired)
+		assert.True(t, decimal.NewFromInt(50).Equal(limits.ContractUnit))
+		assert.Len(t, limits.Layers, 4)
+	})
+
+	t.Run("Global limits without a market", func(t *testing.T) {
+		limits := engine.marketLimits(nil)
+		assert.True(t, config.MinBetAmount.Equal(limits.MinBet))
+		assert.True(t, config.MaxBetAmount.Equal(limits.MaxBet))
+		assert.Equal(t, LimitSourceGlobal, limits.MaxBetSource)
+		assert.False(t, limits.KYCRequired)
+	})
+
+	t.Run("Country limits are enforced on bets", func(t *testing.T) {
+		err := engine.checkBettingLimits(context.Background(), testBet(uuid.New(), decimal.NewFromInt(25000), limitsMarket()))
+		assert.ErrorIs(t, err, models.ErrBetTooLarge)
+
+		err = engine.checkBettingLimits(context.Background(), testBet(uuid.New(), decimal.NewFromInt(250), limitsMarket()))
+		assert.ErrorIs(t, err, models.ErrBetTooSmall)
+	})
+
+	t.Run("Country KYC requirement is enforced on users", func(t *testing.T) {
+		now := time.Now()
+		isActive := true
+		user := &models.User{IsActive: &isActive, EmailVerifiedAt: &now, KYCStatus: models.KYCStatusPending}
+		err := engine.checkUser(context.Background(), &BetContext{User: user, Market: limitsMarket()})
+		assert.ErrorIs(t, err, models.ErrKYCNotVerified)
+	})
+}
+
+func TestRiskEngine_ResolveBetLimits(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	config := GetDefaultConfig()
+
+	t.Run("Daily allowance narrows the maximum", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserDailyBetAmount", ctx, userID, mock.AnythingOfType("time.Time")).
+			Return(config.MaxDailyBetAmount.Sub(decimal.NewFromInt(5000)), nil).Once()
+
+		limits, err := newTestRiskEngine(config, repo, nil).ResolveBetLimits(ctx, userID, limitsMarket())
+		assert.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(5000).Equal(limits.MaxBet), "max %s", limits.MaxBet)
+		assert.Equal(t, LimitSourceDaily, limits.MaxBetSource)
+		assert.True(t, limits.CanBet())
+	})
+
+	t.Run("Player limits can rule out any bet", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserDailyBetAmount", ctx, userID, mock.AnythingOfType("time.Time")).Return(decimal.Zero, nil).Once()
+		headroom := decimal.NewFromInt(100)
+
+		limits, err := newTestRiskEngine(config, repo, &stubGuard{headroom: &headroom}).ResolveBetLimits(ctx, userID, limitsMarket())
+		assert.NoError(t, err)
+		assert.True(t, headroom.Equal(limits.MaxBet))
+		assert.Equal(t, LimitSourcePlayer, limits.MaxBetSource)
+		assert.False(t, limits.CanBet())
+	})
+
+	t.Run("Repo error", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserDailyBetAmount", ctx, userID, mock.AnythingOfType("time.Time")).Return(decimal.Zero, errors.New("db error")).Once()
+
+		_, err := newTestRiskEngine(config, repo, nil).ResolveBetLimits(ctx, userID, limitsMarket())
+		assert.ErrorContains(t, err, "db error")
+	})
+}
@@ -0,0 +1,501 @@
+package prediction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/app/leaderboard"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// QuoteParlay prices a parlay against the current pools without placing it.
+func (s *service) QuoteParlay(ctx context.Context, req *ParlayQuoteRequest) (*ParlayQuoteResponse, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	markets, err := s.loadParlayMarkets(ctx, req.Legs)
+	if err != nil {
+		return nil, err
+	}
+	parlay, err := s.buildParlay(req.Stake, req.Legs, markets)
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToParlayResponse(parlay)
+	return &ParlayQuoteResponse{
+		Stake:           parlay.Stake,
+		Legs:            response.Legs,
+		CombinedPrice:   parlay.CombinedPrice,
+		PotentialPayout: parlay.PotentialPayout,
+		ValidUntil:      time.Now().Add(time.Duration(s.config.BetTimeoutSeconds) * time.Second),
+	}, nil
+}
+
+// PlaceParlay takes a single stake on every leg of the parlay. Each leg's
+// market goes through the risk checks, then the parlay is repriced and the
+// stake debited in one transaction.
+func (s *service) PlaceParlay(ctx context.Context, userID uuid.UUID, req *PlaceParlayRequest) (*ParlayResponse, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	markets, err := s.loadParlayMarkets(ctx, req.Legs)
+	if err != nil {
+		return nil, err
+	}
+	quoted, err := s.buildParlay(req.Stake, req.Legs, markets)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make([]*models.RiskDecision, 0, len(req.Legs))
+	for _, leg := range req.Legs {
+		decision, err := s.runRiskChecks(ctx, userID, markets[leg.MarketID], req.Stake, quoted.CurrencyCode)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, decision)
+	}
+
+	parlay, err := s.createParlayTransaction(ctx, userID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute parlay transaction: %w", err)
+	}
+	s.linkRiskDecisions(ctx, decisions, nil, &parlay.ID)
+	return ToParlayResponse(parlay), nil
+}
+
+// createParlayTransaction prices the parlay against locked markets and debits
+// the stake atomically. The leg markets are locked in ID order, ahead of the
+// wallet, so concurrent parlays and single bets cannot deadlock. Parlay stakes
+// stay out of the market pools.
+func (s *service) createParlayTransaction(ctx context.Context,
+	userID uuid.UUID,
+	req *PlaceParlayRequest) (*models.Parlay, error) {
+	var parlay *models.Parlay
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repoTx := s.repo.WithTx(tx)
+
+		marketIDs := make([]uuid.UUID, 0, len(req.Legs))
+		for _, leg := range req.Legs {
+			marketIDs = append(marketIDs, leg.MarketID)
+		}
+		sort.Slice(marketIDs, func(i, j int) bool { return marketIDs[i].String() < marketIDs[j].String() })
+
+		markets := make(map[uuid.UUID]*models.Market, len(marketIDs))
+		for _, marketID := range marketIDs {
+			market, err := repoTx.LockMarket(ctx, marketID)
+			if err != nil {
+				return fmt.Errorf("lock market: %w", err)
+			}
+			markets[marketID] = market
+		}
+
+		var err error
+		parlay, err = s.buildParlay(req.Stake, req.Legs, markets)
+		if err != nil {
+			return err
+		}
+		if s.config.EnableSlippageProtection && !req.ExpectedPayout.IsZero() {
+			maxSlippage := req.MaxSlippage
+			if maxSlippage.IsZero() {
+				maxSlippage = s.config.MaxSlippagePercentage
+			}
+			slippage := s.bettingEngine.CalculateSlippage(req.ExpectedPayout, parlay.PotentialPayout)
+			if err := s.bettingEngine.ValidateSlippage(slippage, maxSlippage); err != nil {
+				return fmt.Errorf("slippage validation failed: %w", err)
+			}
+		}
+
+		wallet, err := repoTx.LockUserWallet(ctx, userID, parlay.CurrencyCode)
+		if err != nil {
+			return fmt.Errorf("lock user wallet: %w", err)
+		}
+		if !wallet.CanDebit(req.Stake) {
+			return models.ErrInsufficientWalletBalance
+		}
+		if err := s.checkStakeLocked(ctx, tx, userID, req.Stake); err != nil {
+			return err
+		}
+
+		ledgerTx := models.CreateParlayTransaction(userID, wallet.ID, req.Stake, wallet.Balance, uuid.Nil)
+		if err := repoTx.CreateTransaction(ctx, ledgerTx); err != nil {
+			return fmt.Errorf("create ledger transaction: %w", err)
+		}
+
+		parlay.UserID = userID
+		parlay.TransactionID = ledgerTx.ID
+		if err := repoTx.CreateParlay(ctx, parlay); err != nil {
+			return fmt.Errorf("create parlay record: %w", err)
+		}
+
+		ledgerTx.ReferenceID = &parlay.ID
+		if err := repoTx.UpdateTransaction(ctx, ledgerTx); err != nil {
+			return fmt.Errorf("update ledger transaction with parlay ID: %w", err)
+		}
+
+		oldWalletValues := wallet.AuditValues()
+		if err := wallet.Debit(req.Stake); err != nil {
+			return fmt.Errorf("in-memory wallet debit: %w", err)
+		}
+		if err := repoTx.UpdateWallet(ctx, wallet); err != nil {
+			return fmt.Errorf("update wallet record: %w", err)
+		}
+		return recordWalletChange(ctx, repoTx, models.AuditActionParlayPlaced, wallet, oldWalletValues, "parlay_id", parlay.ID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return parlay, nil
+}
+
+// checkParlayLegs enforces the number of legs and that each is on its own market
+func (s *service) checkParlayLegs(legs []ParlayLegRequest) error {
+	if len(legs) < models.MinParlayLegs || len(legs) > s.config.MaxParlayLegs {
+		return models.ErrParlayLegCount
+	}
+	seen := make(map[uuid.UUID]bool, len(legs))
+	for _, leg := range legs {
+		if seen[leg.MarketID] {
+			return models.ErrParlayDuplicateMarket
+		}
+		seen[leg.MarketID] = true
+	}
+	return nil
+}
+
+// loadParlayMarkets fetches the market of every leg, with its outcomes and country.
+func (s *service) loadParlayMarkets(ctx context.Context, legs []ParlayLegRequest) (map[uuid.UUID]*models.Market, error) {
+	if err := s.checkParlayLegs(legs); err != nil {
+		return nil, err
+	}
+
+	markets := make(map[uuid.UUID]*models.Market, len(legs))
+	for _, leg := range legs {
+		market, err := s.repo.GetMarketWithOutcomes(ctx, leg.MarketID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, models.ErrRecordNotFound
+			}
+			return nil, fmt.Errorf("get market %s: %w", leg.MarketID, err)
+		}
+		markets[leg.MarketID] = market
+	}
+	return markets, nil
+}
+
+// buildParlay prices every leg at its outcome's implied probability and
+// combines them. All markets must be open and share a currency.
+func (s *service) buildParlay(stake decimal.Decimal,
+	legs []ParlayLegRequest,
+	markets map[uuid.UUID]*models.Market) (*models.Parlay, error) {
+	if err := s.checkParlayLegs(legs); err != nil {
+		return nil, err
+	}
+
+	parlay := &models.Parlay{
+		Stake:  stake,
+		Status: models.ParlayStatusActive,
+		Legs:   make([]models.ParlayLeg, 0, len(legs)),
+	}
+	for _, leg := range legs {
+		market := markets[leg.MarketID]
+		if market == nil {
+			return nil, fmt.Errorf("market %s: %w", leg.MarketID, models.ErrRecordNotFound)
+		}
+		if !market.CanBet() {
+			return nil, models.ErrMarketNotOpenForBetting
+		}
+		if market.Country == nil {
+			return nil, errors.New("market configuration error: missing country data for currency")
+		}
+		if parlay.CurrencyCode == "" {
+			parlay.CurrencyCode = market.Country.CurrencyCode
+		} else if parlay.CurrencyCode != market.Country.CurrencyCode {
+			return nil, models.ErrParlayCurrencyMismatch
+		}
+
+		var outcome *models.MarketOutcome
+		for i := range market.Outcomes {
+			if market.Outcomes[i].ID == leg.OutcomeID {
+				outcome = &market.Outcomes[i]
+				break
+			}
+		}
+		if outcome == nil {
+			return nil, fmt.Errorf("outcome %s not found within market %s: %w", leg.OutcomeID, market.ID, models.ErrRecordNotFound)
+		}
+
+		price := s.bettingEngine.CalculateContractPrice(market, outcome)
+		parlay.Legs = append(parlay.Legs, models.ParlayLeg{
+			MarketID:        market.ID,
+			MarketOutcomeID: outcome.ID,
+			Price:           s.bettingEngine.CalculateImpliedProbability(price).Round(6),
+			Status:          models.ParlayLegStatusPending,
+			Market:          market,
+			MarketOutcome:   outcome,
+		})
+	}
+
+	parlay.Reprice()
+	if parlay.PotentialPayout.GreaterThan(s.config.MaxParlayPayout) {
+		return nil, models.ErrParlayPayoutTooLarge
+	}
+	return parlay, nil
+}
+
+// GetParlayByID returns a specific parlay, ensuring ownership.
+func (s *service) GetParlayByID(ctx context.Context, userID, parlayID uuid.UUID) (*ParlayResponse, error) {
+	parlay, err := s.repo.GetParlayByID(ctx, parlayID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get parlay: %w", err)
+	}
+	if parlay.UserID != userID {
+		return nil, models.ErrForbidden
+	}
+	return ToParlayResponse(parlay), nil
+}
+
+// GetUserParlays returns a user's parlays, newest first.
+func (s *service) GetUserParlays(ctx context.Context, userID uuid.UUID, filters *ParlayFilters) (*ParlayListResponse, error) {
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.PerPage <= 0 || filters.PerPage > 100 {
+		filters.PerPage = 20
+	}
+
+	parlays, total, err := s.repo.GetParlaysByUser(ctx, userID, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user parlays: %w", err)
+	}
+
+	responses := make([]ParlayResponse, len(parlays))
+	for i := range parlays {
+		responses[i] = *ToParlayResponse(&parlays[i])
+	}
+	return &ParlayListResponse{
+		Parlays: responses,
+		Total:   total,
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+	}, nil
+}
+
+// SettleMarket settles the market's active bets and records its result on
+// every active parlay with a leg on it, paying out or refunding the bets and
+// parlays that result decides.
+func (s *service) SettleMarket(ctx context.Context, marketID uuid.UUID) {
+	if err := s.settleMarket(ctx, marketID); err != nil {
+		log.Printf("Warning: Failed to settle market %s: %v", marketID, err)
+	}
+}
+
+// RunParlayReconciliation reconciles parlays straight away and then every
+// interval until ctx is done.
+func RunParlayReconciliation(ctx context.Context, reconciler ParlayReconciler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := reconciler.ReconcileParlays(ctx); err != nil {
+			log.Printf("Warning: Failed to reconcile parlays: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReconcileParlays settles the active bets and parlay legs on markets that
+// have already been resolved or voided, and returns how many markets it
+// settled. One market failing does not hold up the rest.
+func (s *service) ReconcileParlays(ctx context.Context) (int, error) {
+	marketIDs, err := s.repo.GetDecidedMarketIDsWithActiveBets(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get markets awaiting settlement: %w", err)
+	}
+
+	settled := 0
+	for _, marketID := range marketIDs {
+		if err := s.settleMarket(ctx, marketID); err != nil {
+			log.Printf("Warning: Failed to reconcile parlays on market %s: %v", marketID, err)
+			continue
+		}
+		settled++
+	}
+	return settled, nil
+}
+
+// settleMarket settles the bets and parlay legs on a decided market. Nothing
+// is settled while the market is undecided.
+func (s *service) settleMarket(ctx context.Context, marketID uuid.UUID) error {
+	market, err := s.repo.GetMarketWithOutcomes(ctx, marketID)
+	if err != nil {
+		return fmt.Errorf("get market: %w", err)
+	}
+	if !market.IsResolved() && !market.IsVoided() {
+		return nil
+	}
+
+	// Payouts and refunds are the system's doing, not that of the admin who resolved the market
+	systemCtx := audit.WithActor(ctx, audit.Actor{})
+	if err := s.settleMarketBets(systemCtx, market); err != nil {
+		return err
+	}
+	return s.settleParlayLegs(systemCtx, market)
+}
+
+func (s *service) settleParlayLegs(ctx context.Context, market *models.Market) error {
+	parlayIDs, err := s.repo.GetActiveParlayIDsByMarket(ctx, market.ID)
+	if err != nil {
+		return fmt.Errorf("get parlays: %w", err)
+	}
+
+	for _, parlayID := range parlayIDs {
+		// One parlay failing must not hold up the rest
+		if err := s.settleParlayLeg(ctx, parlayID, market); err != nil {
+			log.Printf("Warning: Failed to settle parlay %s on market %s: %v", parlayID, market.ID, err)
+		}
+	}
+	return nil
+}
+
+// settleParlayLeg records the market's result on one parlay and, if that
+// decides the parlay, credits what it owes. The parlay is locked ahead of
+// the wallet so a parlay is only ever paid once.
+func (s *service) settleParlayLeg(ctx context.Context, parlayID uuid.UUID, market *models.Market) error {
+	var settled *models.Parlay
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repoTx := s.repo.WithTx(tx)
+
+		parlay, err := repoTx.LockParlay(ctx, parlayID)
+		if err != nil {
+			return fmt.Errorf("lock parlay: %w", err)
+		}
+		if !parlay.IsActive() {
+			return nil
+		}
+		leg := parlay.LegOn(market.ID)
+		if leg == nil {
+			return nil
+		}
+		result, ok := parlayLegResult(market, leg.MarketOutcomeID)
+		if !ok || !parlay.SettleLeg(market.ID, result) {
+			return nil
+		}
+
+		decided := parlay.Settle()
+		if decided && parlay.SettlementAmount.IsPositive() {
+			if err := s.creditParlay(ctx, repoTx, parlay); err != nil {
+				return err
+			}
+		}
+		if err := repoTx.UpdateParlay(ctx, parlay); err != nil {
+			return fmt.Errorf("update parlay: %w", err)
+		}
+		if decided {
+			settled = parlay
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if settled != nil {
+		s.recordParlayResult(ctx, settled, market)
+	}
+	return nil
+}
+
+// recordParlayResult reports a won or lost parlay. A referred user's first
+// one reaches the settled-bet referral milestone, and it goes on the
+// leaderboards of the country of the market that decided it. A parlay can
+// span categories, so it counts toward the country-wide boards only.
+func (s *service) recordParlayResult(ctx context.Context, parlay *models.Parlay, market *models.Market) {
+	if parlay.Status == models.ParlayStatusRefunded {
+		return
+	}
+	if s.referrals != nil {
+		s.referrals.RecordMilestone(ctx, parlay.UserID, models.ReferralMilestoneFirstSettledBet,
+			parlay.CurrencyCode, parlay.Stake)
+	}
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.RecordResult(ctx, leaderboard.Result{
+		UserID:    parlay.UserID,
+		CountryID: market.CountryID,
+		Stake:     parlay.Stake,
+		Payout:    *parlay.SettlementAmount,
+		Won:       parlay.Status == models.ParlayStatusWon,
+		SettledAt: *parlay.SettledAt,
+	})
+}
+
+// creditParlay pays a won parlay out, or refunds one whose legs were all voided
+func (s *service) creditParlay(ctx context.Context, repoTx Repository, parlay *models.Parlay) error {
+	wallet, err := repoTx.LockUserWallet(ctx, parlay.UserID, parlay.CurrencyCode)
+	if err != nil {
+		return fmt.Errorf("lock user wallet: %w", err)
+	}
+
+	amount := *parlay.SettlementAmount
+	ledgerTx := models.CreateParlayPayoutTransaction(parlay.UserID, wallet.ID, amount, wallet.Balance, parlay.ID)
+	if parlay.Status == models.ParlayStatusRefunded {
+		ledgerTx = models.CreateParlayRefundTransaction(parlay.UserID, wallet.ID, amount, wallet.Balance, parlay.ID)
+	}
+	if err := repoTx.CreateTransaction(ctx, ledgerTx); err != nil {
+		return fmt.Errorf("create ledger transaction: %w", err)
+	}
+
+	oldWalletValues := wallet.AuditValues()
+	if err := wallet.Credit(amount); err != nil {
+		return fmt.Errorf("in-memory wallet credit: %w", err)
+	}
+	if err := repoTx.UpdateWallet(ctx, wallet); err != nil {
+		return fmt.Errorf("update wallet record: %w", err)
+	}
+	return recordWalletChange(ctx, repoTx, models.AuditActionParlaySettled, wallet, oldWalletValues, "parlay_id", parlay.ID)
+}
+
+// parlayLegResult returns what a resolved or voided market means for a leg
+// on one of its outcomes. It reports false while the market is undecided.
+func parlayLegResult(market *models.Market, outcomeID uuid.UUID) (models.ParlayLegStatus, bool) {
+	if market.IsVoided() {
+		return models.ParlayLegStatusVoid, true
+	}
+	if !market.IsResolved() {
+		return models.ParlayLegStatusPending, false
+	}
+	for i := range market.Outcomes {
+		if market.Outcomes[i].ID != outcomeID {
+			continue
+		}
+		switch {
+		case market.Outcomes[i].IsWinner():
+			return models.ParlayLegStatusWon, true
+		case market.Outcomes[i].IsLoser():
+			return models.ParlayLegStatusLost, true
+		}
+	}
+	return models.ParlayLegStatusPending, false
+}
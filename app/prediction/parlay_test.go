@@ -0,0 +1,194 @@
+package prediction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/leaderboard"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// parlayMarket returns an open two-outcome market whose first outcome holds
+// share percent of the pool
+func parlayMarket(currency string, share int64) *models.Market {
+	marketID := uuid.New()
+	return &models.Market{
+		ID:              marketID,
+		Status:          models.MarketStatusOpen,
+		CloseTime:       time.Now().Add(time.Hour),
+		TotalPoolAmount: decimal.NewFromInt(100),
+		Country:         &models.Country{CurrencyCode: currency},
+		Outcomes: []models.MarketOutcome{
+			{ID: uuid.New(), MarketID: marketID, PoolAmount: decimal.NewFromInt(share)},
+			{ID: uuid.New(), MarketID: marketID, PoolAmount: decimal.NewFromInt(100 - share)},
+		},
+	}
+}
+
+func newParlayTestService(repo Repository) *service {
+	config := GetDefaultConfig()
+	return NewService(nil, repo, config, NewBettingEngine(config), allowAllRiskEngine{}, nil, nil, nil, nil, nil).(*service)
+}
+
+func TestService_BuildParlay(t *testing.T) {
+	s := newParlayTestService(new(MockRepository))
+	first, second := parlayMarket("NGN", 50), parlayMarket("NGN", 25)
+	markets := map[uuid.UUID]*models.Market{first.ID: first, second.ID: second}
+	legs := []ParlayLegRequest{
+		{MarketID: first.ID, OutcomeID: first.Outcomes[0].ID},
+		{MarketID: second.ID, OutcomeID: second.Outcomes[0].ID},
+	}
+
+	t.Run("Combines the implied probabilities", func(t *testing.T) {
+		parlay, err := s.buildParlay(decimal.NewFromInt(100), legs, markets)
+		require.NoError(t, err)
+		assert.Equal(t, "NGN", parlay.CurrencyCode)
+		assert.True(t, decimal.NewFromFloat(0.5).Equal(parlay.Legs[0].Price), "price %s", parlay.Legs[0].Price)
+		assert.True(t, decimal.NewFromFloat(0.125).Equal(parlay.CombinedPrice), "combined %s", parlay.CombinedPrice)
+		assert.True(t, decimal.NewFromInt(800).Equal(parlay.PotentialPayout), "payout %s", parlay.PotentialPayout)
+	})
+
+	t.Run("Refuses too few legs", func(t *testing.T) {
+		_, err := s.buildParlay(decimal.NewFromInt(100), legs[:1], markets)
+		assert.ErrorIs(t, err, models.ErrParlayLegCount)
+	})
+
+	t.Run("Refuses two legs on one market", func(t *testing.T) {
+		duplicate := []ParlayLegRequest{legs[0], {MarketID: first.ID, OutcomeID: first.Outcomes[1].ID}}
+		_, err := s.buildParlay(decimal.NewFromInt(100), duplicate, markets)
+		assert.ErrorIs(t, err, models.ErrParlayDuplicateMarket)
+	})
+
+	t.Run("Refuses markets in different currencies", func(t *testing.T) {
+		kenyan := parlayMarket("KES", 50)
+		mixed := map[uuid.UUID]*models.Market{first.ID: first, kenyan.ID: kenyan}
+		_, err := s.buildParlay(decimal.NewFromInt(100), []ParlayLegRequest{
+			legs[0], {MarketID: kenyan.ID, OutcomeID: kenyan.Outcomes[0].ID},
+		}, mixed)
+		assert.ErrorIs(t, err, models.ErrParlayCurrencyMismatch)
+	})
+
+	t.Run("Refuses a closed market", func(t *testing.T) {
+		closed := parlayMarket("NGN", 50)
+		closed.Status = models.MarketStatusClosed
+		_, err := s.buildParlay(decimal.NewFromInt(100), []ParlayLegRequest{
+			legs[0], {MarketID: closed.ID, OutcomeID: closed.Outcomes[0].ID},
+		}, map[uuid.UUID]*models.Market{first.ID: first, closed.ID: closed})
+		assert.ErrorIs(t, err, models.ErrMarketNotOpenForBetting)
+	})
+
+	t.Run("Refuses an outcome from another market", func(t *testing.T) {
+		_, err := s.buildParlay(decimal.NewFromInt(100), []ParlayLegRequest{
+			legs[0], {MarketID: second.ID, OutcomeID: first.Outcomes[1].ID},
+		}, markets)
+		assert.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("Refuses a payout above the maximum", func(t *testing.T) {
+		_, err := s.buildParlay(decimal.NewFromInt(2000000), legs, markets)
+		assert.ErrorIs(t, err, models.ErrParlayPayoutTooLarge)
+	})
+}
+
+func TestParlayLegResult(t *testing.T) {
+	market := parlayMarket("NGN", 50)
+	winner, loser := market.Outcomes[0].ID, market.Outcomes[1].ID
+
+	_, ok := parlayLegResult(market, winner)
+	assert.False(t, ok, "an open market decides nothing")
+
+	resolvedAt := time.Now()
+	market.Status = models.MarketStatusResolved
+	market.ResolvedAt = &resolvedAt
+	market.Outcomes[0].SetAsWinner()
+	market.Outcomes[1].SetAsLoser()
+
+	result, ok := parlayLegResult(market, winner)
+	assert.True(t, ok)
+	assert.Equal(t, models.ParlayLegStatusWon, result)
+	result, _ = parlayLegResult(market, loser)
+	assert.Equal(t, models.ParlayLegStatusLost, result)
+
+	market.Status = models.MarketStatusVoided
+	result, _ = parlayLegResult(market, winner)
+	assert.Equal(t, models.ParlayLegStatusVoid, result)
+}
+
+func TestService_SettleMarket_SkipsUndecidedMarket(t *testing.T) {
+	repo := new(MockRepository)
+	market := parlayMarket("NGN", 50)
+	repo.On("GetMarketWithOutcomes", mock.Anything, market.ID).Return(market, nil)
+
+	newParlayTestService(repo).SettleMarket(context.Background(), market.ID)
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "GetActiveParlayIDsByMarket", mock.Anything, mock.Anything)
+}
+
+func TestService_ReconcileParlays(t *testing.T) {
+	repo := new(MockRepository)
+	undecided := parlayMarket("NGN", 50)
+	missing := uuid.New()
+	repo.On("GetDecidedMarketIDsWithActiveBets", mock.Anything).Return([]uuid.UUID{undecided.ID, missing}, nil)
+	repo.On("GetMarketWithOutcomes", mock.Anything, undecided.ID).Return(undecided, nil)
+	repo.On("GetMarketWithOutcomes", mock.Anything, missing).Return(nil, models.ErrRecordNotFound)
+
+	settled, err := newParlayTestService(repo).ReconcileParlays(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, settled, "a market that fails to settle is skipped")
+	repo.AssertExpectations(t)
+}
+
+type recordedResults []leaderboard.Result
+
+func (r *recordedResults) RecordResult(_ context.Context, result leaderboard.Result) {
+	*r = append(*r, result)
+}
+
+type recordedMilestones []models.ReferralMilestone
+
+func (r *recordedMilestones) RecordMilestone(_ context.Context,
+	_ uuid.UUID,
+	milestone models.ReferralMilestone,
+	_ string,
+	_ decimal.Decimal) {
+	*r = append(*r, milestone)
+}
+
+func TestService_RecordParlayResult(t *testing.T) {
+	recorder := &recordedResults{}
+	milestones := &recordedMilestones{}
+	s := newParlayTestService(new(MockRepository))
+	s.recorder = recorder
+	s.referrals = milestones
+	market := parlayMarket("NGN", 50)
+	market.CountryID = uuid.New()
+
+	settle := func(status models.ParlayStatus, amount int64) *models.Parlay {
+		settledAt, settlement := time.Now(), decimal.NewFromInt(amount)
+		return &models.Parlay{
+			UserID: uuid.New(), Stake: decimal.NewFromInt(100), Status: status,
+			SettledAt: &settledAt, SettlementAmount: &settlement,
+		}
+	}
+
+	s.recordParlayResult(context.Background(), settle(models.ParlayStatusWon, 800), market)
+	s.recordParlayResult(context.Background(), settle(models.ParlayStatusLost, 0), market)
+	s.recordParlayResult(context.Background(), settle(models.ParlayStatusRefunded, 100), market)
+
+	require.Len(t, *recorder, 2, "a refund is not a result")
+	won := (*recorder)[0]
+	assert.True(t, won.Won)
+	assert.Equal(t, market.CountryID, won.CountryID)
+	assert.Equal(t, uuid.Nil, won.CategoryID, "parlays count toward country-wide boards only")
+	assert.True(t, decimal.NewFromInt(800).Equal(won.Payout))
+	assert.False(t, (*recorder)[1].Won)
+	assert.Equal(t, recordedMilestones{models.ReferralMilestoneFirstSettledBet, models.ReferralMilestoneFirstSettledBet},
+		*milestones, "a refunded parlay was not settled")
+}
@@ -0,0 +1,8 @@
+package prediction
+
+import "github.com/joefazee/neo/app/api"
+
+// Permissions required by the admin routes of this module.
+var (
+	PermissionBetLimitsRead = api.DefinePermission("admin:bets:limits:read", "Preview the bet limits a user faces on a market")
+)
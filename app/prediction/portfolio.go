@@ -0,0 +1,371 @@
+package prediction
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// Portfolio history granularities
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// defaultPortfolioHistoryDays is the range charted when the request gives no start
+const defaultPortfolioHistoryDays = 30
+
+// PortfolioHolding is a user's bets on one outcome, totalled for a snapshot
+type PortfolioHolding struct {
+	UserID             uuid.UUID
+	MarketID           uuid.UUID
+	OutcomeID          uuid.UUID
+	CategoryID         uuid.UUID
+	OpenStake          decimal.Decimal
+	OpenContracts      decimal.Decimal
+	SettledStake       decimal.Decimal
+	RealizedProfitLoss decimal.Decimal
+	DailyStake         decimal.Decimal
+}
+
+// RunPortfolioSnapshots snapshots the day's portfolios straight away and then
+// every interval until ctx is done. Each run replaces the day's earlier one,
+// so the last run before midnight is the day's closing snapshot.
+func RunPortfolioSnapshots(ctx context.Context, snapshotter PortfolioSnapshotter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := snapshotter.SnapshotPortfolios(ctx, time.Now()); err != nil {
+			log.Printf("Warning: Failed to snapshot portfolios: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SnapshotPortfolios records users' holdings as they stand now under day,
+// and returns how many market holdings were recorded. Only markets a user
+// holds open or bet on or settled that day are recorded, with open bets valued
+// at the current contract prices. Realized totals are recorded per category
+// for the users that had bets settle that day.
+func (s *service) SnapshotPortfolios(ctx context.Context, day time.Time) (int, error) {
+	dayStart := startOfDay(day)
+	holdings, err := s.repo.GetPortfolioHoldings(ctx, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, fmt.Errorf("get portfolio holdings: %w", err)
+	}
+
+	var openMarketIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for i := range holdings {
+		if holdings[i].OpenContracts.IsPositive() && !seen[holdings[i].MarketID] {
+			seen[holdings[i].MarketID] = true
+			openMarketIDs = append(openMarketIDs, holdings[i].MarketID)
+		}
+	}
+	markets, err := s.repo.GetMarketsWithOutcomes(ctx, openMarketIDs)
+	if err != nil {
+		return 0, fmt.Errorf("get markets for portfolio snapshot: %w", err)
+	}
+	marketsByID := make(map[uuid.UUID]*models.Market, len(markets))
+	for i := range markets {
+		marketsByID[markets[i].ID] = &markets[i]
+	}
+
+	snapshots := s.buildPortfolioSnapshots(dayStart, holdings, marketsByID)
+	if err := s.repo.SavePortfolioSnapshots(ctx, snapshots); err != nil {
+		return 0, fmt.Errorf("save portfolio snapshots: %w", err)
+	}
+
+	totals, err := s.repo.GetPortfolioRealizedTotals(ctx, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, fmt.Errorf("get portfolio realized totals: %w", err)
+	}
+	if err := s.repo.SavePortfolioRealizedTotals(ctx, totals); err != nil {
+		return 0, fmt.Errorf("save portfolio realized totals: %w", err)
+	}
+	return len(snapshots), nil
+}
+
+// buildPortfolioSnapshots folds per-outcome holdings into one snapshot per
+// user and market. Holdings with nothing staked, such as refunded bets, are dropped.
+func (s *service) buildPortfolioSnapshots(
+	day time.Time,
+	holdings []PortfolioHolding,
+	markets map[uuid.UUID]*models.Market,
+) []models.PortfolioSnapshot {
+	type key struct{ userID, marketID uuid.UUID }
+	index := make(map[key]int)
+	var snapshots []models.PortfolioSnapshot
+
+	for i := range holdings {
+		holding := &holdings[i]
+		if holding.OpenStake.IsZero() && holding.SettledStake.IsZero() && holding.DailyStake.IsZero() {
+			continue
+		}
+
+		k := key{holding.UserID, holding.MarketID}
+		pos, ok := index[k]
+		if !ok {
+			pos = len(snapshots)
+			index[k] = pos
+			snapshots = append(snapshots, models.PortfolioSnapshot{
+				UserID:       holding.UserID,
+				SnapshotDate: day,
+				MarketID:     holding.MarketID,
+				CategoryID:   holding.CategoryID,
+			})
+		}
+
+		snapshot := &snapshots[pos]
+		snapshot.OpenStake = snapshot.OpenStake.Add(holding.OpenStake)
+		snapshot.OpenValue = snapshot.OpenValue.Add(s.holdingValue(holding, markets[holding.MarketID]))
+		snapshot.SettledStake = snapshot.SettledStake.Add(holding.SettledStake)
+		snapshot.RealizedProfitLoss = snapshot.RealizedProfitLoss.Add(holding.RealizedProfitLoss)
+		snapshot.DailyStake = snapshot.DailyStake.Add(holding.DailyStake)
+	}
+	return snapshots
+}
+
+// holdingValue values a holding's open contracts at the outcome's current
+// price. Without the market the open bets are carried at cost.
+func (s *service) holdingValue(holding *PortfolioHolding, market *models.Market) decimal.Decimal {
+	if !holding.OpenContracts.IsPositive() {
+		return decimal.Zero
+	}
+	if market != nil && market.Country != nil {
+		for i := range market.Outcomes {
+			if market.Outcomes[i].ID == holding.OutcomeID {
+				price := s.bettingEngine.CalculateContractPrice(market, &market.Outcomes[i])
+				return holding.OpenContracts.
+					Mul(market.GetContractUnit()).
+					Mul(price.Div(decimal.NewFromInt(100))).
+					Round(2)
+			}
+		}
+	}
+	log.Printf("Warning: Cannot price outcome %s of market %s for portfolio snapshot", holding.OutcomeID, holding.MarketID)
+	return holding.OpenStake
+}
+
+// GetPortfolioHistory charts a user's portfolio from the daily snapshots,
+// with the range broken down by category and by market.
+func (s *service) GetPortfolioHistory(ctx context.Context,
+	userID uuid.UUID,
+	req *PortfolioHistoryRequest) (*PortfolioHistoryResponse, error) {
+	from, to, granularity, err := s.portfolioHistoryRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.repo.GetPortfolioSnapshots(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get portfolio snapshots: %w", err)
+	}
+	totals, err := s.repo.GetUserRealizedTotals(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get portfolio realized totals: %w", err)
+	}
+
+	categories, markets := portfolioBreakdowns(snapshots, totals)
+	return &PortfolioHistoryResponse{
+		UserID:      userID,
+		From:        from,
+		To:          to,
+		Granularity: granularity,
+		Points:      portfolioHistoryPoints(snapshots, totals, granularity),
+		Categories:  categories,
+		Markets:     markets,
+	}, nil
+}
+
+// portfolioHistoryRange applies the defaults to a history request: the last
+// defaultPortfolioHistoryDays up to today, by day
+func (s *service) portfolioHistoryRange(req *PortfolioHistoryRequest) (from, to time.Time, granularity string, err error) {
+	if err = s.validator.Struct(req); err != nil {
+		return from, to, "", fmt.Errorf("validation error: %w", err)
+	}
+
+	to = startOfDay(time.Now())
+	if !req.To.IsZero() {
+		to = startOfDay(req.To)
+	}
+	from = to.AddDate(0, 0, 1-defaultPortfolioHistoryDays)
+	if !req.From.IsZero() {
+		from = startOfDay(req.From)
+	}
+	if to.Before(from) || to.Sub(from) >= time.Duration(s.config.MaxPortfolioHistoryDays)*24*time.Hour {
+		return from, to, "", models.ErrInvalidHistoryRange
+	}
+
+	granularity = req.Granularity
+	if granularity == "" {
+		granularity = GranularityDay
+	}
+	return from, to, granularity, nil
+}
+
+// portfolioHistoryPoints totals the snapshots per period. Holdings are taken
+// from the period's last day; staking is summed over the period. Realized
+// P&L is each category's latest total up to that day.
+func portfolioHistoryPoints(snapshots []models.PortfolioSnapshot,
+	totals []models.PortfolioRealizedTotal,
+	granularity string) []PortfolioHistoryPoint {
+	points := []PortfolioHistoryPoint{}
+	realized := make(map[uuid.UUID]*models.PortfolioRealizedTotal)
+	var (
+		current    *PortfolioHistoryPoint
+		currentDay time.Time
+		next       int
+	)
+
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		period := periodStart(snapshot.SnapshotDate, granularity)
+		if current == nil || !current.Date.Equal(period) {
+			points = append(points, PortfolioHistoryPoint{Date: period})
+			current = &points[len(points)-1]
+			currentDay = time.Time{}
+		}
+
+		// A later day in the period replaces the holdings, but keeps the staking
+		if !snapshot.SnapshotDate.Equal(currentDay) {
+			currentDay = snapshot.SnapshotDate
+			staked := current.Staked
+			*current = PortfolioHistoryPoint{Date: period, Staked: staked}
+
+			for ; next < len(totals) && !totals[next].SnapshotDate.After(currentDay); next++ {
+				realized[totals[next].CategoryID] = &totals[next]
+			}
+			settledStake := decimal.Zero
+			for _, total := range realized {
+				current.RealizedProfitLoss = current.RealizedProfitLoss.Add(total.RealizedProfitLoss)
+				settledStake = settledStake.Add(total.SettledStake)
+			}
+			current.ROI = portfolioROI(current.RealizedProfitLoss, settledStake)
+		}
+
+		current.PortfolioValue = current.PortfolioValue.Add(snapshot.OpenValue)
+		current.OpenStake = current.OpenStake.Add(snapshot.OpenStake)
+		current.UnrealizedProfitLoss = current.UnrealizedProfitLoss.Add(snapshot.UnrealizedProfitLoss())
+		current.TotalProfitLoss = current.RealizedProfitLoss.Add(current.UnrealizedProfitLoss)
+		current.Staked = current.Staked.Add(snapshot.DailyStake)
+	}
+	return points
+}
+
+// portfolioBreakdowns breaks the range down by category and by market, using
+// each market's latest snapshot, each category's latest realized total and
+// the staking over the whole range. Both lists are ordered by amount staked,
+// largest first.
+func portfolioBreakdowns(snapshots []models.PortfolioSnapshot,
+	totals []models.PortfolioRealizedTotal) (categories, markets []PortfolioBreakdown) {
+	latest := make(map[uuid.UUID]*models.PortfolioSnapshot)
+	staked := make(map[uuid.UUID]decimal.Decimal)
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		latest[snapshot.MarketID] = snapshot // snapshots are oldest first
+		staked[snapshot.MarketID] = staked[snapshot.MarketID].Add(snapshot.DailyStake)
+	}
+
+	categoryIndex := make(map[uuid.UUID]int)
+	category := func(id uuid.UUID, named *models.Category) *PortfolioBreakdown {
+		pos, ok := categoryIndex[id]
+		if !ok {
+			pos = len(categories)
+			categoryIndex[id] = pos
+			breakdown := PortfolioBreakdown{ID: id}
+			if named != nil {
+				breakdown.Name = named.Name
+			}
+			categories = append(categories, breakdown)
+		}
+		return &categories[pos]
+	}
+
+	categories, markets = []PortfolioBreakdown{}, []PortfolioBreakdown{}
+	for marketID, snapshot := range latest {
+		market := PortfolioBreakdown{ID: marketID, Staked: staked[marketID]}
+		if snapshot.Market != nil {
+			market.Name = snapshot.Market.Title
+		}
+		market.addOpen(snapshot)
+		market.addRealized(snapshot.SettledStake, snapshot.RealizedProfitLoss)
+		markets = append(markets, market)
+
+		breakdown := category(snapshot.CategoryID, snapshot.Category)
+		breakdown.Staked = breakdown.Staked.Add(staked[marketID])
+		breakdown.addOpen(snapshot)
+	}
+
+	latestTotals := make(map[uuid.UUID]*models.PortfolioRealizedTotal)
+	for i := range totals {
+		latestTotals[totals[i].CategoryID] = &totals[i] // totals are oldest first
+	}
+	for categoryID, total := range latestTotals {
+		category(categoryID, total.Category).addRealized(total.SettledStake, total.RealizedProfitLoss)
+	}
+
+	for _, list := range [][]PortfolioBreakdown{categories, markets} {
+		sort.SliceStable(list, func(i, j int) bool {
+			if !list[i].Staked.Equal(list[j].Staked) {
+				return list[i].Staked.GreaterThan(list[j].Staked)
+			}
+			return list[i].ID.String() < list[j].ID.String()
+		})
+	}
+	return categories, markets
+}
+
+// addOpen folds a market's latest open holdings into the breakdown
+func (b *PortfolioBreakdown) addOpen(snapshot *models.PortfolioSnapshot) {
+	b.OpenStake = b.OpenStake.Add(snapshot.OpenStake)
+	b.OpenValue = b.OpenValue.Add(snapshot.OpenValue)
+	b.UnrealizedProfitLoss = b.UnrealizedProfitLoss.Add(snapshot.UnrealizedProfitLoss())
+}
+
+// addRealized folds settled stake and realized P&L into the breakdown
+func (b *PortfolioBreakdown) addRealized(settledStake, realized decimal.Decimal) {
+	b.SettledStake = b.SettledStake.Add(settledStake)
+	b.RealizedProfitLoss = b.RealizedProfitLoss.Add(realized)
+	b.ROI = portfolioROI(b.RealizedProfitLoss, b.SettledStake)
+}
+
+// portfolioROI is realized P&L as a percentage of the stake it was made on
+func portfolioROI(realized, settledStake decimal.Decimal) decimal.Decimal {
+	if !settledStake.IsPositive() {
+		return decimal.Zero
+	}
+	return realized.Div(settledStake).Mul(decimal.NewFromInt(100)).Round(2)
+}
+
+// startOfDay truncates t to midnight UTC, the boundary snapshots are taken on
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart returns the first day of the week (Monday) or month holding day
+func periodStart(day time.Time, granularity string) time.Time {
+	day = startOfDay(day)
+	switch granularity {
+	case GranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
@@ -0,0 +1,200 @@
+package prediction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func janDay(d int) time.Time {
+	return time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestService_SnapshotPortfolios(t *testing.T) {
+	repo := new(MockRepository)
+	s := newParlayTestService(repo)
+	market := parlayMarket("NGN", 50)
+	userID, categoryID := uuid.New(), uuid.New()
+	closed := uuid.New()
+
+	repo.On("GetPortfolioHoldings", mock.Anything, janDay(15), janDay(16)).Return([]PortfolioHolding{
+		{
+			UserID: userID, MarketID: market.ID, OutcomeID: market.Outcomes[0].ID, CategoryID: categoryID,
+			OpenStake: decimal.NewFromInt(40), OpenContracts: decimal.NewFromInt(1), DailyStake: decimal.NewFromInt(40),
+		},
+		{
+			UserID: userID, MarketID: market.ID, OutcomeID: market.Outcomes[1].ID, CategoryID: categoryID,
+			SettledStake: decimal.NewFromInt(20), RealizedProfitLoss: decimal.NewFromInt(-20),
+		},
+		{UserID: userID, MarketID: closed, OutcomeID: uuid.New(), CategoryID: categoryID},
+	}, nil)
+	repo.On("GetMarketsWithOutcomes", mock.Anything, []uuid.UUID{market.ID}).Return([]models.Market{*market}, nil)
+
+	var saved []models.PortfolioSnapshot
+	repo.On("SavePortfolioSnapshots", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { saved = args.Get(1).([]models.PortfolioSnapshot) }).
+		Return(nil)
+	totals := []models.PortfolioRealizedTotal{{
+		UserID: userID, CategoryID: categoryID, SnapshotDate: janDay(15),
+		SettledStake: decimal.NewFromInt(20), RealizedProfitLoss: decimal.NewFromInt(-20),
+	}}
+	repo.On("GetPortfolioRealizedTotals", mock.Anything, janDay(15), janDay(16)).Return(totals, nil)
+	repo.On("SavePortfolioRealizedTotals", mock.Anything, totals).Return(nil)
+
+	count, err := s.SnapshotPortfolios(context.Background(), janDay(15).Add(13*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "a market with only refunded bets is left out")
+
+	snapshot := saved[0]
+	assert.Equal(t, janDay(15), snapshot.SnapshotDate)
+	assert.Equal(t, categoryID, snapshot.CategoryID)
+	assert.True(t, decimal.NewFromInt(40).Equal(snapshot.OpenStake))
+	assert.True(t, decimal.NewFromInt(50).Equal(snapshot.OpenValue), "one contract at 50%% of a 100 unit, got %s", snapshot.OpenValue)
+	assert.True(t, decimal.NewFromInt(20).Equal(snapshot.SettledStake))
+	assert.True(t, decimal.NewFromInt(-20).Equal(snapshot.RealizedProfitLoss))
+	assert.True(t, decimal.NewFromInt(40).Equal(snapshot.DailyStake))
+	repo.AssertExpectations(t)
+}
+
+var (
+	sports   = &models.Category{ID: uuid.New(), Name: "Sports"}
+	politics = &models.Category{ID: uuid.New(), Name: "Politics"}
+	crypto   = &models.Category{ID: uuid.New(), Name: "Crypto"}
+)
+
+// portfolioSnapshots has the derby settled on the 2nd, so it is not snapshot
+// again after that
+func portfolioSnapshots() []models.PortfolioSnapshot {
+	derby := &models.Market{ID: uuid.New(), Title: "Derby"}
+	final := &models.Market{ID: uuid.New(), Title: "Final"}
+	election := &models.Market{ID: uuid.New(), Title: "Election"}
+
+	snapshot := func(d int, market *models.Market, category *models.Category, open, value, settled, realized, staked int64) models.PortfolioSnapshot {
+		return models.PortfolioSnapshot{
+			SnapshotDate: janDay(d), MarketID: market.ID, Market: market, CategoryID: category.ID, Category: category,
+			OpenStake: decimal.NewFromInt(open), OpenValue: decimal.NewFromInt(value),
+			SettledStake: decimal.NewFromInt(settled), RealizedProfitLoss: decimal.NewFromInt(realized),
+			DailyStake: decimal.NewFromInt(staked),
+		}
+	}
+	return []models.PortfolioSnapshot{
+		snapshot(1, derby, sports, 100, 120, 0, 0, 100),
+		snapshot(1, election, politics, 50, 40, 0, 0, 50),
+		snapshot(2, derby, sports, 0, 0, 100, 80, 0),
+		snapshot(2, election, politics, 50, 45, 0, 0, 0),
+		snapshot(2, final, sports, 200, 200, 0, 0, 200),
+		snapshot(8, election, politics, 80, 90, 0, 0, 30),
+		snapshot(8, final, sports, 200, 150, 0, 0, 0),
+	}
+}
+
+// portfolioRealizedTotals has crypto bets settled before the range and the
+// derby settling on the 2nd
+func portfolioRealizedTotals() []models.PortfolioRealizedTotal {
+	total := func(d int, category *models.Category, settled, realized int64) models.PortfolioRealizedTotal {
+		return models.PortfolioRealizedTotal{
+			SnapshotDate: janDay(d), CategoryID: category.ID, Category: category,
+			SettledStake: decimal.NewFromInt(settled), RealizedProfitLoss: decimal.NewFromInt(realized),
+		}
+	}
+	return []models.PortfolioRealizedTotal{
+		total(-10, crypto, 50, -10),
+		total(2, sports, 100, 80),
+	}
+}
+
+func TestPortfolioHistoryPoints(t *testing.T) {
+	t.Run("By day", func(t *testing.T) {
+		points := portfolioHistoryPoints(portfolioSnapshots(), portfolioRealizedTotals(), GranularityDay)
+		require.Len(t, points, 3)
+
+		assert.Equal(t, janDay(1), points[0].Date)
+		assert.True(t, decimal.NewFromInt(160).Equal(points[0].PortfolioValue))
+		assert.True(t, decimal.NewFromInt(10).Equal(points[0].UnrealizedProfitLoss))
+		assert.True(t, decimal.NewFromInt(150).Equal(points[0].Staked))
+		assert.True(t, decimal.NewFromInt(-10).Equal(points[0].RealizedProfitLoss), "totals from before the range carry in")
+
+		assert.True(t, decimal.NewFromInt(245).Equal(points[1].PortfolioValue))
+		assert.True(t, decimal.NewFromInt(70).Equal(points[1].RealizedProfitLoss))
+		assert.True(t, decimal.NewFromInt(65).Equal(points[1].TotalProfitLoss))
+		assert.True(t, decimal.RequireFromString("46.67").Equal(points[1].ROI), "roi %s", points[1].ROI)
+
+		assert.True(t, decimal.NewFromInt(70).Equal(points[2].RealizedProfitLoss), "realized totals carry forward")
+	})
+
+	t.Run("By week", func(t *testing.T) {
+		points := portfolioHistoryPoints(portfolioSnapshots(), portfolioRealizedTotals(), GranularityWeek)
+		require.Len(t, points, 2)
+
+		// 1 January 2024 is a Monday, so the first two days share a week
+		assert.Equal(t, janDay(1), points[0].Date)
+		assert.True(t, decimal.NewFromInt(245).Equal(points[0].PortfolioValue), "holdings come from the last day")
+		assert.True(t, decimal.NewFromInt(350).Equal(points[0].Staked), "staking is summed over the week")
+
+		assert.Equal(t, janDay(8), points[1].Date)
+		assert.True(t, decimal.NewFromInt(30).Equal(points[1].Staked))
+	})
+
+	t.Run("By month", func(t *testing.T) {
+		points := portfolioHistoryPoints(portfolioSnapshots(), portfolioRealizedTotals(), GranularityMonth)
+		require.Len(t, points, 1)
+		assert.True(t, decimal.NewFromInt(380).Equal(points[0].Staked))
+		assert.True(t, decimal.NewFromInt(240).Equal(points[0].PortfolioValue))
+	})
+
+	t.Run("No snapshots", func(t *testing.T) {
+		assert.Empty(t, portfolioHistoryPoints(nil, nil, GranularityDay))
+	})
+}
+
+func TestPortfolioBreakdowns(t *testing.T) {
+	categories, markets := portfolioBreakdowns(portfolioSnapshots(), portfolioRealizedTotals())
+
+	require.Len(t, categories, 3)
+	assert.Equal(t, "Sports", categories[0].Name)
+	assert.True(t, decimal.NewFromInt(300).Equal(categories[0].Staked))
+	assert.True(t, decimal.NewFromInt(80).Equal(categories[0].RealizedProfitLoss))
+	assert.True(t, decimal.NewFromInt(-50).Equal(categories[0].UnrealizedProfitLoss))
+	assert.True(t, decimal.NewFromInt(80).Equal(categories[0].ROI))
+	assert.Equal(t, "Politics", categories[1].Name)
+	assert.True(t, decimal.NewFromInt(90).Equal(categories[1].OpenValue))
+	assert.Equal(t, "Crypto", categories[2].Name, "a category with only settled bets is still broken down")
+	assert.True(t, decimal.NewFromInt(-10).Equal(categories[2].RealizedProfitLoss))
+
+	require.Len(t, markets, 3)
+	assert.Equal(t, []string{"Final", "Derby", "Election"},
+		[]string{markets[0].Name, markets[1].Name, markets[2].Name})
+}
+
+func TestService_PortfolioHistoryRange(t *testing.T) {
+	s := newParlayTestService(new(MockRepository))
+
+	from, to, granularity, err := s.portfolioHistoryRange(&PortfolioHistoryRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, startOfDay(time.Now()), to)
+	assert.Equal(t, to.AddDate(0, 0, -29), from)
+	assert.Equal(t, GranularityDay, granularity)
+
+	_, _, _, err = s.portfolioHistoryRange(&PortfolioHistoryRequest{From: janDay(10), To: janDay(9)})
+	assert.ErrorIs(t, err, models.ErrInvalidHistoryRange)
+
+	_, _, _, err = s.portfolioHistoryRange(&PortfolioHistoryRequest{From: janDay(1), To: janDay(1).AddDate(1, 0, 1)})
+	assert.ErrorIs(t, err, models.ErrInvalidHistoryRange)
+
+	_, _, _, err = s.portfolioHistoryRange(&PortfolioHistoryRequest{Granularity: "hour"})
+	assert.Error(t, err)
+}
+
+func TestPeriodStart(t *testing.T) {
+	sunday := time.Date(2024, time.January, 14, 18, 0, 0, 0, time.UTC)
+	assert.Equal(t, janDay(8), periodStart(sunday, GranularityWeek))
+	assert.Equal(t, janDay(1), periodStart(sunday, GranularityMonth))
+	assert.Equal(t, janDay(14), periodStart(sunday, GranularityDay))
+}
@@ -0,0 +1,88 @@
+package prediction
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// quotePurpose binds sealed quote IDs to bet quotes so no other token opens as one
+const quotePurpose = "bet_quote"
+
+// betQuote is the price a quote ID guarantees, sealed into the ID itself.
+// Only the user it was issued to can bet on it, and only once.
+type betQuote struct {
+	Nonce     uuid.UUID       `json:"nonce"`
+	UserID    uuid.UUID       `json:"user_id"`
+	MarketID  uuid.UUID       `json:"market_id"`
+	OutcomeID uuid.UUID       `json:"outcome_id"`
+	Amount    decimal.Decimal `json:"amount"`
+	Price     decimal.Decimal `json:"price"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// quoteValidity returns how long a quote is honoured. A request may ask for
+// less than the configured timeout but never more.
+func (s *service) quoteValidity(requested int) time.Duration {
+	seconds := s.config.BetTimeoutSeconds
+	if requested > 0 && requested < seconds {
+		seconds = requested
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sealQuote turns a quote into a tamper-proof quote ID. Without a sealer
+// quotes are informational and no ID is issued.
+func (s *service) sealQuote(quote *betQuote) (string, error) {
+	if s.sealer == nil {
+		return "", nil
+	}
+	id, err := s.sealer.Seal(quote, quotePurpose)
+	if err != nil {
+		return "", fmt.Errorf("seal quote: %w", err)
+	}
+	return id, nil
+}
+
+// openQuote verifies the quote ID on a user's bet request. It returns nil when
+// the request carries no quote. Whether the quote was already used is checked
+// when the bet is written.
+func (s *service) openQuote(userID uuid.UUID, req *PlaceBetRequest) (*betQuote, error) {
+	if req.QuoteID == "" {
+		return nil, nil
+	}
+	if s.sealer == nil {
+		return nil, models.ErrInvalidQuote
+	}
+
+	quote := &betQuote{}
+	if err := s.sealer.Open(req.QuoteID, quotePurpose, quote); err != nil {
+		return nil, models.ErrInvalidQuote
+	}
+	if quote.UserID != userID || quote.Nonce == uuid.Nil {
+		return nil, models.ErrInvalidQuote
+	}
+	if time.Now().After(quote.ExpiresAt) {
+		return nil, models.ErrQuoteExpired
+	}
+	if quote.MarketID != req.MarketID || quote.OutcomeID != req.OutcomeID || !quote.Amount.Equal(req.Amount) {
+		return nil, models.ErrQuoteMismatch
+	}
+	return quote, nil
+}
+
+// honourQuote returns the quoted price if the current price is still within
+// the configured tolerance of it
+func (s *service) honourQuote(quote *betQuote, currentPrice decimal.Decimal) (decimal.Decimal, error) {
+	if time.Now().After(quote.ExpiresAt) {
+		return decimal.Zero, models.ErrQuoteExpired
+	}
+	movement := s.bettingEngine.CalculateSlippage(quote.Price, currentPrice)
+	if movement.GreaterThan(s.config.QuoteTolerancePercentage) {
+		return decimal.Zero, models.ErrQuotePriceMoved
+	}
+	return quote.Price, nil
+}
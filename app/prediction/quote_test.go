@@ -0,0 +1,141 @@
+package prediction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newQuoteTestService(t *testing.T, repo Repository) *service {
+	t.Helper()
+	maker, err := security.NewPasetoMaker("12345678901234567890123456789012")
+	require.NoError(t, err)
+	config := GetDefaultConfig()
+	return NewService(nil, repo, config, NewBettingEngine(config), allowAllRiskEngine{}, nil, nil, maker, nil, nil).(*service)
+}
+
+func TestService_CalculateBetQuote_IssuesQuoteID(t *testing.T) {
+	repo := new(MockRepository)
+	s := newQuoteTestService(t, repo)
+	market := parlayMarket("NGN", 50)
+	repo.On("GetMarketWithOutcomes", mock.Anything, market.ID).Return(market, nil)
+
+	userID := uuid.New()
+	quote, err := s.CalculateBetQuote(context.Background(), userID, BetQuoteRequest{
+		MarketID:       market.ID,
+		OutcomeID:      market.Outcomes[0].ID,
+		Amount:         decimal.NewFromInt(500),
+		TimeoutSeconds: 10,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, quote.QuoteID)
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), quote.ValidUntil, time.Second)
+
+	opened, err := s.openQuote(userID, &PlaceBetRequest{
+		MarketID:  market.ID,
+		OutcomeID: market.Outcomes[0].ID,
+		Amount:    decimal.NewFromInt(500),
+		QuoteID:   quote.QuoteID,
+	})
+	require.NoError(t, err)
+	assert.True(t, quote.CurrentPrice.Equal(opened.Price))
+	assert.NotEqual(t, uuid.Nil, opened.Nonce)
+}
+
+func TestService_QuoteValidity(t *testing.T) {
+	s := newQuoteTestService(t, new(MockRepository))
+	assert.Equal(t, 30*time.Second, s.quoteValidity(0))
+	assert.Equal(t, 10*time.Second, s.quoteValidity(10))
+	assert.Equal(t, 30*time.Second, s.quoteValidity(120), "a request cannot extend the quote")
+}
+
+func TestService_OpenQuote(t *testing.T) {
+	s := newQuoteTestService(t, new(MockRepository))
+	market := parlayMarket("NGN", 50)
+	userID := uuid.New()
+	req := func(quoteID string) *PlaceBetRequest {
+		return &PlaceBetRequest{
+			MarketID:  market.ID,
+			OutcomeID: market.Outcomes[0].ID,
+			Amount:    decimal.NewFromInt(500),
+			QuoteID:   quoteID,
+		}
+	}
+	seal := func(expiresAt time.Time) string {
+		id, err := s.sealQuote(&betQuote{
+			Nonce:     uuid.New(),
+			UserID:    userID,
+			MarketID:  market.ID,
+			OutcomeID: market.Outcomes[0].ID,
+			Amount:    decimal.NewFromInt(500),
+			Price:     decimal.NewFromInt(50),
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	t.Run("No quote", func(t *testing.T) {
+		quote, err := s.openQuote(userID, req(""))
+		assert.NoError(t, err)
+		assert.Nil(t, quote)
+	})
+
+	t.Run("Expired quote", func(t *testing.T) {
+		_, err := s.openQuote(userID, req(seal(time.Now().Add(-time.Second))))
+		assert.ErrorIs(t, err, models.ErrQuoteExpired)
+	})
+
+	t.Run("Tampered quote", func(t *testing.T) {
+		id := seal(time.Now().Add(time.Minute))
+		_, err := s.openQuote(userID, req(id[:len(id)-2]+"AA"))
+		assert.ErrorIs(t, err, models.ErrInvalidQuote)
+	})
+
+	t.Run("Different amount", func(t *testing.T) {
+		other := req(seal(time.Now().Add(time.Minute)))
+		other.Amount = decimal.NewFromInt(5000)
+		_, err := s.openQuote(userID, other)
+		assert.ErrorIs(t, err, models.ErrQuoteMismatch)
+	})
+
+	t.Run("Another user's quote", func(t *testing.T) {
+		_, err := s.openQuote(uuid.New(), req(seal(time.Now().Add(time.Minute))))
+		assert.ErrorIs(t, err, models.ErrInvalidQuote)
+	})
+
+	t.Run("Access token is not a quote", func(t *testing.T) {
+		maker, _ := security.NewPasetoMaker("12345678901234567890123456789012")
+		token, _, err := maker.CreateToken(market.ID, time.Minute, 1, security.TokenScopeAccess)
+		require.NoError(t, err)
+		_, err = s.openQuote(userID, req(token))
+		assert.ErrorIs(t, err, models.ErrInvalidQuote)
+
+		_, err = maker.VerifyToken(seal(time.Now().Add(time.Minute)))
+		assert.ErrorIs(t, err, security.ErrInvalidToken, "a quote is not an access token")
+	})
+}
+
+func TestService_HonourQuote(t *testing.T) {
+	s := newQuoteTestService(t, new(MockRepository))
+	quote := &betQuote{Price: decimal.NewFromInt(50), ExpiresAt: time.Now().Add(time.Minute)}
+
+	price, err := s.honourQuote(quote, decimal.NewFromFloat(50.4))
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(50).Equal(price), "executes at the quoted price")
+
+	_, err = s.honourQuote(quote, decimal.NewFromInt(52))
+	assert.ErrorIs(t, err, models.ErrQuotePriceMoved)
+
+	quote.ExpiresAt = time.Now().Add(-time.Second)
+	_, err = s.honourQuote(quote, decimal.NewFromInt(50))
+	assert.ErrorIs(t, err, models.ErrQuoteExpired)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/joefazee/neo/models"
 )
@@ -28,11 +29,11 @@ func (r *repository) WithTx(tx *gorm.DB) Repository {
 	return &repository{db: tx}
 }
 
-// GetBetByID returns a bet by ID with related data
+// GetBetByID returns a bet by ID with related data, including the market's country
 func (r *repository) GetBetByID(ctx context.Context, id uuid.UUID) (*models.Bet, error) {
 	var bet models.Bet
 	err := r.db.WithContext(ctx).
-		Preload("Market").
+		Preload("Market.Country").
 		Preload("MarketOutcome").
 		Preload("User").
 		Preload("Transaction").
@@ -64,7 +65,7 @@ func (r *repository) GetBetsByUser(ctx context.Context, userID uuid.UUID, filter
 	query = r.applyBetPagination(query, filters)
 
 	// Preload related data
-	query = query.Preload("Market").Preload("MarketOutcome").Preload("Transaction")
+	query = query.Preload("Market.Country").Preload("MarketOutcome").Preload("Transaction")
 
 	err := query.Find(&bets).Error
 	return bets, total, err
@@ -82,11 +83,27 @@ func (r *repository) GetBetsByMarket(ctx context.Context, marketID uuid.UUID) ([
 	return bets, err
 }
 
-// GetActiveBetsByUser returns all active bets for a user
+// GetActiveBetIDsByMarket returns the IDs of the bets on a market that are
+// still to be settled
+func (r *repository) GetActiveBetIDsByMarket(ctx context.Context, marketID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.Bet{}).
+		Where("market_id = ? AND status = ?", marketID, models.BetStatusActive).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CreateSettlement records how a bet was settled
+func (r *repository) CreateSettlement(ctx context.Context, settlement *models.Settlement) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(settlement).Error
+}
+
+// GetActiveBetsByUser returns all active bets for a user, with each market's country
 func (r *repository) GetActiveBetsByUser(ctx context.Context, userID uuid.UUID) ([]models.Bet, error) {
 	var bets []models.Bet
 	err := r.db.WithContext(ctx).
-		Preload("Market").
+		Preload("Market.Country").
 		Preload("MarketOutcome").
 		Where("user_id = ? AND status = ?", userID, models.BetStatusActive).
 		Order("created_at DESC").
@@ -117,9 +134,10 @@ func (r *repository) GetUserPositionInMarket(ctx context.Context, userID, market
 	return totalAmount, err
 }
 
-// GetUserDailyBetAmount calculates user's total bet amount for a day
+// GetUserDailyBetAmount calculates user's total bet amount for a day,
+// counting parlay stakes alongside single bets
 func (r *repository) GetUserDailyBetAmount(ctx context.Context, userID uuid.UUID, date time.Time) (decimal.Decimal, error) {
-	var totalAmount decimal.Decimal
+	var betAmount, parlayAmount decimal.Decimal
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
@@ -127,24 +145,49 @@ func (r *repository) GetUserDailyBetAmount(ctx context.Context, userID uuid.UUID
 		Model(&models.Bet{}).
 		Select("COALESCE(SUM(amount), 0)").
 		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, startOfDay, endOfDay).
-		Scan(&totalAmount).Error
-	return totalAmount, err
+		Scan(&betAmount).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&models.Parlay{}).
+		Select("COALESCE(SUM(stake), 0)").
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, startOfDay, endOfDay).
+		Scan(&parlayAmount).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return betAmount.Add(parlayAmount), nil
 }
 
-// GetUserBetCount returns the number of bets placed by user since a time
+// GetUserBetCount returns the number of bets and parlays placed by user since a time
 func (r *repository) GetUserBetCount(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
-	var count int64
+	var betCount, parlayCount int64
 	err := r.db.WithContext(ctx).
 		Model(&models.Bet{}).
 		Where("user_id = ? AND created_at >= ?", userID, since).
-		Count(&count).Error
-	return int(count), err
+		Count(&betCount).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&models.Parlay{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&parlayCount).Error
+	if err != nil {
+		return 0, err
+	}
+	return int(betCount + parlayCount), nil
 }
 
-// GetMarketWithOutcomes returns a market with its outcomes
+// GetMarketWithOutcomes returns a market with its country, category and outcomes
 func (r *repository) GetMarketWithOutcomes(ctx context.Context, marketID uuid.UUID) (*models.Market, error) {
 	var market models.Market
 	err := r.db.WithContext(ctx).
+		Preload("Country").
+		Preload("Category").
 		Preload("Outcomes").
 		Where("id = ?", marketID).
 		First(&market).Error
@@ -154,11 +197,11 @@ func (r *repository) GetMarketWithOutcomes(ctx context.Context, marketID uuid.UU
 	return &market, nil
 }
 
-// GetMarketOutcome returns a market outcome by ID
+// GetMarketOutcome returns a market outcome by ID with its market and country
 func (r *repository) GetMarketOutcome(ctx context.Context, outcomeID uuid.UUID) (*models.MarketOutcome, error) {
 	var outcome models.MarketOutcome
 	err := r.db.WithContext(ctx).
-		Preload("Market").
+		Preload("Market.Country").
 		Where("id = ?", outcomeID).
 		First(&outcome).Error
 	if err != nil {
@@ -177,6 +220,80 @@ func (r *repository) UpdateMarket(ctx context.Context, market *models.Market) er
 	return r.db.WithContext(ctx).Save(market).Error
 }
 
+// LockBet returns a bet with its market, country and outcome, holding a row lock
+// on the bet until the transaction ends
+func (r *repository) LockBet(ctx context.Context, betID uuid.UUID) (*models.Bet, error) {
+	var bet models.Bet
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Market.Country").
+		Preload("MarketOutcome").
+		Where("id = ?", betID).
+		First(&bet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bet, nil
+}
+
+// LockMarket returns a market with its country and outcomes, holding a row lock
+// on the market until the transaction ends
+func (r *repository) LockMarket(ctx context.Context, marketID uuid.UUID) (*models.Market, error) {
+	var market models.Market
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Country").
+		Preload("Outcomes").
+		Where("id = ?", marketID).
+		First(&market).Error
+	if err != nil {
+		return nil, err
+	}
+	return &market, nil
+}
+
+// LockMarketOutcome returns a market outcome, holding a row lock on it until the
+// transaction ends
+func (r *repository) LockMarketOutcome(ctx context.Context, outcomeID uuid.UUID) (*models.MarketOutcome, error) {
+	var outcome models.MarketOutcome
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", outcomeID).
+		First(&outcome).Error
+	if err != nil {
+		return nil, err
+	}
+	return &outcome, nil
+}
+
+// LockUserWallet returns user's wallet for a currency, holding a row lock on it
+// until the transaction ends
+func (r *repository) LockUserWallet(ctx context.Context, userID uuid.UUID, currencyCode string) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND currency_code = ?", userID, currencyCode).
+		First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// AdjustPools adds delta to the market's total pool and the outcome's pool in place,
+// so concurrent writers never overwrite each other's amounts
+func (r *repository) AdjustPools(ctx context.Context, marketID, outcomeID uuid.UUID, delta decimal.Decimal) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Model(&models.Market{}).
+		Where("id = ?", marketID).
+		Update("total_pool_amount", gorm.Expr("total_pool_amount + ?", delta)).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.MarketOutcome{}).
+		Where("id = ? AND market_id = ?", outcomeID, marketID).
+		Update("pool_amount", gorm.Expr("pool_amount + ?", delta)).Error
+}
+
 // GetUserWallet returns user's wallet for a currency
 func (r *repository) GetUserWallet(ctx context.Context, userID uuid.UUID, currencyCode string) (*models.Wallet, error) {
 	var wallet models.Wallet
@@ -189,6 +306,18 @@ func (r *repository) GetUserWallet(ctx context.Context, userID uuid.UUID, curren
 	return &wallet, nil
 }
 
+// GetUserByID retrieves a user with their country
+func (r *repository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).
+		Preload("Country").
+		First(&user, "id = ?", userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // UpdateWallet updates a user's wallet
 func (r *repository) UpdateWallet(ctx context.Context, wallet *models.Wallet) error {
 	return r.db.WithContext(ctx).Save(wallet).Error
@@ -199,6 +328,295 @@ func (r *repository) CreateTransaction(ctx context.Context, transaction *models.
 	return r.db.WithContext(ctx).Create(transaction).Error
 }
 
+func (r *repository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// CreateRiskDecision records the risk engine's decision on a bet
+func (r *repository) CreateRiskDecision(ctx context.Context, decision *models.RiskDecision) error {
+	return r.db.WithContext(ctx).Create(decision).Error
+}
+
+// LinkRiskDecisions points the decisions at the bet or parlay they let through
+func (r *repository) LinkRiskDecisions(ctx context.Context, decisionIDs []uuid.UUID, betID, parlayID *uuid.UUID) error {
+	if len(decisionIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.RiskDecision{}).
+		Where("id IN ?", decisionIDs).
+		Updates(map[string]interface{}{"bet_id": betID, "parlay_id": parlayID}).Error
+}
+
+// CreateParlay creates a parlay with its legs. The legs' markets and outcomes
+// are only read, never written.
+func (r *repository) CreateParlay(ctx context.Context, parlay *models.Parlay) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Omit(clause.Associations).Create(parlay).Error; err != nil {
+		return err
+	}
+	for i := range parlay.Legs {
+		parlay.Legs[i].ParlayID = parlay.ID
+		if err := db.Omit(clause.Associations).Create(&parlay.Legs[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateParlay saves a parlay and the results of its legs
+func (r *repository) UpdateParlay(ctx context.Context, parlay *models.Parlay) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Omit(clause.Associations).Save(parlay).Error; err != nil {
+		return err
+	}
+	for i := range parlay.Legs {
+		if err := db.Omit(clause.Associations).Save(&parlay.Legs[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetParlayByID returns a parlay with its legs' markets and outcomes
+func (r *repository) GetParlayByID(ctx context.Context, id uuid.UUID) (*models.Parlay, error) {
+	var parlay models.Parlay
+	err := r.db.WithContext(ctx).
+		Preload("Legs", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Legs.Market").
+		Preload("Legs.MarketOutcome").
+		Where("id = ?", id).
+		First(&parlay).Error
+	if err != nil {
+		return nil, err
+	}
+	return &parlay, nil
+}
+
+// GetParlaysByUser returns a user's parlays, newest first
+func (r *repository) GetParlaysByUser(ctx context.Context,
+	userID uuid.UUID,
+	filters *ParlayFilters) ([]models.Parlay, int64, error) {
+	var parlays []models.Parlay
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Parlay{}).Where("user_id = ?", userID)
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Legs", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Legs.Market").
+		Preload("Legs.MarketOutcome").
+		Order("created_at DESC").
+		Offset((filters.Page - 1) * filters.PerPage).
+		Limit(filters.PerPage).
+		Find(&parlays).Error
+	return parlays, total, err
+}
+
+// GetActiveParlayIDsByMarket returns the active parlays still waiting on their leg on a market
+func (r *repository) GetActiveParlayIDsByMarket(ctx context.Context, marketID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.ParlayLeg{}).
+		Joins("JOIN parlays ON parlays.id = parlay_legs.parlay_id").
+		Where("parlay_legs.market_id = ? AND parlay_legs.status = ? AND parlays.status = ?",
+			marketID, models.ParlayLegStatusPending, models.ParlayStatusActive).
+		Order("parlays.created_at ASC").
+		Pluck("parlay_legs.parlay_id", &ids).Error
+	return ids, err
+}
+
+// GetDecidedMarketIDsWithActiveBets returns the resolved or voided markets
+// that active bets or parlays are still waiting on
+func (r *repository) GetDecidedMarketIDsWithActiveBets(ctx context.Context) ([]uuid.UUID, error) {
+	decided := []models.MarketStatus{models.MarketStatusResolved, models.MarketStatusVoided}
+	bets := r.db.WithContext(ctx).
+		Model(&models.Bet{}).
+		Select("bets.market_id").
+		Joins("JOIN markets ON markets.id = bets.market_id").
+		Where("bets.status = ? AND markets.status IN ?", models.BetStatusActive, decided)
+	legs := r.db.WithContext(ctx).
+		Model(&models.ParlayLeg{}).
+		Select("parlay_legs.market_id").
+		Joins("JOIN parlays ON parlays.id = parlay_legs.parlay_id").
+		Joins("JOIN markets ON markets.id = parlay_legs.market_id").
+		Where("parlay_legs.status = ? AND parlays.status = ? AND markets.status IN ?",
+			models.ParlayLegStatusPending, models.ParlayStatusActive, decided)
+
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw("? UNION ?", bets, legs).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// LockParlay returns a parlay with its legs, holding a row lock on the parlay
+// until the transaction ends
+func (r *repository) LockParlay(ctx context.Context, id uuid.UUID) (*models.Parlay, error) {
+	var parlay models.Parlay
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Legs").
+		Where("id = ?", id).
+		First(&parlay).Error
+	if err != nil {
+		return nil, err
+	}
+	return &parlay, nil
+}
+
+// GetPortfolioHoldings totals users' bets per outcome as they stand now, for
+// the markets each user holds open or had a bet placed or settled on between
+// dayStart and dayEnd. Bets placed from dayEnd on are left out; DailyStake
+// covers those placed from dayStart.
+func (r *repository) GetPortfolioHoldings(ctx context.Context, dayStart, dayEnd time.Time) ([]PortfolioHolding, error) {
+	var holdings []PortfolioHolding
+	args := map[string]interface{}{
+		"active":   models.BetStatusActive,
+		"settled":  models.BetStatusSettled,
+		"refunded": models.BetStatusRefunded,
+		"dayStart": dayStart,
+		"dayEnd":   dayEnd,
+	}
+	changed := r.db.WithContext(ctx).
+		Model(&models.Bet{}).
+		Select("user_id, market_id").
+		Where(`(status = @active AND created_at < @dayEnd)
+			OR (created_at >= @dayStart AND created_at < @dayEnd)
+			OR (settled_at >= @dayStart AND settled_at < @dayEnd)`, args)
+	err := r.db.WithContext(ctx).
+		Model(&models.Bet{}).
+		Select(`bets.user_id, bets.market_id, bets.market_outcome_id AS outcome_id, markets.category_id,
+			COALESCE(SUM(bets.amount) FILTER (WHERE bets.status = @active), 0) AS open_stake,
+			COALESCE(SUM(bets.contracts_bought) FILTER (WHERE bets.status = @active), 0) AS open_contracts,
+			COALESCE(SUM(bets.amount) FILTER (WHERE bets.status = @settled), 0) AS settled_stake,
+			COALESCE(SUM(COALESCE(bets.settlement_amount, 0) - bets.amount) FILTER (WHERE bets.status = @settled), 0) AS realized_profit_loss,
+			COALESCE(SUM(bets.amount) FILTER (WHERE bets.created_at >= @dayStart AND bets.status <> @refunded), 0) AS daily_stake`,
+			args).
+		Joins("JOIN markets ON markets.id = bets.market_id").
+		Where("bets.created_at < ?", dayEnd).
+		Where("(bets.user_id, bets.market_id) IN (?)", changed).
+		Group("bets.user_id, bets.market_id, bets.market_outcome_id, markets.category_id").
+		Scan(&holdings).Error
+	return holdings, err
+}
+
+// GetPortfolioRealizedTotals totals the settled bets of each user and category
+// that had a bet settle between dayStart and dayEnd, up to dayEnd
+func (r *repository) GetPortfolioRealizedTotals(ctx context.Context,
+	dayStart, dayEnd time.Time) ([]models.PortfolioRealizedTotal, error) {
+	var totals []models.PortfolioRealizedTotal
+	changed := r.db.WithContext(ctx).
+		Model(&models.Bet{}).
+		Select("bets.user_id, markets.category_id").
+		Joins("JOIN markets ON markets.id = bets.market_id").
+		Where("bets.status = ? AND bets.settled_at >= ? AND bets.settled_at < ?", models.BetStatusSettled, dayStart, dayEnd)
+	err := r.db.WithContext(ctx).
+		Model(&models.Bet{}).
+		Select(`bets.user_id, markets.category_id,
+			SUM(bets.amount) AS settled_stake,
+			SUM(COALESCE(bets.settlement_amount, 0) - bets.amount) AS realized_profit_loss`).
+		Joins("JOIN markets ON markets.id = bets.market_id").
+		Where("bets.status = ? AND bets.settled_at < ?", models.BetStatusSettled, dayEnd).
+		Where("(bets.user_id, markets.category_id) IN (?)", changed).
+		Group("bets.user_id, markets.category_id").
+		Scan(&totals).Error
+	for i := range totals {
+		totals[i].SnapshotDate = dayStart
+	}
+	return totals, err
+}
+
+// GetMarketsWithOutcomes returns markets with their country and outcomes
+func (r *repository) GetMarketsWithOutcomes(ctx context.Context, marketIDs []uuid.UUID) ([]models.Market, error) {
+	var markets []models.Market
+	if len(marketIDs) == 0 {
+		return markets, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Country").
+		Preload("Outcomes").
+		Where("id IN ?", marketIDs).
+		Find(&markets).Error
+	return markets, err
+}
+
+// SavePortfolioSnapshots stores snapshots, replacing any already taken of the
+// same user, market and day
+func (r *repository) SavePortfolioSnapshots(ctx context.Context, snapshots []models.PortfolioSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "snapshot_date"}, {Name: "market_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"category_id", "open_stake", "open_value", "settled_stake",
+				"realized_profit_loss", "daily_stake", "updated_at",
+			}),
+		}).
+		CreateInBatches(snapshots, 500).Error
+}
+
+// GetPortfolioSnapshots returns a user's snapshots between two days inclusive,
+// oldest first, with the market titles and category names
+func (r *repository) GetPortfolioSnapshots(ctx context.Context,
+	userID uuid.UUID,
+	from, to time.Time) ([]models.PortfolioSnapshot, error) {
+	var snapshots []models.PortfolioSnapshot
+	err := r.db.WithContext(ctx).
+		Preload("Market", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title") }).
+		Preload("Category", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name") }).
+		Where("user_id = ? AND snapshot_date >= ? AND snapshot_date <= ?", userID, from, to).
+		Order("snapshot_date ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+// SavePortfolioRealizedTotals stores realized totals, replacing any already
+// taken of the same user, category and day
+func (r *repository) SavePortfolioRealizedTotals(ctx context.Context, totals []models.PortfolioRealizedTotal) error {
+	if len(totals) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "category_id"}, {Name: "snapshot_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"settled_stake", "realized_profit_loss", "updated_at"}),
+		}).
+		CreateInBatches(totals, 500).Error
+}
+
+// GetUserRealizedTotals returns a user's realized totals up to a day,
+// oldest first: each category's latest before from, then those from it on,
+// with the category names
+func (r *repository) GetUserRealizedTotals(ctx context.Context,
+	userID uuid.UUID,
+	from, to time.Time) ([]models.PortfolioRealizedTotal, error) {
+	var totals []models.PortfolioRealizedTotal
+	latestBefore := r.db.WithContext(ctx).
+		Model(&models.PortfolioRealizedTotal{}).
+		Select("DISTINCT ON (category_id) id").
+		Where("user_id = ? AND snapshot_date < ?", userID, from).
+		Order("category_id, snapshot_date DESC")
+	err := r.db.WithContext(ctx).
+		Preload("Category", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name") }).
+		Where("user_id = ?", userID).
+		Where("id IN (?) OR (snapshot_date >= ? AND snapshot_date <= ?)", latestBefore, from, to).
+		Order("snapshot_date ASC").
+		Find(&totals).Error
+	return totals, err
+}
+
 // Helper methods for filtering, sorting, and pagination
 
 func (r *repository) applyBetFilters(query *gorm.DB, filters *BetFilters) *gorm.DB {
@@ -283,3 +701,13 @@ func (r *repository) applyBetPagination(query *gorm.DB, filters *BetFilters) *go
 func (r *repository) UpdateTransaction(ctx context.Context, transaction *models.Transaction) error {
 	return r.db.WithContext(ctx).Save(transaction).Error
 }
+
+// IsQuoteUsed checks if a bet was already placed on the quote
+func (r *repository) IsQuoteUsed(ctx context.Context, nonce uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Bet{}).
+		Where("quote_nonce = ?", nonce).
+		Count(&count).Error
+	return count > 0, err
+}
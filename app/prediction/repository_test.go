@@ -42,6 +42,7 @@ func (suite *PredictionRepositoryTestSuite) TestGetBetByID() {
 	suite.AssertNoDBError(err)
 	suite.Assert().Equal(bet.ID, result.ID)
 	suite.Assert().NotNil(result.Market)
+	suite.Assert().NotNil(result.Market.Country)
 	suite.Assert().NotNil(result.MarketOutcome)
 	suite.Assert().NotNil(result.User)
 }
@@ -81,6 +82,7 @@ func (suite *PredictionRepositoryTestSuite) TestGetBetsByUser() {
 	betIDs := []uuid.UUID{bets[0].ID, bets[1].ID}
 	suite.Assert().Contains(betIDs, bet1.ID)
 	suite.Assert().Contains(betIDs, bet2.ID)
+	suite.Assert().NotNil(bets[0].Market.Country)
 }
 
 func (suite *PredictionRepositoryTestSuite) TestGetBetsByUser_WithFilters() {
@@ -183,6 +185,30 @@ func (suite *PredictionRepositoryTestSuite) TestGetBetsByMarket() {
 	suite.Assert().Contains(betIDs, bet2.ID)
 }
 
+func (suite *PredictionRepositoryTestSuite) TestBetSettlementQueries() {
+	ctx := context.Background()
+	market := suite.createTestMarket()
+	active := suite.createTestBetForMarket(market.ID, models.BetStatusActive)
+	suite.createTestBetForMarket(market.ID, models.BetStatusSettled)
+
+	ids, err := suite.repo.GetActiveBetIDsByMarket(ctx, market.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal([]uuid.UUID{active.ID}, ids)
+
+	decided, err := suite.repo.GetDecidedMarketIDsWithActiveBets(ctx)
+	suite.AssertNoDBError(err)
+	suite.Assert().NotContains(decided, market.ID, "an open market is not decided")
+
+	suite.AssertNoDBError(suite.DB.Model(market).Update("status", models.MarketStatusResolved).Error)
+	decided, err = suite.repo.GetDecidedMarketIDsWithActiveBets(ctx)
+	suite.AssertNoDBError(err)
+	suite.Assert().Contains(decided, market.ID)
+
+	settlement := models.CreateLossSettlement(market.ID, active.UserID, active.ID, active.Amount)
+	suite.AssertNoDBError(suite.repo.CreateSettlement(ctx, settlement))
+	suite.Assert().NotEqual(uuid.Nil, settlement.ID)
+}
+
 func (suite *PredictionRepositoryTestSuite) TestGetActiveBetsByUser() {
 	ctx := context.Background()
 	user := suite.createTestUser()
@@ -310,12 +336,33 @@ func (suite *PredictionRepositoryTestSuite) TestGetUserDailyBetAmount() {
 	err = suite.DB.Save(bet3).Error
 	suite.AssertNoDBError(err)
 
+	// Parlay stakes count towards the day as well
+	suite.createTestParlayForUser(user.ID, decimal.NewFromFloat(25), testDate.Add(time.Hour))
+	suite.createTestParlayForUser(user.ID, decimal.NewFromFloat(75), testDate.Add(25*time.Hour))
+
 	dailyAmount, err := suite.repo.GetUserDailyBetAmount(ctx, user.ID, testDate)
 	suite.AssertNoDBError(err)
-	expected := decimal.NewFromFloat(300) // 100 + 200
+	expected := decimal.NewFromFloat(325) // 100 + 200 + 25
 	suite.Assert().True(expected.Equal(dailyAmount))
 }
 
+func (suite *PredictionRepositoryTestSuite) TestIsQuoteUsed() {
+	ctx := context.Background()
+	nonce := uuid.New()
+
+	used, err := suite.repo.IsQuoteUsed(ctx, nonce)
+	suite.AssertNoDBError(err)
+	suite.Assert().False(used)
+
+	bet := suite.createTestBet()
+	bet.QuoteNonce = &nonce
+	suite.AssertNoDBError(suite.DB.Save(bet).Error)
+
+	used, err = suite.repo.IsQuoteUsed(ctx, nonce)
+	suite.AssertNoDBError(err)
+	suite.Assert().True(used)
+}
+
 func (suite *PredictionRepositoryTestSuite) TestGetUserBetCount() {
 	ctx := context.Background()
 	user := suite.createTestUser()
@@ -339,9 +386,13 @@ func (suite *PredictionRepositoryTestSuite) TestGetUserBetCount() {
 	err = suite.DB.Save(bet3).Error
 	suite.AssertNoDBError(err)
 
+	// Parlays count as bets too
+	suite.createTestParlayForUser(user.ID, decimal.NewFromFloat(25), since.Add(3*time.Hour))
+	suite.createTestParlayForUser(user.ID, decimal.NewFromFloat(25), since.Add(-time.Hour))
+
 	count, err := suite.repo.GetUserBetCount(ctx, user.ID, since)
 	suite.AssertNoDBError(err)
-	suite.Assert().Equal(2, count)
+	suite.Assert().Equal(3, count)
 }
 
 func (suite *PredictionRepositoryTestSuite) TestGetMarketWithOutcomes() {
@@ -461,6 +512,79 @@ func (suite *PredictionRepositoryTestSuite) TestApplyBetSorting_SQLInjectionProt
 }
 
 // Helper methods
+func (suite *PredictionRepositoryTestSuite) TestPortfolioSnapshots() {
+	ctx := context.Background()
+	user := suite.createTestUser()
+	market := suite.createTestMarket()
+	suite.createTestBetWithAmount(user.ID, market.ID, decimal.NewFromFloat(100))
+
+	settled := suite.createTestBetWithAmount(user.ID, market.ID, decimal.NewFromFloat(50))
+	payout := decimal.NewFromFloat(80)
+	suite.Require().NoError(settled.Settle(payout))
+	suite.AssertNoDBError(suite.repo.UpdateBet(ctx, settled))
+
+	// A market settled last week has not changed today
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	oldMarket := suite.createTestMarket()
+	old := suite.createTestBetWithAmount(user.ID, oldMarket.ID, decimal.NewFromFloat(40))
+	suite.Require().NoError(old.Settle(decimal.NewFromFloat(60)))
+	old.SettledAt = &lastWeek
+	old.CreatedAt = lastWeek
+	suite.AssertNoDBError(suite.repo.UpdateBet(ctx, old))
+
+	today := startOfDay(time.Now())
+	holdings, err := suite.repo.GetPortfolioHoldings(ctx, today, today.AddDate(0, 0, 1))
+	suite.AssertNoDBError(err)
+
+	openStake, realized, daily := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, holding := range holdings {
+		if holding.UserID != user.ID {
+			continue
+		}
+		suite.Assert().Equal(market.ID, holding.MarketID, "only open or changed markets are held")
+		suite.Assert().Equal(market.CategoryID, holding.CategoryID)
+		openStake = openStake.Add(holding.OpenStake)
+		realized = realized.Add(holding.RealizedProfitLoss)
+		daily = daily.Add(holding.DailyStake)
+	}
+	suite.Assert().True(decimal.NewFromFloat(100).Equal(openStake))
+	suite.Assert().True(decimal.NewFromFloat(30).Equal(realized))
+	suite.Assert().True(decimal.NewFromFloat(150).Equal(daily))
+
+	snapshot := models.PortfolioSnapshot{
+		UserID: user.ID, SnapshotDate: today, MarketID: market.ID, CategoryID: market.CategoryID,
+		OpenStake: decimal.NewFromFloat(100),
+	}
+	suite.AssertNoDBError(suite.repo.SavePortfolioSnapshots(ctx, []models.PortfolioSnapshot{snapshot}))
+	snapshot.ID = uuid.Nil
+	snapshot.OpenStake = decimal.NewFromFloat(60)
+	suite.AssertNoDBError(suite.repo.SavePortfolioSnapshots(ctx, []models.PortfolioSnapshot{snapshot}))
+
+	snapshots, err := suite.repo.GetPortfolioSnapshots(ctx, user.ID, today, today)
+	suite.AssertNoDBError(err)
+	suite.Require().Len(snapshots, 1, "a second snapshot of the day replaces the first")
+	suite.Assert().True(decimal.NewFromFloat(60).Equal(snapshots[0].OpenStake))
+	suite.Assert().Equal(market.Title, snapshots[0].Market.Title)
+	suite.Assert().NotEmpty(snapshots[0].Category.Name)
+
+	totals, err := suite.repo.GetPortfolioRealizedTotals(ctx, today, today.AddDate(0, 0, 1))
+	suite.AssertNoDBError(err)
+	var total *models.PortfolioRealizedTotal
+	for i := range totals {
+		if totals[i].UserID == user.ID && totals[i].CategoryID == market.CategoryID {
+			total = &totals[i]
+		}
+	}
+	suite.Require().NotNil(total)
+	suite.Assert().Equal(today, total.SnapshotDate)
+
+	suite.AssertNoDBError(suite.repo.SavePortfolioRealizedTotals(ctx, []models.PortfolioRealizedTotal{*total}))
+	saved, err := suite.repo.GetUserRealizedTotals(ctx, user.ID, today.AddDate(0, 0, 1), today.AddDate(0, 0, 2))
+	suite.AssertNoDBError(err)
+	suite.Require().Len(saved, 1, "the latest total before the range carries into it")
+	suite.Assert().True(total.RealizedProfitLoss.Equal(saved[0].RealizedProfitLoss))
+}
+
 func (suite *PredictionRepositoryTestSuite) createTestUser() *models.User {
 	country := suite.createTestCountry()
 	isActive := true
@@ -608,6 +732,23 @@ func (suite *PredictionRepositoryTestSuite) createTestBet() *models.Bet {
 	return bet
 }
 
+func (suite *PredictionRepositoryTestSuite) createTestParlayForUser(userID uuid.UUID, stake decimal.Decimal, createdAt time.Time) *models.Parlay {
+	transaction := suite.createTestTransaction(userID)
+	parlay := &models.Parlay{
+		UserID:          userID,
+		CurrencyCode:    "USD",
+		Stake:           stake,
+		CombinedPrice:   decimal.NewFromFloat(0.25),
+		PotentialPayout: stake.Mul(decimal.NewFromInt(4)),
+		TransactionID:   transaction.ID,
+		Status:          models.ParlayStatusActive,
+		CreatedAt:       createdAt,
+	}
+	err := suite.DB.Create(parlay).Error
+	suite.AssertNoDBError(err)
+	return parlay
+}
+
 func (suite *PredictionRepositoryTestSuite) createTestBetForUser(userID uuid.UUID) *models.Bet {
 	market := suite.createTestMarket() // Creates a new market each time
 	outcome := suite.createTestOutcome(market.ID)
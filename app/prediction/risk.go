@@ -2,77 +2,262 @@ package prediction
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/responsible"
 	"github.com/joefazee/neo/models"
 	"github.com/shopspring/decimal"
 )
 
+// BetContext carries everything the risk rules need to judge a bet
+type BetContext struct {
+	User     *models.User
+	Market   *models.Market
+	Amount   decimal.Decimal
+	Currency string
+	// Wallet is nil when the user has no wallet in the bet currency
+	Wallet *models.Wallet
+}
+
+// Country returns the market's country, or nil if it is not loaded
+func (b *BetContext) Country() *models.Country {
+	if b.Market == nil {
+		return nil
+	}
+	return b.Market.Country
+}
+
+// CountryCode returns the code of the market's country, or "" if it is not loaded
+func (b *BetContext) CountryCode() string {
+	if country := b.Country(); country != nil {
+		return country.Code
+	}
+	return ""
+}
+
+// RiskRule is one step of the risk pipeline. It returns a nil reason to allow
+// the bet, a reason to review or deny it, and an error only when it could not
+// reach a decision.
+type RiskRule interface {
+	Name() string
+	Evaluate(ctx context.Context, bet *BetContext) (*models.RiskReason, error)
+}
+
+// RiskReviewError flags a bet for review without refusing it
+type RiskReviewError struct {
+	Code    string
+	Message string
+}
+
+func (e *RiskReviewError) Error() string {
+	return e.Message
+}
+
+// riskReasonCodes maps the errors returned by checks to the reason codes
+// stored with a denied decision
+var riskReasonCodes = []struct {
+	err  error
+	code string
+}{
+	{models.ErrBetTooSmall, "bet_too_small"},
+	{models.ErrBetTooLarge, "bet_too_large"},
+	{models.ErrDailyLimitExceeded, "daily_limit_exceeded"},
+	{models.ErrPositionLimitExceeded, "position_limit_exceeded"},
+	{models.ErrRateLimitExceeded, "rate_limit_exceeded"},
+	{models.ErrBetCooldownActive, "cooldown_active"},
+	{models.ErrMarketNotOpenForBetting, "market_not_open"},
+	{models.ErrKYCNotVerified, "kyc_required"},
+	{models.ErrUnauthorized, "account_not_eligible"},
+	{models.ErrInsufficientWalletBalance, "insufficient_balance"},
+	{models.ErrPlayerExcluded, "player_excluded"},
+	{models.ErrStakeLimitExceeded, "stake_limit_exceeded"},
+	{models.ErrLossLimitExceeded, "loss_limit_exceeded"},
+	{models.ErrBetRefused, "bet_refused"},
+}
+
+// checkRule adapts a check that returns an error into a RiskRule
+type checkRule struct {
+	name  string
+	check func(ctx context.Context, bet *BetContext) error
+}
+
+// NewRiskRule wraps a check function as a rule. A nil error allows the bet, a
+// *RiskReviewError sends it to review and a known betting error denies it; any
+// other error is returned as a failure to evaluate.
+func NewRiskRule(name string, check func(ctx context.Context, bet *BetContext) error) RiskRule {
+	return &checkRule{name: name, check: check}
+}
+
+func (r *checkRule) Name() string {
+	return r.name
+}
+
+func (r *checkRule) Evaluate(ctx context.Context, bet *BetContext) (*models.RiskReason, error) {
+	err := r.check(ctx, bet)
+	if err == nil {
+		return nil, nil
+	}
+
+	var review *RiskReviewError
+	if errors.As(err, &review) {
+		return &models.RiskReason{
+			Rule:    r.name,
+			Code:    review.Code,
+			Outcome: models.RiskOutcomeReview,
+			Message: review.Message,
+		}, nil
+	}
+
+	for _, rc := range riskReasonCodes {
+		if errors.Is(err, rc.err) {
+			return &models.RiskReason{
+				Rule:    r.name,
+				Code:    rc.code,
+				Outcome: models.RiskOutcomeDeny,
+				Message: err.Error(),
+				Err:     err,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("risk rule %s: %w", r.name, err)
+}
+
 // riskEngine implements the RiskEngine interface
 type riskEngine struct {
 	config *Config
 	repo   Repository
+	guard  responsible.Guard
+	rules  []RiskRule
 }
 
-// NewRiskEngine creates a new risk engine
-func NewRiskEngine(config *Config, repo Repository) RiskEngine {
-	return &riskEngine{
+// NewRiskEngine creates a new risk engine with the built-in rules followed by
+// any extra rules. guard enforces the player's own limits and exclusions; a
+// nil guard skips those checks.
+func NewRiskEngine(config *Config, repo Repository, guard responsible.Guard, rules ...RiskRule) RiskEngine {
+	re := &riskEngine{
 		config: config,
 		repo:   repo,
-	}
+		guard:  guard,
+	}
+	re.rules = append([]RiskRule{
+		NewRiskRule("market_open", re.checkMarket),
+		NewRiskRule("account_status", re.checkUser),
+		NewRiskRule("player_protection", re.checkPlayerProtection),
+		NewRiskRule("bet_limits", re.checkBettingLimits),
+		NewRiskRule("position_limits", re.checkPositionLimits),
+		NewRiskRule("rate_limit", re.checkRateLimit),
+		NewRiskRule("cooldown", re.checkCooldown),
+		NewRiskRule("wallet_balance", re.checkWalletBalance),
+	}, rules...)
+	return re
 }
 
-// CheckBettingLimits validates betting amount limits
-func (re *riskEngine) CheckBettingLimits(userID uuid.UUID, amount decimal.Decimal, market *models.Market) error {
-	// Check minimum bet amount
-	minAmount := market.MinBetAmount
-	if re.config.MinBetAmount.GreaterThan(minAmount) {
-		minAmount = re.config.MinBetAmount
-	}
-	if amount.LessThan(minAmount) {
-		return models.ErrBetTooSmall
+// Evaluate runs every rule enabled for the market's country, scores the bet
+// and records the decision. All rules run so the decision lists every reason,
+// not just the first.
+func (re *riskEngine) Evaluate(ctx context.Context, bet *BetContext) (*models.RiskDecision, error) {
+	settings := re.config.RiskRulesFor(bet.Country())
+
+	decision := &models.RiskDecision{
+		UserID:       bet.User.ID,
+		MarketID:     bet.Market.ID,
+		CountryCode:  bet.CountryCode(),
+		Amount:       bet.Amount,
+		CurrencyCode: bet.Currency,
+		Outcome:      models.RiskOutcomeAllow,
+		Reasons:      models.RiskReasons{},
+	}
+
+	for _, rule := range re.rules {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if settings.IsDisabled(rule.Name()) {
+			continue
+		}
+		reason, err := rule.Evaluate(ctx, bet)
+		if err != nil {
+			return nil, err
+		}
+		if reason != nil {
+			decision.AddReason(*reason)
+		}
 	}
 
-	// Check maximum bet amount
-	maxAmount := re.config.MaxBetAmount
-	if market.MaxBetAmount != nil && market.MaxBetAmount.LessThan(maxAmount) {
-		maxAmount = *market.MaxBetAmount
+	score, err := re.AssessRiskScore(ctx, bet)
+	if err != nil {
+		return nil, err
+	}
+	decision.Score = score
+
+	switch {
+	case settings.DenyScore.IsPositive() && score.GreaterThanOrEqual(settings.DenyScore):
+		decision.AddReason(models.RiskReason{
+			Rule:    "risk_score",
+			Code:    "risk_score_too_high",
+			Outcome: models.RiskOutcomeDeny,
+			Message: fmt.Sprintf("risk score %s is at or above %s", score.StringFixed(4), settings.DenyScore),
+			Err:     models.ErrBetRefused,
+		})
+	case settings.ReviewScore.IsPositive() && score.GreaterThanOrEqual(settings.ReviewScore):
+		decision.AddReason(models.RiskReason{
+			Rule:    "risk_score",
+			Code:    "risk_score_high",
+			Outcome: models.RiskOutcomeReview,
+			Message: fmt.Sprintf("risk score %s is at or above %s", score.StringFixed(4), settings.ReviewScore),
+		})
+	}
+
+	// The decision is analytics; failing to store it must not block the bet
+	if err := re.repo.CreateRiskDecision(ctx, decision); err != nil {
+		log.Printf("Warning: failed to record risk decision for user %s on market %s: %v", bet.User.ID, bet.Market.ID, err)
+	}
+
+	return decision, nil
+}
+
+// checkBettingLimits validates the bet against the country, category and
+// market limits and the user's daily limit
+func (re *riskEngine) checkBettingLimits(ctx context.Context, bet *BetContext) error {
+	limits := re.marketLimits(bet.Market)
+	if bet.Amount.LessThan(limits.MinBet) {
+		return models.ErrBetTooSmall
 	}
-	if amount.GreaterThan(maxAmount) {
+	if bet.Amount.GreaterThan(limits.MaxBet) {
 		return models.ErrBetTooLarge
 	}
 
 	// Check daily limit
-	ctx := context.Background()
-	dailyAmount, err := re.repo.GetUserDailyBetAmount(ctx, userID, time.Now())
+	dailyAmount, err := re.repo.GetUserDailyBetAmount(ctx, bet.User.ID, time.Now())
 	if err != nil {
 		return err
 	}
 
-	if dailyAmount.Add(amount).GreaterThan(re.config.MaxDailyBetAmount) {
+	if dailyAmount.Add(bet.Amount).GreaterThan(re.config.MaxDailyBetAmount) {
 		return models.ErrDailyLimitExceeded
 	}
 
 	return nil
 }
 
-// CheckPositionLimits validates position size limits
-func (re *riskEngine) CheckPositionLimits(userID uuid.UUID, amount decimal.Decimal, market *models.Market) error {
+// checkPositionLimits validates position size limits
+func (re *riskEngine) checkPositionLimits(ctx context.Context, bet *BetContext) error {
 	if !re.config.EnablePositionLimits {
 		return nil
 	}
 
-	ctx := context.Background()
-
 	// Check current position in this market
-	currentPosition, err := re.repo.GetUserPositionInMarket(ctx, userID, market.ID)
+	currentPosition, err := re.repo.GetUserPositionInMarket(ctx, bet.User.ID, bet.Market.ID)
 	if err != nil {
 		return err
 	}
 
-	newPosition := currentPosition.Add(amount)
+	newPosition := currentPosition.Add(bet.Amount)
 
 	// Check per-market position limit
 	if newPosition.GreaterThan(re.config.MaxPositionPerMarket) {
@@ -80,16 +265,16 @@ func (re *riskEngine) CheckPositionLimits(userID uuid.UUID, amount decimal.Decim
 	}
 
 	// Check total position limit across all markets
-	activeBets, err := re.repo.GetActiveBetsByUser(ctx, userID)
+	activeBets, err := re.repo.GetActiveBetsByUser(ctx, bet.User.ID)
 	if err != nil {
 		return err
 	}
 
 	totalPosition := decimal.Zero
 	for i := range activeBets {
-		bet := activeBets[i]
-		if bet.MarketID != market.ID { // Don't double count current market
-			totalPosition = totalPosition.Add(bet.Amount)
+		active := activeBets[i]
+		if active.MarketID != bet.Market.ID { // Don't double count current market
+			totalPosition = totalPosition.Add(active.Amount)
 		}
 	}
 	totalPosition = totalPosition.Add(newPosition) // Add new position
@@ -101,12 +286,11 @@ func (re *riskEngine) CheckPositionLimits(userID uuid.UUID, amount decimal.Decim
 	return nil
 }
 
-// CheckRateLimit validates betting rate limits
-func (re *riskEngine) CheckRateLimit(userID uuid.UUID) error {
-	ctx := context.Background()
+// checkRateLimit validates betting rate limits
+func (re *riskEngine) checkRateLimit(ctx context.Context, bet *BetContext) error {
 	since := time.Now().Add(-time.Minute)
 
-	betCount, err := re.repo.GetUserBetCount(ctx, userID, since)
+	betCount, err := re.repo.GetUserBetCount(ctx, bet.User.ID, since)
 	if err != nil {
 		return err
 	}
@@ -118,16 +302,15 @@ func (re *riskEngine) CheckRateLimit(userID uuid.UUID) error {
 	return nil
 }
 
-// CheckCooldown validates betting cooldown period
-func (re *riskEngine) CheckCooldown(userID uuid.UUID) error {
+// checkCooldown validates betting cooldown period
+func (re *riskEngine) checkCooldown(ctx context.Context, bet *BetContext) error {
 	if re.config.CooldownPeriod <= 0 {
 		return nil
 	}
 
-	ctx := context.Background()
 	since := time.Now().Add(-re.config.CooldownPeriod)
 
-	betCount, err := re.repo.GetUserBetCount(ctx, userID, since)
+	betCount, err := re.repo.GetUserBetCount(ctx, bet.User.ID, since)
 	if err != nil {
 		return err
 	}
@@ -139,19 +322,15 @@ func (re *riskEngine) CheckCooldown(userID uuid.UUID) error {
 	return nil
 }
 
-// ValidateMarketForBetting ensures market is suitable for betting
-func (re *riskEngine) ValidateMarketForBetting(market *models.Market) error {
+// checkMarket ensures market is suitable for betting
+func (re *riskEngine) checkMarket(_ context.Context, bet *BetContext) error {
+	market := bet.Market
+
 	// Check if market is open
 	if !market.CanBet() {
 		return models.ErrMarketNotOpenForBetting
 	}
 
-	// Check if market closes soon (warn but don't block)
-	timeToClose := time.Until(market.CloseTime)
-	if timeToClose < 5*time.Minute {
-		log.Printf("Warning: Market %s closes in less than 5 minutes", market.ID)
-	}
-
 	// Check if market has sufficient outcomes
 	if len(market.Outcomes) < 2 {
 		return models.ErrMarketNotOpenForBetting
@@ -164,13 +343,20 @@ func (re *riskEngine) ValidateMarketForBetting(market *models.Market) error {
 		}
 	}
 
+	// Bets just before close are allowed but flagged
+	if time.Until(market.CloseTime) < 5*time.Minute {
+		return &RiskReviewError{Code: "market_closing_soon", Message: "market closes in less than 5 minutes"}
+	}
+
 	return nil
 }
 
-// ValidateUserForBetting ensures user is allowed to bet
-func (re *riskEngine) ValidateUserForBetting(user *models.User) error {
+// checkUser ensures user is allowed to bet
+func (re *riskEngine) checkUser(_ context.Context, bet *BetContext) error {
+	user := bet.User
+
 	// Check if user account is active
-	if !*user.IsActive {
+	if user.IsActive == nil || !*user.IsActive {
 		return models.ErrUnauthorized
 	}
 
@@ -184,63 +370,64 @@ func (re *riskEngine) ValidateUserForBetting(user *models.User) error {
 		return models.ErrUnauthorized
 	}
 
-	// Check KYC if required
-	if re.config.RequireKYCForBetting && !user.IsKYCVerified() {
-		return models.ErrUnauthorized
+	// Check KYC if the platform or the market's country requires it
+	if re.marketLimits(bet.Market).KYCRequired && !user.IsKYCVerified() {
+		return models.ErrKYCNotVerified
 	}
 
 	return nil
 }
 
-// CheckWalletBalance validates sufficient wallet balance
-func (re *riskEngine) CheckWalletBalance(userID uuid.UUID, amount decimal.Decimal, currencyCode string) error {
-	ctx := context.Background()
-	wallet, err := re.repo.GetUserWallet(ctx, userID, currencyCode)
-	if err != nil {
-		return models.ErrInsufficientWalletBalance
+// checkPlayerProtection enforces the user's exclusions and stake and loss limits
+func (re *riskEngine) checkPlayerProtection(ctx context.Context, bet *BetContext) error {
+	if re.guard == nil {
+		return nil
 	}
+	return re.guard.CheckStake(ctx, bet.User.ID, bet.Amount)
+}
 
-	if !wallet.CanDebit(amount) {
+// checkWalletBalance validates sufficient wallet balance
+func (re *riskEngine) checkWalletBalance(_ context.Context, bet *BetContext) error {
+	if bet.Wallet == nil || !bet.Wallet.CanDebit(bet.Amount) {
 		return models.ErrInsufficientWalletBalance
 	}
-
 	return nil
 }
 
-// AssessRiskScore calculates overall risk score for a bet (0-100)
-func (re *riskEngine) AssessRiskScore(userID uuid.UUID, amount decimal.Decimal, market *models.Market) (decimal.Decimal, error) {
+// AssessRiskScore calculates overall risk score for a bet, from 0 to 1
+func (re *riskEngine) AssessRiskScore(ctx context.Context, bet *BetContext) (decimal.Decimal, error) {
 	score := decimal.Zero
-	ctx := context.Background()
+	userID, amount, market := bet.User.ID, bet.Amount, bet.Market
 
-	// Amount risk (25 points max)
+	// Amount risk (25% weight)
 	amountRisk := re.calculateAmountRisk(amount, market)
 	score = score.Add(amountRisk.Mul(decimal.NewFromFloat(0.25)))
 
-	// Position risk (20 points max)
+	// Position risk (20% weight)
 	positionRisk, err := re.calculatePositionRisk(ctx, userID, amount, market)
 	if err != nil {
 		positionRisk = decimal.NewFromFloat(0.5) // Default medium risk if error
 	}
 	score = score.Add(positionRisk.Mul(decimal.NewFromFloat(0.20)))
 
-	// Frequency risk (15 points max)
+	// Frequency risk (15% weight)
 	frequencyRisk, err := re.calculateFrequencyRisk(ctx, userID)
 	if err != nil {
 		frequencyRisk = decimal.NewFromFloat(0.3) // Default low-medium risk
 	}
 	score = score.Add(frequencyRisk.Mul(decimal.NewFromFloat(0.15)))
 
-	// Market risk (25 points max)
+	// Market risk (25% weight)
 	marketRisk := re.calculateMarketRisk(market)
 	score = score.Add(marketRisk.Mul(decimal.NewFromFloat(0.25)))
 
-	// Time risk (15 points max)
+	// Time risk (15% weight)
 	timeRisk := re.calculateTimeRisk(market)
 	score = score.Add(timeRisk.Mul(decimal.NewFromFloat(0.15)))
 
 	// Ensure score is within bounds
-	if score.GreaterThan(decimal.NewFromInt(100)) {
-		score = decimal.NewFromInt(100)
+	if score.GreaterThan(decimal.NewFromInt(1)) {
+		score = decimal.NewFromInt(1)
 	}
 
 	return score, nil
@@ -250,10 +437,7 @@ func (re *riskEngine) AssessRiskScore(userID uuid.UUID, amount decimal.Decimal,
 
 func (re *riskEngine) calculateAmountRisk(amount decimal.Decimal, market *models.Market) decimal.Decimal {
 	// Risk increases with bet size relative to limits
-	maxAmount := re.config.MaxBetAmount
-	if market.MaxBetAmount != nil && market.MaxBetAmount.LessThan(maxAmount) {
-		maxAmount = *market.MaxBetAmount
-	}
+	maxAmount := re.marketLimits(market).MaxBet
 
 	if maxAmount.IsZero() {
 		return decimal.Zero
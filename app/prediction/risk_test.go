@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/responsible"
 	"github.com/joefazee/neo/models"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -54,6 +55,19 @@ func (m *MockRepository) GetBetsByMarket(ctx context.Context, marketID uuid.UUID
 	return args.Get(0).([]models.Bet), args.Error(1)
 }
 
+func (m *MockRepository) GetActiveBetIDsByMarket(ctx context.Context, marketID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, marketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) CreateSettlement(ctx context.Context, settlement *models.Settlement) error {
+	args := m.Called(ctx, settlement)
+	return args.Error(0)
+}
+
 func (m *MockRepository) GetActiveBetsByUser(ctx context.Context, userID uuid.UUID) ([]models.Bet, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
@@ -121,6 +135,51 @@ func (m *MockRepository) GetUserWallet(ctx context.Context, userID uuid.UUID, cu
 	return args.Get(0).(*models.Wallet), args.Error(1)
 }
 
+func (m *MockRepository) LockBet(ctx context.Context, betID uuid.UUID) (*models.Bet, error) {
+	args := m.Called(ctx, betID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Bet), args.Error(1)
+}
+
+func (m *MockRepository) LockMarket(ctx context.Context, marketID uuid.UUID) (*models.Market, error) {
+	args := m.Called(ctx, marketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Market), args.Error(1)
+}
+
+func (m *MockRepository) LockMarketOutcome(ctx context.Context, outcomeID uuid.UUID) (*models.MarketOutcome, error) {
+	args := m.Called(ctx, outcomeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MarketOutcome), args.Error(1)
+}
+
+func (m *MockRepository) LockUserWallet(ctx context.Context, userID uuid.UUID, currencyCode string) (*models.Wallet, error) {
+	args := m.Called(ctx, userID, currencyCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Wallet), args.Error(1)
+}
+
+func (m *MockRepository) AdjustPools(ctx context.Context, marketID, outcomeID uuid.UUID, delta decimal.Decimal) error {
+	args := m.Called(ctx, marketID, outcomeID, delta)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func (m *MockRepository) UpdateWallet(ctx context.Context, wallet *models.Wallet) error {
 	args := m.Called(ctx, wallet)
 	return args.Error(0)
@@ -131,12 +190,147 @@ func (m *MockRepository) CreateTransaction(ctx context.Context, transaction *mod
 	return args.Error(0)
 }
 
+func (m *MockRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
 // UpdateTransaction is the newly added method to satisfy the interface.
 func (m *MockRepository) UpdateTransaction(ctx context.Context, transaction *models.Transaction) error {
 	args := m.Called(ctx, transaction)
 	return args.Error(0)
 }
 
+func (m *MockRepository) IsQuoteUsed(ctx context.Context, nonce uuid.UUID) (bool, error) {
+	args := m.Called(ctx, nonce)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CreateRiskDecision(ctx context.Context, decision *models.RiskDecision) error {
+	args := m.Called(ctx, decision)
+	return args.Error(0)
+}
+
+func (m *MockRepository) LinkRiskDecisions(ctx context.Context, decisionIDs []uuid.UUID, betID, parlayID *uuid.UUID) error {
+	args := m.Called(ctx, decisionIDs, betID, parlayID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateParlay(ctx context.Context, parlay *models.Parlay) error {
+	args := m.Called(ctx, parlay)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateParlay(ctx context.Context, parlay *models.Parlay) error {
+	args := m.Called(ctx, parlay)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetParlayByID(ctx context.Context, id uuid.UUID) (*models.Parlay, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Parlay), args.Error(1)
+}
+
+func (m *MockRepository) GetParlaysByUser(ctx context.Context, userID uuid.UUID, filters *ParlayFilters) ([]models.Parlay, int64, error) {
+	args := m.Called(ctx, userID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]models.Parlay), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepository) GetDecidedMarketIDsWithActiveBets(ctx context.Context) ([]uuid.UUID, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) GetActiveParlayIDsByMarket(ctx context.Context, marketID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, marketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) LockParlay(ctx context.Context, id uuid.UUID) (*models.Parlay, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Parlay), args.Error(1)
+}
+
+func (m *MockRepository) GetPortfolioHoldings(ctx context.Context, dayStart, dayEnd time.Time) ([]PortfolioHolding, error) {
+	args := m.Called(ctx, dayStart, dayEnd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]PortfolioHolding), args.Error(1)
+}
+
+func (m *MockRepository) GetMarketsWithOutcomes(ctx context.Context, marketIDs []uuid.UUID) ([]models.Market, error) {
+	args := m.Called(ctx, marketIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Market), args.Error(1)
+}
+
+func (m *MockRepository) SavePortfolioSnapshots(ctx context.Context, snapshots []models.PortfolioSnapshot) error {
+	args := m.Called(ctx, snapshots)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetPortfolioSnapshots(ctx context.Context,
+	userID uuid.UUID,
+	from, to time.Time) ([]models.PortfolioSnapshot, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PortfolioSnapshot), args.Error(1)
+}
+
+func (m *MockRepository) GetPortfolioRealizedTotals(ctx context.Context,
+	dayStart, dayEnd time.Time) ([]models.PortfolioRealizedTotal, error) {
+	args := m.Called(ctx, dayStart, dayEnd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PortfolioRealizedTotal), args.Error(1)
+}
+
+func (m *MockRepository) SavePortfolioRealizedTotals(ctx context.Context, totals []models.PortfolioRealizedTotal) error {
+	args := m.Called(ctx, totals)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetUserRealizedTotals(ctx context.Context,
+	userID uuid.UUID,
+	from, to time.Time) ([]models.PortfolioRealizedTotal, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PortfolioRealizedTotal), args.Error(1)
+}
+
+// newTestRiskEngine exposes the engine's individual checks to the tests
+func newTestRiskEngine(config *Config, repo Repository, guard responsible.Guard, rules ...RiskRule) *riskEngine {
+	return NewRiskEngine(config, repo, guard, rules...).(*riskEngine)
+}
+
+// testBet builds the bet context the checks need for a user and amount
+func testBet(userID uuid.UUID, amount decimal.Decimal, market *models.Market) *BetContext {
+	return &BetContext{User: &models.User{ID: userID}, Market: market, Amount: amount}
+}
+
 func TestRiskEngine_CheckBettingLimits(t *testing.T) {
 	config := GetDefaultConfig() // Use your actual default config
 	mockRepo := new(MockRepository)
@@ -153,74 +347,74 @@ func TestRiskEngine_CheckBettingLimits(t *testing.T) {
 	t.Run("Valid bet amount", func(t *testing.T) {
 		// Specific mock for this sub-test if the general one is too broad or causes issues
 		localMockRepo := new(MockRepository)
-		localEngine := NewRiskEngine(config, localMockRepo)
+		localEngine := newTestRiskEngine(config, localMockRepo, nil)
 		localMockRepo.On("GetUserDailyBetAmount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(decimal.NewFromInt(500), nil).Once()
 
-		err := localEngine.CheckBettingLimits(userID, config.MinBetAmount.Add(decimal.NewFromInt(1)), market)
+		err := localEngine.checkBettingLimits(context.Background(), testBet(userID, config.MinBetAmount.Add(decimal.NewFromInt(1)), market))
 		assert.NoError(t, err)
 		localMockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Bet too small (global config)", func(t *testing.T) {
 		localMockRepo := new(MockRepository)
-		localEngine := NewRiskEngine(config, localMockRepo)
+		localEngine := newTestRiskEngine(config, localMockRepo, nil)
 		// GetUserDailyBetAmount won't be called if amount checks fail first
 		// No mock needed here for GetUserDailyBetAmount unless the logic changes
 
-		err := localEngine.CheckBettingLimits(userID, config.MinBetAmount.Sub(decimal.NewFromInt(1)), market)
+		err := localEngine.checkBettingLimits(context.Background(), testBet(userID, config.MinBetAmount.Sub(decimal.NewFromInt(1)), market))
 		assert.EqualError(t, err, models.ErrBetTooSmall.Error())
 	})
 
 	t.Run("Bet too small (market specific, lower than global)", func(t *testing.T) {
 		localMockRepo := new(MockRepository)
 		tempConfig := *config // Make a copy to modify MinBetAmount locally for this test
-		localEngine := NewRiskEngine(&tempConfig, localMockRepo)
+		localEngine := newTestRiskEngine(&tempConfig, localMockRepo, nil)
 
 		marketSpecificMin := decimal.NewFromInt(50)
 		market.MinBetAmount = marketSpecificMin
 		tempConfig.MinBetAmount = decimal.NewFromInt(100) // Global is higher
 
-		err := localEngine.CheckBettingLimits(userID, marketSpecificMin.Sub(decimal.NewFromInt(1)), market)
+		err := localEngine.checkBettingLimits(context.Background(), testBet(userID, marketSpecificMin.Sub(decimal.NewFromInt(1)), market))
 		assert.EqualError(t, err, models.ErrBetTooSmall.Error()) // Should fail against global min
 	})
 
 	t.Run("Bet too large (global config)", func(t *testing.T) {
 		localMockRepo := new(MockRepository)
-		localEngine := NewRiskEngine(config, localMockRepo)
+		localEngine := newTestRiskEngine(config, localMockRepo, nil)
 
-		err := localEngine.CheckBettingLimits(userID, config.MaxBetAmount.Add(decimal.NewFromInt(1)), market)
+		err := localEngine.checkBettingLimits(context.Background(), testBet(userID, config.MaxBetAmount.Add(decimal.NewFromInt(1)), market))
 		assert.EqualError(t, err, models.ErrBetTooLarge.Error())
 	})
 
 	t.Run("Bet too large (market specific, higher than global)", func(t *testing.T) {
 		localMockRepo := new(MockRepository)
 		tempConfig := *config
-		localEngine := NewRiskEngine(&tempConfig, localMockRepo)
+		localEngine := newTestRiskEngine(&tempConfig, localMockRepo, nil)
 
 		marketSpecificMax := decimal.NewFromInt(100000)
 		market.MaxBetAmount = &marketSpecificMax
 		tempConfig.MaxBetAmount = decimal.NewFromInt(50000) // Global is lower
 
-		err := localEngine.CheckBettingLimits(userID, marketSpecificMax.Add(decimal.NewFromInt(1)), market)
+		err := localEngine.checkBettingLimits(context.Background(), testBet(userID, marketSpecificMax.Add(decimal.NewFromInt(1)), market))
 		assert.EqualError(t, err, models.ErrBetTooLarge.Error()) // Should fail against global max
 	})
 
 	t.Run("Exceeds daily limit", func(t *testing.T) {
 		localMockRepo := new(MockRepository)
-		localEngine := NewRiskEngine(config, localMockRepo)
+		localEngine := newTestRiskEngine(config, localMockRepo, nil)
 		localMockRepo.On("GetUserDailyBetAmount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(config.MaxDailyBetAmount.Sub(decimal.NewFromInt(50)), nil).Once()
 
-		err := localEngine.CheckBettingLimits(userID, decimal.NewFromInt(100), market)
+		err := localEngine.checkBettingLimits(context.Background(), testBet(userID, decimal.NewFromInt(100), market))
 		assert.EqualError(t, err, models.ErrDailyLimitExceeded.Error())
 		localMockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Repo error for daily amount", func(t *testing.T) {
 		localMockRepo := new(MockRepository)
-		localEngine := NewRiskEngine(config, localMockRepo)
+		localEngine := newTestRiskEngine(config, localMockRepo, nil)
 		localMockRepo.On("GetUserDailyBetAmount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(decimal.Zero, errors.New("db error")).Once()
 
-		err := localEngine.CheckBettingLimits(userID, decimal.NewFromInt(100), market)
+		err := localEngine.checkBettingLimits(context.Background(), testBet(userID, decimal.NewFromInt(100), market))
 		assert.Error(t, err)
 		assert.Equal(t, "db error", err.Error())
 		localMockRepo.AssertExpectations(t)
@@ -240,60 +434,60 @@ func TestRiskEngine_CheckPositionLimits(t *testing.T) {
 		tempConfig := *config
 		tempConfig.EnablePositionLimits = false
 		mockRepo := new(MockRepository) // Fresh mock
-		tempEngine := NewRiskEngine(&tempConfig, mockRepo)
-		err := tempEngine.CheckPositionLimits(userID, decimal.NewFromInt(1000), market)
+		tempEngine := newTestRiskEngine(&tempConfig, mockRepo, nil)
+		err := tempEngine.checkPositionLimits(context.Background(), testBet(userID, decimal.NewFromInt(1000), market))
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t) // Should not have made any calls
 	})
 
 	t.Run("Valid position", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.NewFromInt(1000), nil).Once()
 		mockRepo.On("GetActiveBetsByUser", mock.Anything, userID).Return([]models.Bet{
 			{MarketID: otherMarketID, Amount: decimal.NewFromInt(2000)},
 		}, nil).Once()
-		err := engine.CheckPositionLimits(userID, decimal.NewFromInt(500), market) // New position = 1500, Total = 1500+2000=3500
+		err := engine.checkPositionLimits(context.Background(), testBet(userID, decimal.NewFromInt(500), market)) // New position = 1500, Total = 1500+2000=3500
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Exceeds per-market limit", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(config.MaxPositionPerMarket.Sub(decimal.NewFromInt(100)), nil).Once()
-		err := engine.CheckPositionLimits(userID, decimal.NewFromInt(200), market)
+		err := engine.checkPositionLimits(context.Background(), testBet(userID, decimal.NewFromInt(200), market))
 		assert.EqualError(t, err, models.ErrPositionLimitExceeded.Error())
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Exceeds total user limit", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.NewFromInt(1000), nil).Once()
 		mockRepo.On("GetActiveBetsByUser", mock.Anything, userID).Return([]models.Bet{
 			{MarketID: otherMarketID, Amount: config.MaxPositionPerUser.Sub(decimal.NewFromInt(1500))},
 		}, nil).Once()
-		err := engine.CheckPositionLimits(userID, decimal.NewFromInt(501), market)
+		err := engine.checkPositionLimits(context.Background(), testBet(userID, decimal.NewFromInt(501), market))
 		assert.EqualError(t, err, models.ErrPositionLimitExceeded.Error())
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Repo error GetUserPositionInMarket", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.Zero, errors.New("db error")).Once()
-		err := engine.CheckPositionLimits(userID, decimal.NewFromInt(100), market)
+		err := engine.checkPositionLimits(context.Background(), testBet(userID, decimal.NewFromInt(100), market))
 		assert.ErrorContains(t, err, "db error")
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Repo error GetActiveBetsByUser", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.Zero, nil).Once()
 		mockRepo.On("GetActiveBetsByUser", mock.Anything, userID).Return(nil, errors.New("db error")).Once()
-		err := engine.CheckPositionLimits(userID, decimal.NewFromInt(100), market)
+		err := engine.checkPositionLimits(context.Background(), testBet(userID, decimal.NewFromInt(100), market))
 		assert.ErrorContains(t, err, "db error")
 		mockRepo.AssertExpectations(t)
 	})
@@ -305,27 +499,27 @@ func TestRiskEngine_CheckRateLimit(t *testing.T) {
 
 	t.Run("Within rate limit", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(config.MaxBetsPerMinute-1, nil).Once()
-		err := engine.CheckRateLimit(userID)
+		err := engine.checkRateLimit(context.Background(), testBet(userID, decimal.Zero, nil))
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Exceeds rate limit", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(config.MaxBetsPerMinute, nil).Once()
-		err := engine.CheckRateLimit(userID)
+		err := engine.checkRateLimit(context.Background(), testBet(userID, decimal.Zero, nil))
 		assert.EqualError(t, err, models.ErrRateLimitExceeded.Error())
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Repo error", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, errors.New("db error")).Once()
-		err := engine.CheckRateLimit(userID)
+		err := engine.checkRateLimit(context.Background(), testBet(userID, decimal.Zero, nil))
 		assert.ErrorContains(t, err, "db error")
 		mockRepo.AssertExpectations(t)
 	})
@@ -338,18 +532,18 @@ func TestRiskEngine_CheckCooldown(t *testing.T) {
 
 	t.Run("Cooldown period not active", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
-		err := engine.CheckCooldown(userID)
+		err := engine.checkCooldown(context.Background(), testBet(userID, decimal.Zero, nil))
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Cooldown period active", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(1, nil).Once()
-		err := engine.CheckCooldown(userID)
+		err := engine.checkCooldown(context.Background(), testBet(userID, decimal.Zero, nil))
 		assert.EqualError(t, err, models.ErrBetCooldownActive.Error())
 		mockRepo.AssertExpectations(t)
 	})
@@ -358,9 +552,9 @@ func TestRiskEngine_CheckCooldown(t *testing.T) {
 		localMockRepo := new(MockRepository) // Use a local mock for AssertNotCalled
 		tempConfig := *config
 		tempConfig.CooldownPeriod = 0
-		tempEngine := NewRiskEngine(&tempConfig, localMockRepo) // Pass the local mock
+		tempEngine := newTestRiskEngine(&tempConfig, localMockRepo, nil) // Pass the local mock
 
-		err := tempEngine.CheckCooldown(userID)
+		err := tempEngine.checkCooldown(context.Background(), testBet(userID, decimal.Zero, nil))
 		assert.NoError(t, err)
 		// AssertNotCalled applies to localMockRepo, which hasn't had GetUserBetCount called on it.
 		localMockRepo.AssertNotCalled(t, "GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time"))
@@ -368,16 +562,16 @@ func TestRiskEngine_CheckCooldown(t *testing.T) {
 
 	t.Run("Repo error", func(t *testing.T) {
 		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, errors.New("db error")).Once()
-		err := engine.CheckCooldown(userID)
+		err := engine.checkCooldown(context.Background(), testBet(userID, decimal.Zero, nil))
 		assert.ErrorContains(t, err, "db error")
 		mockRepo.AssertExpectations(t)
 	})
 }
 
-func TestRiskEngine_ValidateMarketForBetting(t *testing.T) {
-	engine := NewRiskEngine(GetDefaultConfig(), new(MockRepository))
+func TestRiskEngine_CheckMarket(t *testing.T) {
+	engine := newTestRiskEngine(GetDefaultConfig(), new(MockRepository), nil)
 	marketID := uuid.New()
 
 	t.Run("Valid market", func(t *testing.T) {
@@ -390,19 +584,19 @@ func TestRiskEngine_ValidateMarketForBetting(t *testing.T) {
 				{OutcomeKey: "no", OutcomeLabel: "No"},
 			},
 		}
-		err := engine.ValidateMarketForBetting(market)
+		err := engine.checkMarket(context.Background(), &BetContext{Market: market})
 		assert.NoError(t, err)
 	})
 
 	t.Run("Market not open", func(t *testing.T) {
 		market := &models.Market{Status: models.MarketStatusClosed, CloseTime: time.Now().Add(time.Hour)}
-		err := engine.ValidateMarketForBetting(market)
+		err := engine.checkMarket(context.Background(), &BetContext{Market: market})
 		assert.EqualError(t, err, models.ErrMarketNotOpenForBetting.Error())
 	})
 
 	t.Run("Market closed (past CloseTime)", func(t *testing.T) {
 		market := &models.Market{Status: models.MarketStatusOpen, CloseTime: time.Now().Add(-time.Hour)}
-		err := engine.ValidateMarketForBetting(market)
+		err := engine.checkMarket(context.Background(), &BetContext{Market: market})
 		assert.EqualError(t, err, models.ErrMarketNotOpenForBetting.Error())
 	})
 
@@ -412,7 +606,7 @@ func TestRiskEngine_ValidateMarketForBetting(t *testing.T) {
 			CloseTime: time.Now().Add(10 * time.Minute),
 			Outcomes:  []models.MarketOutcome{{OutcomeKey: "yes", OutcomeLabel: "Yes"}},
 		}
-		err := engine.ValidateMarketForBetting(market)
+		err := engine.checkMarket(context.Background(), &BetContext{Market: market})
 		assert.EqualError(t, err, models.ErrMarketNotOpenForBetting.Error())
 	})
 
@@ -425,14 +619,29 @@ func TestRiskEngine_ValidateMarketForBetting(t *testing.T) {
 				{OutcomeKey: "no", OutcomeLabel: "No"},
 			},
 		}
-		err := engine.ValidateMarketForBetting(market)
+		err := engine.checkMarket(context.Background(), &BetContext{Market: market})
 		assert.EqualError(t, err, models.ErrMarketNotOpenForBetting.Error())
 	})
+
+	t.Run("Market closing soon is sent to review", func(t *testing.T) {
+		market := &models.Market{
+			Status:    models.MarketStatusOpen,
+			CloseTime: time.Now().Add(2 * time.Minute),
+			Outcomes: []models.MarketOutcome{
+				{OutcomeKey: "yes", OutcomeLabel: "Yes"},
+				{OutcomeKey: "no", OutcomeLabel: "No"},
+			},
+		}
+		err := engine.checkMarket(context.Background(), &BetContext{Market: market})
+		var review *RiskReviewError
+		assert.ErrorAs(t, err, &review)
+		assert.Equal(t, "market_closing_soon", review.Code)
+	})
 }
 
-func TestRiskEngine_ValidateUserForBetting(t *testing.T) {
+func TestRiskEngine_CheckUser(t *testing.T) {
 	config := GetDefaultConfig()
-	engine := NewRiskEngine(config, new(MockRepository))
+	engine := newTestRiskEngine(config, new(MockRepository), nil)
 	now := time.Now()
 	isActive := true
 	isNotActive := false
@@ -440,75 +649,168 @@ func TestRiskEngine_ValidateUserForBetting(t *testing.T) {
 	t.Run("Valid user", func(t *testing.T) {
 		user := &models.User{IsActive: &isActive, EmailVerifiedAt: &now, KYCStatus: models.KYCStatusVerified, KYCVerifiedAt: &now}
 		config.RequireKYCForBetting = true
-		err := engine.ValidateUserForBetting(user)
+		err := engine.checkUser(context.Background(), &BetContext{User: user})
 		assert.NoError(t, err)
 	})
 
 	t.Run("User not active", func(t *testing.T) {
 		user := &models.User{IsActive: &isNotActive}
-		err := engine.ValidateUserForBetting(user)
+		err := engine.checkUser(context.Background(), &BetContext{User: user})
 		assert.EqualError(t, err, models.ErrUnauthorized.Error())
 	})
 
 	t.Run("User locked", func(t *testing.T) {
 		lockedUntil := time.Now().Add(time.Hour)
 		user := &models.User{IsActive: &isActive, LockedUntil: &lockedUntil}
-		err := engine.ValidateUserForBetting(user)
+		err := engine.checkUser(context.Background(), &BetContext{User: user})
 		assert.EqualError(t, err, models.ErrUnauthorized.Error())
 	})
 
 	t.Run("Email not verified", func(t *testing.T) {
 		user := &models.User{IsActive: &isActive, EmailVerifiedAt: nil}
-		err := engine.ValidateUserForBetting(user)
+		err := engine.checkUser(context.Background(), &BetContext{User: user})
 		assert.EqualError(t, err, models.ErrUnauthorized.Error())
 	})
 
 	t.Run("KYC required and not verified", func(t *testing.T) {
 		config.RequireKYCForBetting = true
 		user := &models.User{IsActive: &isActive, EmailVerifiedAt: &now, KYCStatus: models.KYCStatusPending}
-		err := engine.ValidateUserForBetting(user)
-		assert.EqualError(t, err, models.ErrUnauthorized.Error())
+		err := engine.checkUser(context.Background(), &BetContext{User: user})
+		assert.EqualError(t, err, models.ErrKYCNotVerified.Error())
 	})
 
 	t.Run("KYC not required, user not KYC verified", func(t *testing.T) {
 		config.RequireKYCForBetting = false
 		user := &models.User{IsActive: &isActive, EmailVerifiedAt: &now, KYCStatus: models.KYCStatusPending}
-		err := engine.ValidateUserForBetting(user)
+		err := engine.checkUser(context.Background(), &BetContext{User: user})
 		assert.NoError(t, err)
 	})
 }
 
 func TestRiskEngine_CheckWalletBalance(t *testing.T) {
+	engine := newTestRiskEngine(GetDefaultConfig(), new(MockRepository), nil)
 	userID := uuid.New()
-	currency := "USD"
 
 	t.Run("Sufficient balance", func(t *testing.T) {
-		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(GetDefaultConfig(), mockRepo)
-		wallet := &models.Wallet{Balance: decimal.NewFromInt(1000), LockedBalance: decimal.NewFromInt(100)} // Available 900
-		mockRepo.On("GetUserWallet", mock.Anything, userID, currency).Return(wallet, nil).Once()
-		err := engine.CheckWalletBalance(userID, decimal.NewFromInt(500), currency)
+		bet := testBet(userID, decimal.NewFromInt(500), nil)
+		bet.Wallet = &models.Wallet{Balance: decimal.NewFromInt(1000), LockedBalance: decimal.NewFromInt(100)} // Available 900
+		err := engine.checkWalletBalance(context.Background(), bet)
 		assert.NoError(t, err)
-		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Insufficient balance", func(t *testing.T) {
-		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(GetDefaultConfig(), mockRepo)
-		wallet := &models.Wallet{Balance: decimal.NewFromInt(500), LockedBalance: decimal.NewFromInt(100)} // Available 400
-		mockRepo.On("GetUserWallet", mock.Anything, userID, currency).Return(wallet, nil).Once()
-		err := engine.CheckWalletBalance(userID, decimal.NewFromInt(500), currency)
+		bet := testBet(userID, decimal.NewFromInt(500), nil)
+		bet.Wallet = &models.Wallet{Balance: decimal.NewFromInt(500), LockedBalance: decimal.NewFromInt(100)} // Available 400
+		err := engine.checkWalletBalance(context.Background(), bet)
 		assert.EqualError(t, err, models.ErrInsufficientWalletBalance.Error())
-		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Wallet not found", func(t *testing.T) {
-		mockRepo := new(MockRepository) // Fresh mock
-		engine := NewRiskEngine(GetDefaultConfig(), mockRepo)
-		mockRepo.On("GetUserWallet", mock.Anything, userID, currency).Return(nil, errors.New("wallet not found")).Once()
-		err := engine.CheckWalletBalance(userID, decimal.NewFromInt(100), currency)
+		err := engine.checkWalletBalance(context.Background(), testBet(userID, decimal.NewFromInt(100), nil))
 		assert.EqualError(t, err, models.ErrInsufficientWalletBalance.Error()) // Should interpret as insufficient
-		mockRepo.AssertExpectations(t)
+	})
+}
+
+type stubGuard struct {
+	err      error
+	amount   decimal.Decimal
+	headroom *decimal.Decimal
+}
+
+func (g *stubGuard) CheckDeposit(_ context.Context, _ uuid.UUID, amount decimal.Decimal) error {
+	g.amount = amount
+	return g.err
+}
+
+func (g *stubGuard) CheckStake(_ context.Context, _ uuid.UUID, amount decimal.Decimal) error {
+	g.amount = amount
+	return g.err
+}
+
+func (g *stubGuard) StakeHeadroom(context.Context, uuid.UUID) (*decimal.Decimal, error) {
+	return g.headroom, g.err
+}
+
+func (g *stubGuard) WithTx(*gorm.DB) responsible.Guard {
+	return g
+}
+
+func TestRiskEngine_CheckPlayerProtection(t *testing.T) {
+	userID := uuid.New()
+	amount := decimal.NewFromInt(25)
+
+	t.Run("No guard configured", func(t *testing.T) {
+		engine := newTestRiskEngine(GetDefaultConfig(), new(MockRepository), nil)
+		assert.NoError(t, engine.checkPlayerProtection(context.Background(), testBet(userID, amount, nil)))
+	})
+
+	t.Run("Guard allows stake", func(t *testing.T) {
+		guard := &stubGuard{}
+		engine := newTestRiskEngine(GetDefaultConfig(), new(MockRepository), guard)
+		assert.NoError(t, engine.checkPlayerProtection(context.Background(), testBet(userID, amount, nil)))
+		assert.True(t, guard.amount.Equal(amount))
+	})
+
+	t.Run("Excluded player", func(t *testing.T) {
+		engine := newTestRiskEngine(GetDefaultConfig(), new(MockRepository), &stubGuard{err: models.ErrPlayerExcluded})
+		assert.ErrorIs(t, engine.checkPlayerProtection(context.Background(), testBet(userID, amount, nil)), models.ErrPlayerExcluded)
+	})
+
+	t.Run("Stake limit exceeded", func(t *testing.T) {
+		engine := newTestRiskEngine(GetDefaultConfig(), new(MockRepository), &stubGuard{err: models.ErrStakeLimitExceeded})
+		assert.ErrorIs(t, engine.checkPlayerProtection(context.Background(), testBet(userID, amount, nil)), models.ErrStakeLimitExceeded)
+	})
+}
+
+func TestService_CheckStakeLocked(t *testing.T) {
+	userID := uuid.New()
+	amount := decimal.NewFromInt(25)
+
+	t.Run("No guard configured", func(t *testing.T) {
+		svc := &service{}
+		assert.NoError(t, svc.checkStakeLocked(context.Background(), nil, userID, amount))
+	})
+
+	t.Run("Limit exceeded under the lock", func(t *testing.T) {
+		guard := &stubGuard{err: models.ErrLossLimitExceeded}
+		svc := &service{guard: guard}
+		err := svc.checkStakeLocked(context.Background(), nil, userID, amount)
+		assert.ErrorIs(t, err, models.ErrLossLimitExceeded)
+		assert.True(t, guard.amount.Equal(amount))
+	})
+}
+
+func TestService_LinkRiskDecisions(t *testing.T) {
+	ctx := context.Background()
+	parlayID := uuid.New()
+	saved := []*models.RiskDecision{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	t.Run("Links saved decisions to the parlay", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("LinkRiskDecisions", ctx, []uuid.UUID{saved[0].ID, saved[1].ID}, (*uuid.UUID)(nil), &parlayID).Return(nil).Once()
+
+		svc := &service{repo: repo}
+		svc.linkRiskDecisions(ctx, append(saved, &models.RiskDecision{}), nil, &parlayID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Skips decisions that were never saved", func(t *testing.T) {
+		repo := new(MockRepository)
+		betID := uuid.New()
+
+		svc := &service{repo: repo}
+		svc.linkRiskDecisions(ctx, []*models.RiskDecision{{}}, &betID, nil)
+		repo.AssertNotCalled(t, "LinkRiskDecisions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Link failure does not fail the bet", func(t *testing.T) {
+		repo := new(MockRepository)
+		betID := uuid.New()
+		repo.On("LinkRiskDecisions", ctx, []uuid.UUID{saved[0].ID}, &betID, (*uuid.UUID)(nil)).Return(errors.New("db error")).Once()
+
+		svc := &service{repo: repo}
+		svc.linkRiskDecisions(ctx, saved[:1], &betID, nil)
+		repo.AssertExpectations(t)
 	})
 }
 
@@ -566,11 +868,11 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 
 	t.Run("Low risk scenario", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.Zero, nil).Once()
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
 
-		score, err := engine.AssessRiskScore(userID, baseAmount, baseMarket)
+		score, err := engine.AssessRiskScore(context.Background(), testBet(userID, baseAmount, baseMarket))
 		assert.NoError(t, err)
 		assert.True(t, score.Equal(expectedLowRiskScore), "Expected low risk score %s, got %s", expectedLowRiskScore, score)
 		mockRepo.AssertExpectations(t)
@@ -578,7 +880,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 
 	t.Run("High amount risk", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.Zero, nil).Once()
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
 
@@ -599,7 +901,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 		expectedScore := highAmountComponent.Add(currentHighAmountPositionComponent).Add(lowFrequencyComponent).Add(lowMarketComponent).Add(lowTimeComponent)
 		// expectedScore = 0.225 + 0.09 + 0 + 0 + 0 = 0.315
 
-		score, err := engine.AssessRiskScore(userID, highAmount, baseMarket)
+		score, err := engine.AssessRiskScore(context.Background(), testBet(userID, highAmount, baseMarket))
 		assert.NoError(t, err)
 		assert.True(t, score.Equal(expectedScore), "Expected score %s due to amount, got %s", expectedScore, score)
 		mockRepo.AssertExpectations(t)
@@ -607,7 +909,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 
 	t.Run("High position risk", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 
 		currentPos := config.MaxPositionPerMarket.Mul(decimal.NewFromFloat(0.8))           // 80000
 		betAmountForHighPos := config.MaxPositionPerMarket.Mul(decimal.NewFromFloat(0.15)) // 15000
@@ -629,7 +931,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(currentPos, nil).Once()
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
 
-		score, err := engine.AssessRiskScore(userID, betAmountForHighPos, baseMarket)
+		score, err := engine.AssessRiskScore(context.Background(), testBet(userID, betAmountForHighPos, baseMarket))
 		assert.NoError(t, err)
 		assert.True(t, score.Equal(expectedScore), "Expected score %s due to position, got %s", expectedScore, score)
 		mockRepo.AssertExpectations(t)
@@ -637,7 +939,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 
 	t.Run("High frequency risk", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 
 		// frequencyRiskRatio = (10*50) / (10*60) = 500 / 600 = 5/6
 		// frequencyComponent = (5/6) * 0.15 = 0.125
@@ -650,7 +952,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.Zero, nil).Once()
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(config.MaxBetsPerMinute*50, nil).Once()
 
-		score, err := engine.AssessRiskScore(userID, baseAmount, baseMarket)
+		score, err := engine.AssessRiskScore(context.Background(), testBet(userID, baseAmount, baseMarket))
 		assert.NoError(t, err)
 		assert.True(t, score.Equal(expectedScore), "Expected score %s due to frequency, got %s", expectedScore, score)
 		mockRepo.AssertExpectations(t)
@@ -658,7 +960,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 
 	t.Run("High market risk (low liquidity)", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.Zero, nil).Once()
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
 
@@ -690,7 +992,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 		expectedScore := lowAmountComponent.Add(lowPositionComponent).Add(lowFrequencyComponent).Add(highMarketComponent).Add(lowTimeComponent)
 		// expectedScore = 0.000505 + 0.000202 + 0 + 0.0825 + 0 = 0.083207
 
-		score, err := engine.AssessRiskScore(userID, baseAmount, &illiquidMarket)
+		score, err := engine.AssessRiskScore(context.Background(), testBet(userID, baseAmount, &illiquidMarket))
 		assert.NoError(t, err)
 		assert.True(t, score.Equal(expectedScore), "Expected score %s due to market liquidity, got %s", expectedScore, score)
 		mockRepo.AssertExpectations(t)
@@ -698,7 +1000,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 
 	t.Run("High time risk (closing soon)", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.Zero, nil).Once()
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
 
@@ -710,7 +1012,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 		expectedScore := lowAmountComponent.Add(lowPositionComponent).Add(lowFrequencyComponent).Add(lowMarketComponent).Add(highTimeComponent)
 		// expectedScore = 0.000505 + 0.000202 + 0 + 0 + 0.15 = 0.150707
 
-		score, err := engine.AssessRiskScore(userID, baseAmount, &closingSoonMarket)
+		score, err := engine.AssessRiskScore(context.Background(), testBet(userID, baseAmount, &closingSoonMarket))
 		assert.NoError(t, err)
 		assert.True(t, score.Equal(expectedScore), "Expected score %s due to time, got %s", expectedScore, score)
 		mockRepo.AssertExpectations(t)
@@ -718,7 +1020,7 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 
 	t.Run("Error in GetUserPositionInMarket", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		engine := NewRiskEngine(config, mockRepo)
+		engine := newTestRiskEngine(config, mockRepo, nil)
 		mockRepo.On("GetUserPositionInMarket", mock.Anything, userID, marketID).Return(decimal.Zero, errors.New("db error")).Once()
 		mockRepo.On("GetUserBetCount", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
 
@@ -728,9 +1030,152 @@ func TestRiskEngine_AssessRiskScore(t *testing.T) {
 		expectedScore := lowAmountComponent.Add(defaultErrorPositionComponent).Add(lowFrequencyComponent).Add(lowMarketComponent).Add(lowTimeComponent)
 		// expectedScore = 0.000505 + 0.10 + 0 + 0 + 0 = 0.100505
 
-		score, err := engine.AssessRiskScore(userID, baseAmount, baseMarket)
+		score, err := engine.AssessRiskScore(context.Background(), testBet(userID, baseAmount, baseMarket))
 		assert.NoError(t, err)
 		assert.True(t, score.Equal(expectedScore), "Expected score %s with default medium position risk, got %s", expectedScore, score)
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+// evaluateFixture returns a bet that passes every built-in rule and a repo primed for it
+func evaluateFixture() (*MockRepository, *BetContext) {
+	now := time.Now()
+	isActive := true
+	user := &models.User{ID: uuid.New(), IsActive: &isActive, EmailVerifiedAt: &now, KYCStatus: models.KYCStatusVerified, KYCVerifiedAt: &now}
+	market := &models.Market{
+		ID:              uuid.New(),
+		Country:         &models.Country{Code: "NG"},
+		Status:          models.MarketStatusOpen,
+		CloseTime:       now.Add(48 * time.Hour),
+		TotalPoolAmount: decimal.NewFromInt(20000),
+		Outcomes: []models.MarketOutcome{
+			{OutcomeKey: "yes", OutcomeLabel: "Yes", PoolAmount: decimal.NewFromInt(10000)},
+			{OutcomeKey: "no", OutcomeLabel: "No", PoolAmount: decimal.NewFromInt(10000)},
+		},
+	}
+
+	repo := new(MockRepository)
+	repo.On("GetUserDailyBetAmount", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return(decimal.Zero, nil).Maybe()
+	repo.On("GetUserPositionInMarket", mock.Anything, user.ID, market.ID).Return(decimal.Zero, nil).Maybe()
+	repo.On("GetActiveBetsByUser", mock.Anything, user.ID).Return(nil, nil).Maybe()
+	repo.On("GetUserBetCount", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return(0, nil).Maybe()
+
+	return repo, &BetContext{
+		User:     user,
+		Market:   market,
+		Amount:   decimal.NewFromInt(1000),
+		Currency: "NGN",
+		Wallet:   &models.Wallet{Balance: decimal.NewFromInt(10000)},
+	}
+}
+
+func TestRiskEngine_Evaluate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Allows a clean bet and records the decision", func(t *testing.T) {
+		repo, bet := evaluateFixture()
+		repo.On("CreateRiskDecision", ctx, mock.MatchedBy(func(d *models.RiskDecision) bool {
+			return d.UserID == bet.User.ID && d.MarketID == bet.Market.ID && d.CountryCode == "NG" &&
+				d.Outcome == models.RiskOutcomeAllow && d.Amount.Equal(bet.Amount)
+		})).Return(nil).Once()
+
+		decision, err := newTestRiskEngine(GetDefaultConfig(), repo, nil).Evaluate(ctx, bet)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RiskOutcomeAllow, decision.Outcome)
+		assert.Empty(t, decision.Reasons)
+		assert.True(t, decision.Score.Equal(decimal.NewFromFloat(0.007)), "score %s", decision.Score)
+		assert.NoError(t, decision.Err())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Collects every denying reason", func(t *testing.T) {
+		config := GetDefaultConfig()
+		repo := new(MockRepository)
+		_, bet := evaluateFixture()
+		repo.On("GetUserDailyBetAmount", mock.Anything, bet.User.ID, mock.AnythingOfType("time.Time")).Return(config.MaxDailyBetAmount, nil)
+		repo.On("GetUserPositionInMarket", mock.Anything, bet.User.ID, bet.Market.ID).Return(decimal.Zero, nil)
+		repo.On("GetActiveBetsByUser", mock.Anything, bet.User.ID).Return(nil, nil)
+		repo.On("GetUserBetCount", mock.Anything, bet.User.ID, mock.AnythingOfType("time.Time")).Return(0, nil)
+		repo.On("CreateRiskDecision", ctx, mock.Anything).Return(nil).Once()
+		bet.Wallet = nil
+
+		decision, err := newTestRiskEngine(config, repo, nil).Evaluate(ctx, bet)
+		assert.NoError(t, err)
+		assert.True(t, decision.IsDenied())
+		assert.Equal(t, []string{"daily_limit_exceeded", "insufficient_balance"}, decision.Codes())
+		assert.Equal(t, "bet_limits", decision.Reasons[0].Rule)
+		assert.ErrorIs(t, decision.Err(), models.ErrDailyLimitExceeded)
+	})
+
+	t.Run("Plugged-in rule sends the bet to review", func(t *testing.T) {
+		repo, bet := evaluateFixture()
+		repo.On("CreateRiskDecision", ctx, mock.Anything).Return(nil).Once()
+		watchlist := NewRiskRule("watchlist", func(context.Context, *BetContext) error {
+			return &RiskReviewError{Code: "watchlisted", Message: "user is on the watchlist"}
+		})
+
+		decision, err := newTestRiskEngine(GetDefaultConfig(), repo, nil, watchlist).Evaluate(ctx, bet)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RiskOutcomeReview, decision.Outcome)
+		assert.Equal(t, []string{"watchlisted"}, decision.Codes())
+		assert.NoError(t, decision.Err())
+	})
+
+	t.Run("Rules disabled for the country are skipped", func(t *testing.T) {
+		repo, bet := evaluateFixture()
+		repo.On("CreateRiskDecision", ctx, mock.Anything).Return(nil).Once()
+		bet.Wallet = nil
+		config := GetDefaultConfig()
+		bet.Market.Country.Config = &models.CountryConfig{
+			RiskRules: &models.RiskRuleSettings{DisabledRules: []string{"wallet_balance"}},
+		}
+
+		decision, err := newTestRiskEngine(config, repo, nil).Evaluate(ctx, bet)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RiskOutcomeAllow, decision.Outcome)
+	})
+
+	t.Run("Score at the country deny threshold refuses the bet", func(t *testing.T) {
+		repo, bet := evaluateFixture()
+		repo.On("CreateRiskDecision", ctx, mock.Anything).Return(nil).Once()
+		config := GetDefaultConfig()
+		bet.Market.Country.Config = &models.CountryConfig{
+			RiskRules: &models.RiskRuleSettings{DenyScore: decimal.NewFromFloat(0.005)},
+		}
+
+		decision, err := newTestRiskEngine(config, repo, nil).Evaluate(ctx, bet)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"risk_score_too_high"}, decision.Codes())
+		assert.ErrorIs(t, decision.Err(), models.ErrBetRefused)
+	})
+
+	t.Run("Rule failure aborts without a decision", func(t *testing.T) {
+		repo, bet := evaluateFixture()
+		broken := NewRiskRule("feed", func(context.Context, *BetContext) error {
+			return errors.New("feed unavailable")
+		})
+
+		decision, err := newTestRiskEngine(GetDefaultConfig(), repo, nil, broken).Evaluate(ctx, bet)
+		assert.Nil(t, decision)
+		assert.ErrorContains(t, err, "risk rule feed: feed unavailable")
+		repo.AssertNotCalled(t, "CreateRiskDecision", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failing to record the decision does not block the bet", func(t *testing.T) {
+		repo, bet := evaluateFixture()
+		repo.On("CreateRiskDecision", ctx, mock.Anything).Return(errors.New("db error")).Once()
+
+		decision, err := newTestRiskEngine(GetDefaultConfig(), repo, nil).Evaluate(ctx, bet)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RiskOutcomeAllow, decision.Outcome)
+	})
+
+	t.Run("Cancelled context stops the pipeline", func(t *testing.T) {
+		repo, bet := evaluateFixture()
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := newTestRiskEngine(GetDefaultConfig(), repo, nil).Evaluate(cancelled, bet)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -9,6 +9,11 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/leaderboard"
+	"github.com/joefazee/neo/app/referral"
+	"github.com/joefazee/neo/app/responsible"
 	"github.com/joefazee/neo/models"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -21,18 +26,42 @@ type service struct {
 	config        *Config
 	bettingEngine BettingEngine
 	riskEngine    RiskEngine
+	guard         responsible.Guard
+	monitor       fraud.Monitor
+	sealer        QuoteSealer
+	recorder      leaderboard.Recorder
+	referrals     referral.Tracker
 	validator     *validator.Validate
 }
 
-// NewService creates a new betting service
-func NewService(db *gorm.DB, repo Repository, config *Config, bettingEngine BettingEngine, riskEngine RiskEngine) Service {
+// NewService creates a new betting service. guard, which may be nil, rechecks
+// the player's stake and loss limits under the wallet lock. monitor, which
+// may be nil, is shown every placed bet so coordinated betting by linked
+// accounts is caught. sealer signs quote IDs; without it quotes carry no
+// guaranteed price. recorder, which may be nil, puts settled parlays on the
+// leaderboards, and referrals, which may be nil, pays the settled-bet reward.
+func NewService(db *gorm.DB,
+	repo Repository,
+	config *Config,
+	bettingEngine BettingEngine,
+	riskEngine RiskEngine,
+	guard responsible.Guard,
+	monitor fraud.Monitor,
+	sealer QuoteSealer,
+	recorder leaderboard.Recorder,
+	referrals referral.Tracker) Service {
 	return &service{
 		db:            db,
 		repo:          repo,
 		config:        config,
 		bettingEngine: bettingEngine,
 		riskEngine:    riskEngine,
-		validator:     validator.New(),
+		guard:         guard,
+		monitor:       monitor,
+		sealer:        sealer,
+		recorder:      recorder,
+		referrals:     referrals,
+		validator:     newValidator(),
 	}
 }
 
@@ -47,7 +76,12 @@ func (s *service) PlaceBet(
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
-	market, outcome, err := s.loadMarketAndOutcome(ctx, req.MarketID, req.OutcomeID)
+	quote, err := s.openQuote(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	market, _, err := s.loadMarketAndOutcome(ctx, req.MarketID, req.OutcomeID)
 	if err != nil {
 		return nil, err
 	}
@@ -58,30 +92,22 @@ func (s *service) PlaceBet(
 	}
 	currency := market.Country.CurrencyCode
 
-	if err := s.runRiskChecks(ctx, userID, market, req.Amount, currency); err != nil {
-		return nil, err
-	}
-
-	price, contracts, err := s.determinePriceAndContracts(
-		market, outcome, req.Amount,
-		req.ExpectedPrice, req.MaxSlippage,
-	)
+	decision, err := s.runRiskChecks(ctx, userID, market, req.Amount, currency)
 	if err != nil {
 		return nil, err
 	}
 
-	if contracts.IsZero() && req.Amount.GreaterThan(decimal.Zero) {
-		return nil, errors.New("bet amount too small to purchase any contracts at current price, or price is too extreme")
-	}
-
-	bet, err := s.createBetTransaction(
-		ctx, userID, market, outcome,
-		req.Amount, contracts, price, currency,
-	)
+	// Pricing happens inside the transaction, against the locked pools
+	bet, price, err := s.createBetTransaction(ctx, userID, req, quote, currency)
 	if err != nil {
 		// The error from createBetTransaction will already be descriptive.
 		return nil, fmt.Errorf("failed to execute bet transaction: %w", err)
 	}
+	s.linkRiskDecisions(ctx, []*models.RiskDecision{decision}, &bet.ID, nil)
+
+	if s.monitor != nil {
+		s.monitor.ReviewBet(ctx, bet)
+	}
 
 	resp := ToBetResponse(bet)
 	// Populate dynamic fields based on the price at the time of the bet
@@ -98,7 +124,7 @@ func (s *service) PlaceBet(
 func (s *service) loadMarketAndOutcome(
 	ctx context.Context, marketID, outcomeID uuid.UUID,
 ) (*models.Market, *models.MarketOutcome, error) {
-	market, err := s.repo.GetMarketWithOutcomes(ctx, marketID) // Expects Country to be preloaded
+	market, err := s.repo.GetMarketWithOutcomes(ctx, marketID) // Preloads Country
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil, models.ErrRecordNotFound
@@ -122,42 +148,84 @@ func (s *service) loadMarketAndOutcome(
 	return market, foundOutcome, nil
 }
 
-// runRiskChecks performs all necessary risk evaluations before placing a bet.
+// runRiskChecks evaluates the bet against the risk rules, refusing it if any rule denies it.
+// Bets sent to review are placed; the decision is kept for the risk team.
 func (s *service) runRiskChecks(
 	ctx context.Context,
 	userID uuid.UUID,
 	market *models.Market,
 	amount decimal.Decimal,
 	currencyCode string,
-) error {
-	user := s.getUserByID(ctx, userID) // This is a placeholder in the service
-
-	checks := []func() error{
-		func() error { return s.riskEngine.ValidateMarketForBetting(market) },
-		func() error { return s.riskEngine.ValidateUserForBetting(user) },
-		func() error { return s.riskEngine.CheckBettingLimits(userID, amount, market) },
-		func() error { return s.riskEngine.CheckPositionLimits(userID, amount, market) },
-		func() error { return s.riskEngine.CheckRateLimit(userID) },
-		func() error { return s.riskEngine.CheckCooldown(userID) },
-		func() error { return s.riskEngine.CheckWalletBalance(userID, amount, currencyCode) },
-	}
-	for _, check := range checks {
-		if err := check(); err != nil {
-			return err
+) (*models.RiskDecision, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrUnauthorized
 		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	wallet, err := s.repo.GetUserWallet(ctx, userID, currencyCode)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
+	}
+
+	decision, err := s.riskEngine.Evaluate(ctx, &BetContext{
+		User:     user,
+		Market:   market,
+		Amount:   amount,
+		Currency: currencyCode,
+		Wallet:   wallet,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("risk evaluation failed: %w", err)
+	}
+	return decision, decision.Err()
+}
+
+// linkRiskDecisions points the decisions that let a bet or parlay through at
+// it. The decisions are analytics, so a failure is only logged.
+func (s *service) linkRiskDecisions(ctx context.Context, decisions []*models.RiskDecision, betID, parlayID *uuid.UUID) {
+	ids := make([]uuid.UUID, 0, len(decisions))
+	for _, decision := range decisions {
+		// Decisions that failed to save have no ID
+		if decision != nil && decision.ID != uuid.Nil {
+			ids = append(ids, decision.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := s.repo.LinkRiskDecisions(ctx, ids, betID, parlayID); err != nil {
+		log.Printf("Warning: failed to link risk decisions %v: %v", ids, err)
 	}
-	return nil
+}
+
+// checkStakeLocked rechecks the player's stake and loss limits inside the
+// transaction holding the wallet lock. The risk checks run before the lock is
+// taken, so concurrent bets could otherwise each pass against the same usage.
+func (s *service) checkStakeLocked(ctx context.Context, tx *gorm.DB, userID uuid.UUID, amount decimal.Decimal) error {
+	if s.guard == nil {
+		return nil
+	}
+	return s.guard.WithTx(tx).CheckStake(ctx, userID, amount)
 }
 
 // determinePriceAndContracts calculates the execution price and contracts, handling slippage.
+// A quoted bet executes at the quoted price while the market is within tolerance of it.
 func (s *service) determinePriceAndContracts(
 	market *models.Market,
 	outcome *models.MarketOutcome,
 	amount, expectedPrice, maxSlippage decimal.Decimal,
+	quote *betQuote,
 ) (price, contracts decimal.Decimal, err error) {
 	price = s.bettingEngine.CalculateContractPrice(market, outcome)
 
-	if s.config.EnableSlippageProtection && !expectedPrice.IsZero() {
+	if quote != nil {
+		if price, err = s.honourQuote(quote, price); err != nil {
+			return decimal.Zero, decimal.Zero, err
+		}
+	} else if s.config.EnableSlippageProtection && !expectedPrice.IsZero() {
 		slippage := s.bettingEngine.CalculateSlippage(expectedPrice, price)
 		effectiveMaxSlippage := maxSlippage
 		if effectiveMaxSlippage.IsZero() {
@@ -168,30 +236,78 @@ func (s *service) determinePriceAndContracts(
 		}
 	}
 
-	contracts = s.bettingEngine.CalculateContractsBought(amount, price)
+	contracts = s.bettingEngine.CalculateContractsBought(amount, price, market.GetContractUnit())
 	return price, contracts, nil
 }
 
 // createBetTransaction handles the database operations for creating a bet atomically.
+// The market, outcome and wallet rows are locked in that order for the life of the
+// transaction, so concurrent bets on a market are priced and pooled one at a time
+// and a wallet can never be debited twice from the same balance.
 func (s *service) createBetTransaction(ctx context.Context,
 	userID uuid.UUID,
-	market *models.Market,
-	outcome *models.MarketOutcome,
-	amount, contracts, price decimal.Decimal,
-	currencyCode string) (*models.Bet, error) {
-	var betRecordToReturn *models.Bet
+	req *PlaceBetRequest,
+	quote *betQuote,
+	currencyCode string) (*models.Bet, decimal.Decimal, error) {
+	var (
+		betRecordToReturn *models.Bet
+		price             decimal.Decimal
+	)
+	amount := req.Amount
 
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		repoTx := s.repo.WithTx(tx)
 
-		wallet, err := repoTx.GetUserWallet(ctx, userID, currencyCode)
+		market, err := repoTx.LockMarket(ctx, req.MarketID)
+		if err != nil {
+			return fmt.Errorf("lock market: %w", err)
+		}
+		// The market may have closed since the risk checks ran
+		if !market.CanBet() {
+			return models.ErrMarketNotOpenForBetting
+		}
+		// A quote is only for one market, so its lock keeps two bets off the same quote
+		if quote != nil {
+			used, err := repoTx.IsQuoteUsed(ctx, quote.Nonce)
+			if err != nil {
+				return fmt.Errorf("check quote: %w", err)
+			}
+			if used {
+				return models.ErrQuoteUsed
+			}
+		}
+
+		outcome, err := repoTx.LockMarketOutcome(ctx, req.OutcomeID)
+		if err != nil {
+			return fmt.Errorf("lock outcome: %w", err)
+		}
+		if outcome.MarketID != market.ID {
+			return fmt.Errorf("outcome %s not found within market %s: %w", outcome.ID, market.ID, models.ErrRecordNotFound)
+		}
+
+		var contracts decimal.Decimal
+		price, contracts, err = s.determinePriceAndContracts(
+			market, outcome, amount,
+			req.ExpectedPrice, req.MaxSlippage, quote,
+		)
+		if err != nil {
+			return err
+		}
+		if contracts.IsZero() && amount.GreaterThan(decimal.Zero) {
+			return errors.New("bet amount too small to purchase any contracts at current price, or price is too extreme")
+		}
+
+		wallet, err := repoTx.LockUserWallet(ctx, userID, currencyCode)
 		if err != nil {
-			return fmt.Errorf("get user wallet: %w", err)
+			return fmt.Errorf("lock user wallet: %w", err)
 		}
 
 		if !wallet.CanDebit(amount) {
 			return models.ErrInsufficientWalletBalance
 		}
+		if err := s.checkStakeLocked(ctx, tx, userID, amount); err != nil {
+			return err
+		}
 		originalBalance := wallet.Balance
 
 		ledgerTx := models.CreateBetTransaction(userID, wallet.ID, amount, originalBalance, uuid.Nil)
@@ -210,6 +326,9 @@ func (s *service) createBetTransaction(ctx context.Context,
 			TransactionID:    ledgerTx.ID,
 			Status:           models.BetStatusActive,
 		}
+		if quote != nil {
+			bet.QuoteNonce = &quote.Nonce
+		}
 		if err := repoTx.CreateBet(ctx, bet); err != nil {
 			return fmt.Errorf("create bet record: %w", err)
 		}
@@ -220,26 +339,23 @@ func (s *service) createBetTransaction(ctx context.Context,
 			return fmt.Errorf("update ledger transaction with bet ID: %w", err)
 		}
 
+		oldWalletValues := wallet.AuditValues()
 		if err := wallet.Debit(amount); err != nil {
 			return fmt.Errorf("in-memory wallet debit: %w", err)
 		}
 		if err := repoTx.UpdateWallet(ctx, wallet); err != nil {
 			return fmt.Errorf("update wallet record: %w", err)
 		}
+		if err := recordBetWalletChange(ctx, repoTx, models.AuditActionBetPlaced, wallet, oldWalletValues, bet.ID); err != nil {
+			return err
+		}
 
-		// 5. Update Market and Outcome Pools
-		// These should ideally be fetched fresh within the transaction or locked.
-		// For MVP, updating passed-in objects assuming they are current for this tx.
+		if err := repoTx.AdjustPools(ctx, market.ID, outcome.ID, amount); err != nil {
+			return fmt.Errorf("update market pools: %w", err)
+		}
 		market.TotalPoolAmount = market.TotalPoolAmount.Add(amount)
 		outcome.PoolAmount = outcome.PoolAmount.Add(amount)
 
-		if err := repoTx.UpdateMarket(ctx, market); err != nil {
-			return fmt.Errorf("update market pool: %w", err)
-		}
-		if err := repoTx.UpdateMarketOutcome(ctx, outcome); err != nil {
-			return fmt.Errorf("update outcome pool: %w", err)
-		}
-
 		// Populate associations for the response
 		betRecordToReturn.Market = market
 		betRecordToReturn.MarketOutcome = outcome
@@ -249,9 +365,9 @@ func (s *service) createBetTransaction(ctx context.Context,
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, decimal.Zero, err
 	}
-	return betRecordToReturn, nil
+	return betRecordToReturn, price, nil
 }
 
 // CancelBet cancels an active bet if within the allowed window and refunds the user.
@@ -284,7 +400,8 @@ func (s *service) fetchAndValidateCancel(
 	repoTx Repository,
 	userID, betID uuid.UUID,
 ) (*models.Bet, string, error) {
-	bet, err := repoTx.GetBetByID(ctx, betID)
+	// Locking the bet first stops two cancellations of it both refunding
+	bet, err := repoTx.LockBet(ctx, betID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, "", models.ErrRecordNotFound
@@ -318,7 +435,8 @@ func (s *service) fetchAndValidateCancel(
 }
 
 // executeRefund does all of the DB + wallet + pool updates for the refund.
-// It returns the first error it encounters, exactly as before.
+// It takes the market, outcome and wallet locks in the same order as bet
+// placement, after the bet lock held by fetchAndValidateCancel.
 func (s *service) executeRefund(
 	ctx context.Context,
 	repoTx Repository,
@@ -336,10 +454,18 @@ func (s *service) executeRefund(
 		return fmt.Errorf("update bet status to refunded: %w", err)
 	}
 
-	// 2) ledger tx
-	wallet, err := repoTx.GetUserWallet(ctx, bet.UserID, currencyCode)
+	// 2) lock market and outcome ahead of the wallet
+	if _, err := repoTx.LockMarket(ctx, bet.MarketID); err != nil {
+		return fmt.Errorf("lock market for refund: %w", err)
+	}
+	if _, err := repoTx.LockMarketOutcome(ctx, bet.MarketOutcomeID); err != nil {
+		return fmt.Errorf("lock outcome for refund: %w", err)
+	}
+
+	// 3) ledger tx
+	wallet, err := repoTx.LockUserWallet(ctx, bet.UserID, currencyCode)
 	if err != nil {
-		return fmt.Errorf("get user wallet for refund: %w", err)
+		return fmt.Errorf("lock user wallet for refund: %w", err)
 	}
 	original := wallet.Balance
 
@@ -350,32 +476,21 @@ func (s *service) executeRefund(
 		return fmt.Errorf("create refund ledger transaction: %w", err)
 	}
 
-	// 3) credit + persist wallet
+	// 4) credit + persist wallet
+	oldWalletValues := wallet.AuditValues()
 	if err := wallet.Credit(amount); err != nil {
 		return fmt.Errorf("in-memory wallet credit for refund: %w", err)
 	}
 	if err := repoTx.UpdateWallet(ctx, wallet); err != nil {
 		return fmt.Errorf("update wallet record for refund: %w", err)
 	}
+	if err := recordBetWalletChange(ctx, repoTx, models.AuditActionBetRefunded, wallet, oldWalletValues, bet.ID); err != nil {
+		return err
+	}
 
-	// 4) adjust pools
-	if bet.MarketOutcome != nil {
-		m := bet.Market
-		o := bet.MarketOutcome
-		m.TotalPoolAmount = m.TotalPoolAmount.Sub(amount)
-		o.PoolAmount = o.PoolAmount.Sub(amount)
-
-		if err := repoTx.UpdateMarket(ctx, m); err != nil {
-			return fmt.Errorf("update market pool on refund: %w", err)
-		}
-		if err := repoTx.UpdateMarketOutcome(ctx, o); err != nil {
-			return fmt.Errorf("update outcome pool on refund: %w", err)
-		}
-	} else {
-		log.Printf(
-			"Warning: MarketOutcome data missing for bet %s during refund; pools not adjusted.",
-			bet.ID,
-		)
+	// 5) adjust pools
+	if err := repoTx.AdjustPools(ctx, bet.MarketID, bet.MarketOutcomeID, amount.Neg()); err != nil {
+		return fmt.Errorf("update market pools on refund: %w", err)
 	}
 
 	return nil
@@ -456,6 +571,7 @@ func (s *service) GetUserPositions(ctx context.Context, userID uuid.UUID) ([]Pos
 	}
 
 	positionsMap := make(map[string]*PositionResponse)
+	contractUnits := make(map[string]decimal.Decimal)
 
 	for i := range activeBets {
 		bet := &activeBets[i]
@@ -474,6 +590,7 @@ func (s *service) GetUserPositions(ctx context.Context, userID uuid.UUID) ([]Pos
 				position.LastBetAt = bet.CreatedAt
 			}
 		} else {
+			contractUnits[key] = bet.Market.GetContractUnit()
 			positionsMap[key] = &PositionResponse{
 				MarketID:       bet.MarketID,
 				MarketTitle:    bet.Market.Title,
@@ -488,15 +605,16 @@ func (s *service) GetUserPositions(ctx context.Context, userID uuid.UUID) ([]Pos
 	}
 
 	result := make([]PositionResponse, 0, len(positionsMap))
-	for _, position := range positionsMap {
+	for key, position := range positionsMap {
+		unit := contractUnits[key]
 		if position.TotalContracts.GreaterThan(decimal.Zero) {
-			position.AveragePrice = position.TotalInvested.Div(position.TotalContracts).Mul(decimal.NewFromInt(100))
+			position.AveragePrice = position.TotalInvested.Div(position.TotalContracts.Mul(unit)).Mul(decimal.NewFromInt(100))
 		}
 
 		outcome, errOutcome := s.repo.GetMarketOutcome(ctx, position.OutcomeID) // Preloads Market.Country
 		if errOutcome == nil && outcome != nil && outcome.Market != nil && outcome.Market.Country != nil {
 			position.CurrentPrice = s.bettingEngine.CalculateContractPrice(outcome.Market, outcome)
-			position.CurrentValue = position.TotalContracts.Mul(position.CurrentPrice.Div(decimal.NewFromInt(100)))
+			position.CurrentValue = position.TotalContracts.Mul(unit).Mul(position.CurrentPrice.Div(decimal.NewFromInt(100)))
 			position.ProfitLoss = position.CurrentValue.Sub(position.TotalInvested)
 
 			if position.TotalInvested.GreaterThan(decimal.Zero) {
@@ -510,8 +628,9 @@ func (s *service) GetUserPositions(ctx context.Context, userID uuid.UUID) ([]Pos
 	return result, nil
 }
 
-// CalculateBetQuote calculates a quote for a potential bet.
-func (s *service) CalculateBetQuote(ctx context.Context, req BetQuoteRequest) (*BetQuoteResponse, error) {
+// CalculateBetQuote calculates a quote for a potential bet. The quote ID it
+// issues can only be used by userID.
+func (s *service) CalculateBetQuote(ctx context.Context, userID uuid.UUID, req BetQuoteRequest) (*BetQuoteResponse, error) {
 	if err := s.validator.Struct(req); err != nil {
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
@@ -541,14 +660,11 @@ func (s *service) CalculateBetQuote(ctx context.Context, req BetQuoteRequest) (*
 	currentPrice := s.bettingEngine.CalculateContractPrice(market, outcome)
 	estimatedPrice := s.bettingEngine.CalculateNewPrice(market, outcome, req.Amount)
 	priceImpact := s.bettingEngine.CalculatePriceImpact(market.TotalPoolAmount, req.Amount)
-	contractsBought := s.bettingEngine.CalculateContractsBought(req.Amount, currentPrice)
+	unit := market.GetContractUnit()
+	contractsBought := s.bettingEngine.CalculateContractsBought(req.Amount, currentPrice, unit)
+	potentialPayout := contractsBought.Mul(unit)
 
-	var potentialPayout decimal.Decimal
-	if !estimatedPrice.IsZero() {
-		potentialPayout = contractsBought.Mul(decimal.NewFromInt(100)).Div(estimatedPrice)
-	}
-
-	breakevenPrice := s.bettingEngine.CalculateBreakevenPrice(req.Amount, contractsBought)
+	breakevenPrice := s.bettingEngine.CalculateBreakevenPrice(req.Amount, contractsBought, unit)
 	slippage := s.bettingEngine.CalculateSlippage(currentPrice, estimatedPrice)
 
 	var warnings []string
@@ -559,6 +675,20 @@ func (s *service) CalculateBetQuote(ctx context.Context, req BetQuoteRequest) (*
 		warnings = append(warnings, "Slippage exceeds recommended limit")
 	}
 
+	validUntil := time.Now().Add(s.quoteValidity(req.TimeoutSeconds))
+	quoteID, err := s.sealQuote(&betQuote{
+		Nonce:     uuid.New(),
+		UserID:    userID,
+		MarketID:  req.MarketID,
+		OutcomeID: req.OutcomeID,
+		Amount:    req.Amount,
+		Price:     currentPrice,
+		ExpiresAt: validUntil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &BetQuoteResponse{
 		MarketID:          req.MarketID,
 		OutcomeID:         req.OutcomeID,
@@ -571,7 +701,8 @@ func (s *service) CalculateBetQuote(ctx context.Context, req BetQuoteRequest) (*
 		BreakevenPrice:    breakevenPrice,
 		MaxLoss:           req.Amount,
 		EstimatedSlippage: slippage,
-		ValidUntil:        time.Now().Add(time.Duration(s.config.BetTimeoutSeconds) * time.Second),
+		ValidUntil:        validUntil,
+		QuoteID:           quoteID,
 		Warnings:          warnings,
 	}, nil
 }
@@ -623,6 +754,30 @@ func (s *service) GetMarketPriceImpact(ctx context.Context, marketID, outcomeID
 	}, nil
 }
 
+// PreviewBetLimits resolves the bet limits a user currently faces on a market.
+func (s *service) PreviewBetLimits(ctx context.Context, userID, marketID uuid.UUID) (*BetLimitsResponse, error) {
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	market, err := s.repo.GetMarketWithOutcomes(ctx, marketID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get market: %w", err)
+	}
+
+	limits, err := s.riskEngine.ResolveBetLimits(ctx, userID, market)
+	if err != nil {
+		return nil, err
+	}
+	return ToBetLimitsResponse(userID, marketID, limits), nil
+}
+
 // GetUserPortfolio returns user's complete betting portfolio.
 func (s *service) GetUserPortfolio(ctx context.Context, userID uuid.UUID) (*PortfolioResponse, error) {
 	positions, err := s.GetUserPositions(ctx, userID)
@@ -777,24 +932,12 @@ func aggregateBetStats(bets []models.Bet) *betAgg {
 	return agg
 }
 
-// getUserByID is a placeholder. In a real app, this would fetch from a user repository.
-func (s *service) getUserByID(_ context.Context, userID uuid.UUID) *models.User {
-	isActive := true
-	return &models.User{
-		ID:              userID,
-		IsActive:        &isActive,
-		EmailVerifiedAt: &[]time.Time{time.Now()}[0],
-		KYCStatus:       models.KYCStatusVerified,
-		KYCVerifiedAt:   &[]time.Time{time.Now()}[0],
-		Country:         &models.Country{CurrencyCode: "USD"},
-	}
-}
-
+// calculatePotentialPayout returns what the bet pays out if its outcome wins
 func (s *service) calculatePotentialPayout(bet *models.Bet) decimal.Decimal {
 	if bet.ContractsBought.IsZero() {
 		return decimal.Zero
 	}
-	return bet.ContractsBought.Mul(decimal.NewFromInt(100))
+	return bet.ContractsBought.Mul(bet.Market.GetContractUnit())
 }
 
 func (s *service) calculateCurrentProfitLoss(bet *models.Bet, currentMarketPrice decimal.Decimal) decimal.Decimal {
@@ -807,7 +950,7 @@ func (s *service) calculateCurrentProfitLoss(bet *models.Bet, currentMarketPrice
 	if currentMarketPrice.IsZero() {
 		return bet.Amount.Neg()
 	}
-	currentValue := bet.ContractsBought.Mul(currentMarketPrice.Div(decimal.NewFromInt(100)))
+	currentValue := bet.ContractsBought.Mul(bet.Market.GetContractUnit()).Mul(currentMarketPrice.Div(decimal.NewFromInt(100)))
 	return currentValue.Sub(bet.Amount)
 }
 
@@ -840,3 +983,30 @@ func (s *service) calculateWinRate(ctx context.Context, userID uuid.UUID) (decim
 
 	return decimal.NewFromInt(int64(wins)).Div(decimal.NewFromInt(int64(totalSettledForWinRate))).Mul(decimal.NewFromInt(100)), nil
 }
+
+// recordBetWalletChange audits a wallet movement caused by a bet, within the bet's transaction.
+func recordBetWalletChange(ctx context.Context,
+	repoTx Repository,
+	action string,
+	wallet *models.Wallet,
+	oldValues models.AuditValues,
+	betID uuid.UUID) error {
+	return recordWalletChange(ctx, repoTx, action, wallet, oldValues, "bet_id", betID)
+}
+
+// recordWalletChange audits a wallet movement, noting what caused it under key.
+func recordWalletChange(ctx context.Context,
+	repoTx Repository,
+	action string,
+	wallet *models.Wallet,
+	oldValues models.AuditValues,
+	key string,
+	id uuid.UUID) error {
+	newValues := wallet.AuditValues()
+	newValues[key] = id.String()
+	entry := audit.NewLog(ctx, action, models.AuditResourceWallet, &wallet.ID, oldValues, newValues)
+	if err := repoTx.CreateAuditLog(ctx, entry); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package prediction
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/joefazee/neo/models"
+	"github.com/joefazee/neo/tests/suites"
+)
+
+// allowAllRiskEngine passes every risk check so the stress tests exercise only
+// the locking in the bet write path
+type allowAllRiskEngine struct{}
+
+func (allowAllRiskEngine) Evaluate(context.Context, *BetContext) (*models.RiskDecision, error) {
+	return &models.RiskDecision{Outcome: models.RiskOutcomeAllow}, nil
+}
+
+func (allowAllRiskEngine) AssessRiskScore(context.Context, *BetContext) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+func (allowAllRiskEngine) ResolveBetLimits(context.Context, uuid.UUID, *models.Market) (*BetLimits, error) {
+	return &BetLimits{}, nil
+}
+
+type BetConcurrencyTestSuite struct {
+	suites.RepositoryTestSuite
+	service Service
+}
+
+func (suite *BetConcurrencyTestSuite) SetupSuite() {
+	if testing.Short() {
+		suite.T().Skip("Skipping database integration test")
+	}
+
+	suite.AutoMigrate = true
+	suite.RepositoryTestSuite.SetupSuite()
+
+	config := GetDefaultConfig()
+	suite.service = NewService(suite.DB, NewRepository(suite.DB), config, NewBettingEngine(config), allowAllRiskEngine{}, nil, nil, nil, nil, nil)
+}
+
+func TestBetConcurrency(t *testing.T) {
+	suite.Run(t, new(BetConcurrencyTestSuite))
+}
+
+func (suite *BetConcurrencyTestSuite) TestParallelBetsKeepPoolsAndBalancesConsistent() {
+	const (
+		users       = 20
+		betsPerUser = 15
+	)
+	stake := decimal.NewFromInt(10)
+	initial := decimal.NewFromInt(1000)
+
+	market, outcomes := suite.createMarket()
+	wallets := make([]*models.Wallet, users)
+	for i := range wallets {
+		wallets[i] = suite.createFundedWallet(initial)
+	}
+
+	errs := suite.placeInParallel(users*betsPerUser, func(i int) (uuid.UUID, *PlaceBetRequest) {
+		return wallets[i%users].UserID, &PlaceBetRequest{
+			MarketID:  market.ID,
+			OutcomeID: outcomes[i%len(outcomes)].ID,
+			Amount:    stake,
+		}
+	})
+	for _, err := range errs {
+		suite.Require().NoError(err)
+	}
+
+	totalStaked := stake.Mul(decimal.NewFromInt(users * betsPerUser))
+	suite.assertPoolsMatchBets(market.ID, totalStaked)
+
+	for _, w := range wallets {
+		var reloaded models.Wallet
+		suite.Require().NoError(suite.DB.First(&reloaded, "id = ?", w.ID).Error)
+		suite.Assert().True(
+			initial.Sub(stake.Mul(decimal.NewFromInt(betsPerUser))).Equal(reloaded.Balance),
+			"wallet %s balance %s", w.ID, reloaded.Balance,
+		)
+	}
+}
+
+func (suite *BetConcurrencyTestSuite) TestParallelBetsCannotOverdrawWallet() {
+	const attempts = 100
+	stake := decimal.NewFromInt(10)
+	initial := decimal.NewFromInt(250)
+
+	market, outcomes := suite.createMarket()
+	wallet := suite.createFundedWallet(initial)
+
+	errs := suite.placeInParallel(attempts, func(i int) (uuid.UUID, *PlaceBetRequest) {
+		return wallet.UserID, &PlaceBetRequest{
+			MarketID:  market.ID,
+			OutcomeID: outcomes[i%len(outcomes)].ID,
+			Amount:    stake,
+		}
+	})
+
+	placed := 0
+	for _, err := range errs {
+		if err == nil {
+			placed++
+			continue
+		}
+		suite.Assert().True(errors.Is(err, models.ErrInsufficientWalletBalance), "unexpected error: %v", err)
+	}
+	suite.Assert().Equal(25, placed)
+
+	var reloaded models.Wallet
+	suite.Require().NoError(suite.DB.First(&reloaded, "id = ?", wallet.ID).Error)
+	suite.Assert().True(reloaded.Balance.IsZero(), "balance %s", reloaded.Balance)
+
+	suite.assertPoolsMatchBets(market.ID, initial)
+}
+
+// placeInParallel releases n PlaceBet calls at once and returns their errors by index
+func (suite *BetConcurrencyTestSuite) placeInParallel(n int, bet func(i int) (uuid.UUID, *PlaceBetRequest)) []error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	errs := make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			userID, req := bet(i)
+			<-start
+			_, errs[i] = suite.service.PlaceBet(ctx, userID, req)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+// assertPoolsMatchBets checks the market pool, the outcome pools and the bets
+// placed on the market all agree with the expected total
+func (suite *BetConcurrencyTestSuite) assertPoolsMatchBets(marketID uuid.UUID, expected decimal.Decimal) {
+	var market models.Market
+	suite.Require().NoError(suite.DB.Preload("Outcomes").First(&market, "id = ?", marketID).Error)
+	suite.Assert().True(expected.Equal(market.TotalPoolAmount), "market pool %s, want %s", market.TotalPoolAmount, expected)
+
+	outcomeTotal := decimal.Zero
+	for i := range market.Outcomes {
+		var staked decimal.Decimal
+		suite.Require().NoError(suite.DB.Model(&models.Bet{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("market_outcome_id = ?", market.Outcomes[i].ID).
+			Scan(&staked).Error)
+		suite.Assert().True(staked.Equal(market.Outcomes[i].PoolAmount),
+			"outcome %s pool %s, bets %s", market.Outcomes[i].ID, market.Outcomes[i].PoolAmount, staked)
+		outcomeTotal = outcomeTotal.Add(market.Outcomes[i].PoolAmount)
+	}
+	suite.Assert().True(expected.Equal(outcomeTotal), "outcome pools %s, want %s", outcomeTotal, expected)
+}
+
+func (suite *BetConcurrencyTestSuite) createMarket() (*models.Market, []models.MarketOutcome) {
+	id := uuid.New().String()[:8]
+	isActive := true
+
+	country := &models.Country{
+		Name:           "Stress Country " + id,
+		Code:           "S" + id[:2],
+		CurrencyCode:   "USD",
+		CurrencySymbol: "$",
+		IsActive:       &isActive,
+		Config: &models.CountryConfig{
+			MinBet: decimal.NewFromFloat(1),
+			MaxBet: decimal.NewFromFloat(1000),
+		},
+	}
+	suite.Require().NoError(suite.DB.Where(models.Country{Code: country.Code}).FirstOrCreate(country).Error)
+
+	category := &models.Category{
+		CountryID: country.ID,
+		Name:      "Stress Category " + id,
+		Slug:      "stress-category-" + id,
+		IsActive:  true,
+	}
+	suite.Require().NoError(suite.DB.Create(category).Error)
+
+	market := &models.Market{
+		CountryID:           country.ID,
+		CategoryID:          category.ID,
+		Title:               "Stress Market " + id,
+		Description:         "Concurrent betting",
+		MarketType:          models.MarketTypeBinary,
+		Status:              models.MarketStatusOpen,
+		CloseTime:           time.Now().Add(24 * time.Hour),
+		ResolutionDeadline:  time.Now().Add(48 * time.Hour),
+		MinBetAmount:        decimal.NewFromFloat(1),
+		TotalPoolAmount:     decimal.Zero,
+		RakePercentage:      decimal.NewFromFloat(0.05),
+		CreatorRevenueShare: decimal.NewFromFloat(0.5),
+	}
+	suite.Require().NoError(suite.DB.Create(market).Error)
+
+	outcomes := []models.MarketOutcome{
+		{MarketID: market.ID, OutcomeKey: "yes", OutcomeLabel: "Yes", SortOrder: 1, PoolAmount: decimal.Zero},
+		{MarketID: market.ID, OutcomeKey: "no", OutcomeLabel: "No", SortOrder: 2, PoolAmount: decimal.Zero},
+	}
+	suite.Require().NoError(suite.DB.Create(&outcomes).Error)
+
+	return market, outcomes
+}
+
+func (suite *BetConcurrencyTestSuite) createFundedWallet(balance decimal.Decimal) *models.Wallet {
+	var country models.Country
+	suite.Require().NoError(suite.DB.First(&country).Error)
+
+	id := uuid.New()
+	isActive := true
+	user := &models.User{
+		CountryID:    country.ID,
+		Email:        "stress" + id.String()[:8] + "@example.com",
+		PasswordHash: "hashedpassword",
+		IsActive:     &isActive,
+	}
+	suite.Require().NoError(suite.DB.Create(user).Error)
+
+	wallet := &models.Wallet{
+		UserID:        user.ID,
+		CurrencyCode:  "USD",
+		Balance:       balance,
+		LockedBalance: decimal.Zero,
+	}
+	suite.Require().NoError(suite.DB.Create(wallet).Error)
+	return wallet
+}
@@ -0,0 +1,158 @@
+package prediction
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/leaderboard"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// settleMarketBets settles every active bet on a decided market. One bet
+// failing does not hold up the rest.
+func (s *service) settleMarketBets(ctx context.Context, market *models.Market) error {
+	betIDs, err := s.repo.GetActiveBetIDsByMarket(ctx, market.ID)
+	if err != nil {
+		return fmt.Errorf("get bets: %w", err)
+	}
+
+	for _, betID := range betIDs {
+		if err := s.settleBet(ctx, betID, market); err != nil {
+			log.Printf("Warning: Failed to settle bet %s on market %s: %v", betID, market.ID, err)
+		}
+	}
+	return nil
+}
+
+// settleBet settles one bet on the market's result: a winning bet pays out
+// its contracts at the country's contract unit, a losing one nothing, and a
+// bet on a voided market is refunded. The bet is locked ahead of the wallet
+// so a bet is only ever settled once.
+func (s *service) settleBet(ctx context.Context, betID uuid.UUID, market *models.Market) error {
+	var settled *models.Bet
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repoTx := s.repo.WithTx(tx)
+
+		bet, err := repoTx.LockBet(ctx, betID)
+		if err != nil {
+			return fmt.Errorf("lock bet: %w", err)
+		}
+		if !bet.IsActive() {
+			return nil
+		}
+		result, ok := parlayLegResult(market, bet.MarketOutcomeID)
+		if !ok {
+			return nil
+		}
+
+		settlement, err := s.applyBetResult(bet, result)
+		if err != nil {
+			return err
+		}
+		if settlement.PayoutAmount.IsPositive() {
+			if err := s.creditBet(ctx, repoTx, bet, settlement); err != nil {
+				return err
+			}
+		}
+		if err := repoTx.CreateSettlement(ctx, settlement); err != nil {
+			return fmt.Errorf("create settlement: %w", err)
+		}
+		if err := repoTx.UpdateBet(ctx, bet); err != nil {
+			return fmt.Errorf("update bet: %w", err)
+		}
+		settled = bet
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if settled != nil {
+		s.recordBetResult(ctx, settled, market)
+	}
+	return nil
+}
+
+// applyBetResult settles or refunds the bet and returns the settlement that
+// records it
+func (s *service) applyBetResult(bet *models.Bet, result models.ParlayLegStatus) (*models.Settlement, error) {
+	switch result {
+	case models.ParlayLegStatusVoid:
+		if err := bet.Refund(); err != nil {
+			return nil, err
+		}
+		return models.CreateRefundSettlement(bet.MarketID, bet.UserID, bet.ID, bet.Amount), nil
+	case models.ParlayLegStatusWon:
+		payout := s.calculatePotentialPayout(bet).Round(2)
+		if err := bet.Settle(payout); err != nil {
+			return nil, err
+		}
+		return models.CreateWinSettlement(bet.MarketID, bet.UserID, bet.ID, bet.Amount, payout, decimal.Zero), nil
+	default:
+		if err := bet.Settle(decimal.Zero); err != nil {
+			return nil, err
+		}
+		return models.CreateLossSettlement(bet.MarketID, bet.UserID, bet.ID, bet.Amount), nil
+	}
+}
+
+// creditBet pays a settlement out to the bet's wallet
+func (s *service) creditBet(ctx context.Context, repoTx Repository, bet *models.Bet, settlement *models.Settlement) error {
+	if bet.Market == nil || bet.Market.Country == nil {
+		return fmt.Errorf("bet %s has no market currency", bet.ID)
+	}
+	wallet, err := repoTx.LockUserWallet(ctx, bet.UserID, bet.Market.Country.CurrencyCode)
+	if err != nil {
+		return fmt.Errorf("lock user wallet: %w", err)
+	}
+
+	settlement.ID = uuid.New()
+	amount := settlement.PayoutAmount
+	ledgerTx := models.CreatePayoutTransaction(bet.UserID, wallet.ID, amount, wallet.Balance, settlement.ID)
+	action := models.AuditActionBetSettled
+	if settlement.IsRefund() {
+		ledgerTx = models.CreateBetRefundTransaction(bet.UserID, wallet.ID, amount, wallet.Balance, bet.ID)
+		action = models.AuditActionBetRefunded
+	}
+	if err := repoTx.CreateTransaction(ctx, ledgerTx); err != nil {
+		return fmt.Errorf("create ledger transaction: %w", err)
+	}
+	settlement.TransactionID = &ledgerTx.ID
+
+	oldWalletValues := wallet.AuditValues()
+	if err := wallet.Credit(amount); err != nil {
+		return fmt.Errorf("in-memory wallet credit: %w", err)
+	}
+	if err := repoTx.UpdateWallet(ctx, wallet); err != nil {
+		return fmt.Errorf("update wallet record: %w", err)
+	}
+	return recordWalletChange(ctx, repoTx, action, wallet, oldWalletValues, "bet_id", bet.ID)
+}
+
+// recordBetResult counts a won or lost bet toward the user's referral
+// milestone and puts it on the leaderboards of its market's country and
+// category
+func (s *service) recordBetResult(ctx context.Context, bet *models.Bet, market *models.Market) {
+	if !bet.IsSettled() {
+		return
+	}
+	if s.referrals != nil && market.Country != nil {
+		s.referrals.RecordMilestone(ctx, bet.UserID, models.ReferralMilestoneFirstSettledBet,
+			market.Country.CurrencyCode, bet.Amount)
+	}
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.RecordResult(ctx, leaderboard.Result{
+		UserID:     bet.UserID,
+		CountryID:  market.CountryID,
+		CategoryID: market.CategoryID,
+		Stake:      bet.Amount,
+		Payout:     *bet.SettlementAmount,
+		Won:        bet.SettlementAmount.IsPositive(),
+		SettledAt:  *bet.SettledAt,
+	})
+}
@@ -0,0 +1,88 @@
+package prediction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ApplyBetResult(t *testing.T) {
+	s := newParlayTestService(new(MockRepository))
+	market := parlayMarket("NGN", 50)
+	bet := func() *models.Bet {
+		return &models.Bet{
+			ID: uuid.New(), UserID: uuid.New(), MarketID: market.ID, Market: market,
+			Amount: decimal.NewFromInt(100), ContractsBought: decimal.NewFromInt(2), Status: models.BetStatusActive,
+		}
+	}
+
+	t.Run("Winning bet pays its contracts out", func(t *testing.T) {
+		won := bet()
+		settlement, err := s.applyBetResult(won, models.ParlayLegStatusWon)
+		require.NoError(t, err)
+		payout := decimal.NewFromInt(2).Mul(market.GetContractUnit())
+		assert.True(t, settlement.IsWin())
+		assert.True(t, payout.Equal(settlement.PayoutAmount), "payout %s", settlement.PayoutAmount)
+		assert.True(t, won.IsSettled())
+		assert.True(t, payout.Equal(*won.SettlementAmount))
+	})
+
+	t.Run("Losing bet pays nothing", func(t *testing.T) {
+		lost := bet()
+		settlement, err := s.applyBetResult(lost, models.ParlayLegStatusLost)
+		require.NoError(t, err)
+		assert.True(t, settlement.IsLoss())
+		assert.True(t, settlement.PayoutAmount.IsZero())
+		assert.True(t, lost.SettlementAmount.IsZero())
+	})
+
+	t.Run("Bet on a voided market is refunded", func(t *testing.T) {
+		voided := bet()
+		settlement, err := s.applyBetResult(voided, models.ParlayLegStatusVoid)
+		require.NoError(t, err)
+		assert.True(t, settlement.IsRefund())
+		assert.True(t, decimal.NewFromInt(100).Equal(settlement.PayoutAmount))
+		assert.True(t, voided.IsRefunded())
+	})
+
+	t.Run("Settled bet is not settled again", func(t *testing.T) {
+		settled := bet()
+		settled.Status = models.BetStatusSettled
+		_, err := s.applyBetResult(settled, models.ParlayLegStatusWon)
+		assert.ErrorIs(t, err, models.ErrBetAlreadySettled)
+	})
+}
+
+func TestService_RecordBetResult(t *testing.T) {
+	recorder := &recordedResults{}
+	milestones := &recordedMilestones{}
+	s := newParlayTestService(new(MockRepository))
+	s.recorder = recorder
+	s.referrals = milestones
+	market := parlayMarket("NGN", 50)
+	market.CountryID, market.CategoryID = uuid.New(), uuid.New()
+
+	won := &models.Bet{UserID: uuid.New(), Amount: decimal.NewFromInt(100), Status: models.BetStatusActive}
+	require.NoError(t, won.Settle(decimal.NewFromInt(200)))
+	lost := &models.Bet{UserID: uuid.New(), Amount: decimal.NewFromInt(100), Status: models.BetStatusActive}
+	require.NoError(t, lost.Settle(decimal.Zero))
+	refunded := &models.Bet{UserID: uuid.New(), Amount: decimal.NewFromInt(100), Status: models.BetStatusActive}
+	require.NoError(t, refunded.Refund())
+
+	for _, bet := range []*models.Bet{won, lost, refunded} {
+		s.recordBetResult(context.Background(), bet, market)
+	}
+
+	require.Len(t, *recorder, 2, "a refund is not a result")
+	assert.True(t, (*recorder)[0].Won)
+	assert.Equal(t, market.CountryID, (*recorder)[0].CountryID)
+	assert.Equal(t, market.CategoryID, (*recorder)[0].CategoryID, "single bets count toward their category's boards")
+	assert.False(t, (*recorder)[1].Won)
+	assert.Equal(t, recordedMilestones{models.ReferralMilestoneFirstSettledBet, models.ReferralMilestoneFirstSettledBet},
+		*milestones, "a refunded bet was not settled")
+}
@@ -0,0 +1,25 @@
+package privacy
+
+import (
+	"errors"
+	"time"
+)
+
+// Config represents the configuration for the privacy module
+type Config struct {
+	ExportTTL time.Duration `env:"PRIVACY_EXPORT_TTL"`
+}
+
+func (c *Config) Validate() error {
+	if c.ExportTTL <= 0 {
+		return errors.New("export TTL must be positive")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default privacy configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		ExportTTL: 7 * 24 * time.Hour,
+	}
+}
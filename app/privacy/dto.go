@@ -0,0 +1,53 @@
+package privacy
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// PersonalData is everything held about a user, as written to an export archive
+type PersonalData struct {
+	Profile             *models.User
+	Wallets             []models.Wallet
+	Bets                []models.Bet
+	Parlays             []models.Parlay
+	Settlements         []models.Settlement
+	Transactions        []models.Transaction
+	PaymentTransactions []models.PaymentTransaction
+	AuditLogs           []models.AuditLog
+}
+
+// ErasureRequest confirms an account erasure with the user's password
+type ErasureRequest struct {
+	Password string `json:"password"`
+}
+
+// Validate checks the erasure request.
+func (r *ErasureRequest) Validate(v *validator.Validator) {
+	v.Check(r.Password != "", "password", "password is required")
+}
+
+// ExportResponse represents the state of the user's latest data export
+type ExportResponse struct {
+	ID          uuid.UUID               `json:"id"`
+	Status      models.DataExportStatus `json:"status"`
+	Error       string                  `json:"error,omitempty"`
+	RequestedAt time.Time               `json:"requested_at"`
+	CompletedAt *time.Time              `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time              `json:"expires_at,omitempty"`
+}
+
+// ToExportResponse converts a models.DataExport to ExportResponse
+func ToExportResponse(export *models.DataExport) *ExportResponse {
+	return &ExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		Error:       export.Error,
+		RequestedAt: export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
@@ -0,0 +1,150 @@
+package privacy
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// Handler handles HTTP requests for personal data export and erasure
+type Handler struct {
+	service Service
+	logger  logger.Logger
+}
+
+// NewHandler creates a new privacy handler
+func NewHandler(service Service, lg logger.Logger) *Handler {
+	return &Handler{service: service, logger: lg}
+}
+
+// RequestExport godoc
+// @Summary      Export personal data
+// @Description  Start building an archive of the authenticated user's data, or return the one already in progress or ready
+// @Tags         privacy
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=ExportResponse}
+// @Success      202  {object}  api.Response{data=ExportResponse}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/me/export [get]
+func (h *Handler) RequestExport(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	export, err := h.service.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "RequestExport", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to request data export")
+		return
+	}
+
+	if export.Status == models.DataExportStatusReady {
+		api.SuccessResponse(c, http.StatusOK, "Data export is ready to download", export)
+		return
+	}
+	api.SuccessResponse(c, http.StatusAccepted, "Data export is being prepared", export)
+}
+
+// DownloadExport godoc
+// @Summary      Download personal data export
+// @Description  Download the authenticated user's latest data export as a zip archive
+// @Tags         privacy
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Success      200  {file}    file
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/me/export/download [get]
+func (h *Handler) DownloadExport(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	export, err := h.service.GetExportFile(c.Request.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRecordNotFound):
+			api.NotFoundResponse(c, "Data export")
+		case errors.Is(err, models.ErrExportNotReady):
+			api.ConflictResponse(c, "Data export is not ready or has expired")
+		default:
+			h.logger.Error(err, logger.Fields{"handler": "DownloadExport", "user_id": userID})
+			api.InternalErrorResponse(c, "Failed to download data export")
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+export.FileName()+`"`)
+	c.Data(http.StatusOK, "application/zip", export.Content)
+}
+
+// Erase godoc
+// @Summary      Erase account
+// @Description  Anonymize the authenticated user's personal data. Bets and financial records are kept. Refused while bets are open or a wallet holds funds
+// @Tags         privacy
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ErasureRequest true "Password confirmation"
+// @Success      200  {object}  api.Response
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/me/erasure [post]
+func (h *Handler) Erase(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ErasureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	err := h.service.Erase(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidPassword):
+			v.AddError("password", "password is incorrect")
+			api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		case errors.Is(err, models.ErrOpenPositions):
+			api.ConflictResponse(c, "Account cannot be erased while bets are open")
+		case errors.Is(err, models.ErrNonZeroBalance):
+			api.ConflictResponse(c, "Account cannot be erased while a wallet holds funds")
+		case errors.Is(err, models.ErrAccountErased):
+			api.ConflictResponse(c, "Account has already been erased")
+		case errors.Is(err, models.ErrRecordNotFound):
+			api.NotFoundResponse(c, "User")
+		default:
+			h.logger.Error(err, logger.Fields{"handler": "Erase", "user_id": userID})
+			api.InternalErrorResponse(c, "Failed to erase account")
+		}
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Account erased successfully", nil)
+}
@@ -0,0 +1,43 @@
+package privacy
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/user"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "privacy_repository"
+	ServiceKey = "privacy_service"
+)
+
+// MountAuthenticated mounts the user's data export and erasure routes
+func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	meGroup := r.Group("/users/me")
+	meGroup.GET("/export", handler.RequestExport)
+	meGroup.GET("/export/download", handler.DownloadExport)
+	meGroup.POST("/erasure", handler.Erase)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid privacy configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+
+	revoker, _ := container.GetService(user.TokenVersionsKey).(TokenRevoker)
+	container.RegisterService(ServiceKey, NewService(repo, config, revoker, container.Logger))
+}
+
+// createHandler creates a privacy handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+
+	return NewHandler(service, container.Logger)
+}
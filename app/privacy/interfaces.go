@@ -0,0 +1,34 @@
+package privacy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+)
+
+type Repository interface {
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	GetPersonalData(ctx context.Context, userID uuid.UUID) (*PersonalData, error)
+
+	CreateExport(ctx context.Context, export *models.DataExport) error
+	UpdateExport(ctx context.Context, export *models.DataExport) error
+	GetLatestExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+
+	EraseUser(ctx context.Context, user *models.User, log *models.AuditLog) error
+
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+}
+
+// TokenRevoker invalidates every token issued to a user
+type TokenRevoker interface {
+	Revoke(ctx context.Context, userID uuid.UUID) error
+}
+
+type Service interface {
+	RequestExport(ctx context.Context, userID uuid.UUID) (*ExportResponse, error)
+	// GetExportFile returns the user's latest export with its archive if it
+	// can be downloaded.
+	GetExportFile(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+	Erase(ctx context.Context, userID uuid.UUID, req *ErasureRequest) error
+}
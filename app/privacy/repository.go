@@ -0,0 +1,172 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new privacy repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Preload("Country").First(&user, "id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetPersonalData collects everything held about the user, oldest first.
+func (r *repository) GetPersonalData(ctx context.Context, userID uuid.UUID) (*PersonalData, error) {
+	profile, err := r.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &PersonalData{Profile: profile}
+	db := r.db.WithContext(ctx)
+
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&data.Wallets).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&data.Bets).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Preload("Legs").Order("created_at").Find(&data.Parlays).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&data.Settlements).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&data.Transactions).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&data.PaymentTransactions).Error; err != nil {
+		return nil, err
+	}
+	err = db.Where("user_id = ? OR (resource_type = ? AND resource_id = ?)", userID, models.AuditResourceUser, userID).
+		Order("created_at").
+		Find(&data.AuditLogs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *repository) CreateExport(ctx context.Context, export *models.DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *repository) UpdateExport(ctx context.Context, export *models.DataExport) error {
+	return r.db.WithContext(ctx).Save(export).Error
+}
+
+func (r *repository) GetLatestExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// countOpenBets counts the user's active bets and parlays
+func countOpenBets(tx *gorm.DB, userID uuid.UUID) (int64, error) {
+	var bets, parlays int64
+	err := tx.Model(&models.Bet{}).
+		Where("user_id = ? AND status = ?", userID, models.BetStatusActive).
+		Count(&bets).Error
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Model(&models.Parlay{}).
+		Where("user_id = ? AND status = ?", userID, models.ParlayStatusActive).
+		Count(&parlays).Error
+	return bets + parlays, err
+}
+
+// EraseUser saves the anonymized user and removes what else identifies them:
+// sessions, roles, export archives, stored idempotent responses and account
+// signals are deleted and API keys are revoked.
+// Bets, ledger entries, payments and audit history are kept.
+//
+// The user's wallets are locked first, so no bet can be placed and no money
+// can move while the erasure is refused with ErrOpenPositions or
+// ErrNonZeroBalance or goes through.
+func (r *repository) EraseUser(ctx context.Context, user *models.User, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wallets []models.Wallet
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", user.ID).
+			Order("id").
+			Find(&wallets).Error
+		if err != nil {
+			return err
+		}
+		for i := range wallets {
+			if !wallets[i].Balance.IsZero() || !wallets[i].LockedBalance.IsZero() {
+				return models.ErrNonZeroBalance
+			}
+		}
+		openBets, err := countOpenBets(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if openBets > 0 {
+			return models.ErrOpenPositions
+		}
+
+		if err := tx.Omit(clause.Associations).Save(user).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.UserSession{}).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&models.APIKey{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM user_roles WHERE user_id = ?", user.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.DataExport{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.AccountSignal{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
+}
+
+func (r *repository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
@@ -0,0 +1,160 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+type service struct {
+	repo     Repository
+	config   *Config
+	revoker  TokenRevoker
+	logger   logger.Logger
+	runAsync func(func())
+}
+
+// NewService creates a new privacy service. revoker may be nil, in which case
+// tokens issued before an erasure stay valid until they expire.
+func NewService(repo Repository, config *Config, revoker TokenRevoker, lg logger.Logger) Service {
+	return &service{
+		repo:     repo,
+		config:   config,
+		revoker:  revoker,
+		logger:   lg,
+		runAsync: func(f func()) { go f() },
+	}
+}
+
+// RequestExport starts building an archive of the user's data in the
+// background. An export that is still pending or downloadable is returned
+// instead of starting another.
+func (s *service) RequestExport(ctx context.Context, userID uuid.UUID) (*ExportResponse, error) {
+	latest, err := s.repo.GetLatestExport(ctx, userID)
+	if err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get export: %w", err)
+	}
+	if latest != nil && latest.IsCurrent(time.Now()) {
+		return ToExportResponse(latest), nil
+	}
+
+	export := &models.DataExport{UserID: userID, Status: models.DataExportStatusPending}
+	if err := s.repo.CreateExport(ctx, export); err != nil {
+		return nil, fmt.Errorf("failed to create export: %w", err)
+	}
+
+	log := audit.NewLog(ctx, models.AuditActionExportRequested, models.AuditResourceUser, &userID, nil,
+		models.AuditValues{"export_id": export.ID.String()})
+	if err := s.repo.CreateAuditLog(ctx, log); err != nil {
+		return nil, fmt.Errorf("failed to create audit log: %w", err)
+	}
+
+	pending := *export
+	s.runAsync(func() { s.buildExport(context.Background(), &pending) })
+
+	return ToExportResponse(export), nil
+}
+
+// buildExport builds the archive and records the outcome on the export.
+func (s *service) buildExport(ctx context.Context, export *models.DataExport) {
+	content, err := s.renderArchive(ctx, export)
+	if err != nil {
+		s.logger.Error(err, logger.Fields{"component": "privacy", "export_id": export.ID, "user_id": export.UserID})
+		export.MarkFailed("the export could not be built, please request a new one", time.Now())
+	} else {
+		export.MarkReady(content, time.Now(), s.config.ExportTTL)
+	}
+
+	if err := s.repo.UpdateExport(ctx, export); err != nil {
+		s.logger.Error(err, logger.Fields{"component": "privacy", "export_id": export.ID, "user_id": export.UserID})
+	}
+}
+
+func (s *service) renderArchive(ctx context.Context, export *models.DataExport) ([]byte, error) {
+	data, err := s.repo.GetPersonalData(ctx, export.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal data: %w", err)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	entries := []struct {
+		name  string
+		value any
+	}{
+		{"profile.json", data.Profile},
+		{"wallets.json", data.Wallets},
+		{"bets.json", data.Bets},
+		{"parlays.json", data.Parlays},
+		{"settlements.json", data.Settlements},
+		{"transactions.json", data.Transactions},
+		{"payment_transactions.json", data.PaymentTransactions},
+		{"audit_logs.json", data.AuditLogs},
+	}
+	for _, entry := range entries {
+		w, err := archive.Create(entry.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s: %w", entry.name, err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(entry.value); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", entry.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *service) GetExportFile(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	export, err := s.repo.GetLatestExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !export.IsDownloadable(time.Now()) {
+		return nil, models.ErrExportNotReady
+	}
+	return export, nil
+}
+
+// Erase anonymizes the user's personal data. Financial records are kept and
+// stay linked to the anonymized account. Erasure is refused while the user
+// has open bets or money left in any wallet.
+func (s *service) Erase(ctx context.Context, userID uuid.UUID, req *ErasureRequest) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsErased() {
+		return models.ErrAccountErased
+	}
+	if !user.CheckPassword(req.Password) {
+		return models.ErrInvalidPassword
+	}
+
+	user.Anonymize(time.Now())
+	log := audit.NewLog(ctx, models.AuditActionAccountErased, models.AuditResourceUser, &userID, nil, nil)
+	if err := s.repo.EraseUser(ctx, user, log); err != nil {
+		return fmt.Errorf("failed to erase user: %w", err)
+	}
+
+	if s.revoker != nil {
+		if err := s.revoker.Revoke(ctx, userID); err != nil {
+			s.logger.Error(err, logger.Fields{"component": "privacy", "user_id": userID})
+		}
+	}
+
+	return nil
+}
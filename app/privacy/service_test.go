@@ -0,0 +1,260 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if u := args.Get(0); u != nil {
+		return u.(*models.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetPersonalData(ctx context.Context, userID uuid.UUID) (*PersonalData, error) {
+	args := m.Called(ctx, userID)
+	if d := args.Get(0); d != nil {
+		return d.(*PersonalData), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) CreateExport(ctx context.Context, export *models.DataExport) error {
+	return m.Called(ctx, export).Error(0)
+}
+
+func (m *MockRepository) UpdateExport(ctx context.Context, export *models.DataExport) error {
+	return m.Called(ctx, export).Error(0)
+}
+
+func (m *MockRepository) GetLatestExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	args := m.Called(ctx, userID)
+	if e := args.Get(0); e != nil {
+		return e.(*models.DataExport), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) EraseUser(ctx context.Context, user *models.User, log *models.AuditLog) error {
+	return m.Called(ctx, user, log).Error(0)
+}
+
+func (m *MockRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return m.Called(ctx, log).Error(0)
+}
+
+type stubRevoker struct {
+	revoked []uuid.UUID
+}
+
+func (r *stubRevoker) Revoke(_ context.Context, userID uuid.UUID) error {
+	r.revoked = append(r.revoked, userID)
+	return nil
+}
+
+func newTestService(t *testing.T, repo *MockRepository, revoker TokenRevoker) *service {
+	s := NewService(repo, GetDefaultConfig(), revoker, logger.NewNullLogger()).(*service)
+	s.runAsync = func(f func()) { f() }
+	return s
+}
+
+func TestService_RequestExport(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("reuses an export in progress", func(t *testing.T) {
+		repo := new(MockRepository)
+		pending := &models.DataExport{ID: uuid.New(), UserID: userID, Status: models.DataExportStatusPending}
+		repo.On("GetLatestExport", ctx, userID).Return(pending, nil)
+
+		resp, err := newTestService(t, repo, nil).RequestExport(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, pending.ID, resp.ID)
+		repo.AssertNotCalled(t, "CreateExport", mock.Anything, mock.Anything)
+	})
+
+	t.Run("builds a new archive", func(t *testing.T) {
+		repo := new(MockRepository)
+		svc := newTestService(t, repo, nil)
+		data := &PersonalData{
+			Profile: &models.User{ID: userID, Email: "jane@example.com"},
+			Bets:    []models.Bet{{ID: uuid.New(), UserID: userID}},
+		}
+
+		var built *models.DataExport
+		repo.On("GetLatestExport", ctx, userID).Return(nil, models.ErrRecordNotFound)
+		repo.On("CreateExport", ctx, mock.AnythingOfType("*models.DataExport")).
+			Run(func(args mock.Arguments) { args.Get(1).(*models.DataExport).ID = uuid.New() }).
+			Return(nil)
+		repo.On("CreateAuditLog", ctx, mock.MatchedBy(func(l *models.AuditLog) bool {
+			return l.Action == models.AuditActionExportRequested
+		})).Return(nil)
+		repo.On("GetPersonalData", mock.Anything, userID).Return(data, nil)
+		repo.On("UpdateExport", mock.Anything, mock.AnythingOfType("*models.DataExport")).
+			Run(func(args mock.Arguments) { built = args.Get(1).(*models.DataExport) }).
+			Return(nil)
+
+		resp, err := svc.RequestExport(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, models.DataExportStatusPending, resp.Status)
+
+		require.NotNil(t, built)
+		assert.Equal(t, models.DataExportStatusReady, built.Status)
+		assert.NotNil(t, built.ExpiresAt)
+
+		archive, err := zip.NewReader(bytes.NewReader(built.Content), int64(len(built.Content)))
+		require.NoError(t, err)
+
+		var names []string
+		for _, f := range archive.File {
+			names = append(names, f.Name)
+		}
+		assert.Contains(t, names, "profile.json")
+		assert.Contains(t, names, "bets.json")
+		assert.Contains(t, names, "transactions.json")
+		assert.Contains(t, names, "payment_transactions.json")
+		assert.Contains(t, names, "audit_logs.json")
+	})
+
+	t.Run("records a failed build", func(t *testing.T) {
+		repo := new(MockRepository)
+		expired := time.Now().Add(-time.Hour)
+		old := &models.DataExport{UserID: userID, Status: models.DataExportStatusReady, ExpiresAt: &expired}
+
+		var built *models.DataExport
+		repo.On("GetLatestExport", ctx, userID).Return(old, nil)
+		repo.On("CreateExport", ctx, mock.Anything).Return(nil)
+		repo.On("CreateAuditLog", ctx, mock.Anything).Return(nil)
+		repo.On("GetPersonalData", mock.Anything, userID).Return(nil, errors.New("db down"))
+		repo.On("UpdateExport", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { built = args.Get(1).(*models.DataExport) }).
+			Return(nil)
+
+		_, err := newTestService(t, repo, nil).RequestExport(ctx, userID)
+		require.NoError(t, err)
+		require.NotNil(t, built)
+		assert.Equal(t, models.DataExportStatusFailed, built.Status)
+		assert.Empty(t, built.Content)
+	})
+}
+
+func TestService_GetExportFile(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("no export", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetLatestExport", ctx, userID).Return(nil, models.ErrRecordNotFound)
+
+		_, err := newTestService(t, repo, nil).GetExportFile(ctx, userID)
+		assert.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("still pending", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetLatestExport", ctx, userID).Return(&models.DataExport{Status: models.DataExportStatusPending}, nil)
+
+		_, err := newTestService(t, repo, nil).GetExportFile(ctx, userID)
+		assert.ErrorIs(t, err, models.ErrExportNotReady)
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		repo := new(MockRepository)
+		export := &models.DataExport{}
+		export.MarkReady([]byte("zip"), time.Now(), time.Hour)
+		repo.On("GetLatestExport", ctx, userID).Return(export, nil)
+
+		file, err := newTestService(t, repo, nil).GetExportFile(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("zip"), file.Content)
+	})
+}
+
+func TestService_Erase(t *testing.T) {
+	ctx := context.Background()
+
+	newUser := func(t *testing.T) *models.User {
+		active := true
+		user := &models.User{ID: uuid.New(), Email: "jane@example.com", FirstName: "Jane", IsActive: &active}
+		require.NoError(t, user.SetPassword("correct-horse"))
+		return user
+	}
+
+	t.Run("wrong password", func(t *testing.T) {
+		repo := new(MockRepository)
+		user := newUser(t)
+		repo.On("GetUserByID", ctx, user.ID).Return(user, nil)
+
+		err := newTestService(t, repo, nil).Erase(ctx, user.ID, &ErasureRequest{Password: "wrong-password"})
+		assert.ErrorIs(t, err, models.ErrInvalidPassword)
+	})
+
+	t.Run("already erased", func(t *testing.T) {
+		repo := new(MockRepository)
+		user := newUser(t)
+		user.Anonymize(time.Now())
+		repo.On("GetUserByID", ctx, user.ID).Return(user, nil)
+
+		err := newTestService(t, repo, nil).Erase(ctx, user.ID, &ErasureRequest{Password: "correct-horse"})
+		assert.ErrorIs(t, err, models.ErrAccountErased)
+	})
+
+	t.Run("open bets", func(t *testing.T) {
+		repo := new(MockRepository)
+		user := newUser(t)
+		repo.On("GetUserByID", ctx, user.ID).Return(user, nil)
+		repo.On("EraseUser", ctx, mock.Anything, mock.Anything).Return(models.ErrOpenPositions)
+
+		err := newTestService(t, repo, nil).Erase(ctx, user.ID, &ErasureRequest{Password: "correct-horse"})
+		assert.ErrorIs(t, err, models.ErrOpenPositions)
+	})
+
+	t.Run("funds left in a wallet", func(t *testing.T) {
+		repo := new(MockRepository)
+		revoker := &stubRevoker{}
+		user := newUser(t)
+		repo.On("GetUserByID", ctx, user.ID).Return(user, nil)
+		repo.On("EraseUser", ctx, mock.Anything, mock.Anything).Return(models.ErrNonZeroBalance)
+
+		err := newTestService(t, repo, revoker).Erase(ctx, user.ID, &ErasureRequest{Password: "correct-horse"})
+		assert.ErrorIs(t, err, models.ErrNonZeroBalance)
+		assert.Empty(t, revoker.revoked)
+	})
+
+	t.Run("anonymizes the user", func(t *testing.T) {
+		repo := new(MockRepository)
+		revoker := &stubRevoker{}
+		svc := newTestService(t, repo, revoker)
+		user := newUser(t)
+
+		repo.On("GetUserByID", ctx, user.ID).Return(user, nil)
+		repo.On("EraseUser", ctx, mock.MatchedBy(func(u *models.User) bool {
+			return u.IsErased() && u.FirstName == "" && u.Email != "jane@example.com"
+		}), mock.MatchedBy(func(l *models.AuditLog) bool {
+			return l.Action == models.AuditActionAccountErased
+		})).Return(nil)
+
+		err := svc.Erase(ctx, user.ID, &ErasureRequest{Password: "correct-horse"})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{user.ID}, revoker.revoked)
+		repo.AssertExpectations(t)
+	})
+}
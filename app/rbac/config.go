@@ -0,0 +1,30 @@
+package rbac
+
+import (
+	"errors"
+
+	"github.com/joefazee/neo/models"
+)
+
+// Config represents the configuration for the RBAC catalogue sync
+type Config struct {
+	// SuperAdminEmail names an existing account to promote to super-admin when
+	// no super-admin exists yet.
+	SuperAdminEmail string `env:"RBAC_SUPER_ADMIN_EMAIL"`
+	// SyncOnStartup runs the catalogue sync when the API starts.
+	SyncOnStartup bool `env:"RBAC_SYNC_ON_STARTUP"`
+}
+
+func (c *Config) Validate() error {
+	if c.SuperAdminEmail != "" && !models.IsEmail(c.SuperAdminEmail) {
+		return errors.New("super admin email must be a valid email address")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default RBAC configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		SyncOnStartup: false,
+	}
+}
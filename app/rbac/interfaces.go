@@ -0,0 +1,27 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/joefazee/neo/models"
+)
+
+type Repository interface {
+	// UpsertPermission creates the permission or refreshes its description.
+	UpsertPermission(ctx context.Context, name, description string) (*models.Permission, bool, error)
+	// FindOrCreateRole returns the named role, creating it when missing.
+	FindOrCreateRole(ctx context.Context, name, description string) (*models.Role, bool, error)
+	// GrantPermissions adds the permissions to the role, skipping existing grants.
+	GrantPermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) (int64, error)
+
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	CountRoleMembers(ctx context.Context, roleID uuid.UUID) (int64, error)
+	// AddUserRole gives the user the role without touching their other roles.
+	AddUserRole(ctx context.Context, userID, roleID uuid.UUID) error
+}
+
+type Service interface {
+	Sync(ctx context.Context) (*SyncResult, error)
+}
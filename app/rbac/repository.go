@@ -0,0 +1,79 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new RBAC repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) UpsertPermission(ctx context.Context, name, description string) (*models.Permission, bool, error) {
+	var permission models.Permission
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&permission).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		permission = models.Permission{Name: name, Description: description}
+		return &permission, true, r.db.WithContext(ctx).Create(&permission).Error
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if description != "" && permission.Description != description {
+		permission.Description = description
+		err = r.db.WithContext(ctx).Model(&permission).Update("description", description).Error
+	}
+	return &permission, false, err
+}
+
+func (r *repository) FindOrCreateRole(ctx context.Context, name, description string) (*models.Role, bool, error) {
+	var role models.Role
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		role = models.Role{Name: name, Description: description}
+		return &role, true, r.db.WithContext(ctx).Create(&role).Error
+	}
+	return &role, false, err
+}
+
+func (r *repository) GrantPermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) (int64, error) {
+	if len(permissionIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT ?, id FROM permissions WHERE id IN ?
+		ON CONFLICT DO NOTHING`, roleID, permissionIDs)
+	return result.RowsAffected, result.Error
+}
+
+func (r *repository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, models.ErrRecordNotFound
+	}
+	return &user, err
+}
+
+func (r *repository) CountRoleMembers(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("user_roles").Where("role_id = ?", roleID).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) AddUserRole(ctx context.Context, userID, roleID uuid.UUID) error {
+	return r.db.WithContext(ctx).Exec(
+		"INSERT INTO user_roles (user_id, role_id) VALUES (?, ?) ON CONFLICT DO NOTHING", userID, roleID,
+	).Error
+}
@@ -0,0 +1,66 @@
+package rbac
+
+import (
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/kyc"
+	"github.com/joefazee/neo/app/markets"
+	"github.com/joefazee/neo/app/prediction"
+	"github.com/joefazee/neo/app/statement"
+	"github.com/joefazee/neo/app/user"
+)
+
+// Default role names.
+const (
+	RoleSuperAdmin  = "super-admin"
+	RoleMarketAdmin = "market-admin"
+	RoleSupport     = "support"
+	RoleUser        = "user"
+)
+
+// RoleDefinition describes a role the sync guarantees exists, with at least
+// the listed permissions. Grants made later through the admin API are kept.
+type RoleDefinition struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// DefaultRoles returns the roles every environment starts with. Referencing
+// the module permission variables also makes sure their catalogues are registered.
+func DefaultRoles() []RoleDefinition {
+	return []RoleDefinition{
+		{
+			Name:        RoleSuperAdmin,
+			Description: "Unrestricted access to every permission",
+			Permissions: []string{api.PermissionWildcard},
+		},
+		{
+			Name:        RoleMarketAdmin,
+			Description: "Manages markets",
+			Permissions: []string{markets.PermissionMarketAdmin, "market:" + api.PermissionWildcard},
+		},
+		{
+			Name:        RoleSupport,
+			Description: "Customer support: account assistance and KYC review",
+			Permissions: []string{
+				user.PermissionAdminAccess,
+				user.PermissionUsersRead,
+				user.PermissionUsersUnlock,
+				user.PermissionUsersRevokeSessions,
+				user.PermissionUsersImpersonate,
+				kyc.PermissionSubmissionsRead,
+				kyc.PermissionSubmissionsReview,
+				prediction.PermissionBetLimitsRead,
+				fraud.PermissionCasesRead,
+				statement.PermissionStatementsRead,
+				statement.PermissionStatementsCreate,
+			},
+		},
+		{
+			Name:        RoleUser,
+			Description: "Regular platform user",
+			Permissions: []string{},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/models"
+)
+
+// SyncResult summarizes the changes a sync made.
+type SyncResult struct {
+	PermissionsCreated int
+	RolesCreated       int
+	GrantsAdded        int64
+	// SuperAdmin is the email promoted to super-admin, if any.
+	SuperAdmin string
+}
+
+type service struct {
+	repo   Repository
+	config *Config
+}
+
+// NewService creates an RBAC sync service.
+func NewService(repo Repository, config *Config) Service {
+	return &service{repo: repo, config: config}
+}
+
+// Sync upserts the permission catalogue and default roles, then bootstraps the
+// first super-admin. It is additive and safe to run repeatedly: nothing granted
+// through the admin API is removed.
+func (s *service) Sync(ctx context.Context) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	permissionIDs := make(map[string]uuid.UUID)
+	for _, def := range api.RegisteredPermissions() {
+		if err := s.upsertPermission(ctx, def.Name, def.Description, permissionIDs, result); err != nil {
+			return result, err
+		}
+	}
+
+	roleIDs := make(map[string]uuid.UUID)
+	for _, def := range DefaultRoles() {
+		role, created, err := s.repo.FindOrCreateRole(ctx, def.Name, def.Description)
+		if err != nil {
+			return result, fmt.Errorf("failed to sync role %s: %w", def.Name, err)
+		}
+		if created {
+			result.RolesCreated++
+		}
+		roleIDs[def.Name] = role.ID
+
+		ids := make([]uuid.UUID, 0, len(def.Permissions))
+		for _, name := range def.Permissions {
+			// Wildcard grants are not routes' requirements, so they are not in the catalogue.
+			if _, ok := permissionIDs[name]; !ok {
+				if err := s.upsertPermission(ctx, name, "Wildcard grant", permissionIDs, result); err != nil {
+					return result, err
+				}
+			}
+			ids = append(ids, permissionIDs[name])
+		}
+
+		added, err := s.repo.GrantPermissions(ctx, role.ID, ids)
+		if err != nil {
+			return result, fmt.Errorf("failed to grant permissions to %s: %w", def.Name, err)
+		}
+		result.GrantsAdded += added
+	}
+
+	if err := s.bootstrapSuperAdmin(ctx, roleIDs[RoleSuperAdmin], result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (s *service) upsertPermission(ctx context.Context,
+	name, description string,
+	ids map[string]uuid.UUID,
+	result *SyncResult) error {
+	permission, created, err := s.repo.UpsertPermission(ctx, name, description)
+	if err != nil {
+		return fmt.Errorf("failed to sync permission %s: %w", name, err)
+	}
+	if created {
+		result.PermissionsCreated++
+	}
+	ids[name] = permission.ID
+	return nil
+}
+
+// bootstrapSuperAdmin promotes the configured account, but only while nobody
+// holds the role, so the env var cannot be used to regain access later.
+func (s *service) bootstrapSuperAdmin(ctx context.Context, roleID uuid.UUID, result *SyncResult) error {
+	if s.config.SuperAdminEmail == "" {
+		return nil
+	}
+
+	members, err := s.repo.CountRoleMembers(ctx, roleID)
+	if err != nil {
+		return fmt.Errorf("failed to count super admins: %w", err)
+	}
+	if members > 0 {
+		return nil
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, s.config.SuperAdminEmail)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return fmt.Errorf("super admin %s has not registered an account yet", s.config.SuperAdminEmail)
+		}
+		return fmt.Errorf("failed to get super admin: %w", err)
+	}
+
+	if err := s.repo.AddUserRole(ctx, user.ID, roleID); err != nil {
+		return fmt.Errorf("failed to assign super admin role: %w", err)
+	}
+	result.SuperAdmin = user.Email
+	return nil
+}
@@ -0,0 +1,164 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/app/user"
+	"github.com/joefazee/neo/models"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) UpsertPermission(ctx context.Context, name, description string) (*models.Permission, bool, error) {
+	args := m.Called(ctx, name, description)
+	if fn, ok := args.Get(0).(func(context.Context, string, string) *models.Permission); ok {
+		return fn(ctx, name, description), args.Bool(1), args.Error(2)
+	}
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*models.Permission), args.Bool(1), args.Error(2)
+}
+
+func (m *MockRepository) FindOrCreateRole(ctx context.Context, name, description string) (*models.Role, bool, error) {
+	args := m.Called(ctx, name, description)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*models.Role), args.Bool(1), args.Error(2)
+}
+
+func (m *MockRepository) GrantPermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, roleID, permissionIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockRepository) CountRoleMembers(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, roleID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) AddUserRole(ctx context.Context, userID, roleID uuid.UUID) error {
+	args := m.Called(ctx, userID, roleID)
+	return args.Error(0)
+}
+
+// expectCatalogue makes every permission and default role resolvable, returning the super-admin role.
+func expectCatalogue(repo *MockRepository) *models.Role {
+	repo.On("UpsertPermission", mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, name, description string) *models.Permission {
+			return &models.Permission{ID: uuid.New(), Name: name, Description: description}
+		}, true, nil)
+
+	superAdmin := &models.Role{ID: uuid.New(), Name: RoleSuperAdmin}
+	repo.On("FindOrCreateRole", mock.Anything, RoleSuperAdmin, mock.Anything).Return(superAdmin, true, nil)
+	repo.On("FindOrCreateRole", mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.Role{ID: uuid.New()}, false, nil)
+	repo.On("GrantPermissions", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
+	return superAdmin
+}
+
+func TestSync_UpsertsCatalogueAndRoles(t *testing.T) {
+	repo := &MockRepository{}
+	expectCatalogue(repo)
+
+	result, err := NewService(repo, GetDefaultConfig()).Sync(context.Background())
+
+	assert.NoError(t, err)
+	// The catalogue plus the "*" and "market:*" wildcard grants.
+	assert.Equal(t, len(api.RegisteredPermissions())+2, result.PermissionsCreated)
+	assert.Equal(t, 1, result.RolesCreated)
+	assert.Equal(t, int64(len(DefaultRoles())), result.GrantsAdded)
+	assert.Empty(t, result.SuperAdmin)
+
+	repo.AssertCalled(t, "UpsertPermission", mock.Anything, user.PermissionUsersBulkAssign, mock.Anything)
+	repo.AssertNotCalled(t, "CountRoleMembers", mock.Anything, mock.Anything)
+}
+
+func TestSync_BootstrapsSuperAdmin(t *testing.T) {
+	repo := &MockRepository{}
+	superAdmin := expectCatalogue(repo)
+	account := &models.User{ID: uuid.New(), Email: "root@example.com"}
+
+	repo.On("CountRoleMembers", mock.Anything, superAdmin.ID).Return(int64(0), nil)
+	repo.On("GetUserByEmail", mock.Anything, "root@example.com").Return(account, nil)
+	repo.On("AddUserRole", mock.Anything, account.ID, superAdmin.ID).Return(nil)
+
+	result, err := NewService(repo, &Config{SuperAdminEmail: "root@example.com"}).Sync(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "root@example.com", result.SuperAdmin)
+	repo.AssertExpectations(t)
+}
+
+func TestSync_SkipsBootstrapWhenSuperAdminExists(t *testing.T) {
+	repo := &MockRepository{}
+	superAdmin := expectCatalogue(repo)
+
+	repo.On("CountRoleMembers", mock.Anything, superAdmin.ID).Return(int64(1), nil)
+
+	result, err := NewService(repo, &Config{SuperAdminEmail: "root@example.com"}).Sync(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.SuperAdmin)
+	repo.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
+}
+
+func TestSync_SuperAdminNotRegistered(t *testing.T) {
+	repo := &MockRepository{}
+	superAdmin := expectCatalogue(repo)
+
+	repo.On("CountRoleMembers", mock.Anything, superAdmin.ID).Return(int64(0), nil)
+	repo.On("GetUserByEmail", mock.Anything, "root@example.com").Return(nil, models.ErrRecordNotFound)
+
+	_, err := NewService(repo, &Config{SuperAdminEmail: "root@example.com"}).Sync(context.Background())
+
+	assert.ErrorContains(t, err, "has not registered")
+}
+
+func TestSync_PermissionError(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("UpsertPermission", mock.Anything, mock.Anything, mock.Anything).Return(nil, false, errors.New("db down"))
+
+	_, err := NewService(repo, GetDefaultConfig()).Sync(context.Background())
+
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "FindOrCreateRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDefaultRoles_UseKnownPermissions(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, d := range api.RegisteredPermissions() {
+		registered[d.Name] = true
+	}
+
+	for _, role := range DefaultRoles() {
+		for _, name := range role.Permissions {
+			matcher := api.NewPermissionMatcher([]string{name})
+			assert.True(t, registered[name] || matcher.Allows(user.PermissionAdminAccess) || matcher.Allows("market:admin"),
+				"%s grants unknown permission %s", role.Name, name)
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, GetDefaultConfig().Validate())
+	assert.Error(t, (&Config{SuperAdminEmail: "not-an-email"}).Validate())
+}
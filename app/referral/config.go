@@ -0,0 +1,70 @@
+package referral
+
+import (
+	"errors"
+
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// Reward is what each side of a referral earns for a milestone, credited in
+// the currency the milestone happened in. Deposits or stakes below Minimum do
+// not reach the milestone, so a token amount cannot collect the reward.
+type Reward struct {
+	Referrer decimal.Decimal
+	Referee  decimal.Decimal
+	Minimum  decimal.Decimal
+}
+
+// IsZero reports whether the reward pays nothing to either side
+func (r Reward) IsZero() bool {
+	return r.Referrer.IsZero() && r.Referee.IsZero()
+}
+
+// Config represents the configuration for the referral module
+type Config struct {
+	// Rewards maps each milestone to its reward. Milestones without a reward
+	// do not pay out.
+	Rewards map[models.ReferralMilestone]Reward
+
+	// MaxSignupsPerSource is how many referrals a referrer may collect from one
+	// IP address or device before further sign-ups from it are flagged.
+	MaxSignupsPerSource int `env:"REFERRAL_MAX_SIGNUPS_PER_SOURCE"`
+}
+
+func (c *Config) Validate() error {
+	for milestone, reward := range c.Rewards {
+		if milestone != models.ReferralMilestoneFirstDeposit && milestone != models.ReferralMilestoneFirstSettledBet {
+			return errors.New("unknown referral milestone: " + string(milestone))
+		}
+		if reward.Referrer.IsNegative() || reward.Referee.IsNegative() {
+			return errors.New("referral rewards cannot be negative")
+		}
+		if reward.Minimum.IsNegative() {
+			return errors.New("referral minimum amount cannot be negative")
+		}
+	}
+	if c.MaxSignupsPerSource < 1 {
+		return errors.New("max sign-ups per source must be at least 1")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default referral configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		Rewards: map[models.ReferralMilestone]Reward{
+			models.ReferralMilestoneFirstDeposit: {
+				Referrer: decimal.NewFromInt(500),
+				Referee:  decimal.NewFromInt(500),
+				Minimum:  decimal.NewFromInt(2000),
+			},
+			models.ReferralMilestoneFirstSettledBet: {
+				Referrer: decimal.NewFromInt(250),
+				Referee:  decimal.NewFromInt(250),
+				Minimum:  decimal.NewFromInt(1000),
+			},
+		},
+		MaxSignupsPerSource: 1,
+	}
+}
@@ -0,0 +1,30 @@
+package referral
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joefazee/neo/models"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, GetDefaultConfig().Validate())
+
+	config := GetDefaultConfig()
+	config.Rewards["first_login"] = Reward{}
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.Rewards[models.ReferralMilestoneFirstDeposit] = Reward{Referrer: decimal.NewFromInt(-1)}
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.Rewards[models.ReferralMilestoneFirstDeposit] = Reward{Minimum: decimal.NewFromInt(-1)}
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.MaxSignupsPerSource = 0
+	assert.Error(t, config.Validate())
+}
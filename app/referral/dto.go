@@ -0,0 +1,80 @@
+package referral
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// ReferralResponse represents one user the caller referred. Only the referee's
+// first name and last initial are shown.
+type ReferralResponse struct {
+	ID          uuid.UUID                  `json:"id"`
+	RefereeName string                     `json:"referee_name"`
+	Status      models.ReferralStatus      `json:"status"`
+	Milestones  []models.ReferralMilestone `json:"milestones"`
+	Earned      []EarningResponse          `json:"earned"`
+	JoinedAt    time.Time                  `json:"joined_at"`
+}
+
+// EarningResponse is a reward total in one currency
+type EarningResponse struct {
+	CurrencyCode string          `json:"currency_code"`
+	Amount       decimal.Decimal `json:"amount"`
+}
+
+// DashboardResponse summarises the caller's referral activity
+type DashboardResponse struct {
+	ReferralCode   string             `json:"referral_code"`
+	TotalReferrals int                `json:"total_referrals"`
+	ActiveCount    int                `json:"active_referrals"`
+	FlaggedCount   int                `json:"flagged_referrals"`
+	TotalEarned    []EarningResponse  `json:"total_earned"`
+	Referrals      []ReferralResponse `json:"referrals"`
+}
+
+// ToReferralResponse converts a referral with the referrer's rewards preloaded
+func ToReferralResponse(referral *models.Referral) *ReferralResponse {
+	resp := &ReferralResponse{
+		ID:         referral.ID,
+		Status:     referral.Status,
+		Milestones: make([]models.ReferralMilestone, 0, len(referral.Rewards)),
+		Earned:     SumEarnings(referral.Rewards),
+		JoinedAt:   referral.CreatedAt,
+	}
+	if referral.Referee != nil {
+		resp.RefereeName = displayName(referral.Referee)
+	}
+	for i := range referral.Rewards {
+		resp.Milestones = append(resp.Milestones, referral.Rewards[i].Milestone)
+	}
+	return resp
+}
+
+// SumEarnings totals rewards per currency, ordered by currency code
+func SumEarnings(rewards []models.ReferralReward) []EarningResponse {
+	totals := make(map[string]decimal.Decimal)
+	for i := range rewards {
+		totals[rewards[i].CurrencyCode] = totals[rewards[i].CurrencyCode].Add(rewards[i].Amount)
+	}
+
+	earnings := make([]EarningResponse, 0, len(totals))
+	for currency, amount := range totals {
+		earnings = append(earnings, EarningResponse{CurrencyCode: currency, Amount: amount})
+	}
+	sort.Slice(earnings, func(i, j int) bool {
+		return earnings[i].CurrencyCode < earnings[j].CurrencyCode
+	})
+	return earnings
+}
+
+func displayName(user *models.User) string {
+	lastName := []rune(user.LastName)
+	if len(lastName) == 0 {
+		return user.FirstName
+	}
+	return user.FirstName + " " + string(lastName[0]) + "."
+}
@@ -0,0 +1,47 @@
+package referral
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+)
+
+// Handler handles HTTP requests for referrals
+type Handler struct {
+	service Service
+	logger  logger.Logger
+}
+
+// NewHandler creates a new referral handler
+func NewHandler(service Service, lg logger.Logger) *Handler {
+	return &Handler{service: service, logger: lg}
+}
+
+// GetDashboard godoc
+// @Summary      Get referral dashboard
+// @Description  Get the authenticated user's referral code, referred users and rewards earned
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=DashboardResponse}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/referrals [get]
+func (h *Handler) GetDashboard(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	dashboard, err := h.service.GetDashboard(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetDashboard", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to retrieve referrals")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Referrals retrieved successfully", dashboard)
+}
@@ -0,0 +1,38 @@
+package referral
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "referral_repository"
+	ServiceKey = "referral_service"
+)
+
+// MountAuthenticated mounts the referral dashboard
+func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	userGroup := r.Group("/users")
+	userGroup.GET("/referrals", handler.GetDashboard)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid referral configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+	container.RegisterService(ServiceKey, NewService(repo, config, container.Logger))
+}
+
+// createHandler creates a referral handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+
+	return NewHandler(service, container.Logger)
+}
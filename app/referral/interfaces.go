@@ -0,0 +1,62 @@
+package referral
+
+import (
+	"context"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+type Repository interface {
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	GetUserByReferralCode(ctx context.Context, code string) (*models.User, error)
+	ReferralCodeExists(ctx context.Context, code string) (bool, error)
+	SetReferralCode(ctx context.Context, userID uuid.UUID, code string) error
+
+	CreateReferral(ctx context.Context, referral *models.Referral) error
+	GetReferralByReferee(ctx context.Context, refereeID uuid.UUID) (*models.Referral, error)
+	GetReferralsByReferrer(ctx context.Context, referrerID uuid.UUID) ([]models.Referral, error)
+	CountReferralsFromSource(ctx context.Context, referrerID uuid.UUID, ip net.IP, deviceID string) (int64, error)
+
+	HasReward(ctx context.Context, referralID, userID uuid.UUID, milestone models.ReferralMilestone) (bool, error)
+	CreditReward(ctx context.Context, reward *models.ReferralReward) error
+	GetRewardsByUser(ctx context.Context, userID uuid.UUID) ([]models.ReferralReward, error)
+}
+
+// Signup describes where a new user registered from, for abuse checks
+type Signup struct {
+	IPAddress string
+	DeviceID  string
+}
+
+// Enroller gives new users a referral code and attributes them to the user
+// whose code they signed up with. The user module calls it on registration.
+type Enroller interface {
+	// PrepareSignup assigns the user a code and resolves the code they entered.
+	// It runs before the user is created so an unknown code rejects the sign-up.
+	PrepareSignup(ctx context.Context, user *models.User, referralCode string) error
+	// RecordSignup attributes a newly created user to their referrer. The
+	// account already exists by then, so failures are logged rather than
+	// returned and a lost referral never fails the sign-up.
+	RecordSignup(ctx context.Context, user *models.User, signup Signup)
+}
+
+// Tracker pays referral rewards when a referred user reaches a milestone.
+// amount is the deposit or stake that reached it. Failures are logged rather
+// than returned so they never undo the action that reached the milestone.
+type Tracker interface {
+	RecordMilestone(ctx context.Context,
+		userID uuid.UUID,
+		milestone models.ReferralMilestone,
+		currencyCode string,
+		amount decimal.Decimal)
+}
+
+type Service interface {
+	Enroller
+	Tracker
+
+	GetDashboard(ctx context.Context, userID uuid.UUID) (*DashboardResponse, error)
+}
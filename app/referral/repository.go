@@ -0,0 +1,177 @@
+package referral
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new referral repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *repository) GetUserByReferralCode(ctx context.Context, code string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).
+		Where("metadata ->> 'referral_code' = ?", code).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *repository) ReferralCodeExists(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("metadata ->> 'referral_code' = ?", code).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SetReferralCode stores a code for a user created before referral codes existed.
+func (r *repository) SetReferralCode(ctx context.Context, userID uuid.UUID, code string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("metadata", gorm.Expr("jsonb_set(COALESCE(metadata, '{}'::jsonb), '{referral_code}', to_jsonb(?::text))", code)).
+		Error
+}
+
+func (r *repository) CreateReferral(ctx context.Context, referral *models.Referral) error {
+	return r.db.WithContext(ctx).Create(referral).Error
+}
+
+func (r *repository) GetReferralByReferee(ctx context.Context, refereeID uuid.UUID) (*models.Referral, error) {
+	var referral models.Referral
+	if err := r.db.WithContext(ctx).First(&referral, "referee_id = ?", refereeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &referral, nil
+}
+
+func (r *repository) GetReferralsByReferrer(ctx context.Context, referrerID uuid.UUID) ([]models.Referral, error) {
+	var referrals []models.Referral
+	err := r.db.WithContext(ctx).
+		Preload("Referee").
+		Preload("Rewards", "user_id = ?", referrerID).
+		Where("referrer_id = ?", referrerID).
+		Order("created_at DESC").
+		Find(&referrals).Error
+	return referrals, err
+}
+
+// CountReferralsFromSource counts a referrer's referrals that signed up from
+// the given IP address or device.
+func (r *repository) CountReferralsFromSource(ctx context.Context, referrerID uuid.UUID, ip net.IP, deviceID string) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Referral{}).Where("referrer_id = ?", referrerID)
+
+	source := r.db.Where("1 = 0")
+	if ip != nil {
+		source = source.Or("signup_ip = ?", ip.String())
+	}
+	if deviceID != "" {
+		source = source.Or("device_id = ?", deviceID)
+	}
+
+	var count int64
+	err := query.Where(source).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) HasReward(ctx context.Context, referralID, userID uuid.UUID, milestone models.ReferralMilestone) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.ReferralReward{}).
+		Where("referral_id = ? AND user_id = ? AND milestone = ?", referralID, userID, milestone).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreditReward pays a reward into the user's wallet for its currency, opening
+// the wallet if needed, and records the ledger entry, reward and audit log
+// in one transaction.
+func (r *repository) CreditReward(ctx context.Context, reward *models.ReferralReward) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wallet models.Wallet
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND currency_code = ?", reward.UserID, reward.CurrencyCode).
+			First(&wallet).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			wallet = models.Wallet{UserID: reward.UserID, CurrencyCode: reward.CurrencyCode}
+			if err := tx.Create(&wallet).Error; err != nil {
+				return fmt.Errorf("failed to create wallet: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("failed to get wallet: %w", err)
+		}
+
+		if !wallet.IsOperationAllowed() {
+			return errors.New("wallet is locked")
+		}
+
+		balanceBefore := wallet.Balance
+		oldValues := wallet.AuditValues()
+		if err := wallet.Credit(reward.Amount); err != nil {
+			return fmt.Errorf("failed to credit wallet: %w", err)
+		}
+		if err := tx.Save(&wallet).Error; err != nil {
+			return fmt.Errorf("failed to update wallet: %w", err)
+		}
+
+		transaction := models.CreateReferralBonusTransaction(reward.UserID, wallet.ID, reward.Amount, balanceBefore, reward.ReferralID)
+		if err := tx.Create(transaction).Error; err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		reward.TransactionID = transaction.ID
+		if err := tx.Create(reward).Error; err != nil {
+			return fmt.Errorf("failed to create reward: %w", err)
+		}
+
+		newValues := wallet.AuditValues()
+		newValues["referral_id"] = reward.ReferralID.String()
+		newValues["milestone"] = string(reward.Milestone)
+		log := models.CreateSystemAuditLog(models.AuditActionWalletCredited, models.AuditResourceWallet, &wallet.ID, oldValues, newValues)
+		return tx.Create(log).Error
+	})
+}
+
+func (r *repository) GetRewardsByUser(ctx context.Context, userID uuid.UUID) ([]models.ReferralReward, error) {
+	var rewards []models.ReferralReward
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&rewards).Error
+	return rewards, err
+}
@@ -0,0 +1,252 @@
+package referral
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// maxCodeAttempts bounds retries when a generated code is already taken
+const maxCodeAttempts = 5
+
+type service struct {
+	repo   Repository
+	config *Config
+	logger logger.Logger
+}
+
+// NewService creates a new referral service.
+func NewService(repo Repository, config *Config, lg logger.Logger) Service {
+	return &service{repo: repo, config: config, logger: lg}
+}
+
+func (s *service) PrepareSignup(ctx context.Context, user *models.User, referralCode string) error {
+	if user.Metadata == nil {
+		user.Metadata = &models.UserMetadata{}
+	}
+
+	if referralCode != "" {
+		code := models.NormalizeReferralCode(referralCode)
+		if _, err := s.repo.GetUserByReferralCode(ctx, code); err != nil {
+			if errors.Is(err, models.ErrRecordNotFound) {
+				return models.ErrInvalidReferralCode
+			}
+			return fmt.Errorf("failed to resolve referral code: %w", err)
+		}
+		user.Metadata.ReferredBy = code
+	}
+
+	code, err := s.newCode(ctx)
+	if err != nil {
+		return err
+	}
+	user.Metadata.ReferralCode = code
+	return nil
+}
+
+func (s *service) RecordSignup(ctx context.Context, user *models.User, signup Signup) {
+	if err := s.recordSignup(ctx, user, signup); err != nil {
+		s.logger.Error(err, logger.Fields{"component": "referral", "user_id": user.ID})
+	}
+}
+
+// recordSignup links the user to their referrer. Sign-ups that share an IP
+// address or device with the referrer, or with too many of the referrer's
+// other referrals, are flagged and earn no rewards.
+func (s *service) recordSignup(ctx context.Context, user *models.User, signup Signup) error {
+	if user.Metadata == nil || user.Metadata.ReferredBy == "" {
+		return nil
+	}
+
+	referrer, err := s.repo.GetUserByReferralCode(ctx, user.Metadata.ReferredBy)
+	if err != nil {
+		return fmt.Errorf("failed to resolve referrer: %w", err)
+	}
+
+	referral := &models.Referral{
+		ReferrerID: referrer.ID,
+		RefereeID:  user.ID,
+		Code:       user.Metadata.ReferredBy,
+		Status:     models.ReferralStatusActive,
+		SignupIP:   net.ParseIP(signup.IPAddress),
+		DeviceID:   signup.DeviceID,
+	}
+
+	reason, err := s.abuseReason(ctx, referrer, referral)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		referral.Flag(reason)
+	}
+
+	if err := s.repo.CreateReferral(ctx, referral); err != nil {
+		return fmt.Errorf("failed to create referral: %w", err)
+	}
+	return nil
+}
+
+// abuseReason returns why the referral looks like a self-referral, or "" if it does not.
+func (s *service) abuseReason(ctx context.Context, referrer *models.User, referral *models.Referral) (string, error) {
+	if referral.SignupIP != nil && referral.SignupIP.Equal(referrer.LastLoginIP) {
+		return "signed up from the referrer's IP address", nil
+	}
+
+	// The referrer may have been referred themselves, which records their own device
+	own, err := s.repo.GetReferralByReferee(ctx, referrer.ID)
+	if err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		return "", fmt.Errorf("failed to get referrer's referral: %w", err)
+	}
+	if own != nil {
+		if referral.SignupIP != nil && referral.SignupIP.Equal(own.SignupIP) {
+			return "signed up from the referrer's IP address", nil
+		}
+		if referral.DeviceID != "" && referral.DeviceID == own.DeviceID {
+			return "signed up from the referrer's device", nil
+		}
+	}
+
+	if referral.SignupIP == nil && referral.DeviceID == "" {
+		return "", nil
+	}
+	count, err := s.repo.CountReferralsFromSource(ctx, referrer.ID, referral.SignupIP, referral.DeviceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to count referrals from source: %w", err)
+	}
+	if count >= int64(s.config.MaxSignupsPerSource) {
+		return "too many referrals from the same IP address or device", nil
+	}
+	return "", nil
+}
+
+func (s *service) RecordMilestone(ctx context.Context,
+	userID uuid.UUID,
+	milestone models.ReferralMilestone,
+	currencyCode string,
+	amount decimal.Decimal) {
+	if err := s.recordMilestone(ctx, userID, milestone, currencyCode, amount); err != nil {
+		s.logger.Error(err, logger.Fields{"component": "referral", "user_id": userID, "milestone": milestone})
+	}
+}
+
+func (s *service) recordMilestone(ctx context.Context,
+	userID uuid.UUID,
+	milestone models.ReferralMilestone,
+	currencyCode string,
+	amount decimal.Decimal) error {
+	reward, ok := s.config.Rewards[milestone]
+	if !ok || reward.IsZero() || amount.LessThan(reward.Minimum) {
+		return nil
+	}
+
+	referral, err := s.repo.GetReferralByReferee(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get referral: %w", err)
+	}
+	if !referral.IsRewardable() {
+		return nil
+	}
+
+	parties := []struct {
+		userID uuid.UUID
+		amount decimal.Decimal
+	}{
+		{referral.ReferrerID, reward.Referrer},
+		{referral.RefereeID, reward.Referee},
+	}
+	for _, party := range parties {
+		if !party.amount.IsPositive() {
+			continue
+		}
+		rewarded, err := s.repo.HasReward(ctx, referral.ID, party.userID, milestone)
+		if err != nil {
+			return fmt.Errorf("failed to check reward: %w", err)
+		}
+		if rewarded {
+			continue
+		}
+		err = s.repo.CreditReward(ctx, &models.ReferralReward{
+			ReferralID:   referral.ID,
+			UserID:       party.userID,
+			Milestone:    milestone,
+			Amount:       party.amount,
+			CurrencyCode: currencyCode,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to credit referral reward: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *service) GetDashboard(ctx context.Context, userID uuid.UUID) (*DashboardResponse, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	code := ""
+	if user.Metadata != nil {
+		code = user.Metadata.ReferralCode
+	}
+	if code == "" {
+		// Users who registered before referral codes existed get one on first visit
+		if code, err = s.newCode(ctx); err != nil {
+			return nil, err
+		}
+		if err := s.repo.SetReferralCode(ctx, userID, code); err != nil {
+			return nil, fmt.Errorf("failed to save referral code: %w", err)
+		}
+	}
+
+	referrals, err := s.repo.GetReferralsByReferrer(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referrals: %w", err)
+	}
+	rewards, err := s.repo.GetRewardsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rewards: %w", err)
+	}
+
+	dashboard := &DashboardResponse{
+		ReferralCode:   code,
+		TotalReferrals: len(referrals),
+		TotalEarned:    SumEarnings(rewards),
+		Referrals:      make([]ReferralResponse, 0, len(referrals)),
+	}
+	for i := range referrals {
+		if referrals[i].IsRewardable() {
+			dashboard.ActiveCount++
+		} else {
+			dashboard.FlaggedCount++
+		}
+		dashboard.Referrals = append(dashboard.Referrals, *ToReferralResponse(&referrals[i]))
+	}
+	return dashboard, nil
+}
+
+func (s *service) newCode(ctx context.Context) (string, error) {
+	for i := 0; i < maxCodeAttempts; i++ {
+		code, err := models.GenerateReferralCode()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate referral code: %w", err)
+		}
+		exists, err := s.repo.ReferralCodeExists(ctx, code)
+		if err != nil {
+			return "", fmt.Errorf("failed to check referral code: %w", err)
+		}
+		if !exists {
+			return code, nil
+		}
+	}
+	return "", errors.New("failed to generate a unique referral code")
+}
@@ -0,0 +1,299 @@
+package referral
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if u := args.Get(0); u != nil {
+		return u.(*models.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetUserByReferralCode(ctx context.Context, code string) (*models.User, error) {
+	args := m.Called(ctx, code)
+	if u := args.Get(0); u != nil {
+		return u.(*models.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) ReferralCodeExists(ctx context.Context, code string) (bool, error) {
+	args := m.Called(ctx, code)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) SetReferralCode(ctx context.Context, userID uuid.UUID, code string) error {
+	return m.Called(ctx, userID, code).Error(0)
+}
+
+func (m *MockRepository) CreateReferral(ctx context.Context, referral *models.Referral) error {
+	return m.Called(ctx, referral).Error(0)
+}
+
+func (m *MockRepository) GetReferralByReferee(ctx context.Context, refereeID uuid.UUID) (*models.Referral, error) {
+	args := m.Called(ctx, refereeID)
+	if r := args.Get(0); r != nil {
+		return r.(*models.Referral), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetReferralsByReferrer(ctx context.Context, referrerID uuid.UUID) ([]models.Referral, error) {
+	args := m.Called(ctx, referrerID)
+	if r := args.Get(0); r != nil {
+		return r.([]models.Referral), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) CountReferralsFromSource(ctx context.Context, referrerID uuid.UUID, ip net.IP, deviceID string) (int64, error) {
+	args := m.Called(ctx, referrerID, ip, deviceID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) HasReward(ctx context.Context, referralID, userID uuid.UUID, milestone models.ReferralMilestone) (bool, error) {
+	args := m.Called(ctx, referralID, userID, milestone)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CreditReward(ctx context.Context, reward *models.ReferralReward) error {
+	return m.Called(ctx, reward).Error(0)
+}
+
+func (m *MockRepository) GetRewardsByUser(ctx context.Context, userID uuid.UUID) ([]models.ReferralReward, error) {
+	args := m.Called(ctx, userID)
+	if r := args.Get(0); r != nil {
+		return r.([]models.ReferralReward), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func newTestService(repo *MockRepository) Service {
+	return NewService(repo, GetDefaultConfig(), logger.NewNullLogger())
+}
+
+func TestService_PrepareSignup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("assigns a code and resolves the referrer", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByReferralCode", ctx, "ABCD2345").Return(&models.User{ID: uuid.New()}, nil)
+		repo.On("ReferralCodeExists", ctx, mock.AnythingOfType("string")).Return(false, nil)
+
+		user := &models.User{}
+		err := newTestService(repo).PrepareSignup(ctx, user, " abcd2345")
+		require.NoError(t, err)
+		assert.Len(t, user.Metadata.ReferralCode, models.ReferralCodeLength)
+		assert.Equal(t, "ABCD2345", user.Metadata.ReferredBy)
+	})
+
+	t.Run("retries a taken code", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("ReferralCodeExists", ctx, mock.Anything).Return(true, nil).Once()
+		repo.On("ReferralCodeExists", ctx, mock.Anything).Return(false, nil).Once()
+
+		user := &models.User{}
+		require.NoError(t, newTestService(repo).PrepareSignup(ctx, user, ""))
+		assert.NotEmpty(t, user.Metadata.ReferralCode)
+		assert.Empty(t, user.Metadata.ReferredBy)
+		repo.AssertNumberOfCalls(t, "ReferralCodeExists", 2)
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByReferralCode", ctx, "NOPE").Return(nil, models.ErrRecordNotFound)
+
+		err := newTestService(repo).PrepareSignup(ctx, &models.User{}, "nope")
+		assert.ErrorIs(t, err, models.ErrInvalidReferralCode)
+	})
+}
+
+func TestService_RecordSignup(t *testing.T) {
+	ctx := context.Background()
+	referrer := &models.User{ID: uuid.New(), LastLoginIP: net.ParseIP("198.51.100.1")}
+	newReferee := func() *models.User {
+		return &models.User{ID: uuid.New(), Metadata: &models.UserMetadata{ReferredBy: "ABCD2345"}}
+	}
+
+	t.Run("records an active referral", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByReferralCode", ctx, "ABCD2345").Return(referrer, nil)
+		repo.On("GetReferralByReferee", ctx, referrer.ID).Return(nil, models.ErrRecordNotFound)
+		repo.On("CountReferralsFromSource", ctx, referrer.ID, mock.Anything, "device-2").Return(int64(0), nil)
+		repo.On("CreateReferral", ctx, mock.MatchedBy(func(r *models.Referral) bool {
+			return r.ReferrerID == referrer.ID && r.Status == models.ReferralStatusActive && r.Code == "ABCD2345"
+		})).Return(nil)
+
+		newTestService(repo).RecordSignup(ctx, newReferee(), Signup{IPAddress: "203.0.113.9", DeviceID: "device-2"})
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("flags sign-up from the referrer's IP", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByReferralCode", ctx, "ABCD2345").Return(referrer, nil)
+		repo.On("CreateReferral", ctx, mock.MatchedBy(func(r *models.Referral) bool {
+			return r.Status == models.ReferralStatusFlagged && r.FlagReason != ""
+		})).Return(nil)
+
+		newTestService(repo).RecordSignup(ctx, newReferee(), Signup{IPAddress: "198.51.100.1"})
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("flags sign-up from the referrer's device", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByReferralCode", ctx, "ABCD2345").Return(referrer, nil)
+		repo.On("GetReferralByReferee", ctx, referrer.ID).Return(&models.Referral{DeviceID: "device-1"}, nil)
+		repo.On("CreateReferral", ctx, mock.MatchedBy(func(r *models.Referral) bool {
+			return r.Status == models.ReferralStatusFlagged
+		})).Return(nil)
+
+		newTestService(repo).RecordSignup(ctx, newReferee(), Signup{IPAddress: "203.0.113.9", DeviceID: "device-1"})
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("flags repeat sign-ups from one source", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByReferralCode", ctx, "ABCD2345").Return(referrer, nil)
+		repo.On("GetReferralByReferee", ctx, referrer.ID).Return(nil, models.ErrRecordNotFound)
+		repo.On("CountReferralsFromSource", ctx, referrer.ID, mock.Anything, "").Return(int64(1), nil)
+		repo.On("CreateReferral", ctx, mock.MatchedBy(func(r *models.Referral) bool {
+			return r.Status == models.ReferralStatusFlagged
+		})).Return(nil)
+
+		newTestService(repo).RecordSignup(ctx, newReferee(), Signup{IPAddress: "203.0.113.9"})
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("no referral code", func(t *testing.T) {
+		repo := new(MockRepository)
+		newTestService(repo).RecordSignup(ctx, &models.User{ID: uuid.New()}, Signup{})
+		repo.AssertNotCalled(t, "CreateReferral", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_RecordMilestone(t *testing.T) {
+	ctx := context.Background()
+	referral := &models.Referral{ID: uuid.New(), ReferrerID: uuid.New(), RefereeID: uuid.New(), Status: models.ReferralStatusActive}
+
+	t.Run("rewards both parties once", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetReferralByReferee", ctx, referral.RefereeID).Return(referral, nil)
+		repo.On("HasReward", ctx, referral.ID, referral.ReferrerID, models.ReferralMilestoneFirstDeposit).Return(false, nil)
+		repo.On("HasReward", ctx, referral.ID, referral.RefereeID, models.ReferralMilestoneFirstDeposit).Return(true, nil)
+		repo.On("CreditReward", ctx, mock.MatchedBy(func(r *models.ReferralReward) bool {
+			return r.UserID == referral.ReferrerID && r.Amount.Equal(decimal.NewFromInt(500)) && r.CurrencyCode == "NGN"
+		})).Return(nil).Once()
+
+		newTestService(repo).RecordMilestone(ctx, referral.RefereeID, models.ReferralMilestoneFirstDeposit, "NGN", decimal.NewFromInt(5000))
+		repo.AssertExpectations(t)
+		repo.AssertNumberOfCalls(t, "CreditReward", 1)
+	})
+
+	t.Run("flagged referral earns nothing", func(t *testing.T) {
+		flagged := *referral
+		flagged.Status = models.ReferralStatusFlagged
+		repo := new(MockRepository)
+		repo.On("GetReferralByReferee", ctx, referral.RefereeID).Return(&flagged, nil)
+
+		newTestService(repo).RecordMilestone(ctx, referral.RefereeID, models.ReferralMilestoneFirstDeposit, "NGN", decimal.NewFromInt(5000))
+		repo.AssertNotCalled(t, "CreditReward", mock.Anything, mock.Anything)
+	})
+
+	t.Run("user was not referred", func(t *testing.T) {
+		repo := new(MockRepository)
+		userID := uuid.New()
+		repo.On("GetReferralByReferee", ctx, userID).Return(nil, models.ErrRecordNotFound)
+
+		newTestService(repo).RecordMilestone(ctx, userID, models.ReferralMilestoneFirstSettledBet, "NGN", decimal.NewFromInt(5000))
+		repo.AssertNotCalled(t, "CreditReward", mock.Anything, mock.Anything)
+	})
+
+	t.Run("deposit below the minimum earns nothing", func(t *testing.T) {
+		repo := new(MockRepository)
+		minimum := GetDefaultConfig().Rewards[models.ReferralMilestoneFirstDeposit].Minimum
+
+		newTestService(repo).RecordMilestone(ctx, referral.RefereeID, models.ReferralMilestoneFirstDeposit, "NGN",
+			minimum.Sub(decimal.NewFromInt(1)))
+		repo.AssertNotCalled(t, "GetReferralByReferee", mock.Anything, mock.Anything)
+	})
+
+	t.Run("milestone without a reward", func(t *testing.T) {
+		repo := new(MockRepository)
+		config := GetDefaultConfig()
+		delete(config.Rewards, models.ReferralMilestoneFirstDeposit)
+
+		NewService(repo, config, logger.NewNullLogger()).
+			RecordMilestone(ctx, referral.RefereeID, models.ReferralMilestoneFirstDeposit, "NGN", decimal.NewFromInt(5000))
+		repo.AssertNotCalled(t, "GetReferralByReferee", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_GetDashboard(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("summarises referrals and earnings", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByID", ctx, userID).Return(&models.User{ID: userID, Metadata: &models.UserMetadata{ReferralCode: "ABCD2345"}}, nil)
+		repo.On("GetReferralsByReferrer", ctx, userID).Return([]models.Referral{
+			{
+				Status:    models.ReferralStatusActive,
+				Referee:   &models.User{FirstName: "Ada", LastName: "Obi"},
+				Rewards:   []models.ReferralReward{{Milestone: models.ReferralMilestoneFirstDeposit, Amount: decimal.NewFromInt(500), CurrencyCode: "NGN"}},
+				CreatedAt: time.Now(),
+			},
+			{Status: models.ReferralStatusFlagged, Referee: &models.User{FirstName: "Bo"}},
+		}, nil)
+		repo.On("GetRewardsByUser", ctx, userID).Return([]models.ReferralReward{
+			{Amount: decimal.NewFromInt(500), CurrencyCode: "NGN"},
+			{Amount: decimal.NewFromInt(250), CurrencyCode: "NGN"},
+			{Amount: decimal.NewFromInt(5), CurrencyCode: "USD"},
+		}, nil)
+
+		dashboard, err := newTestService(repo).GetDashboard(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "ABCD2345", dashboard.ReferralCode)
+		assert.Equal(t, 2, dashboard.TotalReferrals)
+		assert.Equal(t, 1, dashboard.ActiveCount)
+		assert.Equal(t, 1, dashboard.FlaggedCount)
+		require.Len(t, dashboard.TotalEarned, 2)
+		assert.Equal(t, "NGN", dashboard.TotalEarned[0].CurrencyCode)
+		assert.True(t, dashboard.TotalEarned[0].Amount.Equal(decimal.NewFromInt(750)))
+		assert.Equal(t, "Ada O.", dashboard.Referrals[0].RefereeName)
+		assert.Equal(t, []models.ReferralMilestone{models.ReferralMilestoneFirstDeposit}, dashboard.Referrals[0].Milestones)
+		assert.Equal(t, "Bo", dashboard.Referrals[1].RefereeName)
+	})
+
+	t.Run("assigns a code to existing users", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetUserByID", ctx, userID).Return(&models.User{ID: userID}, nil)
+		repo.On("ReferralCodeExists", ctx, mock.Anything).Return(false, nil)
+		repo.On("SetReferralCode", ctx, userID, mock.AnythingOfType("string")).Return(nil)
+		repo.On("GetReferralsByReferrer", ctx, userID).Return([]models.Referral{}, nil)
+		repo.On("GetRewardsByUser", ctx, userID).Return([]models.ReferralReward{}, nil)
+
+		dashboard, err := newTestService(repo).GetDashboard(ctx, userID)
+		require.NoError(t, err)
+		assert.Len(t, dashboard.ReferralCode, models.ReferralCodeLength)
+		repo.AssertExpectations(t)
+	})
+}
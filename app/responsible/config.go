@@ -0,0 +1,44 @@
+package responsible
+
+import (
+	"errors"
+	"time"
+)
+
+// Config represents the configuration for the responsible gambling module
+type Config struct {
+	// LimitIncreaseDelay is how long a raised or removed limit waits before it
+	// applies. Decreases always apply immediately.
+	LimitIncreaseDelay      time.Duration `env:"RG_LIMIT_INCREASE_DELAY"`
+	MaxTimeOutDays          int           `env:"RG_MAX_TIME_OUT_DAYS"`
+	SelfExclusionMonths     int           `env:"RG_SELF_EXCLUSION_MONTHS"`
+	MinRealityCheckInterval time.Duration `env:"RG_MIN_REALITY_CHECK_INTERVAL"`
+	MaxRealityCheckInterval time.Duration `env:"RG_MAX_REALITY_CHECK_INTERVAL"`
+}
+
+func (c *Config) Validate() error {
+	if c.LimitIncreaseDelay <= 0 {
+		return errors.New("limit increase delay must be positive")
+	}
+	if c.MaxTimeOutDays < 1 {
+		return errors.New("maximum time-out must be at least one day")
+	}
+	if c.SelfExclusionMonths < 6 {
+		return errors.New("self-exclusion must last at least 6 months")
+	}
+	if c.MinRealityCheckInterval <= 0 || c.MaxRealityCheckInterval < c.MinRealityCheckInterval {
+		return errors.New("invalid reality check interval range")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default responsible gambling configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		LimitIncreaseDelay:      24 * time.Hour,
+		MaxTimeOutDays:          42, // six weeks
+		SelfExclusionMonths:     6,
+		MinRealityCheckInterval: 15 * time.Minute,
+		MaxRealityCheckInterval: 4 * time.Hour,
+	}
+}
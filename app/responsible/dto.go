@@ -0,0 +1,162 @@
+package responsible
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+// SetLimitRequest sets or changes one of the user's own limits.
+type SetLimitRequest struct {
+	LimitType string          `json:"limit_type"`
+	Period    string          `json:"period"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// Validate sanitizes and checks the limit.
+func (r *SetLimitRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	r.LimitType = s.StripHTML(r.LimitType)
+	r.Period = s.StripHTML(r.Period)
+
+	v.Check(ValidLimitType(r.LimitType), "limit_type", "limit type must be one of deposit, stake or loss")
+	v.Check(ValidLimitPeriod(r.Period), "period", "period must be one of daily, weekly or monthly")
+	v.Check(r.Amount.GreaterThan(decimal.Zero), "amount", "amount must be greater than zero")
+	v.Check(r.Amount.Equal(r.Amount.Round(2)), "amount", "amount must not have more than 2 decimal places")
+}
+
+// ValidLimitType reports whether value names a limit type.
+func ValidLimitType(value string) bool {
+	return validator.In(models.PlayerLimitType(value),
+		models.PlayerLimitDeposit,
+		models.PlayerLimitStake,
+		models.PlayerLimitLoss)
+}
+
+// ValidLimitPeriod reports whether value names a limit period.
+func ValidLimitPeriod(value string) bool {
+	return validator.In(models.PlayerLimitPeriod(value),
+		models.PlayerLimitDaily,
+		models.PlayerLimitWeekly,
+		models.PlayerLimitMonthly)
+}
+
+// ExclusionRequest starts a time-out or a self-exclusion. Time-outs last Days
+// days; self-exclusions last the configured number of months unless Permanent.
+type ExclusionRequest struct {
+	ExclusionType string `json:"exclusion_type"`
+	Days          int    `json:"days"`
+	Permanent     bool   `json:"permanent"`
+	Reason        string `json:"reason"`
+}
+
+// Validate sanitizes and checks the exclusion. maxTimeOutDays bounds a time-out.
+func (r *ExclusionRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer, maxTimeOutDays int) {
+	r.ExclusionType = s.StripHTML(r.ExclusionType)
+	r.Reason = s.StripHTML(r.Reason)
+
+	switch models.PlayerExclusionType(r.ExclusionType) {
+	case models.PlayerExclusionTimeOut:
+		v.Check(r.Days >= 1 && r.Days <= maxTimeOutDays, "days", "a time-out must last between 1 day and the maximum allowed")
+		v.Check(!r.Permanent, "permanent", "a time-out cannot be permanent")
+	case models.PlayerExclusionSelfExclusion:
+		v.Check(r.Days == 0, "days", "self-exclusion lasts a fixed period; set permanent for an indefinite one")
+	default:
+		v.AddError("exclusion_type", "exclusion type must be either time_out or self_exclusion")
+	}
+	v.Check(validator.MaxRunes(r.Reason, 500), "reason", "reason must not be more than 500 characters")
+}
+
+// RealityCheckRequest sets how often the user is reminded of their session.
+type RealityCheckRequest struct {
+	IntervalMinutes int `json:"interval_minutes"`
+}
+
+// Validate checks the interval; zero turns reminders off.
+func (r *RealityCheckRequest) Validate(v *validator.Validator, config *Config) {
+	if r.IntervalMinutes == 0 {
+		return
+	}
+	interval := time.Duration(r.IntervalMinutes) * time.Minute
+	v.Check(interval >= config.MinRealityCheckInterval && interval <= config.MaxRealityCheckInterval,
+		"interval_minutes", "interval must be 0 or within the allowed range")
+}
+
+// LimitResponse represents a limit with its usage in the current window.
+type LimitResponse struct {
+	ID             uuid.UUID                `json:"id"`
+	LimitType      models.PlayerLimitType   `json:"limit_type"`
+	Period         models.PlayerLimitPeriod `json:"period"`
+	Amount         decimal.Decimal          `json:"amount"`
+	Used           decimal.Decimal          `json:"used"`
+	Remaining      decimal.Decimal          `json:"remaining"`
+	PendingAmount  *decimal.Decimal         `json:"pending_amount,omitempty"`
+	PendingRemoval bool                     `json:"pending_removal"`
+	PendingFrom    *time.Time               `json:"pending_from,omitempty"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+}
+
+// ExclusionResponse represents a time-out or self-exclusion.
+type ExclusionResponse struct {
+	ID            uuid.UUID                  `json:"id"`
+	ExclusionType models.PlayerExclusionType `json:"exclusion_type"`
+	StartsAt      time.Time                  `json:"starts_at"`
+	EndsAt        *time.Time                 `json:"ends_at,omitempty"`
+	Permanent     bool                       `json:"permanent"`
+}
+
+// RealityCheckResponse tells the client when to remind the user and what to show them.
+type RealityCheckResponse struct {
+	IntervalMinutes  int             `json:"interval_minutes"`
+	SessionStartedAt time.Time       `json:"session_started_at"`
+	NextDueAt        *time.Time      `json:"next_due_at,omitempty"`
+	Due              bool            `json:"due"`
+	Staked           decimal.Decimal `json:"staked"`
+	NetResult        decimal.Decimal `json:"net_result"`
+}
+
+// SettingsResponse is the user's full set of player-protection controls.
+type SettingsResponse struct {
+	Limits          []LimitResponse    `json:"limits"`
+	ActiveExclusion *ExclusionResponse `json:"active_exclusion,omitempty"`
+	RealityCheck    int                `json:"reality_check_interval_minutes"`
+}
+
+// ToLimitResponse converts a limit model with the amount used in its current window.
+func ToLimitResponse(limit *models.PlayerLimit, used decimal.Decimal, at time.Time) *LimitResponse {
+	amount, _ := limit.EffectiveAmount(at)
+	remaining := amount.Sub(used)
+	if remaining.IsNegative() {
+		remaining = decimal.Zero
+	}
+
+	response := &LimitResponse{
+		ID:        limit.ID,
+		LimitType: limit.LimitType,
+		Period:    limit.Period,
+		Amount:    amount,
+		Used:      used,
+		Remaining: remaining,
+		UpdatedAt: limit.UpdatedAt,
+	}
+	if limit.HasPendingChange(at) {
+		response.PendingAmount = limit.PendingAmount
+		response.PendingRemoval = limit.PendingAmount == nil
+		response.PendingFrom = limit.PendingFrom
+	}
+	return response
+}
+
+// ToExclusionResponse converts an exclusion model.
+func ToExclusionResponse(e *models.PlayerExclusion) *ExclusionResponse {
+	return &ExclusionResponse{
+		ID:            e.ID,
+		ExclusionType: e.ExclusionType,
+		StartsAt:      e.StartsAt,
+		EndsAt:        e.EndsAt,
+		Permanent:     e.IsPermanent(),
+	}
+}
@@ -0,0 +1,71 @@
+package responsible
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+)
+
+func TestSetLimitRequest_Validate(t *testing.T) {
+	s := sanitizer.NewHTMLStripper()
+
+	req := &SetLimitRequest{LimitType: "deposit", Period: "weekly", Amount: decimal.NewFromInt(500)}
+	v := validator.New()
+	req.Validate(v, s)
+	assert.True(t, v.Valid())
+
+	invalid := &SetLimitRequest{LimitType: "bonus", Period: "yearly", Amount: decimal.RequireFromString("10.005")}
+	v = validator.New()
+	invalid.Validate(v, s)
+	assert.Contains(t, v.Errors, "limit_type")
+	assert.Contains(t, v.Errors, "period")
+	assert.Contains(t, v.Errors, "amount")
+
+	zero := &SetLimitRequest{LimitType: "loss", Period: "daily"}
+	v = validator.New()
+	zero.Validate(v, s)
+	assert.Contains(t, v.Errors, "amount")
+}
+
+func TestExclusionRequest_Validate(t *testing.T) {
+	s := sanitizer.NewHTMLStripper()
+
+	tests := []struct {
+		name    string
+		req     ExclusionRequest
+		invalid []string
+	}{
+		{"time-out", ExclusionRequest{ExclusionType: "time_out", Days: 7}, nil},
+		{"self-exclusion", ExclusionRequest{ExclusionType: "self_exclusion"}, nil},
+		{"permanent self-exclusion", ExclusionRequest{ExclusionType: "self_exclusion", Permanent: true}, nil},
+		{"time-out too long", ExclusionRequest{ExclusionType: "time_out", Days: 43}, []string{"days"}},
+		{"permanent time-out", ExclusionRequest{ExclusionType: "time_out", Days: 1, Permanent: true}, []string{"permanent"}},
+		{"self-exclusion with days", ExclusionRequest{ExclusionType: "self_exclusion", Days: 30}, []string{"days"}},
+		{"unknown type", ExclusionRequest{ExclusionType: "break"}, []string{"exclusion_type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+			tt.req.Validate(v, s, 42)
+			assert.Len(t, v.Errors, len(tt.invalid))
+			for _, field := range tt.invalid {
+				assert.Contains(t, v.Errors, field)
+			}
+		})
+	}
+}
+
+func TestRealityCheckRequest_Validate(t *testing.T) {
+	config := GetDefaultConfig()
+
+	for minutes, valid := range map[int]bool{0: true, 15: true, 60: true, 240: true, 5: false, 241: false} {
+		v := validator.New()
+		(&RealityCheckRequest{IntervalMinutes: minutes}).Validate(v, config)
+		assert.Equal(t, valid, v.Valid(), "interval %d", minutes)
+	}
+}
@@ -0,0 +1,290 @@
+package responsible
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// Handler handles HTTP requests for responsible gambling controls
+type Handler struct {
+	service   Service
+	config    *Config
+	sanitizer sanitizer.HTMLStripperer
+	logger    logger.Logger
+}
+
+// NewHandler creates a new responsible gambling handler
+func NewHandler(service Service, config *Config, s sanitizer.HTMLStripperer, lg logger.Logger) *Handler {
+	return &Handler{service: service, config: config, sanitizer: s, logger: lg}
+}
+
+// GetSettings godoc
+// @Summary      Get responsible gambling settings
+// @Description  Get the authenticated user's limits, active exclusion and reality check interval
+// @Tags         responsible-gambling
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=SettingsResponse}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/responsible-gambling [get]
+func (h *Handler) GetSettings(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	settings, err := h.service.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetSettings", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to retrieve responsible gambling settings")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Responsible gambling settings retrieved successfully", settings)
+}
+
+// SetLimit godoc
+// @Summary      Set a limit
+// @Description  Create or change a deposit, stake or loss limit. Increases apply after a cooling period
+// @Tags         responsible-gambling
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body SetLimitRequest true "Limit"
+// @Success      200  {object}  api.Response{data=LimitResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/responsible-gambling/limits [put]
+func (h *Handler) SetLimit(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req SetLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	limit, err := h.service.SetLimit(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "SetLimit", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to set limit")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Limit saved successfully", limit)
+}
+
+// RemoveLimit godoc
+// @Summary      Remove a limit
+// @Description  Schedule removal of a limit once the cooling period has passed
+// @Tags         responsible-gambling
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type    path  string  true  "Limit type"  Enums(deposit, stake, loss)
+// @Param        period  path  string  true  "Limit period"  Enums(daily, weekly, monthly)
+// @Success      200  {object}  api.Response{data=LimitResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/responsible-gambling/limits/{type}/{period} [delete]
+func (h *Handler) RemoveLimit(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	limitType, period := c.Param("type"), c.Param("period")
+	if !ValidLimitType(limitType) || !ValidLimitPeriod(period) {
+		api.BadRequestResponse(c, "Invalid limit type or period")
+		return
+	}
+
+	limit, err := h.service.RemoveLimit(c.Request.Context(),
+		userID,
+		models.PlayerLimitType(limitType),
+		models.PlayerLimitPeriod(period))
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Limit")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "RemoveLimit", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to remove limit")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Limit removal scheduled", limit)
+}
+
+// Exclude godoc
+// @Summary      Exclude from betting
+// @Description  Start a time-out or self-exclusion. An exclusion in force cannot be shortened
+// @Tags         responsible-gambling
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ExclusionRequest true "Exclusion"
+// @Success      201  {object}  api.Response{data=ExclusionResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/responsible-gambling/exclusions [post]
+func (h *Handler) Exclude(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ExclusionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v, h.sanitizer, h.config.MaxTimeOutDays)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	exclusion, err := h.service.Exclude(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrPlayerExcluded) {
+			api.ConflictResponse(c, "A longer exclusion is already in force")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "Exclude", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to create exclusion")
+		return
+	}
+
+	api.CreatedResponse(c, "Exclusion started", exclusion)
+}
+
+// GetRealityCheck godoc
+// @Summary      Get reality check
+// @Description  Get session time, stakes and net result, and whether a reminder is due
+// @Tags         responsible-gambling
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=RealityCheckResponse}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/responsible-gambling/reality-check [get]
+func (h *Handler) GetRealityCheck(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	check, err := h.service.GetRealityCheck(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetRealityCheck", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to retrieve reality check")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Reality check retrieved successfully", check)
+}
+
+// SetRealityCheck godoc
+// @Summary      Set reality check interval
+// @Description  Set how often reality check reminders are shown. Zero turns them off
+// @Tags         responsible-gambling
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body RealityCheckRequest true "Reality check"
+// @Success      200  {object}  api.Response{data=RealityCheckResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/responsible-gambling/reality-check [put]
+func (h *Handler) SetRealityCheck(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req RealityCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v, h.config)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	check, err := h.service.SetRealityCheck(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "SetRealityCheck", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to save reality check")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Reality check saved successfully", check)
+}
+
+// AcknowledgeRealityCheck godoc
+// @Summary      Acknowledge reality check
+// @Description  Acknowledge a reality check reminder, restarting the interval
+// @Tags         responsible-gambling
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=RealityCheckResponse}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/responsible-gambling/reality-check/acknowledge [post]
+func (h *Handler) AcknowledgeRealityCheck(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	check, err := h.service.AcknowledgeRealityCheck(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Reality check")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "AcknowledgeRealityCheck", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to acknowledge reality check")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Reality check acknowledged", check)
+}
@@ -0,0 +1,47 @@
+package responsible
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "responsible_repository"
+	ServiceKey = "responsible_service"
+	ConfigKey  = "responsible_config"
+)
+
+// MountAuthenticated mounts the user's responsible gambling routes
+func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	rgGroup := r.Group("/responsible-gambling")
+	rgGroup.GET("", handler.GetSettings)
+	rgGroup.PUT("/limits", handler.SetLimit)
+	rgGroup.DELETE("/limits/:type/:period", handler.RemoveLimit)
+	rgGroup.POST("/exclusions", handler.Exclude)
+	rgGroup.GET("/reality-check", handler.GetRealityCheck)
+	rgGroup.PUT("/reality-check", handler.SetRealityCheck)
+	rgGroup.POST("/reality-check/acknowledge", handler.AcknowledgeRealityCheck)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid responsible gambling configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+	container.RegisterService(ServiceKey, NewService(repo, config))
+	container.RegisterService(ConfigKey, config)
+}
+
+// createHandler creates a responsible gambling handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+	config := container.GetService(ConfigKey).(*Config)
+
+	return NewHandler(service, config, container.Sanitizer, container.Logger)
+}
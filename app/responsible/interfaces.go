@@ -0,0 +1,56 @@
+package responsible
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetLimits(ctx context.Context, userID uuid.UUID) ([]models.PlayerLimit, error)
+	GetLimit(ctx context.Context, userID uuid.UUID, limitType models.PlayerLimitType, period models.PlayerLimitPeriod) (*models.PlayerLimit, error)
+	SaveLimit(ctx context.Context, limit *models.PlayerLimit) error
+
+	GetActiveExclusion(ctx context.Context, userID uuid.UUID, at time.Time) (*models.PlayerExclusion, error)
+	CreateExclusion(ctx context.Context, exclusion *models.PlayerExclusion) error
+
+	GetRealityCheck(ctx context.Context, userID uuid.UUID) (*models.RealityCheck, error)
+	SaveRealityCheck(ctx context.Context, check *models.RealityCheck) error
+
+	SumTransactions(ctx context.Context, userID uuid.UUID, since time.Time, types ...models.TransactionType) (decimal.Decimal, error)
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+
+	WithTx(tx *gorm.DB) Repository
+}
+
+// Guard enforces a player's exclusions and limits on money entering play.
+// The betting risk engine and the deposit flow consult it.
+type Guard interface {
+	CheckDeposit(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error
+	CheckStake(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error
+	// StakeHeadroom returns the largest stake the user's own limits allow
+	// right now, or nil if no stake or loss limit applies.
+	StakeHeadroom(ctx context.Context, userID uuid.UUID) (*decimal.Decimal, error)
+	// WithTx returns a guard reading through tx. Callers check under the
+	// wallet lock they hold so concurrent deposits or stakes are serialized
+	// and each sees the usage of the ones committed before it.
+	WithTx(tx *gorm.DB) Guard
+}
+
+type Service interface {
+	Guard
+
+	GetSettings(ctx context.Context, userID uuid.UUID) (*SettingsResponse, error)
+	SetLimit(ctx context.Context, userID uuid.UUID, req *SetLimitRequest) (*LimitResponse, error)
+	RemoveLimit(ctx context.Context, userID uuid.UUID, limitType models.PlayerLimitType, period models.PlayerLimitPeriod) (*LimitResponse, error)
+	Exclude(ctx context.Context, userID uuid.UUID, req *ExclusionRequest) (*ExclusionResponse, error)
+
+	GetRealityCheck(ctx context.Context, userID uuid.UUID) (*RealityCheckResponse, error)
+	SetRealityCheck(ctx context.Context, userID uuid.UUID, req *RealityCheckRequest) (*RealityCheckResponse, error)
+	AcknowledgeRealityCheck(ctx context.Context, userID uuid.UUID) (*RealityCheckResponse, error)
+}
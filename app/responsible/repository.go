@@ -0,0 +1,122 @@
+package responsible
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new responsible gambling repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) WithTx(tx *gorm.DB) Repository {
+	return &repository{db: tx}
+}
+
+func (r *repository) GetLimits(ctx context.Context, userID uuid.UUID) ([]models.PlayerLimit, error) {
+	var limits []models.PlayerLimit
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("limit_type, period").
+		Find(&limits).Error
+	return limits, err
+}
+
+func (r *repository) GetLimit(ctx context.Context,
+	userID uuid.UUID,
+	limitType models.PlayerLimitType,
+	period models.PlayerLimitPeriod) (*models.PlayerLimit, error) {
+	var limit models.PlayerLimit
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND limit_type = ? AND period = ?", userID, limitType, period).
+		First(&limit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &limit, nil
+}
+
+func (r *repository) SaveLimit(ctx context.Context, limit *models.PlayerLimit) error {
+	return r.db.WithContext(ctx).Save(limit).Error
+}
+
+// GetActiveExclusion returns the exclusion in force at the given time that ends last.
+func (r *repository) GetActiveExclusion(ctx context.Context, userID uuid.UUID, at time.Time) (*models.PlayerExclusion, error) {
+	var exclusion models.PlayerExclusion
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", userID, at, at).
+		Order("ends_at DESC NULLS FIRST").
+		First(&exclusion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &exclusion, nil
+}
+
+func (r *repository) CreateExclusion(ctx context.Context, exclusion *models.PlayerExclusion) error {
+	return r.db.WithContext(ctx).Create(exclusion).Error
+}
+
+func (r *repository) GetRealityCheck(ctx context.Context, userID uuid.UUID) (*models.RealityCheck, error) {
+	var check models.RealityCheck
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&check).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &check, nil
+}
+
+func (r *repository) SaveRealityCheck(ctx context.Context, check *models.RealityCheck) error {
+	return r.db.WithContext(ctx).Save(check).Error
+}
+
+// SumTransactions adds up the signed amounts of the user's ledger entries of
+// the given types created since the given time.
+func (r *repository) SumTransactions(ctx context.Context,
+	userID uuid.UUID,
+	since time.Time,
+	types ...models.TransactionType) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND transaction_type IN ? AND created_at >= ?", userID, types, since).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *repository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, "id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *repository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
@@ -0,0 +1,441 @@
+package responsible
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Ledger entries counted towards each limit. Stakes are net of refunds and
+// losses are net of payouts; both are stored as negative amounts.
+var (
+	depositTypes = []models.TransactionType{models.TransactionTypeDeposit}
+	stakeTypes   = []models.TransactionType{models.TransactionTypeBetPlace, models.TransactionTypeBetRefund}
+	resultTypes  = []models.TransactionType{
+		models.TransactionTypeBetPlace,
+		models.TransactionTypeBetRefund,
+		models.TransactionTypePayout,
+	}
+)
+
+type service struct {
+	repo   Repository
+	config *Config
+}
+
+// NewService creates a new responsible gambling service.
+func NewService(repo Repository, config *Config) Service {
+	return &service{repo: repo, config: config}
+}
+
+func (s *service) WithTx(tx *gorm.DB) Guard {
+	return &service{repo: s.repo.WithTx(tx), config: s.config}
+}
+
+// CheckDeposit rejects deposits from excluded users and deposits that would
+// break one of the user's deposit limits.
+func (s *service) CheckDeposit(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+	return s.check(ctx, userID, amount, models.PlayerLimitDeposit)
+}
+
+// CheckStake rejects bets from excluded users and bets that would break a
+// stake limit, or a loss limit if the whole stake were lost.
+func (s *service) CheckStake(ctx context.Context, userID uuid.UUID, amount decimal.Decimal) error {
+	return s.check(ctx, userID, amount, models.PlayerLimitStake, models.PlayerLimitLoss)
+}
+
+func (s *service) check(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, limitTypes ...models.PlayerLimitType) error {
+	now := time.Now()
+
+	if _, err := s.repo.GetActiveExclusion(ctx, userID, now); err == nil {
+		return models.ErrPlayerExcluded
+	} else if !errors.Is(err, models.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get exclusion: %w", err)
+	}
+
+	limits, err := s.repo.GetLimits(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get limits: %w", err)
+	}
+
+	for i := range limits {
+		limit := &limits[i]
+		if !containsLimitType(limitTypes, limit.LimitType) {
+			continue
+		}
+		max, ok := limit.EffectiveAmount(now)
+		if !ok {
+			continue
+		}
+		used, err := s.usage(ctx, userID, limit, now)
+		if err != nil {
+			return err
+		}
+		if used.Add(amount).GreaterThan(max) {
+			return limitExceededError(limit.LimitType)
+		}
+	}
+	return nil
+}
+
+// StakeHeadroom returns the smallest amount left under the user's stake and
+// loss limits; an excluded user has no headroom at all.
+func (s *service) StakeHeadroom(ctx context.Context, userID uuid.UUID) (*decimal.Decimal, error) {
+	now := time.Now()
+
+	if _, err := s.repo.GetActiveExclusion(ctx, userID, now); err == nil {
+		none := decimal.Zero
+		return &none, nil
+	} else if !errors.Is(err, models.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get exclusion: %w", err)
+	}
+
+	limits, err := s.repo.GetLimits(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get limits: %w", err)
+	}
+
+	var headroom *decimal.Decimal
+	for i := range limits {
+		limit := &limits[i]
+		if limit.LimitType != models.PlayerLimitStake && limit.LimitType != models.PlayerLimitLoss {
+			continue
+		}
+		max, ok := limit.EffectiveAmount(now)
+		if !ok {
+			continue
+		}
+		used, err := s.usage(ctx, userID, limit, now)
+		if err != nil {
+			return nil, err
+		}
+		left := decimal.Max(max.Sub(used), decimal.Zero)
+		if headroom == nil || left.LessThan(*headroom) {
+			headroom = &left
+		}
+	}
+	return headroom, nil
+}
+
+// usage returns how much of the limit the user has used in its current window.
+func (s *service) usage(ctx context.Context, userID uuid.UUID, limit *models.PlayerLimit, now time.Time) (decimal.Decimal, error) {
+	since := now.Add(-limit.Period.Window())
+
+	var used decimal.Decimal
+	var err error
+	switch limit.LimitType {
+	case models.PlayerLimitDeposit:
+		used, err = s.repo.SumTransactions(ctx, userID, since, depositTypes...)
+	case models.PlayerLimitStake:
+		used, err = s.repo.SumTransactions(ctx, userID, since, stakeTypes...)
+		used = used.Neg()
+	case models.PlayerLimitLoss:
+		used, err = s.repo.SumTransactions(ctx, userID, since, resultTypes...)
+		used = used.Neg()
+	}
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to calculate %s usage: %w", limit.LimitType, err)
+	}
+	if used.IsNegative() {
+		used = decimal.Zero
+	}
+	return used, nil
+}
+
+func (s *service) GetSettings(ctx context.Context, userID uuid.UUID) (*SettingsResponse, error) {
+	now := time.Now()
+
+	limits, err := s.repo.GetLimits(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get limits: %w", err)
+	}
+
+	response := &SettingsResponse{Limits: make([]LimitResponse, 0, len(limits))}
+	for i := range limits {
+		limit := &limits[i]
+		if _, ok := limit.EffectiveAmount(now); !ok {
+			continue
+		}
+		used, err := s.usage(ctx, userID, limit, now)
+		if err != nil {
+			return nil, err
+		}
+		response.Limits = append(response.Limits, *ToLimitResponse(limit, used, now))
+	}
+
+	exclusion, err := s.repo.GetActiveExclusion(ctx, userID, now)
+	if err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get exclusion: %w", err)
+	}
+	if exclusion != nil {
+		response.ActiveExclusion = ToExclusionResponse(exclusion)
+	}
+
+	check, err := s.repo.GetRealityCheck(ctx, userID)
+	if err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get reality check: %w", err)
+	}
+	if check != nil {
+		response.RealityCheck = check.IntervalMinutes
+	}
+
+	return response, nil
+}
+
+// SetLimit creates or changes a limit. New limits and decreases apply at once;
+// increases wait for the configured delay while the current limit stays in force.
+func (s *service) SetLimit(ctx context.Context, userID uuid.UUID, req *SetLimitRequest) (*LimitResponse, error) {
+	now := time.Now()
+	limitType := models.PlayerLimitType(req.LimitType)
+	period := models.PlayerLimitPeriod(req.Period)
+
+	limit, err := s.repo.GetLimit(ctx, userID, limitType, period)
+	if err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get limit: %w", err)
+	}
+
+	var oldValues models.AuditValues
+	switch {
+	case limit == nil:
+		limit = &models.PlayerLimit{UserID: userID, LimitType: limitType, Period: period, Amount: req.Amount}
+	case !limit.Settle(now):
+		// A removal already took effect, so there is no limit to protect
+		limit.Amount = req.Amount
+	default:
+		oldValues = limitAuditValues(limit)
+		if req.Amount.LessThanOrEqual(limit.Amount) {
+			limit.Amount = req.Amount
+			limit.PendingAmount = nil
+			limit.PendingFrom = nil
+		} else {
+			pendingFrom := now.Add(s.config.LimitIncreaseDelay)
+			limit.PendingAmount = &req.Amount
+			limit.PendingFrom = &pendingFrom
+		}
+	}
+
+	if err := s.repo.SaveLimit(ctx, limit); err != nil {
+		return nil, fmt.Errorf("failed to save limit: %w", err)
+	}
+	if err := s.recordChange(ctx, userID, models.AuditActionPlayerLimitSet, limit.ID, oldValues, limitAuditValues(limit)); err != nil {
+		return nil, err
+	}
+
+	used, err := s.usage(ctx, userID, limit, now)
+	if err != nil {
+		return nil, err
+	}
+	return ToLimitResponse(limit, used, now), nil
+}
+
+// RemoveLimit lifts a limit once the configured delay has passed.
+func (s *service) RemoveLimit(ctx context.Context,
+	userID uuid.UUID,
+	limitType models.PlayerLimitType,
+	period models.PlayerLimitPeriod) (*LimitResponse, error) {
+	now := time.Now()
+
+	limit, err := s.repo.GetLimit(ctx, userID, limitType, period)
+	if err != nil {
+		return nil, err
+	}
+	if !limit.Settle(now) {
+		return nil, models.ErrRecordNotFound
+	}
+
+	oldValues := limitAuditValues(limit)
+	pendingFrom := now.Add(s.config.LimitIncreaseDelay)
+	limit.PendingFrom = &pendingFrom
+
+	if err := s.repo.SaveLimit(ctx, limit); err != nil {
+		return nil, fmt.Errorf("failed to save limit: %w", err)
+	}
+	if err := s.recordChange(ctx, userID, models.AuditActionPlayerLimitSet, limit.ID, oldValues, limitAuditValues(limit)); err != nil {
+		return nil, err
+	}
+
+	used, err := s.usage(ctx, userID, limit, now)
+	if err != nil {
+		return nil, err
+	}
+	return ToLimitResponse(limit, used, now), nil
+}
+
+// Exclude starts a time-out or self-exclusion. It cannot shorten an exclusion
+// already in force, only extend it.
+func (s *service) Exclude(ctx context.Context, userID uuid.UUID, req *ExclusionRequest) (*ExclusionResponse, error) {
+	now := time.Now()
+	exclusion := &models.PlayerExclusion{
+		UserID:        userID,
+		ExclusionType: models.PlayerExclusionType(req.ExclusionType),
+		Reason:        req.Reason,
+		StartsAt:      now,
+	}
+	switch {
+	case exclusion.ExclusionType == models.PlayerExclusionTimeOut:
+		endsAt := now.AddDate(0, 0, req.Days)
+		exclusion.EndsAt = &endsAt
+	case !req.Permanent:
+		endsAt := now.AddDate(0, s.config.SelfExclusionMonths, 0)
+		exclusion.EndsAt = &endsAt
+	}
+
+	active, err := s.repo.GetActiveExclusion(ctx, userID, now)
+	if err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get exclusion: %w", err)
+	}
+	if active != nil && active.CoversUntil(exclusion.EndsAt) {
+		return nil, models.ErrPlayerExcluded
+	}
+
+	if err := s.repo.CreateExclusion(ctx, exclusion); err != nil {
+		return nil, fmt.Errorf("failed to create exclusion: %w", err)
+	}
+	newValues := models.AuditValues{
+		"exclusion_type": exclusion.ExclusionType,
+		"ends_at":        exclusion.EndsAt,
+		"permanent":      exclusion.IsPermanent(),
+	}
+	if err := s.recordChange(ctx, userID, models.AuditActionPlayerExcluded, exclusion.ID, nil, newValues); err != nil {
+		return nil, err
+	}
+
+	return ToExclusionResponse(exclusion), nil
+}
+
+// GetRealityCheck reports the time spent and net result of the current
+// session and whether a reminder is due.
+func (s *service) GetRealityCheck(ctx context.Context, userID uuid.UUID) (*RealityCheckResponse, error) {
+	check, err := s.repo.GetRealityCheck(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, models.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get reality check: %w", err)
+		}
+		check = &models.RealityCheck{UserID: userID}
+	}
+	return s.realityCheckStatus(ctx, check)
+}
+
+func (s *service) SetRealityCheck(ctx context.Context, userID uuid.UUID, req *RealityCheckRequest) (*RealityCheckResponse, error) {
+	check, err := s.repo.GetRealityCheck(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, models.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get reality check: %w", err)
+		}
+		check = &models.RealityCheck{UserID: userID}
+	}
+
+	check.IntervalMinutes = req.IntervalMinutes
+	if err := s.repo.SaveRealityCheck(ctx, check); err != nil {
+		return nil, fmt.Errorf("failed to save reality check: %w", err)
+	}
+	return s.realityCheckStatus(ctx, check)
+}
+
+// AcknowledgeRealityCheck records that the user saw the reminder, which
+// restarts the interval.
+func (s *service) AcknowledgeRealityCheck(ctx context.Context, userID uuid.UUID) (*RealityCheckResponse, error) {
+	check, err := s.repo.GetRealityCheck(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	check.LastAcknowledgedAt = &now
+	if err := s.repo.SaveRealityCheck(ctx, check); err != nil {
+		return nil, fmt.Errorf("failed to save reality check: %w", err)
+	}
+	return s.realityCheckStatus(ctx, check)
+}
+
+// realityCheckStatus measures the session from the user's last login.
+func (s *service) realityCheckStatus(ctx context.Context, check *models.RealityCheck) (*RealityCheckResponse, error) {
+	now := time.Now()
+
+	user, err := s.repo.GetUserByID(ctx, check.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	sessionStart := now
+	if user.LastLoginAt != nil {
+		sessionStart = *user.LastLoginAt
+	}
+
+	staked, err := s.repo.SumTransactions(ctx, check.UserID, sessionStart, stakeTypes...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate session stakes: %w", err)
+	}
+	result, err := s.repo.SumTransactions(ctx, check.UserID, sessionStart, resultTypes...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate session result: %w", err)
+	}
+
+	nextDue := check.NextDueAt(sessionStart)
+	return &RealityCheckResponse{
+		IntervalMinutes:  check.IntervalMinutes,
+		SessionStartedAt: sessionStart,
+		NextDueAt:        nextDue,
+		Due:              nextDue != nil && !now.Before(*nextDue),
+		Staked:           staked.Neg(),
+		NetResult:        result,
+	}, nil
+}
+
+func (s *service) recordChange(ctx context.Context,
+	userID uuid.UUID,
+	action string,
+	resourceID uuid.UUID,
+	oldValues, newValues models.AuditValues) error {
+	actor := audit.ActorFromContext(ctx)
+	auditLog := models.CreateUserAuditLog(userID,
+		action,
+		models.AuditResourceUser,
+		&resourceID,
+		oldValues,
+		newValues,
+		actor.IP, actor.UserAgent)
+	if err := s.repo.CreateAuditLog(ctx, auditLog); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+func limitAuditValues(limit *models.PlayerLimit) models.AuditValues {
+	values := models.AuditValues{
+		"limit_type":   limit.LimitType,
+		"period":       limit.Period,
+		"amount":       limit.Amount.StringFixed(2),
+		"pending_from": limit.PendingFrom,
+	}
+	if limit.PendingAmount != nil {
+		values["pending_amount"] = limit.PendingAmount.StringFixed(2)
+	}
+	return values
+}
+
+func limitExceededError(limitType models.PlayerLimitType) error {
+	switch limitType {
+	case models.PlayerLimitDeposit:
+		return models.ErrDepositLimitExceeded
+	case models.PlayerLimitLoss:
+		return models.ErrLossLimitExceeded
+	default:
+		return models.ErrStakeLimitExceeded
+	}
+}
+
+func containsLimitType(types []models.PlayerLimitType, limitType models.PlayerLimitType) bool {
+	for _, t := range types {
+		if t == limitType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,394 @@
+package responsible
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/joefazee/neo/models"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetLimits(ctx context.Context, userID uuid.UUID) ([]models.PlayerLimit, error) {
+	args := m.Called(ctx, userID)
+	if l := args.Get(0); l != nil {
+		return l.([]models.PlayerLimit), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetLimit(ctx context.Context,
+	userID uuid.UUID,
+	limitType models.PlayerLimitType,
+	period models.PlayerLimitPeriod) (*models.PlayerLimit, error) {
+	args := m.Called(ctx, userID, limitType, period)
+	if l := args.Get(0); l != nil {
+		return l.(*models.PlayerLimit), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) SaveLimit(ctx context.Context, limit *models.PlayerLimit) error {
+	return m.Called(ctx, limit).Error(0)
+}
+
+func (m *MockRepository) GetActiveExclusion(ctx context.Context, userID uuid.UUID, at time.Time) (*models.PlayerExclusion, error) {
+	args := m.Called(ctx, userID, at)
+	if e := args.Get(0); e != nil {
+		return e.(*models.PlayerExclusion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) CreateExclusion(ctx context.Context, exclusion *models.PlayerExclusion) error {
+	return m.Called(ctx, exclusion).Error(0)
+}
+
+func (m *MockRepository) GetRealityCheck(ctx context.Context, userID uuid.UUID) (*models.RealityCheck, error) {
+	args := m.Called(ctx, userID)
+	if r := args.Get(0); r != nil {
+		return r.(*models.RealityCheck), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) SaveRealityCheck(ctx context.Context, check *models.RealityCheck) error {
+	return m.Called(ctx, check).Error(0)
+}
+
+func (m *MockRepository) SumTransactions(ctx context.Context,
+	userID uuid.UUID,
+	since time.Time,
+	types ...models.TransactionType) (decimal.Decimal, error) {
+	args := m.Called(ctx, userID, since, types)
+	return args.Get(0).(decimal.Decimal), args.Error(1)
+}
+
+func (m *MockRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if u := args.Get(0); u != nil {
+		return u.(*models.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return m.Called(ctx, log).Error(0)
+}
+
+func (m *MockRepository) WithTx(*gorm.DB) Repository {
+	return m
+}
+
+func newTestService(repo *MockRepository) Service {
+	return NewService(repo, GetDefaultConfig())
+}
+
+func TestService_CheckStake(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("excluded player", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(&models.PlayerExclusion{}, nil)
+
+		err := newTestService(repo).CheckStake(ctx, userID, decimal.NewFromInt(10))
+		assert.ErrorIs(t, err, models.ErrPlayerExcluded)
+		repo.AssertNotCalled(t, "GetLimits", mock.Anything, mock.Anything)
+	})
+
+	t.Run("within stake limit", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("GetLimits", ctx, userID).Return([]models.PlayerLimit{
+			{LimitType: models.PlayerLimitStake, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(100)},
+			{LimitType: models.PlayerLimitDeposit, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(1)},
+		}, nil)
+		repo.On("SumTransactions", ctx, userID, mock.Anything, stakeTypes).Return(decimal.NewFromInt(-60), nil)
+
+		err := newTestService(repo).CheckStake(ctx, userID, decimal.NewFromInt(40))
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("stake limit exceeded", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("GetLimits", ctx, userID).Return([]models.PlayerLimit{
+			{LimitType: models.PlayerLimitStake, Period: models.PlayerLimitWeekly, Amount: decimal.NewFromInt(100)},
+		}, nil)
+		repo.On("SumTransactions", ctx, userID, mock.Anything, stakeTypes).Return(decimal.NewFromInt(-60), nil)
+
+		err := newTestService(repo).CheckStake(ctx, userID, decimal.NewFromInt(41))
+		assert.ErrorIs(t, err, models.ErrStakeLimitExceeded)
+	})
+
+	t.Run("loss limit exceeded", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("GetLimits", ctx, userID).Return([]models.PlayerLimit{
+			{LimitType: models.PlayerLimitLoss, Period: models.PlayerLimitMonthly, Amount: decimal.NewFromInt(50)},
+		}, nil)
+		repo.On("SumTransactions", ctx, userID, mock.Anything, resultTypes).Return(decimal.NewFromInt(-45), nil)
+
+		err := newTestService(repo).CheckStake(ctx, userID, decimal.NewFromInt(10))
+		assert.ErrorIs(t, err, models.ErrLossLimitExceeded)
+	})
+
+	t.Run("winnings do not count towards loss limit", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("GetLimits", ctx, userID).Return([]models.PlayerLimit{
+			{LimitType: models.PlayerLimitLoss, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(50)},
+		}, nil)
+		repo.On("SumTransactions", ctx, userID, mock.Anything, resultTypes).Return(decimal.NewFromInt(200), nil)
+
+		err := newTestService(repo).CheckStake(ctx, userID, decimal.NewFromInt(50))
+		assert.NoError(t, err)
+	})
+
+	t.Run("removed limit is ignored", func(t *testing.T) {
+		past := time.Now().Add(-time.Minute)
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("GetLimits", ctx, userID).Return([]models.PlayerLimit{
+			{LimitType: models.PlayerLimitStake, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(1), PendingFrom: &past},
+		}, nil)
+
+		err := newTestService(repo).CheckStake(ctx, userID, decimal.NewFromInt(500))
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "SumTransactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_StakeHeadroom(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("excluded player", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(&models.PlayerExclusion{}, nil)
+
+		headroom, err := newTestService(repo).StakeHeadroom(ctx, userID)
+		assert.NoError(t, err)
+		assert.True(t, headroom.IsZero())
+	})
+
+	t.Run("no stake or loss limits", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("GetLimits", ctx, userID).Return([]models.PlayerLimit{
+			{LimitType: models.PlayerLimitDeposit, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(1)},
+		}, nil)
+
+		headroom, err := newTestService(repo).StakeHeadroom(ctx, userID)
+		assert.NoError(t, err)
+		assert.Nil(t, headroom)
+	})
+
+	t.Run("tightest limit wins", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("GetLimits", ctx, userID).Return([]models.PlayerLimit{
+			{LimitType: models.PlayerLimitStake, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(100)},
+			{LimitType: models.PlayerLimitLoss, Period: models.PlayerLimitWeekly, Amount: decimal.NewFromInt(50)},
+		}, nil)
+		repo.On("SumTransactions", ctx, userID, mock.Anything, stakeTypes).Return(decimal.NewFromInt(-60), nil)
+		repo.On("SumTransactions", ctx, userID, mock.Anything, resultTypes).Return(decimal.NewFromInt(-30), nil)
+
+		headroom, err := newTestService(repo).StakeHeadroom(ctx, userID)
+		assert.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(20).Equal(*headroom), "headroom %s", headroom)
+	})
+}
+
+func TestService_CheckDeposit(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	repo := new(MockRepository)
+	repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+	repo.On("GetLimits", ctx, userID).Return([]models.PlayerLimit{
+		{LimitType: models.PlayerLimitDeposit, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(100)},
+		{LimitType: models.PlayerLimitStake, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(1)},
+	}, nil)
+	repo.On("SumTransactions", ctx, userID, mock.Anything, depositTypes).Return(decimal.NewFromInt(80), nil)
+
+	svc := newTestService(repo)
+	assert.NoError(t, svc.CheckDeposit(ctx, userID, decimal.NewFromInt(20)))
+	assert.ErrorIs(t, svc.CheckDeposit(ctx, userID, decimal.NewFromInt(21)), models.ErrDepositLimitExceeded)
+}
+
+func TestService_SetLimit(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	req := func(amount int64) *SetLimitRequest {
+		return &SetLimitRequest{LimitType: "deposit", Period: "daily", Amount: decimal.NewFromInt(amount)}
+	}
+	expectWrites := func(repo *MockRepository) {
+		repo.On("SaveLimit", ctx, mock.AnythingOfType("*models.PlayerLimit")).Return(nil)
+		repo.On("CreateAuditLog", ctx, mock.AnythingOfType("*models.AuditLog")).Return(nil)
+		repo.On("SumTransactions", ctx, userID, mock.Anything, depositTypes).Return(decimal.Zero, nil)
+	}
+
+	t.Run("new limit applies immediately", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetLimit", ctx, userID, models.PlayerLimitDeposit, models.PlayerLimitDaily).Return(nil, models.ErrRecordNotFound)
+		expectWrites(repo)
+
+		resp, err := newTestService(repo).SetLimit(ctx, userID, req(100))
+		require.NoError(t, err)
+		assert.True(t, resp.Amount.Equal(decimal.NewFromInt(100)))
+		assert.Nil(t, resp.PendingFrom)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("decrease applies immediately", func(t *testing.T) {
+		pending := decimal.NewFromInt(300)
+		later := time.Now().Add(time.Hour)
+		existing := &models.PlayerLimit{
+			LimitType: models.PlayerLimitDeposit, Period: models.PlayerLimitDaily,
+			Amount: decimal.NewFromInt(100), PendingAmount: &pending, PendingFrom: &later,
+		}
+		repo := new(MockRepository)
+		repo.On("GetLimit", ctx, userID, models.PlayerLimitDeposit, models.PlayerLimitDaily).Return(existing, nil)
+		expectWrites(repo)
+
+		_, err := newTestService(repo).SetLimit(ctx, userID, req(50))
+		require.NoError(t, err)
+		assert.True(t, existing.Amount.Equal(decimal.NewFromInt(50)))
+		assert.Nil(t, existing.PendingAmount)
+		assert.Nil(t, existing.PendingFrom)
+	})
+
+	t.Run("increase waits for cooling period", func(t *testing.T) {
+		existing := &models.PlayerLimit{LimitType: models.PlayerLimitDeposit, Period: models.PlayerLimitDaily, Amount: decimal.NewFromInt(100)}
+		repo := new(MockRepository)
+		repo.On("GetLimit", ctx, userID, models.PlayerLimitDeposit, models.PlayerLimitDaily).Return(existing, nil)
+		expectWrites(repo)
+
+		resp, err := newTestService(repo).SetLimit(ctx, userID, req(500))
+		require.NoError(t, err)
+		assert.True(t, existing.Amount.Equal(decimal.NewFromInt(100)))
+		require.NotNil(t, existing.PendingAmount)
+		assert.True(t, existing.PendingAmount.Equal(decimal.NewFromInt(500)))
+		require.NotNil(t, existing.PendingFrom)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), *existing.PendingFrom, time.Minute)
+		assert.True(t, resp.Amount.Equal(decimal.NewFromInt(100)))
+	})
+}
+
+func TestService_RemoveLimit(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("schedules removal", func(t *testing.T) {
+		existing := &models.PlayerLimit{LimitType: models.PlayerLimitStake, Period: models.PlayerLimitWeekly, Amount: decimal.NewFromInt(100)}
+		repo := new(MockRepository)
+		repo.On("GetLimit", ctx, userID, models.PlayerLimitStake, models.PlayerLimitWeekly).Return(existing, nil)
+		repo.On("SaveLimit", ctx, existing).Return(nil)
+		repo.On("CreateAuditLog", ctx, mock.Anything).Return(nil)
+		repo.On("SumTransactions", ctx, userID, mock.Anything, stakeTypes).Return(decimal.Zero, nil)
+
+		resp, err := newTestService(repo).RemoveLimit(ctx, userID, models.PlayerLimitStake, models.PlayerLimitWeekly)
+		require.NoError(t, err)
+		assert.True(t, resp.PendingRemoval)
+		_, ok := existing.EffectiveAmount(time.Now())
+		assert.True(t, ok, "limit stays in force during the cooling period")
+	})
+
+	t.Run("missing limit", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetLimit", ctx, userID, models.PlayerLimitStake, models.PlayerLimitWeekly).Return(nil, models.ErrRecordNotFound)
+
+		_, err := newTestService(repo).RemoveLimit(ctx, userID, models.PlayerLimitStake, models.PlayerLimitWeekly)
+		assert.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+}
+
+func TestService_Exclude(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("time-out", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("CreateExclusion", ctx, mock.AnythingOfType("*models.PlayerExclusion")).Return(nil)
+		repo.On("CreateAuditLog", ctx, mock.AnythingOfType("*models.AuditLog")).Return(nil)
+
+		resp, err := newTestService(repo).Exclude(ctx, userID, &ExclusionRequest{ExclusionType: "time_out", Days: 7})
+		require.NoError(t, err)
+		require.NotNil(t, resp.EndsAt)
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), *resp.EndsAt, time.Minute)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("self-exclusion lasts six months", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(nil, models.ErrRecordNotFound)
+		repo.On("CreateExclusion", ctx, mock.Anything).Return(nil)
+		repo.On("CreateAuditLog", ctx, mock.Anything).Return(nil)
+
+		resp, err := newTestService(repo).Exclude(ctx, userID, &ExclusionRequest{ExclusionType: "self_exclusion"})
+		require.NoError(t, err)
+		require.NotNil(t, resp.EndsAt)
+		assert.WithinDuration(t, time.Now().AddDate(0, 6, 0), *resp.EndsAt, time.Minute)
+	})
+
+	t.Run("cannot shorten an active exclusion", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(&models.PlayerExclusion{StartsAt: time.Now()}, nil)
+
+		_, err := newTestService(repo).Exclude(ctx, userID, &ExclusionRequest{ExclusionType: "time_out", Days: 1})
+		assert.ErrorIs(t, err, models.ErrPlayerExcluded)
+		repo.AssertNotCalled(t, "CreateExclusion", mock.Anything, mock.Anything)
+	})
+
+	t.Run("permanent self-exclusion extends a time-out", func(t *testing.T) {
+		endsAt := time.Now().Add(time.Hour)
+		repo := new(MockRepository)
+		repo.On("GetActiveExclusion", ctx, userID, mock.Anything).Return(&models.PlayerExclusion{EndsAt: &endsAt}, nil)
+		repo.On("CreateExclusion", ctx, mock.Anything).Return(nil)
+		repo.On("CreateAuditLog", ctx, mock.Anything).Return(nil)
+
+		resp, err := newTestService(repo).Exclude(ctx, userID, &ExclusionRequest{ExclusionType: "self_exclusion", Permanent: true})
+		require.NoError(t, err)
+		assert.Nil(t, resp.EndsAt)
+	})
+}
+
+func TestService_RealityCheck(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	loginAt := time.Now().Add(-45 * time.Minute)
+
+	repo := new(MockRepository)
+	check := &models.RealityCheck{UserID: userID, IntervalMinutes: 30}
+	repo.On("GetRealityCheck", ctx, userID).Return(check, nil)
+	repo.On("SaveRealityCheck", ctx, check).Return(nil)
+	repo.On("GetUserByID", ctx, userID).Return(&models.User{ID: userID, LastLoginAt: &loginAt}, nil)
+	repo.On("SumTransactions", ctx, userID, loginAt, stakeTypes).Return(decimal.NewFromInt(-40), nil)
+	repo.On("SumTransactions", ctx, userID, loginAt, resultTypes).Return(decimal.NewFromInt(-15), nil)
+
+	svc := newTestService(repo)
+	status, err := svc.GetRealityCheck(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, status.Due)
+	assert.True(t, status.Staked.Equal(decimal.NewFromInt(40)))
+	assert.True(t, status.NetResult.Equal(decimal.NewFromInt(-15)))
+
+	status, err = svc.AcknowledgeRealityCheck(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, status.Due)
+	require.NotNil(t, check.LastAcknowledgedAt)
+}
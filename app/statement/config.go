@@ -0,0 +1,59 @@
+package statement
+
+import (
+	"errors"
+	"time"
+
+	"github.com/joefazee/neo/models"
+)
+
+// Config represents the configuration for the statement module
+type Config struct {
+	// MaxRangeDays is the longest period one statement may cover.
+	MaxRangeDays int `env:"STATEMENT_MAX_RANGE_DAYS"`
+
+	// SyncMaxDays is the longest period built while the request waits; longer
+	// statements are built in the background.
+	SyncMaxDays int `env:"STATEMENT_SYNC_MAX_DAYS"`
+
+	// MonthlyFormat is the format monthly statements are produced in.
+	MonthlyFormat models.StatementFormat `env:"STATEMENT_MONTHLY_FORMAT"`
+
+	// MonthlyCheckInterval is how often users are checked for a monthly
+	// statement that is due.
+	MonthlyCheckInterval time.Duration `env:"STATEMENT_MONTHLY_CHECK_INTERVAL"`
+
+	// PendingTimeout is how long a statement may stay pending before it is
+	// given up on, for example because the instance building it stopped.
+	PendingTimeout time.Duration `env:"STATEMENT_PENDING_TIMEOUT"`
+}
+
+func (c *Config) Validate() error {
+	if c.MaxRangeDays < 1 {
+		return errors.New("statement max range must be at least 1 day")
+	}
+	if c.SyncMaxDays < 0 || c.SyncMaxDays > c.MaxRangeDays {
+		return errors.New("statement sync range must be between 0 and the max range")
+	}
+	if c.MonthlyFormat != models.StatementFormatCSV && c.MonthlyFormat != models.StatementFormatPDF {
+		return errors.New("monthly statement format must be csv or pdf")
+	}
+	if c.MonthlyCheckInterval <= 0 {
+		return errors.New("monthly statement check interval must be positive")
+	}
+	if c.PendingTimeout <= 0 {
+		return errors.New("statement pending timeout must be positive")
+	}
+	return nil
+}
+
+// GetDefaultConfig returns the default statement configuration
+func GetDefaultConfig() *Config {
+	return &Config{
+		MaxRangeDays:         366,
+		SyncMaxDays:          31,
+		MonthlyFormat:        models.StatementFormatPDF,
+		MonthlyCheckInterval: time.Hour,
+		PendingTimeout:       30 * time.Minute,
+	}
+}
@@ -0,0 +1,31 @@
+package statement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, GetDefaultConfig().Validate())
+
+	config := GetDefaultConfig()
+	config.MaxRangeDays = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.SyncMaxDays = config.MaxRangeDays + 1
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.MonthlyFormat = "xlsx"
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.MonthlyCheckInterval = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.PendingTimeout = 0
+	assert.Error(t, config.Validate())
+}
@@ -0,0 +1,214 @@
+package statement
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	timeLayout = "2006-01-02 15:04"
+	dateLayout = "2006-01-02"
+)
+
+// column is one column of a statement table. Amounts are right-aligned in
+// PDFs; references are full IDs, too wide to print and left to the CSV.
+type column struct {
+	name    string
+	amount  bool
+	csvOnly bool
+}
+
+// table is one section of a statement
+type table struct {
+	title   string
+	columns []column
+	rows    [][]string
+}
+
+// document is what a statement says, whatever format it is rendered in
+type document struct {
+	heading [][2]string
+	tables  []table
+}
+
+// dated is a table row with the time it is ordered by
+type dated struct {
+	at  time.Time
+	row []string
+}
+
+// buildDocument lays out the statement's data as a summary of balances
+// followed by the transactions, bets and settlements of the period
+func buildDocument(statement *models.Statement, data *Data, generatedAt time.Time) *document {
+	currencies := make(map[uuid.UUID]string, len(data.Wallets))
+	for i := range data.Wallets {
+		currencies[data.Wallets[i].ID] = data.Wallets[i].CurrencyCode
+	}
+
+	return &document{
+		heading: [][2]string{
+			{"Account", statement.UserID.String()},
+			{"Period", statement.PeriodStart.Format(dateLayout) + " to " + statement.PeriodEnd.Format(dateLayout)},
+			{"Generated", generatedAt.UTC().Format(timeLayout) + " UTC"},
+		},
+		tables: []table{
+			balancesTable(data),
+			transactionsTable(data, currencies),
+			betsTable(data),
+			settlementsTable(data),
+		},
+	}
+}
+
+func balancesTable(data *Data) table {
+	t := table{
+		title: "Balances",
+		columns: []column{
+			{name: "Currency"}, {name: "Opening", amount: true}, {name: "Credits", amount: true},
+			{name: "Debits", amount: true}, {name: "Closing", amount: true},
+		},
+	}
+	for i := range data.Wallets {
+		wallet := &data.Wallets[i]
+		credits, debits := decimal.Zero, decimal.Zero
+		for j := range data.Transactions {
+			tx := &data.Transactions[j]
+			switch {
+			case tx.WalletID != wallet.ID:
+			case tx.IsCredit():
+				credits = credits.Add(tx.Amount)
+			case tx.IsDebit():
+				debits = debits.Add(tx.Amount)
+			}
+		}
+		t.rows = append(t.rows, []string{
+			wallet.CurrencyCode,
+			money(data.OpeningBalances[wallet.ID]),
+			money(credits),
+			money(debits),
+			money(data.ClosingBalances[wallet.ID]),
+		})
+	}
+	return t
+}
+
+func transactionsTable(data *Data, currencies map[uuid.UUID]string) table {
+	t := table{
+		title: "Transactions",
+		columns: []column{
+			{name: "Date"}, {name: "Reference", csvOnly: true}, {name: "Currency"}, {name: "Type"},
+			{name: "Description"}, {name: "Amount", amount: true}, {name: "Balance", amount: true},
+		},
+	}
+	for i := range data.Transactions {
+		tx := &data.Transactions[i]
+		t.rows = append(t.rows, []string{
+			stamp(tx.CreatedAt), tx.ID.String(), currencies[tx.WalletID], string(tx.TransactionType),
+			tx.Description, money(tx.Amount), money(tx.BalanceAfter),
+		})
+	}
+	return t
+}
+
+func betsTable(data *Data) table {
+	var rows []dated
+	for i := range data.Bets {
+		bet := &data.Bets[i]
+		rows = append(rows, dated{bet.CreatedAt, []string{
+			stamp(bet.CreatedAt), bet.ID.String(), marketCurrency(bet.Market), "single",
+			selection(bet.Market, bet.MarketOutcome), money(bet.Amount), string(bet.Status),
+		}})
+	}
+	for i := range data.Parlays {
+		parlay := &data.Parlays[i]
+		rows = append(rows, dated{parlay.CreatedAt, []string{
+			stamp(parlay.CreatedAt), parlay.ID.String(), parlay.CurrencyCode, "parlay",
+			fmt.Sprintf("%d-leg parlay", len(parlay.Legs)), money(parlay.Stake), string(parlay.Status),
+		}})
+	}
+	return table{
+		title: "Bets placed",
+		columns: []column{
+			{name: "Date"}, {name: "Reference", csvOnly: true}, {name: "Currency"}, {name: "Bet"},
+			{name: "Selection"}, {name: "Stake", amount: true}, {name: "Status"},
+		},
+		rows: byTime(rows),
+	}
+}
+
+func settlementsTable(data *Data) table {
+	var rows []dated
+	for i := range data.Settlements {
+		settlement := &data.Settlements[i]
+		var outcome *models.MarketOutcome
+		if settlement.Bet != nil {
+			outcome = settlement.Bet.MarketOutcome
+		}
+		rows = append(rows, dated{settlement.CreatedAt, []string{
+			stamp(settlement.CreatedAt), settlement.BetID.String(), marketCurrency(settlement.Market), "single",
+			selection(settlement.Market, outcome), string(settlement.SettlementType),
+			money(settlement.OriginalAmount), money(settlement.PayoutAmount), money(settlement.GetNetAmount()),
+		}})
+	}
+	for i := range data.SettledParlays {
+		parlay := &data.SettledParlays[i]
+		payout := decimal.Zero
+		if parlay.SettlementAmount != nil {
+			payout = *parlay.SettlementAmount
+		}
+		rows = append(rows, dated{*parlay.SettledAt, []string{
+			stamp(*parlay.SettledAt), parlay.ID.String(), parlay.CurrencyCode, "parlay",
+			fmt.Sprintf("%d-leg parlay", len(parlay.Legs)), string(parlay.Status),
+			money(parlay.Stake), money(payout), money(payout.Sub(parlay.Stake)),
+		}})
+	}
+	return table{
+		title: "Settlements",
+		columns: []column{
+			{name: "Date"}, {name: "Reference", csvOnly: true}, {name: "Currency"}, {name: "Bet"},
+			{name: "Selection"}, {name: "Result"}, {name: "Stake", amount: true},
+			{name: "Payout", amount: true}, {name: "Net", amount: true},
+		},
+		rows: byTime(rows),
+	}
+}
+
+// byTime orders rows from different sources by when they happened
+func byTime(rows []dated) [][]string {
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].at.Before(rows[j].at) })
+	sorted := make([][]string, len(rows))
+	for i := range rows {
+		sorted[i] = rows[i].row
+	}
+	return sorted
+}
+
+func selection(market *models.Market, outcome *models.MarketOutcome) string {
+	if market == nil {
+		return ""
+	}
+	if outcome == nil {
+		return market.Title
+	}
+	return market.Title + ": " + outcome.OutcomeLabel
+}
+
+func marketCurrency(market *models.Market) string {
+	if market == nil || market.Country == nil {
+		return ""
+	}
+	return market.Country.CurrencyCode
+}
+
+func money(amount decimal.Decimal) string {
+	return amount.StringFixed(2)
+}
+
+func stamp(at time.Time) string {
+	return at.UTC().Format(timeLayout)
+}
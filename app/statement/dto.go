@@ -0,0 +1,71 @@
+package statement
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// StatementRequest asks for a statement between two dates, both included.
+type StatementRequest struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Format string `json:"format"`
+
+	ParsedFrom time.Time `json:"-"`
+	ParsedTo   time.Time `json:"-"`
+}
+
+// Validate parses the dates and checks the period. Format defaults to PDF.
+func (r *StatementRequest) Validate(v *validator.Validator, config *Config, now time.Time) bool {
+	if r.Format == "" {
+		r.Format = string(models.StatementFormatPDF)
+	}
+	v.Check(validator.In(models.StatementFormat(r.Format), models.StatementFormatCSV, models.StatementFormatPDF),
+		"format", "format must be csv or pdf")
+
+	from, fromErr := time.Parse("2006-01-02", r.From)
+	v.Check(fromErr == nil, "from", "from must be a date in YYYY-MM-DD format")
+	to, toErr := time.Parse("2006-01-02", r.To)
+	v.Check(toErr == nil, "to", "to must be a date in YYYY-MM-DD format")
+	if fromErr != nil || toErr != nil {
+		return v.Valid()
+	}
+
+	days := int(to.Sub(from).Hours()/24) + 1
+	v.Check(!to.Before(from), "to", "to must not be before from")
+	v.Check(!to.After(now), "to", "to must not be in the future")
+	v.Check(days <= config.MaxRangeDays, "to", "the period is too long")
+	r.ParsedFrom, r.ParsedTo = from, to
+	return v.Valid()
+}
+
+// StatementResponse describes a statement and whether it can be downloaded
+type StatementResponse struct {
+	ID          uuid.UUID              `json:"id"`
+	Kind        models.StatementKind   `json:"kind"`
+	Format      models.StatementFormat `json:"format"`
+	PeriodStart string                 `json:"period_start"`
+	PeriodEnd   string                 `json:"period_end"`
+	Status      models.StatementStatus `json:"status"`
+	Error       string                 `json:"error,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// ToStatementResponse converts a statement model to StatementResponse
+func ToStatementResponse(statement *models.Statement) *StatementResponse {
+	return &StatementResponse{
+		ID:          statement.ID,
+		Kind:        statement.Kind,
+		Format:      statement.Format,
+		PeriodStart: statement.PeriodStart.Format("2006-01-02"),
+		PeriodEnd:   statement.PeriodEnd.Format("2006-01-02"),
+		Status:      statement.Status,
+		Error:       statement.Error,
+		CompletedAt: statement.CompletedAt,
+		CreatedAt:   statement.CreatedAt,
+	}
+}
@@ -0,0 +1,225 @@
+package statement
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// Handler handles HTTP requests for account statements
+type Handler struct {
+	service Service
+	config  *Config
+	logger  logger.Logger
+}
+
+// NewHandler creates a new statement handler
+func NewHandler(service Service, config *Config, lg logger.Logger) *Handler {
+	return &Handler{service: service, config: config, logger: lg}
+}
+
+// GetStatements godoc
+// @Summary      List account statements
+// @Description  List the authenticated user's statements, both requested and monthly, latest period first
+// @Tags         statements
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  api.Response{data=[]StatementResponse}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/statements [get]
+func (h *Handler) GetStatements(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+	h.listStatements(c, userID)
+}
+
+// RequestStatement godoc
+// @Summary      Request an account statement
+// @Description  Generate a CSV or PDF statement of bets, settlements and wallet transactions between two dates, with opening and closing balances. Short periods are ready at once; longer ones are built in the background
+// @Tags         statements
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body StatementRequest true "Statement period and format"
+// @Success      201  {object}  api.Response{data=StatementResponse}
+// @Success      202  {object}  api.Response{data=StatementResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/statements [post]
+func (h *Handler) RequestStatement(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+	h.requestStatement(c, userID)
+}
+
+// DownloadStatement godoc
+// @Summary      Download an account statement
+// @Description  Download one of the authenticated user's statements once it is ready
+// @Tags         statements
+// @Produce      text/csv
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Statement ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      401  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/users/statements/{id}/download [get]
+func (h *Handler) DownloadStatement(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+	h.downloadStatement(c, userID, c.Param("id"))
+}
+
+// GetUserStatements godoc
+// @Summary      List a user's account statements
+// @Description  List a user's statements for support, latest period first
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  api.Response{data=[]StatementResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      403  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/users/{id}/statements [get]
+func (h *Handler) GetUserStatements(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid user ID format")
+		return
+	}
+	h.listStatements(c, userID)
+}
+
+// RequestUserStatement godoc
+// @Summary      Request an account statement for a user
+// @Description  Generate a statement for a user on behalf of support. Short periods are ready at once; longer ones are built in the background
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string            true  "User ID"
+// @Param        request  body  StatementRequest  true  "Statement period and format"
+// @Success      201  {object}  api.Response{data=StatementResponse}
+// @Success      202  {object}  api.Response{data=StatementResponse}
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      403  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/users/{id}/statements [post]
+func (h *Handler) RequestUserStatement(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid user ID format")
+		return
+	}
+	h.requestStatement(c, userID)
+}
+
+// DownloadUserStatement godoc
+// @Summary      Download a user's account statement
+// @Description  Download one of a user's statements for support once it is ready
+// @Tags         admin
+// @Produce      text/csv
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param        id            path  string  true  "User ID"
+// @Param        statement_id  path  string  true  "Statement ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      403  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      404  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      409  {object}  api.Response{error=api.ErrorInfo}
+// @Failure      500  {object}  api.Response{error=api.ErrorInfo}
+// @Router       /api/v1/admin/users/{id}/statements/{statement_id}/download [get]
+func (h *Handler) DownloadUserStatement(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid user ID format")
+		return
+	}
+	h.downloadStatement(c, userID, c.Param("statement_id"))
+}
+
+func (h *Handler) listStatements(c *gin.Context, userID uuid.UUID) {
+	statements, err := h.service.GetStatements(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "GetStatements", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to retrieve statements")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Statements retrieved successfully", statements)
+}
+
+func (h *Handler) requestStatement(c *gin.Context, userID uuid.UUID) {
+	var req StatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	if !req.Validate(v, h.config, time.Now()) {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	statement, err := h.service.RequestStatement(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "RequestStatement", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to generate statement")
+		return
+	}
+
+	if statement.Status == models.StatementStatusReady {
+		api.CreatedResponse(c, "Statement is ready to download", statement)
+		return
+	}
+	api.SuccessResponse(c, http.StatusAccepted, "Statement is being prepared", statement)
+}
+
+func (h *Handler) downloadStatement(c *gin.Context, userID uuid.UUID, rawStatementID string) {
+	statementID, err := uuid.Parse(rawStatementID)
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid statement ID format")
+		return
+	}
+
+	statement, err := h.service.GetStatementFile(c.Request.Context(), userID, statementID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRecordNotFound):
+			api.NotFoundResponse(c, "Statement")
+		case errors.Is(err, models.ErrStatementNotReady):
+			api.ConflictResponse(c, "Statement is not ready")
+		default:
+			h.logger.Error(err, logger.Fields{"handler": "DownloadStatement", "user_id": userID, "statement_id": statementID})
+			api.InternalErrorResponse(c, "Failed to download statement")
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+statement.FileName()+`"`)
+	c.Data(http.StatusOK, statement.ContentType(), statement.Content)
+}
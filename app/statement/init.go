@@ -0,0 +1,63 @@
+package statement
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/deps"
+)
+
+const (
+	RepoKey    = "statement_repository"
+	ServiceKey = "statement_service"
+	ConfigKey  = "statement_config"
+)
+
+// MountAuthenticated mounts the user's statement routes
+func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	statementsGroup := r.Group("/users/statements")
+	statementsGroup.GET("", handler.GetStatements)
+	statementsGroup.POST("", handler.RequestStatement)
+	statementsGroup.GET("/:id/download", handler.DownloadStatement)
+}
+
+// MountAdmin mounts the statement routes support uses on users' behalf
+func MountAdmin(r *gin.RouterGroup, container *deps.Container) {
+	handler := createHandler(container)
+
+	adminGroup := r.Group("/admin/users/:id/statements")
+	adminGroup.GET("", api.Can(PermissionStatementsRead), handler.GetUserStatements)
+	adminGroup.POST("", api.Can(PermissionStatementsCreate), handler.RequestUserStatement)
+	adminGroup.GET("/:statement_id/download", api.Can(PermissionStatementsRead), handler.DownloadUserStatement)
+}
+
+// InitRepositories initializes and registers repositories and services for this module
+func InitRepositories(container *deps.Container) {
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid statement configuration: " + err.Error())
+	}
+
+	repo := NewRepository(container.DB)
+	container.RegisterRepository(RepoKey, repo)
+	container.RegisterService(ServiceKey, NewService(repo, config, container.Logger))
+	container.RegisterService(ConfigKey, config)
+}
+
+// StartJobs starts the module's background jobs. They run until ctx is done.
+func StartJobs(ctx context.Context, container *deps.Container) {
+	config := container.GetService(ConfigKey).(*Config)
+	generator := container.GetService(ServiceKey).(MonthlyGenerator)
+	go RunMonthlyStatements(ctx, generator, config.MonthlyCheckInterval)
+}
+
+// createHandler creates a statement handler with all dependencies
+func createHandler(container *deps.Container) *Handler {
+	service := container.GetService(ServiceKey).(Service)
+	config := container.GetService(ConfigKey).(*Config)
+
+	return NewHandler(service, config, container.Logger)
+}
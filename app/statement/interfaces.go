@@ -0,0 +1,61 @@
+package statement
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+)
+
+type Repository interface {
+	CreateStatement(ctx context.Context, statement *models.Statement) error
+	// CompleteStatement records a built or failed statement, unless it is no
+	// longer pending, and reports whether it did.
+	CompleteStatement(ctx context.Context, statement *models.Statement) (bool, error)
+	// FailStaleStatements fails the statements still pending that were
+	// created before before, and returns how many there were.
+	FailStaleStatements(ctx context.Context, before time.Time, reason string) (int64, error)
+	GetStatement(ctx context.Context, userID, statementID uuid.UUID) (*models.Statement, error)
+	GetStatements(ctx context.Context, userID uuid.UUID) ([]models.Statement, error)
+	// GetUsersDueMonthlyStatement returns the users with ledger activity
+	// between from and to who have no monthly statement starting at from,
+	// other than ones that failed.
+	GetUsersDueMonthlyStatement(ctx context.Context, from, to time.Time) ([]uuid.UUID, error)
+
+	GetStatementData(ctx context.Context, userID uuid.UUID, from, to time.Time) (*Data, error)
+
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+}
+
+// Data is everything a statement covers, for activity from up to but not
+// including to
+type Data struct {
+	Wallets []models.Wallet
+	// OpeningBalances and ClosingBalances are keyed by wallet ID. A wallet
+	// without ledger entries before a point had a zero balance there.
+	OpeningBalances map[uuid.UUID]decimal.Decimal
+	ClosingBalances map[uuid.UUID]decimal.Decimal
+	Transactions    []models.Transaction
+	Bets            []models.Bet
+	Parlays         []models.Parlay
+	Settlements     []models.Settlement
+	SettledParlays  []models.Parlay
+}
+
+// MonthlyGenerator produces last month's statement for every user with
+// activity in it who does not have one yet, after giving up on statements
+// left pending too long
+type MonthlyGenerator interface {
+	GenerateMonthlyStatements(ctx context.Context, now time.Time) (int, error)
+}
+
+type Service interface {
+	MonthlyGenerator
+
+	RequestStatement(ctx context.Context, userID uuid.UUID, req *StatementRequest) (*StatementResponse, error)
+	GetStatements(ctx context.Context, userID uuid.UUID) ([]StatementResponse, error)
+	// GetStatementFile returns a ready statement with its file content.
+	GetStatementFile(ctx context.Context, userID, statementID uuid.UUID) (*models.Statement, error)
+}
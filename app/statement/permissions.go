@@ -0,0 +1,9 @@
+package statement
+
+import "github.com/joefazee/neo/app/api"
+
+// Permissions required by the admin routes of this module.
+var (
+	PermissionStatementsRead   = api.DefinePermission("admin:statements:read", "List and download users' account statements")
+	PermissionStatementsCreate = api.DefinePermission("admin:statements:create", "Generate account statements for users")
+)
@@ -0,0 +1,153 @@
+package statement
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/joefazee/neo/internal/pdf"
+	"github.com/joefazee/neo/models"
+)
+
+// minColumnWidth is as narrow as a PDF column is squeezed to fit the page
+const minColumnWidth = 8
+
+// render writes doc in the statement's format
+func render(w io.Writer, format models.StatementFormat, doc *document) error {
+	if format == models.StatementFormatCSV {
+		return renderCSV(w, doc)
+	}
+	return renderPDF(w, doc)
+}
+
+// renderCSV writes each section as a title row, a header row and its rows,
+// with a blank row between sections
+func renderCSV(w io.Writer, doc *document) error {
+	out := csv.NewWriter(w)
+	records := [][]string{{"Account statement"}}
+	for _, field := range doc.heading {
+		records = append(records, []string{field[0], field[1]})
+	}
+	for _, t := range doc.tables {
+		header := make([]string, len(t.columns))
+		for i, c := range t.columns {
+			header[i] = c.name
+		}
+		records = append(records, []string{}, []string{t.title}, header)
+		for _, row := range t.rows {
+			record := make([]string, len(row))
+			for i, cell := range row {
+				if t.columns[i].amount {
+					record[i] = cell
+				} else {
+					record[i] = escapeFormula(cell)
+				}
+			}
+			records = append(records, record)
+		}
+	}
+	if err := out.WriteAll(records); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	return nil
+}
+
+// escapeFormula stops spreadsheets from running text that looks like a
+// formula, such as a market title starting with "="
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func renderPDF(w io.Writer, doc *document) error {
+	out := pdf.NewDocument()
+	out.AddLines("Account statement", "")
+	for _, field := range doc.heading {
+		out.AddLine(fmt.Sprintf("%-10s %s", field[0]+":", field[1]))
+	}
+	for _, t := range doc.tables {
+		out.AddLines("", t.title, strings.Repeat("=", len(t.title)))
+		if len(t.rows) == 0 {
+			out.AddLine("None in this period")
+			continue
+		}
+		out.AddLines(layoutTable(t, pdf.LineWidth)...)
+	}
+	if _, err := out.WriteTo(w); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
+	}
+	return nil
+}
+
+// layoutTable formats a table as fixed-width lines no wider than width. When
+// the columns do not fit, the widest text column is narrowed and its cells
+// cut short.
+func layoutTable(t table, width int) []string {
+	var shown []int
+	for i, c := range t.columns {
+		if !c.csvOnly {
+			shown = append(shown, i)
+		}
+	}
+
+	widths := make(map[int]int, len(shown))
+	for _, i := range shown {
+		widths[i] = utf8.RuneCountInString(t.columns[i].name)
+		for _, row := range t.rows {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+	}
+	for total := lineLength(widths); total > width; total = lineLength(widths) {
+		widest := -1
+		for _, i := range shown {
+			if !t.columns[i].amount && widths[i] > minColumnWidth && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest] = max(minColumnWidth, widths[widest]-(total-width))
+	}
+
+	line := func(cells func(i int) string) string {
+		parts := make([]string, len(shown))
+		for j, i := range shown {
+			parts[j] = pad(cells(i), widths[i], t.columns[i].amount)
+		}
+		return strings.TrimRight(strings.Join(parts, "  "), " ")
+	}
+	lines := []string{
+		line(func(i int) string { return t.columns[i].name }),
+		line(func(i int) string { return strings.Repeat("-", widths[i]) }),
+	}
+	for _, row := range t.rows {
+		lines = append(lines, line(func(i int) string { return row[i] }))
+	}
+	return lines
+}
+
+func lineLength(widths map[int]int) int {
+	total := 2 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	return total
+}
+
+// pad fits text to width, cutting it short or filling it out with spaces
+func pad(text string, width int, right bool) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	fill := strings.Repeat(" ", width-len(runes))
+	if right {
+		return fill + text
+	}
+	return text + fill
+}
@@ -0,0 +1,160 @@
+package statement
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new statement repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateStatement(ctx context.Context, statement *models.Statement) error {
+	return r.db.WithContext(ctx).Create(statement).Error
+}
+
+func (r *repository) CompleteStatement(ctx context.Context, statement *models.Statement) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(statement).
+		Where("status = ?", models.StatementStatusPending).
+		Select("status", "content", "error", "completed_at", "updated_at").
+		Updates(statement)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) FailStaleStatements(ctx context.Context, before time.Time, reason string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Statement{}).
+		Where("status = ? AND created_at < ?", models.StatementStatusPending, before).
+		Updates(map[string]interface{}{
+			"status":       models.StatementStatusFailed,
+			"error":        reason,
+			"completed_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *repository) GetStatement(ctx context.Context, userID, statementID uuid.UUID) (*models.Statement, error) {
+	var statement models.Statement
+	err := r.db.WithContext(ctx).First(&statement, "id = ? AND user_id = ?", statementID, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// GetStatements lists the user's statements, latest period first, without
+// their file content.
+func (r *repository) GetStatements(ctx context.Context, userID uuid.UUID) ([]models.Statement, error) {
+	var statements []models.Statement
+	err := r.db.WithContext(ctx).
+		Omit("content").
+		Where("user_id = ?", userID).
+		Order("period_start DESC, created_at DESC").
+		Find(&statements).Error
+	return statements, err
+}
+
+func (r *repository) GetUsersDueMonthlyStatement(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Distinct("user_id").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Where("NOT EXISTS (SELECT 1 FROM statements s WHERE s.user_id = transactions.user_id "+
+			"AND s.kind = ? AND s.period_start = ? AND s.status <> ?)",
+			models.StatementKindMonthly, from, models.StatementStatusFailed).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// GetStatementData loads the period's activity, oldest first. Bets and
+// settlements carry their market and its country, for titles and currencies.
+func (r *repository) GetStatementData(ctx context.Context, userID uuid.UUID, from, to time.Time) (*Data, error) {
+	data := &Data{}
+	db := r.db.WithContext(ctx)
+	inPeriod := func(column string) string { return column + " >= ? AND " + column + " < ?" }
+
+	if err := db.Where("user_id = ? AND created_at < ?", userID, to).Order("currency_code").Find(&data.Wallets).Error; err != nil {
+		return nil, err
+	}
+	var err error
+	if data.OpeningBalances, err = r.balancesAt(ctx, userID, from); err != nil {
+		return nil, err
+	}
+	if data.ClosingBalances, err = r.balancesAt(ctx, userID, to); err != nil {
+		return nil, err
+	}
+
+	err = db.Where("user_id = ?", userID).Where(inPeriod("created_at"), from, to).
+		Order("created_at").Find(&data.Transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Where("user_id = ?", userID).Where(inPeriod("created_at"), from, to).
+		Preload("Market.Country").Preload("MarketOutcome").
+		Order("created_at").Find(&data.Bets).Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Where("user_id = ?", userID).Where(inPeriod("created_at"), from, to).
+		Preload("Legs").Order("created_at").Find(&data.Parlays).Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Where("user_id = ?", userID).Where(inPeriod("created_at"), from, to).
+		Preload("Market.Country").Preload("Bet.MarketOutcome").
+		Order("created_at").Find(&data.Settlements).Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Where("user_id = ?", userID).Where(inPeriod("settled_at"), from, to).
+		Preload("Legs").Order("settled_at").Find(&data.SettledParlays).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// balancesAt returns each wallet's balance after its last ledger entry
+// before at
+func (r *repository) balancesAt(ctx context.Context, userID uuid.UUID, at time.Time) (map[uuid.UUID]decimal.Decimal, error) {
+	var rows []struct {
+		WalletID     uuid.UUID
+		BalanceAfter decimal.Decimal
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Select("DISTINCT ON (wallet_id) wallet_id, balance_after").
+		Where("user_id = ? AND created_at < ?", userID, at).
+		Order("wallet_id, created_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[uuid.UUID]decimal.Decimal, len(rows))
+	for _, row := range rows {
+		balances[row.WalletID] = row.BalanceAfter
+	}
+	return balances, nil
+}
+
+func (r *repository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
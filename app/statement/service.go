@@ -0,0 +1,200 @@
+package statement
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+type service struct {
+	repo     Repository
+	config   *Config
+	logger   logger.Logger
+	runAsync func(func())
+}
+
+// NewService creates a new statement service.
+func NewService(repo Repository, config *Config, lg logger.Logger) Service {
+	return &service{
+		repo:     repo,
+		config:   config,
+		logger:   lg,
+		runAsync: func(f func()) { go f() },
+	}
+}
+
+// RequestStatement creates a statement for the period asked for. Short
+// periods are built before returning; longer ones are returned pending and
+// built in the background.
+func (s *service) RequestStatement(ctx context.Context, userID uuid.UUID, req *StatementRequest) (*StatementResponse, error) {
+	statement := &models.Statement{
+		UserID:      userID,
+		Kind:        models.StatementKindRequested,
+		Format:      models.StatementFormat(req.Format),
+		PeriodStart: req.ParsedFrom,
+		PeriodEnd:   req.ParsedTo,
+		Status:      models.StatementStatusPending,
+	}
+	if err := s.repo.CreateStatement(ctx, statement); err != nil {
+		return nil, fmt.Errorf("failed to create statement: %w", err)
+	}
+
+	log := audit.NewLog(ctx, models.AuditActionStatementIssued, models.AuditResourceUser, &userID, nil,
+		models.AuditValues{"statement_id": statement.ID.String()})
+	if err := s.repo.CreateAuditLog(ctx, log); err != nil {
+		return nil, fmt.Errorf("failed to create audit log: %w", err)
+	}
+
+	if statement.PeriodDays() > s.config.SyncMaxDays {
+		pending := *statement
+		s.runAsync(func() { _ = s.buildStatement(context.Background(), &pending) })
+		return ToStatementResponse(statement), nil
+	}
+
+	if err := s.buildStatement(ctx, statement); err != nil {
+		return nil, err
+	}
+	return ToStatementResponse(statement), nil
+}
+
+// statementFailedReason is what users are told about a statement that could
+// not be built
+const statementFailedReason = "the statement could not be generated, please request a new one"
+
+// buildStatement renders the statement file and records the outcome on the
+// statement. A statement given up on as stale in the meantime is left failed.
+func (s *service) buildStatement(ctx context.Context, statement *models.Statement) error {
+	content, buildErr := s.renderStatement(ctx, statement)
+	if buildErr != nil {
+		s.logger.Error(buildErr, logger.Fields{"component": "statement", "statement_id": statement.ID, "user_id": statement.UserID})
+		statement.MarkFailed(statementFailedReason, time.Now())
+	} else {
+		statement.MarkReady(content, time.Now())
+	}
+
+	completed, err := s.repo.CompleteStatement(ctx, statement)
+	if err != nil {
+		s.logger.Error(err, logger.Fields{"component": "statement", "statement_id": statement.ID, "user_id": statement.UserID})
+		if buildErr == nil {
+			return fmt.Errorf("failed to update statement: %w", err)
+		}
+	}
+	if !completed && err == nil && buildErr == nil {
+		return fmt.Errorf("statement %s is no longer pending", statement.ID)
+	}
+	return buildErr
+}
+
+func (s *service) renderStatement(ctx context.Context, statement *models.Statement) ([]byte, error) {
+	from := statement.PeriodStart
+	to := statement.PeriodEnd.AddDate(0, 0, 1)
+	data, err := s.repo.GetStatementData(ctx, statement.UserID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement data: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf, statement.Format, buildDocument(statement, data, time.Now())); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetStatements lists the user's statements, latest period first.
+func (s *service) GetStatements(ctx context.Context, userID uuid.UUID) ([]StatementResponse, error) {
+	statements, err := s.repo.GetStatements(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statements: %w", err)
+	}
+	responses := make([]StatementResponse, len(statements))
+	for i := range statements {
+		responses[i] = *ToStatementResponse(&statements[i])
+	}
+	return responses, nil
+}
+
+func (s *service) GetStatementFile(ctx context.Context, userID, statementID uuid.UUID) (*models.Statement, error) {
+	statement, err := s.repo.GetStatement(ctx, userID, statementID)
+	if err != nil {
+		return nil, err
+	}
+	if !statement.IsReady() {
+		return nil, models.ErrStatementNotReady
+	}
+	return statement, nil
+}
+
+// RunMonthlyStatements produces monthly statements every interval until ctx
+// is done. Checking often is cheap, since users who already have last
+// month's statement are skipped, and it lets a month that failed be retried.
+func RunMonthlyStatements(ctx context.Context, generator MonthlyGenerator, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := generator.GenerateMonthlyStatements(ctx, time.Now()); err != nil {
+			log.Printf("Warning: Failed to generate monthly statements: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GenerateMonthlyStatements produces the statement for the month before now
+// for every user with ledger activity in it, and returns how many were
+// produced. One user's statement failing does not hold up the rest.
+//
+// Statements pending for longer than the timeout are failed first: whatever
+// was building them has stopped, and a pending monthly statement would
+// otherwise keep its month from being produced again.
+func (s *service) GenerateMonthlyStatements(ctx context.Context, now time.Time) (int, error) {
+	stale, err := s.repo.FailStaleStatements(ctx, now.Add(-s.config.PendingTimeout), statementFailedReason)
+	if err != nil {
+		return 0, fmt.Errorf("fail stale statements: %w", err)
+	}
+	if stale > 0 {
+		log.Printf("Warning: Gave up on %d statements left pending", stale)
+	}
+
+	now = now.UTC()
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, -1, 0)
+
+	userIDs, err := s.repo.GetUsersDueMonthlyStatement(ctx, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("get users due a statement: %w", err)
+	}
+
+	produced := 0
+	for _, userID := range userIDs {
+		statement := &models.Statement{
+			UserID:      userID,
+			Kind:        models.StatementKindMonthly,
+			Format:      s.config.MonthlyFormat,
+			PeriodStart: from,
+			PeriodEnd:   to.AddDate(0, 0, -1),
+			Status:      models.StatementStatusPending,
+		}
+		// Another instance may have got there first; the unique index turns
+		// that into an error here
+		if err := s.repo.CreateStatement(ctx, statement); err != nil {
+			log.Printf("Warning: Failed to create monthly statement for user %s: %v", userID, err)
+			continue
+		}
+		if err := s.buildStatement(ctx, statement); err == nil {
+			produced++
+		}
+	}
+	return produced, nil
+}
@@ -0,0 +1,303 @@
+package statement
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateStatement(ctx context.Context, statement *models.Statement) error {
+	return m.Called(ctx, statement).Error(0)
+}
+
+func (m *MockRepository) CompleteStatement(ctx context.Context, statement *models.Statement) (bool, error) {
+	args := m.Called(ctx, statement)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) FailStaleStatements(ctx context.Context, before time.Time, reason string) (int64, error) {
+	args := m.Called(ctx, before, reason)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetStatement(ctx context.Context, userID, statementID uuid.UUID) (*models.Statement, error) {
+	args := m.Called(ctx, userID, statementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Statement), args.Error(1)
+}
+
+func (m *MockRepository) GetStatements(ctx context.Context, userID uuid.UUID) ([]models.Statement, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Statement), args.Error(1)
+}
+
+func (m *MockRepository) GetUsersDueMonthlyStatement(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) GetStatementData(ctx context.Context, userID uuid.UUID, from, to time.Time) (*Data, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Data), args.Error(1)
+}
+
+func (m *MockRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return m.Called(ctx, log).Error(0)
+}
+
+func newTestService(t *testing.T, repo Repository) *service {
+	t.Helper()
+	s := NewService(repo, GetDefaultConfig(), logger.NewNullLogger()).(*service)
+	s.runAsync = func(f func()) { f() }
+	return s
+}
+
+// expectCreate gives created statements an ID, as the database would
+func expectCreate(repo *MockRepository) {
+	repo.On("CreateStatement", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { args.Get(1).(*models.Statement).ID = uuid.New() }).
+		Return(nil)
+}
+
+func marchDay(d int) time.Time {
+	return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC)
+}
+
+func statementData() *Data {
+	wallet := models.Wallet{ID: uuid.New(), CurrencyCode: "NGN"}
+	outcome := &models.MarketOutcome{ID: uuid.New(), OutcomeLabel: "Yes"}
+	market := &models.Market{ID: uuid.New(), Title: "=HYPERLINK(\"x\")", Country: &models.Country{CurrencyCode: "NGN"}}
+
+	return &Data{
+		Wallets:         []models.Wallet{wallet},
+		OpeningBalances: map[uuid.UUID]decimal.Decimal{wallet.ID: decimal.NewFromInt(1000)},
+		ClosingBalances: map[uuid.UUID]decimal.Decimal{wallet.ID: decimal.NewFromInt(800)},
+		Transactions: []models.Transaction{
+			{
+				ID: uuid.New(), WalletID: wallet.ID, TransactionType: models.TransactionTypeBetPlace,
+				Amount: decimal.NewFromInt(-200), BalanceAfter: decimal.NewFromInt(800),
+				Description: "Bet placed", CreatedAt: marchDay(3).Add(9 * time.Hour),
+			},
+		},
+		Bets: []models.Bet{
+			{
+				ID: uuid.New(), Market: market, MarketOutcome: outcome, Amount: decimal.NewFromInt(200),
+				Status: models.BetStatusActive, CreatedAt: marchDay(3).Add(9 * time.Hour),
+			},
+		},
+	}
+}
+
+func TestService_RequestStatement(t *testing.T) {
+	t.Run("Short period is ready at once", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := newTestService(t, repo)
+		userID := uuid.New()
+
+		expectCreate(repo)
+		repo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(log *models.AuditLog) bool {
+			return log.Action == models.AuditActionStatementIssued
+		})).Return(nil)
+		repo.On("GetStatementData", mock.Anything, userID, marchDay(1), marchDay(8)).Return(statementData(), nil)
+		var updated *models.Statement
+		repo.On("CompleteStatement", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*models.Statement) }).
+			Return(true, nil)
+
+		resp, err := s.RequestStatement(context.Background(), userID, &StatementRequest{
+			Format: "csv", ParsedFrom: marchDay(1), ParsedTo: marchDay(7),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatementStatusReady, resp.Status)
+		assert.Equal(t, "2024-03-07", resp.PeriodEnd)
+
+		csv := string(updated.Content)
+		assert.Contains(t, csv, "NGN,1000.00,0.00,-200.00,800.00", "opening, credits, debits and closing balances")
+		assert.Contains(t, csv, "'=HYPERLINK", "formulas are not left for spreadsheets to run")
+		assert.Contains(t, csv, "-200.00,800.00", "amounts are not escaped")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Long period is built in the background", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := newTestService(t, repo)
+		var deferred func()
+		s.runAsync = func(f func()) { deferred = f }
+		userID := uuid.New()
+
+		expectCreate(repo)
+		repo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+		repo.On("GetStatementData", mock.Anything, userID, marchDay(1), marchDay(1).AddDate(0, 3, 0)).Return(statementData(), nil)
+		var updated *models.Statement
+		repo.On("CompleteStatement", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*models.Statement) }).
+			Return(true, nil)
+
+		resp, err := s.RequestStatement(context.Background(), userID, &StatementRequest{
+			Format: "pdf", ParsedFrom: marchDay(1), ParsedTo: marchDay(1).AddDate(0, 3, -1),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatementStatusPending, resp.Status)
+		require.NotNil(t, deferred)
+
+		deferred()
+		require.NotNil(t, updated)
+		assert.True(t, updated.IsReady())
+		assert.True(t, strings.HasPrefix(string(updated.Content), "%PDF-"))
+	})
+
+	t.Run("Statement given up on in the meantime stays failed", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := newTestService(t, repo)
+		userID := uuid.New()
+
+		expectCreate(repo)
+		repo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+		repo.On("GetStatementData", mock.Anything, userID, mock.Anything, mock.Anything).Return(statementData(), nil)
+		repo.On("CompleteStatement", mock.Anything, mock.Anything).Return(false, nil)
+
+		_, err := s.RequestStatement(context.Background(), userID, &StatementRequest{
+			Format: "csv", ParsedFrom: marchDay(1), ParsedTo: marchDay(7),
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Failure is recorded on the statement", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := newTestService(t, repo)
+		userID := uuid.New()
+
+		expectCreate(repo)
+		repo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+		repo.On("GetStatementData", mock.Anything, userID, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+		repo.On("CompleteStatement", mock.Anything, mock.MatchedBy(func(statement *models.Statement) bool {
+			return statement.Status == models.StatementStatusFailed && statement.Content == nil
+		})).Return(true, nil)
+
+		_, err := s.RequestStatement(context.Background(), userID, &StatementRequest{
+			Format: "csv", ParsedFrom: marchDay(1), ParsedTo: marchDay(7),
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestService_GetStatementFile(t *testing.T) {
+	repo := new(MockRepository)
+	s := newTestService(t, repo)
+	userID := uuid.New()
+	pending := &models.Statement{ID: uuid.New(), UserID: userID, Status: models.StatementStatusPending}
+	ready := &models.Statement{
+		ID: uuid.New(), UserID: userID, Format: models.StatementFormatCSV, Status: models.StatementStatusReady,
+		PeriodStart: marchDay(1), PeriodEnd: marchDay(31), Content: []byte("Date,Amount"),
+	}
+	repo.On("GetStatement", mock.Anything, userID, pending.ID).Return(pending, nil)
+	repo.On("GetStatement", mock.Anything, userID, ready.ID).Return(ready, nil)
+
+	_, err := s.GetStatementFile(context.Background(), userID, pending.ID)
+	assert.ErrorIs(t, err, models.ErrStatementNotReady)
+
+	statement, err := s.GetStatementFile(context.Background(), userID, ready.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Date,Amount"), statement.Content)
+	assert.Equal(t, "text/csv", statement.ContentType())
+}
+
+func TestService_GenerateMonthlyStatements(t *testing.T) {
+	repo := new(MockRepository)
+	s := newTestService(t, repo)
+	first, second := uuid.New(), uuid.New()
+	april := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	now := april.Add(36 * time.Hour)
+
+	repo.On("FailStaleStatements", mock.Anything, now.Add(-s.config.PendingTimeout), statementFailedReason).
+		Return(int64(1), nil)
+	repo.On("GetUsersDueMonthlyStatement", mock.Anything, marchDay(1), april).Return([]uuid.UUID{first, second}, nil)
+	repo.On("CreateStatement", mock.Anything, mock.MatchedBy(func(statement *models.Statement) bool {
+		return statement.UserID == first
+	})).Run(func(args mock.Arguments) {
+		statement := args.Get(1).(*models.Statement)
+		statement.ID = uuid.New()
+		assert.Equal(t, models.StatementKindMonthly, statement.Kind)
+		assert.Equal(t, marchDay(1), statement.PeriodStart)
+		assert.Equal(t, marchDay(31), statement.PeriodEnd)
+	}).Return(nil)
+	repo.On("CreateStatement", mock.Anything, mock.MatchedBy(func(statement *models.Statement) bool {
+		return statement.UserID == second
+	})).Return(assert.AnError)
+	repo.On("GetStatementData", mock.Anything, first, marchDay(1), april).Return(statementData(), nil)
+	repo.On("CompleteStatement", mock.Anything, mock.Anything).Return(true, nil)
+
+	produced, err := s.GenerateMonthlyStatements(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, produced, "a statement another instance created is skipped")
+	repo.AssertExpectations(t)
+}
+
+func TestStatementRequest_Validate(t *testing.T) {
+	config := GetDefaultConfig()
+	now := marchDay(15).Add(12 * time.Hour)
+	validate := func(req StatementRequest) (*StatementRequest, map[string]string) {
+		v := validator.New()
+		req.Validate(v, config, now)
+		return &req, v.Errors
+	}
+
+	req, errs := validate(StatementRequest{From: "2024-03-01", To: "2024-03-15"})
+	assert.Empty(t, errs)
+	assert.Equal(t, "pdf", req.Format)
+	assert.Equal(t, marchDay(1), req.ParsedFrom)
+	assert.Equal(t, marchDay(15), req.ParsedTo)
+
+	_, errs = validate(StatementRequest{From: "1 March", To: "2024-03-15", Format: "xlsx"})
+	assert.Contains(t, errs, "from")
+	assert.Contains(t, errs, "format")
+
+	_, errs = validate(StatementRequest{From: "2024-03-10", To: "2024-03-09"})
+	assert.Contains(t, errs, "to")
+
+	_, errs = validate(StatementRequest{From: "2024-03-10", To: "2024-03-16"})
+	assert.Contains(t, errs, "to", "the period cannot end in the future")
+
+	_, errs = validate(StatementRequest{From: "2022-03-01", To: "2024-03-01"})
+	assert.Contains(t, errs, "to", "the period cannot exceed the maximum")
+}
+
+func TestLayoutTable(t *testing.T) {
+	lines := layoutTable(table{
+		columns: []column{{name: "Date"}, {name: "Reference", csvOnly: true}, {name: "Selection"}, {name: "Stake", amount: true}},
+		rows:    [][]string{{"2024-03-01 09:00", uuid.NewString(), strings.Repeat("x", 80), "200.00"}},
+	}, 40)
+
+	require.Len(t, lines, 3)
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 40)
+	}
+	assert.NotContains(t, lines[0], "Reference")
+	assert.True(t, strings.HasSuffix(lines[2], "200.00"))
+}
@@ -32,6 +32,40 @@ func (f *AdminUserFilters) SanitizeAndValidate(v *validator.Validator, s sanitiz
 	v.Check(validator.In(f.SortOrder, "", "asc", "desc"), "sort_order", "sort order must be either asc or desc")
 }
 
+// Normalize applies the default page and page size.
+func (f *AdminUserFilters) Normalize() {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PerPage < 1 || f.PerPage > 100 {
+		f.PerPage = 20
+	}
+}
+
+// RBACFilters defines the query parameters for listing roles and permissions.
+type RBACFilters struct {
+	Page    int    `form:"page"`
+	PerPage int    `form:"per_page"`
+	Search  string `form:"search"`
+}
+
+// SanitizeAndValidate cleans and validates the filter inputs.
+func (f *RBACFilters) SanitizeAndValidate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	f.Search = s.StripHTML(f.Search)
+
+	v.Check(validator.MaxRunes(f.Search, 50), "search", "search must not be more than 50 characters")
+}
+
+// Normalize applies the default page and page size.
+func (f *RBACFilters) Normalize() {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PerPage < 1 || f.PerPage > 100 {
+		f.PerPage = 20
+	}
+}
+
 // AdminAssignRoleRequest is the request body for assigning a role to a user.
 type AdminAssignRoleRequest struct {
 	RoleID uuid.UUID `json:"role_id"`
@@ -64,6 +98,27 @@ func (r *AdminBulkAssignRequest) Validate(v *validator.Validator) {
 	v.Check(len(r.PermissionIDs) > 0, "permission_ids", "At least one permission_id is required")
 }
 
+// ImpersonateRequest is the request body for starting an impersonation.
+type ImpersonateRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate sanitizes and checks the request data.
+func (r *ImpersonateRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer) {
+	r.Reason = s.StripHTML(r.Reason)
+	v.Check(validator.MinRunes(r.Reason, 10), "reason", "reason must be at least 10 characters")
+	v.Check(validator.MaxRunes(r.Reason, 500), "reason", "reason must not be more than 500 characters")
+}
+
+// ImpersonationResponse carries a read-only token for acting as a user.
+type ImpersonationResponse struct {
+	Token          string    `json:"token"`
+	UserID         uuid.UUID `json:"user_id"`
+	ImpersonatorID uuid.UUID `json:"impersonator_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	ReadOnly       bool      `json:"read_only"`
+}
+
 // AdminUserResponse is the detailed user response for admin views.
 type AdminUserResponse struct {
 	ID              uuid.UUID        `json:"id"`
@@ -100,14 +155,17 @@ type CreatePermissionRequest struct {
 
 // CreateRoleRequest represents the request to create a role
 type CreateRoleRequest struct {
-	Name        string `json:"name" binding:"required,min=2,max=50"`
-	Description string `json:"description,omitempty"`
+	Name        string     `json:"name" binding:"required,min=2,max=50"`
+	Description string     `json:"description,omitempty"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
 }
 
 // UpdateRoleRequest represents the request to update a role
 type UpdateRoleRequest struct {
 	Name        *string `json:"name,omitempty" binding:"omitempty,min=2,max=50"`
 	Description *string `json:"description,omitempty"`
+	// ParentID sets the role this one extends; uuid.Nil clears it.
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
 }
 
 // AssignPermissionsRequest represents the request to assign permissions to a role
@@ -134,6 +192,7 @@ type RoleResponse struct {
 	ID          uuid.UUID            `json:"id"`
 	Name        string               `json:"name"`
 	Description string               `json:"description"`
+	ParentID    *uuid.UUID           `json:"parent_id,omitempty"`
 	Permissions []PermissionResponse `json:"permissions"`
 	CreatedAt   time.Time            `json:"created_at"`
 	UpdatedAt   time.Time            `json:"updated_at"`
@@ -161,6 +220,7 @@ func ToRoleResponse(role *models.Role) *RoleResponse {
 		ID:          role.ID,
 		Name:        role.Name,
 		Description: role.Description,
+		ParentID:    role.ParentID,
 		Permissions: permissions,
 		CreatedAt:   role.CreatedAt,
 		UpdatedAt:   role.UpdatedAt,
@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -243,6 +244,10 @@ func (h *AdminHandler) CreateRole(c *gin.Context) {
 
 	role, err := h.service.CreateRole(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidParentRole) || errors.Is(err, models.ErrRoleInheritanceCycle) {
+			api.BadRequestResponse(c, err.Error())
+			return
+		}
 		api.InternalErrorResponse(c, "Failed to create role")
 		return
 	}
@@ -286,6 +291,10 @@ func (h *AdminHandler) UpdateRole(c *gin.Context) {
 			api.NotFoundResponse(c, "Role")
 			return
 		}
+		if errors.Is(err, models.ErrInvalidParentRole) || errors.Is(err, models.ErrRoleInheritanceCycle) {
+			api.BadRequestResponse(c, err.Error())
+			return
+		}
 		api.InternalErrorResponse(c, "Failed to update role")
 		return
 	}
@@ -464,3 +473,381 @@ func (h *AdminHandler) RemoveRoleFromUser(c *gin.Context) {
 
 	api.SuccessResponse(c, 200, "Role removed from user successfully", user)
 }
+
+// UnlockUser godoc
+// @Summary Unlock user account
+// @Description Clear the failed-login counter and lift an active lockout on a user account
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} api.Response{data=AdminUserResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/users/{id}/unlock [post]
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid user ID format")
+		return
+	}
+
+	actorID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	user, err := h.service.UnlockUser(c.Request.Context(), actorID, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "User")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "UnlockUser", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to unlock user")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "User unlocked successfully", user)
+}
+
+// RevokeSessions godoc
+// @Summary Revoke user sessions
+// @Description Invalidate every token issued to the user so they must log in again
+// @Tags admin-users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/users/{id}/sessions/revoke [post]
+func (h *AdminHandler) RevokeSessions(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid user ID format")
+		return
+	}
+
+	actorID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	if err := h.service.RevokeSessions(c.Request.Context(), actorID, userID); err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "User")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "RevokeSessions", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to revoke sessions")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "User sessions revoked successfully", nil)
+}
+
+// ListPermissions godoc
+// @Summary List permissions
+// @Description Retrieve a paginated list of permissions ordered by name
+// @Tags admin-permissions
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param search query string false "Search term for the permission name"
+// @Success 200 {object} api.Response{data=[]PermissionResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/permissions [get]
+func (h *AdminHandler) ListPermissions(c *gin.Context) {
+	filters, ok := h.bindRBACFilters(c)
+	if !ok {
+		return
+	}
+
+	permissions, total, err := h.service.ListPermissions(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "ListPermissions"})
+		api.InternalErrorResponse(c, "Failed to retrieve permissions")
+		return
+	}
+
+	api.PaginatedResponse(c, "Permissions retrieved successfully", permissions, api.PaginationMeta{
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+		Total:   total,
+	})
+}
+
+// DeletePermission godoc
+// @Summary Delete a permission
+// @Description Delete a permission and revoke it from every role granting it
+// @Tags admin-permissions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Permission ID"
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/permissions/{id} [delete]
+func (h *AdminHandler) DeletePermission(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid permission ID format")
+		return
+	}
+
+	if err := h.service.DeletePermission(c.Request.Context(), id); err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Permission")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "DeletePermission", "permission_id": id})
+		api.InternalErrorResponse(c, "Failed to delete permission")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "Permission deleted successfully", nil)
+}
+
+// ListRoles godoc
+// @Summary List roles
+// @Description Retrieve a paginated list of roles with their permissions, ordered by name
+// @Tags admin-roles
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param search query string false "Search term for the role name"
+// @Success 200 {object} api.Response{data=[]RoleResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/roles [get]
+func (h *AdminHandler) ListRoles(c *gin.Context) {
+	filters, ok := h.bindRBACFilters(c)
+	if !ok {
+		return
+	}
+
+	roles, total, err := h.service.ListRoles(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "ListRoles"})
+		api.InternalErrorResponse(c, "Failed to retrieve roles")
+		return
+	}
+
+	api.PaginatedResponse(c, "Roles retrieved successfully", roles, api.PaginationMeta{
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+		Total:   total,
+	})
+}
+
+// GetRole godoc
+// @Summary Get a role
+// @Description Retrieve a role with its permissions
+// @Tags admin-roles
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Success 200 {object} api.Response{data=RoleResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/roles/{id} [get]
+func (h *AdminHandler) GetRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid role ID format")
+		return
+	}
+
+	role, err := h.service.GetRole(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Role")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "GetRole", "role_id": id})
+		api.InternalErrorResponse(c, "Failed to get role")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "Role retrieved successfully", role)
+}
+
+// GetRoleMembers godoc
+// @Summary List role members
+// @Description Retrieve a paginated list of the users assigned a role
+// @Tags admin-roles
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param status query string false "Filter by status (active or inactive)" Enums(active, inactive)
+// @Param search query string false "Search term for name or email"
+// @Success 200 {object} api.Response{data=[]AdminUserResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/roles/{id}/members [get]
+func (h *AdminHandler) GetRoleMembers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid role ID format")
+		return
+	}
+
+	var filters AdminUserFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	filters.SanitizeAndValidate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	users, total, err := h.service.GetRoleMembers(c.Request.Context(), id, &filters)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Role")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "GetRoleMembers", "role_id": id})
+		api.InternalErrorResponse(c, "Failed to retrieve role members")
+		return
+	}
+
+	api.PaginatedResponse(c, "Role members retrieved successfully", users, api.PaginationMeta{
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+		Total:   total,
+	})
+}
+
+// DeleteRole godoc
+// @Summary Delete a role
+// @Description Delete a role. A role that is still assigned to users is only deleted with force=true.
+// @Tags admin-roles
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role ID"
+// @Param force query bool false "Delete the role even if users hold it" default(false)
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/roles/{id} [delete]
+func (h *AdminHandler) DeleteRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid role ID format")
+		return
+	}
+
+	force := false
+	if value := c.Query("force"); value != "" {
+		if force, err = strconv.ParseBool(value); err != nil {
+			api.BadRequestResponse(c, "force must be a boolean")
+			return
+		}
+	}
+
+	if err := h.service.DeleteRole(c.Request.Context(), id, force); err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Role")
+			return
+		}
+		if errors.Is(err, models.ErrRoleInUse) {
+			api.ConflictResponse(c, "Role is assigned to users; use force=true to delete it anyway")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "DeleteRole", "role_id": id})
+		api.InternalErrorResponse(c, "Failed to delete role")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "Role deleted successfully", nil)
+}
+
+// GetUserPermissions godoc
+// @Summary List a user's effective permissions
+// @Description Retrieve every permission a user holds through their roles, including inherited ones
+// @Tags admin-users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} api.Response{data=[]PermissionResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/users/{id}/permissions [get]
+func (h *AdminHandler) GetUserPermissions(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid user ID format")
+		return
+	}
+
+	permissions, err := h.service.GetUserPermissions(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "User")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "GetUserPermissions", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to retrieve user permissions")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "User permissions retrieved successfully", permissions)
+}
+
+// bindRBACFilters binds and validates the role and permission list filters,
+// writing the error response itself when they are invalid.
+func (h *AdminHandler) bindRBACFilters(c *gin.Context) (*RBACFilters, bool) {
+	var filters RBACFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return nil, false
+	}
+
+	v := validator.New()
+	filters.SanitizeAndValidate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return nil, false
+	}
+	return &filters, true
+}
@@ -95,6 +95,58 @@ func (m *MockAdminService) RemoveRoleFromUser(ctx context.Context, userID, roleI
 	return args.Get(0).(*AdminUserResponse), args.Error(1)
 }
 
+func (m *MockAdminService) UnlockUser(ctx context.Context, actorID, userID uuid.UUID) (*AdminUserResponse, error) {
+	args := m.Called(ctx, actorID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*AdminUserResponse), args.Error(1)
+}
+
+func (m *MockAdminService) RevokeSessions(ctx context.Context, actorID, userID uuid.UUID) error {
+	args := m.Called(ctx, actorID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) ListPermissions(ctx context.Context, filters *RBACFilters) ([]PermissionResponse, int64, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).([]PermissionResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAdminService) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockAdminService) ListRoles(ctx context.Context, filters *RBACFilters) ([]RoleResponse, int64, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).([]RoleResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAdminService) GetRole(ctx context.Context, id uuid.UUID) (*RoleResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*RoleResponse), args.Error(1)
+}
+
+func (m *MockAdminService) GetRoleMembers(ctx context.Context, roleID uuid.UUID, filters *AdminUserFilters) ([]AdminUserResponse, int64, error) {
+	args := m.Called(ctx, roleID, filters)
+	return args.Get(0).([]AdminUserResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAdminService) DeleteRole(ctx context.Context, id uuid.UUID, force bool) error {
+	return m.Called(ctx, id, force).Error(0)
+}
+
+func (m *MockAdminService) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]PermissionResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]PermissionResponse), args.Error(1)
+}
+
 type MockSanitizer struct {
 	mock.Mock
 }
@@ -321,6 +373,24 @@ func (suite *AdminHandlerTestSuite) TestCreateRole_Success() {
 	suite.service.AssertExpectations(suite.T())
 }
 
+func (suite *AdminHandlerTestSuite) TestCreateRole_InvalidParent() {
+	parentID := uuid.New()
+	reqBody := CreateRoleRequest{Name: "test_role", ParentID: &parentID}
+	body, _ := json.Marshal(reqBody)
+
+	suite.service.On("CreateRole", mock.Anything, &reqBody).Return(nil, models.ErrInvalidParentRole)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/roles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	suite.handler.CreateRole(c)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
 func (suite *AdminHandlerTestSuite) TestUpdateRole_Success() {
 	roleID := uuid.New()
 	name := "updated_role"
@@ -999,3 +1069,239 @@ func (suite *AdminHandlerTestSuite) TestRemovePermissionsFromRole_NotFoundError(
 
 	suite.Equal(http.StatusNotFound, w.Code)
 }
+
+func (suite *AdminHandlerTestSuite) TestUnlockUser_Success() {
+	userID := uuid.New()
+	actorID := uuid.New()
+
+	suite.service.On("UnlockUser", mock.Anything, actorID, userID).Return(&AdminUserResponse{ID: userID}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/users/"+userID.String()+"/unlock", http.NoBody)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: userID.String()}}
+	c.Set("userID", actorID)
+
+	suite.handler.UnlockUser(c)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.service.AssertExpectations(suite.T())
+}
+
+func (suite *AdminHandlerTestSuite) TestUnlockUser_InvalidID() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/users/invalid/unlock", http.NoBody)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "invalid"}}
+
+	suite.handler.UnlockUser(c)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestUnlockUser_MissingActor() {
+	userID := uuid.New()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/users/"+userID.String()+"/unlock", http.NoBody)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: userID.String()}}
+
+	suite.handler.UnlockUser(c)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.service.AssertNotCalled(suite.T(), "UnlockUser")
+}
+
+func (suite *AdminHandlerTestSuite) TestUnlockUser_NotFound() {
+	userID := uuid.New()
+	actorID := uuid.New()
+
+	suite.service.On("UnlockUser", mock.Anything, actorID, userID).Return(nil, models.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/users/"+userID.String()+"/unlock", http.NoBody)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: userID.String()}}
+	c.Set("userID", actorID)
+
+	suite.handler.UnlockUser(c)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestRevokeSessions_Success() {
+	userID := uuid.New()
+	actorID := uuid.New()
+
+	suite.service.On("RevokeSessions", mock.Anything, actorID, userID).Return(nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/users/"+userID.String()+"/sessions/revoke", http.NoBody)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: userID.String()}}
+	c.Set("userID", actorID)
+
+	suite.handler.RevokeSessions(c)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.service.AssertExpectations(suite.T())
+}
+
+func (suite *AdminHandlerTestSuite) TestRevokeSessions_NotFound() {
+	userID := uuid.New()
+	actorID := uuid.New()
+
+	suite.service.On("RevokeSessions", mock.Anything, actorID, userID).Return(models.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/users/"+userID.String()+"/sessions/revoke", http.NoBody)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: userID.String()}}
+	c.Set("userID", actorID)
+
+	suite.handler.RevokeSessions(c)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestListRoles_Success() {
+	roles := []RoleResponse{{ID: uuid.New(), Name: "support"}}
+	suite.sanitizer.On("StripHTML", "sup").Return("sup")
+	suite.service.On("ListRoles", mock.Anything, mock.MatchedBy(func(f *RBACFilters) bool {
+		return f.Search == "sup"
+	})).Return(roles, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/roles?search=sup", http.NoBody)
+
+	suite.handler.ListRoles(c)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"total":1`)
+	suite.service.AssertExpectations(suite.T())
+}
+
+func (suite *AdminHandlerTestSuite) TestListPermissions_ServiceError() {
+	suite.sanitizer.On("StripHTML", "").Return("")
+	suite.service.On("ListPermissions", mock.Anything, mock.Anything).Return([]PermissionResponse{}, int64(0), errors.New("db down"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/permissions", http.NoBody)
+
+	suite.handler.ListPermissions(c)
+
+	suite.Equal(http.StatusInternalServerError, w.Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestGetRole_NotFound() {
+	id := uuid.New()
+	suite.service.On("GetRole", mock.Anything, id).Return(nil, models.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/roles/"+id.String(), http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	suite.handler.GetRole(c)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestGetRoleMembers_Success() {
+	id := uuid.New()
+	users := []AdminUserResponse{{ID: uuid.New()}}
+	suite.sanitizer.On("StripHTML", "").Return("")
+	suite.service.On("GetRoleMembers", mock.Anything, id, mock.Anything).Return(users, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/roles/"+id.String()+"/members", http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	suite.handler.GetRoleMembers(c)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.service.AssertExpectations(suite.T())
+}
+
+func (suite *AdminHandlerTestSuite) TestDeleteRole_InUse() {
+	id := uuid.New()
+	suite.service.On("DeleteRole", mock.Anything, id, false).Return(models.ErrRoleInUse)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("DELETE", "/admin/roles/"+id.String(), http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	suite.handler.DeleteRole(c)
+
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestDeleteRole_Forced() {
+	id := uuid.New()
+	suite.service.On("DeleteRole", mock.Anything, id, true).Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("DELETE", "/admin/roles/"+id.String()+"?force=true", http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	suite.handler.DeleteRole(c)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.service.AssertExpectations(suite.T())
+}
+
+func (suite *AdminHandlerTestSuite) TestDeleteRole_InvalidForce() {
+	id := uuid.New()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("DELETE", "/admin/roles/"+id.String()+"?force=maybe", http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	suite.handler.DeleteRole(c)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.service.AssertNotCalled(suite.T(), "DeleteRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AdminHandlerTestSuite) TestDeletePermission_NotFound() {
+	id := uuid.New()
+	suite.service.On("DeletePermission", mock.Anything, id).Return(models.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("DELETE", "/admin/permissions/"+id.String(), http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+	suite.handler.DeletePermission(c)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestGetUserPermissions_Success() {
+	userID := uuid.New()
+	permissions := []PermissionResponse{{ID: uuid.New(), Name: "admin"}}
+	suite.service.On("GetUserPermissions", mock.Anything, userID).Return(permissions, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/users/"+userID.String()+"/permissions", http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: userID.String()}}
+
+	suite.handler.GetUserPermissions(c)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"name":"admin"`)
+}
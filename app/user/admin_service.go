@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
+	"github.com/joefazee/neo/app/audit"
 	"github.com/joefazee/neo/models"
 	"gorm.io/gorm"
 
@@ -18,37 +20,48 @@ type AdminService interface {
 	BulkAssignPermissions(ctx context.Context, userIDs, permissionIDs []uuid.UUID) error
 
 	CreatePermission(ctx context.Context, req *CreatePermissionRequest) (*PermissionResponse, error)
+	ListPermissions(ctx context.Context, filters *RBACFilters) ([]PermissionResponse, int64, error)
+	DeletePermission(ctx context.Context, id uuid.UUID) error
 
 	CreateRole(ctx context.Context, req *CreateRoleRequest) (*RoleResponse, error)
+	ListRoles(ctx context.Context, filters *RBACFilters) ([]RoleResponse, int64, error)
+	GetRole(ctx context.Context, id uuid.UUID) (*RoleResponse, error)
+	GetRoleMembers(ctx context.Context, roleID uuid.UUID, filters *AdminUserFilters) ([]AdminUserResponse, int64, error)
+	DeleteRole(ctx context.Context, id uuid.UUID, force bool) error
 	UpdateRole(ctx context.Context, id uuid.UUID, req *UpdateRoleRequest) (*RoleResponse, error)
 	AssignPermissionsToRole(ctx context.Context, roleID uuid.UUID, req *AssignPermissionsRequest) (*RoleResponse, error)
 	RemovePermissionsFromRole(ctx context.Context, roleID uuid.UUID, req *RemovePermissionsRequest) (*RoleResponse, error)
 
 	GetUserByID(ctx context.Context, id uuid.UUID) (*AdminUserResponse, error)
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]PermissionResponse, error)
 	RemoveRoleFromUser(ctx context.Context, userID, roleID uuid.UUID) (*AdminUserResponse, error)
+	UnlockUser(ctx context.Context, actorID, userID uuid.UUID) (*AdminUserResponse, error)
+	RevokeSessions(ctx context.Context, actorID, userID uuid.UUID) error
 }
 
 type adminService struct {
-	repo Repository
+	repo        Repository
+	versions    TokenVersions
+	permissions PermissionCache
 }
 
-func NewAdminService(repo Repository) AdminService {
-	return &adminService{repo: repo}
+func NewAdminService(repo Repository, versions TokenVersions, permissions PermissionCache) AdminService {
+	return &adminService{repo: repo, versions: versions, permissions: permissions}
 }
 
 func (s *adminService) GetUsers(ctx context.Context, filters *AdminUserFilters) ([]AdminUserResponse, int64, error) {
-	if filters.Page < 1 {
-		filters.Page = 1
-	}
-	if filters.PerPage < 1 || filters.PerPage > 100 {
-		filters.PerPage = 20
-	}
+	filters.Normalize()
 
 	users, total, err := s.repo.GetUsers(ctx, filters)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	return toAdminUserResponses(users), total, nil
+}
+
+// toAdminUserResponses builds the list view of users with their roles.
+func toAdminUserResponses(users []models.User) []AdminUserResponse {
 	userResponses := make([]AdminUserResponse, 0, len(users))
 	for i := range users {
 		user := users[i]
@@ -67,20 +80,90 @@ func (s *adminService) GetUsers(ctx context.Context, filters *AdminUserFilters)
 			Roles:     roles,
 		})
 	}
-
-	return userResponses, total, nil
+	return userResponses
 }
 
 func (s *adminService) UpdateUserStatus(ctx context.Context, userID uuid.UUID, isActive bool) error {
-	return s.repo.UpdateUserStatus(ctx, userID, isActive)
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrRecordNotFound
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	oldValues := models.AuditValues{"is_active": user.IsActive}
+
+	if err := s.repo.UpdateUserStatus(ctx, userID, isActive); err != nil {
+		return err
+	}
+	if !isActive {
+		if err := s.versions.Revoke(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	return s.recordChange(ctx, models.AuditActionUserStatusChanged, models.AuditResourceUser, userID,
+		oldValues, models.AuditValues{"is_active": isActive})
 }
 
 func (s *adminService) AssignRole(ctx context.Context, userID, roleID uuid.UUID) error {
-	return s.repo.AssignRole(ctx, userID, roleID)
+	user, err := s.repo.GetUserByIDWithRoles(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrRecordNotFound
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := s.repo.AssignRole(ctx, userID, roleID); err != nil {
+		return err
+	}
+	if err := s.permissions.InvalidateUsers(ctx, userID); err != nil {
+		return err
+	}
+
+	updatedUser, err := s.repo.GetUserByIDWithRoles(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get updated user: %w", err)
+	}
+	return s.recordChange(ctx, models.AuditActionRoleAssigned, models.AuditResourceUser, userID,
+		models.AuditValues{"roles": roleNames(user.Roles)},
+		models.AuditValues{"roles": roleNames(updatedUser.Roles)})
 }
 
+// BulkAssignPermissions grants permissions to several users at once. Each
+// user gets an audit entry with their effective permissions before and after.
 func (s *adminService) BulkAssignPermissions(ctx context.Context, userIDs, permissionIDs []uuid.UUID) error {
-	return s.repo.BulkAssignPermissions(ctx, userIDs, permissionIDs)
+	oldValues := make(map[uuid.UUID]models.AuditValues, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := s.repo.GetByIDWithPermissions(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrRecordNotFound
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		oldValues[userID] = models.AuditValues{"permissions": effectivePermissionNames(user)}
+	}
+
+	if err := s.repo.BulkAssignPermissions(ctx, userIDs, permissionIDs); err != nil {
+		return err
+	}
+	if err := s.permissions.InvalidateUsers(ctx, userIDs...); err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		user, err := s.repo.GetByIDWithPermissions(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get updated user: %w", err)
+		}
+		if err := s.recordChange(ctx, models.AuditActionPermissionsGranted, models.AuditResourceUser, userID,
+			oldValues[userID], models.AuditValues{"permissions": effectivePermissionNames(user)}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *adminService) CreatePermission(ctx context.Context, req *CreatePermissionRequest) (*PermissionResponse, error) {
@@ -102,22 +185,162 @@ func (s *adminService) CreatePermission(ctx context.Context, req *CreatePermissi
 		return nil, fmt.Errorf("failed to create permission: %w", err)
 	}
 
+	if err := s.recordChange(ctx, models.AuditActionPermissionCreated, models.AuditResourcePermission, permission.ID,
+		nil, permissionValues(permission)); err != nil {
+		return nil, err
+	}
+
 	return ToPermissionResponse(permission), nil
 }
 
+// ListPermissions returns a page of permissions ordered by name.
+func (s *adminService) ListPermissions(ctx context.Context, filters *RBACFilters) ([]PermissionResponse, int64, error) {
+	filters.Normalize()
+
+	permissions, total, err := s.repo.ListPermissions(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list permissions: %w", err)
+	}
+
+	responses := make([]PermissionResponse, len(permissions))
+	for i := range permissions {
+		responses[i] = *ToPermissionResponse(&permissions[i])
+	}
+	return responses, total, nil
+}
+
+// DeletePermission removes a permission from the system and from every role
+// granting it. Users who held it through those roles lose it immediately.
+func (s *adminService) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	permission, err := s.repo.GetPermissionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrRecordNotFound
+		}
+		return fmt.Errorf("failed to get permission: %w", err)
+	}
+
+	// Members must be resolved before the grants disappear
+	roleIDs, err := s.repo.GetRoleIDsByPermission(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to resolve roles: %w", err)
+	}
+	var userIDs []uuid.UUID
+	if len(roleIDs) > 0 {
+		if userIDs, err = s.repo.GetUserIDsByRoles(ctx, roleIDs...); err != nil {
+			return fmt.Errorf("failed to resolve role members: %w", err)
+		}
+	}
+
+	if err := s.repo.DeletePermission(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete permission: %w", err)
+	}
+	if err := s.permissions.InvalidateUsers(ctx, userIDs...); err != nil {
+		return err
+	}
+
+	return s.recordChange(ctx, models.AuditActionPermissionDeleted, models.AuditResourcePermission, id,
+		permissionValues(permission), nil)
+}
+
 func (s *adminService) CreateRole(ctx context.Context, req *CreateRoleRequest) (*RoleResponse, error) {
 	role := &models.Role{
 		Name:        req.Name,
 		Description: req.Description,
 	}
 
+	if req.ParentID != nil {
+		if err := s.setParentRole(ctx, role, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.repo.CreateRole(ctx, role); err != nil {
 		return nil, fmt.Errorf("failed to create role: %w", err)
 	}
 
+	if err := s.recordChange(ctx, models.AuditActionRoleCreated, models.AuditResourceRole, role.ID,
+		nil, roleValues(role)); err != nil {
+		return nil, err
+	}
+
 	return ToRoleResponse(role), nil
 }
 
+// ListRoles returns a page of roles with their direct permissions.
+func (s *adminService) ListRoles(ctx context.Context, filters *RBACFilters) ([]RoleResponse, int64, error) {
+	filters.Normalize()
+
+	roles, total, err := s.repo.ListRoles(ctx, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	responses := make([]RoleResponse, len(roles))
+	for i := range roles {
+		responses[i] = *ToRoleResponse(&roles[i])
+	}
+	return responses, total, nil
+}
+
+func (s *adminService) GetRole(ctx context.Context, id uuid.UUID) (*RoleResponse, error) {
+	role, err := s.repo.GetRoleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return ToRoleResponse(role), nil
+}
+
+// GetRoleMembers returns a page of the users holding the role directly.
+func (s *adminService) GetRoleMembers(ctx context.Context, roleID uuid.UUID, filters *AdminUserFilters) ([]AdminUserResponse, int64, error) {
+	if _, err := s.repo.GetRoleByID(ctx, roleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, models.ErrRecordNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	filters.Normalize()
+	users, total, err := s.repo.GetRoleMembers(ctx, roleID, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get role members: %w", err)
+	}
+
+	return toAdminUserResponses(users), total, nil
+}
+
+// DeleteRole removes a role. A role that is still assigned is only removed
+// when force is set, in which case its members lose it along with the role.
+// Roles extending it are detached and stop inheriting its permissions.
+func (s *adminService) DeleteRole(ctx context.Context, id uuid.UUID, force bool) error {
+	role, err := s.repo.GetRoleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrRecordNotFound
+		}
+		return fmt.Errorf("failed to get role: %w", err)
+	}
+
+	userIDs, err := s.repo.DeleteRole(ctx, id, force)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) || errors.Is(err, models.ErrRoleInUse) {
+			return err
+		}
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	if err := s.permissions.InvalidateUsers(ctx, userIDs...); err != nil {
+		return err
+	}
+
+	oldValues := roleValues(role)
+	oldValues["affected_users"] = len(userIDs)
+	return s.recordChange(ctx, models.AuditActionRoleDeleted, models.AuditResourceRole, id, oldValues, nil)
+}
+
 func (s *adminService) UpdateRole(ctx context.Context, id uuid.UUID, req *UpdateRoleRequest) (*RoleResponse, error) {
 	role, err := s.repo.GetRoleByID(ctx, id)
 	if err != nil {
@@ -126,6 +349,7 @@ func (s *adminService) UpdateRole(ctx context.Context, id uuid.UUID, req *Update
 		}
 		return nil, fmt.Errorf("failed to get role: %w", err)
 	}
+	oldValues := roleValues(role)
 
 	// Update fields if provided
 	if req.Name != nil {
@@ -134,17 +358,77 @@ func (s *adminService) UpdateRole(ctx context.Context, id uuid.UUID, req *Update
 	if req.Description != nil {
 		role.Description = *req.Description
 	}
+	if req.ParentID != nil {
+		if err := s.setParentRole(ctx, role, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.repo.UpdateRole(ctx, role); err != nil {
 		return nil, fmt.Errorf("failed to update role: %w", err)
 	}
 
+	// A new parent changes what the role's members inherit
+	if req.ParentID != nil {
+		if err := s.permissions.InvalidateRoles(ctx, role.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.recordChange(ctx, models.AuditActionRoleUpdated, models.AuditResourceRole, role.ID,
+		oldValues, roleValues(role)); err != nil {
+		return nil, err
+	}
+
 	return ToRoleResponse(role), nil
 }
 
+// setParentRole points role at parentID, rejecting unknown parents and
+// inheritance cycles. uuid.Nil removes the parent.
+func (s *adminService) setParentRole(ctx context.Context, role *models.Role, parentID uuid.UUID) error {
+	if parentID == uuid.Nil {
+		role.ParentID = nil
+		return nil
+	}
+	if parentID == role.ID {
+		return models.ErrRoleInheritanceCycle
+	}
+
+	if _, err := s.repo.GetRoleByID(ctx, parentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrInvalidParentRole
+		}
+		return fmt.Errorf("failed to get parent role: %w", err)
+	}
+
+	// A new role has no descendants yet, so only existing roles can close a loop.
+	if role.ID != uuid.Nil {
+		ancestors, err := s.repo.GetRoleAncestorIDs(ctx, parentID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve role inheritance: %w", err)
+		}
+		for _, id := range ancestors {
+			if id == role.ID {
+				return models.ErrRoleInheritanceCycle
+			}
+		}
+	}
+
+	role.ParentID = &parentID
+	return nil
+}
+
 // AssignPermissionsToRole assigns permissions to a role by their codes.
 // //nolint: dupl
 func (s *adminService) AssignPermissionsToRole(ctx context.Context, roleID uuid.UUID, req *AssignPermissionsRequest) (*RoleResponse, error) {
+	role, err := s.repo.GetRoleByID(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
 	// Get permissions by their codes
 	permissions, err := s.repo.GetPermissionsByNames(ctx, req.PermissionCodes)
 	if err != nil {
@@ -166,18 +450,36 @@ func (s *adminService) AssignPermissionsToRole(ctx context.Context, roleID uuid.
 		return nil, fmt.Errorf("failed to assign permissions to role: %w", err)
 	}
 
+	if err := s.permissions.InvalidateRoles(ctx, roleID); err != nil {
+		return nil, err
+	}
+
 	// Get updated role with permissions
 	updatedRole, err := s.repo.GetRoleByID(ctx, roleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get updated role: %w", err)
 	}
 
+	if err := s.recordChange(ctx, models.AuditActionRoleUpdated, models.AuditResourceRole, roleID,
+		models.AuditValues{"permissions": permissionNames(role.Permissions)},
+		models.AuditValues{"permissions": permissionNames(updatedRole.Permissions)}); err != nil {
+		return nil, err
+	}
+
 	return ToRoleResponse(updatedRole), nil
 }
 
 // RemovePermissionsFromRole removes permissions from a role by their codes.
 // //nolint: dupl
 func (s *adminService) RemovePermissionsFromRole(ctx context.Context, roleID uuid.UUID, req *RemovePermissionsRequest) (*RoleResponse, error) {
+	role, err := s.repo.GetRoleByID(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
 	// Get permissions by their codes
 	permissions, err := s.repo.GetPermissionsByNames(ctx, req.PermissionCodes)
 	if err != nil {
@@ -199,12 +501,22 @@ func (s *adminService) RemovePermissionsFromRole(ctx context.Context, roleID uui
 		return nil, fmt.Errorf("failed to remove permissions from role: %w", err)
 	}
 
+	if err := s.permissions.InvalidateRoles(ctx, roleID); err != nil {
+		return nil, err
+	}
+
 	// Get updated role with permissions
 	updatedRole, err := s.repo.GetRoleByID(ctx, roleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get updated role: %w", err)
 	}
 
+	if err := s.recordChange(ctx, models.AuditActionRoleUpdated, models.AuditResourceRole, roleID,
+		models.AuditValues{"permissions": permissionNames(role.Permissions)},
+		models.AuditValues{"permissions": permissionNames(updatedRole.Permissions)}); err != nil {
+		return nil, err
+	}
+
 	return ToRoleResponse(updatedRole), nil
 }
 
@@ -220,6 +532,45 @@ func (s *adminService) GetUserByID(ctx context.Context, id uuid.UUID) (*AdminUse
 	return ToUserResponse(user), nil
 }
 
+// GetUserPermissions lists the user's effective permissions, including those
+// inherited through parent roles, ordered by name.
+func (s *adminService) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]PermissionResponse, error) {
+	user, err := s.repo.GetByIDWithPermissions(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	permissions := make([]PermissionResponse, 0)
+	for _, permission := range effectivePermissions(user) {
+		permissions = append(permissions, *ToPermissionResponse(permission))
+	}
+
+	sort.Slice(permissions, func(i, j int) bool {
+		return permissions[i].Name < permissions[j].Name
+	})
+	return permissions, nil
+}
+
+// effectivePermissions flattens the permissions granted by the user's roles.
+func effectivePermissions(user *models.User) []*models.Permission {
+	seen := make(map[uuid.UUID]struct{})
+	var permissions []*models.Permission
+	for i := range user.Roles {
+		for j := range user.Roles[i].Permissions {
+			permission := &user.Roles[i].Permissions[j]
+			if _, ok := seen[permission.ID]; ok {
+				continue
+			}
+			seen[permission.ID] = struct{}{}
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
 func (s *adminService) RemoveRoleFromUser(ctx context.Context, userID, roleID uuid.UUID) (*AdminUserResponse, error) {
 	// Check if user exists
 	user, err := s.repo.GetUserByIDWithRoles(ctx, userID)
@@ -248,11 +599,133 @@ func (s *adminService) RemoveRoleFromUser(ctx context.Context, userID, roleID uu
 		return nil, fmt.Errorf("failed to remove role from user: %w", err)
 	}
 
+	// Tokens issued while the user held the role must not outlive it
+	if err := s.versions.Revoke(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.permissions.InvalidateUsers(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Get updated user
 	updatedUser, err := s.repo.GetUserByIDWithRoles(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get updated user: %w", err)
 	}
 
+	if err := s.recordChange(ctx, models.AuditActionRoleRevoked, models.AuditResourceUser, userID,
+		models.AuditValues{"roles": roleNames(user.Roles)},
+		models.AuditValues{"roles": roleNames(updatedUser.Roles)}); err != nil {
+		return nil, err
+	}
+
 	return ToUserResponse(updatedUser), nil
 }
+
+// UnlockUser clears the failed-login counter and lock window of a user.
+func (s *adminService) UnlockUser(ctx context.Context, actorID, userID uuid.UUID) (*AdminUserResponse, error) {
+	user, err := s.repo.GetUserByIDWithRoles(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	oldValues := models.AuditValues{
+		"failed_login_attempts": user.FailedLoginAttempts,
+		"locked_until":          user.LockedUntil,
+	}
+
+	user.ResetFailedLogins()
+	if err := s.repo.UpdateLoginState(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to unlock user: %w", err)
+	}
+
+	actor := audit.ActorFromContext(ctx)
+	auditLog := models.CreateUserAuditLog(actorID,
+		models.AuditActionAccountUnlocked,
+		models.AuditResourceUser,
+		&user.ID,
+		oldValues,
+		models.AuditValues{"failed_login_attempts": 0, "locked_until": nil},
+		actor.IP, actor.UserAgent)
+	if err := s.repo.CreateAuditLog(ctx, auditLog); err != nil {
+		return nil, fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return ToUserResponse(user), nil
+}
+
+// RevokeSessions logs the user out everywhere by bumping their token version.
+func (s *adminService) RevokeSessions(ctx context.Context, actorID, userID uuid.UUID) error {
+	if err := s.versions.Revoke(ctx, userID); err != nil {
+		return err
+	}
+
+	actor := audit.ActorFromContext(ctx)
+	auditLog := models.CreateUserAuditLog(actorID,
+		models.AuditActionSessionsRevoked,
+		models.AuditResourceUser,
+		&userID,
+		nil, nil,
+		actor.IP, actor.UserAgent)
+	if err := s.repo.CreateAuditLog(ctx, auditLog); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// recordChange writes an audit entry for an admin mutation, attributed to the
+// actor in ctx.
+func (s *adminService) recordChange(ctx context.Context, action, resourceType string, resourceID uuid.UUID,
+	oldValues, newValues models.AuditValues) error {
+	log := audit.NewLog(ctx, action, resourceType, &resourceID, oldValues, newValues)
+	if err := s.repo.CreateAuditLog(ctx, log); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// roleValues captures the audited state of a role.
+func roleValues(role *models.Role) models.AuditValues {
+	return models.AuditValues{
+		"name":        role.Name,
+		"description": role.Description,
+		"parent_id":   role.ParentID,
+		"permissions": permissionNames(role.Permissions),
+	}
+}
+
+// permissionValues captures the audited state of a permission.
+func permissionValues(permission *models.Permission) models.AuditValues {
+	return models.AuditValues{"name": permission.Name, "description": permission.Description}
+}
+
+func roleNames(roles []models.Role) []string {
+	names := make([]string, len(roles))
+	for i := range roles {
+		names[i] = roles[i].Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func permissionNames(permissions []models.Permission) []string {
+	names := make([]string, len(permissions))
+	for i := range permissions {
+		names[i] = permissions[i].Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func effectivePermissionNames(user *models.User) []string {
+	permissions := effectivePermissions(user)
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = permission.Name
+	}
+	sort.Strings(names)
+	return names
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 
+	"github.com/joefazee/neo/internal/cache"
 	"github.com/joefazee/neo/models"
 )
 
@@ -28,6 +29,9 @@ func (m *MockRepo) Create(ctx context.Context, user *models.User) error {
 
 func (m *MockRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	args := m.Called(ctx, email)
+	if fn, ok := args.Get(0).(func() *models.User); ok {
+		return fn(), args.Error(1)
+	}
 	if u := args.Get(0); u != nil {
 		return u.(*models.User), args.Error(1)
 	}
@@ -142,11 +146,157 @@ func (m *MockRepo) RemoveRoleFromUser(ctx context.Context, userID, roleID uuid.U
 	return m.Called(ctx, userID, roleID).Error(0)
 }
 
+func (m *MockRepo) UpdateLoginState(ctx context.Context, user *models.User) error {
+	return m.Called(ctx, user).Error(0)
+}
+
+func (m *MockRepo) IncrementFailedLogins(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	if fn, ok := args.Get(0).(func() int); ok {
+		return fn(), args.Error(1)
+	}
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepo) LockUntil(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	return m.Called(ctx, userID, until).Error(0)
+}
+
+func (m *MockRepo) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return m.Called(ctx, log).Error(0)
+}
+
+func (m *MockRepo) GetRoleMembers(ctx context.Context, roleID uuid.UUID, filters *AdminUserFilters) ([]models.User, int64, error) {
+	args := m.Called(ctx, roleID, filters)
+	if users := args.Get(0); users != nil {
+		return users.([]models.User), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepo) GetPermissionByID(ctx context.Context, id uuid.UUID) (*models.Permission, error) {
+	args := m.Called(ctx, id)
+	if p := args.Get(0); p != nil {
+		return p.(*models.Permission), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepo) ListPermissions(ctx context.Context, filters *RBACFilters) ([]models.Permission, int64, error) {
+	args := m.Called(ctx, filters)
+	if perms := args.Get(0); perms != nil {
+		return perms.([]models.Permission), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepo) GetRoleIDsByPermission(ctx context.Context, permissionID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, permissionID)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepo) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockRepo) ListRoles(ctx context.Context, filters *RBACFilters) ([]models.Role, int64, error) {
+	args := m.Called(ctx, filters)
+	if roles := args.Get(0); roles != nil {
+		return roles.([]models.Role), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepo) DeleteRole(ctx context.Context, id uuid.UUID, force bool) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id, force)
+	if ids := args.Get(0); ids != nil {
+		return ids.([]uuid.UUID), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // Helper functions
 func ptrString(s string) *string { return &s }
+func (m *MockRepo) GetTokenVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) IncrementTokenVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) GetRoleAncestorIDs(ctx context.Context, roleIDs ...uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, roleIDs)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepo) GetUserIDsByRoles(ctx context.Context, roleIDs ...uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, roleIDs)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepo) CreateSession(ctx context.Context, session *models.UserSession) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockRepo) GetActiveSessions(ctx context.Context, userID uuid.UUID) ([]models.UserSession, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.UserSession), args.Error(1)
+}
+
+func (m *MockRepo) GetSessionByID(ctx context.Context, userID, sessionID uuid.UUID) (*models.UserSession, error) {
+	args := m.Called(ctx, userID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSession), args.Error(1)
+}
+
+func (m *MockRepo) HasSessionForDevice(ctx context.Context, userID uuid.UUID, deviceName string) (bool, error) {
+	args := m.Called(ctx, userID, deviceName)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) RevokeSession(ctx context.Context, session *models.UserSession, entry *models.TokenBlacklist) error {
+	args := m.Called(ctx, session, entry)
+	return args.Error(0)
+}
+
+func (m *MockRepo) IsTokenBlacklisted(ctx context.Context, jti string) (bool, error) {
+	args := m.Called(ctx, jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) TouchSession(ctx context.Context, userID uuid.UUID, jti string, at time.Time) error {
+	args := m.Called(ctx, userID, jti, at)
+	return args.Error(0)
+}
+
+func newTestAdminService(repo *MockRepo) AdminService {
+	permissions := &MockPermissionCache{}
+	permissions.On("InvalidateUsers", mock.Anything, mock.Anything).Return(nil).Maybe()
+	permissions.On("InvalidateRoles", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return NewAdminService(repo, NewTokenVersions(repo, cache.NewMemoryCache[string]()), permissions)
+}
+
+// expectAudit expects one audit entry for action and returns the entry the
+// service wrote, filled in once CreateAuditLog is called.
+func expectAudit(repo *MockRepo, action string) *models.AuditLog {
+	entry := &models.AuditLog{}
+	repo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l *models.AuditLog) bool {
+		return l.Action == action
+	})).Run(func(args mock.Arguments) {
+		*entry = *args.Get(1).(*models.AuditLog)
+	}).Return(nil).Once()
+	return entry
+}
+
 func TestGetUsers(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 
 	// Use filters that will be normalized by the service
 	filters := &AdminUserFilters{Page: 0, PerPage: 200}
@@ -178,7 +328,7 @@ func TestGetUsers(t *testing.T) {
 
 func TestGetUsers_Error(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	filters := &AdminUserFilters{Page: 1, PerPage: 20}
 
 	repo.On("GetUsers", mock.Anything, filters).Return(nil, int64(0), errors.New("fail"))
@@ -191,22 +341,27 @@ func TestGetUsers_Error(t *testing.T) {
 
 func TestUpdateUserStatus(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	id := uuid.New()
 
+	repo.On("GetByID", mock.Anything, id).Return(&models.User{ID: id, IsActive: ptrBool(false)}, nil)
 	repo.On("UpdateUserStatus", mock.Anything, id, true).Return(nil)
+	entry := expectAudit(repo, models.AuditActionUserStatusChanged)
 
 	err := svc.UpdateUserStatus(context.Background(), id, true)
 	assert.NoError(t, err)
+	assert.Equal(t, models.AuditValues{"is_active": ptrBool(false)}, entry.OldValues)
+	assert.Equal(t, models.AuditValues{"is_active": true}, entry.NewValues)
 
 	repo.AssertExpectations(t)
 }
 
 func TestUpdateUserStatus_Error(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	id := uuid.New()
 
+	repo.On("GetByID", mock.Anything, id).Return(&models.User{ID: id, IsActive: ptrBool(true)}, nil)
 	repo.On("UpdateUserStatus", mock.Anything, id, false).Return(errors.New("err"))
 
 	err := svc.UpdateUserStatus(context.Background(), id, false)
@@ -217,22 +372,30 @@ func TestUpdateUserStatus_Error(t *testing.T) {
 
 func TestAssignRole(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	u, r := uuid.New(), uuid.New()
 
+	repo.On("GetUserByIDWithRoles", mock.Anything, u).
+		Return(&models.User{ID: u, Roles: []models.Role{{Name: "support"}}}, nil).Once()
 	repo.On("AssignRole", mock.Anything, u, r).Return(nil)
+	repo.On("GetUserByIDWithRoles", mock.Anything, u).
+		Return(&models.User{ID: u, Roles: []models.Role{{Name: "trader"}, {ID: r, Name: "support"}}}, nil).Once()
+	entry := expectAudit(repo, models.AuditActionRoleAssigned)
 
 	err := svc.AssignRole(context.Background(), u, r)
 	assert.NoError(t, err)
+	assert.Equal(t, models.AuditValues{"roles": []string{"support"}}, entry.OldValues)
+	assert.Equal(t, models.AuditValues{"roles": []string{"support", "trader"}}, entry.NewValues)
 
 	repo.AssertExpectations(t)
 }
 
 func TestAssignRole_Error(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	u, r := uuid.New(), uuid.New()
 
+	repo.On("GetUserByIDWithRoles", mock.Anything, u).Return(&models.User{ID: u}, nil)
 	repo.On("AssignRole", mock.Anything, u, r).Return(errors.New("fail"))
 
 	err := svc.AssignRole(context.Background(), u, r)
@@ -243,22 +406,30 @@ func TestAssignRole_Error(t *testing.T) {
 
 func TestBulkAssignPermissions(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	uids, pids := []uuid.UUID{uuid.New()}, []uuid.UUID{uuid.New()}
 
+	granted := models.Role{Permissions: []models.Permission{{Name: "markets:read"}}}
+	repo.On("GetByIDWithPermissions", mock.Anything, uids[0]).Return(&models.User{ID: uids[0]}, nil).Once()
 	repo.On("BulkAssignPermissions", mock.Anything, uids, pids).Return(nil)
+	repo.On("GetByIDWithPermissions", mock.Anything, uids[0]).
+		Return(&models.User{ID: uids[0], Roles: []models.Role{granted}}, nil).Once()
+	entry := expectAudit(repo, models.AuditActionPermissionsGranted)
 
 	err := svc.BulkAssignPermissions(context.Background(), uids, pids)
 	assert.NoError(t, err)
+	assert.Equal(t, models.AuditValues{"permissions": []string{}}, entry.OldValues)
+	assert.Equal(t, models.AuditValues{"permissions": []string{"markets:read"}}, entry.NewValues)
 
 	repo.AssertExpectations(t)
 }
 
 func TestBulkAssignPermissions_Error(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	uids, pids := []uuid.UUID{uuid.New()}, []uuid.UUID{uuid.New()}
 
+	repo.On("GetByIDWithPermissions", mock.Anything, uids[0]).Return(&models.User{ID: uids[0]}, nil)
 	repo.On("BulkAssignPermissions", mock.Anything, uids, pids).Return(errors.New("err"))
 
 	err := svc.BulkAssignPermissions(context.Background(), uids, pids)
@@ -269,7 +440,7 @@ func TestBulkAssignPermissions_Error(t *testing.T) {
 
 func TestCreatePermission(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	req := &CreatePermissionRequest{Name: "test_permission", Description: "Test Description"}
 
 	repo.On("GetPermissionByName", mock.Anything, req.Name).Return(nil, gorm.ErrRecordNotFound)
@@ -282,6 +453,7 @@ func TestCreatePermission(t *testing.T) {
 		p.CreatedAt = time.Now()
 		p.UpdatedAt = time.Now()
 	}).Return(nil)
+	expectAudit(repo, models.AuditActionPermissionCreated)
 
 	resp, err := svc.CreatePermission(context.Background(), req)
 	assert.NoError(t, err)
@@ -294,7 +466,7 @@ func TestCreatePermission(t *testing.T) {
 
 func TestCreatePermission_Exists(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	existing := &models.Permission{ID: uuid.New(), Name: "existing"}
 
 	repo.On("GetPermissionByName", mock.Anything, "existing").Return(existing, nil)
@@ -308,7 +480,7 @@ func TestCreatePermission_Exists(t *testing.T) {
 
 func TestCreatePermission_ErrorOnCheck(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 
 	repo.On("GetPermissionByName", mock.Anything, "test").Return(nil, errors.New("db error"))
 
@@ -320,7 +492,7 @@ func TestCreatePermission_ErrorOnCheck(t *testing.T) {
 
 func TestCreatePermission_ErrorOnCreate(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 
 	repo.On("GetPermissionByName", mock.Anything, "test").Return(nil, gorm.ErrRecordNotFound)
 	repo.On("CreatePermission", mock.Anything, mock.Anything).Return(errors.New("create error"))
@@ -333,7 +505,7 @@ func TestCreatePermission_ErrorOnCreate(t *testing.T) {
 
 func TestCreateRole(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	req := &CreateRoleRequest{Name: "test_role", Description: "Test Description"}
 
 	repo.On("CreateRole", mock.Anything, mock.MatchedBy(func(r *models.Role) bool {
@@ -345,19 +517,22 @@ func TestCreateRole(t *testing.T) {
 		r.CreatedAt = time.Now()
 		r.UpdatedAt = time.Now()
 	}).Return(nil)
+	entry := expectAudit(repo, models.AuditActionRoleCreated)
 
 	resp, err := svc.CreateRole(context.Background(), req)
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, resp.ID)
 	assert.Equal(t, req.Name, resp.Name)
 	assert.Equal(t, req.Description, resp.Description)
+	assert.Nil(t, entry.OldValues)
+	assert.Equal(t, req.Name, entry.NewValues["name"])
 
 	repo.AssertExpectations(t)
 }
 
 func TestCreateRole_Error(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 
 	repo.On("CreateRole", mock.Anything, mock.Anything).Return(errors.New("create error"))
 
@@ -369,7 +544,7 @@ func TestCreateRole_Error(t *testing.T) {
 
 func TestUpdateRole_NotFound(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	id := uuid.New()
 
 	repo.On("GetRoleByID", mock.Anything, id).Return(nil, gorm.ErrRecordNotFound)
@@ -382,7 +557,7 @@ func TestUpdateRole_NotFound(t *testing.T) {
 
 func TestUpdateRole_ErrorOnGet(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	id := uuid.New()
 
 	repo.On("GetRoleByID", mock.Anything, id).Return(nil, errors.New("get error"))
@@ -395,7 +570,7 @@ func TestUpdateRole_ErrorOnGet(t *testing.T) {
 
 func TestUpdateRole_ErrorOnUpdate(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	id := uuid.New()
 	role := &models.Role{ID: id, Name: "old", Description: "old"}
 
@@ -412,7 +587,7 @@ func TestUpdateRole_ErrorOnUpdate(t *testing.T) {
 
 func TestUpdateRole_Success(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	id := uuid.New()
 	role := &models.Role{
 		ID:          id,
@@ -426,6 +601,7 @@ func TestUpdateRole_Success(t *testing.T) {
 	repo.On("UpdateRole", mock.Anything, mock.MatchedBy(func(r *models.Role) bool {
 		return r.ID == id && r.Name == "new" && r.Description == "new_desc"
 	})).Return(nil)
+	entry := expectAudit(repo, models.AuditActionRoleUpdated)
 
 	resp, err := svc.UpdateRole(context.Background(), id, &UpdateRoleRequest{
 		Name:        ptrString("new"),
@@ -435,17 +611,93 @@ func TestUpdateRole_Success(t *testing.T) {
 	assert.Equal(t, id, resp.ID)
 	assert.Equal(t, "new", resp.Name)
 	assert.Equal(t, "new_desc", resp.Description)
+	assert.Equal(t, "old", entry.OldValues["name"])
+	assert.Equal(t, "new", entry.NewValues["name"])
 
 	repo.AssertExpectations(t)
 }
 
+func TestUpdateRole_SetsParent(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+	parentID := uuid.New()
+	role := &models.Role{ID: id, Name: "moderator"}
+
+	repo.On("GetRoleByID", mock.Anything, id).Return(role, nil)
+	repo.On("GetRoleByID", mock.Anything, parentID).Return(&models.Role{ID: parentID, Name: "support"}, nil)
+	repo.On("GetRoleAncestorIDs", mock.Anything, []uuid.UUID{parentID}).Return([]uuid.UUID{uuid.New()}, nil)
+	repo.On("UpdateRole", mock.Anything, mock.MatchedBy(func(r *models.Role) bool {
+		return r.ParentID != nil && *r.ParentID == parentID
+	})).Return(nil)
+	expectAudit(repo, models.AuditActionRoleUpdated)
+
+	resp, err := svc.UpdateRole(context.Background(), id, &UpdateRoleRequest{ParentID: &parentID})
+	assert.NoError(t, err)
+	assert.Equal(t, &parentID, resp.ParentID)
+
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateRole_ClearsParent(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+	parentID := uuid.New()
+	role := &models.Role{ID: id, Name: "moderator", ParentID: &parentID}
+
+	repo.On("GetRoleByID", mock.Anything, id).Return(role, nil)
+	repo.On("UpdateRole", mock.Anything, mock.MatchedBy(func(r *models.Role) bool {
+		return r.ParentID == nil
+	})).Return(nil)
+	expectAudit(repo, models.AuditActionRoleUpdated)
+
+	_, err := svc.UpdateRole(context.Background(), id, &UpdateRoleRequest{ParentID: &uuid.Nil})
+	assert.NoError(t, err)
+
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateRole_RejectsInheritanceCycle(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+	childID := uuid.New()
+
+	repo.On("GetRoleByID", mock.Anything, id).Return(&models.Role{ID: id, Name: "admin"}, nil)
+	repo.On("GetRoleByID", mock.Anything, childID).Return(&models.Role{ID: childID, Name: "super_admin", ParentID: &id}, nil)
+	repo.On("GetRoleAncestorIDs", mock.Anything, []uuid.UUID{childID}).Return([]uuid.UUID{id}, nil)
+
+	_, err := svc.UpdateRole(context.Background(), id, &UpdateRoleRequest{ParentID: &childID})
+	assert.ErrorIs(t, err, models.ErrRoleInheritanceCycle)
+
+	_, err = svc.UpdateRole(context.Background(), id, &UpdateRoleRequest{ParentID: &id})
+	assert.ErrorIs(t, err, models.ErrRoleInheritanceCycle)
+
+	repo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything)
+}
+
+func TestCreateRole_UnknownParent(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	parentID := uuid.New()
+
+	repo.On("GetRoleByID", mock.Anything, parentID).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.CreateRole(context.Background(), &CreateRoleRequest{Name: "moderator", ParentID: &parentID})
+	assert.ErrorIs(t, err, models.ErrInvalidParentRole)
+
+	repo.AssertNotCalled(t, "CreateRole", mock.Anything, mock.Anything)
+}
+
 func TestAssignPermissionsToRole(t *testing.T) {
 	t.Run("error getting permissions", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		rid := uuid.New()
 		codes := []string{"c1", "c2"}
 
+		repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid}, nil)
 		repo.On("GetPermissionsByNames", mock.Anything, codes).Return(nil, errors.New("get error"))
 
 		_, err := svc.AssignPermissionsToRole(context.Background(), rid, &AssignPermissionsRequest{PermissionCodes: codes})
@@ -456,9 +708,10 @@ func TestAssignPermissionsToRole(t *testing.T) {
 
 	t.Run("permission codes not found", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		rid := uuid.New()
 
+		repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid}, nil)
 		repo.On("GetPermissionsByNames", mock.Anything, []string{"c1", "c2", "c3"}).Return([]models.Permission{{ID: uuid.New()}, {ID: uuid.New()}}, nil)
 
 		_, err := svc.AssignPermissionsToRole(context.Background(), rid, &AssignPermissionsRequest{PermissionCodes: []string{"c1", "c2", "c3"}})
@@ -469,12 +722,13 @@ func TestAssignPermissionsToRole(t *testing.T) {
 
 	t.Run("error assigning permissions", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		rid := uuid.New()
 		codes := []string{"c1", "c2"}
 		perms := []models.Permission{{ID: uuid.New()}, {ID: uuid.New()}}
 		ids := []uuid.UUID{perms[0].ID, perms[1].ID}
 
+		repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid}, nil)
 		repo.On("GetPermissionsByNames", mock.Anything, codes).Return(perms, nil)
 		repo.On("AssignPermissionsToRole", mock.Anything, rid, ids).Return(errors.New("assign error"))
 
@@ -486,7 +740,7 @@ func TestAssignPermissionsToRole(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		rid := uuid.New()
 		codes := []string{"c1", "c2"}
 		perms := []models.Permission{
@@ -503,14 +757,18 @@ func TestAssignPermissionsToRole(t *testing.T) {
 			UpdatedAt:   time.Now(),
 		}
 
+		repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid, Name: "test_role"}, nil).Once()
 		repo.On("GetPermissionsByNames", mock.Anything, codes).Return(perms, nil)
 		repo.On("AssignPermissionsToRole", mock.Anything, rid, ids).Return(nil)
-		repo.On("GetRoleByID", mock.Anything, rid).Return(updatedRole, nil)
+		repo.On("GetRoleByID", mock.Anything, rid).Return(updatedRole, nil).Once()
+		entry := expectAudit(repo, models.AuditActionRoleUpdated)
 
 		resp, err := svc.AssignPermissionsToRole(context.Background(), rid, &AssignPermissionsRequest{PermissionCodes: codes})
 		assert.NoError(t, err)
 		assert.Equal(t, rid, resp.ID)
 		assert.Len(t, resp.Permissions, len(perms))
+		assert.Equal(t, models.AuditValues{"permissions": []string{}}, entry.OldValues)
+		assert.Equal(t, models.AuditValues{"permissions": []string{"c1", "c2"}}, entry.NewValues)
 
 		repo.AssertExpectations(t)
 	})
@@ -519,10 +777,11 @@ func TestAssignPermissionsToRole(t *testing.T) {
 func TestRemovePermissionsFromRole(t *testing.T) {
 	t.Run("error getting permissions", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		rid := uuid.New()
 		codes := []string{"c1"}
 
+		repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid}, nil)
 		repo.On("GetPermissionsByNames", mock.Anything, codes).Return(nil, errors.New("get error"))
 
 		_, err := svc.RemovePermissionsFromRole(context.Background(), rid, &RemovePermissionsRequest{PermissionCodes: codes})
@@ -533,10 +792,11 @@ func TestRemovePermissionsFromRole(t *testing.T) {
 
 	t.Run("permission codes not found", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		rid := uuid.New()
 		codes := []string{"c1"}
 
+		repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid}, nil)
 		repo.On("GetPermissionsByNames", mock.Anything, codes).Return([]models.Permission{}, nil)
 
 		_, err := svc.RemovePermissionsFromRole(context.Background(), rid, &RemovePermissionsRequest{PermissionCodes: codes})
@@ -547,12 +807,13 @@ func TestRemovePermissionsFromRole(t *testing.T) {
 
 	t.Run("error removing permissions", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		rid := uuid.New()
 		codes := []string{"c1"}
 		perms := []models.Permission{{ID: uuid.New()}}
 		ids := []uuid.UUID{perms[0].ID}
 
+		repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid}, nil)
 		repo.On("GetPermissionsByNames", mock.Anything, codes).Return(perms, nil)
 		repo.On("RemovePermissionsFromRole", mock.Anything, rid, ids).Return(errors.New("remove error"))
 
@@ -564,7 +825,7 @@ func TestRemovePermissionsFromRole(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		rid := uuid.New()
 		codes := []string{"c1"}
 		perms := []models.Permission{{ID: uuid.New(), Name: "c1"}}
@@ -578,14 +839,18 @@ func TestRemovePermissionsFromRole(t *testing.T) {
 			UpdatedAt:   time.Now(),
 		}
 
+		repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid, Permissions: perms}, nil).Once()
 		repo.On("GetPermissionsByNames", mock.Anything, codes).Return(perms, nil)
 		repo.On("RemovePermissionsFromRole", mock.Anything, rid, ids).Return(nil)
-		repo.On("GetRoleByID", mock.Anything, rid).Return(updatedRole, nil)
+		repo.On("GetRoleByID", mock.Anything, rid).Return(updatedRole, nil).Once()
+		entry := expectAudit(repo, models.AuditActionRoleUpdated)
 
 		resp, err := svc.RemovePermissionsFromRole(context.Background(), rid, &RemovePermissionsRequest{PermissionCodes: codes})
 		assert.NoError(t, err)
 		assert.Equal(t, rid, resp.ID)
 		assert.Empty(t, resp.Permissions)
+		assert.Equal(t, models.AuditValues{"permissions": []string{"c1"}}, entry.OldValues)
+		assert.Equal(t, models.AuditValues{"permissions": []string{}}, entry.NewValues)
 
 		repo.AssertExpectations(t)
 	})
@@ -594,7 +859,7 @@ func TestRemovePermissionsFromRole(t *testing.T) {
 func TestGetUserByID(t *testing.T) {
 	t.Run("user not found", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		uid := uuid.New()
 
 		repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(nil, gorm.ErrRecordNotFound)
@@ -607,7 +872,7 @@ func TestGetUserByID(t *testing.T) {
 
 	t.Run("database error", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		uid := uuid.New()
 
 		repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(nil, errors.New("db error"))
@@ -620,7 +885,7 @@ func TestGetUserByID(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		uid := uuid.New()
 		usr := &models.User{
 			ID:        uid,
@@ -649,7 +914,7 @@ func TestGetUserByID(t *testing.T) {
 func TestRemoveRoleFromUser(t *testing.T) {
 	t.Run("user not found", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		uid, rid := uuid.New(), uuid.New()
 
 		repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(nil, gorm.ErrRecordNotFound)
@@ -662,7 +927,7 @@ func TestRemoveRoleFromUser(t *testing.T) {
 
 	t.Run("database error getting user", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		uid, rid := uuid.New(), uuid.New()
 
 		repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(nil, errors.New("db error"))
@@ -675,7 +940,7 @@ func TestRemoveRoleFromUser(t *testing.T) {
 
 	t.Run("user does not have role", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		uid, rid := uuid.New(), uuid.New()
 		user := &models.User{ID: uid, Roles: []models.Role{}}
 
@@ -689,7 +954,7 @@ func TestRemoveRoleFromUser(t *testing.T) {
 
 	t.Run("error removing role", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		uid, rid := uuid.New(), uuid.New()
 		userWithRole := &models.User{ID: uid, Roles: []models.Role{{ID: rid}}}
 
@@ -704,9 +969,9 @@ func TestRemoveRoleFromUser(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		repo := &MockRepo{}
-		svc := NewAdminService(repo)
+		svc := newTestAdminService(repo)
 		uid, rid := uuid.New(), uuid.New()
-		userWithRole := &models.User{ID: uid, Roles: []models.Role{{ID: rid}}}
+		userWithRole := &models.User{ID: uid, Roles: []models.Role{{ID: rid, Name: "trader"}}}
 		updatedUser := &models.User{
 			ID:        uid,
 			Email:     "test@example.com",
@@ -721,12 +986,16 @@ func TestRemoveRoleFromUser(t *testing.T) {
 
 		repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(userWithRole, nil).Once()
 		repo.On("RemoveRoleFromUser", mock.Anything, uid, rid).Return(nil)
+		repo.On("IncrementTokenVersion", mock.Anything, uid).Return(int64(2), nil)
 		repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(updatedUser, nil).Once()
+		entry := expectAudit(repo, models.AuditActionRoleRevoked)
 
 		resp, err := svc.RemoveRoleFromUser(context.Background(), uid, rid)
 		assert.NoError(t, err)
 		assert.Equal(t, uid, resp.ID)
 		assert.Empty(t, resp.Roles)
+		assert.Equal(t, models.AuditValues{"roles": []string{"trader"}}, entry.OldValues)
+		assert.Equal(t, models.AuditValues{"roles": []string{}}, entry.NewValues)
 
 		repo.AssertExpectations(t)
 	})
@@ -734,7 +1003,7 @@ func TestRemoveRoleFromUser(t *testing.T) {
 
 func TestGetUsers_WithRoles(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	filters := &AdminUserFilters{Page: 1, PerPage: 20}
 
 	role1 := models.Role{
@@ -782,15 +1051,16 @@ func TestGetUsers_WithRoles(t *testing.T) {
 
 func TestAssignPermissionsToRole_ErrorGettingUpdatedRole(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	rid := uuid.New()
 	codes := []string{"c1"}
 	perms := []models.Permission{{ID: uuid.New(), Name: "c1"}}
 	ids := []uuid.UUID{perms[0].ID}
 
+	repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid}, nil).Once()
 	repo.On("GetPermissionsByNames", mock.Anything, codes).Return(perms, nil)
 	repo.On("AssignPermissionsToRole", mock.Anything, rid, ids).Return(nil)
-	repo.On("GetRoleByID", mock.Anything, rid).Return(nil, errors.New("failed to fetch updated role"))
+	repo.On("GetRoleByID", mock.Anything, rid).Return(nil, errors.New("failed to fetch updated role")).Once()
 
 	_, err := svc.AssignPermissionsToRole(context.Background(), rid, &AssignPermissionsRequest{PermissionCodes: codes})
 	assert.EqualError(t, err, "failed to get updated role: failed to fetch updated role")
@@ -800,15 +1070,16 @@ func TestAssignPermissionsToRole_ErrorGettingUpdatedRole(t *testing.T) {
 
 func TestRemovePermissionsFromRole_ErrorGettingUpdatedRole(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	rid := uuid.New()
 	codes := []string{"c1"}
 	perms := []models.Permission{{ID: uuid.New(), Name: "c1"}}
 	ids := []uuid.UUID{perms[0].ID}
 
+	repo.On("GetRoleByID", mock.Anything, rid).Return(&models.Role{ID: rid}, nil).Once()
 	repo.On("GetPermissionsByNames", mock.Anything, codes).Return(perms, nil)
 	repo.On("RemovePermissionsFromRole", mock.Anything, rid, ids).Return(nil)
-	repo.On("GetRoleByID", mock.Anything, rid).Return(nil, errors.New("failed to fetch updated role"))
+	repo.On("GetRoleByID", mock.Anything, rid).Return(nil, errors.New("failed to fetch updated role")).Once()
 
 	_, err := svc.RemovePermissionsFromRole(context.Background(), rid, &RemovePermissionsRequest{PermissionCodes: codes})
 	assert.EqualError(t, err, "failed to get updated role: failed to fetch updated role")
@@ -818,12 +1089,13 @@ func TestRemovePermissionsFromRole_ErrorGettingUpdatedRole(t *testing.T) {
 
 func TestRemoveRoleFromUser_ErrorGettingUpdatedUser(t *testing.T) {
 	repo := &MockRepo{}
-	svc := NewAdminService(repo)
+	svc := newTestAdminService(repo)
 	uid, rid := uuid.New(), uuid.New()
 	userWithRole := &models.User{ID: uid, Roles: []models.Role{{ID: rid}}}
 
 	repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(userWithRole, nil).Once()
 	repo.On("RemoveRoleFromUser", mock.Anything, uid, rid).Return(nil)
+	repo.On("IncrementTokenVersion", mock.Anything, uid).Return(int64(2), nil)
 	repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(nil, errors.New("failed to fetch updated user")).Once()
 
 	_, err := svc.RemoveRoleFromUser(context.Background(), uid, rid)
@@ -831,3 +1103,283 @@ func TestRemoveRoleFromUser_ErrorGettingUpdatedUser(t *testing.T) {
 
 	repo.AssertExpectations(t)
 }
+
+func TestUnlockUser(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	actorID, uid := uuid.New(), uuid.New()
+	lockedUntil := time.Now().Add(time.Hour)
+	user := &models.User{ID: uid, FailedLoginAttempts: 5, LockedUntil: &lockedUntil}
+
+	repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(user, nil)
+	repo.On("UpdateLoginState", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+		return u.FailedLoginAttempts == 0 && u.LockedUntil == nil
+	})).Return(nil)
+	repo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l *models.AuditLog) bool {
+		return l.Action == models.AuditActionAccountUnlocked && *l.UserID == actorID && *l.ResourceID == uid
+	})).Return(nil)
+
+	res, err := svc.UnlockUser(context.Background(), actorID, uid)
+	assert.NoError(t, err)
+	assert.Equal(t, uid, res.ID)
+
+	repo.AssertExpectations(t)
+}
+
+func TestUnlockUser_NotFound(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	uid := uuid.New()
+
+	repo.On("GetUserByIDWithRoles", mock.Anything, uid).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.UnlockUser(context.Background(), uuid.New(), uid)
+	assert.ErrorIs(t, err, models.ErrRecordNotFound)
+
+	repo.AssertNotCalled(t, "UpdateLoginState")
+}
+
+func TestUpdateUserStatus_DeactivateRevokesSessions(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+
+	repo.On("GetByID", mock.Anything, id).Return(&models.User{ID: id, IsActive: ptrBool(true)}, nil)
+	repo.On("UpdateUserStatus", mock.Anything, id, false).Return(nil)
+	repo.On("IncrementTokenVersion", mock.Anything, id).Return(int64(3), nil)
+	expectAudit(repo, models.AuditActionUserStatusChanged)
+
+	err := svc.UpdateUserStatus(context.Background(), id, false)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestRevokeSessions(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	actorID, userID := uuid.New(), uuid.New()
+
+	repo.On("IncrementTokenVersion", mock.Anything, userID).Return(int64(2), nil)
+	repo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(log *models.AuditLog) bool {
+		return log.Action == models.AuditActionSessionsRevoked && *log.UserID == actorID && *log.ResourceID == userID
+	})).Return(nil)
+
+	err := svc.RevokeSessions(context.Background(), actorID, userID)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestRevokeSessions_NotFound(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	userID := uuid.New()
+
+	repo.On("IncrementTokenVersion", mock.Anything, userID).Return(int64(0), models.ErrRecordNotFound)
+
+	err := svc.RevokeSessions(context.Background(), uuid.New(), userID)
+
+	assert.ErrorIs(t, err, models.ErrRecordNotFound)
+	repo.AssertNotCalled(t, "CreateAuditLog", mock.Anything, mock.Anything)
+}
+
+func TestListRoles(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+
+	roles := []models.Role{{
+		ID:          uuid.New(),
+		Name:        "support",
+		Permissions: []models.Permission{{ID: uuid.New(), Name: "admin:users:read"}},
+	}}
+	repo.On("ListRoles", mock.Anything, &RBACFilters{Page: 1, PerPage: 20, Search: "sup"}).Return(roles, int64(1), nil)
+
+	res, total, err := svc.ListRoles(context.Background(), &RBACFilters{PerPage: 500, Search: "sup"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "support", res[0].Name)
+	assert.Equal(t, "admin:users:read", res[0].Permissions[0].Name)
+	repo.AssertExpectations(t)
+}
+
+func TestListPermissions(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+
+	permissions := []models.Permission{{ID: uuid.New(), Name: "admin"}, {ID: uuid.New(), Name: "admin:users:read"}}
+	repo.On("ListPermissions", mock.Anything, &RBACFilters{Page: 2, PerPage: 20}).Return(permissions, int64(22), nil)
+
+	res, total, err := svc.ListPermissions(context.Background(), &RBACFilters{Page: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(22), total)
+	assert.Len(t, res, 2)
+	assert.Equal(t, permissions[1].ID, res[1].ID)
+}
+
+func TestGetRole_NotFound(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+
+	repo.On("GetRoleByID", mock.Anything, id).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.GetRole(context.Background(), id)
+	assert.ErrorIs(t, err, models.ErrRecordNotFound)
+}
+
+func TestGetRoleMembers(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	roleID := uuid.New()
+
+	users := []models.User{{ID: uuid.New(), Email: "a@example.com", IsActive: ptrBool(true)}}
+	repo.On("GetRoleByID", mock.Anything, roleID).Return(&models.Role{ID: roleID}, nil)
+	repo.On("GetRoleMembers", mock.Anything, roleID, &AdminUserFilters{Page: 1, PerPage: 20}).Return(users, int64(1), nil)
+
+	res, total, err := svc.GetRoleMembers(context.Background(), roleID, &AdminUserFilters{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, users[0].ID, res[0].ID)
+	repo.AssertExpectations(t)
+}
+
+func TestGetRoleMembers_RoleNotFound(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	roleID := uuid.New()
+
+	repo.On("GetRoleByID", mock.Anything, roleID).Return(nil, gorm.ErrRecordNotFound)
+
+	_, _, err := svc.GetRoleMembers(context.Background(), roleID, &AdminUserFilters{})
+
+	assert.ErrorIs(t, err, models.ErrRecordNotFound)
+	repo.AssertNotCalled(t, "GetRoleMembers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteRole_Unassigned(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+
+	repo.On("GetRoleByID", mock.Anything, id).Return(&models.Role{ID: id}, nil)
+	repo.On("DeleteRole", mock.Anything, id, false).Return([]uuid.UUID{}, nil)
+	expectAudit(repo, models.AuditActionRoleDeleted)
+
+	assert.NoError(t, svc.DeleteRole(context.Background(), id, false))
+	repo.AssertExpectations(t)
+}
+
+func TestDeleteRole_InUse(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+
+	repo.On("GetRoleByID", mock.Anything, id).Return(&models.Role{ID: id}, nil)
+	repo.On("DeleteRole", mock.Anything, id, false).Return(nil, models.ErrRoleInUse)
+
+	err := svc.DeleteRole(context.Background(), id, false)
+
+	assert.ErrorIs(t, err, models.ErrRoleInUse)
+	repo.AssertNotCalled(t, "CreateAuditLog", mock.Anything, mock.Anything)
+}
+
+func TestDeleteRole_ForcedPurgesMemberPermissions(t *testing.T) {
+	repo := &MockRepo{}
+	permissions := &MockPermissionCache{}
+	svc := NewAdminService(repo, NewTokenVersions(repo, cache.NewMemoryCache[string]()), permissions)
+	id := uuid.New()
+	members := []uuid.UUID{uuid.New(), uuid.New()}
+
+	repo.On("GetRoleByID", mock.Anything, id).Return(&models.Role{ID: id, Name: "moderator"}, nil)
+	repo.On("DeleteRole", mock.Anything, id, true).Return(members, nil)
+	entry := expectAudit(repo, models.AuditActionRoleDeleted)
+	permissions.On("InvalidateUsers", mock.Anything, members).Return(nil)
+
+	assert.NoError(t, svc.DeleteRole(context.Background(), id, true))
+	assert.Equal(t, "moderator", entry.OldValues["name"])
+	assert.Equal(t, 2, entry.OldValues["affected_users"])
+	assert.Nil(t, entry.NewValues)
+	repo.AssertExpectations(t)
+	permissions.AssertExpectations(t)
+}
+
+func TestDeleteRole_NotFound(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+
+	repo.On("GetRoleByID", mock.Anything, id).Return(nil, gorm.ErrRecordNotFound)
+
+	assert.ErrorIs(t, svc.DeleteRole(context.Background(), id, true), models.ErrRecordNotFound)
+}
+
+func TestDeletePermission(t *testing.T) {
+	repo := &MockRepo{}
+	permissions := &MockPermissionCache{}
+	svc := NewAdminService(repo, NewTokenVersions(repo, cache.NewMemoryCache[string]()), permissions)
+	id := uuid.New()
+	roleIDs := []uuid.UUID{uuid.New()}
+	members := []uuid.UUID{uuid.New()}
+
+	repo.On("GetPermissionByID", mock.Anything, id).Return(&models.Permission{ID: id, Name: "markets:read"}, nil)
+	repo.On("GetRoleIDsByPermission", mock.Anything, id).Return(roleIDs, nil)
+	repo.On("GetUserIDsByRoles", mock.Anything, roleIDs).Return(members, nil)
+	repo.On("DeletePermission", mock.Anything, id).Return(nil)
+	entry := expectAudit(repo, models.AuditActionPermissionDeleted)
+	permissions.On("InvalidateUsers", mock.Anything, members).Return(nil)
+
+	assert.NoError(t, svc.DeletePermission(context.Background(), id))
+	assert.Equal(t, "markets:read", entry.OldValues["name"])
+	repo.AssertExpectations(t)
+	permissions.AssertExpectations(t)
+}
+
+func TestDeletePermission_NotFound(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	id := uuid.New()
+
+	repo.On("GetPermissionByID", mock.Anything, id).Return(nil, gorm.ErrRecordNotFound)
+
+	assert.ErrorIs(t, svc.DeletePermission(context.Background(), id), models.ErrRecordNotFound)
+	repo.AssertNotCalled(t, "DeletePermission", mock.Anything, mock.Anything)
+}
+
+func TestGetUserPermissions_DedupesInheritedGrants(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	userID := uuid.New()
+	read := models.Permission{ID: uuid.New(), Name: "admin:users:read"}
+	admin := models.Permission{ID: uuid.New(), Name: "admin"}
+
+	repo.On("GetByIDWithPermissions", mock.Anything, userID).Return(&models.User{
+		ID: userID,
+		Roles: []models.Role{
+			{Name: "support", Permissions: []models.Permission{read}},
+			{Name: "staff", Permissions: []models.Permission{admin, read}},
+		},
+	}, nil)
+
+	res, err := svc.GetUserPermissions(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "admin", res[0].Name)
+	assert.Equal(t, "admin:users:read", res[1].Name)
+}
+
+func TestGetUserPermissions_NotFound(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAdminService(repo)
+	userID := uuid.New()
+
+	repo.On("GetByIDWithPermissions", mock.Anything, userID).Return(&models.User{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.GetUserPermissions(context.Background(), userID)
+	assert.ErrorIs(t, err, models.ErrRecordNotFound)
+}
@@ -2,36 +2,64 @@ package user
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"time"
 
 	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
 	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
 )
 
 type AuthService interface {
-	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) (*api.PermissionMatcher, error)
+	ValidateTokenVersion(ctx context.Context, userID uuid.UUID, version int64) error
+	ValidateSession(ctx context.Context, payload *security.Payload) error
 }
 
 type authService struct {
-	repo  Repository
-	cache cache.Cache[string]
+	repo        Repository
+	cache       cache.Cache[string]
+	versions    TokenVersions
+	sessions    SessionService
+	permissions PermissionCache
 }
 
-func NewAuthService(repo Repository, cache cache.Cache[string]) AuthService {
-	return &authService{repo: repo, cache: cache}
+func NewAuthService(repo Repository,
+	cache cache.Cache[string],
+	sessions SessionService,
+	permissions PermissionCache) AuthService {
+	return &authService{
+		repo:        repo,
+		cache:       cache,
+		versions:    NewTokenVersions(repo, cache),
+		sessions:    sessions,
+		permissions: permissions,
+	}
 }
 
-func (s *authService) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
-	cacheKey := fmt.Sprintf("user:%s:permissions", userID)
+// ValidateSession returns models.ErrTokenRevoked when the token's session was revoked.
+func (s *authService) ValidateSession(ctx context.Context, payload *security.Payload) error {
+	return s.sessions.Validate(ctx, payload)
+}
 
-	cachedPermissions, err := s.cache.Get(ctx, cacheKey)
-	if err == nil && cachedPermissions != "" {
-		var permissions []string
-		if err := json.Unmarshal([]byte(cachedPermissions), &permissions); err == nil {
-			return permissions, nil
-		}
+// ValidateTokenVersion returns models.ErrTokenRevoked when the token was issued
+// before the user's sessions were last revoked.
+func (s *authService) ValidateTokenVersion(ctx context.Context, userID uuid.UUID, version int64) error {
+	current, err := s.versions.Current(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if version != current {
+		return models.ErrTokenRevoked
+	}
+	return nil
+}
+
+// GetUserPermissions resolves the user's grants and denies, including those
+// inherited through parent roles, into a matcher.
+func (s *authService) GetUserPermissions(ctx context.Context, userID uuid.UUID) (*api.PermissionMatcher, error) {
+	if matcher, ok := s.permissions.Get(ctx, userID); ok {
+		return matcher, nil
 	}
 
 	user, err := s.repo.GetByIDWithPermissions(ctx, userID)
@@ -51,10 +79,5 @@ func (s *authService) GetUserPermissions(ctx context.Context, userID uuid.UUID)
 		permissions = append(permissions, perm)
 	}
 
-	permissionsJSON, err := json.Marshal(permissions)
-	if err == nil {
-		err = s.cache.Set(ctx, cacheKey, string(permissionsJSON), 30*time.Minute)
-	}
-
-	return permissions, err
+	return s.permissions.Set(ctx, userID, permissions)
 }
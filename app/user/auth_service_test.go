@@ -18,7 +18,7 @@ import (
 func TestGetUserPermissions_CacheHit(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -29,7 +29,7 @@ func TestGetUserPermissions_CacheHit(t *testing.T) {
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
 	assert.NoError(t, err)
-	assert.Equal(t, []string{"read", "write", "delete"}, permissions)
+	assert.ElementsMatch(t, []string{"read", "write", "delete"}, permissions.Permissions())
 
 	repo.AssertNotCalled(t, "GetByIDWithPermissions")
 	mockCache.AssertExpectations(t)
@@ -38,7 +38,7 @@ func TestGetUserPermissions_CacheHit(t *testing.T) {
 func TestGetUserPermissions_CacheHitInvalidJSON(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -65,7 +65,7 @@ func TestGetUserPermissions_CacheHitInvalidJSON(t *testing.T) {
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
 	assert.NoError(t, err)
-	assert.ElementsMatch(t, []string{"read", "write"}, permissions)
+	assert.ElementsMatch(t, []string{"read", "write"}, permissions.Permissions())
 
 	repo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
@@ -74,7 +74,7 @@ func TestGetUserPermissions_CacheHitInvalidJSON(t *testing.T) {
 func TestGetUserPermissions_CacheMiss(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -100,7 +100,7 @@ func TestGetUserPermissions_CacheMiss(t *testing.T) {
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
 	assert.NoError(t, err)
-	assert.ElementsMatch(t, []string{"read", "write"}, permissions)
+	assert.ElementsMatch(t, []string{"read", "write"}, permissions.Permissions())
 
 	repo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
@@ -109,7 +109,7 @@ func TestGetUserPermissions_CacheMiss(t *testing.T) {
 func TestGetUserPermissions_CacheError(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -134,7 +134,7 @@ func TestGetUserPermissions_CacheError(t *testing.T) {
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
 	assert.NoError(t, err)
-	assert.Equal(t, []string{"read"}, permissions)
+	assert.Equal(t, []string{"read"}, permissions.Permissions())
 
 	repo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
@@ -143,7 +143,7 @@ func TestGetUserPermissions_CacheError(t *testing.T) {
 func TestGetUserPermissions_RepoError(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -164,7 +164,7 @@ func TestGetUserPermissions_RepoError(t *testing.T) {
 func TestGetUserPermissions_UserWithNoRoles(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -181,7 +181,7 @@ func TestGetUserPermissions_UserWithNoRoles(t *testing.T) {
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
 	assert.NoError(t, err)
-	assert.Empty(t, permissions)
+	assert.Empty(t, permissions.Permissions())
 
 	repo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
@@ -190,7 +190,7 @@ func TestGetUserPermissions_UserWithNoRoles(t *testing.T) {
 func TestGetUserPermissions_UserWithRolesButNoPermissions(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -213,7 +213,7 @@ func TestGetUserPermissions_UserWithRolesButNoPermissions(t *testing.T) {
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
 	assert.NoError(t, err)
-	assert.Empty(t, permissions)
+	assert.Empty(t, permissions.Permissions())
 
 	repo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
@@ -222,7 +222,7 @@ func TestGetUserPermissions_UserWithRolesButNoPermissions(t *testing.T) {
 func TestGetUserPermissions_DuplicatePermissionsAcrossRoles(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -256,8 +256,8 @@ func TestGetUserPermissions_DuplicatePermissionsAcrossRoles(t *testing.T) {
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
 	assert.NoError(t, err)
-	assert.Len(t, permissions, 3) // Should deduplicate 'read'
-	assert.ElementsMatch(t, []string{"read", "write", "edit"}, permissions)
+	assert.Len(t, permissions.Permissions(), 3) // Should deduplicate 'read'
+	assert.ElementsMatch(t, []string{"read", "write", "edit"}, permissions.Permissions())
 
 	repo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
@@ -266,7 +266,7 @@ func TestGetUserPermissions_DuplicatePermissionsAcrossRoles(t *testing.T) {
 func TestGetUserPermissions_CacheSetError(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -290,7 +290,7 @@ func TestGetUserPermissions_CacheSetError(t *testing.T) {
 
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
-	assert.Equal(t, []string{"read"}, permissions)
+	assert.Equal(t, []string{"read"}, permissions.Permissions())
 	assert.EqualError(t, err, "cache set error") // Should return cache error
 
 	repo.AssertExpectations(t)
@@ -300,7 +300,7 @@ func TestGetUserPermissions_CacheSetError(t *testing.T) {
 func TestGetUserPermissions_MultipleRolesWithPermissions(t *testing.T) {
 	repo := &MockRepo{}
 	mockCache := &cache.MockCache{}
-	svc := NewAuthService(repo, mockCache)
+	svc := NewAuthService(repo, mockCache, nil, NewPermissionCache(repo, mockCache))
 
 	userID := uuid.New()
 	cacheKey := "user:" + userID.String() + ":permissions"
@@ -343,13 +343,52 @@ func TestGetUserPermissions_MultipleRolesWithPermissions(t *testing.T) {
 	permissions, err := svc.GetUserPermissions(context.Background(), userID)
 
 	assert.NoError(t, err)
-	assert.Len(t, permissions, 7)
+	assert.Len(t, permissions.Permissions(), 7)
 	assert.ElementsMatch(t, []string{
 		"users.create", "users.update", "users.delete",
 		"posts.moderate", "comments.moderate",
 		"posts.read", "posts.create",
-	}, permissions)
+	}, permissions.Permissions())
 
 	repo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
 }
+
+func TestGetUserPermissions_ResolvesWildcardsAndDenies(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAuthService(repo)
+	userID := uuid.New()
+
+	user := &models.User{
+		ID: userID,
+		Roles: []models.Role{
+			{
+				Name: "admin",
+				Permissions: []models.Permission{
+					{Name: "admin:users:*"},
+					{Name: "!admin:users:unlock"},
+				},
+			},
+			{
+				// Inherited role appended by the repository.
+				Name:        "support",
+				Permissions: []models.Permission{{Name: "admin:kyc:read"}},
+			},
+		},
+	}
+
+	repo.On("GetByIDWithPermissions", mock.Anything, userID).Return(user, nil).Once()
+
+	matcher, err := svc.GetUserPermissions(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.True(t, matcher.Allows("admin:users:read"))
+	assert.True(t, matcher.Allows("admin:kyc:read"))
+	assert.False(t, matcher.Allows("admin:users:unlock"))
+	assert.False(t, matcher.Allows("admin:kyc:review"))
+
+	// Served from the cache and reuses the matcher built for the same list.
+	cached, err := svc.GetUserPermissions(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Same(t, matcher, cached)
+	repo.AssertExpectations(t)
+}
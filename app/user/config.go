@@ -2,21 +2,95 @@ package user
 
 import (
 	"errors"
+	"time"
 )
 
 type Config struct {
 	SymmetricKey string `env:"SYMMETRIC_KEY"`
+
+	// MaxFailedLogins is the number of consecutive failures that locks an account.
+	MaxFailedLogins int `env:"AUTH_MAX_FAILED_LOGINS"`
+	// LockoutDuration is the first lock window; it doubles for every further
+	// MaxFailedLogins failures until MaxLockoutDuration is reached.
+	LockoutDuration    time.Duration `env:"AUTH_LOCKOUT_DURATION"`
+	MaxLockoutDuration time.Duration `env:"AUTH_MAX_LOCKOUT_DURATION"`
+
+	// MaxLoginAttemptsPerIP caps failed logins from a single IP within LoginAttemptWindow.
+	MaxLoginAttemptsPerIP int           `env:"AUTH_MAX_LOGIN_ATTEMPTS_PER_IP"`
+	LoginAttemptWindow    time.Duration `env:"AUTH_LOGIN_ATTEMPT_WINDOW"`
+
+	// SessionTouchInterval throttles last-seen writes for an active session.
+	SessionTouchInterval time.Duration `env:"AUTH_SESSION_TOUCH_INTERVAL"`
+
+	// ImpersonationTokenDuration is the lifetime of read-only impersonation tokens.
+	ImpersonationTokenDuration time.Duration `env:"AUTH_IMPERSONATION_TOKEN_DURATION"`
+
+	// EmailChangeTTL and PhoneChangeTTL bound how long a requested change of
+	// contact details waits for its verification token or OTP.
+	EmailChangeTTL time.Duration `env:"PROFILE_EMAIL_CHANGE_TTL"`
+	PhoneChangeTTL time.Duration `env:"PROFILE_PHONE_CHANGE_TTL"`
+	// MaxVerificationAttempts is the number of wrong codes after which a pending change is dropped.
+	MaxVerificationAttempts int `env:"PROFILE_MAX_VERIFICATION_ATTEMPTS"`
 }
 
 func (c *Config) Validate() error {
 	if c.SymmetricKey == "" {
 		return errors.New("symmetric key must be set")
 	}
+	if c.MaxFailedLogins < 1 {
+		return errors.New("max failed logins must be at least 1")
+	}
+	if c.LockoutDuration <= 0 || c.MaxLockoutDuration < c.LockoutDuration {
+		return errors.New("invalid lockout duration")
+	}
+	if c.MaxLoginAttemptsPerIP < 1 || c.LoginAttemptWindow <= 0 {
+		return errors.New("invalid login attempt limit")
+	}
+	if c.SessionTouchInterval <= 0 {
+		return errors.New("session touch interval must be positive")
+	}
+	if c.ImpersonationTokenDuration <= 0 || c.ImpersonationTokenDuration > time.Hour {
+		return errors.New("impersonation token duration must be between 0 and 1 hour")
+	}
+	if c.EmailChangeTTL <= 0 || c.PhoneChangeTTL <= 0 {
+		return errors.New("contact change TTLs must be positive")
+	}
+	if c.MaxVerificationAttempts < 1 {
+		return errors.New("max verification attempts must be at least 1")
+	}
 	return nil
 }
 
+// LockoutFor returns how long an account should stay locked after the given
+// number of consecutive failed logins. Zero means the account is not locked
+// again: only every MaxFailedLogins-th failure locks it.
+func (c *Config) LockoutFor(failedAttempts int) time.Duration {
+	if failedAttempts < c.MaxFailedLogins || failedAttempts%c.MaxFailedLogins != 0 {
+		return 0
+	}
+
+	duration := c.LockoutDuration
+	for step := failedAttempts/c.MaxFailedLogins - 1; step > 0; step-- {
+		duration *= 2
+		if duration >= c.MaxLockoutDuration {
+			return c.MaxLockoutDuration
+		}
+	}
+	return duration
+}
+
 func GetDefaultConfig() *Config {
 	return &Config{
-		SymmetricKey: "12345678901234567890123456789012",
+		SymmetricKey:               "12345678901234567890123456789012",
+		MaxFailedLogins:            5,
+		LockoutDuration:            15 * time.Minute,
+		MaxLockoutDuration:         24 * time.Hour,
+		MaxLoginAttemptsPerIP:      20,
+		LoginAttemptWindow:         15 * time.Minute,
+		SessionTouchInterval:       5 * time.Minute,
+		ImpersonationTokenDuration: 15 * time.Minute,
+		EmailChangeTTL:             24 * time.Hour,
+		PhoneChangeTTL:             10 * time.Minute,
+		MaxVerificationAttempts:    5,
 	}
 }
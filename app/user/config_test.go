@@ -2,6 +2,7 @@ package user
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,3 +16,51 @@ func TestConfig(t *testing.T) {
 	err = config.Validate()
 	assert.Error(t, err, "Expected error for invalid config")
 }
+
+func TestConfig_InvalidLockoutPolicy(t *testing.T) {
+	config := GetDefaultConfig()
+	config.MaxFailedLogins = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.MaxLockoutDuration = config.LockoutDuration - time.Second
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.LoginAttemptWindow = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.SessionTouchInterval = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.ImpersonationTokenDuration = 2 * time.Hour
+	assert.Error(t, config.Validate())
+}
+
+func TestConfig_InvalidContactChangePolicy(t *testing.T) {
+	config := GetDefaultConfig()
+	config.PhoneChangeTTL = 0
+	assert.Error(t, config.Validate())
+
+	config = GetDefaultConfig()
+	config.MaxVerificationAttempts = 0
+	assert.Error(t, config.Validate())
+}
+
+func TestConfig_LockoutFor(t *testing.T) {
+	config := &Config{
+		MaxFailedLogins:    3,
+		LockoutDuration:    time.Minute,
+		MaxLockoutDuration: 5 * time.Minute,
+	}
+
+	assert.Equal(t, time.Duration(0), config.LockoutFor(2))
+	assert.Equal(t, time.Minute, config.LockoutFor(3))
+	assert.Equal(t, time.Duration(0), config.LockoutFor(4), "a failure after the lock expires does not lock again")
+	assert.Equal(t, time.Duration(0), config.LockoutFor(5))
+	assert.Equal(t, 2*time.Minute, config.LockoutFor(6))
+	assert.Equal(t, 4*time.Minute, config.LockoutFor(9))
+	assert.Equal(t, 5*time.Minute, config.LockoutFor(12))
+}
@@ -13,17 +13,22 @@ import (
 	"github.com/joefazee/neo/internal/validator"
 
 	"github.com/google/uuid"
+	"github.com/joefazee/neo/models"
 )
 
 // RegisterUserRequest represents the request to create a user.
 type RegisterUserRequest struct {
-	FirstName   string    `json:"first_name"`
-	LastName    string    `json:"last_name"`
-	Email       string    `json:"email"`
-	CountryCode string    `json:"country_code"`
-	PhoneNumber string    `json:"phone_number"`
-	Password    string    `json:"password"`
-	CountryID   uuid.UUID `json:"-"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Email       string `json:"email"`
+	CountryCode string `json:"country_code"`
+	PhoneNumber string `json:"phone_number"`
+	Password    string `json:"password"`
+	// ReferralCode is the optional code of the user who referred them
+	ReferralCode string    `json:"referral_code"`
+	CountryID    uuid.UUID `json:"-"`
+	IPAddress    string    `json:"-"`
+	DeviceID     string    `json:"-"`
 }
 
 func (r *RegisterUserRequest) Validate(ctx context.Context,
@@ -33,6 +38,7 @@ func (r *RegisterUserRequest) Validate(ctx context.Context,
 	r.LastName = s.StripHTML(r.LastName)
 	r.Email = s.StripHTML(r.Email)
 	r.PhoneNumber = s.StripHTML(r.PhoneNumber)
+	r.ReferralCode = models.NormalizeReferralCode(r.ReferralCode)
 
 	v.Check(r.FirstName != "", "first_name", "first name is required")
 	v.Check(r.LastName != "", "last_name", "last name is required")
@@ -40,6 +46,7 @@ func (r *RegisterUserRequest) Validate(ctx context.Context,
 	v.Check(validator.MinRunes(r.LastName, 2) && validator.MaxRunes(r.LastName, 150), "last_name", "last name must be between 2 and 150 characters")
 	v.Check(validator.IsEmail(r.Email), "email", "email is invalid")
 	v.Check(r.CountryCode != "", "country_code", "country code is required")
+	v.Check(validator.MaxRunes(r.ReferralCode, 16), "referral_code", "referral code must not be more than 16 characters")
 
 	r.Email = strings.ToLower(r.Email)
 	country, err := countryRepo.GetByCode(ctx, r.CountryCode)
@@ -67,6 +74,9 @@ type LoginRequest struct {
 	Identity    string `json:"identity"`
 	Password    string `json:"password"`
 	CountryCode string `json:"country_code"`
+	IPAddress   string `json:"-"`
+	UserAgent   string `json:"-"`
+	DeviceID    string `json:"-"`
 }
 
 func (r *LoginRequest) Validate(ctx context.Context,
@@ -116,7 +126,9 @@ type Response struct {
 	LastName  string    `json:"last_name"`
 	Email     string    `json:"email,omitempty"`
 	Phone     string    `json:"phone,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
+	// ReferralCode is the code the user shares to refer others
+	ReferralCode string    `json:"referral_code,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 // LoginResponse represents the response for a successful login.
@@ -124,3 +136,27 @@ type LoginResponse struct {
 	AccessToken string   `json:"access_token"`
 	User        Response `json:"user"`
 }
+
+// SessionResponse represents an active login session.
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName string    `json:"device_name"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// ToSessionResponse converts a session model, flagging the one used for the current request.
+func ToSessionResponse(s *models.UserSession, currentTokenID uuid.UUID) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		DeviceName: s.DeviceName,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    currentTokenID != uuid.Nil && s.TokenJTI == currentTokenID.String(),
+	}
+}
@@ -145,6 +145,41 @@ func (suite *UserHandlerTestSuite) TestRegister_ValidationError() {
 	suite.Equal(http.StatusBadRequest, w.Code)
 }
 
+func (suite *UserHandlerTestSuite) TestRegister_InvalidReferralCode() {
+	isActive := true
+	country := &models.Country{ID: uuid.New(), Code: "US", IsActive: &isActive}
+
+	suite.setupRegisterMocks("John", "Doe", "john@example.com", "US", "+1234567890")
+	suite.countryRepo.On("GetByCode", mock.Anything, "US").Return(country, nil)
+	suite.service.On("Register", mock.Anything, mock.MatchedBy(func(req *RegisterUserRequest) bool {
+		return req.ReferralCode == "ABCD2345" && req.DeviceID == "device-1" && req.IPAddress != ""
+	})).Return(nil, models.ErrInvalidReferralCode)
+
+	reqBody := RegisterUserRequest{
+		FirstName:    "John",
+		LastName:     "Doe",
+		Email:        "john@example.com",
+		CountryCode:  "US",
+		PhoneNumber:  "+1234567890",
+		Password:     "password123",
+		ReferralCode: " abcd2345 ",
+	}
+	body, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeviceIDHeader, "device-1")
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	suite.handler.Register(c)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "referral_code")
+	suite.service.AssertExpectations(suite.T())
+}
+
 func (suite *UserHandlerTestSuite) TestRegister_ServiceError() {
 	isActive := true
 	country := &models.Country{ID: uuid.New(), Code: "US", IsActive: &isActive}
@@ -253,6 +288,43 @@ func (suite *UserHandlerTestSuite) TestLogin_ServiceError() {
 	suite.Equal(http.StatusUnauthorized, w.Code)
 }
 
+func (suite *UserHandlerTestSuite) TestLogin_AccountLocked() {
+	suite.sanitizer.On("StripHTML", "john@example.com").Return("john@example.com")
+	suite.service.On("Login", mock.Anything, mock.Anything).Return(nil, models.ErrAccountLocked)
+
+	body, _ := json.Marshal(LoginRequest{Identity: "john@example.com", Password: "password123"})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	suite.handler.Login(c)
+
+	suite.Equal(http.StatusLocked, w.Code)
+}
+
+func (suite *UserHandlerTestSuite) TestLogin_TooManyAttempts() {
+	suite.sanitizer.On("StripHTML", "john@example.com").Return("john@example.com")
+	suite.service.On("Login", mock.Anything, mock.MatchedBy(func(req *LoginRequest) bool {
+		return req.IPAddress == "192.0.2.1"
+	})).Return(nil, models.ErrTooManyLoginAttempts)
+
+	body, _ := json.Marshal(LoginRequest{Identity: "john@example.com", Password: "password123"})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	suite.handler.Login(c)
+
+	suite.Equal(http.StatusTooManyRequests, w.Code)
+	suite.service.AssertExpectations(suite.T())
+}
+
 func (suite *UserHandlerTestSuite) TestRequestPasswordReset_Success() {
 	suite.service.On("RequestPasswordReset", mock.Anything, "john@example.com").Return(nil)
 
@@ -353,17 +425,6 @@ func (suite *UserHandlerTestSuite) TestResetPassword_ServiceError() {
 	suite.Equal(http.StatusInternalServerError, w.Code)
 }
 
-func (suite *UserHandlerTestSuite) TestGetProfile_Success() {
-	w := httptest.NewRecorder()
-	req := httptest.NewRequest("GET", "/profile", http.NoBody)
-	c, _ := gin.CreateTestContext(w)
-	c.Request = req
-
-	suite.handler.GetProfile(c)
-
-	suite.Equal(http.StatusOK, w.Code)
-}
-
 func (suite *UserHandlerTestSuite) setupRegisterMocks(firstName, lastName, email, _, phone string) {
 	suite.sanitizer.On("StripHTML", firstName).Return(firstName)
 	suite.sanitizer.On("StripHTML", lastName).Return(lastName)
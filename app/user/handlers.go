@@ -1,9 +1,11 @@
 package user
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/joefazee/neo/app/countries"
+	"github.com/joefazee/neo/models"
 
 	"github.com/joefazee/neo/internal/logger"
 
@@ -15,6 +17,10 @@ import (
 	"github.com/joefazee/neo/app/api"
 )
 
+// DeviceIDHeader carries the client's device identifier on registration and
+// login, used to spot referral abuse and linked accounts
+const DeviceIDHeader = "X-Device-ID"
+
 // Handler handles HTTP requests for user operations
 type Handler struct {
 	service           Service
@@ -39,6 +45,7 @@ func NewHandler(service Service,
 // @Accept       json
 // @Produce      json
 // @Param        request  body      RegisterUserRequest  true  "User registration details"
+// @Param        X-Device-ID  header  string  false  "Client device identifier"
 // @Success      201      {object}  api.Response{data=Response}
 // @Failure      400      {object}  api.Response{error=api.ErrorInfo}
 // @Failure      500      {object}  api.Response{error=api.ErrorInfo}
@@ -56,8 +63,16 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
+	req.IPAddress = c.ClientIP()
+	req.DeviceID = c.GetHeader(DeviceIDHeader)
+
 	user, err := h.service.Register(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidReferralCode) {
+			v.AddError("referral_code", "referral code does not exist")
+			api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+			return
+		}
 		api.InternalErrorResponse(c, "Failed to register user")
 		return
 	}
@@ -75,6 +90,8 @@ func (h *Handler) Register(c *gin.Context) {
 // @Success      200      {object}  api.Response{data=LoginResponse}
 // @Failure      400      {object}  api.Response{error=api.ErrorInfo}
 // @Failure      401      {object}  api.Response{error=api.ErrorInfo}
+// @Failure      423      {object}  api.Response{error=api.ErrorInfo}
+// @Failure      429      {object}  api.Response{error=api.ErrorInfo}
 // @Failure      500      {object}  api.Response{error=api.ErrorInfo}
 // @Router       /api/v1/users/login [post]
 func (h *Handler) Login(c *gin.Context) {
@@ -90,9 +107,20 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	req.DeviceID = c.GetHeader(DeviceIDHeader)
+
 	resp, err := h.service.Login(c.Request.Context(), &req)
 	if err != nil {
-		api.UnauthorizedResponse(c)
+		switch {
+		case errors.Is(err, models.ErrTooManyLoginAttempts):
+			api.ErrorResponse(c, http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS", "Too many failed login attempts, try again later", nil)
+		case errors.Is(err, models.ErrAccountLocked):
+			api.ErrorResponse(c, http.StatusLocked, "ACCOUNT_LOCKED", "Account is temporarily locked", nil)
+		default:
+			api.UnauthorizedResponse(c)
+		}
 		return
 	}
 
@@ -152,7 +180,3 @@ func (h *Handler) ResetPassword(c *gin.Context) {
 
 	api.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
 }
-
-func (h *Handler) GetProfile(c *gin.Context) {
-	api.SuccessResponse(c, http.StatusOK, "User profile retrieved successfully", nil)
-}
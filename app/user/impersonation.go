@@ -0,0 +1,98 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+)
+
+// ImpersonationService issues read-only tokens that let support staff see the
+// platform as a user does.
+type ImpersonationService interface {
+	// Start issues an impersonation token for userID on behalf of actorID.
+	Start(ctx context.Context, actorID, userID uuid.UUID, req *ImpersonateRequest) (*ImpersonationResponse, error)
+}
+
+type impersonationService struct {
+	repo       Repository
+	tokenMaker security.Maker
+	versions   TokenVersions
+	config     *Config
+}
+
+func NewImpersonationService(repo Repository, tokenMaker security.Maker, versions TokenVersions, config *Config) ImpersonationService {
+	return &impersonationService{repo: repo, tokenMaker: tokenMaker, versions: versions, config: config}
+}
+
+// Start refuses to impersonate yourself, inactive accounts or other admins, so
+// an impersonation token never carries more access than a regular user has.
+func (s *impersonationService) Start(ctx context.Context,
+	actorID, userID uuid.UUID,
+	req *ImpersonateRequest) (*ImpersonationResponse, error) {
+	if actorID == userID {
+		return nil, models.ErrImpersonationDenied
+	}
+
+	user, err := s.repo.GetByIDWithPermissions(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsActive == nil || !*user.IsActive || hasAdminAccess(user) {
+		return nil, models.ErrImpersonationDenied
+	}
+
+	// Tokens follow the user's token version, so revoking their sessions ends impersonations too.
+	version, err := s.versions.Current(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token version: %w", err)
+	}
+
+	token, payload, err := s.tokenMaker.CreateImpersonationToken(userID, actorID, s.config.ImpersonationTokenDuration, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonation token: %w", err)
+	}
+
+	actor := audit.ActorFromContext(ctx)
+	auditLog := models.CreateUserAuditLog(actorID,
+		models.AuditActionImpersonation,
+		models.AuditResourceUser,
+		&userID,
+		nil,
+		models.AuditValues{
+			"reason":     req.Reason,
+			"token_id":   payload.ID.String(),
+			"expires_at": payload.ExpiredAt,
+		},
+		actor.IP, actor.UserAgent)
+	if err := s.repo.CreateAuditLog(ctx, auditLog); err != nil {
+		return nil, fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return &ImpersonationResponse{
+		Token:          token,
+		UserID:         userID,
+		ImpersonatorID: actorID,
+		ExpiresAt:      payload.ExpiredAt,
+		ReadOnly:       true,
+	}, nil
+}
+
+func hasAdminAccess(user *models.User) bool {
+	var names []string
+	for _, role := range user.Roles {
+		for _, permission := range role.Permissions {
+			names = append(names, permission.Name)
+		}
+	}
+	return api.NewPermissionMatcher(names).Allows(PermissionAdminAccess)
+}
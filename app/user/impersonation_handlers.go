@@ -0,0 +1,82 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+type ImpersonationHandler struct {
+	impersonation ImpersonationService
+	sanitizer     sanitizer.HTMLStripperer
+	logger        logger.Logger
+}
+
+func NewImpersonationHandler(impersonation ImpersonationService, s sanitizer.HTMLStripperer, logger logger.Logger) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonation: impersonation, sanitizer: s, logger: logger}
+}
+
+// Impersonate godoc
+// @Summary Impersonate a user
+// @Description Issue a short-lived, read-only token to view the platform as the user. Every request made with it is audited.
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body ImpersonateRequest true "Why the user is being impersonated"
+// @Success 201 {object} api.Response{data=ImpersonationResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid user ID format")
+		return
+	}
+
+	actorID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	req.Validate(v, h.sanitizer)
+	if !v.Valid() {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	response, err := h.impersonation.Start(c.Request.Context(), actorID, userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrRecordNotFound):
+			api.NotFoundResponse(c, "User")
+		case errors.Is(err, models.ErrImpersonationDenied):
+			api.ForbiddenResponse(c, err.Error())
+		default:
+			h.logger.Error(err, logger.Fields{"handler": "Impersonate", "user_id": userID, "actor_id": actorID})
+			api.InternalErrorResponse(c, "Failed to start impersonation")
+		}
+		return
+	}
+
+	api.CreatedResponse(c, "Impersonation started", response)
+}
@@ -0,0 +1,198 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+)
+
+type MockImpersonationService struct {
+	mock.Mock
+}
+
+func (m *MockImpersonationService) Start(ctx context.Context, actorID, userID uuid.UUID, req *ImpersonateRequest) (*ImpersonationResponse, error) {
+	args := m.Called(ctx, actorID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ImpersonationResponse), args.Error(1)
+}
+
+func newTestImpersonationService(repo *MockRepo, maker *security.MockMaker) ImpersonationService {
+	return NewImpersonationService(repo, maker, NewTokenVersions(repo, cache.NewMemoryCache[string]()), GetDefaultConfig())
+}
+
+func impersonationTarget(active bool, permissions ...string) *models.User {
+	role := models.Role{ID: uuid.New(), Name: "member"}
+	for _, name := range permissions {
+		role.Permissions = append(role.Permissions, models.Permission{ID: uuid.New(), Name: name})
+	}
+	return &models.User{ID: uuid.New(), IsActive: &active, Roles: []models.Role{role}}
+}
+
+func TestImpersonationService_Start(t *testing.T) {
+	repo := &MockRepo{}
+	maker := &security.MockMaker{}
+	actorID := uuid.New()
+	target := impersonationTarget(true, "market:read")
+
+	payload, err := security.NewImpersonationPayload(target.ID, actorID, 15*time.Minute, 3)
+	require.NoError(t, err)
+
+	repo.On("GetByIDWithPermissions", mock.Anything, target.ID).Return(target, nil)
+	repo.On("GetTokenVersion", mock.Anything, target.ID).Return(int64(3), nil)
+	maker.On("CreateImpersonationToken", target.ID, actorID, 15*time.Minute, int64(3)).Return("imp-token", payload, nil)
+	repo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l *models.AuditLog) bool {
+		return l.Action == models.AuditActionImpersonation &&
+			*l.UserID == actorID &&
+			*l.ResourceID == target.ID &&
+			l.NewValues["reason"] == "Customer cannot see their bets" &&
+			l.NewValues["token_id"] == payload.ID.String()
+	})).Return(nil)
+
+	response, err := newTestImpersonationService(repo, maker).Start(context.Background(), actorID, target.ID,
+		&ImpersonateRequest{Reason: "Customer cannot see their bets"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "imp-token", response.Token)
+	assert.Equal(t, target.ID, response.UserID)
+	assert.Equal(t, actorID, response.ImpersonatorID)
+	assert.True(t, response.ReadOnly)
+	repo.AssertExpectations(t)
+	maker.AssertExpectations(t)
+}
+
+func TestImpersonationService_Denied(t *testing.T) {
+	actorID := uuid.New()
+	request := &ImpersonateRequest{Reason: "Customer cannot see their bets"}
+
+	t.Run("self", func(t *testing.T) {
+		_, err := newTestImpersonationService(&MockRepo{}, &security.MockMaker{}).
+			Start(context.Background(), actorID, actorID, request)
+		assert.ErrorIs(t, err, models.ErrImpersonationDenied)
+	})
+
+	t.Run("admin target", func(t *testing.T) {
+		repo := &MockRepo{}
+		target := impersonationTarget(true, PermissionAdminAccess)
+		repo.On("GetByIDWithPermissions", mock.Anything, target.ID).Return(target, nil)
+
+		_, err := newTestImpersonationService(repo, &security.MockMaker{}).
+			Start(context.Background(), actorID, target.ID, request)
+		assert.ErrorIs(t, err, models.ErrImpersonationDenied)
+	})
+
+	t.Run("wildcard target", func(t *testing.T) {
+		repo := &MockRepo{}
+		target := impersonationTarget(true, api.PermissionWildcard)
+		repo.On("GetByIDWithPermissions", mock.Anything, target.ID).Return(target, nil)
+
+		_, err := newTestImpersonationService(repo, &security.MockMaker{}).
+			Start(context.Background(), actorID, target.ID, request)
+		assert.ErrorIs(t, err, models.ErrImpersonationDenied)
+	})
+
+	t.Run("inactive target", func(t *testing.T) {
+		repo := &MockRepo{}
+		target := impersonationTarget(false)
+		repo.On("GetByIDWithPermissions", mock.Anything, target.ID).Return(target, nil)
+
+		_, err := newTestImpersonationService(repo, &security.MockMaker{}).
+			Start(context.Background(), actorID, target.ID, request)
+		assert.ErrorIs(t, err, models.ErrImpersonationDenied)
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		repo := &MockRepo{}
+		targetID := uuid.New()
+		repo.On("GetByIDWithPermissions", mock.Anything, targetID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := newTestImpersonationService(repo, &security.MockMaker{}).
+			Start(context.Background(), actorID, targetID, request)
+		assert.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+}
+
+func newImpersonationRouter(service ImpersonationService, actorID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(api.ContextUserIDKey, actorID)
+		c.Next()
+	})
+	handler := NewImpersonationHandler(service, sanitizer.NewHTMLStripper(), logger.NewNullLogger())
+	router.POST("/admin/users/:id/impersonate", handler.Impersonate)
+	return router
+}
+
+func TestImpersonationHandler_Impersonate(t *testing.T) {
+	service := &MockImpersonationService{}
+	actorID := uuid.New()
+	targetID := uuid.New()
+
+	service.On("Start", mock.Anything, actorID, targetID, &ImpersonateRequest{Reason: "Investigating ticket 1234"}).
+		Return(&ImpersonationResponse{Token: "imp-token", UserID: targetID, ImpersonatorID: actorID, ReadOnly: true}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/"+targetID.String()+"/impersonate",
+		strings.NewReader(`{"reason":"Investigating ticket 1234"}`))
+	req.Header.Set("Content-Type", "application/json")
+	newImpersonationRouter(service, actorID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "imp-token", body["data"].(map[string]interface{})["token"])
+}
+
+func TestImpersonationHandler_Errors(t *testing.T) {
+	actorID := uuid.New()
+	targetID := uuid.New()
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		err    error
+		status int
+	}{
+		{name: "invalid id", path: "not-a-uuid", body: `{"reason":"Investigating ticket 1234"}`, status: http.StatusBadRequest},
+		{name: "missing reason", path: targetID.String(), body: `{}`, status: http.StatusBadRequest},
+		{name: "denied", path: targetID.String(), body: `{"reason":"Investigating ticket 1234"}`, err: models.ErrImpersonationDenied, status: http.StatusForbidden},
+		{name: "not found", path: targetID.String(), body: `{"reason":"Investigating ticket 1234"}`, err: models.ErrRecordNotFound, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &MockImpersonationService{}
+			if tt.err != nil {
+				service.On("Start", mock.Anything, actorID, targetID, mock.Anything).Return(nil, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/"+tt.path+"/impersonate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			newImpersonationRouter(service, actorID).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
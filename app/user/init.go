@@ -1,17 +1,28 @@
 package user
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/joefazee/neo/app/api"
 	"github.com/joefazee/neo/app/countries"
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/referral"
+	"github.com/joefazee/neo/internal/cache"
 	"github.com/joefazee/neo/internal/deps"
+	"github.com/joefazee/neo/internal/logger"
 )
 
 const (
-	RepoKey         = "user_repository"
-	ServiceKey      = "user_service"
-	AdminServiceKey = "admin_service"
-	AuthServiceKey  = "auth_service"
+	RepoKey          = "user_repository"
+	ServiceKey       = "user_service"
+	AdminServiceKey  = "admin_service"
+	AuthServiceKey   = "auth_service"
+	SessionsKey      = "session_service"
+	PermissionsKey   = "permission_cache"
+	ImpersonationKey = "impersonation_service"
+	TokenVersionsKey = "token_versions"
+	ProfileKey       = "profile_service"
 )
 
 // MountPublic mounts public user routes (registration, login, password reset)
@@ -27,34 +38,55 @@ func MountPublic(r *gin.RouterGroup, container *deps.Container) {
 
 // MountAuthenticated mounts authenticated user routes (profile management, etc.)
 func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
-	handler := createHandler(container)
+	profileHandler := createProfileHandler(container)
 
 	userGroup := r.Group("/users")
-	userGroup.GET("/profile", handler.GetProfile)
+	userGroup.GET("/profile", profileHandler.GetProfile)
+	userGroup.PATCH("/profile", profileHandler.UpdateProfile)
+	userGroup.PUT("/profile/password", profileHandler.ChangePassword)
+	userGroup.POST("/profile/email", profileHandler.RequestEmailChange)
+	userGroup.POST("/profile/email/verify", profileHandler.ConfirmEmailChange)
+	userGroup.POST("/profile/phone", profileHandler.RequestPhoneChange)
+	userGroup.POST("/profile/phone/verify", profileHandler.ConfirmPhoneChange)
+
+	sessionHandler := createSessionHandler(container)
+	userGroup.GET("/sessions", sessionHandler.ListSessions)
+	userGroup.DELETE("/sessions/:id", sessionHandler.RevokeSession)
 }
 
 func MountAdmin(r *gin.RouterGroup, container *deps.Container) {
 	adminHandler := createAdminHandler(container)
+	impersonationHandler := createImpersonationHandler(container)
 
 	// User management routes
 	adminGroup := r.Group("/admin/users")
-	adminGroup.GET("", api.Can("admin:users:read"), adminHandler.GetUsers)
-	adminGroup.GET("/:id", api.Can("admin:users:read"), adminHandler.GetUserByID) // New route
-	adminGroup.PATCH("/:id/status", api.Can("admin:users:update_status"), adminHandler.UpdateUserStatus)
-	adminGroup.POST("/:id/assign-role", api.Can("admin:users:assign_role"), adminHandler.AssignRoleToUser)
-	adminGroup.DELETE("/:id/roles/:role_id", api.Can("admin:users:remove_role"), adminHandler.RemoveRoleFromUser) // New route
-	adminGroup.POST("/bulk-assign-permissions", api.Can("admin:users:bulk_assign_permission"), adminHandler.BulkAssignPermissions)
+	adminGroup.GET("", api.Can(PermissionUsersRead), adminHandler.GetUsers)
+	adminGroup.GET("/:id", api.Can(PermissionUsersRead), adminHandler.GetUserByID) // New route
+	adminGroup.GET("/:id/permissions", api.Can(PermissionUsersRead), adminHandler.GetUserPermissions)
+	adminGroup.PATCH("/:id/status", api.Can(PermissionUsersUpdateStatus), adminHandler.UpdateUserStatus)
+	adminGroup.POST("/:id/assign-role", api.Can(PermissionUsersAssignRole), adminHandler.AssignRoleToUser)
+	adminGroup.DELETE("/:id/roles/:role_id", api.Can(PermissionUsersRemoveRole), adminHandler.RemoveRoleFromUser) // New route
+	adminGroup.POST("/:id/unlock", api.Can(PermissionUsersUnlock), adminHandler.UnlockUser)
+	adminGroup.POST("/:id/sessions/revoke", api.Can(PermissionUsersRevokeSessions), adminHandler.RevokeSessions)
+	adminGroup.POST("/:id/impersonate", api.Can(PermissionUsersImpersonate), impersonationHandler.Impersonate)
+	adminGroup.POST("/bulk-assign-permissions", api.Can(PermissionUsersBulkAssign), adminHandler.BulkAssignPermissions)
 
 	// Permission management routes
 	permissionGroup := r.Group("/admin/permissions")
-	permissionGroup.POST("", api.Can("admin:permissions:create"), adminHandler.CreatePermission)
+	permissionGroup.GET("", api.Can(PermissionPermissionsRead), adminHandler.ListPermissions)
+	permissionGroup.POST("", api.Can(PermissionPermissionsCreate), adminHandler.CreatePermission)
+	permissionGroup.DELETE("/:id", api.Can(PermissionPermissionsDelete), adminHandler.DeletePermission)
 
 	// Role management routes
 	roleGroup := r.Group("/admin/roles")
-	roleGroup.POST("", api.Can("admin:roles:create"), adminHandler.CreateRole)
-	roleGroup.PUT("/:id", api.Can("admin:roles:update"), adminHandler.UpdateRole)
-	roleGroup.POST("/:id/permissions", api.Can("admin:roles:assign_permissions"), adminHandler.AssignPermissionsToRole)
-	roleGroup.DELETE("/:id/permissions", api.Can("admin:roles:remove_permissions"), adminHandler.RemovePermissionsFromRole)
+	roleGroup.GET("", api.Can(PermissionRolesRead), adminHandler.ListRoles)
+	roleGroup.POST("", api.Can(PermissionRolesCreate), adminHandler.CreateRole)
+	roleGroup.GET("/:id", api.Can(PermissionRolesRead), adminHandler.GetRole)
+	roleGroup.PUT("/:id", api.Can(PermissionRolesUpdate), adminHandler.UpdateRole)
+	roleGroup.DELETE("/:id", api.Can(PermissionRolesDelete), adminHandler.DeleteRole)
+	roleGroup.GET("/:id/members", api.Can(PermissionRolesRead), adminHandler.GetRoleMembers)
+	roleGroup.POST("/:id/permissions", api.Can(PermissionRolesAssignPermission), adminHandler.AssignPermissionsToRole)
+	roleGroup.DELETE("/:id/permissions", api.Can(PermissionRolesRemovePermission), adminHandler.RemovePermissionsFromRole)
 }
 
 // InitRepositories initializes and registers repositories and services for this module
@@ -63,17 +95,53 @@ func InitRepositories(container *deps.Container) {
 	userRepo := NewRepository(container.DB)
 	container.RegisterRepository(RepoKey, userRepo)
 
+	config := GetDefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic("Invalid user configuration: " + err.Error())
+	}
+
 	// Initialize user service
-	userService := NewService(userRepo, container.TokenMaker)
+	sessions := NewSessionService(userRepo, container.Cache, NewLogNotifier(container.Logger), config)
+	container.RegisterService(SessionsKey, sessions)
+
+	counters := sharedCounters(container)
+	loginLimiter := NewLoginLimiter(counters, config)
+	referrals, _ := container.GetService(referral.ServiceKey).(referral.Enroller)
+	signals, _ := container.GetService(fraud.ServiceKey).(fraud.Recorder)
+	userService := NewService(userRepo, container.TokenMaker, config, loginLimiter, sessions, referrals, signals)
 	container.RegisterService(ServiceKey, userService)
 
+	versions := NewTokenVersions(userRepo, container.Cache)
+	container.RegisterService(TokenVersionsKey, versions)
+	profiles := NewProfileService(userRepo, sessions, versions, container.TokenMaker, container.Cache, counters,
+		NewLogVerificationSender(container.Logger), config, signals)
+	container.RegisterService(ProfileKey, profiles)
+
+	// Permission purges from other instances are applied for the life of the process
+	permissions := NewPermissionCache(userRepo, container.Cache)
+	if err := permissions.Listen(context.Background()); err != nil {
+		container.Logger.Error(err, logger.Fields{"component": "permission_cache"})
+	}
+	container.RegisterService(PermissionsKey, permissions)
+
 	// Initialize admin service
-	adminService := NewAdminService(userRepo)
+	adminService := NewAdminService(userRepo, versions, permissions)
 	container.RegisterService(AdminServiceKey, adminService)
+	container.RegisterService(ImpersonationKey, NewImpersonationService(userRepo, container.TokenMaker, versions, config))
 
 	// Auth service will be initialized in main.go since it needs cache
 }
 
+// sharedCounters counts failed logins and verification attempts in the shared
+// cache, so Redis applies the limits across instances. A cache without
+// counters falls back to counting in this process.
+func sharedCounters(container *deps.Container) cache.Counters {
+	if counters, ok := container.Cache.(cache.Counters); ok {
+		return counters
+	}
+	return cache.NewMemoryCache[string]()
+}
+
 // createHandler creates a user handler with all dependencies
 func createHandler(container *deps.Container) *Handler {
 	userService := container.GetService(ServiceKey).(Service)
@@ -82,9 +150,31 @@ func createHandler(container *deps.Container) *Handler {
 	return NewHandler(userService, countryRepo, container.Sanitizer, container.Logger)
 }
 
+// createProfileHandler creates a profile handler with all dependencies
+func createProfileHandler(container *deps.Container) *ProfileHandler {
+	profiles := container.GetService(ProfileKey).(ProfileService)
+	countryRepo := container.GetRepository(countries.CountryRepoKey).(countries.Repository)
+
+	return NewProfileHandler(profiles, countryRepo, container.Sanitizer, container.Logger)
+}
+
+// createSessionHandler creates a session handler with all dependencies
+func createSessionHandler(container *deps.Container) *SessionHandler {
+	sessions := container.GetService(SessionsKey).(SessionService)
+
+	return NewSessionHandler(sessions, container.Logger)
+}
+
 // createAdminHandler creates an admin handler with all dependencies
 func createAdminHandler(container *deps.Container) *AdminHandler {
 	adminService := container.GetService(AdminServiceKey).(AdminService)
 
 	return NewAdminHandler(adminService, container.Sanitizer, container.Logger)
 }
+
+// createImpersonationHandler creates an impersonation handler with all dependencies
+func createImpersonationHandler(container *deps.Container) *ImpersonationHandler {
+	impersonation := container.GetService(ImpersonationKey).(ImpersonationService)
+
+	return NewImpersonationHandler(impersonation, container.Sanitizer, container.Logger)
+}
@@ -36,6 +36,14 @@ func TestMountAuthenticated(t *testing.T) {
 
 	routes := router.Routes()
 	assertRouteExists(t, routes, "GET", "/api/v1/users/profile")
+	assertRouteExists(t, routes, "PATCH", "/api/v1/users/profile")
+	assertRouteExists(t, routes, "PUT", "/api/v1/users/profile/password")
+	assertRouteExists(t, routes, "POST", "/api/v1/users/profile/email")
+	assertRouteExists(t, routes, "POST", "/api/v1/users/profile/email/verify")
+	assertRouteExists(t, routes, "POST", "/api/v1/users/profile/phone")
+	assertRouteExists(t, routes, "POST", "/api/v1/users/profile/phone/verify")
+	assertRouteExists(t, routes, "GET", "/api/v1/users/sessions")
+	assertRouteExists(t, routes, "DELETE", "/api/v1/users/sessions/:id")
 }
 
 func TestMountAdmin(t *testing.T) {
@@ -49,15 +57,25 @@ func TestMountAdmin(t *testing.T) {
 
 	assertRouteExists(t, routes, "GET", "/api/v1/admin/users")
 	assertRouteExists(t, routes, "GET", "/api/v1/admin/users/:id")
+	assertRouteExists(t, routes, "GET", "/api/v1/admin/users/:id/permissions")
 	assertRouteExists(t, routes, "PATCH", "/api/v1/admin/users/:id/status")
 	assertRouteExists(t, routes, "POST", "/api/v1/admin/users/:id/assign-role")
 	assertRouteExists(t, routes, "DELETE", "/api/v1/admin/users/:id/roles/:role_id")
+	assertRouteExists(t, routes, "POST", "/api/v1/admin/users/:id/unlock")
+	assertRouteExists(t, routes, "POST", "/api/v1/admin/users/:id/sessions/revoke")
+	assertRouteExists(t, routes, "POST", "/api/v1/admin/users/:id/impersonate")
 	assertRouteExists(t, routes, "POST", "/api/v1/admin/users/bulk-assign-permissions")
 
+	assertRouteExists(t, routes, "GET", "/api/v1/admin/permissions")
 	assertRouteExists(t, routes, "POST", "/api/v1/admin/permissions")
+	assertRouteExists(t, routes, "DELETE", "/api/v1/admin/permissions/:id")
 
+	assertRouteExists(t, routes, "GET", "/api/v1/admin/roles")
 	assertRouteExists(t, routes, "POST", "/api/v1/admin/roles")
+	assertRouteExists(t, routes, "GET", "/api/v1/admin/roles/:id")
 	assertRouteExists(t, routes, "PUT", "/api/v1/admin/roles/:id")
+	assertRouteExists(t, routes, "DELETE", "/api/v1/admin/roles/:id")
+	assertRouteExists(t, routes, "GET", "/api/v1/admin/roles/:id/members")
 	assertRouteExists(t, routes, "POST", "/api/v1/admin/roles/:id/permissions")
 	assertRouteExists(t, routes, "DELETE", "/api/v1/admin/roles/:id/permissions")
 }
@@ -78,6 +96,22 @@ func TestInitRepositories(t *testing.T) {
 	adminService := container.GetService(AdminServiceKey)
 	assert.NotNil(t, adminService)
 	assert.Implements(t, (*AdminService)(nil), adminService)
+
+	sessions := container.GetService(SessionsKey)
+	assert.NotNil(t, sessions)
+	assert.Implements(t, (*SessionService)(nil), sessions)
+
+	permissions := container.GetService(PermissionsKey)
+	assert.NotNil(t, permissions)
+	assert.Implements(t, (*PermissionCache)(nil), permissions)
+
+	impersonation := container.GetService(ImpersonationKey)
+	assert.NotNil(t, impersonation)
+	assert.Implements(t, (*ImpersonationService)(nil), impersonation)
+
+	profiles := container.GetService(ProfileKey)
+	assert.NotNil(t, profiles)
+	assert.Implements(t, (*ProfileService)(nil), profiles)
 }
 
 func createTestContainer() *deps.Container {
@@ -92,6 +126,9 @@ func createTestContainer() *deps.Container {
 	container.RegisterRepository(countries.CountryRepoKey, &MockCountryRepo{})
 	container.RegisterService(ServiceKey, &MockService{})
 	container.RegisterService(AdminServiceKey, &MockAdminService{})
+	container.RegisterService(SessionsKey, &MockSessionService{})
+	container.RegisterService(ImpersonationKey, &MockImpersonationService{})
+	container.RegisterService(ProfileKey, &MockProfileService{})
 
 	return container
 }
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -18,21 +19,50 @@ type Repository interface {
 
 	GetByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
 	GetUsers(ctx context.Context, filters *AdminUserFilters) ([]models.User, int64, error)
+	GetRoleMembers(ctx context.Context, roleID uuid.UUID, filters *AdminUserFilters) ([]models.User, int64, error)
 	UpdateUserStatus(ctx context.Context, userID uuid.UUID, isActive bool) error
 	BulkAssignPermissions(ctx context.Context, userIDs, permissionIDs []uuid.UUID) error
 
 	CreatePermission(ctx context.Context, permission *models.Permission) error
 	GetPermissionByName(ctx context.Context, name string) (*models.Permission, error)
 	GetPermissionsByNames(ctx context.Context, names []string) ([]models.Permission, error)
+	GetPermissionByID(ctx context.Context, id uuid.UUID) (*models.Permission, error)
+	ListPermissions(ctx context.Context, filters *RBACFilters) ([]models.Permission, int64, error)
+	GetRoleIDsByPermission(ctx context.Context, permissionID uuid.UUID) ([]uuid.UUID, error)
+	DeletePermission(ctx context.Context, id uuid.UUID) error
 
 	CreateRole(ctx context.Context, role *models.Role) error
 	UpdateRole(ctx context.Context, role *models.Role) error
 	GetRoleByID(ctx context.Context, id uuid.UUID) (*models.Role, error)
+	ListRoles(ctx context.Context, filters *RBACFilters) ([]models.Role, int64, error)
+	DeleteRole(ctx context.Context, id uuid.UUID, force bool) ([]uuid.UUID, error)
+	GetRoleAncestorIDs(ctx context.Context, roleIDs ...uuid.UUID) ([]uuid.UUID, error)
+	GetUserIDsByRoles(ctx context.Context, roleIDs ...uuid.UUID) ([]uuid.UUID, error)
 	AssignPermissionsToRole(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error
 	RemovePermissionsFromRole(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error
 
 	GetUserByIDWithRoles(ctx context.Context, id uuid.UUID) (*models.User, error)
 	RemoveRoleFromUser(ctx context.Context, userID, roleID uuid.UUID) error
+
+	UpdateLoginState(ctx context.Context, user *models.User) error
+	// IncrementFailedLogins adds one to the user's failed-login counter in a
+	// single statement and returns the new count.
+	IncrementFailedLogins(ctx context.Context, userID uuid.UUID) (int, error)
+	// LockUntil locks the account until the given time, never shortening a
+	// lock that runs longer.
+	LockUntil(ctx context.Context, userID uuid.UUID, until time.Time) error
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+
+	GetTokenVersion(ctx context.Context, userID uuid.UUID) (int64, error)
+	IncrementTokenVersion(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	CreateSession(ctx context.Context, session *models.UserSession) error
+	GetActiveSessions(ctx context.Context, userID uuid.UUID) ([]models.UserSession, error)
+	GetSessionByID(ctx context.Context, userID, sessionID uuid.UUID) (*models.UserSession, error)
+	HasSessionForDevice(ctx context.Context, userID uuid.UUID, deviceName string) (bool, error)
+	RevokeSession(ctx context.Context, session *models.UserSession, entry *models.TokenBlacklist) error
+	IsTokenBlacklisted(ctx context.Context, jti string) (bool, error)
+	TouchSession(ctx context.Context, userID uuid.UUID, jti string, at time.Time) error
 }
 
 type Service interface {
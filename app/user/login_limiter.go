@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/models"
+)
+
+// LoginLimiter throttles failed login attempts coming from a single client IP.
+type LoginLimiter interface {
+	Allow(ctx context.Context, ip string) error
+	RecordFailure(ctx context.Context, ip string) error
+}
+
+type loginLimiter struct {
+	counters    cache.Counters
+	maxAttempts int
+	window      time.Duration
+}
+
+// NewLoginLimiter creates a cache backed per-IP login limiter. Counting is
+// atomic, so parallel guesses from one IP are all counted.
+func NewLoginLimiter(counters cache.Counters, config *Config) LoginLimiter {
+	return &loginLimiter{
+		counters:    counters,
+		maxAttempts: config.MaxLoginAttemptsPerIP,
+		window:      config.LoginAttemptWindow,
+	}
+}
+
+// Allow returns models.ErrTooManyLoginAttempts once the IP has used up its failures.
+func (l *loginLimiter) Allow(ctx context.Context, ip string) error {
+	if ip == "" {
+		return nil
+	}
+	if l.failures(ctx, ip) >= l.maxAttempts {
+		return models.ErrTooManyLoginAttempts
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt; every failure restarts the window.
+func (l *loginLimiter) RecordFailure(ctx context.Context, ip string) error {
+	if ip == "" {
+		return nil
+	}
+	_, err := l.counters.IncrementCounter(ctx, l.key(ip), l.window)
+	return err
+}
+
+func (l *loginLimiter) failures(ctx context.Context, ip string) int {
+	count, err := l.counters.Counter(ctx, l.key(ip))
+	if err != nil {
+		return 0
+	}
+	return int(count)
+}
+
+func (l *loginLimiter) key(ip string) string {
+	return fmt.Sprintf("login:ip:%s:failed", ip)
+}
@@ -0,0 +1,37 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/models"
+)
+
+func TestLoginLimiter(t *testing.T) {
+	config := GetDefaultConfig()
+	config.MaxLoginAttemptsPerIP = 2
+	limiter := NewLoginLimiter(cache.NewMemoryCache[string](), config)
+	ctx := context.Background()
+
+	assert.NoError(t, limiter.Allow(ctx, "10.0.0.1"))
+	assert.NoError(t, limiter.RecordFailure(ctx, "10.0.0.1"))
+	assert.NoError(t, limiter.Allow(ctx, "10.0.0.1"))
+	assert.NoError(t, limiter.RecordFailure(ctx, "10.0.0.1"))
+	assert.ErrorIs(t, limiter.Allow(ctx, "10.0.0.1"), models.ErrTooManyLoginAttempts)
+
+	// Other addresses are tracked independently
+	assert.NoError(t, limiter.Allow(ctx, "10.0.0.2"))
+}
+
+func TestLoginLimiter_EmptyIP(t *testing.T) {
+	config := GetDefaultConfig()
+	config.MaxLoginAttemptsPerIP = 1
+	limiter := NewLoginLimiter(cache.NewMemoryCache[string](), config)
+	ctx := context.Background()
+
+	assert.NoError(t, limiter.RecordFailure(ctx, ""))
+	assert.NoError(t, limiter.Allow(ctx, ""))
+}
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +34,18 @@ func AuthMiddleware(tokenMaker security.Maker, authService AuthService) gin.Hand
 			return
 		}
 
+		if err := authService.ValidateTokenVersion(c.Request.Context(), payload.UserID, payload.Version); err != nil {
+			api.UnauthorizedResponse(c)
+			c.Abort()
+			return
+		}
+
+		if err := authService.ValidateSession(c.Request.Context(), payload); err != nil {
+			api.UnauthorizedResponse(c)
+			c.Abort()
+			return
+		}
+
 		permissions, err := authService.GetUserPermissions(c.Request.Context(), payload.UserID)
 		if err != nil {
 			api.ForbiddenResponse(c, "Could not retrieve user permissions")
@@ -40,8 +53,24 @@ func AuthMiddleware(tokenMaker security.Maker, authService AuthService) gin.Hand
 			return
 		}
 
-		c.Set("userID", payload.UserID)
-		c.Set("permissions", permissions)
+		c.Set(api.ContextUserIDKey, payload.UserID)
+		c.Set(api.ContextPermissionsKey, permissions)
+		ContextSetToken(c, payload)
+
+		// Impersonation tokens are read-only; the impersonator is kept for the audit trail.
+		if payload.IsImpersonation() {
+			c.Set(api.ContextImpersonatorIDKey, *payload.ImpersonatorID)
+			if !isReadOnlyMethod(c.Request.Method) {
+				api.ForbiddenResponse(c, "Impersonation tokens are read-only")
+				c.Abort()
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
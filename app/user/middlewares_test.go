@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,16 +14,31 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/joefazee/neo/app/api"
 	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
 )
 
 type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+func (m *MockAuthService) GetUserPermissions(ctx context.Context, userID uuid.UUID) (*api.PermissionMatcher, error) {
 	args := m.Called(ctx, userID)
-	return args.Get(0).([]string), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.PermissionMatcher), args.Error(1)
+}
+
+func (m *MockAuthService) ValidateTokenVersion(ctx context.Context, userID uuid.UUID, version int64) error {
+	args := m.Called(ctx, userID, version)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ValidateSession(ctx context.Context, payload *security.Payload) error {
+	args := m.Called(ctx, payload)
+	return args.Error(0)
 }
 
 type AuthMiddlewareTestSuite struct {
@@ -108,7 +124,9 @@ func (suite *AuthMiddlewareTestSuite) TestAuthServiceError() {
 	payload := &security.Payload{UserID: userID}
 
 	suite.tokenMaker.On("VerifyToken", "valid_token").Return(payload, nil)
-	suite.authService.On("GetUserPermissions", mock.Anything, userID).Return([]string{}, errors.New("service error"))
+	suite.authService.On("ValidateTokenVersion", mock.Anything, userID, int64(0)).Return(nil)
+	suite.authService.On("ValidateSession", mock.Anything, payload).Return(nil)
+	suite.authService.On("GetUserPermissions", mock.Anything, userID).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest("GET", "/test", http.NoBody)
@@ -124,9 +142,11 @@ func (suite *AuthMiddlewareTestSuite) TestAuthServiceError() {
 func (suite *AuthMiddlewareTestSuite) TestSuccessful() {
 	userID := uuid.New()
 	payload := &security.Payload{UserID: userID}
-	permissions := []string{"read", "write"}
+	permissions := api.NewPermissionMatcher([]string{"read", "write"})
 
 	suite.tokenMaker.On("VerifyToken", "valid_token").Return(payload, nil)
+	suite.authService.On("ValidateTokenVersion", mock.Anything, userID, int64(0)).Return(nil)
+	suite.authService.On("ValidateSession", mock.Anything, payload).Return(nil)
 	suite.authService.On("GetUserPermissions", mock.Anything, userID).Return(permissions, nil)
 
 	w := httptest.NewRecorder()
@@ -143,9 +163,11 @@ func (suite *AuthMiddlewareTestSuite) TestSuccessful() {
 func (suite *AuthMiddlewareTestSuite) TestContextValues() {
 	userID := uuid.New()
 	payload := &security.Payload{UserID: userID}
-	permissions := []string{"admin"}
+	permissions := api.NewPermissionMatcher([]string{"admin"})
 
 	suite.tokenMaker.On("VerifyToken", "valid_token").Return(payload, nil)
+	suite.authService.On("ValidateTokenVersion", mock.Anything, userID, int64(0)).Return(nil)
+	suite.authService.On("ValidateSession", mock.Anything, payload).Return(nil)
 	suite.authService.On("GetUserPermissions", mock.Anything, userID).Return(permissions, nil)
 
 	suite.router.GET("/context-test", func(c *gin.Context) {
@@ -168,3 +190,72 @@ func (suite *AuthMiddlewareTestSuite) TestContextValues() {
 
 	suite.Equal(http.StatusOK, w.Code)
 }
+
+func (suite *AuthMiddlewareTestSuite) TestRevokedToken() {
+	userID := uuid.New()
+	payload := &security.Payload{UserID: userID, Version: 1}
+
+	suite.tokenMaker.On("VerifyToken", "stale_token").Return(payload, nil)
+	suite.authService.On("ValidateTokenVersion", mock.Anything, userID, int64(1)).Return(models.ErrTokenRevoked)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/test", http.NoBody)
+	req.Header.Set("Authorization", "Bearer stale_token")
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.authService.AssertNotCalled(suite.T(), "GetUserPermissions", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRevokedSession() {
+	userID := uuid.New()
+	payload := &security.Payload{ID: uuid.New(), UserID: userID}
+
+	suite.tokenMaker.On("VerifyToken", "revoked_token").Return(payload, nil)
+	suite.authService.On("ValidateTokenVersion", mock.Anything, userID, int64(0)).Return(nil)
+	suite.authService.On("ValidateSession", mock.Anything, payload).Return(models.ErrTokenRevoked)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/test", http.NoBody)
+	req.Header.Set("Authorization", "Bearer revoked_token")
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.authService.AssertNotCalled(suite.T(), "GetUserPermissions", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestImpersonationTokenIsReadOnly() {
+	userID := uuid.New()
+	adminID := uuid.New()
+	payload, err := security.NewImpersonationPayload(userID, adminID, time.Minute, 0)
+	suite.Require().NoError(err)
+
+	suite.router.POST("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	suite.router.GET("/whoami", func(c *gin.Context) {
+		impersonatorID, ok := api.ImpersonatorIDFromContext(c)
+		suite.True(ok)
+		suite.Equal(adminID, impersonatorID)
+		c.Status(http.StatusOK)
+	})
+
+	suite.tokenMaker.On("VerifyToken", "imp_token").Return(payload, nil)
+	suite.authService.On("ValidateTokenVersion", mock.Anything, userID, int64(0)).Return(nil)
+	suite.authService.On("ValidateSession", mock.Anything, payload).Return(nil)
+	suite.authService.On("GetUserPermissions", mock.Anything, userID).Return(api.NewPermissionMatcher(nil), nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/whoami", http.NoBody)
+	req.Header.Set("Authorization", "Bearer imp_token")
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/test", http.NoBody)
+	req.Header.Set("Authorization", "Bearer imp_token")
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,149 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/cache"
+)
+
+const (
+	// permissionsTTL bounds how long a resolved permission list lives in the shared cache.
+	permissionsTTL = 30 * time.Minute
+	// localPermissionsTTL bounds the in-process copy in case an invalidation
+	// message is lost; pub/sub delivery is at-most-once.
+	localPermissionsTTL = time.Minute
+
+	permissionsInvalidationChannel = "user:permissions:invalidate"
+)
+
+// PermissionCache keeps each user's resolved permission matcher in process and
+// the permission list in the shared cache. Purges are broadcast when the cache
+// backend supports it so every API instance drops its local copy.
+type PermissionCache interface {
+	Get(ctx context.Context, userID uuid.UUID) (*api.PermissionMatcher, bool)
+	Set(ctx context.Context, userID uuid.UUID, permissions []string) (*api.PermissionMatcher, error)
+	// InvalidateUsers purges the given users' permissions on every instance.
+	InvalidateUsers(ctx context.Context, userIDs ...uuid.UUID) error
+	// InvalidateRoles purges every user holding one of the roles, or a role extending them.
+	InvalidateRoles(ctx context.Context, roleIDs ...uuid.UUID) error
+	// Listen applies purges published by other instances until ctx is cancelled.
+	Listen(ctx context.Context) error
+}
+
+type localPermissions struct {
+	matcher   *api.PermissionMatcher
+	expiresAt time.Time
+}
+
+type permissionCache struct {
+	repo  Repository
+	cache cache.Cache[string]
+	local sync.Map // uuid.UUID -> localPermissions
+}
+
+// NewPermissionCache creates a two-level permission cache.
+func NewPermissionCache(repo Repository, c cache.Cache[string]) PermissionCache {
+	return &permissionCache{repo: repo, cache: c}
+}
+
+func (p *permissionCache) Get(ctx context.Context, userID uuid.UUID) (*api.PermissionMatcher, bool) {
+	if entry, ok := p.local.Load(userID); ok {
+		if local := entry.(localPermissions); time.Now().Before(local.expiresAt) {
+			return local.matcher, true
+		}
+		p.local.Delete(userID)
+	}
+
+	cached, err := p.cache.Get(ctx, p.key(userID))
+	if err != nil || cached == "" {
+		return nil, false
+	}
+	var permissions []string
+	if err := json.Unmarshal([]byte(cached), &permissions); err != nil {
+		return nil, false
+	}
+	return p.storeLocal(userID, permissions), true
+}
+
+// Set caches the permission list and returns its matcher. The matcher is
+// returned even when the shared cache write fails.
+func (p *permissionCache) Set(ctx context.Context, userID uuid.UUID, permissions []string) (*api.PermissionMatcher, error) {
+	sort.Strings(permissions)
+	matcher := p.storeLocal(userID, permissions)
+
+	permissionsJSON, err := json.Marshal(permissions)
+	if err != nil {
+		return matcher, err
+	}
+	return matcher, p.cache.Set(ctx, p.key(userID), string(permissionsJSON), permissionsTTL)
+}
+
+func (p *permissionCache) InvalidateUsers(ctx context.Context, userIDs ...uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	for _, userID := range userIDs {
+		p.local.Delete(userID)
+		if err := p.cache.Delete(ctx, p.key(userID)); err != nil {
+			return fmt.Errorf("failed to purge cached permissions: %w", err)
+		}
+	}
+
+	broadcaster, ok := p.cache.(cache.Broadcaster)
+	if !ok {
+		return nil
+	}
+	message, err := json.Marshal(userIDs)
+	if err != nil {
+		return err
+	}
+	if err := broadcaster.Publish(ctx, permissionsInvalidationChannel, string(message)); err != nil {
+		return fmt.Errorf("failed to broadcast permission purge: %w", err)
+	}
+	return nil
+}
+
+func (p *permissionCache) InvalidateRoles(ctx context.Context, roleIDs ...uuid.UUID) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	userIDs, err := p.repo.GetUserIDsByRoles(ctx, roleIDs...)
+	if err != nil {
+		return fmt.Errorf("failed to resolve role members: %w", err)
+	}
+	return p.InvalidateUsers(ctx, userIDs...)
+}
+
+func (p *permissionCache) Listen(ctx context.Context) error {
+	broadcaster, ok := p.cache.(cache.Broadcaster)
+	if !ok {
+		return nil
+	}
+	return broadcaster.Subscribe(ctx, permissionsInvalidationChannel, func(message string) {
+		var userIDs []uuid.UUID
+		if err := json.Unmarshal([]byte(message), &userIDs); err != nil {
+			return
+		}
+		for _, userID := range userIDs {
+			p.local.Delete(userID)
+		}
+	})
+}
+
+func (p *permissionCache) storeLocal(userID uuid.UUID, permissions []string) *api.PermissionMatcher {
+	matcher := api.NewPermissionMatcher(permissions)
+	p.local.Store(userID, localPermissions{matcher: matcher, expiresAt: time.Now().Add(localPermissionsTTL)})
+	return matcher
+}
+
+func (p *permissionCache) key(userID uuid.UUID) string {
+	return fmt.Sprintf("user:%s:permissions", userID)
+}
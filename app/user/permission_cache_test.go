@@ -0,0 +1,137 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/models"
+)
+
+type MockPermissionCache struct {
+	mock.Mock
+}
+
+func (m *MockPermissionCache) Get(ctx context.Context, userID uuid.UUID) (*api.PermissionMatcher, bool) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1)
+	}
+	return args.Get(0).(*api.PermissionMatcher), args.Bool(1)
+}
+
+func (m *MockPermissionCache) Set(ctx context.Context, userID uuid.UUID, permissions []string) (*api.PermissionMatcher, error) {
+	args := m.Called(ctx, userID, permissions)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.PermissionMatcher), args.Error(1)
+}
+
+func (m *MockPermissionCache) InvalidateUsers(ctx context.Context, userIDs ...uuid.UUID) error {
+	args := m.Called(ctx, userIDs)
+	return args.Error(0)
+}
+
+func (m *MockPermissionCache) InvalidateRoles(ctx context.Context, roleIDs ...uuid.UUID) error {
+	args := m.Called(ctx, roleIDs)
+	return args.Error(0)
+}
+
+func (m *MockPermissionCache) Listen(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func newTestAuthService(repo *MockRepo) AuthService {
+	c := cache.NewMemoryCache[string]()
+	return NewAuthService(repo, c, nil, NewPermissionCache(repo, c))
+}
+
+func TestPermissionCache_SetAndGet(t *testing.T) {
+	c := cache.NewMemoryCache[string]()
+	permissions := NewPermissionCache(&MockRepo{}, c)
+	userID := uuid.New()
+
+	_, ok := permissions.Get(context.Background(), userID)
+	assert.False(t, ok)
+
+	matcher, err := permissions.Set(context.Background(), userID, []string{"b", "a"})
+	assert.NoError(t, err)
+
+	cached, ok := permissions.Get(context.Background(), userID)
+	assert.True(t, ok)
+	assert.Same(t, matcher, cached)
+
+	raw, err := c.Get(context.Background(), "user:"+userID.String()+":permissions")
+	assert.NoError(t, err)
+	assert.Equal(t, `["a","b"]`, raw)
+}
+
+func TestPermissionCache_InvalidateUsersPropagates(t *testing.T) {
+	// Two instances sharing one backend; the backend's pub/sub stands in for Redis.
+	shared := cache.NewMemoryCache[string]()
+	local := NewPermissionCache(&MockRepo{}, shared)
+	remote := NewPermissionCache(&MockRepo{}, shared)
+	assert.NoError(t, remote.Listen(context.Background()))
+
+	userID := uuid.New()
+	_, _ = remote.Set(context.Background(), userID, []string{"admin:users:read"})
+
+	assert.NoError(t, local.InvalidateUsers(context.Background(), userID))
+
+	_, ok := remote.Get(context.Background(), userID)
+	assert.False(t, ok, "remote instance must drop its in-process copy")
+}
+
+func TestPermissionCache_InvalidateRoles(t *testing.T) {
+	repo := &MockRepo{}
+	c := cache.NewMemoryCache[string]()
+	permissions := NewPermissionCache(repo, c)
+	roleID := uuid.New()
+	member := uuid.New()
+	other := uuid.New()
+
+	repo.On("GetUserIDsByRoles", mock.Anything, []uuid.UUID{roleID}).Return([]uuid.UUID{member}, nil)
+
+	_, _ = permissions.Set(context.Background(), member, []string{"admin:*"})
+	_, _ = permissions.Set(context.Background(), other, []string{"admin:*"})
+
+	assert.NoError(t, permissions.InvalidateRoles(context.Background(), roleID))
+
+	_, ok := permissions.Get(context.Background(), member)
+	assert.False(t, ok)
+	_, ok = permissions.Get(context.Background(), other)
+	assert.True(t, ok)
+}
+
+func TestAdminService_RoleChangesInvalidatePermissions(t *testing.T) {
+	repo := &MockRepo{}
+	permissions := &MockPermissionCache{}
+	svc := NewAdminService(repo, NewTokenVersions(repo, cache.NewMemoryCache[string]()), permissions)
+	roleID := uuid.New()
+	userID := uuid.New()
+
+	repo.On("GetPermissionsByNames", mock.Anything, []string{"admin:users:read"}).
+		Return([]models.Permission{{ID: uuid.New(), Name: "admin:users:read"}}, nil)
+	repo.On("RemovePermissionsFromRole", mock.Anything, roleID, mock.Anything).Return(nil)
+	repo.On("GetRoleByID", mock.Anything, roleID).Return(&models.Role{ID: roleID}, nil)
+	repo.On("GetUserByIDWithRoles", mock.Anything, userID).Return(&models.User{ID: userID}, nil)
+	repo.On("AssignRole", mock.Anything, userID, roleID).Return(nil)
+	repo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+	permissions.On("InvalidateRoles", mock.Anything, []uuid.UUID{roleID}).Return(nil).Once()
+	permissions.On("InvalidateUsers", mock.Anything, []uuid.UUID{userID}).Return(nil).Once()
+
+	_, err := svc.RemovePermissionsFromRole(context.Background(), roleID, &RemovePermissionsRequest{
+		PermissionCodes: []string{"admin:users:read"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, svc.AssignRole(context.Background(), userID, roleID))
+
+	permissions.AssertExpectations(t)
+}
@@ -0,0 +1,26 @@
+package user
+
+import "github.com/joefazee/neo/app/api"
+
+// Permissions required by the admin routes of this module.
+var (
+	PermissionAdminAccess = api.DefinePermission("admin", "Access the admin API")
+
+	PermissionUsersRead             = api.DefinePermission("admin:users:read", "List and view users")
+	PermissionUsersUpdateStatus     = api.DefinePermission("admin:users:update_status", "Activate or deactivate users")
+	PermissionUsersAssignRole       = api.DefinePermission("admin:users:assign_role", "Assign a role to a user")
+	PermissionUsersRemoveRole       = api.DefinePermission("admin:users:remove_role", "Remove a role from a user")
+	PermissionUsersUnlock           = api.DefinePermission("admin:users:unlock", "Unlock accounts locked after failed logins")
+	PermissionUsersRevokeSessions   = api.DefinePermission("admin:users:revoke_sessions", "Revoke every session of a user")
+	PermissionUsersBulkAssign       = api.DefinePermission("admin:users:bulk_assign_permission", "Grant permissions to many users")
+	PermissionUsersImpersonate      = api.DefinePermission("admin:users:impersonate", "View the platform as a user with a read-only token")
+	PermissionPermissionsRead       = api.DefinePermission("admin:permissions:read", "List permissions")
+	PermissionPermissionsCreate     = api.DefinePermission("admin:permissions:create", "Create permissions")
+	PermissionPermissionsDelete     = api.DefinePermission("admin:permissions:delete", "Delete permissions and revoke them from every role")
+	PermissionRolesRead             = api.DefinePermission("admin:roles:read", "List and view roles and their members")
+	PermissionRolesCreate           = api.DefinePermission("admin:roles:create", "Create roles")
+	PermissionRolesUpdate           = api.DefinePermission("admin:roles:update", "Update roles")
+	PermissionRolesDelete           = api.DefinePermission("admin:roles:delete", "Delete roles")
+	PermissionRolesAssignPermission = api.DefinePermission("admin:roles:assign_permissions", "Grant permissions to a role")
+	PermissionRolesRemovePermission = api.DefinePermission("admin:roles:remove_permissions", "Revoke permissions from a role")
+)
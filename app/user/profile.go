@@ -0,0 +1,477 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+	"gorm.io/gorm"
+)
+
+// VerificationSender delivers the secret that confirms a change of contact details.
+type VerificationSender interface {
+	SendEmailVerification(ctx context.Context, user *models.User, email, token string) error
+	SendPhoneOTP(ctx context.Context, user *models.User, phone, code string) error
+}
+
+// ProfileService lets users manage their own profile and credentials.
+// currentTokenID identifies the session making the request; email and phone
+// changes end every other session. A password change ends every session and
+// hands the caller a token for a new one.
+type ProfileService interface {
+	GetProfile(ctx context.Context, userID uuid.UUID) (*ProfileResponse, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, req *UpdateProfileRequest) (*ProfileResponse, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, req *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, req *ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, userID, currentTokenID uuid.UUID, req *ConfirmEmailChangeRequest) (*ProfileResponse, error)
+	RequestPhoneChange(ctx context.Context, userID uuid.UUID, req *ChangePhoneRequest) error
+	ConfirmPhoneChange(ctx context.Context, userID, currentTokenID uuid.UUID, req *ConfirmPhoneChangeRequest) (*ProfileResponse, error)
+}
+
+// pendingChange is a requested email or phone change awaiting its secret.
+type pendingChange struct {
+	SecretHash string    `json:"secret_hash"`
+	Value      string    `json:"value"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+const (
+	changeKindEmail = "email"
+	changeKindPhone = "phone"
+)
+
+type profileService struct {
+	repo       Repository
+	sessions   SessionService
+	versions   TokenVersions
+	tokenMaker security.Maker
+	cache      cache.Cache[string]
+	counters   cache.Counters
+	sender     VerificationSender
+	config     *Config
+	signals    fraud.Recorder
+}
+
+// NewProfileService creates a profile service. Pending contact changes are kept in the cache
+// and wrong secrets for them are counted in counters.
+// signals, which may be nil, is told about verified phone numbers.
+func NewProfileService(repo Repository,
+	sessions SessionService,
+	versions TokenVersions,
+	tokenMaker security.Maker,
+	c cache.Cache[string],
+	counters cache.Counters,
+	sender VerificationSender,
+	config *Config,
+	signals fraud.Recorder) ProfileService {
+	return &profileService{
+		repo:       repo,
+		sessions:   sessions,
+		versions:   versions,
+		tokenMaker: tokenMaker,
+		cache:      c,
+		counters:   counters,
+		sender:     sender,
+		config:     config,
+		signals:    signals,
+	}
+}
+
+func (s *profileService) GetProfile(ctx context.Context, userID uuid.UUID) (*ProfileResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return ToProfileResponse(user), nil
+}
+
+func (s *profileService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *UpdateProfileRequest) (*ProfileResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Metadata == nil {
+		user.Metadata = &models.UserMetadata{}
+	}
+
+	oldValues, newValues := models.AuditValues{}, models.AuditValues{}
+	if req.FirstName != nil && *req.FirstName != user.FirstName {
+		oldValues["first_name"], newValues["first_name"] = user.FirstName, *req.FirstName
+		user.FirstName = *req.FirstName
+	}
+	if req.LastName != nil && *req.LastName != user.LastName {
+		oldValues["last_name"], newValues["last_name"] = user.LastName, *req.LastName
+		user.LastName = *req.LastName
+	}
+	if req.BirthDate != nil && (user.DateOfBirth == nil || !user.DateOfBirth.Equal(*req.BirthDate)) {
+		oldValues["date_of_birth"], newValues["date_of_birth"] = user.DateOfBirth, *req.BirthDate
+		user.DateOfBirth = req.BirthDate
+	}
+	if req.PreferredLang != nil && *req.PreferredLang != user.Metadata.PreferredLang {
+		oldValues["preferred_lang"], newValues["preferred_lang"] = user.Metadata.PreferredLang, *req.PreferredLang
+		user.Metadata.PreferredLang = *req.PreferredLang
+	}
+	if req.NewsletterSubscribed != nil && *req.NewsletterSubscribed != user.Metadata.NewsletterSubs {
+		oldValues["newsletter_subscribed"], newValues["newsletter_subscribed"] = user.Metadata.NewsletterSubs, *req.NewsletterSubscribed
+		user.Metadata.NewsletterSubs = *req.NewsletterSubscribed
+	}
+	if req.HideFromLeaderboards != nil && *req.HideFromLeaderboards != user.Metadata.HideFromLeaderboards {
+		oldValues["hide_from_leaderboards"], newValues["hide_from_leaderboards"] = user.Metadata.HideFromLeaderboards, *req.HideFromLeaderboards
+		user.Metadata.HideFromLeaderboards = *req.HideFromLeaderboards
+	}
+
+	if len(newValues) == 0 {
+		return ToProfileResponse(user), nil
+	}
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+	if err := s.audit(ctx, user.ID, models.AuditActionProfileUpdated, oldValues, newValues); err != nil {
+		return nil, err
+	}
+	return ToProfileResponse(user), nil
+}
+
+// ChangePassword sets a new password once the current one is confirmed. Every
+// token issued under the old password is revoked, including the caller's, so
+// the caller gets a fresh token for a new session.
+func (s *profileService) ChangePassword(ctx context.Context, userID uuid.UUID, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.CheckPassword(req.CurrentPassword) {
+		return nil, models.ErrInvalidPassword
+	}
+
+	if err := changePassword(ctx, s.repo, s.versions, user, req.NewPassword); err != nil {
+		return nil, err
+	}
+	// The version bump already rejects the old tokens; ending the sessions
+	// keeps the session list in step with it
+	if err := s.sessions.RevokeOthers(ctx, user.ID, uuid.Nil); err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	version, err := s.versions.Current(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token version: %w", err)
+	}
+	accessToken, payload, err := s.tokenMaker.CreateToken(user.ID, accessTokenDuration, version, security.TokenScopeAccess)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.sessions.Start(ctx, user, payload, req.IPAddress, req.UserAgent); err != nil {
+		return nil, err
+	}
+
+	if err := s.audit(ctx, user.ID, models.AuditActionPasswordChanged, nil, nil); err != nil {
+		return nil, err
+	}
+	return &ChangePasswordResponse{AccessToken: accessToken}, nil
+}
+
+// RequestEmailChange sends a verification token to the new address. The
+// address is not changed until ConfirmEmailChange.
+func (s *profileService) RequestEmailChange(ctx context.Context, userID uuid.UUID, req *ChangeEmailRequest) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.CheckPassword(req.Password) {
+		return models.ErrInvalidPassword
+	}
+	if err := s.ensureEmailFree(ctx, userID, req.Email); err != nil {
+		return err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	if err := s.savePending(ctx, userID, changeKindEmail, token, req.Email, s.config.EmailChangeTTL); err != nil {
+		return err
+	}
+	return s.sender.SendEmailVerification(ctx, user, req.Email, token)
+}
+
+// ConfirmEmailChange applies a pending email change. The new address counts as verified.
+func (s *profileService) ConfirmEmailChange(ctx context.Context,
+	userID, currentTokenID uuid.UUID,
+	req *ConfirmEmailChangeRequest) (*ProfileResponse, error) {
+	email, err := s.consumePending(ctx, userID, changeKindEmail, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureEmailFree(ctx, userID, email); err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldEmail := user.Email
+	now := time.Now()
+	user.Email = email
+	user.EmailVerifiedAt = &now
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update email: %w", err)
+	}
+
+	err = s.afterSensitiveChange(ctx, user.ID, currentTokenID, models.AuditActionEmailChanged,
+		models.AuditValues{"email": oldEmail}, models.AuditValues{"email": email})
+	if err != nil {
+		return nil, err
+	}
+	return ToProfileResponse(user), nil
+}
+
+// RequestPhoneChange sends an OTP to the new number. The number is not
+// changed until ConfirmPhoneChange.
+func (s *profileService) RequestPhoneChange(ctx context.Context, userID uuid.UUID, req *ChangePhoneRequest) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.ensurePhoneFree(ctx, userID, req.PhoneNumber); err != nil {
+		return err
+	}
+
+	code, err := randomOTP()
+	if err != nil {
+		return err
+	}
+	if err := s.savePending(ctx, userID, changeKindPhone, code, req.PhoneNumber, s.config.PhoneChangeTTL); err != nil {
+		return err
+	}
+	return s.sender.SendPhoneOTP(ctx, user, req.PhoneNumber, code)
+}
+
+// ConfirmPhoneChange applies a pending phone change. The new number counts as verified.
+func (s *profileService) ConfirmPhoneChange(ctx context.Context,
+	userID, currentTokenID uuid.UUID,
+	req *ConfirmPhoneChangeRequest) (*ProfileResponse, error) {
+	phone, err := s.consumePending(ctx, userID, changeKindPhone, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensurePhoneFree(ctx, userID, phone); err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldPhone := user.Phone
+	now := time.Now()
+	user.Phone = phone
+	user.PhoneVerifiedAt = &now
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update phone: %w", err)
+	}
+
+	err = s.afterSensitiveChange(ctx, user.ID, currentTokenID, models.AuditActionPhoneChanged,
+		models.AuditValues{"phone": oldPhone}, models.AuditValues{"phone": phone})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.signals != nil {
+		s.signals.RecordSignals(ctx, user.ID, fraud.Signal{Type: models.AccountSignalPhone, Value: phone})
+	}
+	return ToProfileResponse(user), nil
+}
+
+func (s *profileService) getUser(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *profileService) ensureEmailFree(ctx context.Context, userID uuid.UUID, email string) error {
+	existing, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != userID {
+		return models.ErrEmailTaken
+	}
+	return nil
+}
+
+func (s *profileService) ensurePhoneFree(ctx context.Context, userID uuid.UUID, phone string) error {
+	existing, err := s.repo.GetByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != userID {
+		return models.ErrPhoneTaken
+	}
+	return nil
+}
+
+// afterSensitiveChange logs out the user's other devices and audits the change.
+func (s *profileService) afterSensitiveChange(ctx context.Context,
+	userID, currentTokenID uuid.UUID,
+	action string,
+	oldValues, newValues models.AuditValues) error {
+	if err := s.sessions.RevokeOthers(ctx, userID, currentTokenID); err != nil {
+		return fmt.Errorf("failed to revoke other sessions: %w", err)
+	}
+	return s.audit(ctx, userID, action, oldValues, newValues)
+}
+
+// changePassword stores a new password and bumps the token version, so every
+// token issued under the old password stops working. Every password change
+// goes through here.
+func changePassword(ctx context.Context, repo Repository, versions TokenVersions, user *models.User, password string) error {
+	if err := user.SetPassword(password); err != nil {
+		return err
+	}
+	if err := repo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return versions.Revoke(ctx, user.ID)
+}
+
+func (s *profileService) audit(ctx context.Context, userID uuid.UUID, action string, oldValues, newValues models.AuditValues) error {
+	auditLog := audit.NewLog(ctx, action, models.AuditResourceUser, &userID, oldValues, newValues)
+	if err := s.repo.CreateAuditLog(ctx, auditLog); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// savePending stores a change awaiting confirmation, replacing any earlier one of the same kind.
+func (s *profileService) savePending(ctx context.Context, userID uuid.UUID, kind, secret, value string, ttl time.Duration) error {
+	pending := pendingChange{SecretHash: hashSecret(secret), Value: value, ExpiresAt: time.Now().Add(ttl)}
+	return s.storePending(ctx, s.pendingKey(userID, kind), &pending)
+}
+
+// consumePending returns the value of a pending change if secret matches.
+// Every try is counted atomically before the secret is compared, so parallel
+// guesses cannot get past MaxVerificationAttempts; the change is dropped once
+// the limit is reached.
+func (s *profileService) consumePending(ctx context.Context, userID uuid.UUID, kind, secret string) (string, error) {
+	key := s.pendingKey(userID, kind)
+	raw, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return "", models.ErrInvalidVerificationCode
+	}
+
+	var pending pendingChange
+	if err := json.Unmarshal([]byte(raw), &pending); err != nil || !time.Now().Before(pending.ExpiresAt) {
+		_ = s.cache.Delete(ctx, key)
+		return "", models.ErrInvalidVerificationCode
+	}
+
+	attempts, err := s.counters.IncrementCounter(ctx, attemptsKey(key, &pending), time.Until(pending.ExpiresAt))
+	if err != nil {
+		return "", fmt.Errorf("failed to count verification attempt: %w", err)
+	}
+	if attempts > int64(s.config.MaxVerificationAttempts) {
+		_ = s.cache.Delete(ctx, key)
+		return "", models.ErrInvalidVerificationCode
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(pending.SecretHash)) != 1 {
+		if attempts >= int64(s.config.MaxVerificationAttempts) {
+			_ = s.cache.Delete(ctx, key)
+		}
+		return "", models.ErrInvalidVerificationCode
+	}
+
+	_ = s.cache.Delete(ctx, key)
+	return pending.Value, nil
+}
+
+func (s *profileService) storePending(ctx context.Context, key string, pending *pendingChange) error {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	if err := s.cache.Set(ctx, key, string(data), time.Until(pending.ExpiresAt)); err != nil {
+		return fmt.Errorf("failed to store pending change: %w", err)
+	}
+	return nil
+}
+
+func (s *profileService) pendingKey(userID uuid.UUID, kind string) string {
+	return fmt.Sprintf("profile:%s:%s_change", userID, kind)
+}
+
+// attemptsKey is where tries at a pending change are counted. It is tied to
+// the change's secret, so a newly requested change starts from zero.
+func attemptsKey(key string, pending *pendingChange) string {
+	return key + ":attempts:" + pending.SecretHash
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken returns a URL safe email verification token.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// randomOTP returns a six digit one-time code.
+func randomOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// NewLogVerificationSender creates a VerificationSender that writes to the
+// logger at debug level until an email and SMS provider is wired in. It logs
+// that a code was sent but never the code, which would let anyone reading the
+// logs confirm the change.
+func NewLogVerificationSender(lg logger.Logger) VerificationSender {
+	return &logNotifier{logger: lg}
+}
+
+func (n *logNotifier) SendEmailVerification(_ context.Context, user *models.User, email, _ string) error {
+	n.logger.Debug("email change verification", logger.Fields{"user_id": user.ID, "email": email})
+	return nil
+}
+
+func (n *logNotifier) SendPhoneOTP(_ context.Context, user *models.User, phone, _ string) error {
+	n.logger.Debug("phone change verification", logger.Fields{"user_id": user.ID, "phone": phone})
+	return nil
+}
@@ -0,0 +1,210 @@
+package user
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/countries"
+	"github.com/joefazee/neo/internal/formatter"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// MinimumAge is the youngest a user may be according to their date of birth.
+const MinimumAge = 18
+
+// DateOfBirthLayout is the format of dates of birth in requests and responses.
+const DateOfBirthLayout = "2006-01-02"
+
+var (
+	langRX = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+	otpRX  = regexp.MustCompile(`^[0-9]{6}$`)
+)
+
+// UpdateProfileRequest changes the user's own profile. Omitted fields are left as they are.
+type UpdateProfileRequest struct {
+	FirstName            *string `json:"first_name"`
+	LastName             *string `json:"last_name"`
+	DateOfBirth          *string `json:"date_of_birth"`
+	PreferredLang        *string `json:"preferred_lang"`
+	NewsletterSubscribed *bool   `json:"newsletter_subscribed"`
+	HideFromLeaderboards *bool   `json:"hide_from_leaderboards"`
+	// BirthDate is DateOfBirth parsed by Validate
+	BirthDate *time.Time `json:"-"`
+}
+
+// Validate sanitizes and checks the fields that are set.
+func (r *UpdateProfileRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer, now time.Time) bool {
+	if r.FirstName != nil {
+		*r.FirstName = s.StripHTML(*r.FirstName)
+		v.Check(validator.MinRunes(*r.FirstName, 2) && validator.MaxRunes(*r.FirstName, 100), "first_name", "first name must be between 2 and 100 characters")
+	}
+	if r.LastName != nil {
+		*r.LastName = s.StripHTML(*r.LastName)
+		v.Check(validator.MinRunes(*r.LastName, 2) && validator.MaxRunes(*r.LastName, 100), "last_name", "last name must be between 2 and 100 characters")
+	}
+	if r.DateOfBirth != nil {
+		dob, err := time.Parse(DateOfBirthLayout, *r.DateOfBirth)
+		if err != nil {
+			v.AddError("date_of_birth", "date of birth must be in YYYY-MM-DD format")
+		} else {
+			v.Check(!dob.AddDate(MinimumAge, 0, 0).After(now), "date_of_birth", "you must be at least 18 years old")
+			r.BirthDate = &dob
+		}
+	}
+	if r.PreferredLang != nil {
+		*r.PreferredLang = s.StripHTML(*r.PreferredLang)
+		v.Check(validator.Matches(*r.PreferredLang, langRX), "preferred_lang", "preferred language must be a language tag such as en or pt-BR")
+	}
+	v.Check(r.FirstName != nil || r.LastName != nil || r.DateOfBirth != nil ||
+		r.PreferredLang != nil || r.NewsletterSubscribed != nil || r.HideFromLeaderboards != nil,
+		"profile", "at least one field must be provided")
+
+	return v.Valid()
+}
+
+// ChangePasswordRequest changes the password; the current one must be supplied.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+	IPAddress       string `json:"-"`
+	UserAgent       string `json:"-"`
+}
+
+// ChangePasswordResponse carries the token for the session that changed the
+// password; every token issued before the change is revoked.
+type ChangePasswordResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// Validate checks the passwords.
+func (r *ChangePasswordRequest) Validate(v *validator.Validator) bool {
+	v.Check(r.CurrentPassword != "", "current_password", "current password is required")
+	v.Check(validator.MinRunes(r.NewPassword, 8), "new_password", "new password must be at least 8 characters long")
+	v.Check(validator.MaxRunes(r.NewPassword, 72), "new_password", "new password must not be more than 72 characters")
+	v.Check(r.NewPassword != r.CurrentPassword, "new_password", "new password must differ from the current one")
+	return v.Valid()
+}
+
+// ChangeEmailRequest starts a change of email address. The address only
+// changes once the token sent to it is confirmed.
+type ChangeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Validate sanitizes and checks the new address.
+func (r *ChangeEmailRequest) Validate(v *validator.Validator, s sanitizer.HTMLStripperer) bool {
+	r.Email = strings.ToLower(strings.TrimSpace(s.StripHTML(r.Email)))
+
+	v.Check(validator.IsEmail(r.Email), "email", "email is invalid")
+	v.Check(r.Password != "", "password", "password is required")
+	return v.Valid()
+}
+
+// ConfirmEmailChangeRequest confirms a change of email address.
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token"`
+}
+
+// Validate checks the token is present.
+func (r *ConfirmEmailChangeRequest) Validate(v *validator.Validator) bool {
+	v.Check(strings.TrimSpace(r.Token) != "", "token", "token is required")
+	return v.Valid()
+}
+
+// ChangePhoneRequest starts a change of phone number. The number only
+// changes once the OTP sent to it is confirmed.
+type ChangePhoneRequest struct {
+	PhoneNumber string `json:"phone_number"`
+	CountryCode string `json:"country_code"`
+}
+
+// Validate sanitizes the number and formats it for the given country.
+func (r *ChangePhoneRequest) Validate(ctx context.Context,
+	v *validator.Validator,
+	countryRepo countries.Repository, s sanitizer.HTMLStripperer) bool {
+	r.PhoneNumber = s.StripHTML(r.PhoneNumber)
+	r.CountryCode = s.StripHTML(r.CountryCode)
+
+	v.Check(r.PhoneNumber != "", "phone_number", "phone number is required")
+	v.Check(r.CountryCode != "", "country_code", "country code is required")
+	if !v.Valid() {
+		return false
+	}
+
+	country, err := countryRepo.GetByCode(ctx, r.CountryCode)
+	if err != nil || country == nil {
+		v.AddError("country_code", "invalid country code")
+		return false
+	}
+	v.Check(*country.IsActive, "country_code", "country is not active")
+
+	r.PhoneNumber, err = formatter.FormatPhone(r.PhoneNumber, r.CountryCode)
+	if err != nil || r.PhoneNumber == "" {
+		v.AddError("phone_number", "invalid phone number")
+	}
+	return v.Valid()
+}
+
+// ConfirmPhoneChangeRequest confirms a change of phone number with the OTP.
+type ConfirmPhoneChangeRequest struct {
+	Code string `json:"code"`
+}
+
+// Validate checks the code is six digits.
+func (r *ConfirmPhoneChangeRequest) Validate(v *validator.Validator) bool {
+	v.Check(validator.Matches(r.Code, otpRX), "code", "code must be 6 digits")
+	return v.Valid()
+}
+
+// ProfileResponse represents the authenticated user's own profile.
+type ProfileResponse struct {
+	ID                   uuid.UUID        `json:"id"`
+	Email                string           `json:"email"`
+	EmailVerified        bool             `json:"email_verified"`
+	Phone                string           `json:"phone"`
+	PhoneVerified        bool             `json:"phone_verified"`
+	FirstName            string           `json:"first_name"`
+	LastName             string           `json:"last_name"`
+	DateOfBirth          string           `json:"date_of_birth,omitempty"`
+	CountryID            uuid.UUID        `json:"country_id"`
+	KYCStatus            models.KYCStatus `json:"kyc_status"`
+	TwoFactorEnabled     bool             `json:"two_factor_enabled"`
+	PreferredLang        string           `json:"preferred_lang,omitempty"`
+	NewsletterSubscribed bool             `json:"newsletter_subscribed"`
+	HideFromLeaderboards bool             `json:"hide_from_leaderboards"`
+	ReferralCode         string           `json:"referral_code,omitempty"`
+	CreatedAt            time.Time        `json:"created_at"`
+}
+
+// ToProfileResponse converts a user model to ProfileResponse.
+func ToProfileResponse(user *models.User) *ProfileResponse {
+	resp := &ProfileResponse{
+		ID:               user.ID,
+		Email:            user.Email,
+		EmailVerified:    user.IsEmailVerified(),
+		Phone:            user.Phone,
+		PhoneVerified:    user.IsPhoneVerified(),
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		CountryID:        user.CountryID,
+		KYCStatus:        user.KYCStatus,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		CreatedAt:        user.CreatedAt,
+	}
+	if user.DateOfBirth != nil {
+		resp.DateOfBirth = user.DateOfBirth.Format(DateOfBirthLayout)
+	}
+	if user.Metadata != nil {
+		resp.PreferredLang = user.Metadata.PreferredLang
+		resp.NewsletterSubscribed = user.Metadata.NewsletterSubs
+		resp.HideFromLeaderboards = user.Metadata.HideFromLeaderboards
+		resp.ReferralCode = user.Metadata.ReferralCode
+	}
+	return resp
+}
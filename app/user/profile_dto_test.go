@@ -0,0 +1,104 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+func TestUpdateProfileRequest_Validate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+
+	t.Run("valid", func(t *testing.T) {
+		req := &UpdateProfileRequest{FirstName: str("<b>Jane</b>"), DateOfBirth: str("1990-05-17"), PreferredLang: str("en")}
+		v := validator.New()
+
+		assert.True(t, req.Validate(v, sanitizer.NewHTMLStripper(), now))
+		assert.Equal(t, "Jane", *req.FirstName)
+		assert.Equal(t, time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), *req.BirthDate)
+	})
+
+	t.Run("under age", func(t *testing.T) {
+		req := &UpdateProfileRequest{DateOfBirth: str("2010-01-01")}
+		v := validator.New()
+
+		assert.False(t, req.Validate(v, sanitizer.NewHTMLStripper(), now))
+		assert.Contains(t, v.Errors, "date_of_birth")
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		req := &UpdateProfileRequest{LastName: str("D"), DateOfBirth: str("17/05/1990"), PreferredLang: str("english!")}
+		v := validator.New()
+
+		assert.False(t, req.Validate(v, sanitizer.NewHTMLStripper(), now))
+		assert.Contains(t, v.Errors, "last_name")
+		assert.Contains(t, v.Errors, "date_of_birth")
+		assert.Contains(t, v.Errors, "preferred_lang")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		v := validator.New()
+
+		assert.False(t, (&UpdateProfileRequest{}).Validate(v, sanitizer.NewHTMLStripper(), now))
+		assert.Contains(t, v.Errors, "profile")
+	})
+}
+
+func TestChangePasswordRequest_Validate(t *testing.T) {
+	v := validator.New()
+	assert.True(t, (&ChangePasswordRequest{CurrentPassword: "old-password", NewPassword: "new-password"}).Validate(v))
+
+	v = validator.New()
+	assert.False(t, (&ChangePasswordRequest{CurrentPassword: "same-password", NewPassword: "same-password"}).Validate(v))
+	assert.Contains(t, v.Errors, "new_password")
+
+	v = validator.New()
+	assert.False(t, (&ChangePasswordRequest{NewPassword: "short"}).Validate(v))
+	assert.Contains(t, v.Errors, "current_password")
+	assert.Contains(t, v.Errors, "new_password")
+}
+
+func TestChangeEmailRequest_Validate(t *testing.T) {
+	req := &ChangeEmailRequest{Email: " New@Example.com", Password: "password"}
+	v := validator.New()
+
+	assert.True(t, req.Validate(v, sanitizer.NewHTMLStripper()))
+	assert.Equal(t, "new@example.com", req.Email)
+
+	v = validator.New()
+	assert.False(t, (&ChangeEmailRequest{Email: "not-an-email"}).Validate(v, sanitizer.NewHTMLStripper()))
+	assert.Contains(t, v.Errors, "email")
+	assert.Contains(t, v.Errors, "password")
+}
+
+func TestChangePhoneRequest_Validate(t *testing.T) {
+	ctx := context.Background()
+	active := true
+
+	countryRepo := &MockCountryRepo{}
+	countryRepo.On("GetByCode", ctx, "NG").Return(&models.Country{ID: uuid.New(), Code: "NG", IsActive: &active}, nil)
+	countryRepo.On("GetByCode", ctx, "XX").Return(nil, models.ErrRecordNotFound)
+
+	req := &ChangePhoneRequest{PhoneNumber: "08012345678", CountryCode: "NG"}
+	v := validator.New()
+	assert.True(t, req.Validate(ctx, v, countryRepo, sanitizer.NewHTMLStripper()))
+	assert.Equal(t, "+2348012345678", req.PhoneNumber)
+
+	v = validator.New()
+	assert.False(t, (&ChangePhoneRequest{PhoneNumber: "08012345678", CountryCode: "XX"}).Validate(ctx, v, countryRepo, sanitizer.NewHTMLStripper()))
+	assert.Contains(t, v.Errors, "country_code")
+}
+
+func TestConfirmPhoneChangeRequest_Validate(t *testing.T) {
+	assert.True(t, (&ConfirmPhoneChangeRequest{Code: "012345"}).Validate(validator.New()))
+	assert.False(t, (&ConfirmPhoneChangeRequest{Code: "12345"}).Validate(validator.New()))
+	assert.False(t, (&ConfirmPhoneChangeRequest{Code: "abcdef"}).Validate(validator.New()))
+}
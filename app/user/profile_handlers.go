@@ -0,0 +1,346 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/app/countries"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/internal/validator"
+	"github.com/joefazee/neo/models"
+)
+
+// ProfileHandler handles the authenticated user's own profile and credentials
+type ProfileHandler struct {
+	profile     ProfileService
+	countryRepo countries.Repository
+	sanitizer   sanitizer.HTMLStripperer
+	logger      logger.Logger
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(profile ProfileService,
+	countryRepo countries.Repository,
+	s sanitizer.HTMLStripperer,
+	lg logger.Logger) *ProfileHandler {
+	return &ProfileHandler{profile: profile, countryRepo: countryRepo, sanitizer: s, logger: lg}
+}
+
+// currentTokenID returns the ID of the token used for the request. API-key
+// requests carry no token and get uuid.Nil.
+func currentTokenID(c *gin.Context) uuid.UUID {
+	if payload, exists := c.Get(ContextToken); exists {
+		return payload.(*security.Payload).ID
+	}
+	return uuid.Nil
+}
+
+// GetProfile godoc
+// @Summary Get profile
+// @Description Get the authenticated user's profile
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} api.Response{data=ProfileResponse}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/profile [get]
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	profile, err := h.profile.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err, "GetProfile", "Failed to retrieve profile")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "User profile retrieved successfully", profile)
+}
+
+// UpdateProfile godoc
+// @Summary Update profile
+// @Description Update the authenticated user's names, date of birth, language and newsletter preference
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileRequest true "Profile fields to change"
+// @Success 200 {object} api.Response{data=ProfileResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/profile [patch]
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	if !req.Validate(v, h.sanitizer, time.Now()) {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	profile, err := h.profile.UpdateProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleError(c, err, "UpdateProfile", "Failed to update profile")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Profile updated successfully", profile)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the password after confirming the current one. Every session is logged out and a new access token is returned for this one
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} api.Response{data=ChangePasswordResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/profile/password [put]
+func (h *ProfileHandler) ChangePassword(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	if !req.Validate(v) {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	resp, err := h.profile.ChangePassword(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidPassword) {
+			v.AddError("current_password", "current password is incorrect")
+			api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+			return
+		}
+		h.handleError(c, err, "ChangePassword", "Failed to change password")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Password changed successfully", resp)
+}
+
+// RequestEmailChange godoc
+// @Summary Request email change
+// @Description Send a verification token to a new email address. The address changes once the token is confirmed
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangeEmailRequest true "New email and current password"
+// @Success 202 {object} api.Response
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/profile/email [post]
+func (h *ProfileHandler) RequestEmailChange(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	if !req.Validate(v, h.sanitizer) {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	if err := h.profile.RequestEmailChange(c.Request.Context(), userID, &req); err != nil {
+		if errors.Is(err, models.ErrInvalidPassword) {
+			v.AddError("password", "password is incorrect")
+			api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+			return
+		}
+		h.handleError(c, err, "RequestEmailChange", "Failed to request email change")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusAccepted, "Verification sent to the new email address", nil)
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm email change
+// @Description Confirm a new email address with the token sent to it. Other sessions are logged out
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ConfirmEmailChangeRequest true "Verification token"
+// @Success 200 {object} api.Response{data=ProfileResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/profile/email/verify [post]
+func (h *ProfileHandler) ConfirmEmailChange(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	if !req.Validate(v) {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	profile, err := h.profile.ConfirmEmailChange(c.Request.Context(), userID, currentTokenID(c), &req)
+	if err != nil {
+		h.handleError(c, err, "ConfirmEmailChange", "Failed to change email")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Email changed successfully", profile)
+}
+
+// RequestPhoneChange godoc
+// @Summary Request phone change
+// @Description Send an OTP to a new phone number. The number changes once the OTP is confirmed
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePhoneRequest true "New phone number"
+// @Success 202 {object} api.Response
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/profile/phone [post]
+func (h *ProfileHandler) RequestPhoneChange(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ChangePhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	if !req.Validate(c, v, h.countryRepo, h.sanitizer) {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	if err := h.profile.RequestPhoneChange(c.Request.Context(), userID, &req); err != nil {
+		h.handleError(c, err, "RequestPhoneChange", "Failed to request phone change")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusAccepted, "Verification code sent to the new phone number", nil)
+}
+
+// ConfirmPhoneChange godoc
+// @Summary Confirm phone change
+// @Description Confirm a new phone number with the OTP sent to it. Other sessions are logged out
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ConfirmPhoneChangeRequest true "One-time code"
+// @Success 200 {object} api.Response{data=ProfileResponse}
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/profile/phone/verify [post]
+func (h *ProfileHandler) ConfirmPhoneChange(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	var req ConfirmPhoneChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	v := validator.New()
+	if !req.Validate(v) {
+		api.ValidationErrorResponse(c, validator.NewValidationError("Validation failed", v.Errors))
+		return
+	}
+
+	profile, err := h.profile.ConfirmPhoneChange(c.Request.Context(), userID, currentTokenID(c), &req)
+	if err != nil {
+		h.handleError(c, err, "ConfirmPhoneChange", "Failed to change phone number")
+		return
+	}
+
+	api.SuccessResponse(c, http.StatusOK, "Phone number changed successfully", profile)
+}
+
+func (h *ProfileHandler) handleError(c *gin.Context, err error, handler, message string) {
+	switch {
+	case errors.Is(err, models.ErrRecordNotFound):
+		api.NotFoundResponse(c, "User")
+	case errors.Is(err, models.ErrEmailTaken):
+		api.ConflictResponse(c, "Email address is already in use")
+	case errors.Is(err, models.ErrPhoneTaken):
+		api.ConflictResponse(c, "Phone number is already in use")
+	case errors.Is(err, models.ErrInvalidVerificationCode):
+		api.BadRequestResponse(c, "Invalid or expired verification code")
+	default:
+		h.logger.Error(err, logger.Fields{"handler": handler})
+		api.InternalErrorResponse(c, message)
+	}
+}
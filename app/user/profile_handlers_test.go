@@ -0,0 +1,114 @@
+package user
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/sanitizer"
+	"github.com/joefazee/neo/models"
+)
+
+func newProfileTestContext(method, path, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func newTestProfileHandler(profiles *MockProfileService) *ProfileHandler {
+	return NewProfileHandler(profiles, &MockCountryRepo{}, sanitizer.NewHTMLStripper(), logger.NewNullLogger())
+}
+
+func TestProfileHandler_GetProfile(t *testing.T) {
+	profiles := &MockProfileService{}
+	userID := uuid.New()
+	profiles.On("GetProfile", mock.Anything, userID).Return(&ProfileResponse{ID: userID}, nil)
+
+	c, w := newProfileTestContext("GET", "/users/profile", "")
+	c.Set("userID", userID)
+	newTestProfileHandler(profiles).GetProfile(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), userID.String())
+}
+
+func TestProfileHandler_GetProfile_Unauthorized(t *testing.T) {
+	c, w := newProfileTestContext("GET", "/users/profile", "")
+	newTestProfileHandler(&MockProfileService{}).GetProfile(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestProfileHandler_UpdateProfile_ValidationError(t *testing.T) {
+	profiles := &MockProfileService{}
+
+	c, w := newProfileTestContext("PATCH", "/users/profile", `{"date_of_birth":"yesterday"}`)
+	c.Set("userID", uuid.New())
+	newTestProfileHandler(profiles).UpdateProfile(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	profiles.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProfileHandler_ChangePassword(t *testing.T) {
+	profiles := &MockProfileService{}
+	userID := uuid.New()
+	profiles.On("ChangePassword", mock.Anything, userID, mock.AnythingOfType("*user.ChangePasswordRequest")).
+		Return(&ChangePasswordResponse{AccessToken: "fresh-token"}, nil)
+
+	c, w := newProfileTestContext("PUT", "/users/profile/password", `{"current_password":"old-password","new_password":"new-password"}`)
+	c.Set("userID", userID)
+	newTestProfileHandler(profiles).ChangePassword(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "fresh-token")
+	profiles.AssertExpectations(t)
+}
+
+func TestProfileHandler_ChangePassword_WrongCurrent(t *testing.T) {
+	profiles := &MockProfileService{}
+	userID := uuid.New()
+	profiles.On("ChangePassword", mock.Anything, userID, mock.Anything).Return(nil, models.ErrInvalidPassword)
+
+	c, w := newProfileTestContext("PUT", "/users/profile/password", `{"current_password":"bad-password","new_password":"new-password"}`)
+	c.Set("userID", userID)
+	newTestProfileHandler(profiles).ChangePassword(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "current_password")
+}
+
+func TestProfileHandler_RequestEmailChange_Taken(t *testing.T) {
+	profiles := &MockProfileService{}
+	userID := uuid.New()
+	profiles.On("RequestEmailChange", mock.Anything, userID, mock.Anything).Return(models.ErrEmailTaken)
+
+	c, w := newProfileTestContext("POST", "/users/profile/email", `{"email":"taken@example.com","password":"password"}`)
+	c.Set("userID", userID)
+	newTestProfileHandler(profiles).RequestEmailChange(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestProfileHandler_ConfirmPhoneChange_InvalidCode(t *testing.T) {
+	profiles := &MockProfileService{}
+	userID := uuid.New()
+	profiles.On("ConfirmPhoneChange", mock.Anything, userID, uuid.Nil, mock.Anything).Return(nil, models.ErrInvalidVerificationCode)
+
+	c, w := newProfileTestContext("POST", "/users/profile/phone/verify", `{"code":"123456"}`)
+	c.Set("userID", userID)
+	newTestProfileHandler(profiles).ConfirmPhoneChange(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "verification code")
+}
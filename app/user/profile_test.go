@@ -0,0 +1,342 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+)
+
+type MockProfileService struct {
+	mock.Mock
+}
+
+func (m *MockProfileService) GetProfile(ctx context.Context, userID uuid.UUID) (*ProfileResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ProfileResponse), args.Error(1)
+}
+
+func (m *MockProfileService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *UpdateProfileRequest) (*ProfileResponse, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ProfileResponse), args.Error(1)
+}
+
+func (m *MockProfileService) ChangePassword(ctx context.Context, userID uuid.UUID, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ChangePasswordResponse), args.Error(1)
+}
+
+func (m *MockProfileService) RequestEmailChange(ctx context.Context, userID uuid.UUID, req *ChangeEmailRequest) error {
+	return m.Called(ctx, userID, req).Error(0)
+}
+
+func (m *MockProfileService) ConfirmEmailChange(ctx context.Context,
+	userID, currentTokenID uuid.UUID,
+	req *ConfirmEmailChangeRequest) (*ProfileResponse, error) {
+	args := m.Called(ctx, userID, currentTokenID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ProfileResponse), args.Error(1)
+}
+
+func (m *MockProfileService) RequestPhoneChange(ctx context.Context, userID uuid.UUID, req *ChangePhoneRequest) error {
+	return m.Called(ctx, userID, req).Error(0)
+}
+
+func (m *MockProfileService) ConfirmPhoneChange(ctx context.Context,
+	userID, currentTokenID uuid.UUID,
+	req *ConfirmPhoneChangeRequest) (*ProfileResponse, error) {
+	args := m.Called(ctx, userID, currentTokenID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ProfileResponse), args.Error(1)
+}
+
+// captureSender records the last secret sent instead of delivering it.
+type captureSender struct {
+	destination string
+	secret      string
+}
+
+func (s *captureSender) SendEmailVerification(_ context.Context, _ *models.User, email, token string) error {
+	s.destination, s.secret = email, token
+	return nil
+}
+
+func (s *captureSender) SendPhoneOTP(_ context.Context, _ *models.User, phone, code string) error {
+	s.destination, s.secret = phone, code
+	return nil
+}
+
+type profileFixture struct {
+	repo       *MockRepo
+	sessions   *MockSessionService
+	tokenMaker *security.MockMaker
+	sender     *captureSender
+	service    ProfileService
+	user       *models.User
+}
+
+func newProfileFixture(t *testing.T) *profileFixture {
+	user := &models.User{ID: uuid.New(), Email: "jane@example.com", Phone: "+2348012345678", FirstName: "Jane", LastName: "Doe"}
+	require.NoError(t, user.SetPassword("current-password"))
+
+	f := &profileFixture{
+		repo:       &MockRepo{},
+		sessions:   &MockSessionService{},
+		tokenMaker: &security.MockMaker{},
+		sender:     &captureSender{},
+		user:       user,
+	}
+	c := cache.NewMemoryCache[string]()
+	f.service = NewProfileService(f.repo, f.sessions, NewTokenVersions(f.repo, c), f.tokenMaker, c, c, f.sender, GetDefaultConfig(), nil)
+	f.repo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	return f
+}
+
+func TestProfileService_UpdateProfile(t *testing.T) {
+	f := newProfileFixture(t)
+	lang, subscribed, hidden := "pt-BR", true, true
+	dob := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	f.repo.On("Update", mock.Anything, f.user).Return(nil)
+	f.repo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l *models.AuditLog) bool {
+		_, langChanged := l.NewValues["preferred_lang"]
+		_, nameChanged := l.NewValues["first_name"]
+		return l.Action == models.AuditActionProfileUpdated && langChanged && !nameChanged
+	})).Return(nil)
+
+	name := "Jane"
+	resp, err := f.service.UpdateProfile(context.Background(), f.user.ID, &UpdateProfileRequest{
+		FirstName:            &name,
+		BirthDate:            &dob,
+		PreferredLang:        &lang,
+		NewsletterSubscribed: &subscribed,
+		HideFromLeaderboards: &hidden,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "pt-BR", resp.PreferredLang)
+	assert.True(t, resp.NewsletterSubscribed)
+	assert.True(t, resp.HideFromLeaderboards)
+	assert.Equal(t, "1990-05-17", resp.DateOfBirth)
+	f.repo.AssertExpectations(t)
+	f.sessions.AssertNotCalled(t, "RevokeOthers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProfileService_UpdateProfileUnchanged(t *testing.T) {
+	f := newProfileFixture(t)
+	name := "Jane"
+
+	_, err := f.service.UpdateProfile(context.Background(), f.user.ID, &UpdateProfileRequest{FirstName: &name})
+
+	require.NoError(t, err)
+	f.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProfileService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revokes every token and issues a new one", func(t *testing.T) {
+		f := newProfileFixture(t)
+		payload := &security.Payload{ID: uuid.New(), UserID: f.user.ID}
+		f.repo.On("Update", ctx, f.user).Return(nil)
+		f.repo.On("IncrementTokenVersion", ctx, f.user.ID).Return(int64(4), nil)
+		f.sessions.On("RevokeOthers", ctx, f.user.ID, uuid.Nil).Return(nil)
+		f.tokenMaker.On("CreateToken", f.user.ID, accessTokenDuration, int64(4), security.TokenScopeAccess).
+			Return("fresh-token", payload, nil)
+		f.sessions.On("Start", ctx, f.user, payload, "10.0.0.1", "Mozilla/5.0").Return(&models.UserSession{}, nil)
+		f.repo.On("CreateAuditLog", ctx, mock.MatchedBy(func(l *models.AuditLog) bool {
+			return l.Action == models.AuditActionPasswordChanged
+		})).Return(nil)
+
+		resp, err := f.service.ChangePassword(ctx, f.user.ID, &ChangePasswordRequest{
+			CurrentPassword: "current-password",
+			NewPassword:     "new-password",
+			IPAddress:       "10.0.0.1",
+			UserAgent:       "Mozilla/5.0",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "fresh-token", resp.AccessToken)
+		assert.True(t, f.user.CheckPassword("new-password"))
+		f.repo.AssertExpectations(t)
+		f.sessions.AssertExpectations(t)
+	})
+
+	t.Run("wrong current password", func(t *testing.T) {
+		f := newProfileFixture(t)
+
+		_, err := f.service.ChangePassword(ctx, f.user.ID, &ChangePasswordRequest{
+			CurrentPassword: "wrong-password",
+			NewPassword:     "new-password",
+		})
+
+		assert.ErrorIs(t, err, models.ErrInvalidPassword)
+		f.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProfileService_EmailChange(t *testing.T) {
+	ctx := context.Background()
+	tokenID := uuid.New()
+
+	t.Run("applies once the token is confirmed", func(t *testing.T) {
+		f := newProfileFixture(t)
+		f.repo.On("GetByEmail", ctx, "new@example.com").Return(nil, models.ErrRecordNotFound)
+
+		err := f.service.RequestEmailChange(ctx, f.user.ID, &ChangeEmailRequest{Email: "new@example.com", Password: "current-password"})
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", f.sender.destination)
+		assert.Equal(t, "jane@example.com", f.user.Email)
+
+		f.repo.On("Update", ctx, f.user).Return(nil)
+		f.sessions.On("RevokeOthers", ctx, f.user.ID, tokenID).Return(nil)
+		f.repo.On("CreateAuditLog", ctx, mock.MatchedBy(func(l *models.AuditLog) bool {
+			return l.Action == models.AuditActionEmailChanged && l.NewValues["email"] == "new@example.com"
+		})).Return(nil)
+
+		resp, err := f.service.ConfirmEmailChange(ctx, f.user.ID, tokenID, &ConfirmEmailChangeRequest{Token: f.sender.secret})
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", resp.Email)
+		assert.True(t, resp.EmailVerified)
+
+		// The token is single use.
+		_, err = f.service.ConfirmEmailChange(ctx, f.user.ID, tokenID, &ConfirmEmailChangeRequest{Token: f.sender.secret})
+		assert.ErrorIs(t, err, models.ErrInvalidVerificationCode)
+	})
+
+	t.Run("address in use", func(t *testing.T) {
+		f := newProfileFixture(t)
+		f.repo.On("GetByEmail", ctx, "taken@example.com").Return(&models.User{ID: uuid.New()}, nil)
+
+		err := f.service.RequestEmailChange(ctx, f.user.ID, &ChangeEmailRequest{Email: "taken@example.com", Password: "current-password"})
+		assert.ErrorIs(t, err, models.ErrEmailTaken)
+		assert.Empty(t, f.sender.secret)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		f := newProfileFixture(t)
+
+		err := f.service.RequestEmailChange(ctx, f.user.ID, &ChangeEmailRequest{Email: "new@example.com", Password: "wrong-password"})
+		assert.ErrorIs(t, err, models.ErrInvalidPassword)
+	})
+}
+
+func TestProfileService_PhoneChange(t *testing.T) {
+	ctx := context.Background()
+	tokenID := uuid.New()
+	newPhone := "+2348098765432"
+
+	t.Run("applies once the OTP is confirmed", func(t *testing.T) {
+		f := newProfileFixture(t)
+		f.repo.On("GetByPhone", ctx, newPhone).Return(nil, models.ErrRecordNotFound)
+
+		require.NoError(t, f.service.RequestPhoneChange(ctx, f.user.ID, &ChangePhoneRequest{PhoneNumber: newPhone}))
+		assert.Equal(t, newPhone, f.sender.destination)
+		assert.Len(t, f.sender.secret, 6)
+
+		f.repo.On("Update", ctx, f.user).Return(nil)
+		f.sessions.On("RevokeOthers", ctx, f.user.ID, tokenID).Return(nil)
+		f.repo.On("CreateAuditLog", ctx, mock.MatchedBy(func(l *models.AuditLog) bool {
+			return l.Action == models.AuditActionPhoneChanged
+		})).Return(nil)
+
+		resp, err := f.service.ConfirmPhoneChange(ctx, f.user.ID, tokenID, &ConfirmPhoneChangeRequest{Code: f.sender.secret})
+		require.NoError(t, err)
+		assert.Equal(t, newPhone, resp.Phone)
+		assert.True(t, resp.PhoneVerified)
+	})
+
+	t.Run("drops the change after too many wrong codes", func(t *testing.T) {
+		f := newProfileFixture(t)
+		f.repo.On("GetByPhone", ctx, newPhone).Return(nil, models.ErrRecordNotFound)
+		require.NoError(t, f.service.RequestPhoneChange(ctx, f.user.ID, &ChangePhoneRequest{PhoneNumber: newPhone}))
+
+		wrong := "000000"
+		if f.sender.secret == wrong {
+			wrong = "111111"
+		}
+		for i := 0; i < GetDefaultConfig().MaxVerificationAttempts; i++ {
+			_, err := f.service.ConfirmPhoneChange(ctx, f.user.ID, tokenID, &ConfirmPhoneChangeRequest{Code: wrong})
+			assert.ErrorIs(t, err, models.ErrInvalidVerificationCode)
+		}
+
+		_, err := f.service.ConfirmPhoneChange(ctx, f.user.ID, tokenID, &ConfirmPhoneChangeRequest{Code: f.sender.secret})
+		assert.ErrorIs(t, err, models.ErrInvalidVerificationCode)
+		f.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("counts parallel wrong codes", func(t *testing.T) {
+		f := newProfileFixture(t)
+		f.repo.On("GetByPhone", ctx, newPhone).Return(nil, models.ErrRecordNotFound)
+		require.NoError(t, f.service.RequestPhoneChange(ctx, f.user.ID, &ChangePhoneRequest{PhoneNumber: newPhone}))
+
+		wrong := "000000"
+		if f.sender.secret == wrong {
+			wrong = "111111"
+		}
+		// Parallel guesses all read the pending change before it is dropped
+		var wg sync.WaitGroup
+		for i := 0; i < 3*GetDefaultConfig().MaxVerificationAttempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = f.service.ConfirmPhoneChange(ctx, f.user.ID, tokenID, &ConfirmPhoneChangeRequest{Code: wrong})
+			}()
+		}
+		wg.Wait()
+
+		_, err := f.service.ConfirmPhoneChange(ctx, f.user.ID, tokenID, &ConfirmPhoneChangeRequest{Code: f.sender.secret})
+		assert.ErrorIs(t, err, models.ErrInvalidVerificationCode)
+		f.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+// debugCapture records the fields of every debug entry.
+type debugCapture struct {
+	*logger.NullLogger
+	fields []logger.Fields
+}
+
+func (l *debugCapture) Debug(_ string, properties map[string]interface{}) {
+	l.fields = append(l.fields, properties)
+}
+
+func TestLogVerificationSender_NeverLogsSecrets(t *testing.T) {
+	lg := &debugCapture{NullLogger: logger.NewNullLogger()}
+	sender := NewLogVerificationSender(lg)
+	user := &models.User{ID: uuid.New()}
+
+	require.NoError(t, sender.SendEmailVerification(context.Background(), user, "new@example.com", "secret-token"))
+	require.NoError(t, sender.SendPhoneOTP(context.Background(), user, "+2348000000000", "123456"))
+
+	require.Len(t, lg.fields, 2)
+	for _, fields := range lg.fields {
+		for _, value := range fields {
+			assert.NotEqual(t, "secret-token", value)
+			assert.NotEqual(t, "123456", value)
+		}
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/joefazee/neo/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -53,14 +55,53 @@ func (r *repository) Update(ctx context.Context, user *models.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+// GetByIDWithPermissions loads the user's roles with their permissions. Roles
+// inherited through parent_id are appended to user.Roles.
 func (r *repository) GetByIDWithPermissions(ctx context.Context, userID uuid.UUID) (*models.User, error) {
 	var user models.User
 	err := r.db.WithContext(ctx).
 		Preload("Roles.Permissions").
 		First(&user, "id = ?", userID).Error
+	if err != nil || len(user.Roles) == 0 {
+		return &user, err
+	}
+
+	roleIDs := make([]uuid.UUID, len(user.Roles))
+	for i := range user.Roles {
+		roleIDs[i] = user.Roles[i].ID
+	}
+	ancestorIDs, err := r.GetRoleAncestorIDs(ctx, roleIDs...)
+	if err != nil || len(ancestorIDs) == 0 {
+		return &user, err
+	}
+
+	var inherited []models.Role
+	err = r.db.WithContext(ctx).
+		Preload("Permissions").
+		Where("id IN ? AND id NOT IN ?", ancestorIDs, roleIDs).
+		Find(&inherited).Error
+	user.Roles = append(user.Roles, inherited...)
 	return &user, err
 }
 
+// GetRoleAncestorIDs returns every role the given roles extend, directly or transitively.
+func (r *repository) GetRoleAncestorIDs(ctx context.Context, roleIDs ...uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if len(roleIDs) == 0 {
+		return ids, nil
+	}
+	// UNION (not UNION ALL) stops the recursion if a cycle ever slips in.
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id AS id FROM roles WHERE id IN ? AND parent_id IS NOT NULL
+			UNION
+			SELECT r.parent_id FROM roles r JOIN ancestors a ON r.id = a.id WHERE r.parent_id IS NOT NULL
+		)
+		SELECT id FROM ancestors`, roleIDs).
+		Scan(&ids).Error
+	return ids, err
+}
+
 // GetByID returns a user by their ID.
 func (r *repository) GetByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
 	var user models.User
@@ -70,11 +111,21 @@ func (r *repository) GetByID(ctx context.Context, userID uuid.UUID) (*models.Use
 
 // GetUsers retrieves a paginated and filtered list of users.
 func (r *repository) GetUsers(ctx context.Context, filters *AdminUserFilters) ([]models.User, int64, error) {
+	return r.findUsers(r.db.WithContext(ctx).Model(&models.User{}), filters)
+}
+
+// GetRoleMembers returns a page of the users holding the role directly.
+func (r *repository) GetRoleMembers(ctx context.Context, roleID uuid.UUID, filters *AdminUserFilters) ([]models.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id IN (SELECT user_id FROM user_roles WHERE role_id = ?)", roleID)
+	return r.findUsers(query, filters)
+}
+
+// findUsers applies the admin user filters, sorting and pagination to query.
+func (r *repository) findUsers(query *gorm.DB, filters *AdminUserFilters) ([]models.User, int64, error) {
 	var users []models.User
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&models.User{})
-
 	if filters.Status != "" {
 		isActive := filters.Status == "active"
 		query = query.Where("is_active = ?", isActive)
@@ -187,6 +238,59 @@ func (r *repository) GetPermissionsByNames(ctx context.Context, names []string)
 	return permissions, err
 }
 
+// GetPermissionByID retrieves a permission by its ID.
+func (r *repository) GetPermissionByID(ctx context.Context, id uuid.UUID) (*models.Permission, error) {
+	var permission models.Permission
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&permission).Error; err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+// ListPermissions returns a page of permissions ordered by name.
+func (r *repository) ListPermissions(ctx context.Context, filters *RBACFilters) ([]models.Permission, int64, error) {
+	var permissions []models.Permission
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Permission{})
+	if filters.Search != "" {
+		query = query.Where("name ILIKE ?", "%"+filters.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting permissions: %w", err)
+	}
+
+	offset := (filters.Page - 1) * filters.PerPage
+	err := query.Order("name ASC").Offset(offset).Limit(filters.PerPage).Find(&permissions).Error
+	return permissions, total, err
+}
+
+// GetRoleIDsByPermission returns the roles granting the permission directly.
+func (r *repository) GetRoleIDsByPermission(ctx context.Context, permissionID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw("SELECT role_id FROM role_permissions WHERE permission_id = ?", permissionID).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// DeletePermission removes a permission and revokes it from every role.
+func (r *repository) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM role_permissions WHERE permission_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.Permission{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
 func (r *repository) CreateRole(ctx context.Context, role *models.Role) error {
 	return r.db.WithContext(ctx).Create(role).Error
 }
@@ -204,6 +308,94 @@ func (r *repository) GetRoleByID(ctx context.Context, id uuid.UUID) (*models.Rol
 	return &role, nil
 }
 
+// ListRoles returns a page of roles with their direct permissions, ordered by name.
+func (r *repository) ListRoles(ctx context.Context, filters *RBACFilters) ([]models.Role, int64, error) {
+	var roles []models.Role
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Role{})
+	if filters.Search != "" {
+		query = query.Where("name ILIKE ?", "%"+filters.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting roles: %w", err)
+	}
+
+	offset := (filters.Page - 1) * filters.PerPage
+	err := query.Preload("Permissions").Order("name ASC").Offset(offset).Limit(filters.PerPage).Find(&roles).Error
+	return roles, total, err
+}
+
+// DeleteRole removes a role, its grants and memberships. Roles extending it are detached.
+// The role row is locked before its members are counted, so an assignment racing
+// the delete either lands first and is seen, or fails once the role is gone.
+// Unless force is set, a role that still has members is left untouched with
+// ErrRoleInUse. It returns the users whose effective permissions depended on it.
+func (r *repository) DeleteRole(ctx context.Context, id uuid.UUID, force bool) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var role models.Role
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&role).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrRecordNotFound
+			}
+			return err
+		}
+
+		var members int64
+		if err := tx.Table("user_roles").Where("role_id = ?", id).Count(&members).Error; err != nil {
+			return err
+		}
+		if members > 0 && !force {
+			return models.ErrRoleInUse
+		}
+
+		// Includes members of descendant roles, which inherit from this one
+		var err error
+		if userIDs, err = userIDsByRoles(tx, id); err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM user_roles WHERE role_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM role_permissions WHERE role_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Role{}).Where("parent_id = ?", id).Update("parent_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.Role{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// GetUserIDsByRoles returns the users holding any of the roles or a role that
+// extends them, i.e. everyone whose effective permissions depend on the roles.
+func (r *repository) GetUserIDsByRoles(ctx context.Context, roleIDs ...uuid.UUID) ([]uuid.UUID, error) {
+	return userIDsByRoles(r.db.WithContext(ctx), roleIDs...)
+}
+
+func userIDsByRoles(db *gorm.DB, roleIDs ...uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if len(roleIDs) == 0 {
+		return ids, nil
+	}
+	err := db.Raw(`
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM roles WHERE id IN ?
+			UNION
+			SELECT r.id FROM roles r JOIN descendants d ON r.parent_id = d.id
+		)
+		SELECT DISTINCT user_id FROM user_roles WHERE role_id IN (SELECT id FROM descendants)`, roleIDs).
+		Scan(&ids).Error
+	return ids, err
+}
+
 // AssignPermissionsToRole assigns a list of permissions to a role.
 // //nolint: dupl
 func (r *repository) AssignPermissionsToRole(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error {
@@ -282,3 +474,138 @@ func (r *repository) RemoveRoleFromUser(ctx context.Context, userID, roleID uuid
 
 	return r.db.WithContext(ctx).Model(&user).Association("Roles").Delete(&role)
 }
+
+// UpdateLoginState persists the failed-login counter, lock window and last login details.
+func (r *repository) UpdateLoginState(ctx context.Context, user *models.User) error {
+	var lastLoginIP interface{}
+	if user.LastLoginIP != nil {
+		lastLoginIP = user.LastLoginIP.String()
+	}
+
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"failed_login_attempts": user.FailedLoginAttempts,
+		"locked_until":          user.LockedUntil,
+		"last_login_at":         user.LastLoginAt,
+		"last_login_ip":         lastLoginIP,
+	}).Error
+}
+
+// IncrementFailedLogins bumps the counter in the database rather than writing
+// back a value read earlier, so concurrent failures are all counted.
+func (r *repository) IncrementFailedLogins(ctx context.Context, userID uuid.UUID) (int, error) {
+	var attempts int
+	err := r.db.WithContext(ctx).
+		Raw("UPDATE users SET failed_login_attempts = failed_login_attempts + 1 WHERE id = ? RETURNING failed_login_attempts", userID).
+		Scan(&attempts).Error
+	if err != nil {
+		return 0, err
+	}
+	if attempts == 0 {
+		return 0, models.ErrRecordNotFound
+	}
+	return attempts, nil
+}
+
+func (r *repository) LockUntil(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).
+		Update("locked_until", gorm.Expr("GREATEST(locked_until, ?)", until)).Error
+}
+
+func (r *repository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// GetTokenVersion returns the user's current token version.
+func (r *repository) GetTokenVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Select("token_version").First(&user, "id = ?", userID).Error
+	return user.TokenVersion, err
+}
+
+// IncrementTokenVersion bumps the user's token version and returns the new value.
+func (r *repository) IncrementTokenVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var version int64
+	err := r.db.WithContext(ctx).
+		Raw("UPDATE users SET token_version = token_version + 1 WHERE id = ? RETURNING token_version", userID).
+		Scan(&version).Error
+	if err != nil {
+		return 0, err
+	}
+	if version == 0 {
+		return 0, models.ErrRecordNotFound
+	}
+	return version, nil
+}
+
+func (r *repository) CreateSession(ctx context.Context, session *models.UserSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// GetActiveSessions returns the user's unrevoked, unexpired sessions, most recently used first.
+func (r *repository) GetActiveSessions(ctx context.Context, userID uuid.UUID) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// GetSessionByID returns a session only if it belongs to userID.
+func (r *repository) GetSessionByID(ctx context.Context, userID, sessionID uuid.UUID) (*models.UserSession, error) {
+	var session models.UserSession
+	err := r.db.WithContext(ctx).First(&session, "id = ? AND user_id = ?", sessionID, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *repository) HasSessionForDevice(ctx context.Context, userID uuid.UUID, deviceName string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.UserSession{}).
+		Where("user_id = ? AND device_name = ?", userID, deviceName).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RevokeSession marks the session revoked and blacklists its token in one transaction.
+func (r *repository) RevokeSession(ctx context.Context, session *models.UserSession, entry *models.TokenBlacklist) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(session).Update("revoked_at", session.RevokedAt).Error; err != nil {
+			return fmt.Errorf("revoking session: %w", err)
+		}
+		if err := tx.Where("token_jti = ?", entry.TokenJTI).FirstOrCreate(entry).Error; err != nil {
+			return fmt.Errorf("blacklisting token: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *repository) IsTokenBlacklisted(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TokenBlacklist{}).
+		Where("token_jti = ?", jti).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// TouchSession records activity on the session and the user's metadata last_seen_at.
+func (r *repository) TouchSession(ctx context.Context, userID uuid.UUID, jti string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserSession{}).
+			Where("token_jti = ?", jti).
+			Update("last_seen_at", at).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).
+			Where("id = ?", userID).
+			UpdateColumn("metadata", gorm.Expr(
+				"jsonb_set(COALESCE(metadata, '{}'::jsonb), '{last_seen_at}', to_jsonb(?::text))",
+				at.UTC().Format(time.RFC3339Nano),
+			)).Error
+	})
+}
@@ -3,7 +3,9 @@ package user
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -509,6 +511,225 @@ func (suite *UserRepositoryTestSuite) TestRemoveRoleFromUser_RoleNotFound() {
 
 // Helper methods
 
+func (suite *UserRepositoryTestSuite) TestIncrementTokenVersion() {
+	ctx := context.Background()
+	user := suite.createTestUser("token_version@example.com", "+1919191919")
+
+	version, err := suite.repo.GetTokenVersion(ctx, user.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal(int64(1), version)
+
+	version, err = suite.repo.IncrementTokenVersion(ctx, user.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal(int64(2), version)
+
+	version, err = suite.repo.GetTokenVersion(ctx, user.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal(int64(2), version)
+}
+
+func (suite *UserRepositoryTestSuite) TestIncrementTokenVersion_UserNotFound() {
+	_, err := suite.repo.IncrementTokenVersion(context.Background(), uuid.New())
+	suite.Assert().ErrorIs(err, models.ErrRecordNotFound)
+}
+
+func (suite *UserRepositoryTestSuite) TestIncrementFailedLogins_Concurrent() {
+	ctx := context.Background()
+	user := suite.createTestUser("failed_logins@example.com", "+1939393939")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := suite.repo.IncrementFailedLogins(ctx, user.ID)
+			suite.AssertNoDBError(err)
+		}()
+	}
+	wg.Wait()
+
+	found, err := suite.repo.GetByID(ctx, user.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal(10, found.FailedLoginAttempts)
+
+	_, err = suite.repo.IncrementFailedLogins(ctx, uuid.New())
+	suite.Assert().ErrorIs(err, models.ErrRecordNotFound)
+}
+
+func (suite *UserRepositoryTestSuite) TestLockUntil_KeepsLongerLock() {
+	ctx := context.Background()
+	user := suite.createTestUser("lock_until@example.com", "+1949494949")
+	later := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	suite.AssertNoDBError(suite.repo.LockUntil(ctx, user.ID, later))
+	suite.AssertNoDBError(suite.repo.LockUntil(ctx, user.ID, time.Now().Add(time.Minute)))
+
+	found, err := suite.repo.GetByID(ctx, user.ID)
+	suite.AssertNoDBError(err)
+	suite.Require().NotNil(found.LockedUntil)
+	suite.Assert().WithinDuration(later, *found.LockedUntil, time.Second)
+}
+
+func (suite *UserRepositoryTestSuite) TestSessions() {
+	ctx := context.Background()
+	user := suite.createTestUser("sessions@example.com", "+1929292929")
+
+	session := &models.UserSession{
+		UserID:     user.ID,
+		TokenJTI:   uuid.New().String(),
+		DeviceName: "Chrome on macOS",
+		IPAddress:  "10.0.0.1",
+		LastSeenAt: time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	suite.AssertNoDBError(suite.repo.CreateSession(ctx, session))
+
+	known, err := suite.repo.HasSessionForDevice(ctx, user.ID, "Chrome on macOS")
+	suite.AssertNoDBError(err)
+	suite.Assert().True(known)
+
+	known, err = suite.repo.HasSessionForDevice(ctx, user.ID, "Firefox on Linux")
+	suite.AssertNoDBError(err)
+	suite.Assert().False(known)
+
+	active, err := suite.repo.GetActiveSessions(ctx, user.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Len(active, 1)
+
+	suite.AssertNoDBError(suite.repo.TouchSession(ctx, user.ID, session.TokenJTI, time.Now()))
+
+	_, err = suite.repo.GetSessionByID(ctx, uuid.New(), session.ID)
+	suite.Assert().ErrorIs(err, models.ErrRecordNotFound)
+
+	found, err := suite.repo.GetSessionByID(ctx, user.ID, session.ID)
+	suite.AssertNoDBError(err)
+
+	now := time.Now()
+	found.RevokedAt = &now
+	entry := models.CreateBlacklistEntry(found.TokenJTI, user.ID, found.ExpiresAt)
+	suite.AssertNoDBError(suite.repo.RevokeSession(ctx, found, entry))
+
+	blacklisted, err := suite.repo.IsTokenBlacklisted(ctx, session.TokenJTI)
+	suite.AssertNoDBError(err)
+	suite.Assert().True(blacklisted)
+
+	active, err = suite.repo.GetActiveSessions(ctx, user.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Empty(active)
+}
+
+func (suite *UserRepositoryTestSuite) TestGetByIDWithPermissions_InheritedRoles() {
+	ctx := context.Background()
+	user := suite.createTestUser("inherit@example.com", "+1939393939")
+
+	base := &models.Role{Name: "inherit_base"}
+	suite.AssertNoDBError(suite.repo.CreateRole(ctx, base))
+	mid := &models.Role{Name: "inherit_mid", ParentID: &base.ID}
+	suite.AssertNoDBError(suite.repo.CreateRole(ctx, mid))
+	top := &models.Role{Name: "inherit_top", ParentID: &mid.ID}
+	suite.AssertNoDBError(suite.repo.CreateRole(ctx, top))
+
+	perm := &models.Permission{Name: "inherit:read"}
+	suite.AssertNoDBError(suite.repo.CreatePermission(ctx, perm))
+	suite.AssertNoDBError(suite.repo.AssignPermissionsToRole(ctx, base.ID, []uuid.UUID{perm.ID}))
+	suite.AssertNoDBError(suite.repo.AssignRole(ctx, user.ID, top.ID))
+
+	ancestors, err := suite.repo.GetRoleAncestorIDs(ctx, top.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().ElementsMatch([]uuid.UUID{mid.ID, base.ID}, ancestors)
+
+	// Changing the base role affects the user through the roles extending it.
+	members, err := suite.repo.GetUserIDsByRoles(ctx, base.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal([]uuid.UUID{user.ID}, members)
+
+	loaded, err := suite.repo.GetByIDWithPermissions(ctx, user.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Len(loaded.Roles, 3)
+
+	var names []string
+	for _, role := range loaded.Roles {
+		for _, p := range role.Permissions {
+			names = append(names, p.Name)
+		}
+	}
+	suite.Assert().Contains(names, "inherit:read")
+}
+
+func (suite *UserRepositoryTestSuite) TestDeleteRole() {
+	ctx := context.Background()
+	user := suite.createTestUser("delrole@example.com", "+1949494949")
+	role := suite.createTestRole("delete_me")
+	child := &models.Role{Name: "delete_me_child", ParentID: &role.ID}
+	suite.AssertNoDBError(suite.repo.CreateRole(ctx, child))
+	perm := suite.createTestPermission("delete_me:read")
+	suite.assignPermissionToRole(ctx, role.ID, perm.ID)
+	suite.assignRoleToUser(user.ID, role.ID)
+
+	members, total, err := suite.repo.GetRoleMembers(ctx, role.ID, &AdminUserFilters{Page: 1, PerPage: 10})
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal(int64(1), total)
+	suite.Assert().Equal(user.ID, members[0].ID)
+
+	_, err = suite.repo.DeleteRole(ctx, role.ID, false)
+	suite.Assert().ErrorIs(err, models.ErrRoleInUse)
+	_, err = suite.repo.GetRoleByID(ctx, role.ID)
+	suite.AssertNoDBError(err)
+
+	affected, err := suite.repo.DeleteRole(ctx, role.ID, true)
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal([]uuid.UUID{user.ID}, affected)
+
+	_, err = suite.repo.GetRoleByID(ctx, role.ID)
+	suite.Assert().ErrorIs(err, gorm.ErrRecordNotFound)
+	detached, err := suite.repo.GetRoleByID(ctx, child.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Nil(detached.ParentID)
+	_, total, err = suite.repo.GetRoleMembers(ctx, role.ID, &AdminUserFilters{Page: 1, PerPage: 10})
+	suite.AssertNoDBError(err)
+	suite.Assert().Zero(total)
+
+	_, err = suite.repo.DeleteRole(ctx, role.ID, true)
+	suite.Assert().ErrorIs(err, models.ErrRecordNotFound)
+}
+
+func (suite *UserRepositoryTestSuite) TestDeletePermission() {
+	ctx := context.Background()
+	role := suite.createTestRole("perm_holder")
+	perm := suite.createTestPermission("perm_holder:write")
+	suite.assignPermissionToRole(ctx, role.ID, perm.ID)
+
+	roleIDs, err := suite.repo.GetRoleIDsByPermission(ctx, perm.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal([]uuid.UUID{role.ID}, roleIDs)
+
+	listed, total, err := suite.repo.ListPermissions(ctx, &RBACFilters{Page: 1, PerPage: 10, Search: "perm_holder"})
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal(int64(1), total)
+	suite.Assert().Equal(perm.ID, listed[0].ID)
+
+	suite.AssertNoDBError(suite.repo.DeletePermission(ctx, perm.ID))
+
+	_, err = suite.repo.GetPermissionByID(ctx, perm.ID)
+	suite.Assert().ErrorIs(err, gorm.ErrRecordNotFound)
+	loaded, err := suite.repo.GetRoleByID(ctx, role.ID)
+	suite.AssertNoDBError(err)
+	suite.Assert().Empty(loaded.Permissions)
+}
+
+func (suite *UserRepositoryTestSuite) TestListRoles() {
+	ctx := context.Background()
+	role := suite.createTestRole("listed_role")
+	perm := suite.createTestPermission("listed_role:read")
+	suite.assignPermissionToRole(ctx, role.ID, perm.ID)
+
+	roles, total, err := suite.repo.ListRoles(ctx, &RBACFilters{Page: 1, PerPage: 10, Search: "listed_"})
+	suite.AssertNoDBError(err)
+	suite.Assert().Equal(int64(1), total)
+	suite.Assert().Equal(role.ID, roles[0].ID)
+	suite.Assert().Len(roles[0].Permissions, 1)
+}
+
 func (suite *UserRepositoryTestSuite) createTestUser(email, phone string) *models.User {
 	country := suite.createTestCountry(email, email[:2])
 	return suite.createTestUserWithCountry(email, phone, country.ID)
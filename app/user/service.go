@@ -3,25 +3,50 @@ package user
 import (
 	"context"
 	"errors"
+	"net"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/referral"
 	"github.com/joefazee/neo/internal/security"
 	"github.com/joefazee/neo/models"
 	"gorm.io/gorm"
 )
 
+// accessTokenDuration is how long a token issued at login or on a password
+// change stays valid.
+const accessTokenDuration = 24 * time.Hour
+
 type service struct {
-	repo       Repository
-	tokenMaker security.Maker
+	repo         Repository
+	tokenMaker   security.Maker
+	config       *Config
+	loginLimiter LoginLimiter
+	sessions     SessionService
+	referrals    referral.Enroller
+	signals      fraud.Recorder
 }
 
-// NewService creates a new user service.
-func NewService(repo Repository, tokenMaker security.Maker) Service {
+// NewService creates a new user service. referrals and signals may be nil;
+// signals is told where users register and log in from so linked accounts
+// can be detected.
+func NewService(repo Repository,
+	tokenMaker security.Maker,
+	config *Config,
+	loginLimiter LoginLimiter,
+	sessions SessionService,
+	referrals referral.Enroller,
+	signals fraud.Recorder) Service {
 	return &service{
-		repo:       repo,
-		tokenMaker: tokenMaker,
+		repo:         repo,
+		tokenMaker:   tokenMaker,
+		config:       config,
+		loginLimiter: loginLimiter,
+		sessions:     sessions,
+		referrals:    referrals,
+		signals:      signals,
 	}
 }
 
@@ -40,21 +65,46 @@ func (s *service) Register(ctx context.Context, req *RegisterUserRequest) (*Resp
 		CountryID:    req.CountryID,
 	}
 
+	if s.referrals != nil {
+		if err := s.referrals.PrepareSignup(ctx, user, req.ReferralCode); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.repo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
-	return &Response{
+	if s.referrals != nil {
+		s.referrals.RecordSignup(ctx, user, referral.Signup{IPAddress: req.IPAddress, DeviceID: req.DeviceID})
+	}
+
+	if s.signals != nil {
+		s.signals.RecordSignals(ctx, user.ID,
+			fraud.Signal{Type: models.AccountSignalIP, Value: req.IPAddress},
+			fraud.Signal{Type: models.AccountSignalDevice, Value: req.DeviceID},
+			fraud.Signal{Type: models.AccountSignalPhone, Value: user.Phone})
+	}
+
+	resp := &Response{
 		ID:        user.ID,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
 		Email:     user.Email,
 		Phone:     user.Phone,
 		CreatedAt: user.CreatedAt,
-	}, nil
+	}
+	if user.Metadata != nil {
+		resp.ReferralCode = user.Metadata.ReferralCode
+	}
+	return resp, nil
 }
 
 func (s *service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+	if err := s.loginLimiter.Allow(ctx, req.IPAddress); err != nil {
+		return nil, err
+	}
+
 	var user *models.User
 	var err error
 
@@ -65,26 +115,51 @@ func (s *service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse,
 	}
 
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid credentials")
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, models.ErrRecordNotFound) {
+			_ = s.loginLimiter.RecordFailure(ctx, req.IPAddress)
+			return nil, models.ErrInvalidCredentials
 		}
 		return nil, err
 	}
 
+	// The password is checked first so a wrong guess looks the same whether
+	// or not the account exists or is locked. Guesses while locked are not
+	// counted against the account.
 	if !models.CheckPasswordHash(req.Password, user.PasswordHash) {
-		return nil, errors.New("invalid credentials")
+		_ = s.loginLimiter.RecordFailure(ctx, req.IPAddress)
+		if !user.IsLocked() {
+			if err := s.recordFailedLogin(ctx, user, req); err != nil {
+				return nil, err
+			}
+		}
+		return nil, models.ErrInvalidCredentials
 	}
 
-	version := user.UpdatedAt.UnixNano()
-	if user.UpdatedAt.IsZero() {
-		version = 0
+	if user.IsLocked() {
+		_ = s.loginLimiter.RecordFailure(ctx, req.IPAddress)
+		return nil, models.ErrAccountLocked
 	}
 
-	accessToken, _, err := s.tokenMaker.CreateToken(user.ID, 24*time.Hour, version, security.TokenScopeAccess)
+	user.UpdateLastLogin(net.ParseIP(req.IPAddress))
+	if err := s.repo.UpdateLoginState(ctx, user); err != nil {
+		return nil, err
+	}
+
+	accessToken, payload, err := s.tokenMaker.CreateToken(user.ID, accessTokenDuration, user.TokenVersion, security.TokenScopeAccess)
 	if err != nil {
 		return nil, err
 	}
 
+	if _, err := s.sessions.Start(ctx, user, payload, req.IPAddress, req.UserAgent); err != nil {
+		return nil, err
+	}
+
+	if s.signals != nil {
+		s.signals.RecordSignals(ctx, user.ID,
+			fraud.Signal{Type: models.AccountSignalIP, Value: req.IPAddress},
+			fraud.Signal{Type: models.AccountSignalDevice, Value: req.DeviceID})
+	}
+
 	return &LoginResponse{
 		AccessToken: accessToken,
 		User: Response{
@@ -96,6 +171,43 @@ func (s *service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse,
 	}, nil
 }
 
+// recordFailedLogin bumps the failure counter and applies the progressive
+// lockout policy, writing an audit entry whenever the account becomes locked.
+// The counter only resets on a successful login, so every MaxFailedLogins
+// further failures lock the account for longer.
+// The count comes back from the database, so parallel guesses cannot reuse a
+// stale count to stay under the lockout.
+func (s *service) recordFailedLogin(ctx context.Context, user *models.User, req *LoginRequest) error {
+	attempts, err := s.repo.IncrementFailedLogins(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	user.FailedLoginAttempts = attempts
+
+	// The lock window is owned by the configured policy rather than the model default.
+	lockout := s.config.LockoutFor(attempts)
+	if lockout <= 0 {
+		return nil
+	}
+	lockedUntil := time.Now().Add(lockout)
+	user.LockedUntil = &lockedUntil
+	if err := s.repo.LockUntil(ctx, user.ID, lockedUntil); err != nil {
+		return err
+	}
+
+	auditLog := models.CreateUserAuditLog(user.ID,
+		models.AuditActionAccountLocked,
+		models.AuditResourceUser,
+		&user.ID,
+		nil,
+		models.AuditValues{
+			"failed_login_attempts": user.FailedLoginAttempts,
+			"locked_until":          user.LockedUntil,
+		},
+		net.ParseIP(req.IPAddress), req.UserAgent)
+	return s.repo.CreateAuditLog(ctx, auditLog)
+}
+
 func (s *service) RequestPasswordReset(ctx context.Context, email string) error {
 	// In a real application, you would generate a unique, short-lived token,
 	// store it with the user's ID, and email a link containing the token.
@@ -113,9 +225,9 @@ func (s *service) ResetPassword(_ context.Context, _, _ string) error {
 	// TODO: Implement password reset logic
 	// 1. Validate the token
 	// 2. Find the user associated with the token
-	// 3. Hash the new password
-	// 4. Update the user's password in the database
-	// 5. Invalidate the reset token
+	// 3. Store the new password with changePassword, which also bumps the
+	//    token version so every existing token stops working
+	// 4. Invalidate the reset token
 	return nil
 }
 
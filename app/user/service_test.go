@@ -3,6 +3,8 @@ package user
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
 
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/referral"
+	"github.com/joefazee/neo/internal/cache"
 	"github.com/joefazee/neo/internal/security"
 	"github.com/joefazee/neo/models"
 )
@@ -20,12 +25,20 @@ type ServiceTestSuite struct {
 	service    Service
 	repo       *MockRepo
 	tokenMaker *security.MockMaker
+	config     *Config
+	limiter    LoginLimiter
+	sessions   *MockSessionService
 }
 
 func (suite *ServiceTestSuite) SetupTest() {
 	suite.repo = &MockRepo{}
 	suite.tokenMaker = &security.MockMaker{}
-	suite.service = NewService(suite.repo, suite.tokenMaker)
+	suite.config = GetDefaultConfig()
+	suite.limiter = NewLoginLimiter(cache.NewMemoryCache[string](), suite.config)
+	suite.sessions = &MockSessionService{}
+	suite.sessions.On("Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.UserSession{}, nil).Maybe()
+	suite.service = NewService(suite.repo, suite.tokenMaker, suite.config, suite.limiter, suite.sessions, nil, nil)
 }
 
 func TestUserService(t *testing.T) {
@@ -72,6 +85,93 @@ func (suite *ServiceTestSuite) TestRegister_RepositoryError() {
 	suite.Nil(result)
 }
 
+type MockEnroller struct {
+	mock.Mock
+}
+
+func (m *MockEnroller) PrepareSignup(ctx context.Context, user *models.User, referralCode string) error {
+	return m.Called(ctx, user, referralCode).Error(0)
+}
+
+func (m *MockEnroller) RecordSignup(ctx context.Context, user *models.User, signup referral.Signup) {
+	m.Called(ctx, user, signup)
+}
+
+func (suite *ServiceTestSuite) TestRegister_WithReferralCode() {
+	enroller := &MockEnroller{}
+	suite.service = NewService(suite.repo, suite.tokenMaker, suite.config, suite.limiter, suite.sessions, enroller, nil)
+	req := &RegisterUserRequest{
+		Email:        "jane@example.com",
+		Password:     "password123",
+		ReferralCode: "ABCD2345",
+		IPAddress:    "203.0.113.7",
+		DeviceID:     "device-1",
+	}
+
+	enroller.On("PrepareSignup", mock.Anything, mock.AnythingOfType("*models.User"), "ABCD2345").
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*models.User).Metadata = &models.UserMetadata{ReferralCode: "WXYZ6789", ReferredBy: "ABCD2345"}
+		}).Return(nil)
+	suite.repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	enroller.On("RecordSignup", mock.Anything, mock.AnythingOfType("*models.User"),
+		referral.Signup{IPAddress: "203.0.113.7", DeviceID: "device-1"})
+
+	result, err := suite.service.Register(context.Background(), req)
+
+	suite.NoError(err)
+	suite.Equal("WXYZ6789", result.ReferralCode)
+	enroller.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceTestSuite) TestRegister_InvalidReferralCode() {
+	enroller := &MockEnroller{}
+	suite.service = NewService(suite.repo, suite.tokenMaker, suite.config, suite.limiter, suite.sessions, enroller, nil)
+	req := &RegisterUserRequest{Password: "password123", ReferralCode: "NOPE"}
+
+	enroller.On("PrepareSignup", mock.Anything, mock.Anything, "NOPE").Return(models.ErrInvalidReferralCode)
+
+	result, err := suite.service.Register(context.Background(), req)
+
+	suite.ErrorIs(err, models.ErrInvalidReferralCode)
+	suite.Nil(result)
+	suite.repo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+type MockRecorder struct {
+	mock.Mock
+}
+
+func (m *MockRecorder) RecordSignals(ctx context.Context, userID uuid.UUID, signals ...fraud.Signal) {
+	m.Called(ctx, userID, signals)
+}
+
+func (suite *ServiceTestSuite) TestRegister_RecordsSignals() {
+	recorder := &MockRecorder{}
+	suite.service = NewService(suite.repo, suite.tokenMaker, suite.config, suite.limiter, suite.sessions, nil, recorder)
+	userID := uuid.New()
+	req := &RegisterUserRequest{
+		Email:       "jane@example.com",
+		PhoneNumber: "+2348000000000",
+		Password:    "password123",
+		IPAddress:   "203.0.113.7",
+		DeviceID:    "device-1",
+	}
+
+	suite.repo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.User).ID = userID
+	}).Return(nil)
+	recorder.On("RecordSignals", mock.Anything, userID, []fraud.Signal{
+		{Type: models.AccountSignalIP, Value: "203.0.113.7"},
+		{Type: models.AccountSignalDevice, Value: "device-1"},
+		{Type: models.AccountSignalPhone, Value: "+2348000000000"},
+	}).Return()
+
+	_, err := suite.service.Register(context.Background(), req)
+
+	suite.NoError(err)
+	recorder.AssertExpectations(suite.T())
+}
+
 func (suite *ServiceTestSuite) TestLogin_SuccessWithEmail() {
 	user := &models.User{
 		ID:           uuid.New(),
@@ -83,6 +183,7 @@ func (suite *ServiceTestSuite) TestLogin_SuccessWithEmail() {
 	}
 
 	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	suite.repo.On("UpdateLoginState", mock.Anything, user).Return(nil)
 	suite.tokenMaker.On("CreateToken", user.ID, 24*time.Hour, mock.AnythingOfType("int64"), security.TokenScopeAccess).Return("token123", &security.Payload{}, nil)
 
 	req := &LoginRequest{
@@ -107,6 +208,7 @@ func (suite *ServiceTestSuite) TestLogin_SuccessWithPhone() {
 	}
 
 	suite.repo.On("GetByPhone", mock.Anything, "+1234567890").Return(user, nil)
+	suite.repo.On("UpdateLoginState", mock.Anything, user).Return(nil)
 	suite.tokenMaker.On("CreateToken", user.ID, 24*time.Hour, mock.AnythingOfType("int64"), security.TokenScopeAccess).Return("token123", &security.Payload{}, nil)
 
 	req := &LoginRequest{
@@ -143,6 +245,7 @@ func (suite *ServiceTestSuite) TestLogin_InvalidPassword() {
 	}
 
 	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	suite.repo.On("IncrementFailedLogins", mock.Anything, user.ID).Return(1, nil)
 
 	req := &LoginRequest{
 		Identity: "john@example.com",
@@ -165,6 +268,7 @@ func (suite *ServiceTestSuite) TestLogin_TokenCreationError() {
 	}
 
 	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	suite.repo.On("UpdateLoginState", mock.Anything, user).Return(nil)
 	suite.tokenMaker.On("CreateToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil, errors.New("token error"))
 
 	req := &LoginRequest{
@@ -238,16 +342,17 @@ func (suite *ServiceTestSuite) TestAssignRole_Error() {
 	suite.Error(err)
 }
 
-func (suite *ServiceTestSuite) TestLogin_ZeroUpdatedAt() {
+func (suite *ServiceTestSuite) TestLogin_UsesTokenVersion() {
 	user := &models.User{
 		ID:           uuid.New(),
 		Email:        "john@example.com",
 		PasswordHash: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi",
-		UpdatedAt:    time.Time{},
+		TokenVersion: 7,
 	}
 
 	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
-	suite.tokenMaker.On("CreateToken", user.ID, 24*time.Hour, int64(0), security.TokenScopeAccess).Return("token123", &security.Payload{}, nil)
+	suite.repo.On("UpdateLoginState", mock.Anything, user).Return(nil)
+	suite.tokenMaker.On("CreateToken", user.ID, 24*time.Hour, int64(7), security.TokenScopeAccess).Return("token123", &security.Payload{}, nil)
 
 	req := &LoginRequest{
 		Identity: "john@example.com",
@@ -268,3 +373,216 @@ func (suite *ServiceTestSuite) TestRegister_HashPasswordError() {
 	suite.Error(err)
 	suite.Nil(result)
 }
+
+func (suite *ServiceTestSuite) TestLogin_RecordsLastLoginIP() {
+	user := &models.User{
+		ID:                  uuid.New(),
+		Email:               "john@example.com",
+		PasswordHash:        "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi",
+		FailedLoginAttempts: 3,
+		UpdatedAt:           time.Now(),
+	}
+
+	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	suite.repo.On("UpdateLoginState", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+		return u.LastLoginAt != nil && u.LastLoginIP.String() == "10.0.0.1" && u.FailedLoginAttempts == 0
+	})).Return(nil)
+	suite.tokenMaker.On("CreateToken", user.ID, 24*time.Hour, mock.AnythingOfType("int64"), security.TokenScopeAccess).Return("token123", &security.Payload{}, nil)
+
+	req := &LoginRequest{
+		Identity:  "john@example.com",
+		Password:  "password",
+		IPAddress: "10.0.0.1",
+	}
+
+	result, err := suite.service.Login(context.Background(), req)
+
+	suite.NoError(err)
+	suite.NotNil(result)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceTestSuite) TestLogin_LocksAccountAfterMaxFailures() {
+	user := &models.User{
+		ID:                  uuid.New(),
+		Email:               "john@example.com",
+		PasswordHash:        "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi",
+		FailedLoginAttempts: suite.config.MaxFailedLogins - 1,
+	}
+
+	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	suite.repo.On("IncrementFailedLogins", mock.Anything, user.ID).Return(suite.config.MaxFailedLogins, nil)
+	suite.repo.On("LockUntil", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.repo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l *models.AuditLog) bool {
+		return l.Action == models.AuditActionAccountLocked && *l.ResourceID == user.ID
+	})).Return(nil)
+
+	req := &LoginRequest{
+		Identity:  "john@example.com",
+		Password:  "wrongpassword",
+		IPAddress: "10.0.0.1",
+	}
+
+	result, err := suite.service.Login(context.Background(), req)
+
+	suite.ErrorIs(err, models.ErrInvalidCredentials)
+	suite.Nil(result)
+	suite.True(user.IsLocked())
+	suite.WithinDuration(time.Now().Add(suite.config.LockoutDuration), *user.LockedUntil, time.Minute)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceTestSuite) TestLogin_ConcurrentFailuresAreAllCounted() {
+	const guesses = 20
+	suite.config.MaxLoginAttemptsPerIP = guesses
+	userID := uuid.New()
+	var attempts atomic.Int32
+
+	// Every request loads its own copy of the row, all showing no failures yet
+	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(func() *models.User {
+		return &models.User{
+			ID:           userID,
+			Email:        "john@example.com",
+			PasswordHash: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi",
+		}
+	}, nil)
+	suite.repo.On("IncrementFailedLogins", mock.Anything, userID).Return(func() int {
+		return int(attempts.Add(1))
+	}, nil)
+	suite.repo.On("LockUntil", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.repo.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := suite.service.Login(context.Background(), &LoginRequest{
+				Identity:  "john@example.com",
+				Password:  "wrongpassword",
+				IPAddress: "10.0.0.9",
+			})
+			suite.ErrorIs(err, models.ErrInvalidCredentials)
+		}()
+	}
+	wg.Wait()
+
+	suite.repo.AssertNumberOfCalls(suite.T(), "IncrementFailedLogins", guesses)
+	suite.repo.AssertNumberOfCalls(suite.T(), "LockUntil", guesses/suite.config.MaxFailedLogins)
+	suite.ErrorIs(suite.limiter.Allow(context.Background(), "10.0.0.9"), models.ErrTooManyLoginAttempts,
+		"every failure from the IP is counted")
+}
+
+func (suite *ServiceTestSuite) TestLogin_LockedAccount() {
+	lockedUntil := time.Now().Add(time.Hour)
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        "john@example.com",
+		PasswordHash: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi",
+		LockedUntil:  &lockedUntil,
+	}
+
+	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+
+	req := &LoginRequest{
+		Identity: "john@example.com",
+		Password: "password",
+	}
+
+	result, err := suite.service.Login(context.Background(), req)
+
+	suite.ErrorIs(err, models.ErrAccountLocked)
+	suite.Nil(result)
+	suite.repo.AssertNotCalled(suite.T(), "UpdateLoginState", mock.Anything, mock.Anything)
+	suite.tokenMaker.AssertNotCalled(suite.T(), "CreateToken")
+}
+
+func (suite *ServiceTestSuite) TestLogin_LockedAccountWrongPassword() {
+	lockedUntil := time.Now().Add(time.Hour)
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        "john@example.com",
+		PasswordHash: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi",
+		LockedUntil:  &lockedUntil,
+	}
+
+	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+
+	req := &LoginRequest{
+		Identity: "john@example.com",
+		Password: "wrongpassword",
+	}
+
+	result, err := suite.service.Login(context.Background(), req)
+
+	suite.ErrorIs(err, models.ErrInvalidCredentials, "a wrong guess does not reveal the lock")
+	suite.Nil(result)
+	suite.repo.AssertNotCalled(suite.T(), "IncrementFailedLogins", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceTestSuite) TestLogin_IPLimitExceeded() {
+	ctx := context.Background()
+	for i := 0; i < suite.config.MaxLoginAttemptsPerIP; i++ {
+		suite.NoError(suite.limiter.RecordFailure(ctx, "10.0.0.2"))
+	}
+
+	req := &LoginRequest{
+		Identity:  "john@example.com",
+		Password:  "password",
+		IPAddress: "10.0.0.2",
+	}
+
+	result, err := suite.service.Login(ctx, req)
+
+	suite.ErrorIs(err, models.ErrTooManyLoginAttempts)
+	suite.Nil(result)
+	suite.repo.AssertNotCalled(suite.T(), "GetByEmail", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceTestSuite) TestLogin_StartsSession() {
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        "john@example.com",
+		PasswordHash: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi",
+	}
+	payload := &security.Payload{ID: uuid.New(), UserID: user.ID}
+
+	suite.sessions = &MockSessionService{}
+	suite.service = NewService(suite.repo, suite.tokenMaker, suite.config, suite.limiter, suite.sessions, nil, nil)
+
+	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	suite.repo.On("UpdateLoginState", mock.Anything, user).Return(nil)
+	suite.tokenMaker.On("CreateToken", user.ID, 24*time.Hour, mock.AnythingOfType("int64"), security.TokenScopeAccess).Return("token123", payload, nil)
+	suite.sessions.On("Start", mock.Anything, user, payload, "10.0.0.1", "curl/8.4.0").Return(&models.UserSession{}, nil)
+
+	_, err := suite.service.Login(context.Background(), &LoginRequest{
+		Identity:  "john@example.com",
+		Password:  "password",
+		IPAddress: "10.0.0.1",
+		UserAgent: "curl/8.4.0",
+	})
+
+	suite.NoError(err)
+	suite.sessions.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceTestSuite) TestLogin_SessionError() {
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        "john@example.com",
+		PasswordHash: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi",
+	}
+
+	suite.sessions = &MockSessionService{}
+	suite.service = NewService(suite.repo, suite.tokenMaker, suite.config, suite.limiter, suite.sessions, nil, nil)
+
+	suite.repo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	suite.repo.On("UpdateLoginState", mock.Anything, user).Return(nil)
+	suite.tokenMaker.On("CreateToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("token123", &security.Payload{}, nil)
+	suite.sessions.On("Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+	result, err := suite.service.Login(context.Background(), &LoginRequest{Identity: "john@example.com", Password: "password"})
+
+	suite.Error(err)
+	suite.Nil(result)
+}
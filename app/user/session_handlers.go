@@ -0,0 +1,87 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/models"
+)
+
+type SessionHandler struct {
+	sessions SessionService
+	logger   logger.Logger
+}
+
+func NewSessionHandler(sessions SessionService, logger logger.Logger) *SessionHandler {
+	return &SessionHandler{sessions: sessions, logger: logger}
+}
+
+// ListSessions godoc
+// @Summary List active sessions
+// @Description List the devices currently logged in to the authenticated user's account
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} api.Response{data=[]SessionResponse}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	// API-key requests carry no token; no session is flagged as current then.
+	sessions, err := h.sessions.List(c.Request.Context(), userID, currentTokenID(c))
+	if err != nil {
+		h.logger.Error(err, logger.Fields{"handler": "ListSessions", "user_id": userID})
+		api.InternalErrorResponse(c, "Failed to retrieve sessions")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Log out one of the authenticated user's devices
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 401 {object} api.Response{error=api.ErrorInfo}
+// @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 500 {object} api.Response{error=api.ErrorInfo}
+// @Router /api/v1/users/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, ok := api.UserIDFromContext(c)
+	if !ok {
+		api.UnauthorizedResponse(c)
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		api.BadRequestResponse(c, "Invalid session ID format")
+		return
+	}
+
+	if err := h.sessions.Revoke(c.Request.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			api.NotFoundResponse(c, "Session")
+			return
+		}
+		h.logger.Error(err, logger.Fields{"handler": "RevokeSession", "session_id": sessionID})
+		api.InternalErrorResponse(c, "Failed to revoke session")
+		return
+	}
+
+	api.SuccessResponse(c, 200, "Session revoked successfully", nil)
+}
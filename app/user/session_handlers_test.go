@@ -0,0 +1,99 @@
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+)
+
+func newSessionTestContext(method, path string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, path, http.NoBody)
+	return c, w
+}
+
+func TestSessionHandler_ListSessions(t *testing.T) {
+	sessions := &MockSessionService{}
+	handler := NewSessionHandler(sessions, logger.NewNullLogger())
+	userID := uuid.New()
+	payload := &security.Payload{ID: uuid.New(), UserID: userID}
+
+	sessions.On("List", mock.Anything, userID, payload.ID).Return([]SessionResponse{{ID: uuid.New(), Current: true}}, nil)
+
+	c, w := newSessionTestContext("GET", "/users/sessions")
+	c.Set("userID", userID)
+	ContextSetToken(c, payload)
+
+	handler.ListSessions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	sessions.AssertExpectations(t)
+}
+
+func TestSessionHandler_ListSessions_Unauthorized(t *testing.T) {
+	handler := NewSessionHandler(&MockSessionService{}, logger.NewNullLogger())
+
+	c, w := newSessionTestContext("GET", "/users/sessions")
+
+	handler.ListSessions(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSessionHandler_RevokeSession(t *testing.T) {
+	sessions := &MockSessionService{}
+	handler := NewSessionHandler(sessions, logger.NewNullLogger())
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	sessions.On("Revoke", mock.Anything, userID, sessionID).Return(nil)
+
+	c, w := newSessionTestContext("DELETE", "/users/sessions/"+sessionID.String())
+	c.Params = gin.Params{{Key: "id", Value: sessionID.String()}}
+	c.Set("userID", userID)
+
+	handler.RevokeSession(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	sessions.AssertExpectations(t)
+}
+
+func TestSessionHandler_RevokeSession_NotFound(t *testing.T) {
+	sessions := &MockSessionService{}
+	handler := NewSessionHandler(sessions, logger.NewNullLogger())
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	sessions.On("Revoke", mock.Anything, userID, sessionID).Return(models.ErrRecordNotFound)
+
+	c, w := newSessionTestContext("DELETE", "/users/sessions/"+sessionID.String())
+	c.Params = gin.Params{{Key: "id", Value: sessionID.String()}}
+	c.Set("userID", userID)
+
+	handler.RevokeSession(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSessionHandler_RevokeSession_InvalidID(t *testing.T) {
+	handler := NewSessionHandler(&MockSessionService{}, logger.NewNullLogger())
+
+	c, w := newSessionTestContext("DELETE", "/users/sessions/bad")
+	c.Params = gin.Params{{Key: "id", Value: "bad"}}
+	c.Set("userID", uuid.New())
+
+	handler.RevokeSession(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,272 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/logger"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+)
+
+// tokenStatusTTL bounds how long a token's blacklist status is trusted from cache.
+// Revocations through this service update the cache directly.
+const tokenStatusTTL = 5 * time.Minute
+
+const (
+	tokenStatusActive  = "active"
+	tokenStatusRevoked = "revoked"
+)
+
+// NewDeviceNotifier is called when a user logs in from a device not seen before.
+type NewDeviceNotifier interface {
+	NotifyNewDevice(ctx context.Context, user *models.User, session *models.UserSession) error
+}
+
+// SessionService manages the session record kept for every issued access token.
+type SessionService interface {
+	Start(ctx context.Context, user *models.User, payload *security.Payload, ip, userAgent string) (*models.UserSession, error)
+	List(ctx context.Context, userID uuid.UUID, currentTokenID uuid.UUID) ([]SessionResponse, error)
+	Revoke(ctx context.Context, userID, sessionID uuid.UUID) error
+	// RevokeOthers ends every active session except the one using currentTokenID.
+	RevokeOthers(ctx context.Context, userID, currentTokenID uuid.UUID) error
+	// Validate rejects blacklisted tokens and records activity, throttled by
+	// Config.SessionTouchInterval.
+	Validate(ctx context.Context, payload *security.Payload) error
+}
+
+type sessionService struct {
+	repo     Repository
+	cache    cache.Cache[string]
+	notifier NewDeviceNotifier
+	config   *Config
+}
+
+// NewSessionService creates a session service.
+func NewSessionService(repo Repository, c cache.Cache[string], notifier NewDeviceNotifier, config *Config) SessionService {
+	return &sessionService{repo: repo, cache: c, notifier: notifier, config: config}
+}
+
+// Start persists a session for a freshly issued token and fires the new-device hook.
+func (s *sessionService) Start(ctx context.Context,
+	user *models.User,
+	payload *security.Payload,
+	ip, userAgent string) (*models.UserSession, error) {
+	deviceName := DeviceName(userAgent)
+
+	knownDevice, err := s.repo.HasSessionForDevice(ctx, user.ID, deviceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check device: %w", err)
+	}
+
+	now := time.Now()
+	session := &models.UserSession{
+		UserID:     user.ID,
+		TokenJTI:   payload.ID.String(),
+		DeviceName: deviceName,
+		UserAgent:  userAgent,
+		IPAddress:  ip,
+		LastSeenAt: now,
+		ExpiresAt:  payload.ExpiredAt,
+	}
+	if err := session.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	_ = s.cache.Set(ctx, s.touchKey(session.TokenJTI), now.Format(time.RFC3339Nano), s.config.SessionTouchInterval)
+
+	if !knownDevice && s.notifier != nil {
+		// A failed notification must not block the login.
+		_ = s.notifier.NotifyNewDevice(ctx, user, session)
+	}
+	return session, nil
+}
+
+func (s *sessionService) List(ctx context.Context, userID, currentTokenID uuid.UUID) ([]SessionResponse, error) {
+	sessions, err := s.repo.GetActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]SessionResponse, 0, len(sessions))
+	for i := range sessions {
+		responses = append(responses, ToSessionResponse(&sessions[i], currentTokenID))
+	}
+	return responses, nil
+}
+
+// Revoke ends one of the user's sessions by blacklisting its token.
+func (s *sessionService) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.repo.GetSessionByID(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !session.IsActive() {
+		return models.ErrRecordNotFound
+	}
+	return s.revoke(ctx, session)
+}
+
+func (s *sessionService) RevokeOthers(ctx context.Context, userID, currentTokenID uuid.UUID) error {
+	sessions, err := s.repo.GetActiveSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		if currentTokenID != uuid.Nil && sessions[i].TokenJTI == currentTokenID.String() {
+			continue
+		}
+		if err := s.revoke(ctx, &sessions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sessionService) revoke(ctx context.Context, session *models.UserSession) error {
+	now := time.Now()
+	session.RevokedAt = &now
+	entry := models.CreateBlacklistEntry(session.TokenJTI, session.UserID, session.ExpiresAt)
+	if err := s.repo.RevokeSession(ctx, session, entry); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	_ = s.cache.Set(ctx, s.statusKey(session.TokenJTI), tokenStatusRevoked, time.Until(session.ExpiresAt))
+	return nil
+}
+
+func (s *sessionService) Validate(ctx context.Context, payload *security.Payload) error {
+	jti := payload.ID.String()
+
+	revoked, err := s.isRevoked(ctx, jti)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return models.ErrTokenRevoked
+	}
+
+	// Impersonation tokens have no session of their own and must not show as user activity
+	if !payload.IsImpersonation() {
+		s.touch(ctx, payload.UserID, jti)
+	}
+	return nil
+}
+
+func (s *sessionService) isRevoked(ctx context.Context, jti string) (bool, error) {
+	if status, err := s.cache.Get(ctx, s.statusKey(jti)); err == nil {
+		return status == tokenStatusRevoked, nil
+	}
+
+	blacklisted, err := s.repo.IsTokenBlacklisted(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	status := tokenStatusActive
+	if blacklisted {
+		status = tokenStatusRevoked
+	}
+	_ = s.cache.Set(ctx, s.statusKey(jti), status, tokenStatusTTL)
+	return blacklisted, nil
+}
+
+// touch updates last-seen at most once per SessionTouchInterval. Failures are ignored.
+func (s *sessionService) touch(ctx context.Context, userID uuid.UUID, jti string) {
+	if _, err := s.cache.Get(ctx, s.touchKey(jti)); err == nil {
+		return
+	}
+
+	now := time.Now()
+	if err := s.repo.TouchSession(ctx, userID, jti, now); err != nil {
+		return
+	}
+	_ = s.cache.Set(ctx, s.touchKey(jti), now.Format(time.RFC3339Nano), s.config.SessionTouchInterval)
+}
+
+func (s *sessionService) statusKey(jti string) string {
+	return fmt.Sprintf("token:%s:status", jti)
+}
+
+func (s *sessionService) touchKey(jti string) string {
+	return fmt.Sprintf("session:%s:touched", jti)
+}
+
+// logNotifier is the default NewDeviceNotifier; it records the event until a
+// delivery channel (email, push) is wired in.
+type logNotifier struct {
+	logger logger.Logger
+}
+
+// NewLogNotifier creates a NewDeviceNotifier that writes to the logger.
+func NewLogNotifier(lg logger.Logger) NewDeviceNotifier {
+	return &logNotifier{logger: lg}
+}
+
+func (n *logNotifier) NotifyNewDevice(_ context.Context, user *models.User, session *models.UserSession) error {
+	n.logger.Info("new device login", logger.Fields{
+		"user_id":    user.ID,
+		"device":     session.DeviceName,
+		"ip_address": session.IPAddress,
+	})
+	return nil
+}
+
+// DeviceName builds a short, human readable device label from a User-Agent.
+func DeviceName(userAgent string) string {
+	ua := strings.TrimSpace(userAgent)
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	browser := detect(ua, []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	})
+	os := detect(ua, []struct{ token, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	})
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case os != "":
+		return os
+	case browser != "":
+		return browser
+	}
+
+	// Non-browser clients such as "curl/8.4.0" or "neo-bot/1.2 (+https://…)"
+	product := strings.Fields(ua)[0]
+	if i := strings.Index(product, "/"); i > 0 {
+		product = product[:i]
+	}
+	if len(product) > 100 {
+		product = product[:100]
+	}
+	return product
+}
+
+func detect(ua string, candidates []struct{ token, name string }) string {
+	for _, c := range candidates {
+		if strings.Contains(ua, c.token) {
+			return c.name
+		}
+	}
+	return ""
+}
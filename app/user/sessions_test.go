@@ -0,0 +1,245 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/internal/security"
+	"github.com/joefazee/neo/models"
+)
+
+type MockSessionService struct {
+	mock.Mock
+}
+
+func (m *MockSessionService) Start(ctx context.Context,
+	user *models.User,
+	payload *security.Payload,
+	ip, userAgent string) (*models.UserSession, error) {
+	args := m.Called(ctx, user, payload, ip, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSession), args.Error(1)
+}
+
+func (m *MockSessionService) List(ctx context.Context, userID, currentTokenID uuid.UUID) ([]SessionResponse, error) {
+	args := m.Called(ctx, userID, currentTokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]SessionResponse), args.Error(1)
+}
+
+func (m *MockSessionService) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockSessionService) RevokeOthers(ctx context.Context, userID, currentTokenID uuid.UUID) error {
+	args := m.Called(ctx, userID, currentTokenID)
+	return args.Error(0)
+}
+
+func (m *MockSessionService) Validate(ctx context.Context, payload *security.Payload) error {
+	args := m.Called(ctx, payload)
+	return args.Error(0)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) NotifyNewDevice(ctx context.Context, user *models.User, session *models.UserSession) error {
+	args := m.Called(ctx, user, session)
+	return args.Error(0)
+}
+
+func newTestSessionService(repo *MockRepo, notifier NewDeviceNotifier) SessionService {
+	return NewSessionService(repo, cache.NewMemoryCache[string](), notifier, GetDefaultConfig())
+}
+
+const testChromeMacUA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 " +
+	"(KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+func TestDeviceName(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{testChromeMacUA, "Chrome on macOS"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) " +
+			"Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.51", "Edge on Windows"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 " +
+			"(KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1", "Safari on iPhone"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "Firefox on Linux"},
+		{"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) " +
+			"Chrome/124.0.0.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"curl/8.4.0", "curl"},
+		{"", "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DeviceName(tt.ua), tt.ua)
+	}
+}
+
+func TestSessionService_StartNotifiesNewDevice(t *testing.T) {
+	repo := &MockRepo{}
+	notifier := &MockNotifier{}
+	svc := newTestSessionService(repo, notifier)
+	user := &models.User{ID: uuid.New()}
+	payload := &security.Payload{ID: uuid.New(), UserID: user.ID, ExpiredAt: time.Now().Add(time.Hour)}
+
+	repo.On("HasSessionForDevice", mock.Anything, user.ID, "Chrome on macOS").Return(false, nil)
+	repo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *models.UserSession) bool {
+		return s.TokenJTI == payload.ID.String() && s.IPAddress == "10.0.0.1" && s.ExpiresAt.Equal(payload.ExpiredAt)
+	})).Return(nil)
+	notifier.On("NotifyNewDevice", mock.Anything, user, mock.Anything).Return(errors.New("smtp down"))
+
+	session, err := svc.Start(context.Background(), user, payload, "10.0.0.1", testChromeMacUA)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Chrome on macOS", session.DeviceName)
+	notifier.AssertExpectations(t)
+}
+
+func TestSessionService_StartKnownDevice(t *testing.T) {
+	repo := &MockRepo{}
+	notifier := &MockNotifier{}
+	svc := newTestSessionService(repo, notifier)
+	user := &models.User{ID: uuid.New()}
+	payload := &security.Payload{ID: uuid.New(), UserID: user.ID, ExpiredAt: time.Now().Add(time.Hour)}
+
+	repo.On("HasSessionForDevice", mock.Anything, user.ID, "Chrome on macOS").Return(true, nil)
+	repo.On("CreateSession", mock.Anything, mock.Anything).Return(nil)
+
+	_, err := svc.Start(context.Background(), user, payload, "10.0.0.1", testChromeMacUA)
+
+	assert.NoError(t, err)
+	notifier.AssertNotCalled(t, "NotifyNewDevice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSessionService_List(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestSessionService(repo, nil)
+	userID := uuid.New()
+	current := uuid.New()
+
+	repo.On("GetActiveSessions", mock.Anything, userID).Return([]models.UserSession{
+		{ID: uuid.New(), TokenJTI: current.String()},
+		{ID: uuid.New(), TokenJTI: uuid.New().String()},
+	}, nil)
+
+	sessions, err := svc.List(context.Background(), userID, current)
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.True(t, sessions[0].Current)
+	assert.False(t, sessions[1].Current)
+}
+
+func TestSessionService_RevokeBlacklistsToken(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestSessionService(repo, nil)
+	userID := uuid.New()
+	jti := uuid.New()
+	session := &models.UserSession{ID: uuid.New(), UserID: userID, TokenJTI: jti.String(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	repo.On("GetSessionByID", mock.Anything, userID, session.ID).Return(session, nil)
+	repo.On("RevokeSession", mock.Anything, session, mock.MatchedBy(func(e *models.TokenBlacklist) bool {
+		return e.TokenJTI == jti.String() && e.UserID == userID
+	})).Return(nil)
+
+	assert.NoError(t, svc.Revoke(context.Background(), userID, session.ID))
+	assert.NotNil(t, session.RevokedAt)
+
+	// The revocation is served from cache without a blacklist lookup.
+	err := svc.Validate(context.Background(), &security.Payload{ID: jti, UserID: userID})
+	assert.ErrorIs(t, err, models.ErrTokenRevoked)
+	repo.AssertNotCalled(t, "IsTokenBlacklisted", mock.Anything, mock.Anything)
+}
+
+func TestSessionService_RevokeAlreadyRevoked(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestSessionService(repo, nil)
+	userID := uuid.New()
+	revokedAt := time.Now()
+	session := &models.UserSession{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+
+	repo.On("GetSessionByID", mock.Anything, userID, session.ID).Return(session, nil)
+
+	err := svc.Revoke(context.Background(), userID, session.ID)
+
+	assert.ErrorIs(t, err, models.ErrRecordNotFound)
+	repo.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSessionService_RevokeOthersKeepsCurrent(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestSessionService(repo, nil)
+	userID := uuid.New()
+	current, other := uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	repo.On("GetActiveSessions", mock.Anything, userID).Return([]models.UserSession{
+		{ID: uuid.New(), UserID: userID, TokenJTI: current.String(), ExpiresAt: expiresAt},
+		{ID: uuid.New(), UserID: userID, TokenJTI: other.String(), ExpiresAt: expiresAt},
+	}, nil)
+	repo.On("RevokeSession", mock.Anything, mock.MatchedBy(func(s *models.UserSession) bool {
+		return s.TokenJTI == other.String()
+	}), mock.Anything).Return(nil).Once()
+
+	require.NoError(t, svc.RevokeOthers(context.Background(), userID, current))
+	repo.AssertExpectations(t)
+
+	err := svc.Validate(context.Background(), &security.Payload{ID: other, UserID: userID})
+	assert.ErrorIs(t, err, models.ErrTokenRevoked)
+}
+
+func TestSessionService_ValidateThrottlesTouch(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestSessionService(repo, nil)
+	payload := &security.Payload{ID: uuid.New(), UserID: uuid.New()}
+
+	repo.On("IsTokenBlacklisted", mock.Anything, payload.ID.String()).Return(false, nil).Once()
+	repo.On("TouchSession", mock.Anything, payload.UserID, payload.ID.String(), mock.Anything).Return(nil).Once()
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, svc.Validate(context.Background(), payload))
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestSessionService_ValidateImpersonationSkipsTouch(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestSessionService(repo, nil)
+	payload, err := security.NewImpersonationPayload(uuid.New(), uuid.New(), time.Minute, 0)
+	require.NoError(t, err)
+
+	repo.On("IsTokenBlacklisted", mock.Anything, payload.ID.String()).Return(false, nil)
+
+	assert.NoError(t, svc.Validate(context.Background(), payload))
+	repo.AssertNotCalled(t, "TouchSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSessionService_ValidateBlacklisted(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestSessionService(repo, nil)
+	payload := &security.Payload{ID: uuid.New(), UserID: uuid.New()}
+
+	repo.On("IsTokenBlacklisted", mock.Anything, payload.ID.String()).Return(true, nil)
+
+	err := svc.Validate(context.Background(), payload)
+
+	assert.ErrorIs(t, err, models.ErrTokenRevoked)
+	repo.AssertNotCalled(t, "TouchSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
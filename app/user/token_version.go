@@ -0,0 +1,67 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joefazee/neo/internal/cache"
+)
+
+// tokenVersionTTL bounds how long a cached version is trusted. Revoke refreshes
+// the cache it was given, which Redis shares between instances. With the
+// in-memory cache each instance has its own copy, so the others keep
+// accepting revoked tokens until their cached version expires.
+const tokenVersionTTL = 10 * time.Minute
+
+// TokenVersions tracks the per-user token version. Tokens carry the version they
+// were issued with; bumping it invalidates every token issued before.
+type TokenVersions interface {
+	Current(ctx context.Context, userID uuid.UUID) (int64, error)
+	Revoke(ctx context.Context, userID uuid.UUID) error
+}
+
+type tokenVersions struct {
+	repo  Repository
+	cache cache.Cache[string]
+}
+
+// NewTokenVersions creates a cache backed token version store.
+func NewTokenVersions(repo Repository, c cache.Cache[string]) TokenVersions {
+	return &tokenVersions{repo: repo, cache: c}
+}
+
+// Current returns the user's token version, hitting the database only on a cache miss.
+func (t *tokenVersions) Current(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if cached, err := t.cache.Get(ctx, t.key(userID)); err == nil {
+		if version, err := strconv.ParseInt(cached, 10, 64); err == nil {
+			return version, nil
+		}
+	}
+
+	version, err := t.repo.GetTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	_ = t.cache.Set(ctx, t.key(userID), strconv.FormatInt(version, 10), tokenVersionTTL)
+	return version, nil
+}
+
+// Revoke bumps the user's token version, logging out every existing session.
+func (t *tokenVersions) Revoke(ctx context.Context, userID uuid.UUID) error {
+	version, err := t.repo.IncrementTokenVersion(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to bump token version: %w", err)
+	}
+	if err := t.cache.Set(ctx, t.key(userID), strconv.FormatInt(version, 10), tokenVersionTTL); err != nil {
+		// A stale cached version would keep old tokens alive, so drop it instead.
+		_ = t.cache.Delete(ctx, t.key(userID))
+	}
+	return nil
+}
+
+func (t *tokenVersions) key(userID uuid.UUID) string {
+	return fmt.Sprintf("user:%s:token_version", userID)
+}
@@ -0,0 +1,69 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joefazee/neo/internal/cache"
+	"github.com/joefazee/neo/models"
+)
+
+func TestTokenVersions_CurrentIsCached(t *testing.T) {
+	repo := &MockRepo{}
+	versions := NewTokenVersions(repo, cache.NewMemoryCache[string]())
+	userID := uuid.New()
+
+	repo.On("GetTokenVersion", mock.Anything, userID).Return(int64(4), nil).Once()
+
+	for i := 0; i < 3; i++ {
+		version, err := versions.Current(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), version)
+	}
+	repo.AssertNumberOfCalls(t, "GetTokenVersion", 1)
+}
+
+func TestTokenVersions_RevokeRefreshesCache(t *testing.T) {
+	repo := &MockRepo{}
+	versions := NewTokenVersions(repo, cache.NewMemoryCache[string]())
+	userID := uuid.New()
+
+	repo.On("GetTokenVersion", mock.Anything, userID).Return(int64(1), nil).Once()
+	repo.On("IncrementTokenVersion", mock.Anything, userID).Return(int64(2), nil)
+
+	version, _ := versions.Current(context.Background(), userID)
+	assert.Equal(t, int64(1), version)
+
+	assert.NoError(t, versions.Revoke(context.Background(), userID))
+
+	version, err := versions.Current(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	repo.AssertNumberOfCalls(t, "GetTokenVersion", 1)
+}
+
+func TestTokenVersions_RevokeError(t *testing.T) {
+	repo := &MockRepo{}
+	versions := NewTokenVersions(repo, cache.NewMemoryCache[string]())
+	userID := uuid.New()
+
+	repo.On("IncrementTokenVersion", mock.Anything, userID).Return(int64(0), errors.New("db down"))
+
+	assert.Error(t, versions.Revoke(context.Background(), userID))
+}
+
+func TestAuthService_ValidateTokenVersion(t *testing.T) {
+	repo := &MockRepo{}
+	svc := newTestAuthService(repo)
+	userID := uuid.New()
+
+	repo.On("GetTokenVersion", mock.Anything, userID).Return(int64(3), nil)
+
+	assert.NoError(t, svc.ValidateTokenVersion(context.Background(), userID, 3))
+	assert.ErrorIs(t, svc.ValidateTokenVersion(context.Background(), userID, 2), models.ErrTokenRevoked)
+}
@@ -103,6 +103,12 @@ func ApplyAuthentication(tokenMaker security.Maker, repo Repository) gin.Handler
 			return
 		}
 
+		// The user is already loaded here, so the version check costs nothing extra.
+		if payload.Version != user.TokenVersion {
+			api.UnauthorizedResponse(c)
+			return
+		}
+
 		ContextSetUser(c, user)
 		ContextSetToken(c, payload)
 
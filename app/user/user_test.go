@@ -398,3 +398,24 @@ func (suite *MiddlewareTestSuite) TestRequireActivatedUser_ActiveUser() {
 
 	suite.Equal(http.StatusOK, w.Code)
 }
+
+func (suite *MiddlewareTestSuite) TestApplyAuthentication_StaleTokenVersion() {
+	userID := uuid.New()
+	payload := &security.Payload{UserID: userID, Version: 1}
+	user := &models.User{ID: userID, TokenVersion: 2}
+
+	suite.tokenMaker.On("VerifyToken", "stale_token").Return(payload, nil)
+	suite.repo.On("GetByEmail", mock.Anything, userID.String()).Return(user, nil)
+
+	suite.router.Use(ApplyAuthentication(suite.tokenMaker, suite.repo))
+	suite.router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/test", http.NoBody)
+	req.Header.Set(AuthorizationHeaderKey, "Bearer stale_token")
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
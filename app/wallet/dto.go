@@ -20,6 +20,9 @@ type CreditWalletRequest struct {
 	Amount      decimal.Decimal `json:"amount" binding:"required"`
 	Description string          `json:"description,omitempty"`
 	ReferenceID *uuid.UUID      `json:"reference_id,omitempty"`
+	// PaymentInstrument identifies the card or account the deposit was paid
+	// from, such as the provider's card fingerprint
+	PaymentInstrument string `json:"payment_instrument,omitempty"`
 }
 
 // DebitWalletRequest represents the request to debit a wallet
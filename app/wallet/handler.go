@@ -122,9 +122,13 @@ func (h *Handler) GetUserWallets(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Wallet ID"
 // @Param request body CreditWalletRequest true "Credit request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the first response"
 // @Success 200 {object} api.Response{data=OperationResponse}
 // @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
 // @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 422 {object} api.Response{error=api.ErrorInfo}
 // @Failure 500 {object} api.Response{error=api.ErrorInfo}
 // @Router /api/v1/wallets/{id}/credit [post]
 // //nolint: dupl
@@ -147,7 +151,11 @@ func (h *Handler) CreditWallet(c *gin.Context) {
 			api.NotFoundResponse(c, "Wallet")
 			return
 		}
-		if strings.Contains(err.Error(), "locked") {
+		if errors.Is(err, models.ErrPlayerExcluded) {
+			api.ForbiddenResponse(c, err.Error())
+			return
+		}
+		if errors.Is(err, models.ErrDepositLimitExceeded) || strings.Contains(err.Error(), "locked") {
 			api.BadRequestResponse(c, err.Error())
 			return
 		}
@@ -167,9 +175,13 @@ func (h *Handler) CreditWallet(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Wallet ID"
 // @Param request body DebitWalletRequest true "Debit request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the first response"
 // @Success 200 {object} api.Response{data=OperationResponse}
 // @Failure 400 {object} api.Response{error=api.ErrorInfo}
+// @Failure 403 {object} api.Response{error=api.ErrorInfo}
 // @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 422 {object} api.Response{error=api.ErrorInfo}
 // @Failure 500 {object} api.Response{error=api.ErrorInfo}
 // @Router /api/v1/wallets/{id}/debit [post]
 // //nolint: dupl
@@ -196,6 +208,14 @@ func (h *Handler) DebitWallet(c *gin.Context) {
 			api.BadRequestResponse(c, "Insufficient balance")
 			return
 		}
+		if errors.Is(err, models.ErrKYCNotVerified) {
+			api.ErrorResponse(c, 403, "KYC_REQUIRED", err.Error(), nil)
+			return
+		}
+		if errors.Is(err, models.ErrWithdrawalNotAllowed) {
+			api.ForbiddenResponse(c, err.Error())
+			return
+		}
 		if strings.Contains(err.Error(), "locked") {
 			api.BadRequestResponse(c, err.Error())
 			return
@@ -216,9 +236,12 @@ func (h *Handler) DebitWallet(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Wallet ID"
 // @Param request body LockFundsRequest true "Lock funds request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the first response"
 // @Success 200 {object} api.Response{data=OperationResponse}
 // @Failure 400 {object} api.Response{error=api.ErrorInfo}
 // @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 422 {object} api.Response{error=api.ErrorInfo}
 // @Failure 500 {object} api.Response{error=api.ErrorInfo}
 // @Router /api/v1/wallets/{id}/lock-funds [post]
 // //nolint: dupl
@@ -265,9 +288,12 @@ func (h *Handler) LockFunds(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Wallet ID"
 // @Param request body UnlockFundsRequest true "Unlock funds request"
+// @Param Idempotency-Key header string false "Key that makes retries replay the first response"
 // @Success 200 {object} api.Response{data=OperationResponse}
 // @Failure 400 {object} api.Response{error=api.ErrorInfo}
 // @Failure 404 {object} api.Response{error=api.ErrorInfo}
+// @Failure 409 {object} api.Response{error=api.ErrorInfo}
+// @Failure 422 {object} api.Response{error=api.ErrorInfo}
 // @Failure 500 {object} api.Response{error=api.ErrorInfo}
 // @Router /api/v1/wallets/{id}/unlock-funds [post]
 // //nolint: dupl
@@ -2,6 +2,10 @@ package wallet
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/idempotency"
+	"github.com/joefazee/neo/app/referral"
+	"github.com/joefazee/neo/app/responsible"
 	"github.com/joefazee/neo/internal/deps"
 )
 
@@ -13,6 +17,7 @@ const (
 // MountAuthenticated mounts authenticated wallet routes
 func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
 	handler := createHandler(container)
+	idempotent := idempotency.Require(container)
 
 	// Wallet management
 	walletsGroup := r.Group("/wallets")
@@ -21,10 +26,10 @@ func MountAuthenticated(r *gin.RouterGroup, container *deps.Container) {
 	walletsGroup.GET("/:id/transactions", handler.GetWalletTransactions)
 
 	// Wallet operations
-	walletsGroup.POST("/:id/credit", handler.CreditWallet)
-	walletsGroup.POST("/:id/debit", handler.DebitWallet)
-	walletsGroup.POST("/:id/lock-funds", handler.LockFunds)
-	walletsGroup.POST("/:id/unlock-funds", handler.UnlockFunds)
+	walletsGroup.POST("/:id/credit", idempotent, handler.CreditWallet)
+	walletsGroup.POST("/:id/debit", idempotent, handler.DebitWallet)
+	walletsGroup.POST("/:id/lock-funds", idempotent, handler.LockFunds)
+	walletsGroup.POST("/:id/unlock-funds", idempotent, handler.UnlockFunds)
 	walletsGroup.PATCH("/:id/lock", handler.LockWallet)
 
 	// User wallets
@@ -39,7 +44,10 @@ func InitRepositories(container *deps.Container) {
 	container.RegisterRepository(RepoKey, repo)
 
 	// Initialize service
-	srv := NewService(repo, container.DB)
+	guard, _ := container.GetService(responsible.ServiceKey).(responsible.Guard)
+	referrals, _ := container.GetService(referral.ServiceKey).(referral.Tracker)
+	signals, _ := container.GetService(fraud.ServiceKey).(fraud.Recorder)
+	srv := NewService(repo, container.DB, guard, referrals, signals)
 	container.RegisterService(ServiceKey, srv)
 }
 
@@ -6,18 +6,23 @@ import (
 	"github.com/google/uuid"
 	"github.com/joefazee/neo/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	CreateWallet(ctx context.Context, wallet *models.Wallet) error
 	GetWalletByID(ctx context.Context, id uuid.UUID) (*models.Wallet, error)
+	LockWalletByID(ctx context.Context, id uuid.UUID) (*models.Wallet, error)
 	GetWalletByUserAndCurrency(ctx context.Context, userID uuid.UUID, currencyCode string) (*models.Wallet, error)
 	GetUserWallets(ctx context.Context, userID uuid.UUID) ([]models.Wallet, error)
 	UpdateWallet(ctx context.Context, wallet *models.Wallet) error
+	GetWalletOwner(ctx context.Context, wallet *models.Wallet) (*models.User, error)
 
 	CreateTransaction(ctx context.Context, transaction *models.Transaction) error
 	GetWalletTransactions(ctx context.Context, walletID uuid.UUID, limit, offset int) ([]models.Transaction, error)
 
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+
 	WithTx(tx *gorm.DB) Repository
 }
 
@@ -49,6 +54,20 @@ func (r *repository) GetWalletByID(ctx context.Context, id uuid.UUID) (*models.W
 	return &wallet, nil
 }
 
+// LockWalletByID returns the wallet, holding a row lock on it until the
+// transaction ends
+func (r *repository) LockWalletByID(ctx context.Context, id uuid.UUID) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
 func (r *repository) GetWalletByUserAndCurrency(ctx context.Context, userID uuid.UUID, currencyCode string) (*models.Wallet, error) {
 	var wallet models.Wallet
 	err := r.db.WithContext(ctx).
@@ -76,6 +95,15 @@ func (r *repository) UpdateWallet(ctx context.Context, wallet *models.Wallet) er
 	return r.db.WithContext(ctx).Save(wallet).Error
 }
 
+func (r *repository) GetWalletOwner(ctx context.Context, wallet *models.Wallet) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("id = ?", wallet.UserID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *repository) CreateTransaction(ctx context.Context, transaction *models.Transaction) error {
 	if err := transaction.Validate(); err != nil {
 		return err
@@ -93,3 +121,7 @@ func (r *repository) GetWalletTransactions(ctx context.Context, walletID uuid.UU
 		Find(&transactions).Error
 	return transactions, err
 }
+
+func (r *repository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
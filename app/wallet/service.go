@@ -6,6 +6,10 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/joefazee/neo/app/audit"
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/referral"
+	"github.com/joefazee/neo/app/responsible"
 	"github.com/joefazee/neo/models"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -26,14 +30,28 @@ type Service interface {
 }
 
 type service struct {
-	repo Repository
-	db   *gorm.DB
+	repo      Repository
+	db        *gorm.DB
+	guard     responsible.Guard
+	referrals referral.Tracker
+	signals   fraud.Recorder
 }
 
-func NewService(repo Repository, db *gorm.DB) Service {
+// NewService creates a wallet service. guard enforces the player's exclusions
+// and deposit limits on credits, referrals is told about deposits so
+// referral rewards can be paid, and signals is told which payment instrument
+// paid for them; any of them may be nil.
+func NewService(repo Repository,
+	db *gorm.DB,
+	guard responsible.Guard,
+	referrals referral.Tracker,
+	signals fraud.Recorder) Service {
 	return &service{
-		repo: repo,
-		db:   db,
+		repo:      repo,
+		db:        db,
+		guard:     guard,
+		referrals: referrals,
+		signals:   signals,
 	}
 }
 
@@ -54,8 +72,15 @@ func (s *service) CreateWallet(ctx context.Context, req *CreateWalletRequest) (*
 		LockedBalance: decimal.Zero,
 	}
 
-	if err := s.repo.CreateWallet(ctx, wallet); err != nil {
-		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.CreateWallet(ctx, wallet); err != nil {
+			return fmt.Errorf("failed to create wallet: %w", err)
+		}
+		return recordWalletChange(ctx, txRepo, models.AuditActionWalletCreated, wallet, nil)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return ToWalletResponse(wallet), nil
@@ -89,7 +114,7 @@ func (s *service) GetUserWallets(ctx context.Context, userID uuid.UUID) ([]Respo
 
 func (s *service) LockWallet(ctx context.Context, id uuid.UUID, req *LockWalletRequest) (*OperationResponse, error) {
 	return s.executeWalletTransaction(func(txRepo Repository) (*OperationResponse, error) {
-		wallet, err := txRepo.GetWalletByID(ctx, id)
+		wallet, err := txRepo.LockWalletByID(ctx, id)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, models.ErrRecordNotFound
@@ -97,8 +122,11 @@ func (s *service) LockWallet(ctx context.Context, id uuid.UUID, req *LockWalletR
 			return nil, fmt.Errorf("failed to get wallet: %w", err)
 		}
 
+		oldValues := wallet.AuditValues()
+		action := models.AuditActionWalletUnlocked
 		if req.IsLocked {
 			wallet.Lock(req.Reason)
+			action = models.AuditActionWalletLocked
 		} else {
 			wallet.Unlock()
 		}
@@ -107,6 +135,10 @@ func (s *service) LockWallet(ctx context.Context, id uuid.UUID, req *LockWalletR
 			return nil, fmt.Errorf("failed to update wallet: %w", err)
 		}
 
+		if err := recordWalletChange(ctx, txRepo, action, wallet, oldValues); err != nil {
+			return nil, err
+		}
+
 		return &OperationResponse{
 			Wallet: ToWalletResponse(wallet),
 		}, nil
@@ -118,8 +150,8 @@ func (s *service) CreditWallet(ctx context.Context, id uuid.UUID, req *CreditWal
 		return nil, errors.New("credit amount must be positive")
 	}
 
-	return s.executeWalletTransaction(func(txRepo Repository) (*OperationResponse, error) {
-		wallet, err := txRepo.GetWalletByID(ctx, id)
+	result, err := s.executeWalletTransactionTx(func(tx *gorm.DB, txRepo Repository) (*OperationResponse, error) {
+		wallet, err := txRepo.LockWalletByID(ctx, id)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, models.ErrRecordNotFound
@@ -131,7 +163,16 @@ func (s *service) CreditWallet(ctx context.Context, id uuid.UUID, req *CreditWal
 			return nil, errors.New("wallet is locked")
 		}
 
+		// Checked under the wallet lock so concurrent deposits cannot each
+		// see the same usage and together break the limit
+		if s.guard != nil {
+			if err := s.guard.WithTx(tx).CheckDeposit(ctx, wallet.UserID, req.Amount); err != nil {
+				return nil, err
+			}
+		}
+
 		balanceBefore := wallet.Balance
+		oldValues := wallet.AuditValues()
 
 		if err := wallet.Credit(req.Amount); err != nil {
 			return nil, fmt.Errorf("failed to credit wallet: %w", err)
@@ -159,11 +200,30 @@ func (s *service) CreditWallet(ctx context.Context, id uuid.UUID, req *CreditWal
 			return nil, fmt.Errorf("failed to create transaction: %w", err)
 		}
 
+		if err := recordWalletChange(ctx, txRepo, models.AuditActionWalletCredited, wallet, oldValues); err != nil {
+			return nil, err
+		}
+
 		return &OperationResponse{
 			Wallet:      ToWalletResponse(wallet),
 			Transaction: ToTransactionResponse(transaction),
 		}, nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Referral rewards are paid once the deposit has committed; only the first
+	// deposit of at least the reward's minimum earns one
+	if s.referrals != nil {
+		s.referrals.RecordMilestone(ctx, result.Wallet.UserID, models.ReferralMilestoneFirstDeposit,
+			result.Wallet.CurrencyCode, req.Amount)
+	}
+	if s.signals != nil && req.PaymentInstrument != "" {
+		s.signals.RecordSignals(ctx, result.Wallet.UserID,
+			fraud.Signal{Type: models.AccountSignalPaymentInstrument, Value: req.PaymentInstrument})
+	}
+	return result, nil
 }
 
 func (s *service) DebitWallet(ctx context.Context, id uuid.UUID, req *DebitWalletRequest) (*OperationResponse, error) {
@@ -172,7 +232,7 @@ func (s *service) DebitWallet(ctx context.Context, id uuid.UUID, req *DebitWalle
 	}
 
 	return s.executeWalletTransaction(func(txRepo Repository) (*OperationResponse, error) {
-		wallet, err := txRepo.GetWalletByID(ctx, id)
+		wallet, err := txRepo.LockWalletByID(ctx, id)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, models.ErrRecordNotFound
@@ -184,6 +244,18 @@ func (s *service) DebitWallet(ctx context.Context, id uuid.UUID, req *DebitWalle
 			return nil, errors.New("wallet is locked")
 		}
 
+		// Debits leave the platform as withdrawals, so the owner must pass KYC
+		owner, err := txRepo.GetWalletOwner(ctx, wallet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wallet owner: %w", err)
+		}
+		if !owner.IsKYCVerified() {
+			return nil, models.ErrKYCNotVerified
+		}
+		if owner.IsActive == nil || !owner.CanWithdraw() {
+			return nil, models.ErrWithdrawalNotAllowed
+		}
+
 		balanceBefore := wallet.Balance
 
 		// Check sufficient balance
@@ -191,6 +263,8 @@ func (s *service) DebitWallet(ctx context.Context, id uuid.UUID, req *DebitWalle
 			return nil, models.ErrInsufficientBalance
 		}
 
+		oldValues := wallet.AuditValues()
+
 		// Debit the wallet
 		if err := wallet.Debit(req.Amount); err != nil {
 			return nil, fmt.Errorf("failed to debit wallet: %w", err)
@@ -218,6 +292,10 @@ func (s *service) DebitWallet(ctx context.Context, id uuid.UUID, req *DebitWalle
 			return nil, fmt.Errorf("failed to create transaction: %w", err)
 		}
 
+		if err := recordWalletChange(ctx, txRepo, models.AuditActionWalletDebited, wallet, oldValues); err != nil {
+			return nil, err
+		}
+
 		return &OperationResponse{
 			Wallet:      ToWalletResponse(wallet),
 			Transaction: ToTransactionResponse(transaction),
@@ -233,7 +311,7 @@ func (s *service) LockFunds(ctx context.Context, id uuid.UUID, req *LockFundsReq
 	}
 
 	return s.executeWalletTransaction(func(txRepo Repository) (*OperationResponse, error) {
-		wallet, err := txRepo.GetWalletByID(ctx, id)
+		wallet, err := txRepo.LockWalletByID(ctx, id)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, models.ErrRecordNotFound
@@ -245,6 +323,8 @@ func (s *service) LockFunds(ctx context.Context, id uuid.UUID, req *LockFundsReq
 			return nil, errors.New("wallet is locked")
 		}
 
+		oldValues := wallet.AuditValues()
+
 		// Lock funds
 		if err := wallet.LockFunds(req.Amount); err != nil {
 			return nil, fmt.Errorf("failed to lock funds: %w", err)
@@ -272,6 +352,10 @@ func (s *service) LockFunds(ctx context.Context, id uuid.UUID, req *LockFundsReq
 			return nil, fmt.Errorf("failed to create transaction: %w", err)
 		}
 
+		if err := recordWalletChange(ctx, txRepo, models.AuditActionFundsLocked, wallet, oldValues); err != nil {
+			return nil, err
+		}
+
 		return &OperationResponse{
 			Wallet:      ToWalletResponse(wallet),
 			Transaction: ToTransactionResponse(transaction),
@@ -287,7 +371,7 @@ func (s *service) UnlockFunds(ctx context.Context, id uuid.UUID, req *UnlockFund
 	}
 
 	return s.executeWalletTransaction(func(txRepo Repository) (*OperationResponse, error) {
-		wallet, err := txRepo.GetWalletByID(ctx, id)
+		wallet, err := txRepo.LockWalletByID(ctx, id)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, models.ErrRecordNotFound
@@ -299,6 +383,8 @@ func (s *service) UnlockFunds(ctx context.Context, id uuid.UUID, req *UnlockFund
 			return nil, errors.New("wallet is locked")
 		}
 
+		oldValues := wallet.AuditValues()
+
 		// Unlock funds
 		if err := wallet.UnlockFunds(req.Amount); err != nil {
 			return nil, fmt.Errorf("failed to unlock funds: %w", err)
@@ -326,6 +412,10 @@ func (s *service) UnlockFunds(ctx context.Context, id uuid.UUID, req *UnlockFund
 			return nil, fmt.Errorf("failed to create transaction: %w", err)
 		}
 
+		if err := recordWalletChange(ctx, txRepo, models.AuditActionFundsUnlocked, wallet, oldValues); err != nil {
+			return nil, err
+		}
+
 		return &OperationResponse{
 			Wallet:      ToWalletResponse(wallet),
 			Transaction: ToTransactionResponse(transaction),
@@ -357,13 +447,21 @@ func (s *service) GetWalletTransactions(ctx context.Context, walletID uuid.UUID,
 
 // executeWalletTransaction executes a wallet operation within a database transaction
 func (s *service) executeWalletTransaction(operation func(Repository) (*OperationResponse, error)) (*OperationResponse, error) {
+	return s.executeWalletTransactionTx(func(_ *gorm.DB, txRepo Repository) (*OperationResponse, error) {
+		return operation(txRepo)
+	})
+}
+
+// executeWalletTransactionTx is executeWalletTransaction for operations that
+// also read through the transaction outside the wallet repository.
+func (s *service) executeWalletTransactionTx(operation func(*gorm.DB, Repository) (*OperationResponse, error)) (*OperationResponse, error) {
 	var result *OperationResponse
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txRepo := s.repo.WithTx(tx)
 
 		var err error
-		result, err = operation(txRepo)
+		result, err = operation(tx, txRepo)
 		return err
 	})
 
@@ -373,3 +471,12 @@ func (s *service) executeWalletTransaction(operation func(Repository) (*Operatio
 
 	return result, nil
 }
+
+// recordWalletChange writes the audit entry for a wallet mutation inside its transaction.
+func recordWalletChange(ctx context.Context, txRepo Repository, action string, wallet *models.Wallet, oldValues models.AuditValues) error {
+	log := audit.NewLog(ctx, action, models.AuditResourceWallet, &wallet.ID, oldValues, wallet.AuditValues())
+	if err := txRepo.CreateAuditLog(ctx, log); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,12 +11,23 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joefazee/neo/app"
 	"github.com/joefazee/neo/app/api"
+	"github.com/joefazee/neo/app/apikey"
+	"github.com/joefazee/neo/app/audit"
 	"github.com/joefazee/neo/app/categories"
 	"github.com/joefazee/neo/app/countries"
 	"github.com/joefazee/neo/app/database"
 	apiDoc "github.com/joefazee/neo/app/doc"
+	"github.com/joefazee/neo/app/fraud"
+	"github.com/joefazee/neo/app/idempotency"
+	"github.com/joefazee/neo/app/kyc"
+	"github.com/joefazee/neo/app/leaderboard"
 	"github.com/joefazee/neo/app/markets"
 	"github.com/joefazee/neo/app/prediction"
+	"github.com/joefazee/neo/app/privacy"
+	"github.com/joefazee/neo/app/rbac"
+	"github.com/joefazee/neo/app/referral"
+	"github.com/joefazee/neo/app/responsible"
+	"github.com/joefazee/neo/app/statement"
 	"github.com/joefazee/neo/app/user"
 	_ "github.com/joefazee/neo/docs"
 	"github.com/joefazee/neo/internal/cache"
@@ -24,6 +36,7 @@ import (
 	"github.com/joefazee/neo/internal/router"
 	"github.com/joefazee/neo/internal/sanitizer"
 	"github.com/joefazee/neo/internal/security"
+	"gorm.io/gorm"
 )
 
 // @title Neo API
@@ -84,17 +97,27 @@ func main() {
 	container := deps.NewContainer(db, tokenMaker, htmlSanitizer, zeroLogger, cacheService)
 
 	initializeRepositories(container)
+	idempotency.StartJobs(context.Background(), container)
+	prediction.StartJobs(context.Background(), container)
+	statement.StartJobs(context.Background(), container)
+
+	if cfg.RBAC.SyncOnStartup {
+		syncRBAC(db, &cfg.RBAC)
+	}
 
 	authService := user.NewAuthService(
 		container.GetRepository(user.RepoKey).(user.Repository),
 		cacheService,
+		container.GetService(user.SessionsKey).(user.SessionService),
+		container.GetService(user.PermissionsKey).(user.PermissionCache),
 	)
 	container.RegisterService("auth_service", authService)
 
 	r := gin.Default()
+	r.Use(audit.ContextMiddleware(), audit.ImpersonationAuditMiddleware(container))
 	mounter := router.NewMounter(container)
 
-	mountRoutes(r, mounter, authService, tokenMaker)
+	mountRoutes(r, mounter, container, authService, tokenMaker)
 
 	apiDoc.Init(r)
 
@@ -105,15 +128,42 @@ func main() {
 }
 
 func initializeRepositories(container *deps.Container) {
+	referral.InitRepositories(container)
+	fraud.InitRepositories(container)
 	user.InitRepositories(container)
+	privacy.InitRepositories(container)
+	statement.InitRepositories(container)
 	countries.InitRepositories(container)
 	categories.InitRepositories(container)
-	markets.InitRepositories(container)
+	responsible.InitRepositories(container)
+	idempotency.InitRepositories(container)
+	leaderboard.InitRepositories(container)
 	prediction.InitRepositories(container)
+	markets.InitRepositories(container)
 	wallet.InitRepositories(container)
+	kyc.InitRepositories(container)
+	apikey.InitRepositories(container)
+	audit.InitRepositories(container)
+}
+
+// syncRBAC makes sure the permission catalogue and default roles exist before serving.
+func syncRBAC(db *gorm.DB, config *rbac.Config) {
+	if err := config.Validate(); err != nil {
+		log.Fatal("Invalid RBAC configuration:", err)
+	}
+	result, err := rbac.NewService(rbac.NewRepository(db), config).Sync(context.Background())
+	if err != nil {
+		log.Fatal("RBAC sync failed:", err)
+	}
+	log.Printf("RBAC sync complete: %d permissions created, %d roles created, %d grants added",
+		result.PermissionsCreated, result.RolesCreated, result.GrantsAdded)
 }
 
-func mountRoutes(engine *gin.Engine, mounter *router.Mounter, authService user.AuthService, tokenMaker security.Maker) {
+func mountRoutes(engine *gin.Engine,
+	mounter *router.Mounter,
+	container *deps.Container,
+	authService user.AuthService,
+	tokenMaker security.Maker) {
 	mounter.Public(engine).
 		Mount(func(r *gin.RouterGroup, _ *deps.Container) {
 			r.GET("/healthz", api.HealthCheck)
@@ -121,23 +171,40 @@ func mountRoutes(engine *gin.Engine, mounter *router.Mounter, authService user.A
 		Mount(countries.MountPublic).
 		Mount(categories.MountPublic).
 		Mount(markets.MountPublic).
-		Mount(user.MountPublic)
+		Mount(leaderboard.MountPublic).
+		Mount(user.MountPublic).
+		Mount(kyc.MountPublic)
 
+	// Authenticated routes also accept HMAC signed API key requests
 	mounter.Authenticated(engine).
-		WithAuth(user.AuthMiddleware(tokenMaker, authService)).
+		WithAuth(apikey.Middleware(container, user.AuthMiddleware(tokenMaker, authService))).
+		Use(audit.ContextMiddleware()).
 		Mount(countries.MountAuthenticated).
 		Mount(markets.MountAuthenticated).
 		Mount(prediction.MountAuthenticated).
 		Mount(wallet.MountAuthenticated).
-		Mount(user.MountAuthenticated)
-
-	mounter.Authorized(engine, "admin").
+		Mount(user.MountAuthenticated).
+		Mount(kyc.MountAuthenticated).
+		Mount(apikey.MountAuthenticated).
+		Mount(responsible.MountAuthenticated).
+		Mount(referral.MountAuthenticated).
+		Mount(privacy.MountAuthenticated).
+		Mount(statement.MountAuthenticated)
+
+	mounter.Authorized(engine, user.PermissionAdminAccess).
 		WithAuth(user.AuthMiddleware(tokenMaker, authService)).
-		WithPermission(api.Can("admin")).
-		Mount(user.MountAdmin)
-
-	mounter.Authorized(engine, "market:admin").
+		WithPermission(api.Can(user.PermissionAdminAccess)).
+		Use(audit.AdminMiddleware(container)).
+		Mount(user.MountAdmin).
+		Mount(kyc.MountAdmin).
+		Mount(prediction.MountAdmin).
+		Mount(fraud.MountAdmin).
+		Mount(audit.MountAdmin).
+		Mount(statement.MountAdmin)
+
+	mounter.Authorized(engine, markets.PermissionMarketAdmin).
 		WithAuth(user.AuthMiddleware(tokenMaker, authService)).
-		WithPermission(api.Can("market:admin")).
+		WithPermission(api.Can(markets.PermissionMarketAdmin)).
+		Use(audit.AdminMiddleware(container)).
 		Mount(markets.MountAdmin)
 }
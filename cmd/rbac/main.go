@@ -0,0 +1,49 @@
+// Command neo-rbac maintains the permission catalogue and default roles.
+//
+//	neo-rbac sync
+//
+// Set RBAC_SUPER_ADMIN_EMAIL to promote an existing account to super-admin
+// on a database that does not have one yet.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joefazee/neo/app"
+	"github.com/joefazee/neo/app/database"
+	"github.com/joefazee/neo/app/rbac"
+)
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "sync" {
+		fmt.Fprintln(os.Stderr, "usage: neo-rbac sync")
+		os.Exit(2)
+	}
+
+	cfg, err := app.LoadConfig()
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+	if err := cfg.RBAC.Validate(); err != nil {
+		log.Fatal("Invalid RBAC configuration:", err)
+	}
+
+	db, err := database.New(&cfg.DB)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	result, err := rbac.NewService(rbac.NewRepository(db), &cfg.RBAC).Sync(context.Background())
+	if err != nil {
+		log.Fatal("RBAC sync failed:", err)
+	}
+
+	log.Printf("RBAC sync complete: %d permissions created, %d roles created, %d grants added",
+		result.PermissionsCreated, result.RolesCreated, result.GrantsAdded)
+	if result.SuperAdmin != "" {
+		log.Printf("Promoted %s to %s", result.SuperAdmin, rbac.RoleSuperAdmin)
+	}
+}
@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Counters is implemented by backends that can count atomically, so that
+// concurrent callers never lose an increment. Callers should type-assert for
+// it, like SortedSets.
+type Counters interface {
+	// IncrementCounter adds one to key, starting from zero, and returns the
+	// new value. A positive ttl restarts the key's expiry.
+	IncrementCounter(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Counter returns key's value, or zero when it is not set.
+	Counter(ctx context.Context, key string) (int64, error)
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCounters runs the behaviour every Counters backend must share
+func testCounters(t *testing.T, counters Counters) {
+	ctx := context.Background()
+
+	value, err := counters.Counter(ctx, "missing")
+	require.NoError(t, err)
+	assert.Zero(t, value)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := counters.IncrementCounter(ctx, "hits", time.Minute)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	value, err = counters.Counter(ctx, "hits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), value, "no increment is lost")
+
+	value, err = counters.IncrementCounter(ctx, "hits", 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(51), value)
+}
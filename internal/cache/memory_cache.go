@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 )
@@ -19,6 +20,24 @@ type shard[V any] struct {
 type MemoryCache[V any] struct {
 	shards []*shard[V]
 	quit   chan struct{}
+
+	// subscribers backs Publish/Subscribe; delivery is limited to this process.
+	subMu       sync.RWMutex
+	subscribers map[string]map[int]func(string)
+	nextSubID   int
+
+	// sets backs the SortedSets methods; like subscribers they are local to this process.
+	setMu sync.Mutex
+	sets  map[string]*scoredSet
+
+	// counters backs the Counters methods and is also local to this process.
+	counterMu sync.Mutex
+	counters  map[string]item[int64]
+}
+
+type scoredSet struct {
+	scores     map[string]float64
+	expiration int64 // Unix nanoseconds; zero = no expire
 }
 
 // NewMemoryCache creates a 256-shard cache with a 1s janitor by default.
@@ -181,8 +200,184 @@ func (mc *MemoryCache[V]) startJanitor(interval time.Duration) {
 					s.Unlock()
 				}(sh)
 			}
+			mc.setMu.Lock()
+			for name, set := range mc.sets {
+				if set.expiration > 0 && now > set.expiration {
+					delete(mc.sets, name)
+				}
+			}
+			mc.setMu.Unlock()
+			mc.counterMu.Lock()
+			for key, counter := range mc.counters {
+				if counter.expiration > 0 && now > counter.expiration {
+					delete(mc.counters, key)
+				}
+			}
+			mc.counterMu.Unlock()
 		case <-mc.quit:
 			return
 		}
 	}
 }
+
+// Publish synchronously delivers message to subscribers in this process.
+func (mc *MemoryCache[V]) Publish(_ context.Context, channel, message string) error {
+	mc.subMu.RLock()
+	handlers := make([]func(string), 0, len(mc.subscribers[channel]))
+	for _, h := range mc.subscribers[channel] {
+		handlers = append(handlers, h)
+	}
+	mc.subMu.RUnlock()
+
+	for _, h := range handlers {
+		h(message)
+	}
+	return nil
+}
+
+// Subscribe registers handler for channel until ctx is cancelled.
+func (mc *MemoryCache[V]) Subscribe(ctx context.Context, channel string, handler func(message string)) error {
+	mc.subMu.Lock()
+	if mc.subscribers == nil {
+		mc.subscribers = make(map[string]map[int]func(string))
+	}
+	if mc.subscribers[channel] == nil {
+		mc.subscribers[channel] = make(map[int]func(string))
+	}
+	id := mc.nextSubID
+	mc.nextSubID++
+	mc.subscribers[channel][id] = handler
+	mc.subMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		mc.subMu.Lock()
+		delete(mc.subscribers[channel], id)
+		mc.subMu.Unlock()
+	}()
+	return nil
+}
+
+// liveSet returns the named set, dropping it if it has expired. With create
+// it makes a missing set. The caller must hold setMu.
+func (mc *MemoryCache[V]) liveSet(name string, create bool) *scoredSet {
+	set, ok := mc.sets[name]
+	if ok && set.expiration > 0 && time.Now().UnixNano() > set.expiration {
+		delete(mc.sets, name)
+		ok = false
+	}
+	if !ok && create {
+		if mc.sets == nil {
+			mc.sets = make(map[string]*scoredSet)
+		}
+		set = &scoredSet{scores: make(map[string]float64)}
+		mc.sets[name] = set
+	}
+	return set
+}
+
+func (mc *MemoryCache[V]) IncrementScore(_ context.Context, set, member string, delta float64) (float64, error) {
+	mc.setMu.Lock()
+	defer mc.setMu.Unlock()
+	s := mc.liveSet(set, true)
+	s.scores[member] += delta
+	return s.scores[member], nil
+}
+
+func (mc *MemoryCache[V]) SetScore(_ context.Context, set, member string, score float64) error {
+	mc.setMu.Lock()
+	defer mc.setMu.Unlock()
+	mc.liveSet(set, true).scores[member] = score
+	return nil
+}
+
+func (mc *MemoryCache[V]) RemoveMember(_ context.Context, set, member string) error {
+	mc.setMu.Lock()
+	defer mc.setMu.Unlock()
+	if s := mc.liveSet(set, false); s != nil {
+		delete(s.scores, member)
+	}
+	return nil
+}
+
+func (mc *MemoryCache[V]) Score(_ context.Context, set, member string) (float64, error) {
+	mc.setMu.Lock()
+	defer mc.setMu.Unlock()
+	if s := mc.liveSet(set, false); s != nil {
+		if score, ok := s.scores[member]; ok {
+			return score, nil
+		}
+	}
+	return 0, ErrCacheMiss
+}
+
+// TopMembers sorts the whole set on every call, which is fine for the set
+// sizes a single process holds.
+func (mc *MemoryCache[V]) TopMembers(_ context.Context, set string, offset, count int) ([]ScoredMember, error) {
+	mc.setMu.Lock()
+	s := mc.liveSet(set, false)
+	if s == nil {
+		mc.setMu.Unlock()
+		return nil, nil
+	}
+	members := make([]ScoredMember, 0, len(s.scores))
+	for member, score := range s.scores {
+		members = append(members, ScoredMember{Member: member, Score: score})
+	}
+	mc.setMu.Unlock()
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Score != members[j].Score {
+			return members[i].Score > members[j].Score
+		}
+		return members[i].Member > members[j].Member
+	})
+	if offset >= len(members) || count <= 0 {
+		return nil, nil
+	}
+	members = members[offset:]
+	if count < len(members) {
+		members = members[:count]
+	}
+	return members, nil
+}
+
+func (mc *MemoryCache[V]) ExpireSet(_ context.Context, set string, ttl time.Duration) error {
+	mc.setMu.Lock()
+	defer mc.setMu.Unlock()
+	if s := mc.liveSet(set, false); s != nil {
+		s.expiration = time.Now().Add(ttl).UnixNano()
+	}
+	return nil
+}
+
+// liveCounter returns the counter under key, treating an expired one as
+// unset. The caller must hold counterMu.
+func (mc *MemoryCache[V]) liveCounter(key string) item[int64] {
+	counter, ok := mc.counters[key]
+	if !ok || (counter.expiration > 0 && time.Now().UnixNano() > counter.expiration) {
+		return item[int64]{}
+	}
+	return counter
+}
+
+func (mc *MemoryCache[V]) IncrementCounter(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	mc.counterMu.Lock()
+	defer mc.counterMu.Unlock()
+	if mc.counters == nil {
+		mc.counters = make(map[string]item[int64])
+	}
+	counter := mc.liveCounter(key)
+	counter.value++
+	if ttl > 0 {
+		counter.expiration = time.Now().Add(ttl).UnixNano()
+	}
+	mc.counters[key] = counter
+	return counter.value, nil
+}
+
+func (mc *MemoryCache[V]) Counter(_ context.Context, key string) (int64, error) {
+	mc.counterMu.Lock()
+	defer mc.counterMu.Unlock()
+	return mc.liveCounter(key).value, nil
+}
@@ -114,3 +114,66 @@ func TestMemoryCacheJanitorCleansExpiredEntries(t *testing.T) {
 	}
 	assert.False(t, found, "expired entry should have been removed by janitor")
 }
+
+func TestMemoryCachePublishSubscribe(t *testing.T) {
+	mc := NewMemoryCache[string]()
+	defer mc.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []string
+	assert.NoError(t, mc.Subscribe(ctx, "events", func(msg string) { got = append(got, msg) }))
+
+	assert.NoError(t, mc.Publish(context.Background(), "events", "first"))
+	assert.NoError(t, mc.Publish(context.Background(), "other", "ignored"))
+	assert.Equal(t, []string{"first"}, got)
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		mc.subMu.RLock()
+		defer mc.subMu.RUnlock()
+		return len(mc.subscribers["events"]) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestMemoryCacheSortedSets(t *testing.T) {
+	mc := NewMemoryCache[string]()
+	defer mc.Stop()
+	testSortedSets(t, mc)
+}
+
+func TestMemoryCacheSortedSetExpiry(t *testing.T) {
+	mc := NewMemoryCacheWithOptions[string](4, 10*time.Millisecond)
+	defer mc.Stop()
+	ctx := context.Background()
+
+	assert.NoError(t, mc.SetScore(ctx, "daily", "alice", 1))
+	assert.NoError(t, mc.ExpireSet(ctx, "daily", 20*time.Millisecond))
+	assert.Eventually(t, func() bool {
+		mc.setMu.Lock()
+		defer mc.setMu.Unlock()
+		return len(mc.sets) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	_, err := mc.Score(ctx, "daily", "alice")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestMemoryCacheCounters(t *testing.T) {
+	mc := NewMemoryCache[string]()
+	defer mc.Stop()
+	testCounters(t, mc)
+}
+
+func TestMemoryCacheCounterExpiry(t *testing.T) {
+	mc := NewMemoryCacheWithOptions[string](4, time.Hour)
+	defer mc.Stop()
+	ctx := context.Background()
+
+	_, err := mc.IncrementCounter(ctx, "attempts", 10*time.Millisecond)
+	assert.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	value, err := mc.IncrementCounter(ctx, "attempts", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), value, "an expired counter starts again")
+}
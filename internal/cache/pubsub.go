@@ -0,0 +1,13 @@
+package cache
+
+import "context"
+
+// Broadcaster is implemented by backends that can fan messages out to every
+// process sharing the cache. Callers should type-assert for it and degrade to
+// local-only behaviour when it is absent.
+type Broadcaster interface {
+	// Publish delivers message to all current subscribers of channel.
+	Publish(ctx context.Context, channel, message string) error
+	// Subscribe calls handler for every message on channel until ctx is cancelled.
+	Subscribe(ctx context.Context, channel string, handler func(message string)) error
+}
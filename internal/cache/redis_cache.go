@@ -164,3 +164,125 @@ func (r *RedisCache[V]) MSet(ctx context.Context, kv map[string]V, ttl time.Dura
 	_, err := pipe.Exec(ctx)
 	return err
 }
+
+// Publish sends message to every instance subscribed to channel.
+func (r *RedisCache[V]) Publish(ctx context.Context, channel, message string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe confirms the subscription, then dispatches messages in the
+// background until ctx is cancelled.
+func (r *RedisCache[V]) Subscribe(ctx context.Context, channel string, handler func(message string)) error {
+	sub := r.client.Subscribe(ctx, channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return err
+	}
+
+	go func() {
+		defer sub.Close()
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				handler(msg.Payload)
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *RedisCache[V]) IncrementScore(ctx context.Context, set, member string, delta float64) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+	return r.client.ZIncrBy(ctx, set, delta, member).Result()
+}
+
+func (r *RedisCache[V]) SetScore(ctx context.Context, set, member string, score float64) error {
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+	return r.client.ZAdd(ctx, set, redis.Z{Score: score, Member: member}).Err()
+}
+
+func (r *RedisCache[V]) RemoveMember(ctx context.Context, set, member string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+	return r.client.ZRem(ctx, set, member).Err()
+}
+
+func (r *RedisCache[V]) Score(ctx context.Context, set, member string) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+
+	score, err := r.client.ZScore(ctx, set, member).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, ErrCacheMiss
+	}
+	return score, err
+}
+
+func (r *RedisCache[V]) TopMembers(ctx context.Context, set string, offset, count int) ([]ScoredMember, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+
+	zs, err := r.client.ZRevRangeWithScores(ctx, set, int64(offset), int64(offset+count-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	members := make([]ScoredMember, len(zs))
+	for i, z := range zs {
+		member, ok := z.Member.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected member type %T from redis", z.Member)
+		}
+		members[i] = ScoredMember{Member: member, Score: z.Score}
+	}
+	return members, nil
+}
+
+func (r *RedisCache[V]) ExpireSet(ctx context.Context, set string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+	return r.client.Expire(ctx, set, ttl).Err()
+}
+
+// IncrementCounter runs INCR and EXPIRE in one transaction, so a key is never
+// left without its expiry.
+func (r *RedisCache[V]) IncrementCounter(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+
+	var incr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *RedisCache[V]) Counter(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.opTimeout)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return value, err
+}
@@ -233,3 +233,76 @@ func TestRedisCacheMSet_TTLBranch(t *testing.T) {
 	assert.ErrorIs(t, errs[0], ErrCacheMiss)
 	assert.ErrorIs(t, errs[1], ErrCacheMiss)
 }
+
+func TestRedisCachePublishSubscribe(t *testing.T) {
+	rc, s := setupRedisCache(t, time.Second)
+	defer func() {
+		rc.Close()
+		s.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan string, 1)
+	assert.NoError(t, rc.Subscribe(ctx, "events", func(msg string) { received <- msg }))
+	assert.NoError(t, rc.Publish(context.Background(), "events", "hello"))
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "hello", msg)
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered")
+	}
+}
+
+func TestRedisCacheSortedSets(t *testing.T) {
+	rc, s := setupRedisCache(t, time.Second)
+	defer func() {
+		rc.Close()
+		s.Close()
+	}()
+	testSortedSets(t, rc)
+}
+
+func TestRedisCacheSortedSetExpiry(t *testing.T) {
+	rc, s := setupRedisCache(t, time.Second)
+	defer func() {
+		rc.Close()
+		s.Close()
+	}()
+	ctx := context.Background()
+
+	assert.NoError(t, rc.SetScore(ctx, "daily", "alice", 1))
+	assert.NoError(t, rc.ExpireSet(ctx, "daily", time.Minute))
+	s.FastForward(2 * time.Minute)
+
+	_, err := rc.Score(ctx, "daily", "alice")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestRedisCacheCounters(t *testing.T) {
+	rc, s := setupRedisCache(t, time.Second)
+	defer func() {
+		rc.Close()
+		s.Close()
+	}()
+	testCounters(t, rc)
+}
+
+func TestRedisCacheCounterExpiry(t *testing.T) {
+	rc, s := setupRedisCache(t, time.Second)
+	defer func() {
+		rc.Close()
+		s.Close()
+	}()
+	ctx := context.Background()
+
+	_, err := rc.IncrementCounter(ctx, "attempts", time.Minute)
+	assert.NoError(t, err)
+	s.FastForward(2 * time.Minute)
+
+	value, err := rc.Counter(ctx, "attempts")
+	assert.NoError(t, err)
+	assert.Zero(t, value)
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// ScoredMember is a member of a sorted set together with its score.
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+// SortedSets is implemented by backends that keep ranked sets of members
+// ordered by score. Callers should type-assert for it, like Broadcaster.
+type SortedSets interface {
+	// IncrementScore adds delta to member's score, creating the set and the
+	// member as needed, and returns the new score.
+	IncrementScore(ctx context.Context, set, member string, delta float64) (float64, error)
+	// SetScore replaces member's score.
+	SetScore(ctx context.Context, set, member string, score float64) error
+	// RemoveMember drops member from set; removing an absent member is not an error.
+	RemoveMember(ctx context.Context, set, member string) error
+	// Score returns member's score or ErrCacheMiss.
+	Score(ctx context.Context, set, member string) (float64, error)
+	// TopMembers returns up to count members from the highest score down,
+	// skipping the first offset. Equal scores are ordered by member, descending.
+	TopMembers(ctx context.Context, set string, offset, count int) ([]ScoredMember, error)
+	// ExpireSet removes set once ttl has passed.
+	ExpireSet(ctx context.Context, set string, ttl time.Duration) error
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSortedSets runs the behaviour every SortedSets backend must share
+func testSortedSets(t *testing.T, sets SortedSets) {
+	ctx := context.Background()
+
+	score, err := sets.IncrementScore(ctx, "board", "alice", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, score)
+	score, err = sets.IncrementScore(ctx, "board", "alice", -2.5)
+	require.NoError(t, err)
+	assert.Equal(t, 7.5, score)
+
+	require.NoError(t, sets.SetScore(ctx, "board", "bob", 20))
+	require.NoError(t, sets.SetScore(ctx, "board", "carol", 7.5))
+	require.NoError(t, sets.SetScore(ctx, "board", "dave", 1))
+
+	top, err := sets.TopMembers(ctx, "board", 0, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []ScoredMember{{"bob", 20}, {"carol", 7.5}, {"alice", 7.5}}, top, "ties are ordered by member, descending")
+
+	top, err = sets.TopMembers(ctx, "board", 3, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []ScoredMember{{"dave", 1}}, top)
+
+	require.NoError(t, sets.RemoveMember(ctx, "board", "bob"))
+	require.NoError(t, sets.RemoveMember(ctx, "board", "nobody"))
+	_, err = sets.Score(ctx, "board", "bob")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	score, err = sets.Score(ctx, "board", "carol")
+	require.NoError(t, err)
+	assert.Equal(t, 7.5, score)
+
+	top, err = sets.TopMembers(ctx, "missing", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, top)
+	_, err = sets.Score(ctx, "missing", "alice")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
@@ -0,0 +1,138 @@
+// Package pdf writes plain-text PDF documents. Text is set in Courier, one of
+// the standard fonts every reader has, so no font is embedded and columns
+// line up as they would in a terminal.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page layout, in points, for A4 portrait
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 40
+	fontSize   = 8
+	leading    = 10
+)
+
+const (
+	// LineWidth is how many characters fit on a line; longer lines are cut.
+	LineWidth = (pageWidth - 2*margin) * 10 / (fontSize * 6)
+	// LinesPerPage is how many lines fit above the page footer.
+	LinesPerPage = (pageHeight-2*margin)/leading - 2
+)
+
+// Document is a sequence of text lines laid out over as many pages as needed,
+// each numbered in its footer.
+type Document struct {
+	lines []string
+}
+
+// NewDocument creates an empty document.
+func NewDocument() *Document {
+	return &Document{}
+}
+
+// AddLine appends a line of text.
+func (d *Document) AddLine(text string) {
+	d.lines = append(d.lines, text)
+}
+
+// AddLines appends several lines of text.
+func (d *Document) AddLines(lines ...string) {
+	d.lines = append(d.lines, lines...)
+}
+
+// PageCount returns how many pages the document takes; an empty document
+// still has one blank page.
+func (d *Document) PageCount() int {
+	if len(d.lines) == 0 {
+		return 1
+	}
+	return (len(d.lines) + LinesPerPage - 1) / LinesPerPage
+}
+
+// WriteTo writes the document as a PDF file.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.PageCount()
+
+	// Objects 1-3 are the catalog, page tree and font; each page then takes
+	// two, the page and its content stream
+	objects := make([]string, 3+2*pages)
+	kids := make([]string, pages)
+	for i := 0; i < pages; i++ {
+		pageObj, contentObj := 4+2*i, 5+2*i
+		kids[i] = fmt.Sprintf("%d 0 R", pageObj)
+
+		start := i * LinesPerPage
+		end := min(start+LinesPerPage, len(d.lines))
+		content := pageContent(d.lines[start:end], i+1, pages)
+
+		objects[pageObj-1] = fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, contentObj)
+		objects[contentObj-1] = fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)
+	}
+	objects[0] = "<< /Type /Catalog /Pages 2 0 R >>"
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages)
+	objects[2] = "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>"
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// pageContent draws the lines from the top of the page down, then the footer
+func pageContent(lines []string, page, pages int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, pageHeight-margin)
+	for _, line := range lines {
+		fmt.Fprintf(&b, "(%s) Tj T*\n", encode(line))
+	}
+	b.WriteString("ET\n")
+
+	footer := fmt.Sprintf("Page %d of %d", page, pages)
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d %d Td\n(%s) Tj\nET", fontSize, margin, margin/2, encode(footer))
+	return b.String()
+}
+
+// encode converts text to a WinAnsi string literal body. Latin-1 characters
+// carry over as they are, anything else becomes a question mark.
+func encode(text string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range text {
+		if n == LineWidth {
+			break
+		}
+		n++
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
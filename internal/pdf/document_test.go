@@ -0,0 +1,55 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := NewDocument()
+	for i := 0; i < LinesPerPage+5; i++ {
+		doc.AddLine(fmt.Sprintf("line %d", i))
+	}
+	assert.Equal(t, 2, doc.PageCount())
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	assert.Contains(t, out, "/Count 2")
+	assert.Contains(t, out, "(Page 2 of 2) Tj")
+
+	// Every cross-reference entry must point at the object it names
+	xref := strings.Index(out, "xref\n")
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(out[xref:], -1)
+	require.Len(t, entries, 3+2*2)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(t, strings.HasPrefix(out[offset:], fmt.Sprintf("%d 0 obj", i+1)), "object %d", i+1)
+	}
+	assert.Contains(t, out, fmt.Sprintf("startxref\n%d\n", xref))
+}
+
+func TestDocument_EmptyHasOnePage(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := NewDocument().WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "/Count 1")
+}
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, `Total \(NGN\) \\ 5`, encode(`Total (NGN) \ 5`))
+	assert.Equal(t, `caf\351 ?`, encode("café €"))
+	assert.Len(t, encode(strings.Repeat("x", LineWidth+10)), LineWidth)
+}
@@ -66,3 +66,9 @@ func (rg *RouteGroup) WithPermission(permissionMiddleware gin.HandlerFunc) *Rout
 	rg.group.Use(permissionMiddleware)
 	return rg
 }
+
+// Use adds middleware that runs after authentication and permission checks
+func (rg *RouteGroup) Use(middleware ...gin.HandlerFunc) *RouteGroup {
+	rg.group.Use(middleware...)
+	return rg
+}
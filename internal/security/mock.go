@@ -19,6 +19,14 @@ func (m *MockMaker) CreateToken(userID uuid.UUID, duration time.Duration, versio
 	return args.String(0), args.Get(1).(*Payload), args.Error(2)
 }
 
+func (m *MockMaker) CreateImpersonationToken(userID, impersonatorID uuid.UUID, duration time.Duration, version int64) (string, *Payload, error) {
+	args := m.Called(userID, impersonatorID, duration, version)
+	if args.Get(1) == nil {
+		return args.String(0), nil, args.Error(2)
+	}
+	return args.String(0), args.Get(1).(*Payload), args.Error(2)
+}
+
 func (m *MockMaker) VerifyToken(token string) (*Payload, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
@@ -26,3 +34,13 @@ func (m *MockMaker) VerifyToken(token string) (*Payload, error) {
 	}
 	return args.Get(0).(*Payload), args.Error(1)
 }
+
+func (m *MockMaker) Seal(claims interface{}, purpose string) (string, error) {
+	args := m.Called(claims, purpose)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockMaker) Open(token, purpose string, claims interface{}) error {
+	args := m.Called(token, purpose, claims)
+	return args.Error(0)
+}
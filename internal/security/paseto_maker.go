@@ -1,6 +1,7 @@
 package security
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -41,12 +42,26 @@ func (m *PasetoMaker) CreateToken(userID uuid.UUID, duration time.Duration, vers
 	return tokenString, payload, err
 }
 
+// CreateImpersonationToken creates a token that lets impersonatorID act as userID
+func (m *PasetoMaker) CreateImpersonationToken(userID, impersonatorID uuid.UUID,
+	duration time.Duration, version int64) (string, *Payload, error) {
+	payload, err := NewImpersonationPayload(userID, impersonatorID, duration, version)
+	if err != nil {
+		return "", nil, err
+	}
+	tokenString, err := m.peseto.Encrypt(m.symmetricKey, payload, nil)
+
+	return tokenString, payload, err
+}
+
 // VerifyToken checks if the token is valid or not
 func (m *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	payload := &Payload{}
-	err := m.peseto.Decrypt(token, m.symmetricKey, payload, nil)
+	var footer string
+	err := m.peseto.Decrypt(token, m.symmetricKey, payload, &footer)
 
-	if err != nil {
+	// Sealed tokens carry their purpose in the footer and are never access tokens
+	if err != nil || footer != "" {
 		return nil, ErrInvalidToken
 	}
 
@@ -57,3 +72,24 @@ func (m *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 
 	return payload, nil
 }
+
+// Seal encrypts claims into a token whose authenticated footer records purpose,
+// so a token sealed for one use is refused by every other
+func (m *PasetoMaker) Seal(claims interface{}, purpose string) (string, error) {
+	if purpose == "" {
+		return "", errors.New("seal: purpose is required")
+	}
+	return m.peseto.Encrypt(m.symmetricKey, claims, purpose)
+}
+
+// Open decrypts a token made by Seal for purpose into claims
+func (m *PasetoMaker) Open(token, purpose string, claims interface{}) error {
+	var footer string
+	if err := m.peseto.Decrypt(token, m.symmetricKey, claims, &footer); err != nil {
+		return ErrInvalidToken
+	}
+	if footer != purpose {
+		return ErrInvalidToken
+	}
+	return nil
+}
@@ -21,6 +21,8 @@ type Payload struct {
 	ExpiredAt time.Time
 	Version   int64
 	Scope     string
+	// ImpersonatorID is the admin acting as UserID; set only on impersonation tokens.
+	ImpersonatorID *uuid.UUID
 }
 
 // NewPayload creates a new token with a specific username and duration
@@ -42,6 +44,21 @@ func NewPayload(userID uuid.UUID, duration time.Duration, version int64, scope s
 	return payload, nil
 }
 
+// NewImpersonationPayload creates a payload that lets impersonatorID act as userID.
+func NewImpersonationPayload(userID, impersonatorID uuid.UUID, duration time.Duration, version int64) (*Payload, error) {
+	payload, err := NewPayload(userID, duration, version, TokenScopeImpersonation)
+	if err != nil {
+		return nil, err
+	}
+	payload.ImpersonatorID = &impersonatorID
+	return payload, nil
+}
+
+// IsImpersonation reports whether the token was issued to an admin acting as the user.
+func (p *Payload) IsImpersonation() bool {
+	return p.Scope == TokenScopeImpersonation && p.ImpersonatorID != nil
+}
+
 func (p *Payload) Valid() error {
 	if time.Now().After(p.ExpiredAt) {
 		return ErrExpiredToken
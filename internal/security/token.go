@@ -9,6 +9,8 @@ import (
 const (
 	TokenScopeAccess  = "access"
 	TokenScopeRefresh = "refresh"
+	// TokenScopeImpersonation marks read-only tokens issued to an admin acting as a user
+	TokenScopeImpersonation = "impersonation"
 )
 
 // Maker makes a new token
@@ -17,6 +19,15 @@ type Maker interface {
 	// CreateToken creates a new token for a specific username and duration
 	CreateToken(userID uuid.UUID, duration time.Duration, version int64, scope string) (string, *Payload, error)
 
+	// CreateImpersonationToken creates a token that lets impersonatorID act as userID
+	CreateImpersonationToken(userID, impersonatorID uuid.UUID, duration time.Duration, version int64) (string, *Payload, error)
+
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
+
+	// Seal encrypts claims into a tamper-proof token bound to purpose
+	Seal(claims interface{}, purpose string) (string, error)
+
+	// Open decrypts a token made by Seal for the same purpose into claims
+	Open(token, purpose string, claims interface{}) error
 }
@@ -0,0 +1,25 @@
+-- KYC submissions
+CREATE TABLE kyc_submissions
+(
+    id                 UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id            UUID         NOT NULL REFERENCES users (id),
+    provider           VARCHAR(50)  NOT NULL,
+    provider_reference VARCHAR(100),
+    status             VARCHAR(20)  NOT NULL    DEFAULT 'pending' CHECK (status IN ('pending', 'in_progress', 'verified', 'rejected')),
+    document_type      VARCHAR(30)  NOT NULL CHECK (document_type IN ('nin', 'bvn', 'passport', 'drivers_license', 'voters_card')),
+    document_number    VARCHAR(50)  NOT NULL,
+    first_name         VARCHAR(100) NOT NULL,
+    last_name          VARCHAR(100) NOT NULL,
+    date_of_birth      DATE         NOT NULL,
+    address            TEXT,
+    rejection_reason   TEXT,
+    reviewed_by        UUID REFERENCES users (id),
+    reviewed_at        TIMESTAMP WITH TIME ZONE,
+    provider_data      JSONB,
+    created_at         TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at         TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_kyc_submissions_user ON kyc_submissions (user_id);
+CREATE INDEX idx_kyc_submissions_status ON kyc_submissions (status);
+CREATE INDEX idx_kyc_submissions_provider_ref ON kyc_submissions (provider, provider_reference);
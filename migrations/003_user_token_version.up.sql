@@ -0,0 +1,3 @@
+-- Token version; bumping it invalidates every token issued before the change
+ALTER TABLE users
+    ADD COLUMN token_version BIGINT NOT NULL DEFAULT 1;
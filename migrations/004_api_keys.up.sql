@@ -0,0 +1,19 @@
+-- API keys for programmatic access
+CREATE TABLE api_keys
+(
+    id           UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         VARCHAR(100) NOT NULL,
+    key_id       VARCHAR(40)  NOT NULL UNIQUE,
+    secret_hash  VARCHAR(64)  NOT NULL,
+    scopes       JSONB        NOT NULL    DEFAULT '[]',
+    ip_allowlist JSONB        NOT NULL    DEFAULT '[]',
+    expires_at   TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    last_used_ip VARCHAR(45),
+    revoked_at   TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_api_keys_user ON api_keys (user_id);
@@ -0,0 +1,17 @@
+-- One row per issued access token
+CREATE TABLE user_sessions
+(
+    id           UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_jti    VARCHAR(255) NOT NULL UNIQUE,
+    device_name  VARCHAR(100),
+    user_agent   TEXT,
+    ip_address   VARCHAR(45),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at   TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_sessions_user ON user_sessions (user_id);
+CREATE INDEX idx_user_sessions_user_device ON user_sessions (user_id, device_name);
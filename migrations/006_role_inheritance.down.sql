@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_roles_parent;
+
+ALTER TABLE roles
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,5 @@
+-- A role may extend another role and inherit its permissions
+ALTER TABLE roles
+    ADD COLUMN parent_id UUID REFERENCES roles (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_roles_parent ON roles (parent_id);
@@ -0,0 +1,3 @@
+-- Support the admin audit log filters
+CREATE INDEX idx_audit_logs_resource ON audit_logs (resource_type, resource_id);
+CREATE INDEX idx_audit_logs_action ON audit_logs (action);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reality_checks;
+DROP TABLE IF EXISTS player_exclusions;
+DROP TABLE IF EXISTS player_limits;
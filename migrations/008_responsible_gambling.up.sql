@@ -0,0 +1,37 @@
+-- Limits players place on their own deposits, stakes and losses
+CREATE TABLE player_limits
+(
+    id             UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id        UUID           NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    limit_type     VARCHAR(20)    NOT NULL CHECK (limit_type IN ('deposit', 'stake', 'loss')),
+    period         VARCHAR(20)    NOT NULL CHECK (period IN ('daily', 'weekly', 'monthly')),
+    amount         DECIMAL(20, 2) NOT NULL CHECK (amount > 0),
+    pending_amount DECIMAL(20, 2) CHECK (pending_amount > 0),
+    pending_from   TIMESTAMP WITH TIME ZONE,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_player_limits_user_type_period ON player_limits (user_id, limit_type, period);
+
+-- Time-outs and self-exclusions; a NULL ends_at is permanent
+CREATE TABLE player_exclusions
+(
+    id             UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id        UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    exclusion_type VARCHAR(20) NOT NULL CHECK (exclusion_type IN ('time_out', 'self_exclusion')),
+    reason         TEXT,
+    starts_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at        TIMESTAMP WITH TIME ZONE,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_player_exclusions_user ON player_exclusions (user_id);
+
+CREATE TABLE reality_checks
+(
+    user_id              UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    interval_minutes     INTEGER NOT NULL DEFAULT 0 CHECK (interval_minutes >= 0),
+    last_acknowledged_at TIMESTAMP WITH TIME ZONE,
+    updated_at           TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
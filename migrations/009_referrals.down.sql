@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS referral_rewards;
+DROP TABLE IF EXISTS referrals;
+DROP INDEX IF EXISTS idx_users_referral_code;
+
+-- Bonus ledger entries are kept; the original constraint is restored for new rows only
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_transaction_type_check CHECK (transaction_type IN
+                                                              ('deposit', 'withdrawal', 'bet_place', 'bet_refund',
+                                                               'payout', 'fee')) NOT VALID;
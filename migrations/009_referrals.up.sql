@@ -0,0 +1,42 @@
+-- Referral rewards are credited to the ledger as bonuses
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_transaction_type_check CHECK (transaction_type IN
+                                                              ('deposit', 'withdrawal', 'bet_place', 'bet_refund',
+                                                               'payout', 'fee', 'bonus'));
+
+CREATE UNIQUE INDEX idx_users_referral_code ON users ((metadata ->> 'referral_code'))
+    WHERE metadata ->> 'referral_code' IS NOT NULL;
+
+CREATE TABLE referrals
+(
+    id          UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    referrer_id UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    referee_id  UUID        NOT NULL UNIQUE REFERENCES users (id) ON DELETE CASCADE,
+    code        VARCHAR(16) NOT NULL,
+    status      VARCHAR(20) NOT NULL     DEFAULT 'active' CHECK (status IN ('active', 'flagged')),
+    flag_reason TEXT,
+    signup_ip   INET,
+    device_id   VARCHAR(128),
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_referrals_referrer ON referrals (referrer_id);
+CREATE INDEX idx_referrals_signup_ip ON referrals (signup_ip);
+CREATE INDEX idx_referrals_device ON referrals (device_id);
+
+CREATE TABLE referral_rewards
+(
+    id             UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    referral_id    UUID           NOT NULL REFERENCES referrals (id) ON DELETE CASCADE,
+    user_id        UUID           NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    milestone      VARCHAR(30)    NOT NULL,
+    amount         DECIMAL(20, 2) NOT NULL CHECK (amount > 0),
+    currency_code  VARCHAR(3)     NOT NULL,
+    transaction_id UUID           NOT NULL REFERENCES transactions (id),
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_referral_rewards_milestone ON referral_rewards (referral_id, user_id, milestone);
+CREATE INDEX idx_referral_rewards_user ON referral_rewards (user_id);
@@ -0,0 +1,18 @@
+-- Set when a user's personal data is erased; the row stays for financial records
+ALTER TABLE users
+    ADD COLUMN erased_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE data_exports
+(
+    id           UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id      UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status       VARCHAR(20) NOT NULL     DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    file_path    TEXT,
+    error        TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at   TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_data_exports_user ON data_exports (user_id, created_at DESC);
@@ -0,0 +1,17 @@
+-- Responses to requests sent with an Idempotency-Key header, replayed on retry
+CREATE TABLE idempotency_keys
+(
+    id              UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id         UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key             VARCHAR(255) NOT NULL,
+    fingerprint     VARCHAR(64)  NOT NULL,
+    status          VARCHAR(20)  NOT NULL    DEFAULT 'processing' CHECK (status IN ('processing', 'completed')),
+    response_status INTEGER,
+    response_body   BYTEA,
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_idempotency_keys_user_key ON idempotency_keys (user_id, key);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
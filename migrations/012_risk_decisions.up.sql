@@ -0,0 +1,18 @@
+-- Every verdict of the bet risk engine, with its reasons and score, for analytics
+CREATE TABLE risk_decisions
+(
+    id            UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id       UUID           NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    market_id     UUID           NOT NULL REFERENCES markets (id) ON DELETE CASCADE,
+    country_code  VARCHAR(3),
+    amount        DECIMAL(20, 2) NOT NULL,
+    currency_code VARCHAR(3),
+    outcome       VARCHAR(10)    NOT NULL CHECK (outcome IN ('allow', 'review', 'deny')),
+    score         DECIMAL(10, 6) NOT NULL  DEFAULT 0,
+    reasons       JSONB          NOT NULL  DEFAULT '[]',
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_risk_decisions_user ON risk_decisions (user_id, created_at DESC);
+CREATE INDEX idx_risk_decisions_market ON risk_decisions (market_id);
+CREATE INDEX idx_risk_decisions_outcome ON risk_decisions (outcome, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS min_bet_amount,
+    DROP COLUMN IF EXISTS max_bet_amount;
@@ -0,0 +1,4 @@
+-- Optional per-category bet limits, applied between the country's and the market's
+ALTER TABLE categories
+    ADD COLUMN min_bet_amount DECIMAL(20, 2) CHECK (min_bet_amount > 0),
+    ADD COLUMN max_bet_amount DECIMAL(20, 2) CHECK (max_bet_amount > 0);
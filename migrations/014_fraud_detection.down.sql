@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS fraud_case_accounts;
+DROP TABLE IF EXISTS fraud_cases;
+DROP TABLE IF EXISTS account_signals;
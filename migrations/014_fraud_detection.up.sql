@@ -0,0 +1,42 @@
+-- Identifying signals seen per account; only hashes of the values are stored
+CREATE TABLE account_signals
+(
+    id            UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id       UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type          VARCHAR(30) NOT NULL CHECK (type IN ('ip', 'device', 'payment_instrument', 'phone')),
+    value_hash    VARCHAR(64) NOT NULL,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_account_signals_user_value ON account_signals (user_id, type, value_hash);
+CREATE INDEX idx_account_signals_value ON account_signals (type, value_hash);
+
+-- Clusters of accounts flagged for multi-accounting or collusion, awaiting review
+CREATE TABLE fraud_cases
+(
+    id             UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    kind           VARCHAR(30) NOT NULL CHECK (kind IN ('multi_accounting', 'collusion')),
+    status         VARCHAR(20) NOT NULL     DEFAULT 'open' CHECK (status IN ('open', 'confirmed', 'dismissed')),
+    market_id      UUID REFERENCES markets (id) ON DELETE SET NULL,
+    evidence       JSONB       NOT NULL     DEFAULT '{}',
+    wallets_locked BOOLEAN     NOT NULL     DEFAULT false,
+    review_note    TEXT,
+    reviewed_by    UUID REFERENCES users (id),
+    reviewed_at    TIMESTAMP WITH TIME ZONE,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_fraud_cases_queue ON fraud_cases (status, created_at);
+CREATE INDEX idx_fraud_cases_market ON fraud_cases (market_id);
+
+CREATE TABLE fraud_case_accounts
+(
+    case_id    UUID NOT NULL REFERENCES fraud_cases (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (case_id, user_id)
+);
+
+CREATE INDEX idx_fraud_case_accounts_user ON fraud_case_accounts (user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS parlay_legs;
+DROP TABLE IF EXISTS parlays;
@@ -0,0 +1,36 @@
+-- Combination bets: one stake across outcomes in several markets
+CREATE TABLE parlays
+(
+    id                UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id           UUID           NOT NULL REFERENCES users (id),
+    currency_code     VARCHAR(3)     NOT NULL,
+    stake             DECIMAL(20, 2) NOT NULL CHECK (stake > 0),
+    combined_price    NUMERIC        NOT NULL CHECK (combined_price > 0 AND combined_price <= 1),
+    potential_payout  DECIMAL(20, 2) NOT NULL,
+    transaction_id    UUID           NOT NULL REFERENCES transactions (id),
+    status            VARCHAR(20)              DEFAULT 'active' CHECK (status IN ('active', 'won', 'lost', 'refunded')),
+    settled_at        TIMESTAMP WITH TIME ZONE,
+    settlement_amount DECIMAL(20, 2),
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_parlays_user ON parlays (user_id, created_at);
+CREATE INDEX idx_parlays_status ON parlays (status);
+
+CREATE TABLE parlay_legs
+(
+    id                UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    parlay_id         UUID           NOT NULL REFERENCES parlays (id) ON DELETE CASCADE,
+    market_id         UUID           NOT NULL REFERENCES markets (id),
+    market_outcome_id UUID           NOT NULL REFERENCES market_outcomes (id),
+    price             DECIMAL(10, 6) NOT NULL CHECK (price > 0 AND price < 1),
+    status            VARCHAR(20)              DEFAULT 'pending' CHECK (status IN ('pending', 'won', 'lost', 'void')),
+    settled_at        TIMESTAMP WITH TIME ZONE,
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (parlay_id, market_id)
+);
+
+CREATE INDEX idx_parlay_legs_parlay ON parlay_legs (parlay_id);
+CREATE INDEX idx_parlay_legs_market ON parlay_legs (market_id, status);
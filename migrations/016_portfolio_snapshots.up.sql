@@ -0,0 +1,18 @@
+-- End-of-day holdings per user and market, for portfolio history charts
+CREATE TABLE portfolio_snapshots
+(
+    id                   UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id              UUID           NOT NULL REFERENCES users (id),
+    snapshot_date        DATE           NOT NULL,
+    market_id            UUID           NOT NULL REFERENCES markets (id),
+    category_id          UUID           NOT NULL REFERENCES categories (id),
+    open_stake           DECIMAL(20, 2) NOT NULL  DEFAULT 0,
+    open_value           DECIMAL(20, 2) NOT NULL  DEFAULT 0,
+    settled_stake        DECIMAL(20, 2) NOT NULL  DEFAULT 0,
+    realized_profit_loss DECIMAL(20, 2) NOT NULL  DEFAULT 0,
+    daily_stake          DECIMAL(20, 2) NOT NULL  DEFAULT 0,
+    created_at           TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at           TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_portfolio_snapshots_day ON portfolio_snapshots (user_id, snapshot_date, market_id);
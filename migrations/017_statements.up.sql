@@ -0,0 +1,22 @@
+-- Account statements, requested by users or support or produced monthly
+CREATE TABLE statements
+(
+    id           UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id      UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind         VARCHAR(20) NOT NULL CHECK (kind IN ('requested', 'monthly')),
+    format       VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'pdf')),
+    period_start DATE        NOT NULL,
+    period_end   DATE        NOT NULL CHECK (period_end >= period_start),
+    status       VARCHAR(20) NOT NULL     DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    file_path    TEXT,
+    error        TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_statements_user ON statements (user_id, period_start DESC);
+
+-- At most one monthly statement per user and month, besides any that failed
+CREATE UNIQUE INDEX idx_statements_monthly ON statements (user_id, period_start)
+    WHERE kind = 'monthly' AND status <> 'failed';
@@ -0,0 +1,11 @@
+-- Keys created since the up migration cannot be converted back and stay unusable
+ALTER TABLE api_keys
+    ADD COLUMN secret_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE revoked_at IS NULL;
+
+ALTER TABLE api_keys
+    DROP COLUMN secret_ciphertext,
+    ALTER COLUMN secret_hash DROP DEFAULT;
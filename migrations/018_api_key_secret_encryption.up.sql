@@ -0,0 +1,13 @@
+-- API key secrets are kept encrypted with the token key. The digest stored
+-- before was itself the signing key, so every key issued under it is revoked
+-- and its owner has to create a new one.
+ALTER TABLE api_keys
+    ADD COLUMN secret_ciphertext TEXT NOT NULL DEFAULT '';
+
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE revoked_at IS NULL;
+
+ALTER TABLE api_keys
+    DROP COLUMN secret_hash,
+    ALTER COLUMN secret_ciphertext DROP DEFAULT;
@@ -0,0 +1,3 @@
+ALTER TABLE risk_decisions
+    DROP COLUMN parlay_id,
+    DROP COLUMN bet_id;
@@ -0,0 +1,8 @@
+-- Risk decisions point at the bet or parlay they let through, so verdicts can
+-- be joined to how the bets turned out. Refused bets have neither.
+ALTER TABLE risk_decisions
+    ADD COLUMN bet_id    UUID REFERENCES bets (id) ON DELETE SET NULL,
+    ADD COLUMN parlay_id UUID REFERENCES parlays (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_risk_decisions_bet ON risk_decisions (bet_id) WHERE bet_id IS NOT NULL;
+CREATE INDEX idx_risk_decisions_parlay ON risk_decisions (parlay_id) WHERE parlay_id IS NOT NULL;
@@ -0,0 +1,5 @@
+UPDATE bets b
+SET contracts_bought = b.contracts_bought * COALESCE(NULLIF((c.config ->> 'contract_unit')::DECIMAL, 0), 100)
+FROM markets m
+         JOIN countries c ON c.id = m.country_id
+WHERE m.id = b.market_id;
@@ -0,0 +1,8 @@
+-- Contracts now pay out the country's contract unit instead of one currency
+-- unit, so bets bought before the change hold unit times too many contracts.
+-- Countries without a unit use the default of 100.
+UPDATE bets b
+SET contracts_bought = b.contracts_bought / COALESCE(NULLIF((c.config ->> 'contract_unit')::DECIMAL, 0), 100)
+FROM markets m
+         JOIN countries c ON c.id = m.country_id
+WHERE m.id = b.market_id;
@@ -0,0 +1,2 @@
+ALTER TABLE bets
+    DROP COLUMN quote_nonce;
@@ -0,0 +1,6 @@
+-- Each sealed quote carries a nonce, recorded on the bet placed on it, so a
+-- quote can only be bet on once
+ALTER TABLE bets
+    ADD COLUMN quote_nonce UUID;
+
+CREATE UNIQUE INDEX idx_bets_quote_nonce ON bets (quote_nonce) WHERE quote_nonce IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bets_settled_at;
+DROP TABLE IF EXISTS portfolio_realized_totals;
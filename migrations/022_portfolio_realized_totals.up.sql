@@ -0,0 +1,29 @@
+-- Cumulative settled stake and realized P&L per user and category, written
+-- on the days bets settle. Portfolio snapshots then only need the markets a
+-- user holds open or traded on the day.
+CREATE TABLE portfolio_realized_totals
+(
+    id                   UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id              UUID           NOT NULL REFERENCES users (id),
+    category_id          UUID           NOT NULL REFERENCES categories (id),
+    snapshot_date        DATE           NOT NULL,
+    settled_stake        DECIMAL(20, 2) NOT NULL  DEFAULT 0,
+    realized_profit_loss DECIMAL(20, 2) NOT NULL  DEFAULT 0,
+    created_at           TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at           TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_portfolio_realized_totals_day ON portfolio_realized_totals (user_id, category_id, snapshot_date);
+CREATE INDEX idx_bets_settled_at ON bets (settled_at) WHERE settled_at IS NOT NULL;
+
+-- Seed each user's totals to date, as of the last day they had a bet settle
+INSERT INTO portfolio_realized_totals (user_id, category_id, snapshot_date, settled_stake, realized_profit_loss)
+SELECT bets.user_id,
+       markets.category_id,
+       COALESCE(MAX(bets.settled_at), MAX(bets.updated_at))::DATE,
+       SUM(bets.amount),
+       SUM(COALESCE(bets.settlement_amount, 0) - bets.amount)
+FROM bets
+         JOIN markets ON markets.id = bets.market_id
+WHERE bets.status = 'settled'
+GROUP BY bets.user_id, markets.category_id;
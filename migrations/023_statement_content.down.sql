@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_statements_pending;
+
+ALTER TABLE statements
+    ADD COLUMN file_path TEXT;
+
+UPDATE statements
+SET status       = 'failed',
+    error        = 'the statement is no longer available, please request a new one',
+    completed_at = NOW()
+WHERE status = 'ready';
+
+ALTER TABLE statements
+    DROP COLUMN content;
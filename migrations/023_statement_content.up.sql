@@ -0,0 +1,17 @@
+-- Statement files are kept in the database, so any instance can serve them
+-- and they survive restarts. Statements written to local files before this
+-- are marked failed: monthly ones are produced again and requested ones can
+-- be asked for again.
+ALTER TABLE statements
+    ADD COLUMN content BYTEA;
+
+UPDATE statements
+SET status       = 'failed',
+    error        = 'the statement is no longer available, please request a new one',
+    completed_at = NOW()
+WHERE status = 'ready';
+
+ALTER TABLE statements
+    DROP COLUMN file_path;
+
+CREATE INDEX idx_statements_pending ON statements (created_at) WHERE status = 'pending';
@@ -0,0 +1,11 @@
+ALTER TABLE data_exports
+    ADD COLUMN file_path TEXT;
+
+UPDATE data_exports
+SET status       = 'failed',
+    error        = 'the export is no longer available, please request a new one',
+    completed_at = NOW()
+WHERE status = 'ready';
+
+ALTER TABLE data_exports
+    DROP COLUMN content;
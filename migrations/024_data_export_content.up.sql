@@ -0,0 +1,14 @@
+-- Export archives are kept in the database, so any instance can serve them
+-- and they survive restarts. Archives written to local files before this are
+-- marked failed so a new export can be requested.
+ALTER TABLE data_exports
+    ADD COLUMN content BYTEA;
+
+UPDATE data_exports
+SET status       = 'failed',
+    error        = 'the export is no longer available, please request a new one',
+    completed_at = NOW()
+WHERE status = 'ready';
+
+ALTER TABLE data_exports
+    DROP COLUMN file_path;
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountSignalType is a kind of identifier that can tie one person to several accounts
+type AccountSignalType string
+
+const (
+	AccountSignalIP                AccountSignalType = "ip"
+	AccountSignalDevice            AccountSignalType = "device"
+	AccountSignalPaymentInstrument AccountSignalType = "payment_instrument"
+	AccountSignalPhone             AccountSignalType = "phone"
+)
+
+// IsValid reports whether the signal type is one the platform records
+func (t AccountSignalType) IsValid() bool {
+	switch t {
+	case AccountSignalIP, AccountSignalDevice, AccountSignalPaymentInstrument, AccountSignalPhone:
+		return true
+	}
+	return false
+}
+
+// AccountSignal records that a user was seen with an IP address, device,
+// payment instrument or phone number. Only a hash of the value is stored;
+// accounts that share a hash are candidates for multi-accounting.
+type AccountSignal struct {
+	ID          uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_account_signals_user_value" json:"user_id"`
+	Type        AccountSignalType `gorm:"type:varchar(30);not null;uniqueIndex:idx_account_signals_user_value" json:"type"`
+	ValueHash   string            `gorm:"type:varchar(64);not null;uniqueIndex:idx_account_signals_user_value" json:"-"`
+	FirstSeenAt time.Time         `gorm:"not null" json:"first_seen_at"`
+	LastSeenAt  time.Time         `gorm:"not null" json:"last_seen_at"`
+}
+
+// TableName specifies the table name for AccountSignal model
+func (*AccountSignal) TableName() string {
+	return "account_signals"
+}
+
+// BeforeCreate sets up the model before creation
+func (s *AccountSignal) BeforeCreate(_ *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// HashAccountSignal normalizes a signal value and returns its hex encoded
+// SHA-256, salted with the type so equal values of different types never match.
+func HashAccountSignal(t AccountSignalType, value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	sum := sha256.Sum256([]byte(string(t) + ":" + normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountSignalType_IsValid(t *testing.T) {
+	assert.True(t, AccountSignalIP.IsValid())
+	assert.True(t, AccountSignalPaymentInstrument.IsValid())
+	assert.False(t, AccountSignalType("email").IsValid())
+}
+
+func TestHashAccountSignal(t *testing.T) {
+	hash := HashAccountSignal(AccountSignalDevice, "Device-ABC")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashAccountSignal(AccountSignalDevice, "  device-abc "))
+	assert.NotEqual(t, hash, HashAccountSignal(AccountSignalPaymentInstrument, "device-abc"))
+	assert.NotEqual(t, hash, HashAccountSignal(AccountSignalDevice, "device-abd"))
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyScope limits what an API key may be used for
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead       APIKeyScope = "read"
+	APIKeyScopeTrade      APIKeyScope = "trade"
+	APIKeyScopeWalletRead APIKeyScope = "wallet:read"
+)
+
+// ValidAPIKeyScopes lists every scope a key may be granted
+var ValidAPIKeyScopes = []APIKeyScope{APIKeyScopeRead, APIKeyScopeTrade, APIKeyScopeWalletRead}
+
+// APIKeyScopes is the set of scopes granted to a key
+type APIKeyScopes []APIKeyScope
+
+// Value implements driver.Valuer interface
+func (s APIKeyScopes) Value() (driver.Value, error) {
+	if s == nil {
+		return json.Marshal([]APIKeyScope{})
+	}
+	return json.Marshal([]APIKeyScope(s))
+}
+
+// Scan implements sql.Scanner interface
+func (s *APIKeyScopes) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return nil
+}
+
+// Has checks if the scope set contains scope
+func (s APIKeyScopes) Has(scope APIKeyScope) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyIPAllowlist holds IP addresses or CIDR ranges a key may be used from
+type APIKeyIPAllowlist []string
+
+// Value implements driver.Valuer interface
+func (l APIKeyIPAllowlist) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal([]string(l))
+}
+
+// Scan implements sql.Scanner interface
+func (l *APIKeyIPAllowlist) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+	return nil
+}
+
+// Allows checks if ip matches the allowlist. An empty allowlist allows every address.
+func (l APIKeyIPAllowlist) Allows(ip net.IP) bool {
+	if len(l) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, entry := range l {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey is a user managed credential for programmatic access
+type APIKey struct {
+	ID               uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID           uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name             string            `gorm:"type:varchar(100);not null" json:"name"`
+	KeyID            string            `gorm:"type:varchar(40);not null;uniqueIndex" json:"key_id"`
+	SecretCiphertext string            `gorm:"type:text;not null" json:"-"`
+	Scopes           APIKeyScopes      `gorm:"type:jsonb;not null;default:'[]'" json:"scopes"`
+	IPAllowlist      APIKeyIPAllowlist `gorm:"type:jsonb;not null;default:'[]'" json:"ip_allowlist"`
+	ExpiresAt        *time.Time        `gorm:"type:timestamptz" json:"expires_at"`
+	LastUsedAt       *time.Time        `gorm:"type:timestamptz" json:"last_used_at"`
+	LastUsedIP       string            `gorm:"type:varchar(45)" json:"last_used_ip"`
+	RevokedAt        *time.Time        `gorm:"type:timestamptz" json:"revoked_at"`
+	CreatedAt        time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Associations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for APIKey model
+func (*APIKey) TableName() string {
+	return "api_keys"
+}
+
+// BeforeCreate sets up the model before creation
+func (k *APIKey) BeforeCreate(_ *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsExpired checks if the key has passed its expiry
+func (k *APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+// IsRevoked checks if the key has been revoked by its owner
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// IsUsable checks if the key can still authenticate requests
+func (k *APIKey) IsUsable() bool {
+	return !k.IsExpired() && !k.IsRevoked()
+}
+
+// Validate performs validation on the API key model
+func (k *APIKey) Validate() error {
+	if k.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if k.KeyID == "" || k.SecretCiphertext == "" {
+		return ErrInvalidAPIKey
+	}
+	if len(k.Scopes) == 0 {
+		return ErrInvalidAPIKeyScope
+	}
+	for _, scope := range k.Scopes {
+		valid := false
+		for _, known := range ValidAPIKeyScopes {
+			if scope == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return ErrInvalidAPIKeyScope
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey(t *testing.T) {
+	t.Run("TableName", func(t *testing.T) {
+		k := APIKey{}
+		assert.Equal(t, "api_keys", k.TableName())
+	})
+
+	t.Run("BeforeCreate", func(t *testing.T) {
+		k := APIKey{}
+		assert.NoError(t, k.BeforeCreate(nil))
+		assert.NotEqual(t, uuid.Nil, k.ID)
+	})
+
+	t.Run("IsUsable", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		future := time.Now().Add(time.Hour)
+
+		assert.True(t, (&APIKey{}).IsUsable())
+		assert.True(t, (&APIKey{ExpiresAt: &future}).IsUsable())
+		assert.False(t, (&APIKey{ExpiresAt: &past}).IsUsable())
+		assert.False(t, (&APIKey{RevokedAt: &past}).IsUsable())
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		k := APIKey{UserID: uuid.New(), KeyID: "nk_1", SecretCiphertext: "sealed", Scopes: APIKeyScopes{APIKeyScopeRead}}
+		assert.NoError(t, k.Validate())
+
+		k.Scopes = APIKeyScopes{"admin"}
+		assert.ErrorIs(t, k.Validate(), ErrInvalidAPIKeyScope)
+
+		k.Scopes = nil
+		assert.ErrorIs(t, k.Validate(), ErrInvalidAPIKeyScope)
+
+		k.Scopes = APIKeyScopes{APIKeyScopeTrade}
+		k.SecretCiphertext = ""
+		assert.ErrorIs(t, k.Validate(), ErrInvalidAPIKey)
+
+		k.UserID = uuid.Nil
+		assert.ErrorIs(t, k.Validate(), ErrInvalidUserID)
+	})
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	scopes := APIKeyScopes{APIKeyScopeRead, APIKeyScopeTrade}
+	assert.True(t, scopes.Has(APIKeyScopeTrade))
+	assert.False(t, scopes.Has(APIKeyScopeWalletRead))
+
+	value, err := scopes.Value()
+	assert.NoError(t, err)
+
+	var scanned APIKeyScopes
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, scopes, scanned)
+}
+
+func TestAPIKeyIPAllowlist(t *testing.T) {
+	assert.True(t, APIKeyIPAllowlist{}.Allows(net.ParseIP("1.2.3.4")))
+
+	allowlist := APIKeyIPAllowlist{"10.0.0.0/8", "192.168.1.10"}
+	assert.True(t, allowlist.Allows(net.ParseIP("10.20.30.40")))
+	assert.True(t, allowlist.Allows(net.ParseIP("192.168.1.10")))
+	assert.False(t, allowlist.Allows(net.ParseIP("192.168.1.11")))
+	assert.False(t, allowlist.Allows(nil))
+
+	value, err := allowlist.Value()
+	assert.NoError(t, err)
+
+	var scanned APIKeyIPAllowlist
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, allowlist, scanned)
+}
@@ -10,6 +10,58 @@ import (
 	"gorm.io/gorm"
 )
 
+// Audit actions
+const (
+	AuditActionAccountLocked      = "account_locked"
+	AuditActionAccountUnlocked    = "account_unlocked"
+	AuditActionKYCReviewed        = "kyc_reviewed"
+	AuditActionSessionsRevoked    = "sessions_revoked"
+	AuditActionAdminRequest       = "admin_request"
+	AuditActionImpersonation      = "impersonation_started"
+	AuditActionImpersonated       = "impersonated_request"
+	AuditActionWalletCreated      = "wallet_created"
+	AuditActionWalletLocked       = "wallet_locked"
+	AuditActionWalletUnlocked     = "wallet_unlocked"
+	AuditActionWalletCredited     = "wallet_credited"
+	AuditActionWalletDebited      = "wallet_debited"
+	AuditActionFundsLocked        = "funds_locked"
+	AuditActionFundsUnlocked      = "funds_unlocked"
+	AuditActionBetPlaced          = "bet_placed"
+	AuditActionBetRefunded        = "bet_refunded"
+	AuditActionBetSettled         = "bet_settled"
+	AuditActionParlayPlaced       = "parlay_placed"
+	AuditActionParlaySettled      = "parlay_settled"
+	AuditActionPlayerLimitSet     = "player_limit_set"
+	AuditActionPlayerExcluded     = "player_excluded"
+	AuditActionExportRequested    = "data_export_requested"
+	AuditActionAccountErased      = "account_erased"
+	AuditActionProfileUpdated     = "profile_updated"
+	AuditActionPasswordChanged    = "password_changed"
+	AuditActionEmailChanged       = "email_changed"
+	AuditActionPhoneChanged       = "phone_changed"
+	AuditActionFraudReviewed      = "fraud_case_reviewed"
+	AuditActionStatementIssued    = "statement_issued"
+	AuditActionUserStatusChanged  = "user_status_changed"
+	AuditActionRoleAssigned       = "role_assigned"
+	AuditActionRoleRevoked        = "role_revoked"
+	AuditActionPermissionsGranted = "permissions_granted"
+	AuditActionRoleCreated        = "role_created"
+	AuditActionRoleUpdated        = "role_updated"
+	AuditActionRoleDeleted        = "role_deleted"
+	AuditActionPermissionCreated  = "permission_created"
+	AuditActionPermissionDeleted  = "permission_deleted"
+)
+
+// Audit resource types
+const (
+	AuditResourceUser          = "user"
+	AuditResourceKYCSubmission = "kyc_submission"
+	AuditResourceWallet        = "wallet"
+	AuditResourceFraudCase     = "fraud_case"
+	AuditResourceRole          = "role"
+	AuditResourcePermission    = "permission"
+)
+
 // AuditValues represents values for audit logging
 type AuditValues map[string]interface{}
 
@@ -61,6 +61,7 @@ type Bet struct {
 	SettledAt        *time.Time       `gorm:"type:timestamptz" json:"settled_at"`
 	SettlementAmount *decimal.Decimal `gorm:"type:decimal(20,2)" json:"settlement_amount"`
 	Metadata         *BetMetadata     `gorm:"type:jsonb;default:'{}'" json:"metadata"`
+	QuoteNonce       *uuid.UUID       `gorm:"type:uuid;uniqueIndex:idx_bets_quote_nonce,where:quote_nonce IS NOT NULL" json:"-"`
 	CreatedAt        time.Time        `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt        time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
@@ -16,8 +17,12 @@ type Category struct {
 	Description string    `gorm:"type:text" json:"description"`
 	IsActive    bool      `gorm:"default:true" json:"is_active"`
 	SortOrder   int       `gorm:"default:0" json:"sort_order"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	// MinBetAmount and MaxBetAmount narrow the country's bet limits for the
+	// category's markets; nil leaves the country limit in place
+	MinBetAmount *decimal.Decimal `gorm:"type:decimal(20,2)" json:"min_bet_amount,omitempty"`
+	MaxBetAmount *decimal.Decimal `gorm:"type:decimal(20,2)" json:"max_bet_amount,omitempty"`
+	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Associations
 	Country *Country `gorm:"foreignKey:CountryID;constraint:OnDelete:CASCADE" json:"country,omitempty"`
@@ -48,6 +53,13 @@ func (c *Category) Validate() error {
 	if c.Slug == "" {
 		return ErrInvalidCategorySlug
 	}
+	if (c.MinBetAmount != nil && !c.MinBetAmount.IsPositive()) ||
+		(c.MaxBetAmount != nil && !c.MaxBetAmount.IsPositive()) {
+		return ErrInvalidBetAmountLimits
+	}
+	if c.MinBetAmount != nil && c.MaxBetAmount != nil && c.MinBetAmount.GreaterThan(*c.MaxBetAmount) {
+		return ErrInvalidBetAmountLimits
+	}
 	return nil
 }
 
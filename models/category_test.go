@@ -5,6 +5,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -73,6 +74,38 @@ func TestCategory(t *testing.T) {
 				},
 				expectedErr: ErrInvalidCategorySlug,
 			},
+			{
+				name: "Category bet limits",
+				category: Category{
+					CountryID:    uuid.New(),
+					Name:         "Sports",
+					Slug:         "sports",
+					MinBetAmount: decimalPtr(200),
+					MaxBetAmount: decimalPtr(20000),
+				},
+				expectedErr: nil,
+			},
+			{
+				name: "Category min bet above max bet",
+				category: Category{
+					CountryID:    uuid.New(),
+					Name:         "Sports",
+					Slug:         "sports",
+					MinBetAmount: decimalPtr(500),
+					MaxBetAmount: decimalPtr(100),
+				},
+				expectedErr: ErrInvalidBetAmountLimits,
+			},
+			{
+				name: "Category max bet not positive",
+				category: Category{
+					CountryID:    uuid.New(),
+					Name:         "Sports",
+					Slug:         "sports",
+					MaxBetAmount: decimalPtr(0),
+				},
+				expectedErr: ErrInvalidBetAmountLimits,
+			},
 		}
 
 		for _, tt := range tests {
@@ -208,3 +241,8 @@ func TestCategory(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func decimalPtr(v int64) *decimal.Decimal {
+	d := decimal.NewFromInt(v)
+	return &d
+}
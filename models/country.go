@@ -18,6 +18,9 @@ type CountryConfig struct {
 	KYCRequired  bool            `json:"kyc_required"`
 	TaxRate      decimal.Decimal `json:"tax_rate,omitempty"`
 	Timezone     string          `json:"timezone,omitempty"`
+	// RiskRules replaces the global risk settings for bets on the country's
+	// markets when set
+	RiskRules *RiskRuleSettings `json:"risk_rules,omitempty"`
 }
 
 // Value implements driver.Valuer interface for database storage
@@ -77,7 +80,7 @@ func (c *Country) IsValidCurrency() bool {
 
 // GetMinBetAmount returns the minimum bet amount for this country
 func (c *Country) GetMinBetAmount() decimal.Decimal {
-	if !c.Config.MinBet.IsZero() {
+	if c != nil && c.Config != nil && !c.Config.MinBet.IsZero() {
 		return c.Config.MinBet
 	}
 	return decimal.NewFromInt(100)
@@ -85,7 +88,7 @@ func (c *Country) GetMinBetAmount() decimal.Decimal {
 
 // GetMaxBetAmount returns the maximum bet amount for this country
 func (c *Country) GetMaxBetAmount() decimal.Decimal {
-	if !c.Config.MaxBet.IsZero() {
+	if c != nil && c.Config != nil && !c.Config.MaxBet.IsZero() {
 		return c.Config.MaxBet
 	}
 	return decimal.NewFromInt(50000)
@@ -93,15 +96,24 @@ func (c *Country) GetMaxBetAmount() decimal.Decimal {
 
 // GetContractUnit returns the contract unit value for this country
 func (c *Country) GetContractUnit() decimal.Decimal {
-	if !c.Config.ContractUnit.IsZero() {
+	if c != nil && c.Config != nil && !c.Config.ContractUnit.IsZero() {
 		return c.Config.ContractUnit
 	}
 	return decimal.NewFromInt(100)
 }
 
+// GetRiskRules returns the country's own risk settings, or nil to use the
+// global ones
+func (c *Country) GetRiskRules() *RiskRuleSettings {
+	if c == nil || c.Config == nil {
+		return nil
+	}
+	return c.Config.RiskRules
+}
+
 // RequiresKYC returns whether KYC is required for this country
 func (c *Country) RequiresKYC() bool {
-	return c.Config.KYCRequired
+	return c != nil && c.Config != nil && c.Config.KYCRequired
 }
 
 func (c *Country) IsActiveValue() bool {
@@ -403,6 +403,16 @@ func TestCountry(t *testing.T) {
 		assert.Equal(t, decimal.NewFromFloat(50.0), c.GetContractUnit())
 	})
 
+	t.Run("Defaults without a config", func(t *testing.T) {
+		var missing *Country
+		assert.Equal(t, decimal.NewFromInt(100), missing.GetContractUnit())
+		assert.False(t, missing.RequiresKYC())
+
+		c := Country{}
+		assert.Equal(t, decimal.NewFromInt(100), c.GetMinBetAmount())
+		assert.Equal(t, decimal.NewFromInt(50000), c.GetMaxBetAmount())
+	})
+
 	t.Run("RequiresKYC", func(t *testing.T) {
 		c := newEmptyCountry()
 		assert.False(t, c.RequiresKYC())
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DataExportStatus represents the progress of a personal data export
+type DataExportStatus string
+
+const (
+	DataExportStatusPending DataExportStatus = "pending"
+	DataExportStatusReady   DataExportStatus = "ready"
+	DataExportStatusFailed  DataExportStatus = "failed"
+)
+
+// DataExport is an archive of everything held about a user, built in the
+// background on request and kept until it expires.
+type DataExport struct {
+	ID          uuid.UUID        `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status      DataExportStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Content     []byte           `gorm:"type:bytea" json:"-"`
+	Error       string           `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time       `gorm:"type:timestamptz" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time       `gorm:"type:timestamptz" json:"expires_at,omitempty"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for DataExport model
+func (*DataExport) TableName() string {
+	return "data_exports"
+}
+
+// BeforeCreate sets up the model before creation
+func (e *DataExport) BeforeCreate(_ *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsExpired checks if a ready export can no longer be downloaded
+func (e *DataExport) IsExpired(at time.Time) bool {
+	return e.ExpiresAt != nil && !at.Before(*e.ExpiresAt)
+}
+
+// IsDownloadable checks if the archive is built and not yet expired
+func (e *DataExport) IsDownloadable(at time.Time) bool {
+	return e.Status == DataExportStatusReady && !e.IsExpired(at)
+}
+
+// IsCurrent checks if the export is still being built or can be downloaded,
+// in which case a new request should reuse it
+func (e *DataExport) IsCurrent(at time.Time) bool {
+	return e.Status == DataExportStatusPending || e.IsDownloadable(at)
+}
+
+// FileName is what the archive is called when downloaded
+func (e *DataExport) FileName() string {
+	return "neo-data-export-" + e.ID.String() + ".zip"
+}
+
+// MarkReady records a successfully built archive
+func (e *DataExport) MarkReady(content []byte, at time.Time, ttl time.Duration) {
+	expiresAt := at.Add(ttl)
+	e.Status = DataExportStatusReady
+	e.Content = content
+	e.CompletedAt = &at
+	e.ExpiresAt = &expiresAt
+}
+
+// MarkFailed records why the archive could not be built
+func (e *DataExport) MarkFailed(reason string, at time.Time) {
+	e.Status = DataExportStatusFailed
+	e.Error = reason
+	e.CompletedAt = &at
+}
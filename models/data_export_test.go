@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataExport_Lifecycle(t *testing.T) {
+	now := time.Now()
+	export := &DataExport{Status: DataExportStatusPending}
+	assert.True(t, export.IsCurrent(now))
+	assert.False(t, export.IsDownloadable(now))
+
+	export.MarkReady([]byte("zip"), now, time.Hour)
+	assert.True(t, export.IsDownloadable(now))
+	assert.True(t, export.IsCurrent(now.Add(59*time.Minute)))
+	assert.True(t, export.IsExpired(now.Add(time.Hour)))
+	assert.False(t, export.IsCurrent(now.Add(time.Hour)))
+
+	failed := &DataExport{Status: DataExportStatusPending}
+	failed.MarkFailed("disk full", now)
+	assert.False(t, failed.IsCurrent(now))
+	assert.Equal(t, "disk full", failed.Error)
+}
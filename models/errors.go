@@ -17,6 +17,36 @@ var (
 	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
 	ErrInvalidUserID    = errors.New("invalid user ID")
 
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrAccountLocked        = errors.New("account is temporarily locked")
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+	ErrTokenRevoked         = errors.New("token has been revoked")
+	ErrImpersonationDenied  = errors.New("user cannot be impersonated")
+
+	ErrEmailTaken              = errors.New("email address is already in use")
+	ErrPhoneTaken              = errors.New("phone number is already in use")
+	ErrInvalidVerificationCode = errors.New("invalid or expired verification code")
+
+	ErrInvalidParentRole    = errors.New("invalid parent role")
+	ErrRoleInheritanceCycle = errors.New("role inheritance would create a cycle")
+	ErrRoleInUse            = errors.New("role is assigned to users")
+
+	ErrInvalidAPIKey          = errors.New("invalid API key")
+	ErrInvalidAPIKeyScope     = errors.New("invalid API key scope")
+	ErrInvalidAPIKeySignature = errors.New("invalid API key signature")
+	ErrAPIKeyScopeDenied      = errors.New("API key does not have the required scope")
+	ErrAPIKeyIPNotAllowed     = errors.New("API key cannot be used from this IP address")
+	ErrAPIKeyRequestReplayed  = errors.New("API key request is outside the replay window")
+
+	ErrKYCNotVerified       = errors.New("KYC verification required")
+	ErrKYCAlreadyVerified   = errors.New("KYC already verified")
+	ErrKYCSubmissionPending = errors.New("KYC submission already under review")
+	ErrKYCSubmissionFinal   = errors.New("KYC submission already decided")
+	ErrInvalidKYCDocument   = errors.New("invalid KYC document")
+	ErrInvalidKYCProvider   = errors.New("invalid KYC provider")
+	ErrInvalidKYCSignature  = errors.New("invalid KYC webhook signature")
+	ErrWithdrawalNotAllowed = errors.New("user is not allowed to withdraw")
+
 	ErrInvalidMarketTitle    = errors.New("invalid market title")
 	ErrInvalidMarketType     = errors.New("invalid market type")
 	ErrInvalidMarketStatus   = errors.New("invalid market status")
@@ -66,6 +96,7 @@ var (
 	ErrInvalidBetTimeout         = errors.New("invalid bet timeout")
 	ErrInvalidRateLimit          = errors.New("invalid rate limit")
 	ErrInvalidCooldownPeriod     = errors.New("invalid cooldown period")
+	ErrInvalidRiskScoreThreshold = errors.New("invalid risk score threshold")
 	ErrSlippageExceeded          = errors.New("slippage tolerance exceeded")
 	ErrPositionLimitExceeded     = errors.New("position limit exceeded")
 	ErrMarketNotOpenForBetting   = errors.New("market not open for betting")
@@ -73,6 +104,44 @@ var (
 	ErrBetCooldownActive         = errors.New("bet cooldown period active")
 	ErrDailyLimitExceeded        = errors.New("daily betting limit exceeded")
 	ErrRateLimitExceeded         = errors.New("betting rate limit exceeded")
+	ErrBetRefused                = errors.New("bet refused by risk checks")
+
+	ErrInvalidPortfolioSettings = errors.New("invalid portfolio snapshot settings")
+	ErrInvalidHistoryRange      = errors.New("invalid history date range")
+
+	ErrInvalidQuoteTolerance = errors.New("invalid quote tolerance")
+	ErrInvalidQuote          = errors.New("invalid quote")
+	ErrQuoteExpired          = errors.New("quote has expired")
+	ErrQuoteMismatch         = errors.New("bet does not match its quote")
+	ErrQuotePriceMoved       = errors.New("price has moved beyond the quote tolerance")
+	ErrQuoteUsed             = errors.New("quote has already been used")
+
+	ErrInvalidParlayLimits    = errors.New("invalid parlay limits")
+	ErrParlayLegCount         = errors.New("parlay has too many or too few legs")
+	ErrParlayDuplicateMarket  = errors.New("parlay legs must be on different markets")
+	ErrParlayCurrencyMismatch = errors.New("parlay legs must be on markets in the same currency")
+	ErrParlayPayoutTooLarge   = errors.New("parlay payout exceeds maximum")
+
+	ErrPlayerExcluded       = errors.New("account is excluded from gambling")
+	ErrDepositLimitExceeded = errors.New("deposit limit exceeded")
+	ErrStakeLimitExceeded   = errors.New("stake limit exceeded")
+	ErrLossLimitExceeded    = errors.New("loss limit exceeded")
+
+	ErrInvalidReferralCode = errors.New("invalid referral code")
+
+	ErrFraudCaseClosed        = errors.New("fraud case has already been decided")
+	ErrInvalidFraudCaseStatus = errors.New("invalid fraud case status")
+
+	ErrOpenPositions  = errors.New("account has open positions")
+	ErrNonZeroBalance = errors.New("account has a non-zero wallet balance")
+	ErrAccountErased  = errors.New("account has been erased")
+	ErrExportNotReady = errors.New("data export is not ready")
+
+	ErrStatementNotReady = errors.New("statement is not ready")
+
+	ErrIdempotencyKeyInUse  = errors.New("idempotency key is in use by a request still being processed")
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyLost   = errors.New("idempotency key was handed to a retry before the request finished")
 
 	ErrInvalidUUID    = errors.New("invalid UUID")
 	ErrRecordNotFound = errors.New("record not found")
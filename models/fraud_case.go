@@ -0,0 +1,182 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// FraudCaseKind is the pattern that opened a fraud case
+type FraudCaseKind string
+
+const (
+	// FraudCaseMultiAccounting is a cluster of accounts sharing identifying signals
+	FraudCaseMultiAccounting FraudCaseKind = "multi_accounting"
+	// FraudCaseCollusion is linked accounts taking opposite sides of a thin market
+	FraudCaseCollusion FraudCaseKind = "collusion"
+)
+
+// FraudCaseStatus tracks a case through the admin review queue
+type FraudCaseStatus string
+
+const (
+	FraudCaseStatusOpen      FraudCaseStatus = "open"
+	FraudCaseStatusConfirmed FraudCaseStatus = "confirmed"
+	FraudCaseStatusDismissed FraudCaseStatus = "dismissed"
+)
+
+// FraudPosition is one linked account's stake on a market outcome
+type FraudPosition struct {
+	UserID    uuid.UUID       `json:"user_id"`
+	OutcomeID uuid.UUID       `json:"outcome_id"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// FraudEvidence is what the detector saw when it opened or grew a case
+type FraudEvidence struct {
+	// Signals are the signal types the accounts were found to share
+	Signals []AccountSignalType `json:"signals,omitempty"`
+	// Positions are the linked accounts' stakes on the case's market
+	Positions []FraudPosition `json:"positions,omitempty"`
+}
+
+// AddSignals records signal types not already in the evidence and reports
+// whether any were added
+func (e *FraudEvidence) AddSignals(types ...AccountSignalType) bool {
+	added := false
+	for _, t := range types {
+		found := false
+		for _, existing := range e.Signals {
+			if existing == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			e.Signals = append(e.Signals, t)
+			added = true
+		}
+	}
+	return added
+}
+
+// Value implements driver.Valuer interface
+func (e FraudEvidence) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+// Scan implements sql.Scanner interface
+func (e *FraudEvidence) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	}
+	return nil
+}
+
+// FraudCase is a cluster of accounts flagged for multi-accounting or
+// collusion, waiting on an admin decision.
+type FraudCase struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Kind          FraudCaseKind   `gorm:"type:varchar(30);not null;index" json:"kind"`
+	Status        FraudCaseStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	MarketID      *uuid.UUID      `gorm:"type:uuid;index" json:"market_id,omitempty"`
+	Evidence      FraudEvidence   `gorm:"type:jsonb;not null" json:"evidence"`
+	WalletsLocked bool            `gorm:"not null;default:false" json:"wallets_locked"`
+	ReviewNote    string          `gorm:"type:text" json:"review_note,omitempty"`
+	ReviewedBy    *uuid.UUID      `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time      `gorm:"type:timestamptz" json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Accounts []FraudCaseAccount `gorm:"foreignKey:CaseID" json:"accounts,omitempty"`
+}
+
+// TableName specifies the table name for FraudCase model
+func (*FraudCase) TableName() string {
+	return "fraud_cases"
+}
+
+// BeforeCreate sets up the model before creation
+func (fc *FraudCase) BeforeCreate(_ *gorm.DB) error {
+	if fc.ID == uuid.Nil {
+		fc.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsOpen reports whether the case still needs a decision
+func (fc *FraudCase) IsOpen() bool {
+	return fc.Status == FraudCaseStatusOpen
+}
+
+// UserIDs returns the accounts in the case
+func (fc *FraudCase) UserIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(fc.Accounts))
+	for _, account := range fc.Accounts {
+		ids = append(ids, account.UserID)
+	}
+	return ids
+}
+
+// AddAccounts adds users not already in the case and returns the ones added
+func (fc *FraudCase) AddAccounts(userIDs ...uuid.UUID) []uuid.UUID {
+	var added []uuid.UUID
+	for _, userID := range userIDs {
+		found := false
+		for _, account := range fc.Accounts {
+			if account.UserID == userID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fc.Accounts = append(fc.Accounts, FraudCaseAccount{CaseID: fc.ID, UserID: userID})
+			added = append(added, userID)
+		}
+	}
+	return added
+}
+
+// LockReason is the wallet lock reason for accounts held by this case, so
+// dismissing the case releases only the locks it placed.
+func (fc *FraudCase) LockReason() string {
+	return "fraud case " + fc.ID.String()
+}
+
+// Resolve records an admin decision on an open case
+func (fc *FraudCase) Resolve(status FraudCaseStatus, reviewerID uuid.UUID, note string) error {
+	if !fc.IsOpen() {
+		return ErrFraudCaseClosed
+	}
+	if status != FraudCaseStatusConfirmed && status != FraudCaseStatusDismissed {
+		return ErrInvalidFraudCaseStatus
+	}
+	now := time.Now()
+	fc.Status = status
+	fc.ReviewNote = note
+	fc.ReviewedBy = &reviewerID
+	fc.ReviewedAt = &now
+	return nil
+}
+
+// FraudCaseAccount puts one user in a fraud case
+type FraudCaseAccount struct {
+	CaseID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for FraudCaseAccount model
+func (*FraudCaseAccount) TableName() string {
+	return "fraud_case_accounts"
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFraudEvidence_AddSignals(t *testing.T) {
+	var evidence FraudEvidence
+	assert.True(t, evidence.AddSignals(AccountSignalDevice, AccountSignalIP, AccountSignalDevice))
+	assert.Equal(t, []AccountSignalType{AccountSignalDevice, AccountSignalIP}, evidence.Signals)
+	assert.False(t, evidence.AddSignals(AccountSignalIP))
+}
+
+func TestFraudEvidence_ValueScan(t *testing.T) {
+	evidence := FraudEvidence{
+		Signals:   []AccountSignalType{AccountSignalPhone},
+		Positions: []FraudPosition{{UserID: uuid.New(), OutcomeID: uuid.New()}},
+	}
+	value, err := evidence.Value()
+	require.NoError(t, err)
+
+	var scanned FraudEvidence
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, evidence.Signals, scanned.Signals)
+	assert.Equal(t, evidence.Positions[0].UserID, scanned.Positions[0].UserID)
+}
+
+func TestFraudCase_AddAccounts(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	fraudCase := &FraudCase{ID: uuid.New()}
+
+	assert.Equal(t, []uuid.UUID{first, second}, fraudCase.AddAccounts(first, second))
+	assert.Empty(t, fraudCase.AddAccounts(second))
+	assert.Equal(t, []uuid.UUID{first, second}, fraudCase.UserIDs())
+	assert.Equal(t, fraudCase.ID, fraudCase.Accounts[0].CaseID)
+}
+
+func TestFraudCase_Resolve(t *testing.T) {
+	reviewer := uuid.New()
+	fraudCase := &FraudCase{Status: FraudCaseStatusOpen}
+
+	assert.ErrorIs(t, fraudCase.Resolve(FraudCaseStatusOpen, reviewer, "note"), ErrInvalidFraudCaseStatus)
+
+	require.NoError(t, fraudCase.Resolve(FraudCaseStatusDismissed, reviewer, "shared office network"))
+	assert.False(t, fraudCase.IsOpen())
+	assert.Equal(t, &reviewer, fraudCase.ReviewedBy)
+	assert.NotNil(t, fraudCase.ReviewedAt)
+
+	assert.ErrorIs(t, fraudCase.Resolve(FraudCaseStatusConfirmed, reviewer, "note"), ErrFraudCaseClosed)
+}
+
+func TestFraudCase_LockReason(t *testing.T) {
+	fraudCase := &FraudCase{ID: uuid.New()}
+	assert.Contains(t, fraudCase.LockReason(), fraudCase.ID.String())
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyStatus represents how far the request behind a key has got
+type IdempotencyKeyStatus string
+
+const (
+	IdempotencyKeyStatusProcessing IdempotencyKeyStatus = "processing"
+	IdempotencyKeyStatusCompleted  IdempotencyKeyStatus = "completed"
+)
+
+// IdempotencyKey records the first request a user sent with an Idempotency-Key
+// header and, once it finishes, the response to replay for retries of it.
+type IdempotencyKey struct {
+	ID             uuid.UUID            `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID         uuid.UUID            `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"user_id"`
+	Key            string               `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+	Fingerprint    string               `gorm:"type:varchar(64);not null" json:"fingerprint"`
+	Status         IdempotencyKeyStatus `gorm:"type:varchar(20);not null;default:'processing'" json:"status"`
+	ResponseStatus int                  `json:"response_status,omitempty"`
+	ResponseBody   []byte               `gorm:"type:bytea" json:"response_body,omitempty"`
+	ExpiresAt      time.Time            `gorm:"type:timestamptz;not null;index" json:"expires_at"`
+	CreatedAt      time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for IdempotencyKey model
+func (*IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// BeforeCreate sets up the model before creation
+func (k *IdempotencyKey) BeforeCreate(_ *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsCompleted checks if the response is stored and can be replayed
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.Status == IdempotencyKeyStatusCompleted
+}
+
+// IsExpired checks if the key can be used again for a new request
+func (k *IdempotencyKey) IsExpired(at time.Time) bool {
+	return !at.Before(k.ExpiresAt)
+}
+
+// IsAbandoned checks if a request still marked as processing has run for longer
+// than lockTimeout, which means the instance handling it went away
+func (k *IdempotencyKey) IsAbandoned(at time.Time, lockTimeout time.Duration) bool {
+	return !k.IsCompleted() && !at.Before(k.UpdatedAt.Add(lockTimeout))
+}
+
+// Matches checks if a retry carries the same request as the one first stored
+func (k *IdempotencyKey) Matches(fingerprint string) bool {
+	return k.Fingerprint == fingerprint
+}
+
+// Complete stores the response to replay for retries
+func (k *IdempotencyKey) Complete(status int, body []byte) {
+	k.Status = IdempotencyKeyStatusCompleted
+	k.ResponseStatus = status
+	k.ResponseBody = body
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKey_Lifecycle(t *testing.T) {
+	now := time.Now()
+	key := &IdempotencyKey{
+		Fingerprint: "abc",
+		Status:      IdempotencyKeyStatusProcessing,
+		ExpiresAt:   now.Add(time.Hour),
+		UpdatedAt:   now,
+	}
+	assert.True(t, key.Matches("abc"))
+	assert.False(t, key.Matches("def"))
+	assert.False(t, key.IsCompleted())
+	assert.False(t, key.IsAbandoned(now.Add(30*time.Second), time.Minute))
+	assert.True(t, key.IsAbandoned(now.Add(time.Minute), time.Minute))
+
+	key.Complete(http.StatusCreated, []byte(`{"success":true}`))
+	assert.True(t, key.IsCompleted())
+	assert.False(t, key.IsAbandoned(now.Add(time.Minute), time.Minute))
+	assert.Equal(t, http.StatusCreated, key.ResponseStatus)
+	assert.False(t, key.IsExpired(now))
+	assert.True(t, key.IsExpired(now.Add(time.Hour)))
+}
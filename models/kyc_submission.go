@@ -0,0 +1,140 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// KYCDocumentType represents the identity document used for verification
+type KYCDocumentType string
+
+const (
+	KYCDocumentNIN           KYCDocumentType = "nin"
+	KYCDocumentBVN           KYCDocumentType = "bvn"
+	KYCDocumentPassport      KYCDocumentType = "passport"
+	KYCDocumentDriverLicense KYCDocumentType = "drivers_license"
+	KYCDocumentVotersCard    KYCDocumentType = "voters_card"
+)
+
+// KYCSubmissionData holds provider-specific payloads attached to a submission
+type KYCSubmissionData map[string]interface{}
+
+// Value implements driver.Valuer interface
+func (d KYCSubmissionData) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return json.Marshal(d)
+}
+
+// Scan implements sql.Scanner interface
+func (d *KYCSubmissionData) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	}
+	return nil
+}
+
+// KYCSubmission represents a single identity verification attempt by a user
+type KYCSubmission struct {
+	ID                uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID            uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	Provider          string            `gorm:"type:varchar(50);not null" json:"provider"`
+	ProviderReference string            `gorm:"type:varchar(100);index" json:"provider_reference"`
+	Status            KYCStatus         `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	DocumentType      KYCDocumentType   `gorm:"type:varchar(30);not null" json:"document_type"`
+	DocumentNumber    string            `gorm:"type:varchar(50);not null" json:"-"`
+	FirstName         string            `gorm:"type:varchar(100);not null" json:"first_name"`
+	LastName          string            `gorm:"type:varchar(100);not null" json:"last_name"`
+	DateOfBirth       time.Time         `gorm:"type:date;not null" json:"date_of_birth"`
+	Address           string            `gorm:"type:text" json:"address"`
+	RejectionReason   string            `gorm:"type:text" json:"rejection_reason,omitempty"`
+	ReviewedBy        *uuid.UUID        `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt        *time.Time        `gorm:"type:timestamptz" json:"reviewed_at,omitempty"`
+	ProviderData      KYCSubmissionData `gorm:"type:jsonb" json:"provider_data,omitempty"`
+	CreatedAt         time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Associations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for KYCSubmission model
+func (*KYCSubmission) TableName() string {
+	return "kyc_submissions"
+}
+
+// BeforeCreate sets up the model before creation
+func (ks *KYCSubmission) BeforeCreate(_ *gorm.DB) error {
+	if ks.ID == uuid.Nil {
+		ks.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsFinal checks if the submission has reached a terminal status
+func (ks *KYCSubmission) IsFinal() bool {
+	return ks.Status == KYCStatusVerified || ks.Status == KYCStatusRejected
+}
+
+// NeedsReview checks if the submission is waiting on a decision
+func (ks *KYCSubmission) NeedsReview() bool {
+	return ks.Status == KYCStatusPending || ks.Status == KYCStatusInProgress
+}
+
+// MaskedDocumentNumber returns the document number with all but the last 4 characters hidden
+func (ks *KYCSubmission) MaskedDocumentNumber() string {
+	if len(ks.DocumentNumber) <= 4 {
+		return ks.DocumentNumber
+	}
+	masked := make([]byte, len(ks.DocumentNumber)-4)
+	for i := range masked {
+		masked[i] = '*'
+	}
+	return string(masked) + ks.DocumentNumber[len(ks.DocumentNumber)-4:]
+}
+
+// Validate performs validation on the KYC submission model
+func (ks *KYCSubmission) Validate() error {
+	if ks.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	switch ks.DocumentType {
+	case KYCDocumentNIN, KYCDocumentBVN, KYCDocumentPassport, KYCDocumentDriverLicense, KYCDocumentVotersCard:
+	default:
+		return ErrInvalidKYCDocument
+	}
+	if ks.DocumentNumber == "" {
+		return ErrInvalidKYCDocument
+	}
+	if ks.Provider == "" {
+		return ErrInvalidKYCProvider
+	}
+	return nil
+}
+
+// ApplyDecision moves the submission and its owner to a final KYC status
+func (ks *KYCSubmission) ApplyDecision(user *User, status KYCStatus, reason string) {
+	now := time.Now()
+	ks.Status = status
+	ks.RejectionReason = reason
+
+	user.KYCStatus = status
+	user.KYCProvider = ks.Provider
+	user.KYCReference = ks.ProviderReference
+	if status == KYCStatusVerified {
+		user.KYCVerifiedAt = &now
+	} else {
+		user.KYCVerifiedAt = nil
+	}
+}
@@ -217,6 +217,12 @@ func (m *Market) ValidateBetAmount(amount decimal.Decimal) error {
 	return nil
 }
 
+// GetContractUnit returns what one winning contract pays out, as set by the
+// market's country. The country default applies if Country is not loaded.
+func (m *Market) GetContractUnit() decimal.Decimal {
+	return m.Country.GetContractUnit()
+}
+
 // Resolve resolves the market with the given outcome
 func (m *Market) Resolve(outcome, source string) error {
 	if !m.CanResolve() {
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// A parlay chains at least MinParlayLegs and at most MaxParlayLegs selections
+const (
+	MinParlayLegs = 2
+	MaxParlayLegs = 10
+)
+
+// ParlayStatus represents the status of a parlay
+type ParlayStatus string
+
+const (
+	ParlayStatusActive   ParlayStatus = "active"
+	ParlayStatusWon      ParlayStatus = "won"
+	ParlayStatusLost     ParlayStatus = "lost"
+	ParlayStatusRefunded ParlayStatus = "refunded"
+)
+
+// ParlayLegStatus represents the result of one leg of a parlay
+type ParlayLegStatus string
+
+const (
+	ParlayLegStatusPending ParlayLegStatus = "pending"
+	ParlayLegStatusWon     ParlayLegStatus = "won"
+	ParlayLegStatusLost    ParlayLegStatus = "lost"
+	ParlayLegStatusVoid    ParlayLegStatus = "void"
+)
+
+// Parlay is a single stake on outcomes in several markets. It pays out only
+// if every leg wins, at the product of the legs' implied probabilities when
+// it was placed. Parlays are priced against the market pools but are not
+// part of them; the house carries the payout.
+type Parlay struct {
+	ID               uuid.UUID        `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID           uuid.UUID        `gorm:"type:uuid;not null;index:idx_parlays_user" json:"user_id"`
+	CurrencyCode     string           `gorm:"type:varchar(3);not null" json:"currency_code"`
+	Stake            decimal.Decimal  `gorm:"type:decimal(20,2);not null;check:stake > 0" json:"stake"`
+	CombinedPrice    decimal.Decimal  `gorm:"type:numeric;not null" json:"combined_price"`
+	PotentialPayout  decimal.Decimal  `gorm:"type:decimal(20,2);not null" json:"potential_payout"`
+	TransactionID    uuid.UUID        `gorm:"type:uuid;not null" json:"transaction_id"`
+	Status           ParlayStatus     `gorm:"type:varchar(20);default:'active';index" json:"status"`
+	SettledAt        *time.Time       `gorm:"type:timestamptz" json:"settled_at"`
+	SettlementAmount *decimal.Decimal `gorm:"type:decimal(20,2)" json:"settlement_amount"`
+	CreatedAt        time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Associations
+	Legs []ParlayLeg `gorm:"foreignKey:ParlayID" json:"legs,omitempty"`
+}
+
+// TableName specifies the table name for Parlay model
+func (*Parlay) TableName() string {
+	return "parlays"
+}
+
+// BeforeCreate sets up the model before creation
+func (p *Parlay) BeforeCreate(_ *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive checks if the parlay is still waiting on its legs
+func (p *Parlay) IsActive() bool {
+	return p.Status == ParlayStatusActive
+}
+
+// CombineParlayPrices multiplies the implied probabilities of a parlay's legs
+func CombineParlayPrices(prices ...decimal.Decimal) decimal.Decimal {
+	combined := decimal.NewFromInt(1)
+	for _, price := range prices {
+		combined = combined.Mul(price)
+	}
+	return combined
+}
+
+// ParlayPayout returns what a stake pays out at a combined price
+func ParlayPayout(stake, combinedPrice decimal.Decimal) decimal.Decimal {
+	if !combinedPrice.IsPositive() {
+		return decimal.Zero
+	}
+	return stake.Div(combinedPrice).Truncate(2)
+}
+
+// Reprice recomputes the combined price and payout from the legs that have
+// not been voided
+func (p *Parlay) Reprice() {
+	prices := make([]decimal.Decimal, 0, len(p.Legs))
+	for _, leg := range p.Legs {
+		if leg.Status != ParlayLegStatusVoid {
+			prices = append(prices, leg.Price)
+		}
+	}
+	p.CombinedPrice = CombineParlayPrices(prices...)
+	p.PotentialPayout = ParlayPayout(p.Stake, p.CombinedPrice)
+}
+
+// LegOn returns the parlay's leg on a market, or nil
+func (p *Parlay) LegOn(marketID uuid.UUID) *ParlayLeg {
+	for i := range p.Legs {
+		if p.Legs[i].MarketID == marketID {
+			return &p.Legs[i]
+		}
+	}
+	return nil
+}
+
+// SettleLeg records the result of the pending leg on a market. A voided leg
+// drops out of the price. It reports whether a leg was settled.
+func (p *Parlay) SettleLeg(marketID uuid.UUID, status ParlayLegStatus) bool {
+	leg := p.LegOn(marketID)
+	if leg == nil || leg.Status != ParlayLegStatusPending || status == ParlayLegStatusPending {
+		return false
+	}
+
+	now := time.Now()
+	leg.Status = status
+	leg.SettledAt = &now
+	if status == ParlayLegStatusVoid {
+		p.Reprice()
+	}
+	return true
+}
+
+// result decides the parlay from its legs. One lost leg loses it; otherwise
+// it waits for every leg, and is refunded if all of them were voided.
+func (p *Parlay) result() ParlayStatus {
+	pending, won := false, false
+	for _, leg := range p.Legs {
+		switch leg.Status {
+		case ParlayLegStatusLost:
+			return ParlayStatusLost
+		case ParlayLegStatusPending:
+			pending = true
+		case ParlayLegStatusWon:
+			won = true
+		}
+	}
+	switch {
+	case pending:
+		return ParlayStatusActive
+	case !won:
+		return ParlayStatusRefunded
+	}
+	return ParlayStatusWon
+}
+
+// Settle settles the parlay once its legs decide it, setting what is owed to
+// the user. It reports whether the parlay was settled.
+func (p *Parlay) Settle() bool {
+	if !p.IsActive() {
+		return false
+	}
+	status := p.result()
+	if status == ParlayStatusActive {
+		return false
+	}
+
+	amount := decimal.Zero
+	switch status {
+	case ParlayStatusWon:
+		amount = p.PotentialPayout
+	case ParlayStatusRefunded:
+		amount = p.Stake
+	}
+
+	now := time.Now()
+	p.Status = status
+	p.SettledAt = &now
+	p.SettlementAmount = &amount
+	return true
+}
+
+// ParlayLeg is one selection of a parlay
+type ParlayLeg struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ParlayID        uuid.UUID `gorm:"type:uuid;not null;index" json:"parlay_id"`
+	MarketID        uuid.UUID `gorm:"type:uuid;not null;index:idx_parlay_legs_market" json:"market_id"`
+	MarketOutcomeID uuid.UUID `gorm:"type:uuid;not null" json:"market_outcome_id"`
+	// Price is the outcome's implied probability (0-1) when the parlay was placed
+	Price     decimal.Decimal `gorm:"type:decimal(10,6);not null;check:price > 0" json:"price"`
+	Status    ParlayLegStatus `gorm:"type:varchar(20);default:'pending';index:idx_parlay_legs_market" json:"status"`
+	SettledAt *time.Time      `gorm:"type:timestamptz" json:"settled_at"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Associations
+	Market        *Market        `gorm:"foreignKey:MarketID" json:"market,omitempty"`
+	MarketOutcome *MarketOutcome `gorm:"foreignKey:MarketOutcomeID" json:"market_outcome,omitempty"`
+}
+
+// TableName specifies the table name for ParlayLeg model
+func (*ParlayLeg) TableName() string {
+	return "parlay_legs"
+}
+
+// BeforeCreate sets up the model before creation
+func (l *ParlayLeg) BeforeCreate(_ *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
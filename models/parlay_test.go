@@ -0,0 +1,92 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testParlay(prices ...float64) *Parlay {
+	parlay := &Parlay{Stake: decimal.NewFromInt(100), Status: ParlayStatusActive}
+	for _, price := range prices {
+		parlay.Legs = append(parlay.Legs, ParlayLeg{
+			MarketID: uuid.New(),
+			Price:    decimal.NewFromFloat(price),
+			Status:   ParlayLegStatusPending,
+		})
+	}
+	parlay.Reprice()
+	return parlay
+}
+
+func TestParlay_Reprice(t *testing.T) {
+	parlay := testParlay(0.5, 0.25)
+	assert.True(t, decimal.NewFromFloat(0.125).Equal(parlay.CombinedPrice), "combined %s", parlay.CombinedPrice)
+	assert.True(t, decimal.NewFromInt(800).Equal(parlay.PotentialPayout), "payout %s", parlay.PotentialPayout)
+
+	assert.True(t, decimal.NewFromFloat(33.33).Equal(ParlayPayout(decimal.NewFromInt(10), decimal.NewFromFloat(0.3))))
+	assert.True(t, ParlayPayout(decimal.NewFromInt(10), decimal.Zero).IsZero())
+}
+
+func TestParlay_SettleLeg(t *testing.T) {
+	parlay := testParlay(0.5, 0.25)
+	voided := parlay.Legs[1].MarketID
+
+	assert.False(t, parlay.SettleLeg(uuid.New(), ParlayLegStatusWon))
+	assert.False(t, parlay.SettleLeg(voided, ParlayLegStatusPending))
+
+	require.True(t, parlay.SettleLeg(voided, ParlayLegStatusVoid))
+	assert.NotNil(t, parlay.Legs[1].SettledAt)
+	assert.True(t, decimal.NewFromFloat(0.5).Equal(parlay.CombinedPrice), "combined %s", parlay.CombinedPrice)
+	assert.True(t, decimal.NewFromInt(200).Equal(parlay.PotentialPayout), "payout %s", parlay.PotentialPayout)
+
+	assert.False(t, parlay.SettleLeg(voided, ParlayLegStatusWon), "a decided leg is not settled again")
+}
+
+func TestParlay_Settle(t *testing.T) {
+	t.Run("Waits for every leg", func(t *testing.T) {
+		parlay := testParlay(0.5, 0.25)
+		parlay.SettleLeg(parlay.Legs[0].MarketID, ParlayLegStatusWon)
+		assert.False(t, parlay.Settle())
+		assert.True(t, parlay.IsActive())
+	})
+
+	t.Run("Wins when every leg wins", func(t *testing.T) {
+		parlay := testParlay(0.5, 0.25)
+		parlay.SettleLeg(parlay.Legs[0].MarketID, ParlayLegStatusWon)
+		parlay.SettleLeg(parlay.Legs[1].MarketID, ParlayLegStatusWon)
+		require.True(t, parlay.Settle())
+		assert.Equal(t, ParlayStatusWon, parlay.Status)
+		assert.True(t, decimal.NewFromInt(800).Equal(*parlay.SettlementAmount))
+		assert.False(t, parlay.Settle(), "a settled parlay is not settled again")
+	})
+
+	t.Run("Loses on the first lost leg", func(t *testing.T) {
+		parlay := testParlay(0.5, 0.25, 0.4)
+		parlay.SettleLeg(parlay.Legs[1].MarketID, ParlayLegStatusLost)
+		require.True(t, parlay.Settle())
+		assert.Equal(t, ParlayStatusLost, parlay.Status)
+		assert.True(t, parlay.SettlementAmount.IsZero())
+	})
+
+	t.Run("Pays the remaining legs when one is voided", func(t *testing.T) {
+		parlay := testParlay(0.5, 0.25)
+		parlay.SettleLeg(parlay.Legs[1].MarketID, ParlayLegStatusVoid)
+		parlay.SettleLeg(parlay.Legs[0].MarketID, ParlayLegStatusWon)
+		require.True(t, parlay.Settle())
+		assert.Equal(t, ParlayStatusWon, parlay.Status)
+		assert.True(t, decimal.NewFromInt(200).Equal(*parlay.SettlementAmount))
+	})
+
+	t.Run("Refunds the stake when every leg is voided", func(t *testing.T) {
+		parlay := testParlay(0.5, 0.25)
+		parlay.SettleLeg(parlay.Legs[0].MarketID, ParlayLegStatusVoid)
+		parlay.SettleLeg(parlay.Legs[1].MarketID, ParlayLegStatusVoid)
+		require.True(t, parlay.Settle())
+		assert.Equal(t, ParlayStatusRefunded, parlay.Status)
+		assert.True(t, parlay.Stake.Equal(*parlay.SettlementAmount))
+	})
+}
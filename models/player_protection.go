@@ -0,0 +1,184 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PlayerLimitType is the money flow a responsible gambling limit caps
+type PlayerLimitType string
+
+const (
+	PlayerLimitDeposit PlayerLimitType = "deposit"
+	PlayerLimitStake   PlayerLimitType = "stake"
+	PlayerLimitLoss    PlayerLimitType = "loss"
+)
+
+// PlayerLimitPeriod is the rolling window a limit applies to
+type PlayerLimitPeriod string
+
+const (
+	PlayerLimitDaily   PlayerLimitPeriod = "daily"
+	PlayerLimitWeekly  PlayerLimitPeriod = "weekly"
+	PlayerLimitMonthly PlayerLimitPeriod = "monthly"
+)
+
+// Window returns the length of the rolling window for the period
+func (p PlayerLimitPeriod) Window() time.Duration {
+	switch p {
+	case PlayerLimitWeekly:
+		return 7 * 24 * time.Hour
+	case PlayerLimitMonthly:
+		return 30 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// PlayerLimit is a limit a user placed on their own deposits, stakes or losses.
+// Decreases apply at once; increases and removals wait in the pending fields
+// until PendingFrom so a player cannot lift a limit in the heat of the moment.
+type PlayerLimit struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID        uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_player_limits_user_type_period" json:"user_id"`
+	LimitType     PlayerLimitType   `gorm:"type:varchar(20);not null;uniqueIndex:idx_player_limits_user_type_period" json:"limit_type"`
+	Period        PlayerLimitPeriod `gorm:"type:varchar(20);not null;uniqueIndex:idx_player_limits_user_type_period" json:"period"`
+	Amount        decimal.Decimal   `gorm:"type:decimal(20,2);not null" json:"amount"`
+	PendingAmount *decimal.Decimal  `gorm:"type:decimal(20,2)" json:"pending_amount,omitempty"`
+	PendingFrom   *time.Time        `gorm:"type:timestamptz" json:"pending_from,omitempty"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for PlayerLimit model
+func (*PlayerLimit) TableName() string {
+	return "player_limits"
+}
+
+// BeforeCreate sets up the model before creation
+func (l *PlayerLimit) BeforeCreate(_ *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// EffectiveAmount returns the limit in force at the given time. The second
+// value is false once a pending removal has taken effect.
+func (l *PlayerLimit) EffectiveAmount(at time.Time) (decimal.Decimal, bool) {
+	if l.PendingFrom == nil || at.Before(*l.PendingFrom) {
+		return l.Amount, true
+	}
+	if l.PendingAmount == nil {
+		return decimal.Zero, false
+	}
+	return *l.PendingAmount, true
+}
+
+// HasPendingChange reports whether an increase or removal is still waiting to apply.
+func (l *PlayerLimit) HasPendingChange(at time.Time) bool {
+	return l.PendingFrom != nil && at.Before(*l.PendingFrom)
+}
+
+// Settle folds a pending change that has taken effect into Amount. It returns
+// false when the change removed the limit.
+func (l *PlayerLimit) Settle(at time.Time) bool {
+	if l.PendingFrom == nil || at.Before(*l.PendingFrom) {
+		return true
+	}
+	amount, ok := l.EffectiveAmount(at)
+	l.Amount = amount
+	l.PendingAmount = nil
+	l.PendingFrom = nil
+	return ok
+}
+
+// PlayerExclusionType distinguishes a short time-out from a self-exclusion
+type PlayerExclusionType string
+
+const (
+	PlayerExclusionTimeOut       PlayerExclusionType = "time_out"
+	PlayerExclusionSelfExclusion PlayerExclusionType = "self_exclusion"
+)
+
+// PlayerExclusion blocks a user from betting and depositing until EndsAt.
+// A nil EndsAt is a permanent self-exclusion. Exclusions cannot be lifted early.
+type PlayerExclusion struct {
+	ID            uuid.UUID           `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID        uuid.UUID           `gorm:"type:uuid;not null;index" json:"user_id"`
+	ExclusionType PlayerExclusionType `gorm:"type:varchar(20);not null" json:"exclusion_type"`
+	Reason        string              `gorm:"type:text" json:"reason,omitempty"`
+	StartsAt      time.Time           `gorm:"type:timestamptz;not null" json:"starts_at"`
+	EndsAt        *time.Time          `gorm:"type:timestamptz" json:"ends_at,omitempty"`
+	CreatedAt     time.Time           `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for PlayerExclusion model
+func (*PlayerExclusion) TableName() string {
+	return "player_exclusions"
+}
+
+// BeforeCreate sets up the model before creation
+func (e *PlayerExclusion) BeforeCreate(_ *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsPermanent checks if the exclusion never ends
+func (e *PlayerExclusion) IsPermanent() bool {
+	return e.EndsAt == nil
+}
+
+// IsActiveAt checks if the exclusion is in force at the given time
+func (e *PlayerExclusion) IsActiveAt(at time.Time) bool {
+	if at.Before(e.StartsAt) {
+		return false
+	}
+	return e.EndsAt == nil || at.Before(*e.EndsAt)
+}
+
+// CoversUntil checks if the exclusion lasts at least until end, where a nil end means forever
+func (e *PlayerExclusion) CoversUntil(end *time.Time) bool {
+	if e.EndsAt == nil {
+		return true
+	}
+	return end != nil && !e.EndsAt.Before(*end)
+}
+
+// RealityCheck holds how often a user wants to be reminded of their time and
+// net result while playing. A zero interval turns the reminders off.
+type RealityCheck struct {
+	UserID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"user_id"`
+	IntervalMinutes    int        `gorm:"not null;default:0" json:"interval_minutes"`
+	LastAcknowledgedAt *time.Time `gorm:"type:timestamptz" json:"last_acknowledged_at,omitempty"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for RealityCheck model
+func (*RealityCheck) TableName() string {
+	return "reality_checks"
+}
+
+// Interval returns the reminder interval
+func (r *RealityCheck) Interval() time.Duration {
+	return time.Duration(r.IntervalMinutes) * time.Minute
+}
+
+// NextDueAt returns when the next reminder is due for a session that started
+// at sessionStart, or nil when reminders are off.
+func (r *RealityCheck) NextDueAt(sessionStart time.Time) *time.Time {
+	if r.IntervalMinutes <= 0 {
+		return nil
+	}
+	from := sessionStart
+	if r.LastAcknowledgedAt != nil && r.LastAcknowledgedAt.After(from) {
+		from = *r.LastAcknowledgedAt
+	}
+	due := from.Add(r.Interval())
+	return &due
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerLimit(t *testing.T) {
+	t.Run("TableName", func(t *testing.T) {
+		l := PlayerLimit{}
+		assert.Equal(t, "player_limits", l.TableName())
+	})
+
+	t.Run("BeforeCreate", func(t *testing.T) {
+		l := PlayerLimit{}
+		assert.NoError(t, l.BeforeCreate(nil))
+		assert.NotEqual(t, uuid.Nil, l.ID)
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		assert.Equal(t, 24*time.Hour, PlayerLimitDaily.Window())
+		assert.Equal(t, 7*24*time.Hour, PlayerLimitWeekly.Window())
+		assert.Equal(t, 30*24*time.Hour, PlayerLimitMonthly.Window())
+	})
+
+	t.Run("EffectiveAmount", func(t *testing.T) {
+		now := time.Now()
+		later := now.Add(time.Hour)
+		raised := decimal.NewFromInt(500)
+		l := PlayerLimit{Amount: decimal.NewFromInt(100), PendingAmount: &raised, PendingFrom: &later}
+
+		amount, ok := l.EffectiveAmount(now)
+		assert.True(t, ok)
+		assert.True(t, amount.Equal(decimal.NewFromInt(100)))
+		assert.True(t, l.HasPendingChange(now))
+
+		amount, ok = l.EffectiveAmount(later)
+		assert.True(t, ok)
+		assert.True(t, amount.Equal(raised))
+		assert.False(t, l.HasPendingChange(later))
+
+		l.PendingAmount = nil
+		_, ok = l.EffectiveAmount(later)
+		assert.False(t, ok, "a pending removal lifts the limit once due")
+	})
+
+	t.Run("Settle", func(t *testing.T) {
+		now := time.Now()
+		past := now.Add(-time.Minute)
+		raised := decimal.NewFromInt(500)
+		l := PlayerLimit{Amount: decimal.NewFromInt(100), PendingAmount: &raised, PendingFrom: &past}
+
+		assert.True(t, l.Settle(now))
+		assert.True(t, l.Amount.Equal(raised))
+		assert.Nil(t, l.PendingAmount)
+		assert.Nil(t, l.PendingFrom)
+
+		l.PendingFrom = &past
+		assert.False(t, l.Settle(now))
+	})
+}
+
+func TestPlayerExclusion(t *testing.T) {
+	t.Run("TableName", func(t *testing.T) {
+		e := PlayerExclusion{}
+		assert.Equal(t, "player_exclusions", e.TableName())
+	})
+
+	t.Run("IsActiveAt", func(t *testing.T) {
+		now := time.Now()
+		end := now.Add(time.Hour)
+		e := PlayerExclusion{StartsAt: now, EndsAt: &end}
+
+		assert.True(t, e.IsActiveAt(now))
+		assert.False(t, e.IsActiveAt(end))
+		assert.False(t, e.IsActiveAt(now.Add(-time.Second)))
+		assert.False(t, e.IsPermanent())
+
+		e.EndsAt = nil
+		assert.True(t, e.IsPermanent())
+		assert.True(t, e.IsActiveAt(now.AddDate(50, 0, 0)))
+	})
+
+	t.Run("CoversUntil", func(t *testing.T) {
+		now := time.Now()
+		end := now.Add(time.Hour)
+		later := now.Add(2 * time.Hour)
+		e := PlayerExclusion{StartsAt: now, EndsAt: &end}
+
+		assert.True(t, e.CoversUntil(&end))
+		assert.False(t, e.CoversUntil(&later))
+		assert.False(t, e.CoversUntil(nil))
+
+		e.EndsAt = nil
+		assert.True(t, e.CoversUntil(nil))
+	})
+}
+
+func TestRealityCheck(t *testing.T) {
+	t.Run("TableName", func(t *testing.T) {
+		r := RealityCheck{}
+		assert.Equal(t, "reality_checks", r.TableName())
+	})
+
+	t.Run("NextDueAt", func(t *testing.T) {
+		start := time.Now().Add(-2 * time.Hour)
+		r := RealityCheck{}
+		assert.Nil(t, r.NextDueAt(start))
+
+		r.IntervalMinutes = 60
+		assert.Equal(t, start.Add(time.Hour), *r.NextDueAt(start))
+
+		acknowledged := start.Add(90 * time.Minute)
+		r.LastAcknowledgedAt = &acknowledged
+		assert.Equal(t, acknowledged.Add(time.Hour), *r.NextDueAt(start))
+	})
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PortfolioRealizedTotal is a user's settled stake and realized P&L in one
+// category, cumulative up to the end of a day. A row is only written on days
+// a bet in the category settles, so the latest row up to a day holds for it.
+type PortfolioRealizedTotal struct {
+	ID                 uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID             uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_portfolio_realized_totals_day,priority:1" json:"user_id"`
+	CategoryID         uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_portfolio_realized_totals_day,priority:2" json:"category_id"`
+	SnapshotDate       time.Time       `gorm:"type:date;not null;uniqueIndex:idx_portfolio_realized_totals_day,priority:3" json:"snapshot_date"`
+	SettledStake       decimal.Decimal `gorm:"type:decimal(20,2);not null;default:0" json:"settled_stake"`
+	RealizedProfitLoss decimal.Decimal `gorm:"type:decimal(20,2);not null;default:0" json:"realized_profit_loss"`
+	CreatedAt          time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Associations
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+// TableName specifies the table name for PortfolioRealizedTotal model
+func (*PortfolioRealizedTotal) TableName() string {
+	return "portfolio_realized_totals"
+}
+
+// BeforeCreate sets up the model before creation
+func (t *PortfolioRealizedTotal) BeforeCreate(_ *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
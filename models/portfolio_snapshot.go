@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PortfolioSnapshot is a user's holding in one market as it stood at the end
+// of a day. Open amounts are the bets still active; settled stake and realized
+// P&L are cumulative up to the day, and DailyStake is what was staked on it.
+type PortfolioSnapshot struct {
+	ID                 uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID             uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_portfolio_snapshots_day,priority:1" json:"user_id"`
+	SnapshotDate       time.Time       `gorm:"type:date;not null;uniqueIndex:idx_portfolio_snapshots_day,priority:2" json:"snapshot_date"`
+	MarketID           uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_portfolio_snapshots_day,priority:3" json:"market_id"`
+	CategoryID         uuid.UUID       `gorm:"type:uuid;not null" json:"category_id"`
+	OpenStake          decimal.Decimal `gorm:"type:decimal(20,2);not null;default:0" json:"open_stake"`
+	OpenValue          decimal.Decimal `gorm:"type:decimal(20,2);not null;default:0" json:"open_value"`
+	SettledStake       decimal.Decimal `gorm:"type:decimal(20,2);not null;default:0" json:"settled_stake"`
+	RealizedProfitLoss decimal.Decimal `gorm:"type:decimal(20,2);not null;default:0" json:"realized_profit_loss"`
+	DailyStake         decimal.Decimal `gorm:"type:decimal(20,2);not null;default:0" json:"daily_stake"`
+	CreatedAt          time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Associations
+	Market   *Market   `gorm:"foreignKey:MarketID" json:"market,omitempty"`
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+// TableName specifies the table name for PortfolioSnapshot model
+func (*PortfolioSnapshot) TableName() string {
+	return "portfolio_snapshots"
+}
+
+// BeforeCreate sets up the model before creation
+func (s *PortfolioSnapshot) BeforeCreate(_ *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// UnrealizedProfitLoss is what the open bets would gain or lose at the
+// prices of the day
+func (s *PortfolioSnapshot) UnrealizedProfitLoss() decimal.Decimal {
+	return s.OpenValue.Sub(s.OpenStake)
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"crypto/rand"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// referralCodeAlphabet leaves out characters that are easy to misread
+const referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// ReferralCodeLength is the length of generated referral codes
+const ReferralCodeLength = 8
+
+// ReferralStatus represents whether a referral can earn rewards
+type ReferralStatus string
+
+const (
+	ReferralStatusActive  ReferralStatus = "active"
+	ReferralStatusFlagged ReferralStatus = "flagged"
+)
+
+// ReferralMilestone is an action by the referred user that can earn rewards
+type ReferralMilestone string
+
+const (
+	ReferralMilestoneFirstDeposit    ReferralMilestone = "first_deposit"
+	ReferralMilestoneFirstSettledBet ReferralMilestone = "first_settled_bet"
+)
+
+// Referral attributes a new user to the user whose code they signed up with.
+// Referrals that look like self-referrals are flagged and earn no rewards.
+type Referral struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ReferrerID uuid.UUID      `gorm:"type:uuid;not null;index" json:"referrer_id"`
+	RefereeID  uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"referee_id"`
+	Code       string         `gorm:"type:varchar(16);not null" json:"code"`
+	Status     ReferralStatus `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	FlagReason string         `gorm:"type:text" json:"flag_reason,omitempty"`
+	SignupIP   net.IP         `gorm:"type:inet" json:"-"`
+	DeviceID   string         `gorm:"type:varchar(128)" json:"-"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Referee *User            `gorm:"foreignKey:RefereeID" json:"referee,omitempty"`
+	Rewards []ReferralReward `gorm:"foreignKey:ReferralID" json:"rewards,omitempty"`
+}
+
+// TableName specifies the table name for Referral model
+func (*Referral) TableName() string {
+	return "referrals"
+}
+
+// BeforeCreate sets up the model before creation
+func (r *Referral) BeforeCreate(_ *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsRewardable reports whether milestones on this referral pay out
+func (r *Referral) IsRewardable() bool {
+	return r.Status == ReferralStatusActive
+}
+
+// Flag marks the referral as suspicious so it earns no rewards
+func (r *Referral) Flag(reason string) {
+	r.Status = ReferralStatusFlagged
+	r.FlagReason = reason
+}
+
+// ReferralReward is a bonus credited to one side of a referral for a milestone.
+// Each party is rewarded at most once per milestone.
+type ReferralReward struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ReferralID    uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_referral_rewards_milestone" json:"referral_id"`
+	UserID        uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_referral_rewards_milestone;index" json:"user_id"`
+	Milestone     ReferralMilestone `gorm:"type:varchar(30);not null;uniqueIndex:idx_referral_rewards_milestone" json:"milestone"`
+	Amount        decimal.Decimal   `gorm:"type:decimal(20,2);not null" json:"amount"`
+	CurrencyCode  string            `gorm:"type:varchar(3);not null" json:"currency_code"`
+	TransactionID uuid.UUID         `gorm:"type:uuid;not null" json:"transaction_id"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for ReferralReward model
+func (*ReferralReward) TableName() string {
+	return "referral_rewards"
+}
+
+// BeforeCreate sets up the model before creation
+func (r *ReferralReward) BeforeCreate(_ *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// GenerateReferralCode returns a random referral code
+func GenerateReferralCode() (string, error) {
+	max := big.NewInt(int64(len(referralCodeAlphabet)))
+	code := make([]byte, ReferralCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = referralCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// NormalizeReferralCode puts a user-entered referral code in canonical form
+func NormalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
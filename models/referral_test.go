@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateReferralCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := GenerateReferralCode()
+		require.NoError(t, err)
+		assert.Len(t, code, ReferralCodeLength)
+		for _, c := range code {
+			assert.True(t, strings.ContainsRune(referralCodeAlphabet, c), "unexpected character %q", c)
+		}
+		seen[code] = true
+	}
+	assert.Greater(t, len(seen), 95)
+}
+
+func TestNormalizeReferralCode(t *testing.T) {
+	assert.Equal(t, "ABCD2345", NormalizeReferralCode("  abcd2345 "))
+}
+
+func TestReferral_Flag(t *testing.T) {
+	referral := &Referral{Status: ReferralStatusActive}
+	assert.True(t, referral.IsRewardable())
+
+	referral.Flag("signed up from the referrer's IP address")
+	assert.False(t, referral.IsRewardable())
+	assert.Equal(t, ReferralStatusFlagged, referral.Status)
+	assert.NotEmpty(t, referral.FlagReason)
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RiskOutcome is what the risk engine decided to do with a bet
+type RiskOutcome string
+
+const (
+	RiskOutcomeAllow  RiskOutcome = "allow"
+	RiskOutcomeReview RiskOutcome = "review"
+	RiskOutcomeDeny   RiskOutcome = "deny"
+)
+
+// severity orders outcomes so the strictest one wins
+func (o RiskOutcome) severity() int {
+	switch o {
+	case RiskOutcomeDeny:
+		return 2
+	case RiskOutcomeReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// RiskReason explains why one risk rule asked for a bet to be reviewed or denied
+type RiskReason struct {
+	Rule    string      `json:"rule"`
+	Code    string      `json:"code"`
+	Outcome RiskOutcome `json:"outcome"`
+	Message string      `json:"message"`
+	// Err is returned to the caller when the reason denies the bet
+	Err error `json:"-"`
+}
+
+// RiskReasons is the list of reasons stored with a decision
+type RiskReasons []RiskReason
+
+// Value implements driver.Valuer interface
+func (r RiskReasons) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements sql.Scanner interface
+func (r *RiskReasons) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, r)
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	}
+	return nil
+}
+
+// RiskDecision records the risk engine's verdict on a bet, with every reason
+// and the risk score, for analytics and for explaining refusals.
+type RiskDecision struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	MarketID uuid.UUID `gorm:"type:uuid;not null;index" json:"market_id"`
+	// BetID or ParlayID is set once the bet the decision let through is placed
+	BetID        *uuid.UUID      `gorm:"type:uuid;index" json:"bet_id,omitempty"`
+	ParlayID     *uuid.UUID      `gorm:"type:uuid;index" json:"parlay_id,omitempty"`
+	CountryCode  string          `gorm:"type:varchar(3)" json:"country_code"`
+	Amount       decimal.Decimal `gorm:"type:decimal(20,2);not null" json:"amount"`
+	CurrencyCode string          `gorm:"type:varchar(3)" json:"currency_code"`
+	Outcome      RiskOutcome     `gorm:"type:varchar(10);not null;index" json:"outcome"`
+	Score        decimal.Decimal `gorm:"type:decimal(10,6);not null" json:"score"`
+	Reasons      RiskReasons     `gorm:"type:jsonb;not null;default:'[]'" json:"reasons"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for RiskDecision model
+func (*RiskDecision) TableName() string {
+	return "risk_decisions"
+}
+
+// BeforeCreate sets up the model before creation
+func (d *RiskDecision) BeforeCreate(_ *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// AddReason records a rule's reason, escalating the outcome if it is stricter
+func (d *RiskDecision) AddReason(reason RiskReason) {
+	d.Reasons = append(d.Reasons, reason)
+	if reason.Outcome.severity() > d.Outcome.severity() {
+		d.Outcome = reason.Outcome
+	}
+}
+
+// IsDenied checks if the bet must be refused
+func (d *RiskDecision) IsDenied() bool {
+	return d.Outcome == RiskOutcomeDeny
+}
+
+// Err returns the error of the first denying reason, or nil if the bet is not denied
+func (d *RiskDecision) Err() error {
+	if !d.IsDenied() {
+		return nil
+	}
+	for i := range d.Reasons {
+		if d.Reasons[i].Outcome == RiskOutcomeDeny && d.Reasons[i].Err != nil {
+			return d.Reasons[i].Err
+		}
+	}
+	return ErrBetRefused
+}
+
+// Codes lists the reason codes in the order they were added
+func (d *RiskDecision) Codes() []string {
+	codes := make([]string, 0, len(d.Reasons))
+	for i := range d.Reasons {
+		codes = append(codes, d.Reasons[i].Code)
+	}
+	return codes
+}
+
+// RiskRuleSettings configures the risk pipeline, globally or for a country
+type RiskRuleSettings struct {
+	DisabledRules []string `json:"disabled_rules,omitempty"`
+	// ReviewScore and DenyScore are risk score thresholds (0-1); zero turns them off
+	ReviewScore decimal.Decimal `json:"review_score"`
+	DenyScore   decimal.Decimal `json:"deny_score"`
+}
+
+// IsDisabled checks if the named rule is switched off
+func (s *RiskRuleSettings) IsDisabled(rule string) bool {
+	for _, name := range s.DisabledRules {
+		if name == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the score thresholds are within 0-1 and the deny score is
+// not below the review score
+func (s *RiskRuleSettings) Validate() error {
+	one := decimal.NewFromInt(1)
+	if s.ReviewScore.IsNegative() || s.ReviewScore.GreaterThan(one) ||
+		s.DenyScore.IsNegative() || s.DenyScore.GreaterThan(one) {
+		return ErrInvalidRiskScoreThreshold
+	}
+	if s.ReviewScore.IsPositive() && s.DenyScore.IsPositive() && s.DenyScore.LessThan(s.ReviewScore) {
+		return ErrInvalidRiskScoreThreshold
+	}
+	return nil
+}
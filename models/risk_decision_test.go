@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskDecision_AddReason(t *testing.T) {
+	decision := &RiskDecision{Outcome: RiskOutcomeAllow}
+
+	decision.AddReason(RiskReason{Code: "market_closing_soon", Outcome: RiskOutcomeReview})
+	assert.Equal(t, RiskOutcomeReview, decision.Outcome)
+	assert.NoError(t, decision.Err())
+
+	decision.AddReason(RiskReason{Code: "bet_too_large", Outcome: RiskOutcomeDeny, Err: ErrBetTooLarge})
+	decision.AddReason(RiskReason{Code: "watchlisted", Outcome: RiskOutcomeReview})
+	assert.Equal(t, RiskOutcomeDeny, decision.Outcome)
+	assert.True(t, decision.IsDenied())
+	assert.ErrorIs(t, decision.Err(), ErrBetTooLarge)
+	assert.Equal(t, []string{"market_closing_soon", "bet_too_large", "watchlisted"}, decision.Codes())
+}
+
+func TestRiskDecision_ErrFallsBackToBetRefused(t *testing.T) {
+	decision := &RiskDecision{Outcome: RiskOutcomeAllow}
+	decision.AddReason(RiskReason{Code: "risk_score_too_high", Outcome: RiskOutcomeDeny})
+	assert.ErrorIs(t, decision.Err(), ErrBetRefused)
+}
+
+func TestRiskReasons_ValueScan(t *testing.T) {
+	reasons := RiskReasons{{Rule: "bet_limits", Code: "bet_too_large", Outcome: RiskOutcomeDeny, Message: "bet amount too large", Err: ErrBetTooLarge}}
+	value, err := reasons.Value()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(value.([]byte)), "Err")
+
+	var scanned RiskReasons
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, "bet_too_large", scanned[0].Code)
+	assert.Equal(t, RiskOutcomeDeny, scanned[0].Outcome)
+	assert.Nil(t, scanned[0].Err)
+}
+
+func TestRiskRuleSettings_Validate(t *testing.T) {
+	assert.NoError(t, (&RiskRuleSettings{}).Validate())
+	assert.NoError(t, (&RiskRuleSettings{ReviewScore: decimal.NewFromFloat(0.5), DenyScore: decimal.NewFromFloat(0.8)}).Validate())
+	assert.ErrorIs(t, (&RiskRuleSettings{ReviewScore: decimal.NewFromFloat(1.5)}).Validate(), ErrInvalidRiskScoreThreshold)
+	assert.ErrorIs(t, (&RiskRuleSettings{DenyScore: decimal.NewFromFloat(-0.1)}).Validate(), ErrInvalidRiskScoreThreshold)
+	assert.ErrorIs(t, (&RiskRuleSettings{ReviewScore: decimal.NewFromFloat(0.5), DenyScore: decimal.NewFromFloat(0.4)}).Validate(), ErrInvalidRiskScoreThreshold)
+}
+
+func TestCountryConfig_RiskRulesRoundTrip(t *testing.T) {
+	raw := []byte(`{"min_bet":"100","risk_rules":{"disabled_rules":["cooldown"],"deny_score":"0.9"}}`)
+
+	var config CountryConfig
+	require.NoError(t, config.Scan(raw))
+	country := &Country{Config: &config}
+	require.NotNil(t, country.GetRiskRules())
+	assert.True(t, country.GetRiskRules().IsDisabled("cooldown"))
+	assert.True(t, country.GetRiskRules().DenyScore.Equal(decimal.NewFromFloat(0.9)))
+
+	value, err := config.Value()
+	require.NoError(t, err)
+	var decoded map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(value.([]byte), &decoded))
+	assert.Contains(t, decoded, "risk_rules")
+
+	assert.Nil(t, (&Country{Config: &CountryConfig{}}).GetRiskRules())
+	assert.Nil(t, (*Country)(nil).GetRiskRules())
+}
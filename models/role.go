@@ -9,9 +9,12 @@ import (
 
 // Role represents a user role in the system
 type Role struct {
-	ID          uuid.UUID    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Name        string       `gorm:"type:varchar(50);not null;unique"`
-	Description string       `gorm:"type:text"`
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name        string    `gorm:"type:varchar(50);not null;unique"`
+	Description string    `gorm:"type:text"`
+	// ParentID names the role this one extends; its permissions are inherited.
+	ParentID    *uuid.UUID   `gorm:"type:uuid"`
+	Parent      *Role        `gorm:"foreignKey:ParentID"`
 	Permissions []Permission `gorm:"many2many:role_permissions;"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
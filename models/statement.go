@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StatementStatus represents the progress of an account statement
+type StatementStatus string
+
+const (
+	StatementStatusPending StatementStatus = "pending"
+	StatementStatusReady   StatementStatus = "ready"
+	StatementStatusFailed  StatementStatus = "failed"
+)
+
+// StatementFormat is the file format a statement is rendered in
+type StatementFormat string
+
+const (
+	StatementFormatCSV StatementFormat = "csv"
+	StatementFormatPDF StatementFormat = "pdf"
+)
+
+// StatementKind records whether a statement was asked for or produced at the
+// end of a month
+type StatementKind string
+
+const (
+	StatementKindRequested StatementKind = "requested"
+	StatementKindMonthly   StatementKind = "monthly"
+)
+
+// Statement is a downloadable account statement: the user's bets,
+// settlements and wallet transactions between two dates, both included, with
+// each wallet's opening and closing balance.
+type Statement struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID       `gorm:"type:uuid;not null;index" json:"user_id"`
+	Kind        StatementKind   `gorm:"type:varchar(20);not null" json:"kind"`
+	Format      StatementFormat `gorm:"type:varchar(10);not null" json:"format"`
+	PeriodStart time.Time       `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd   time.Time       `gorm:"type:date;not null" json:"period_end"`
+	Status      StatementStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Content     []byte          `gorm:"type:bytea" json:"-"`
+	Error       string          `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time      `gorm:"type:timestamptz" json:"completed_at,omitempty"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for Statement model
+func (*Statement) TableName() string {
+	return "statements"
+}
+
+// BeforeCreate sets up the model before creation
+func (s *Statement) BeforeCreate(_ *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsReady checks if the statement file is built and can be downloaded
+func (s *Statement) IsReady() bool {
+	return s.Status == StatementStatusReady
+}
+
+// PeriodDays returns how many days the statement covers
+func (s *Statement) PeriodDays() int {
+	return int(s.PeriodEnd.Sub(s.PeriodStart).Hours()/24) + 1
+}
+
+// FileName is what the statement is called when downloaded
+func (s *Statement) FileName() string {
+	return "neo-statement-" + s.PeriodStart.Format("2006-01-02") + "-to-" +
+		s.PeriodEnd.Format("2006-01-02") + "." + string(s.Format)
+}
+
+// ContentType is the MIME type of the statement file
+func (s *Statement) ContentType() string {
+	if s.Format == StatementFormatCSV {
+		return "text/csv"
+	}
+	return "application/pdf"
+}
+
+// MarkReady records a successfully built statement file
+func (s *Statement) MarkReady(content []byte, at time.Time) {
+	s.Status = StatementStatusReady
+	s.Content = content
+	s.Error = ""
+	s.CompletedAt = &at
+}
+
+// MarkFailed records why the statement could not be built
+func (s *Statement) MarkFailed(reason string, at time.Time) {
+	s.Status = StatementStatusFailed
+	s.Error = reason
+	s.CompletedAt = &at
+}
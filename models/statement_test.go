@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatement_Lifecycle(t *testing.T) {
+	now := time.Now()
+	statement := &Statement{
+		Format:      StatementFormatPDF,
+		PeriodStart: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		Status:      StatementStatusPending,
+	}
+	assert.Equal(t, 29, statement.PeriodDays())
+	assert.Equal(t, "neo-statement-2024-02-01-to-2024-02-29.pdf", statement.FileName())
+	assert.False(t, statement.IsReady())
+
+	statement.MarkFailed("disk full", now)
+	assert.Equal(t, StatementStatusFailed, statement.Status)
+	assert.Equal(t, "disk full", statement.Error)
+
+	statement.MarkReady([]byte("%PDF-"), now)
+	assert.True(t, statement.IsReady())
+	assert.Empty(t, statement.Error)
+	assert.Equal(t, []byte("%PDF-"), statement.Content)
+	assert.Equal(t, "application/pdf", statement.ContentType())
+}
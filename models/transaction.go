@@ -20,6 +20,7 @@ const (
 	TransactionTypeBetRefund  TransactionType = "bet_refund"
 	TransactionTypePayout     TransactionType = "payout"
 	TransactionTypeFee        TransactionType = "fee"
+	TransactionTypeBonus      TransactionType = "bonus"
 )
 
 // TransactionMetadata represents additional transaction metadata
@@ -215,6 +216,79 @@ func CreateBetRefundTransaction(userID,
 	}
 }
 
+// CreateParlayTransaction creates the transaction that takes a parlay's stake
+func CreateParlayTransaction(userID,
+	walletID uuid.UUID,
+	stake, balanceBefore decimal.Decimal,
+	parlayID uuid.UUID) *Transaction {
+	return &Transaction{
+		UserID:          userID,
+		WalletID:        walletID,
+		TransactionType: TransactionTypeBetPlace,
+		Amount:          stake.Neg(),
+		BalanceBefore:   balanceBefore,
+		BalanceAfter:    balanceBefore.Sub(stake),
+		ReferenceType:   "parlay",
+		ReferenceID:     &parlayID,
+		Description:     "Parlay placement",
+	}
+}
+
+// CreateParlayPayoutTransaction creates the transaction that pays out a won parlay
+func CreateParlayPayoutTransaction(userID,
+	walletID uuid.UUID,
+	amount, balanceBefore decimal.Decimal,
+	parlayID uuid.UUID) *Transaction {
+	return &Transaction{
+		UserID:          userID,
+		WalletID:        walletID,
+		TransactionType: TransactionTypePayout,
+		Amount:          amount,
+		BalanceBefore:   balanceBefore,
+		BalanceAfter:    balanceBefore.Add(amount),
+		ReferenceType:   "parlay",
+		ReferenceID:     &parlayID,
+		Description:     "Parlay payout",
+	}
+}
+
+// CreateParlayRefundTransaction creates the transaction that returns the
+// stake of a parlay whose legs were all voided
+func CreateParlayRefundTransaction(userID,
+	walletID uuid.UUID,
+	amount, balanceBefore decimal.Decimal,
+	parlayID uuid.UUID) *Transaction {
+	return &Transaction{
+		UserID:          userID,
+		WalletID:        walletID,
+		TransactionType: TransactionTypeBetRefund,
+		Amount:          amount,
+		BalanceBefore:   balanceBefore,
+		BalanceAfter:    balanceBefore.Add(amount),
+		ReferenceType:   "parlay",
+		ReferenceID:     &parlayID,
+		Description:     "Refund for voided parlay",
+	}
+}
+
+// CreateReferralBonusTransaction creates a referral reward transaction
+func CreateReferralBonusTransaction(userID,
+	walletID uuid.UUID,
+	amount, balanceBefore decimal.Decimal,
+	referralID uuid.UUID) *Transaction {
+	return &Transaction{
+		UserID:          userID,
+		WalletID:        walletID,
+		TransactionType: TransactionTypeBonus,
+		Amount:          amount,
+		BalanceBefore:   balanceBefore,
+		BalanceAfter:    balanceBefore.Add(amount),
+		ReferenceType:   "referral",
+		ReferenceID:     &referralID,
+		Description:     "Referral reward",
+	}
+}
+
 // parseUUIDPtr safely parses a string to UUID pointer
 func parseUUIDPtr(s string) *uuid.UUID {
 	if s == "" {
@@ -202,6 +202,22 @@ func TestTransaction(t *testing.T) {
 		assert.Equal(t, settlementID, *tx.ReferenceID)
 	})
 
+	t.Run("CreateReferralBonusTransaction", func(t *testing.T) {
+		userID := uuid.New()
+		walletID := uuid.New()
+		amount := decimal.NewFromFloat(25)
+		balanceBefore := decimal.NewFromFloat(100)
+		referralID := uuid.New()
+
+		tx := CreateReferralBonusTransaction(userID, walletID, amount, balanceBefore, referralID)
+
+		assert.Equal(t, TransactionTypeBonus, tx.TransactionType)
+		assert.True(t, amount.Equal(tx.Amount))
+		assert.True(t, decimal.NewFromFloat(125).Equal(tx.BalanceAfter))
+		assert.Equal(t, "referral", tx.ReferenceType)
+		assert.Equal(t, referralID, *tx.ReferenceID)
+	})
+
 	t.Run("parseUUIDPtr", func(t *testing.T) {
 		validUUID := uuid.New().String()
 		result := parseUUIDPtr(validUUID)
@@ -22,6 +22,10 @@ const (
 	KYCStatusRejected   KYCStatus = "rejected"
 )
 
+// ErasedPasswordHash replaces the password of an erased user. It is not a
+// valid bcrypt hash, so no password can match it.
+const ErasedPasswordHash = "!erased"
+
 // UserMetadata represents additional user metadata
 type UserMetadata struct {
 	ReferralCode   string    `json:"referral_code,omitempty"`
@@ -29,6 +33,8 @@ type UserMetadata struct {
 	PreferredLang  string    `json:"preferred_lang,omitempty"`
 	NewsletterSubs bool      `json:"newsletter_subscribed,omitempty"`
 	LastSeenAt     time.Time `json:"last_seen_at,omitempty"`
+	// HideFromLeaderboards keeps the user off public leaderboards
+	HideFromLeaderboards bool `json:"hide_from_leaderboards,omitempty"`
 }
 
 // Value implements driver.Valuer interface
@@ -73,7 +79,9 @@ type User struct {
 	FailedLoginAttempts int           `gorm:"default:0" json:"failed_login_attempts"`
 	LockedUntil         *time.Time    `gorm:"type:timestamptz" json:"locked_until"`
 	IsActive            *bool         `gorm:"default:true" json:"is_active"`
+	TokenVersion        int64         `gorm:"not null;default:1" json:"-"`
 	Metadata            *UserMetadata `gorm:"type:jsonb;default:'{}'" json:"metadata"`
+	ErasedAt            *time.Time    `gorm:"type:timestamptz" json:"erased_at,omitempty"`
 	CreatedAt           time.Time     `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt           time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -175,6 +183,33 @@ func (u *User) UpdateLastLogin(ip net.IP) {
 	u.ResetFailedLogins()
 }
 
+// IsErased checks if the user's personal data has been erased
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
+}
+
+// Anonymize strips the user's personal data on an erasure request. The row is
+// kept because bets, ledger entries and payments must still reference it.
+func (u *User) Anonymize(at time.Time) {
+	inactive := false
+
+	u.Email = "erased+" + u.ID.String() + "@erased.invalid"
+	u.EmailVerifiedAt = nil
+	u.PasswordHash = ErasedPasswordHash
+	u.FirstName = ""
+	u.LastName = ""
+	u.Phone = ""
+	u.PhoneVerifiedAt = nil
+	u.DateOfBirth = nil
+	u.KYCReference = ""
+	u.TwoFactorEnabled = false
+	u.TwoFactorSecret = ""
+	u.LastLoginIP = nil
+	u.IsActive = &inactive
+	u.Metadata = &UserMetadata{}
+	u.ErasedAt = &at
+}
+
 // GetFullName returns the user's full name
 func (u *User) GetFullName() string {
 	if u.FirstName == "" && u.LastName == "" {
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserSession records a single issued access token and the device it was issued to
+type UserSession struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenJTI   string     `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"`
+	DeviceName string     `gorm:"type:varchar(100)" json:"device_name"`
+	UserAgent  string     `gorm:"type:text" json:"user_agent"`
+	IPAddress  string     `gorm:"type:varchar(45)" json:"ip_address"`
+	LastSeenAt time.Time  `gorm:"type:timestamptz;not null" json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"type:timestamptz;not null" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"type:timestamptz" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// Associations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for UserSession model
+func (*UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// BeforeCreate sets up the model before creation
+func (s *UserSession) BeforeCreate(_ *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive checks if the session has neither expired nor been revoked
+func (s *UserSession) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// Validate performs validation on the session model
+func (s *UserSession) Validate() error {
+	if s.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if s.TokenJTI == "" {
+		return ErrInvalidTokenJTI
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSession(t *testing.T) {
+	t.Run("TableName", func(t *testing.T) {
+		s := UserSession{}
+		assert.Equal(t, "user_sessions", s.TableName())
+	})
+
+	t.Run("BeforeCreate", func(t *testing.T) {
+		s := UserSession{}
+		assert.NoError(t, s.BeforeCreate(nil))
+		assert.NotEqual(t, uuid.Nil, s.ID)
+	})
+
+	t.Run("IsActive", func(t *testing.T) {
+		now := time.Now()
+		assert.True(t, (&UserSession{ExpiresAt: now.Add(time.Hour)}).IsActive())
+		assert.False(t, (&UserSession{ExpiresAt: now.Add(-time.Hour)}).IsActive())
+		assert.False(t, (&UserSession{ExpiresAt: now.Add(time.Hour), RevokedAt: &now}).IsActive())
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		s := UserSession{UserID: uuid.New(), TokenJTI: uuid.NewString()}
+		assert.NoError(t, s.Validate())
+
+		s.TokenJTI = ""
+		assert.ErrorIs(t, s.Validate(), ErrInvalidTokenJTI)
+
+		s.UserID = uuid.Nil
+		assert.ErrorIs(t, s.Validate(), ErrInvalidUserID)
+	})
+}
@@ -237,4 +237,34 @@ func TestUser(t *testing.T) {
 
 		assert.NotEqual(t, &u, masked)
 	})
+	t.Run("Anonymize", func(t *testing.T) {
+		active := true
+		dob := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+		u := User{
+			ID:               uuid.New(),
+			Email:            "test@example.com",
+			FirstName:        "Ada",
+			LastName:         "Obi",
+			Phone:            "1234567890",
+			PasswordHash:     "secret_hash",
+			DateOfBirth:      &dob,
+			TwoFactorEnabled: true,
+			TwoFactorSecret:  "secret_2fa",
+			LastLoginIP:      net.ParseIP("203.0.113.1"),
+			IsActive:         &active,
+			Metadata:         &UserMetadata{ReferralCode: "ABCD2345"},
+		}
+
+		now := time.Now()
+		u.Anonymize(now)
+
+		assert.True(t, u.IsErased())
+		assert.Equal(t, "erased+"+u.ID.String()+"@erased.invalid", u.Email)
+		assert.Empty(t, u.FirstName+u.LastName+u.Phone+u.TwoFactorSecret)
+		assert.Nil(t, u.DateOfBirth)
+		assert.Nil(t, u.LastLoginIP)
+		assert.False(t, *u.IsActive)
+		assert.Empty(t, u.Metadata.ReferralCode)
+		assert.False(t, CheckPasswordHash("secret_hash", u.PasswordHash))
+	})
 }
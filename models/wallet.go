@@ -135,3 +135,14 @@ func (w *Wallet) Validate() error {
 	}
 	return nil
 }
+
+// AuditValues snapshots the wallet state for the audit trail. Amounts are
+// stored as strings so snapshots compare exactly.
+func (w *Wallet) AuditValues() AuditValues {
+	return AuditValues{
+		"balance":        w.Balance.StringFixed(2),
+		"locked_balance": w.LockedBalance.StringFixed(2),
+		"is_locked":      w.IsLocked,
+		"lock_reason":    w.LockReason,
+	}
+}